@@ -1,6 +1,10 @@
 package main
 
-import "github.com/ellexo2456/FilmLib/internal/app"
+import (
+	"os"
+
+	"github.com/ellexo2456/FilmLib/internal/app"
+)
 
 //	@title			FilmLib API
 //	@version		1.0
@@ -16,5 +20,12 @@ import "github.com/ellexo2456/FilmLib/internal/app"
 // @schemes	http
 // @BasePath	/
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		os.Exit(app.Import(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "import-tsv" {
+		os.Exit(app.ImportTSV(os.Args[2:]))
+	}
+
 	app.StartServer()
 }
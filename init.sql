@@ -4,12 +4,17 @@ CREATE EXTENSION IF NOT EXISTS moddatetime
 
 CREATE TABLE "user"
 (
-    id         SERIAL PRIMARY KEY,
-    email      TEXT  NOT NULL UNIQUE,
-    password   BYTEA NOT NULL UNIQUE,
-    role       INT DEFAULT 0,
-    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+    id             SERIAL PRIMARY KEY,
+    email          TEXT    NOT NULL UNIQUE,
+    password       BYTEA   NOT NULL UNIQUE,
+    role           INT DEFAULT 0,
+    verified       BOOLEAN NOT NULL DEFAULT TRUE,
+    disabled       BOOLEAN NOT NULL DEFAULT FALSE,
+    totp_secret    BYTEA,
+    totp_enabled   BOOLEAN NOT NULL DEFAULT FALSE,
+    totp_last_step BIGINT  NOT NULL DEFAULT 0,
+    created_at     TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at     TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
 );
 
 CREATE TRIGGER modify_user_updated_at
@@ -18,6 +23,95 @@ CREATE TRIGGER modify_user_updated_at
     FOR EACH ROW
 EXECUTE PROCEDURE public.moddatetime(updated_at);
 
+CREATE TABLE recovery_code
+(
+    id      SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES "user" (id) ON DELETE CASCADE,
+    hash    BYTEA   NOT NULL,
+    used_at TIMESTAMPTZ,
+    UNIQUE (user_id, hash)
+);
+
+CREATE TABLE role_policy
+(
+    role        INT PRIMARY KEY,
+    require_2fa BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE TABLE user_identity
+(
+    provider   TEXT    NOT NULL,
+    subject    TEXT    NOT NULL,
+    user_id    INTEGER NOT NULL REFERENCES "user" (id) ON DELETE CASCADE,
+    email      TEXT    NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (provider, subject)
+);
+
+CREATE INDEX user_identity_user_id_idx ON user_identity (user_id);
+
+CREATE TABLE api_token
+(
+    id           SERIAL PRIMARY KEY,
+    user_id      INTEGER NOT NULL REFERENCES "user" (id) ON DELETE CASCADE,
+    name         TEXT    NOT NULL,
+    hash         BYTEA   NOT NULL UNIQUE,
+    scopes       TEXT[]  NOT NULL DEFAULT '{}',
+    expires_at   TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX api_token_user_id_idx ON api_token (user_id);
+
+-- used with SESSION_STORE=postgres, expired rows are swept periodically
+CREATE TABLE session
+(
+    token_hash   BYTEA PRIMARY KEY,
+    id           TEXT        NOT NULL,
+    user_id      INTEGER     NOT NULL REFERENCES "user" (id) ON DELETE CASCADE,
+    role         INT         NOT NULL,
+    verified     BOOLEAN     NOT NULL,
+    user_agent   TEXT        NOT NULL DEFAULT '',
+    ip           TEXT        NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ NOT NULL,
+    last_seen_at TIMESTAMPTZ NOT NULL,
+    expires_at   TIMESTAMPTZ NOT NULL,
+    deadline     TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX session_user_id_idx ON session (user_id);
+CREATE INDEX session_expires_at_idx ON session (expires_at);
+
+CREATE TABLE audit_log
+(
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    INTEGER     NOT NULL,
+    action     TEXT        NOT NULL,
+    entity     TEXT        NOT NULL,
+    entity_id  INTEGER     NOT NULL,
+    diff       JSONB       NOT NULL DEFAULT '{}',
+    request_id TEXT        NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX audit_log_user_id_idx ON audit_log (user_id);
+CREATE INDEX audit_log_entity_idx ON audit_log (entity, entity_id);
+CREATE INDEX audit_log_created_at_idx ON audit_log (created_at);
+
+-- the log is append-only, the user id isn`t a foreign key to outlive the user
+CREATE FUNCTION forbid_audit_log_change() RETURNS TRIGGER AS
+$$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_append_only
+    BEFORE UPDATE OR DELETE
+    ON audit_log
+    FOR EACH ROW
+EXECUTE PROCEDURE forbid_audit_log_change();
 
 CREATE TABLE film
 (
@@ -34,9 +128,19 @@ CREATE TABLE film
         CONSTRAINT rating_range
             CHECK (rating BETWEEN 0 AND 10),
     created_at   TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
-    updated_at   TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+    updated_at   TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at   TIMESTAMPTZ,
+    -- increased on every update, sent as the ETag
+    version      INT           NOT NULL DEFAULT 1,
+    -- id in the dataset the film is imported from
+    source_id    TEXT UNIQUE
 );
 
+-- deleted films stay in the trash until they are purged
+CREATE INDEX film_deleted_at_idx ON film (deleted_at) WHERE deleted_at IS NOT NULL;
+-- the import matches films by the title and the release date
+CREATE INDEX film_title_release_date_idx ON film (title, release_date);
+
 CREATE TRIGGER modify_film_updated_at
     BEFORE UPDATE
     ON film
@@ -55,9 +159,15 @@ CREATE TABLE actor
             CHECK (birthdate >= '1800-01-01'
                 AND birthdate <= CURRENT_DATE),
     created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+    version    INT     NOT NULL DEFAULT 1,
+    source_id  TEXT UNIQUE
 );
 
+CREATE INDEX actor_deleted_at_idx ON actor (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX actor_name_birthdate_idx ON actor (name, birthdate);
+
 
 CREATE TRIGGER modify_actor_updated_at
     BEFORE UPDATE
@@ -67,7 +177,89 @@ EXECUTE PROCEDURE public.moddatetime(updated_at);
 
 CREATE TABLE film_actor
 (
-    film_id  INTEGER REFERENCES film (id) ON DELETE CASCADE,
-    actor_id INTEGER REFERENCES actor (id) ON DELETE CASCADE,
+    film_id    INTEGER REFERENCES film (id) ON DELETE CASCADE,
+    actor_id   INTEGER REFERENCES actor (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
     PRIMARY KEY (film_id, actor_id)
 );
+
+-- the incremental export reads the rows changed since the last sync
+CREATE INDEX film_updated_at_idx ON film (updated_at);
+CREATE INDEX actor_updated_at_idx ON actor (updated_at);
+CREATE INDEX film_actor_created_at_idx ON film_actor (created_at);
+
+-- every change of a film or an actor is kept as a numbered snapshot,
+-- user_id is NULL for the state that existed before the history was kept
+CREATE TABLE film_revision
+(
+    film_id    INTEGER     NOT NULL REFERENCES film (id) ON DELETE CASCADE,
+    revision   INTEGER     NOT NULL,
+    user_id    INTEGER,
+    data       JSONB       NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (film_id, revision)
+);
+
+CREATE TABLE actor_revision
+(
+    actor_id   INTEGER     NOT NULL REFERENCES actor (id) ON DELETE CASCADE,
+    revision   INTEGER     NOT NULL,
+    user_id    INTEGER,
+    data       JSONB       NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (actor_id, revision)
+);
+
+-- ids of the merged actors, they resolve to the actor they were merged into
+CREATE TABLE actor_redirect
+(
+    from_id    INTEGER PRIMARY KEY,
+    to_id      INTEGER     NOT NULL REFERENCES actor (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX actor_redirect_to_id_idx ON actor_redirect (to_id);
+
+-- lines of the dataset files which are imported, the file is told by its size
+CREATE TABLE dataset_progress
+(
+    kind       TEXT PRIMARY KEY,
+    size       BIGINT      NOT NULL,
+    line       BIGINT      NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- the secret signs the deliveries, so it is kept as is
+CREATE TABLE webhook
+(
+    id         SERIAL PRIMARY KEY,
+    url        TEXT        NOT NULL,
+    events     TEXT[]      NOT NULL,
+    secret     TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE webhook_delivery
+(
+    id              BIGSERIAL PRIMARY KEY,
+    webhook_id      INTEGER     NOT NULL REFERENCES webhook (id) ON DELETE CASCADE,
+    event           TEXT        NOT NULL,
+    payload         JSONB       NOT NULL,
+    status          TEXT        NOT NULL DEFAULT 'pending',
+    attempts        INT         NOT NULL DEFAULT 0,
+    response_status INT         NOT NULL DEFAULT 0,
+    error           TEXT        NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- the dispatcher reads only the pending deliveries which are due
+CREATE INDEX webhook_delivery_next_attempt_at_idx ON webhook_delivery (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX webhook_delivery_webhook_id_idx ON webhook_delivery (webhook_id, id);
+
+CREATE TRIGGER modify_webhook_delivery_updated_at
+    BEFORE UPDATE
+    ON webhook_delivery
+    FOR EACH ROW
+EXECUTE PROCEDURE public.moddatetime(updated_at);
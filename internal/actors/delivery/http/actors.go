@@ -2,9 +2,11 @@ package http
 
 import (
 	"encoding/json"
-	"errors"
 	"github.com/ellexo2456/FilmLib/internal/domain"
 	logs "github.com/ellexo2456/FilmLib/internal/logger"
+	"github.com/ellexo2456/FilmLib/internal/middleware"
+	"io"
+	"mime"
 	"net/http"
 	"strconv"
 )
@@ -18,38 +20,38 @@ func NewActorsHandler(mux *http.ServeMux, au domain.ActorsUsecase) {
 		ActorsUsecase: au,
 	}
 
-	mux.HandleFunc("POST /actors", handler.AddActor)
-	mux.HandleFunc("DELETE /actors/{id}", handler.DeleteActor)
-	mux.HandleFunc("PUT /actors", handler.ModifyActor)
+	mux.Handle("POST /actors", middleware.Require(domain.ActorsWrite, handler.AddActor))
+	mux.Handle("DELETE /actors/{id}", middleware.Require(domain.ActorsDelete, handler.DeleteActor))
+	mux.Handle("PUT /actors", middleware.Require(domain.ActorsWrite, handler.ModifyActor))
+	mux.Handle("PATCH /actors/{id}", middleware.Require(domain.ActorsWrite, handler.PatchActor))
+	mux.Handle("GET /actors/{id}/revisions", middleware.Require(domain.ActorsWrite, handler.GetActorRevisions))
+	mux.Handle("GET /actors/{id}/revisions/diff", middleware.Require(domain.ActorsWrite, handler.DiffActorRevisions))
+	mux.Handle("POST /actors/{id}/revisions/{revision}/revert", middleware.Require(domain.ActorsWrite, handler.RevertActor))
+	mux.Handle("GET /actors/duplicates", middleware.Require(domain.ActorsMerge, handler.GetDuplicateActors))
+	mux.Handle("POST /actors/{id}/merge/{duplicate}", middleware.Require(domain.ActorsMerge, handler.MergeActors))
+	mux.Handle("GET /trash/actors", middleware.Require(domain.TrashManage, handler.GetDeletedActors))
+	mux.Handle("POST /trash/actors/{id}/restore", middleware.Require(domain.TrashManage, handler.RestoreActor))
 	mux.HandleFunc("GET /actors", handler.GetActors)
+	mux.HandleFunc("GET /actors/{id}", handler.GetActor)
 
 }
 
 // AddActor godoc
 //
 //	@Summary		Adds a new actor.
-//	@Description	Adds a new actor with the provided data.
+//	@Description	Adds a new actor with the provided data. A retry with the same Idempotency-Key gets the first response.
 //	@Tags			Actors
-//	@Param			body	body	domain.ActorToAdd	true	"actor to add"
+//	@Param			Idempotency-Key	header	string				false	"Key of the request, unique per user"
+//	@Param			body			body	domain.ActorToAdd	true	"actor to add"
 //	@Produce		json
 //	@Success		200	{object}	object{body=object{id=int}}
 //	@Failure		400	{object}	object{err=string}
 //	@Failure		403	{object}	object{err=string}
+//	@Failure		409	{object}	object{err=string}
+//	@Failure		422	{object}	object{err=string}
 //	@Failure		500	{object}	object{err=string}
 //	@Router			/api/v1/actors [post]
 func (h *ActorsHandler) AddActor(w http.ResponseWriter, r *http.Request) {
-	sc, ok := r.Context().Value(domain.SessionContextKey).(domain.SessionContext)
-	if !ok {
-		domain.WriteError(w, "can`t find user", http.StatusInternalServerError)
-		logs.LogError(logs.Logger, "actors/http", "AddActor", errors.New("can`t find user"), "can`t find user")
-	}
-	logs.Logger.Debug("AddActor session context\n: ", sc)
-
-	if sc.Role != domain.Moder {
-		domain.WriteError(w, "forbidden", http.StatusForbidden)
-		logs.LogError(logs.Logger, "actors/http", "AddActor", errors.New("forbidden"), "invalid role")
-	}
-
 	var actor domain.Actor
 
 	err := json.NewDecoder(r.Body).Decode(&actor)
@@ -61,7 +63,7 @@ func (h *ActorsHandler) AddActor(w http.ResponseWriter, r *http.Request) {
 	logs.Logger.Debug("AddActor actor:\n", actor)
 	defer domain.CloseAndAlert(r.Body, "actors/http", "AddActor")
 
-	id, err := h.ActorsUsecase.Add(actor)
+	id, err := h.ActorsUsecase.Add(actor, domain.RequestAuditContext(r))
 	if err != nil {
 		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
 		logs.LogError(logs.Logger, "actors/http", "AddActor", err, err.Error())
@@ -81,7 +83,7 @@ func (h *ActorsHandler) AddActor(w http.ResponseWriter, r *http.Request) {
 // DeleteActor godoc
 //
 //	@Summary		Deletes an actor.
-//	@Description	Deletes an actor by id with all its relations with films.
+//	@Description	Moves an actor to the trash by id, its relations with films are kept for a restore.
 //	@Tags			Actors
 //	@Param			id	path	int	true	"Actor id"
 //	@Produce		json
@@ -92,18 +94,6 @@ func (h *ActorsHandler) AddActor(w http.ResponseWriter, r *http.Request) {
 //	@Failure		500	{object}	object{err=string}
 //	@Router			/api/v1/actors/{id} [delete]
 func (h *ActorsHandler) DeleteActor(w http.ResponseWriter, r *http.Request) {
-	sc, ok := r.Context().Value(domain.SessionContextKey).(domain.SessionContext)
-	if !ok {
-		domain.WriteError(w, "can`t find user", http.StatusInternalServerError)
-		logs.LogError(logs.Logger, "actors/http", "DeleteFilm", errors.New("can`t find user"), "can`t find user")
-	}
-	logs.Logger.Debug("DeleteFilm session context\n: ", sc)
-
-	if sc.Role != domain.Moder {
-		domain.WriteError(w, "forbidden", http.StatusForbidden)
-		logs.LogError(logs.Logger, "actors/http", "DeleteFilm", errors.New("forbidden"), "invalid role")
-	}
-
 	id, err := strconv.Atoi(r.PathValue("id"))
 	if err != nil {
 		domain.WriteError(w, err.Error(), http.StatusBadRequest)
@@ -112,7 +102,7 @@ func (h *ActorsHandler) DeleteActor(w http.ResponseWriter, r *http.Request) {
 	}
 	logs.Logger.Debug("DeleteFilm id:\n", id)
 
-	err = h.ActorsUsecase.Remove(id)
+	err = h.ActorsUsecase.Remove(id, domain.RequestAuditContext(r))
 	if err != nil {
 		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
 		logs.LogError(logs.Logger, "actors/http", "DeleteFilm", err, err.Error())
@@ -122,31 +112,74 @@ func (h *ActorsHandler) DeleteActor(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// GetActor godoc
+//
+//	@Summary		Gets a actor.
+//	@Description	Gets a actor by id. The ETag header holds its version, a matching If-None-Match gets 304.
+//	@Description	The id of a merged actor is redirected to the actor it was merged into.
+//	@Tags			Actors
+//	@Param			id				path	int		true	"Actor id"
+//	@Param			If-None-Match	header	string	false	"ETag of the cached actor"
+//	@Produce		json
+//	@Success		200	{object}	object{body=object{actor=domain.ActorWithoutFilms}}
+//	@Success		301
+//	@Success		304
+//	@Failure		400	{object}	object{err=string}
+//	@Failure		404	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/actors/{id} [get]
+func (h *ActorsHandler) GetActor(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "actors/http", "GetActor", err, err.Error())
+		return
+	}
+
+	actor, err := h.ActorsUsecase.GetById(id)
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "actors/http", "GetActor", err, err.Error())
+		return
+	}
+
+	// the location is relative to keep the prefix the api is mounted on
+	if actor.ID != id {
+		w.Header().Set("Location", strconv.Itoa(actor.ID))
+		w.WriteHeader(http.StatusMovedPermanently)
+		return
+	}
+
+	domain.SetETag(w, actor.Version)
+	if domain.NotModified(r, actor.Version) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	domain.WriteResponse(
+		w,
+		map[string]interface{}{
+			"actor": actor,
+		},
+		http.StatusOK,
+	)
+}
+
 // ModifyActor godoc
 //
 //	@Summary		Modify an actor.
 //	@Description	Modify an actor by id and retrieves a new actor.
 //	@Tags			Actors
+//	@Param			If-Match	header	string	false	"ETag of the actor version being modified"
 //	@Param			body	body	domain.ActorWithoutFilms	true	"Actor to modify"
 //	@Produce		json
 //	@Success		200	{object}	object{body=object{actors=domain.ActorWithoutFilms}}
 //	@Failure		400	{object}	object{err=string}
 //	@Failure		403	{object}	object{err=string}
+//	@Failure		412	{object}	object{err=string}
 //	@Failure		500	{object}	object{err=string}
 //	@Router			/api/v1/actors [put]
 func (h *ActorsHandler) ModifyActor(w http.ResponseWriter, r *http.Request) {
-	sc, ok := r.Context().Value(domain.SessionContextKey).(domain.SessionContext)
-	if !ok {
-		domain.WriteError(w, "can`t find user", http.StatusInternalServerError)
-		logs.LogError(logs.Logger, "actors/http", "ModifyFilm", errors.New("can`t find user"), "can`t find user")
-	}
-	logs.Logger.Debug("DeleteFilm session context\n: ", sc)
-
-	if sc.Role != domain.Moder {
-		domain.WriteError(w, "forbidden", http.StatusForbidden)
-		logs.LogError(logs.Logger, "actors/http", "ModifyFilm", errors.New("forbidden"), "invalid role")
-	}
-
 	var actor domain.Actor
 	err := json.NewDecoder(r.Body).Decode(&actor)
 	if err != nil {
@@ -157,7 +190,14 @@ func (h *ActorsHandler) ModifyActor(w http.ResponseWriter, r *http.Request) {
 	logs.Logger.Debug("ModifyFilm new actor:\n", actor)
 	defer domain.CloseAndAlert(r.Body, "actors/http", "ModifyFilm")
 
-	actor, err = h.ActorsUsecase.Modify(actor)
+	actor.Version, err = domain.IfMatchVersion(r)
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "actors/http", "ModifyFilm", err, err.Error())
+		return
+	}
+
+	actor, err = h.ActorsUsecase.Modify(actor, domain.RequestAuditContext(r))
 	if err != nil {
 		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
 		logs.LogError(logs.Logger, "actors/http", "ModifyFilm", err, err.Error())
@@ -165,6 +205,7 @@ func (h *ActorsHandler) ModifyActor(w http.ResponseWriter, r *http.Request) {
 	}
 	logs.Logger.Debug("ModifyFilm updated actor:\n", actor)
 
+	domain.SetETag(w, actor.Version)
 	domain.WriteResponse(
 		w,
 		map[string]interface{}{
@@ -201,3 +242,364 @@ func (h *ActorsHandler) GetActors(w http.ResponseWriter, r *http.Request) {
 		http.StatusOK,
 	)
 }
+
+// PatchActor godoc
+//
+//	@Summary		Patches a actor.
+//	@Description	Changes only the fields present in a JSON Merge Patch (RFC 7396) or the fields touched by a JSON Patch (RFC 6902). Fields can`t be removed.
+//	@Tags			Actors
+//	@Param			id				path	int		true	"Actor id"
+//	@Param			If-Match		header	string	false	"ETag of the actor version being patched"
+//	@Param			Content-Type	header	string	true	"application/merge-patch+json or application/json-patch+json"
+//	@Param			body			body	object	true	"Patch"
+//	@Produce		json
+//	@Success		200	{object}	object{body=object{actor=domain.ActorWithoutFilms}}
+//	@Failure		400	{object}	object{err=string}
+//	@Failure		403	{object}	object{err=string}
+//	@Failure		404	{object}	object{err=string}
+//	@Failure		412	{object}	object{err=string}
+//	@Failure		415	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/actors/{id} [patch]
+func (h *ActorsHandler) PatchActor(w http.ResponseWriter, r *http.Request) {
+	defer domain.CloseAndAlert(r.Body, "actors/http", "PatchActor")
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "actors/http", "PatchActor", err, err.Error())
+		return
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	patch := domain.Patch{Type: domain.PatchType(mediaType)}
+	if !patch.Type.Valid() {
+		w.Header().Set(domain.AcceptPatchHeader, string(domain.MergePatch)+", "+string(domain.JSONPatch))
+		domain.WriteError(w, "unsupported patch type "+mediaType, http.StatusUnsupportedMediaType)
+		return
+	}
+
+	patch.Version, err = domain.IfMatchVersion(r)
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "actors/http", "PatchActor", err, err.Error())
+		return
+	}
+
+	patch.Body, err = io.ReadAll(r.Body)
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "actors/http", "PatchActor", err, err.Error())
+		return
+	}
+
+	actor, err := h.ActorsUsecase.Patch(id, patch, domain.RequestAuditContext(r))
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "actors/http", "PatchActor", err, err.Error())
+		return
+	}
+
+	domain.SetETag(w, actor.Version)
+	domain.WriteResponse(
+		w,
+		map[string]interface{}{
+			"actor": actor,
+		},
+		http.StatusOK,
+	)
+}
+
+// GetActorRevisions godoc
+//
+//	@Summary		Gets actor revisions.
+//	@Description	Gets all saved revisions of the actor, newest first.
+//	@Tags			Actors
+//	@Param			id	path	int	true	"Actor id"
+//	@Produce		json
+//	@Success		200	{object}	object{body=object{revisions=[]domain.ActorRevisionWithoutFilms}}
+//	@Failure		400	{object}	object{err=string}
+//	@Failure		403	{object}	object{err=string}
+//	@Failure		404	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/actors/{id}/revisions [get]
+func (h *ActorsHandler) GetActorRevisions(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "actors/http", "GetActorRevisions", err, err.Error())
+		return
+	}
+
+	revisions, err := h.ActorsUsecase.GetRevisions(id)
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "actors/http", "GetActorRevisions", err, err.Error())
+		return
+	}
+
+	domain.WriteResponse(
+		w,
+		map[string]interface{}{
+			"revisions": revisions,
+		},
+		http.StatusOK,
+	)
+}
+
+// DiffActorRevisions godoc
+//
+//	@Summary		Compares two actor revisions.
+//	@Description	Gets the fields changed between two revisions of the actor.
+//	@Tags			Actors
+//	@Param			id		path	int	true	"Actor id"
+//	@Param			from	query	int	true	"Revision to compare from"
+//	@Param			to		query	int	true	"Revision to compare to"
+//	@Produce		json
+//	@Success		200	{object}	object{body=object{diff=domain.RevisionDiff}}
+//	@Failure		400	{object}	object{err=string}
+//	@Failure		403	{object}	object{err=string}
+//	@Failure		404	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/actors/{id}/revisions/diff [get]
+func (h *ActorsHandler) DiffActorRevisions(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "actors/http", "DiffActorRevisions", err, err.Error())
+		return
+	}
+
+	queryParams := r.URL.Query()
+	from, err := strconv.Atoi(queryParams.Get(domain.RevisionFromParam))
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "actors/http", "DiffActorRevisions", err, err.Error())
+		return
+	}
+	to, err := strconv.Atoi(queryParams.Get(domain.RevisionToParam))
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "actors/http", "DiffActorRevisions", err, err.Error())
+		return
+	}
+
+	diff, err := h.ActorsUsecase.DiffRevisions(id, from, to)
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "actors/http", "DiffActorRevisions", err, err.Error())
+		return
+	}
+
+	domain.WriteResponse(
+		w,
+		map[string]interface{}{
+			"diff": diff,
+		},
+		http.StatusOK,
+	)
+}
+
+// RevertActor godoc
+//
+//	@Summary		Reverts a actor.
+//	@Description	Restores the actor from the revision. The result is saved as a new revision.
+//	@Tags			Actors
+//	@Param			id			path	int		true	"Actor id"
+//	@Param			revision	path	int		true	"Revision to restore"
+//	@Param			If-Match	header	string	false	"ETag of the actor version being reverted"
+//	@Produce		json
+//	@Success		200	{object}	object{body=object{actor=domain.ActorWithoutFilms}}
+//	@Failure		400	{object}	object{err=string}
+//	@Failure		403	{object}	object{err=string}
+//	@Failure		404	{object}	object{err=string}
+//	@Failure		412	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/actors/{id}/revisions/{revision}/revert [post]
+func (h *ActorsHandler) RevertActor(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "actors/http", "RevertActor", err, err.Error())
+		return
+	}
+	revision, err := strconv.Atoi(r.PathValue("revision"))
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "actors/http", "RevertActor", err, err.Error())
+		return
+	}
+
+	version, err := domain.IfMatchVersion(r)
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "actors/http", "RevertActor", err, err.Error())
+		return
+	}
+
+	actor, err := h.ActorsUsecase.Revert(id, revision, version, domain.RequestAuditContext(r))
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "actors/http", "RevertActor", err, err.Error())
+		return
+	}
+
+	domain.SetETag(w, actor.Version)
+
+	domain.WriteResponse(
+		w,
+		map[string]interface{}{
+			"actor": actor,
+		},
+		http.StatusOK,
+	)
+}
+
+// GetDuplicateActors godoc
+//
+//	@Summary		Gets likely duplicate actors.
+//	@Description	Gets pairs of actors born the same day whose names are alike, the most alike first.
+//	@Description	Names are compared lowercased, without punctuation and regardless of the word order.
+//	@Tags			Actors
+//	@Param			threshold	query	number	false	"Minimal name similarity from 0 to 1, 0.8 by default"
+//	@Produce		json
+//	@Success		200	{object}	object{body=object{duplicates=[]domain.DuplicateActors}}
+//	@Failure		400	{object}	object{err=string}
+//	@Failure		403	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/actors/duplicates [get]
+func (h *ActorsHandler) GetDuplicateActors(w http.ResponseWriter, r *http.Request) {
+	threshold := domain.DefaultDuplicateThreshold
+	if t := r.URL.Query().Get(domain.ThresholdParam); t != "" {
+		var err error
+		if threshold, err = strconv.ParseFloat(t, 64); err != nil {
+			domain.WriteError(w, err.Error(), http.StatusBadRequest)
+			logs.LogError(logs.Logger, "actors/http", "GetDuplicateActors", err, err.Error())
+			return
+		}
+	}
+
+	duplicates, err := h.ActorsUsecase.GetDuplicates(threshold)
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "actors/http", "GetDuplicateActors", err, err.Error())
+		return
+	}
+
+	domain.WriteResponse(
+		w,
+		map[string]interface{}{
+			"duplicates": duplicates,
+		},
+		http.StatusOK,
+	)
+}
+
+// MergeActors godoc
+//
+//	@Summary		Merges a duplicate into an actor.
+//	@Description	Moves the film links of the duplicate onto the actor and deletes the duplicate, it doesn`t go to the trash.
+//	@Description	The id of the duplicate is redirected to the actor, the films of the duplicate get a new revision.
+//	@Tags			Actors
+//	@Param			id			path	int	true	"Id of the actor which is kept"
+//	@Param			duplicate	path	int	true	"Id of the duplicate"
+//	@Produce		json
+//	@Success		200	{object}	object{body=object{actor=domain.ActorWithoutFilms}}
+//	@Failure		400	{object}	object{err=string}
+//	@Failure		403	{object}	object{err=string}
+//	@Failure		404	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/actors/{id}/merge/{duplicate} [post]
+func (h *ActorsHandler) MergeActors(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "actors/http", "MergeActors", err, err.Error())
+		return
+	}
+	duplicateID, err := strconv.Atoi(r.PathValue("duplicate"))
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "actors/http", "MergeActors", err, err.Error())
+		return
+	}
+
+	actor, err := h.ActorsUsecase.Merge(id, duplicateID, domain.RequestAuditContext(r))
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "actors/http", "MergeActors", err, err.Error())
+		return
+	}
+
+	domain.WriteResponse(
+		w,
+		map[string]interface{}{
+			"actor": actor,
+		},
+		http.StatusOK,
+	)
+}
+
+// GetDeletedActors godoc
+//
+//	@Summary		Gets actors in the trash.
+//	@Description	Gets deleted actors, recently deleted first. They are purged after the retention period.
+//	@Tags			Actors
+//	@Produce		json
+//	@Success		200	{object}	object{body=object{actors=[]domain.DeletedActor}}
+//	@Failure		403	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/trash/actors [get]
+func (h *ActorsHandler) GetDeletedActors(w http.ResponseWriter, r *http.Request) {
+	actors, err := h.ActorsUsecase.GetDeleted()
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "actors/http", "GetDeletedActors", err, err.Error())
+		return
+	}
+
+	domain.WriteResponse(
+		w,
+		map[string]interface{}{
+			"actors": actors,
+		},
+		http.StatusOK,
+	)
+}
+
+// RestoreActor godoc
+//
+//	@Summary		Restores a actor.
+//	@Description	Restores a deleted actor from the trash with its links.
+//	@Tags			Actors
+//	@Param			id	path	int	true	"Actor id"
+//	@Produce		json
+//	@Success		200	{object}	object{body=object{actor=domain.ActorWithoutFilms}}
+//	@Failure		400	{object}	object{err=string}
+//	@Failure		403	{object}	object{err=string}
+//	@Failure		404	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/trash/actors/{id}/restore [post]
+func (h *ActorsHandler) RestoreActor(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "actors/http", "RestoreActor", err, err.Error())
+		return
+	}
+
+	actor, err := h.ActorsUsecase.Restore(id, domain.RequestAuditContext(r))
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "actors/http", "RestoreActor", err, err.Error())
+		return
+	}
+
+	domain.WriteResponse(
+		w,
+		map[string]interface{}{
+			"actor": actor,
+		},
+		http.StatusOK,
+	)
+}
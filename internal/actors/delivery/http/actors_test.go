@@ -31,9 +31,9 @@ func TestAddActor(t *testing.T) {
 				return []byte(`{ "name":"john", "sex": "M", "birthdate": "2000-01-01" }`)
 			},
 			setUCaseExpectations: func(usecase *mocks.ActorsUsecase) {
-				usecase.On("Add", mock.Anything).Return(1, nil)
+				usecase.On("Add", mock.Anything, mock.Anything).Return(1, nil)
 			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder}),
+			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder, Verified: true}),
 			status: http.StatusOK,
 		},
 		{
@@ -42,9 +42,9 @@ func TestAddActor(t *testing.T) {
 				return []byte(`{"name":"", "sex": "M", "birthdate": "2000-01-01"}`)
 			},
 			setUCaseExpectations: func(usecase *mocks.ActorsUsecase) {
-				usecase.On("Add", mock.Anything).Return(0, domain.ErrBadRequest)
+				usecase.On("Add", mock.Anything, mock.Anything).Return(0, domain.ErrBadRequest)
 			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder}),
+			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder, Verified: true}),
 			status: http.StatusBadRequest,
 		},
 		{
@@ -53,9 +53,9 @@ func TestAddActor(t *testing.T) {
 				return []byte(`{"name":john, "sex": "M", "birthdate": "2003-01-01"}`)
 			},
 			setUCaseExpectations: func(usecase *mocks.ActorsUsecase) {
-				usecase.On("Add", mock.Anything).Return(0, domain.ErrBadRequest).Maybe()
+				usecase.On("Add", mock.Anything, mock.Anything).Return(0, domain.ErrBadRequest).Maybe()
 			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder}),
+			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder, Verified: true}),
 			status: http.StatusBadRequest,
 		},
 		{
@@ -64,9 +64,9 @@ func TestAddActor(t *testing.T) {
 				return []byte(`{"name":"john", "sex": "M", "birthdate": "2000-01-01"}`)
 			},
 			setUCaseExpectations: func(usecase *mocks.ActorsUsecase) {
-				usecase.On("Add", mock.Anything).Return(0, domain.ErrBadRequest).Maybe()
+				usecase.On("Add", mock.Anything, mock.Anything).Return(0, domain.ErrBadRequest).Maybe()
 			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder}),
+			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder, Verified: true}),
 			status: http.StatusBadRequest,
 		},
 		{
@@ -75,9 +75,9 @@ func TestAddActor(t *testing.T) {
 				return []byte(`{"name":"john", sex: "MAT", birthdate: "2000-01-01"}`)
 			},
 			setUCaseExpectations: func(usecase *mocks.ActorsUsecase) {
-				usecase.On("Add", mock.Anything).Return(0, domain.ErrBadRequest).Maybe()
+				usecase.On("Add", mock.Anything, mock.Anything).Return(0, domain.ErrBadRequest).Maybe()
 			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder}),
+			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder, Verified: true}),
 			status: http.StatusBadRequest,
 		},
 		{
@@ -86,9 +86,9 @@ func TestAddActor(t *testing.T) {
 				return []byte(`{"name":"john", "sex": "M", "birthdate": "2033-01-01"}`)
 			},
 			setUCaseExpectations: func(usecase *mocks.ActorsUsecase) {
-				usecase.On("Add", mock.Anything).Return(0, domain.ErrOutOfRange)
+				usecase.On("Add", mock.Anything, mock.Anything).Return(0, domain.ErrOutOfRange)
 			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder}),
+			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder, Verified: true}),
 			status: http.StatusNotFound,
 		},
 		{
@@ -97,33 +97,11 @@ func TestAddActor(t *testing.T) {
 				return []byte(`{"name":"john", "sex": "M", "birthdate": "1000-01-01"}`)
 			},
 			setUCaseExpectations: func(usecase *mocks.ActorsUsecase) {
-				usecase.On("Add", mock.Anything).Return(0, domain.ErrOutOfRange)
+				usecase.On("Add", mock.Anything, mock.Anything).Return(0, domain.ErrOutOfRange)
 			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder}),
+			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder, Verified: true}),
 			status: http.StatusNotFound,
 		},
-		{
-			name: "BadCase/NoUserContext",
-			getBody: func() []byte {
-				return []byte(`{"name":"john", "sex": "M", "birthdate": "2000-01-01"}`)
-			},
-			setUCaseExpectations: func(usecase *mocks.ActorsUsecase) {
-				usecase.On("Add", mock.Anything).Return(0, domain.ErrBadRequest).Maybe()
-			},
-			ctx:    context.Background(),
-			status: http.StatusInternalServerError,
-		},
-		{
-			name: "BadCase/InvalidRole",
-			getBody: func() []byte {
-				return []byte(`{"name":"john", "sex": "M", "birthdate": "2000-01-01"}`)
-			},
-			setUCaseExpectations: func(usecase *mocks.ActorsUsecase) {
-				usecase.On("Add", mock.Anything).Return(0, domain.ErrBadRequest).Maybe()
-			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Usr}),
-			status: http.StatusForbidden,
-		},
 	}
 
 	for _, test := range tests {
@@ -155,64 +133,46 @@ func TestDeleteActor(t *testing.T) {
 		{
 			name: "GoodCase/Common",
 			setUCaseExpectations: func(usecase *mocks.ActorsUsecase, id int) {
-				usecase.On("Remove", id).Return(nil)
+				usecase.On("Remove", id, mock.Anything).Return(nil)
 			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder}),
+			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder, Verified: true}),
 			id:     "1",
 			status: http.StatusNoContent,
 		},
-		{
-			name: "BadCase/InvalidRole",
-			setUCaseExpectations: func(usecase *mocks.ActorsUsecase, id int) {
-				usecase.On("Remove", id).Return(domain.ErrBadRequest).Maybe()
-			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Usr}),
-			id:     "1",
-			status: http.StatusForbidden,
-		},
 		{
 			name: "BadCase/InvalidID",
 			setUCaseExpectations: func(usecase *mocks.ActorsUsecase, id int) {
-				usecase.On("Remove", id).Return(domain.ErrBadRequest).Maybe()
+				usecase.On("Remove", id, mock.Anything).Return(domain.ErrBadRequest).Maybe()
 			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder}),
+			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder, Verified: true}),
 			id:     "invalid_id",
 			status: http.StatusBadRequest,
 		},
 		{
 			name: "BadCase/EmptyID",
 			setUCaseExpectations: func(usecase *mocks.ActorsUsecase, id int) {
-				usecase.On("Remove", id).Return(domain.ErrBadRequest).Maybe()
+				usecase.On("Remove", id, mock.Anything).Return(domain.ErrBadRequest).Maybe()
 			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder}),
+			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder, Verified: true}),
 			id:     "",
 			status: http.StatusNotFound,
 		},
-		{
-			name: "BadCase/NoUserContext",
-			setUCaseExpectations: func(usecase *mocks.ActorsUsecase, id int) {
-				usecase.On("Remove", id).Return(domain.ErrBadRequest).Maybe()
-			},
-			ctx:    context.Background(),
-			id:     "1",
-			status: http.StatusInternalServerError,
-		},
 
 		{
 			name: "BadCase/OutOfRangeVideoId",
 			setUCaseExpectations: func(fvu *mocks.ActorsUsecase, id int) {
-				fvu.On("Remove", id).Return(domain.ErrOutOfRange)
+				fvu.On("Remove", id, mock.Anything).Return(domain.ErrOutOfRange)
 			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder}),
+			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder, Verified: true}),
 			id:     "1234563456789",
 			status: http.StatusNotFound,
 		},
 		{
 			name: "BadCase/NegativeVideoId",
 			setUCaseExpectations: func(fvu *mocks.ActorsUsecase, id int) {
-				fvu.On("Remove", id).Return(domain.ErrOutOfRange)
+				fvu.On("Remove", id, mock.Anything).Return(domain.ErrOutOfRange)
 			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder}),
+			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder, Verified: true}),
 			id:     "-3",
 			status: http.StatusNotFound,
 		},
@@ -262,14 +222,14 @@ func TestModifyActor(t *testing.T) {
 					Name:      "John",
 					Sex:       "M",
 					Birthdate: d,
-				}).Return(domain.Actor{
+				}, mock.Anything).Return(domain.Actor{
 					ID:        1,
 					Name:      "John",
 					Sex:       "M",
 					Birthdate: d,
 				}, nil)
 			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder}),
+			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder, Verified: true}),
 			status: http.StatusOK,
 		},
 		{
@@ -283,9 +243,9 @@ func TestModifyActor(t *testing.T) {
 					Name:      "",
 					Sex:       "M",
 					Birthdate: d,
-				}).Return(domain.Actor{}, domain.ErrBadRequest)
+				}, mock.Anything).Return(domain.Actor{}, domain.ErrBadRequest)
 			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder}),
+			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder, Verified: true}),
 			status: http.StatusBadRequest,
 		},
 		{
@@ -299,9 +259,9 @@ func TestModifyActor(t *testing.T) {
 					Name:      "John",
 					Sex:       "X",
 					Birthdate: d,
-				}).Return(domain.Actor{}, domain.ErrBadRequest)
+				}, mock.Anything).Return(domain.Actor{}, domain.ErrBadRequest)
 			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder}),
+			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder, Verified: true}),
 			status: http.StatusBadRequest,
 		},
 		{
@@ -315,9 +275,9 @@ func TestModifyActor(t *testing.T) {
 					Name:      "John",
 					Sex:       "M",
 					Birthdate: d,
-				}).Return(domain.Actor{}, domain.ErrOutOfRange)
+				}, mock.Anything).Return(domain.Actor{}, domain.ErrOutOfRange)
 			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder}),
+			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder, Verified: true}),
 			status: http.StatusNotFound,
 		},
 		{
@@ -331,38 +291,20 @@ func TestModifyActor(t *testing.T) {
 					Name:      "John",
 					Sex:       "M",
 					Birthdate: d,
-				}).Return(domain.Actor{}, domain.ErrOutOfRange)
+				}, mock.Anything).Return(domain.Actor{}, domain.ErrOutOfRange)
 			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder}),
+			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder, Verified: true}),
 			status: http.StatusNotFound,
 		},
 		{
 			name:        "BadCase/InvalidJSON",
 			requestBody: strings.NewReader(`invalid json`),
 			setUCaseExpectations: func(usecase *mocks.ActorsUsecase) {
-				usecase.On("Modify", mock.Anything).Return(domain.Actor{}, domain.ErrBadRequest).Maybe()
+				usecase.On("Modify", mock.Anything, mock.Anything).Return(domain.Actor{}, domain.ErrBadRequest).Maybe()
 			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder}),
+			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder, Verified: true}),
 			status: http.StatusBadRequest,
 		},
-		{
-			name:        "BadCase/InvalidRole",
-			requestBody: strings.NewReader(`{"name":"John","sex":"M","birthdate":"2000-01-01"}`),
-			setUCaseExpectations: func(usecase *mocks.ActorsUsecase) {
-				usecase.On("Modify", mock.Anything).Return(domain.Actor{}, domain.ErrBadRequest).Maybe()
-			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Usr}),
-			status: http.StatusForbidden,
-		},
-		{
-			name:        "BadCase/NoUserContext",
-			requestBody: strings.NewReader(`{"name":"John","sex":"M","birthdate":"2000-01-01"}`),
-			setUCaseExpectations: func(usecase *mocks.ActorsUsecase) {
-				usecase.On("Modify", mock.Anything).Return(domain.Actor{}, domain.ErrBadRequest).Maybe()
-			},
-			ctx:    context.Background(),
-			status: http.StatusInternalServerError,
-		},
 	}
 
 	for _, test := range tests {
@@ -427,3 +369,201 @@ func TestGetActors(t *testing.T) {
 		})
 	}
 }
+
+func TestRoutesPermissions(t *testing.T) {
+	userCtx := context.WithValue(context.Background(), domain.SessionContextKey,
+		domain.SessionContext{UserID: 1, Role: domain.Usr, Verified: true})
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		ctx    context.Context
+		status int
+	}{
+		{name: "BadCase/UserPost", method: "POST", target: "/actors", ctx: userCtx, status: http.StatusForbidden},
+		{name: "BadCase/UserPut", method: "PUT", target: "/actors", ctx: userCtx, status: http.StatusForbidden},
+		{name: "BadCase/UserDelete", method: "DELETE", target: "/actors/1", ctx: userCtx, status: http.StatusForbidden},
+		{name: "BadCase/NoUserContext", method: "DELETE", target: "/actors/1", ctx: context.Background(), status: http.StatusUnauthorized},
+		{name: "BadCase/UserRevisions", method: "GET", target: "/actors/1/revisions", ctx: userCtx, status: http.StatusForbidden},
+		{name: "BadCase/UserDiff", method: "GET", target: "/actors/1/revisions/diff?from=1&to=2", ctx: userCtx, status: http.StatusForbidden},
+		{name: "BadCase/UserRevert", method: "POST", target: "/actors/1/revisions/2/revert", ctx: userCtx, status: http.StatusForbidden},
+		{name: "BadCase/UserTrash", method: "GET", target: "/trash/actors", ctx: userCtx, status: http.StatusForbidden},
+		{name: "BadCase/UserRestore", method: "POST", target: "/trash/actors/1/restore", ctx: userCtx, status: http.StatusForbidden},
+		{name: "BadCase/UserDuplicates", method: "GET", target: "/actors/duplicates", ctx: userCtx, status: http.StatusForbidden},
+		{name: "BadCase/UserMerge", method: "POST", target: "/actors/1/merge/2", ctx: userCtx, status: http.StatusForbidden},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockUsecase := new(mocks.ActorsUsecase)
+
+			mux := http.NewServeMux()
+			actor_http.NewActorsHandler(mux, mockUsecase)
+
+			req := httptest.NewRequest(test.method, test.target, strings.NewReader(`{}`))
+			req = req.WithContext(test.ctx)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetActor(t *testing.T) {
+	tests := []struct {
+		name                 string
+		setUCaseExpectations func(usecase *mocks.ActorsUsecase)
+		status               int
+		location             string
+	}{
+		{
+			name: "GoodCase/Common",
+			setUCaseExpectations: func(usecase *mocks.ActorsUsecase) {
+				usecase.On("GetById", 3).Return(domain.Actor{ID: 3, Name: "John", Version: 1}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name: "GoodCase/Merged",
+			setUCaseExpectations: func(usecase *mocks.ActorsUsecase) {
+				usecase.On("GetById", 3).Return(domain.Actor{ID: 5, Name: "John", Version: 1}, nil)
+			},
+			status:   http.StatusMovedPermanently,
+			location: "5",
+		},
+		{
+			name: "BadCase/NotFound",
+			setUCaseExpectations: func(usecase *mocks.ActorsUsecase) {
+				usecase.On("GetById", 3).Return(domain.Actor{}, domain.ErrNotFound)
+			},
+			status: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockUsecase := new(mocks.ActorsUsecase)
+			test.setUCaseExpectations(mockUsecase)
+
+			mux := http.NewServeMux()
+			actor_http.NewActorsHandler(mux, mockUsecase)
+
+			req := httptest.NewRequest("GET", "/actors/3", nil)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			assert.Equal(t, test.location, rec.Header().Get("Location"))
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetDuplicateActors(t *testing.T) {
+	moderCtx := context.WithValue(context.Background(), domain.SessionContextKey,
+		domain.SessionContext{UserID: 1, Role: domain.Moder, Verified: true})
+
+	tests := []struct {
+		name                 string
+		target               string
+		setUCaseExpectations func(usecase *mocks.ActorsUsecase)
+		status               int
+	}{
+		{
+			name:   "GoodCase/DefaultThreshold",
+			target: "/actors/duplicates",
+			setUCaseExpectations: func(usecase *mocks.ActorsUsecase) {
+				usecase.On("GetDuplicates", domain.DefaultDuplicateThreshold).Return([]domain.ActorDuplicate{}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:   "GoodCase/Threshold",
+			target: "/actors/duplicates?threshold=0.9",
+			setUCaseExpectations: func(usecase *mocks.ActorsUsecase) {
+				usecase.On("GetDuplicates", 0.9).Return([]domain.ActorDuplicate{}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:                 "BadCase/InvalidThreshold",
+			target:               "/actors/duplicates?threshold=high",
+			setUCaseExpectations: func(usecase *mocks.ActorsUsecase) {},
+			status:               http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockUsecase := new(mocks.ActorsUsecase)
+			test.setUCaseExpectations(mockUsecase)
+
+			mux := http.NewServeMux()
+			actor_http.NewActorsHandler(mux, mockUsecase)
+
+			req := httptest.NewRequest("GET", test.target, nil)
+			req = req.WithContext(moderCtx)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMergeActors(t *testing.T) {
+	moderCtx := context.WithValue(context.Background(), domain.SessionContextKey,
+		domain.SessionContext{UserID: 1, Role: domain.Moder, Verified: true})
+
+	tests := []struct {
+		name                 string
+		target               string
+		setUCaseExpectations func(usecase *mocks.ActorsUsecase)
+		status               int
+	}{
+		{
+			name:   "GoodCase/Common",
+			target: "/actors/1/merge/2",
+			setUCaseExpectations: func(usecase *mocks.ActorsUsecase) {
+				usecase.On("Merge", 1, 2, mock.Anything).Return(domain.Actor{ID: 1, Name: "John"}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:   "BadCase/NotFound",
+			target: "/actors/1/merge/2",
+			setUCaseExpectations: func(usecase *mocks.ActorsUsecase) {
+				usecase.On("Merge", 1, 2, mock.Anything).Return(domain.Actor{}, domain.ErrNotFound)
+			},
+			status: http.StatusNotFound,
+		},
+		{
+			name:                 "BadCase/InvalidDuplicate",
+			target:               "/actors/1/merge/john",
+			setUCaseExpectations: func(usecase *mocks.ActorsUsecase) {},
+			status:               http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockUsecase := new(mocks.ActorsUsecase)
+			test.setUCaseExpectations(mockUsecase)
+
+			mux := http.NewServeMux()
+			actor_http.NewActorsHandler(mux, mockUsecase)
+
+			req := httptest.NewRequest("POST", test.target, nil)
+			req = req.WithContext(moderCtx)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
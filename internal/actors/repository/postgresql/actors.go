@@ -2,10 +2,12 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"math"
+	"time"
 
 	"github.com/ellexo2456/FilmLib/internal/domain"
 	logs "github.com/ellexo2456/FilmLib/internal/logger"
@@ -19,21 +21,26 @@ const insertQuery = `
 `
 
 const deleteQuery = `
-	DELETE FROM actor
+	UPDATE actor
+	SET deleted_at = CURRENT_TIMESTAMP
 	WHERE id = $1
+	  AND deleted_at IS NULL
 `
 
 const updateQuery = `
 	UPDATE actor
-	SET name = $1, sex = $2, birthdate = $3 
+	SET name = $1, sex = $2, birthdate = $3, version = version + 1
 	WHERE id = $4 
-	RETURNING id, name, sex, birthdate
+	  AND deleted_at IS NULL
+	  AND ($5::INT = 0 OR version = $5)
+	RETURNING id, name, sex, birthdate, version
 `
 
 const selectByIdQuery = `
-	SELECT id, name, sex, birthdate  
+	SELECT id, name, sex, birthdate, version
 	FROM actor
 	WHERE id = $1
+	  AND deleted_at IS NULL
 `
 
 const selectAllQuery = `
@@ -48,8 +55,189 @@ const selectAllQuery = `
        COALESCE(f.rating, 0)
 	FROM actor a
          LEFT JOIN film_actor fa ON a.id = fa.actor_id
-         LEFT JOIN film f ON f.id = fa.film_id
-    	
+         LEFT JOIN film f ON f.id = fa.film_id AND f.deleted_at IS NULL
+	WHERE a.deleted_at IS NULL
+`
+
+// the merged actors are deleted too, but they aren`t in the trash
+const selectDeletedQuery = `
+	SELECT id, name, sex, birthdate, deleted_at
+	FROM actor
+	WHERE deleted_at IS NOT NULL
+	  AND NOT EXISTS(SELECT 1 FROM actor_redirect WHERE from_id = actor.id)
+	ORDER BY deleted_at DESC
+`
+
+const restoreQuery = `
+	UPDATE actor
+	SET deleted_at = NULL
+	WHERE id = $1
+	  AND deleted_at IS NOT NULL
+	  AND NOT EXISTS(SELECT 1 FROM actor_redirect WHERE from_id = actor.id)
+	RETURNING id, name, sex, birthdate
+`
+
+// the film links and revisions are removed by the cascade
+const purgeQuery = `
+	DELETE
+	FROM actor
+	WHERE deleted_at < $1
+`
+
+const selectSameBirthdateQuery = `
+	SELECT a.id, a.name, a.sex, a.birthdate
+	FROM actor a
+	WHERE a.deleted_at IS NULL
+	  AND EXISTS(SELECT 1
+	             FROM actor b
+	             WHERE b.birthdate = a.birthdate
+	               AND b.id <> a.id
+	               AND b.deleted_at IS NULL)
+	ORDER BY a.birthdate, a.id
+`
+
+const lockQuery = selectByIdQuery + `
+	FOR UPDATE
+`
+
+// the links the actor already has are skipped, the rest of the duplicate
+// ones go with it
+const moveLinksQuery = `
+	INSERT INTO film_actor (film_id, actor_id)
+	SELECT film_id, $1
+	FROM film_actor
+	WHERE actor_id = $2
+	ON CONFLICT DO NOTHING
+`
+
+// the ids merged into the duplicate before point to the actor too
+const moveRedirectsQuery = `
+	UPDATE actor_redirect
+	SET to_id = $1
+	WHERE to_id = $2
+`
+
+const insertRedirectQuery = `
+	INSERT INTO actor_redirect (from_id, to_id)
+	VALUES ($2, $1)
+`
+
+// the duplicate is deleted the usual way, so the incremental export
+// reports it. Its source id is released for the actor, the old one is
+// returned
+const deleteDuplicateQuery = `
+	UPDATE actor a
+	SET deleted_at = CURRENT_TIMESTAMP, source_id = NULL
+	FROM actor d
+	WHERE a.id = $1
+	  AND d.id = a.id
+	  AND a.deleted_at IS NULL
+	RETURNING d.source_id
+`
+
+// the dataset import finds the actor by the source id of the duplicate
+// unless the actor has its own
+const moveSourceIDQuery = `
+	UPDATE actor
+	SET source_id = $2
+	WHERE id = $1
+	  AND source_id IS NULL
+`
+
+// filmSnapshot builds the json of the film f the same way the films
+// repository does, the merge changes the casts of the duplicate films
+const filmSnapshot = `
+	jsonb_build_object(
+		'id', f.id,
+		'title', f.title,
+		'description', f.description,
+		'releaseDate', f.release_date,
+		'rating', f.rating,
+		'actors', COALESCE((SELECT jsonb_agg(jsonb_build_object(
+		                               'id', a.id,
+		                               'name', a.name,
+		                               'sex', a.sex,
+		                               'birthdate', a.birthdate) ORDER BY a.id)
+		                    FROM film_actor fa
+		                             JOIN actor a ON a.id = fa.actor_id
+		                    WHERE fa.film_id = f.id
+		                      AND a.deleted_at IS NULL), '[]'::JSONB))
+`
+
+// the films of the duplicate without the history get their state before
+// the merge as the first revision
+const insertFilmBaseRevisionsQuery = `
+	INSERT INTO film_revision (film_id, revision, user_id, data)
+	SELECT f.id, 1, NULL,` + filmSnapshot + `
+	FROM film f
+	WHERE f.id IN (SELECT film_id FROM film_actor WHERE actor_id = $1)
+	  AND NOT EXISTS(SELECT 1 FROM film_revision WHERE film_id = f.id)
+	ON CONFLICT DO NOTHING
+`
+
+const bumpFilmVersionsQuery = `
+	UPDATE film
+	SET version = version + 1
+	WHERE id IN (SELECT film_id FROM film_actor WHERE actor_id = $1)
+`
+
+const insertFilmRevisionsQuery = `
+	INSERT INTO film_revision (film_id, revision, user_id, data)
+	SELECT f.id,
+	       COALESCE((SELECT MAX(revision) FROM film_revision WHERE film_id = f.id), 0) + 1,
+	       NULLIF($2, 0),` + filmSnapshot + `
+	FROM film f
+	WHERE f.id IN (SELECT film_id FROM film_actor WHERE actor_id = $1)
+`
+
+const selectRedirectQuery = `
+	SELECT to_id
+	FROM actor_redirect
+	WHERE from_id = $1
+`
+
+// actorSnapshot builds the json of the actor a.
+const actorSnapshot = `
+	jsonb_build_object(
+		'id', a.id,
+		'name', a.name,
+		'sex', a.sex,
+		'birthdate', a.birthdate)
+`
+
+const insertRevisionQuery = `
+	INSERT INTO actor_revision (actor_id, revision, user_id, data)
+	SELECT a.id,
+	       COALESCE((SELECT MAX(revision) FROM actor_revision WHERE actor_id = a.id), 0) + 1,
+	       NULLIF($2, 0),` + actorSnapshot + `
+	FROM actor a
+	WHERE a.id = $1
+`
+
+// actors created before the history was kept get their current state
+// as the first revision, it runs before the row is locked, so a concurrent
+// first edit may have saved it already
+const insertBaseRevisionQuery = `
+	INSERT INTO actor_revision (actor_id, revision, user_id, data)
+	SELECT a.id, 1, NULL,` + actorSnapshot + `
+	FROM actor a
+	WHERE a.id = $1
+	  AND NOT EXISTS(SELECT 1 FROM actor_revision WHERE actor_id = a.id)
+	ON CONFLICT DO NOTHING
+`
+
+const selectRevisionsQuery = `
+	SELECT revision, COALESCE(user_id, 0), created_at, data
+	FROM actor_revision
+	WHERE actor_id = $1
+	ORDER BY revision DESC
+`
+
+const selectRevisionQuery = `
+	SELECT revision, COALESCE(user_id, 0), created_at, data
+	FROM actor_revision
+	WHERE actor_id = $1
+	  AND revision = $2
 `
 
 type actorsPostgresqlRepository struct {
@@ -64,11 +252,18 @@ func NewActorsPostgresqlRepository(pool domain.PgxPoolIface, ctx context.Context
 	}
 }
 
-func (r *actorsPostgresqlRepository) Insert(actor domain.Actor) (int, error) {
-	row := r.db.QueryRow(r.ctx, insertQuery, actor.Name, actor.Sex, actor.Birthdate)
+func (r *actorsPostgresqlRepository) Insert(actor domain.Actor, userID int) (int, error) {
+	tx, err := r.db.Begin(r.ctx)
+	if err != nil {
+		logs.LogError(logs.Logger, "actors/postgres", "Insert", err, err.Error())
+		return 0, err
+	}
+	defer tx.Rollback(r.ctx)
+
+	row := tx.QueryRow(r.ctx, insertQuery, actor.Name, actor.Sex, actor.Birthdate)
 
 	var id int
-	err := row.Scan(
+	err = row.Scan(
 		&id,
 	)
 
@@ -82,6 +277,17 @@ func (r *actorsPostgresqlRepository) Insert(actor domain.Actor) (int, error) {
 
 		return 0, err
 	}
+
+	if _, err = tx.Exec(r.ctx, insertRevisionQuery, id, userID); err != nil {
+		logs.LogError(logs.Logger, "actors/postgres", "Insert", err, err.Error())
+		return 0, err
+	}
+
+	if err = tx.Commit(r.ctx); err != nil {
+		logs.LogError(logs.Logger, "actors/postgres", "Insert", err, "can`t commit changes")
+		return 0, err
+	}
+
 	return id, nil
 }
 
@@ -100,27 +306,31 @@ func (r *actorsPostgresqlRepository) Delete(id int) error {
 	return nil
 }
 
-func (r *actorsPostgresqlRepository) Update(actor domain.Actor) (domain.Actor, error) {
-	row := r.db.QueryRow(r.ctx, updateQuery, actor.Name, actor.Sex, actor.Birthdate, actor.ID)
+func (r *actorsPostgresqlRepository) Update(actor domain.Actor, userID int) (domain.Actor, error) {
+	tx, err := r.db.Begin(r.ctx)
+	if err != nil {
+		logs.LogError(logs.Logger, "actors/postgres", "Update", err, err.Error())
+		return domain.Actor{}, err
+	}
+	defer tx.Rollback(r.ctx)
 
-	err := row.Scan(
-		&actor.ID,
-		&actor.Name,
-		&actor.Sex,
-		&actor.Birthdate,
-	)
-	if errors.Is(err, pgx.ErrNoRows) {
+	if _, err = tx.Exec(r.ctx, insertBaseRevisionQuery, actor.ID); err != nil {
 		logs.LogError(logs.Logger, "actors/postgres", "Update", err, err.Error())
-		return domain.Actor{}, domain.ErrNotFound
+		return domain.Actor{}, err
 	}
+
+	actor, err = updateActor(r.ctx, tx, actor)
 	if err != nil {
-		logs.LogError(logs.Logger, "actors/postgres", "Update", err, err.Error())
+		return domain.Actor{}, err
+	}
 
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == domain.DateOutOfRangeErrCode {
-			return domain.Actor{}, domain.ErrOutOfRange
-		}
+	if _, err = tx.Exec(r.ctx, insertRevisionQuery, actor.ID, userID); err != nil {
+		logs.LogError(logs.Logger, "actors/postgres", "Update", err, err.Error())
+		return domain.Actor{}, err
+	}
 
+	if err = tx.Commit(r.ctx); err != nil {
+		logs.LogError(logs.Logger, "actors/postgres", "Update", err, "can`t commit changes")
 		return domain.Actor{}, err
 	}
 
@@ -136,6 +346,7 @@ func (r *actorsPostgresqlRepository) SelectById(id int) (domain.Actor, error) {
 		&actor.Name,
 		&actor.Sex,
 		&actor.Birthdate,
+		&actor.Version,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		logs.LogError(logs.Logger, "actors/postgres", "SelectById", err, err.Error())
@@ -214,3 +425,305 @@ func (r *actorsPostgresqlRepository) SelectAll() ([]domain.Actor, error) {
 
 	return actors, nil
 }
+
+func (r *actorsPostgresqlRepository) SelectRevisions(actorID int) ([]domain.ActorRevision, error) {
+	rows, err := r.db.Query(r.ctx, selectRevisionsQuery, actorID)
+	if err != nil {
+		logs.LogError(logs.Logger, "actors/postgres", "SelectRevisions", err, err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := []domain.ActorRevision{}
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			logs.LogError(logs.Logger, "actors/postgres", "SelectRevisions", err, err.Error())
+			return nil, err
+		}
+
+		revisions = append(revisions, revision)
+	}
+	if err = rows.Err(); err != nil {
+		logs.LogError(logs.Logger, "actors/postgres", "SelectRevisions", err, err.Error())
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+func (r *actorsPostgresqlRepository) SelectRevision(actorID, revision int) (domain.ActorRevision, error) {
+	rev, err := scanRevision(r.db.QueryRow(r.ctx, selectRevisionQuery, actorID, revision))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ActorRevision{}, domain.ErrNotFound
+	}
+	if err != nil {
+		logs.LogError(logs.Logger, "actors/postgres", "SelectRevision", err, err.Error())
+		return domain.ActorRevision{}, err
+	}
+
+	return rev, nil
+}
+
+func (r *actorsPostgresqlRepository) Revert(actorID, revision, version, userID int) (domain.Actor, error) {
+	tx, err := r.db.Begin(r.ctx)
+	if err != nil {
+		logs.LogError(logs.Logger, "actors/postgres", "Revert", err, err.Error())
+		return domain.Actor{}, err
+	}
+	defer tx.Rollback(r.ctx)
+
+	rev, err := scanRevision(tx.QueryRow(r.ctx, selectRevisionQuery, actorID, revision))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Actor{}, domain.ErrNotFound
+	}
+	if err != nil {
+		logs.LogError(logs.Logger, "actors/postgres", "Revert", err, err.Error())
+		return domain.Actor{}, err
+	}
+	rev.Actor.ID = actorID
+	rev.Actor.Version = version
+
+	actor, err := updateActor(r.ctx, tx, rev.Actor)
+	if err != nil {
+		return domain.Actor{}, err
+	}
+
+	if _, err = tx.Exec(r.ctx, insertRevisionQuery, actorID, userID); err != nil {
+		logs.LogError(logs.Logger, "actors/postgres", "Revert", err, err.Error())
+		return domain.Actor{}, err
+	}
+
+	if err = tx.Commit(r.ctx); err != nil {
+		logs.LogError(logs.Logger, "actors/postgres", "Revert", err, "can`t commit changes")
+		return domain.Actor{}, err
+	}
+
+	return actor, nil
+}
+
+func updateActor(ctx context.Context, tx pgx.Tx, actor domain.Actor) (domain.Actor, error) {
+	row := tx.QueryRow(ctx, updateQuery, actor.Name, actor.Sex, actor.Birthdate, actor.ID, actor.Version)
+
+	expected := actor.Version
+	err := row.Scan(
+		&actor.ID,
+		&actor.Name,
+		&actor.Sex,
+		&actor.Birthdate,
+		&actor.Version,
+	)
+	if errors.Is(err, pgx.ErrNoRows) && expected != 0 {
+		return domain.Actor{}, domain.ErrPreconditionFailed
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		logs.LogError(logs.Logger, "actors/postgres", "Update", err, err.Error())
+		return domain.Actor{}, domain.ErrNotFound
+	}
+	if err != nil {
+		logs.LogError(logs.Logger, "actors/postgres", "Update", err, err.Error())
+
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == domain.DateOutOfRangeErrCode {
+			return domain.Actor{}, domain.ErrOutOfRange
+		}
+
+		return domain.Actor{}, err
+	}
+
+	return actor, nil
+}
+
+func scanRevision(row pgx.Row) (domain.ActorRevision, error) {
+	var rev domain.ActorRevision
+	var data []byte
+	if err := row.Scan(&rev.Revision, &rev.UserID, &rev.CreatedAt, &data); err != nil {
+		return domain.ActorRevision{}, err
+	}
+	if err := json.Unmarshal(data, &rev.Actor); err != nil {
+		return domain.ActorRevision{}, err
+	}
+
+	return rev, nil
+}
+
+func (r *actorsPostgresqlRepository) SelectDeleted() ([]domain.Actor, error) {
+	rows, err := r.db.Query(r.ctx, selectDeletedQuery)
+	if err != nil {
+		logs.LogError(logs.Logger, "actors/postgres", "SelectDeleted", err, err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	actors := []domain.Actor{}
+	for rows.Next() {
+		var actor domain.Actor
+		err = rows.Scan(
+			&actor.ID,
+			&actor.Name,
+			&actor.Sex,
+			&actor.Birthdate,
+			&actor.DeletedAt,
+		)
+		if err != nil {
+			logs.LogError(logs.Logger, "actors/postgres", "SelectDeleted", err, err.Error())
+			return nil, err
+		}
+
+		actors = append(actors, actor)
+	}
+	if err = rows.Err(); err != nil {
+		logs.LogError(logs.Logger, "actors/postgres", "SelectDeleted", err, err.Error())
+		return nil, err
+	}
+
+	return actors, nil
+}
+
+func (r *actorsPostgresqlRepository) Restore(id int) (domain.Actor, error) {
+	var actor domain.Actor
+	err := r.db.QueryRow(r.ctx, restoreQuery, id).Scan(
+		&actor.ID,
+		&actor.Name,
+		&actor.Sex,
+		&actor.Birthdate,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Actor{}, domain.ErrNotFound
+	}
+	if err != nil {
+		logs.LogError(logs.Logger, "actors/postgres", "Restore", err, err.Error())
+		return domain.Actor{}, err
+	}
+
+	return actor, nil
+}
+
+func (r *actorsPostgresqlRepository) Purge(before time.Time) (int, error) {
+	res, err := r.db.Exec(r.ctx, purgeQuery, before)
+	if err != nil {
+		logs.LogError(logs.Logger, "actors/postgres", "Purge", err, err.Error())
+		return 0, err
+	}
+
+	return int(res.RowsAffected()), nil
+}
+
+func (r *actorsPostgresqlRepository) SelectSameBirthdate() ([]domain.Actor, error) {
+	rows, err := r.db.Query(r.ctx, selectSameBirthdateQuery)
+	if err != nil {
+		logs.LogError(logs.Logger, "actors/postgres", "SelectSameBirthdate", err, err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	actors := []domain.Actor{}
+	for rows.Next() {
+		var actor domain.Actor
+		err = rows.Scan(
+			&actor.ID,
+			&actor.Name,
+			&actor.Sex,
+			&actor.Birthdate,
+		)
+		if err != nil {
+			logs.LogError(logs.Logger, "actors/postgres", "SelectSameBirthdate", err, err.Error())
+			return nil, err
+		}
+
+		actors = append(actors, actor)
+	}
+	if err = rows.Err(); err != nil {
+		logs.LogError(logs.Logger, "actors/postgres", "SelectSameBirthdate", err, err.Error())
+		return nil, err
+	}
+
+	return actors, nil
+}
+
+func (r *actorsPostgresqlRepository) Merge(id, duplicateID, userID int) (domain.Actor, error) {
+	tx, err := r.db.Begin(r.ctx)
+	if err != nil {
+		logs.LogError(logs.Logger, "actors/postgres", "Merge", err, err.Error())
+		return domain.Actor{}, err
+	}
+	defer tx.Rollback(r.ctx)
+
+	var actor domain.Actor
+	err = tx.QueryRow(r.ctx, lockQuery, id).Scan(
+		&actor.ID,
+		&actor.Name,
+		&actor.Sex,
+		&actor.Birthdate,
+		&actor.Version,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Actor{}, domain.ErrNotFound
+	}
+	if err != nil {
+		logs.LogError(logs.Logger, "actors/postgres", "Merge", err, err.Error())
+		return domain.Actor{}, err
+	}
+
+	if _, err = tx.Exec(r.ctx, insertFilmBaseRevisionsQuery, duplicateID); err != nil {
+		logs.LogError(logs.Logger, "actors/postgres", "Merge", err, err.Error())
+		return domain.Actor{}, err
+	}
+
+	for _, query := range []string{moveLinksQuery, moveRedirectsQuery, insertRedirectQuery} {
+		if _, err = tx.Exec(r.ctx, query, id, duplicateID); err != nil {
+			logs.LogError(logs.Logger, "actors/postgres", "Merge", err, err.Error())
+			return domain.Actor{}, err
+		}
+	}
+
+	// the film links and revisions of the duplicate are kept until the
+	// trash purge removes it
+	var sourceID *string
+	err = tx.QueryRow(r.ctx, deleteDuplicateQuery, duplicateID).Scan(&sourceID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Actor{}, domain.ErrNotFound
+	}
+	if err != nil {
+		logs.LogError(logs.Logger, "actors/postgres", "Merge", err, err.Error())
+		return domain.Actor{}, err
+	}
+
+	if sourceID != nil {
+		if _, err = tx.Exec(r.ctx, moveSourceIDQuery, id, *sourceID); err != nil {
+			logs.LogError(logs.Logger, "actors/postgres", "Merge", err, err.Error())
+			return domain.Actor{}, err
+		}
+	}
+
+	// the duplicate films are the ones which changed their cast
+	if _, err = tx.Exec(r.ctx, bumpFilmVersionsQuery, duplicateID); err != nil {
+		logs.LogError(logs.Logger, "actors/postgres", "Merge", err, err.Error())
+		return domain.Actor{}, err
+	}
+	if _, err = tx.Exec(r.ctx, insertFilmRevisionsQuery, duplicateID, userID); err != nil {
+		logs.LogError(logs.Logger, "actors/postgres", "Merge", err, err.Error())
+		return domain.Actor{}, err
+	}
+
+	if err = tx.Commit(r.ctx); err != nil {
+		logs.LogError(logs.Logger, "actors/postgres", "Merge", err, "can`t commit changes")
+		return domain.Actor{}, err
+	}
+
+	return actor, nil
+}
+
+func (r *actorsPostgresqlRepository) SelectRedirect(id int) (int, error) {
+	var to int
+	err := r.db.QueryRow(r.ctx, selectRedirectQuery, id).Scan(&to)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, domain.ErrNotFound
+	}
+	if err != nil {
+		logs.LogError(logs.Logger, "actors/postgres", "SelectRedirect", err, err.Error())
+		return 0, err
+	}
+
+	return to, nil
+}
@@ -1,50 +1,95 @@
 package usecase
 
 import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
 	"github.com/ellexo2456/FilmLib/internal/domain"
 	logs "github.com/ellexo2456/FilmLib/internal/logger"
 )
 
 type actorsUsecase struct {
 	actorsRepo domain.ActorsRepository
+	audit      domain.AuditUsecase
+	events     domain.EventEmitter
 }
 
-func NewActorsUsecase(ar domain.ActorsRepository) domain.ActorsUsecase {
+func NewActorsUsecase(ar domain.ActorsRepository, au domain.AuditUsecase, ee domain.EventEmitter) domain.ActorsUsecase {
 	return &actorsUsecase{
 		actorsRepo: ar,
+		audit:      au,
+		events:     ee,
 	}
 }
 
-func (u *actorsUsecase) Add(actor domain.Actor) (int, error) {
+func (u *actorsUsecase) Add(actor domain.Actor, ac domain.AuditContext) (int, error) {
 	if actor.Name == "" || actor.Sex == "" || !actor.Birthdate.Valid {
 		return 0, domain.ErrBadRequest
 	}
 
-	id, err := u.actorsRepo.Insert(actor)
+	id, err := u.actorsRepo.Insert(actor, ac.UserID)
 	if err != nil {
 		logs.LogError(logs.Logger, "actors/usecase", "Add", err, err.Error())
 		return 0, err
 	}
 
 	logs.Logger.Debug("actors/usecase Add:\n", id)
+	actor.ID = id
+	u.audit.Record(ac, domain.AuditCreate, domain.AuditActor, id, nil, actor)
+	u.events.Emit(domain.ActorCreated, actor)
+
 	return id, nil
 }
 
-func (u *actorsUsecase) Remove(id int) error {
+// Remove reads the actor first, it is kept in the audit log.
+func (u *actorsUsecase) Remove(id int, ac domain.AuditContext) error {
 	if id <= 0 {
 		return domain.ErrNotFound
 	}
 
-	err := u.actorsRepo.Delete(id)
+	actor, err := u.actorsRepo.SelectById(id)
 	if err != nil {
 		logs.LogError(logs.Logger, "actors/usecase", "Remove", err, err.Error())
 		return err
 	}
 
+	err = u.actorsRepo.Delete(id)
+	if err != nil {
+		logs.LogError(logs.Logger, "actors/usecase", "Remove", err, err.Error())
+		return err
+	}
+	u.audit.Record(ac, domain.AuditDelete, domain.AuditActor, id, actor, nil)
+	u.events.Emit(domain.ActorDeleted, actor)
+
 	return nil
 }
 
-func (u *actorsUsecase) Modify(newActor domain.Actor) (domain.Actor, error) {
+func (u *actorsUsecase) GetById(id int) (domain.Actor, error) {
+	if id <= 0 {
+		return domain.Actor{}, domain.ErrNotFound
+	}
+
+	actor, err := u.actorsRepo.SelectById(id)
+	if errors.Is(err, domain.ErrNotFound) {
+		var to int
+		if to, err = u.actorsRepo.SelectRedirect(id); err == nil {
+			actor, err = u.actorsRepo.SelectById(to)
+		}
+	}
+	if err != nil {
+		logs.LogError(logs.Logger, "actors/usecase", "GetById", err, err.Error())
+		return domain.Actor{}, err
+	}
+
+	return actor, nil
+}
+
+func (u *actorsUsecase) Modify(newActor domain.Actor, ac domain.AuditContext) (domain.Actor, error) {
 	if newActor.ID <= 0 {
 		return domain.Actor{}, domain.ErrNotFound
 	}
@@ -55,15 +100,23 @@ func (u *actorsUsecase) Modify(newActor domain.Actor) (domain.Actor, error) {
 		return domain.Actor{}, err
 	}
 	logs.Logger.Debug("actors/usecase Modify old actor:\n", oldActor)
+	if newActor.Version != 0 && newActor.Version != oldActor.Version {
+		return domain.Actor{}, domain.ErrPreconditionFailed
+	}
 
 	newActor = getOldFields(newActor, oldActor)
-	updatedActor, err := u.actorsRepo.Update(newActor)
+	updatedActor, err := u.actorsRepo.Update(newActor, ac.UserID)
 	if err != nil {
 		logs.LogError(logs.Logger, "actors/usecase", "Modify", err, err.Error())
 		return domain.Actor{}, err
 	}
 	logs.Logger.Debug("actors/usecase Modify updated actor:\n", updatedActor)
 
+	after := updatedActor
+	after.Films = nil
+	u.audit.Record(ac, domain.AuditUpdate, domain.AuditActor, after.ID, oldActor, after)
+	u.events.Emit(domain.ActorUpdated, after)
+
 	return updatedActor, nil
 }
 
@@ -80,6 +133,253 @@ func (u *actorsUsecase) GetAll() ([]domain.Actor, error) {
 
 }
 
+// Patch updates the actor on condition that it is still of the version the
+// patch was applied to, so concurrent changes aren`t lost.
+func (u *actorsUsecase) Patch(id int, patch domain.Patch, ac domain.AuditContext) (domain.Actor, error) {
+	if id <= 0 {
+		return domain.Actor{}, domain.ErrNotFound
+	}
+
+	oldActor, err := u.actorsRepo.SelectById(id)
+	if err != nil {
+		logs.LogError(logs.Logger, "actors/usecase", "Patch", err, err.Error())
+		return domain.Actor{}, err
+	}
+	if patch.Version != 0 && patch.Version != oldActor.Version {
+		return domain.Actor{}, domain.ErrPreconditionFailed
+	}
+
+	newActor := oldActor
+	if err = patch.ApplyTo(&newActor, "name", "sex", "birthdate"); err != nil {
+		logs.LogError(logs.Logger, "actors/usecase", "Patch", err, err.Error())
+		return domain.Actor{}, err
+	}
+	if err = newActor.Validate(); err != nil {
+		logs.LogError(logs.Logger, "actors/usecase", "Patch", err, err.Error())
+		return domain.Actor{}, err
+	}
+
+	updated, err := u.actorsRepo.Update(newActor, ac.UserID)
+	if err != nil {
+		logs.LogError(logs.Logger, "actors/usecase", "Patch", err, err.Error())
+		return domain.Actor{}, err
+	}
+	u.audit.Record(ac, domain.AuditUpdate, domain.AuditActor, id, oldActor, updated)
+	u.events.Emit(domain.ActorUpdated, updated)
+
+	return updated, nil
+}
+
+func (u *actorsUsecase) GetRevisions(actorID int) ([]domain.ActorRevision, error) {
+	if actorID <= 0 {
+		return nil, domain.ErrNotFound
+	}
+
+	revisions, err := u.actorsRepo.SelectRevisions(actorID)
+	if err != nil {
+		logs.LogError(logs.Logger, "actors/usecase", "GetRevisions", err, err.Error())
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+func (u *actorsUsecase) DiffRevisions(actorID, from, to int) (domain.RevisionDiff, error) {
+	if actorID <= 0 || from <= 0 || to <= 0 {
+		return domain.RevisionDiff{}, domain.ErrBadRequest
+	}
+
+	fromRev, err := u.actorsRepo.SelectRevision(actorID, from)
+	if err != nil {
+		logs.LogError(logs.Logger, "actors/usecase", "DiffRevisions", err, err.Error())
+		return domain.RevisionDiff{}, err
+	}
+	toRev, err := u.actorsRepo.SelectRevision(actorID, to)
+	if err != nil {
+		logs.LogError(logs.Logger, "actors/usecase", "DiffRevisions", err, err.Error())
+		return domain.RevisionDiff{}, err
+	}
+
+	changes, err := domain.Diff(fromRev.Actor, toRev.Actor)
+	if err != nil {
+		logs.LogError(logs.Logger, "actors/usecase", "DiffRevisions", err, err.Error())
+		return domain.RevisionDiff{}, domain.ErrInternalServerError
+	}
+
+	return domain.RevisionDiff{From: from, To: to, Changes: changes}, nil
+}
+
+// Revert reads the actor first, the state it replaces is kept in the audit log.
+func (u *actorsUsecase) Revert(actorID, revision, version int, ac domain.AuditContext) (domain.Actor, error) {
+	if actorID <= 0 || revision <= 0 {
+		return domain.Actor{}, domain.ErrNotFound
+	}
+
+	oldActor, err := u.actorsRepo.SelectById(actorID)
+	if err != nil {
+		logs.LogError(logs.Logger, "actors/usecase", "Revert", err, err.Error())
+		return domain.Actor{}, err
+	}
+	if version != 0 && version != oldActor.Version {
+		return domain.Actor{}, domain.ErrPreconditionFailed
+	}
+
+	reverted, err := u.actorsRepo.Revert(actorID, revision, version, ac.UserID)
+	if err != nil {
+		logs.LogError(logs.Logger, "actors/usecase", "Revert", err, err.Error())
+		return domain.Actor{}, err
+	}
+	u.audit.Record(ac, domain.AuditUpdate, domain.AuditActor, actorID, oldActor, reverted)
+	u.events.Emit(domain.ActorUpdated, reverted)
+
+	return reverted, nil
+}
+
+func (u *actorsUsecase) GetDeleted() ([]domain.Actor, error) {
+	actors, err := u.actorsRepo.SelectDeleted()
+	if err != nil {
+		logs.LogError(logs.Logger, "actors/usecase", "GetDeleted", err, err.Error())
+		return nil, err
+	}
+
+	return actors, nil
+}
+
+func (u *actorsUsecase) Restore(id int, ac domain.AuditContext) (domain.Actor, error) {
+	if id <= 0 {
+		return domain.Actor{}, domain.ErrNotFound
+	}
+
+	actor, err := u.actorsRepo.Restore(id)
+	if err != nil {
+		logs.LogError(logs.Logger, "actors/usecase", "Restore", err, err.Error())
+		return domain.Actor{}, err
+	}
+	u.audit.Record(ac, domain.AuditRestore, domain.AuditActor, id, nil, actor)
+	u.events.Emit(domain.ActorRestored, actor)
+
+	return actor, nil
+}
+
+// Purge does nothing for a non-positive retention, the trash is kept forever then.
+func (u *actorsUsecase) Purge(retention time.Duration) (int, error) {
+	if retention <= 0 {
+		return 0, nil
+	}
+
+	count, err := u.actorsRepo.Purge(time.Now().Add(-retention))
+	if err != nil {
+		logs.LogError(logs.Logger, "actors/usecase", "Purge", err, err.Error())
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetDuplicates compares the names of the actors born the same day, the
+// most alike pairs go first.
+func (u *actorsUsecase) GetDuplicates(threshold float64) ([]domain.ActorDuplicate, error) {
+	if threshold <= 0 || threshold > 1 {
+		return nil, domain.ErrBadRequest
+	}
+
+	actors, err := u.actorsRepo.SelectSameBirthdate()
+	if err != nil {
+		logs.LogError(logs.Logger, "actors/usecase", "GetDuplicates", err, err.Error())
+		return nil, err
+	}
+
+	names := make([]string, len(actors))
+	for i, actor := range actors {
+		names[i] = normalizeName(actor.Name)
+	}
+
+	duplicates := []domain.ActorDuplicate{}
+	for i := range actors {
+		for j := i + 1; j < len(actors) && actors[j].Birthdate == actors[i].Birthdate; j++ {
+			similarity := nameSimilarity(names[i], names[j])
+			if similarity >= threshold {
+				duplicates = append(duplicates, domain.ActorDuplicate{
+					Actors:     [2]domain.Actor{actors[i], actors[j]},
+					Similarity: similarity,
+				})
+			}
+		}
+	}
+	sort.SliceStable(duplicates, func(i, j int) bool {
+		return duplicates[i].Similarity > duplicates[j].Similarity
+	})
+
+	return duplicates, nil
+}
+
+// Merge reads the duplicate first, it is kept in the audit log.
+func (u *actorsUsecase) Merge(id, duplicateID int, ac domain.AuditContext) (domain.Actor, error) {
+	if id <= 0 || duplicateID <= 0 {
+		return domain.Actor{}, domain.ErrNotFound
+	}
+	if id == duplicateID {
+		return domain.Actor{}, fmt.Errorf("%w: actor can`t be merged into itself", domain.ErrBadRequest)
+	}
+
+	duplicate, err := u.actorsRepo.SelectById(duplicateID)
+	if err != nil {
+		logs.LogError(logs.Logger, "actors/usecase", "Merge", err, err.Error())
+		return domain.Actor{}, err
+	}
+
+	actor, err := u.actorsRepo.Merge(id, duplicateID, ac.UserID)
+	if err != nil {
+		logs.LogError(logs.Logger, "actors/usecase", "Merge", err, err.Error())
+		return domain.Actor{}, err
+	}
+	u.audit.Record(ac, domain.AuditMerge, domain.AuditActor, duplicateID, duplicate, actor)
+	u.events.Emit(domain.ActorMerged, domain.MergedActor{Actor: actor, DuplicateID: duplicateID})
+
+	return actor, nil
+}
+
+// normalizeName lowercases the name and drops everything but letters and
+// digits, the words are sorted so the order of the name parts doesn`t
+// matter.
+func normalizeName(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	sort.Strings(words)
+
+	return strings.Join(words, " ")
+}
+
+// nameSimilarity is one minus the edit distance of the names divided by
+// the length of the longer one, rounded to hundredths.
+func nameSimilarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 && len(rb) == 0 {
+		return 1
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	similarity := 1 - float64(prev[len(rb)])/float64(max(len(ra), len(rb)))
+	return math.Round(similarity*100) / 100
+}
+
 func getOldFields(newActor, oldActor domain.Actor) domain.Actor {
 	if newActor.Name == "" {
 		newActor.Name = oldActor.Name
@@ -33,7 +33,7 @@ func TestAdd(t *testing.T) {
 				}
 			},
 			setActorsRepoExpectation: func(actorsRepo *mocks.ActorsRepository, id int, err error) {
-				actorsRepo.On("Insert", mock.Anything).Return(id, err)
+				actorsRepo.On("Insert", mock.Anything, ac.UserID).Return(id, err)
 			},
 			expectedID:    1,
 			expectedError: nil,
@@ -51,7 +51,7 @@ func TestAdd(t *testing.T) {
 				}
 			},
 			setActorsRepoExpectation: func(actorsRepo *mocks.ActorsRepository, id int, err error) {
-				actorsRepo.On("Insert", mock.Anything).Return(id, err)
+				actorsRepo.On("Insert", mock.Anything, ac.UserID).Return(id, err)
 			},
 			expectedID:    0,
 			expectedError: domain.ErrBadRequest,
@@ -69,7 +69,7 @@ func TestAdd(t *testing.T) {
 				}
 			},
 			setActorsRepoExpectation: func(actorsRepo *mocks.ActorsRepository, id int, err error) {
-				actorsRepo.On("Insert", mock.Anything).Return(id, err)
+				actorsRepo.On("Insert", mock.Anything, ac.UserID).Return(id, err)
 			},
 			expectedID:    1,
 			expectedError: nil,
@@ -80,7 +80,7 @@ func TestAdd(t *testing.T) {
 				return domain.Actor{}
 			},
 			setActorsRepoExpectation: func(actorsRepo *mocks.ActorsRepository, id int, err error) {
-				actorsRepo.On("Insert", mock.Anything).Return(id, err).Maybe()
+				actorsRepo.On("Insert", mock.Anything, ac.UserID).Return(id, err).Maybe()
 			},
 			expectedID:    0,
 			expectedError: domain.ErrBadRequest,
@@ -97,7 +97,7 @@ func TestAdd(t *testing.T) {
 				}
 			},
 			setActorsRepoExpectation: func(actorsRepo *mocks.ActorsRepository, id int, err error) {
-				actorsRepo.On("Insert", mock.Anything).Return(id, err).Maybe()
+				actorsRepo.On("Insert", mock.Anything, ac.UserID).Return(id, err).Maybe()
 			},
 			expectedID:    0,
 			expectedError: domain.ErrBadRequest,
@@ -114,7 +114,7 @@ func TestAdd(t *testing.T) {
 				}
 			},
 			setActorsRepoExpectation: func(actorsRepo *mocks.ActorsRepository, id int, err error) {
-				actorsRepo.On("Insert", mock.Anything).Return(id, err).Maybe()
+				actorsRepo.On("Insert", mock.Anything, ac.UserID).Return(id, err).Maybe()
 			},
 			expectedID:    0,
 			expectedError: domain.ErrBadRequest,
@@ -128,7 +128,7 @@ func TestAdd(t *testing.T) {
 				}
 			},
 			setActorsRepoExpectation: func(actorsRepo *mocks.ActorsRepository, id int, err error) {
-				actorsRepo.On("Insert", mock.Anything).Return(id, err).Maybe()
+				actorsRepo.On("Insert", mock.Anything, ac.UserID).Return(id, err).Maybe()
 			},
 			expectedID:    0,
 			expectedError: domain.ErrBadRequest,
@@ -140,8 +140,8 @@ func TestAdd(t *testing.T) {
 			actorsRepo := new(mocks.ActorsRepository)
 			test.setActorsRepoExpectation(actorsRepo, test.expectedID, test.expectedError)
 
-			actorsUsecase := usecase.NewActorsUsecase(actorsRepo)
-			id, err := actorsUsecase.Add(test.getActor())
+			actorsUsecase := usecase.NewActorsUsecase(actorsRepo, allowingAudit(), allowingEvents())
+			id, err := actorsUsecase.Add(test.getActor(), ac)
 
 			assert.Equal(t, test.expectedID, id)
 			assert.Equal(t, test.expectedError, err)
@@ -162,6 +162,7 @@ func TestRemove(t *testing.T) {
 			name: "GoodCase/Common",
 			id:   1,
 			setActorsRepoExpectation: func(actorsRepo *mocks.ActorsRepository, err error) {
+				actorsRepo.On("SelectById", 1).Return(domain.Actor{ID: 1}, nil)
 				actorsRepo.On("Delete", 1).Return(err)
 			},
 			expectedError: nil,
@@ -182,10 +183,19 @@ func TestRemove(t *testing.T) {
 			},
 			expectedError: domain.ErrNotFound,
 		},
+		{
+			name: "BadCase/NotFound",
+			id:   3,
+			setActorsRepoExpectation: func(actorsRepo *mocks.ActorsRepository, err error) {
+				actorsRepo.On("SelectById", 3).Return(domain.Actor{}, domain.ErrNotFound)
+			},
+			expectedError: domain.ErrNotFound,
+		},
 		{
 			name: "BadCase/RepoError",
 			id:   2,
 			setActorsRepoExpectation: func(actorsRepo *mocks.ActorsRepository, err error) {
+				actorsRepo.On("SelectById", 2).Return(domain.Actor{ID: 2}, nil)
 				actorsRepo.On("Delete", 2).Return(errors.New("repository error"))
 			},
 			expectedError: errors.New("repository error"),
@@ -197,8 +207,8 @@ func TestRemove(t *testing.T) {
 			actorsRepo := new(mocks.ActorsRepository)
 			test.setActorsRepoExpectation(actorsRepo, test.expectedError)
 
-			actorsUsecase := usecase.NewActorsUsecase(actorsRepo)
-			err := actorsUsecase.Remove(test.id)
+			actorsUsecase := usecase.NewActorsUsecase(actorsRepo, allowingAudit(), allowingEvents())
+			err := actorsUsecase.Remove(test.id, ac)
 
 			assert.Equal(t, test.expectedError, err)
 
@@ -238,7 +248,7 @@ func TestModify(t *testing.T) {
 			},
 			setActorsRepoExpectations: func(actorsRepo *mocks.ActorsRepository, oldActor domain.Actor, updatedActor domain.Actor, err error) {
 				actorsRepo.On("SelectById", oldActor.ID).Return(oldActor, err)
-				actorsRepo.On("Update", mock.Anything).Return(updatedActor, err)
+				actorsRepo.On("Update", mock.Anything, ac.UserID).Return(updatedActor, err)
 			},
 			getExpectedActor: func() domain.Actor {
 				var d pgtype.Date
@@ -296,7 +306,7 @@ func TestModify(t *testing.T) {
 			},
 			setActorsRepoExpectations: func(actorsRepo *mocks.ActorsRepository, oldActor domain.Actor, updatedActor domain.Actor, err error) {
 				actorsRepo.On("SelectById", oldActor.ID).Return(oldActor, nil)
-				actorsRepo.On("Update", mock.Anything).Return(updatedActor, err)
+				actorsRepo.On("Update", mock.Anything, ac.UserID).Return(updatedActor, err)
 			},
 			getExpectedActor: func() domain.Actor {
 				return domain.Actor{}
@@ -327,7 +337,7 @@ func TestModify(t *testing.T) {
 			},
 			setActorsRepoExpectations: func(actorsRepo *mocks.ActorsRepository, oldActor domain.Actor, updatedActor domain.Actor, err error) {
 				actorsRepo.On("SelectById", oldActor.ID).Return(oldActor, nil)
-				actorsRepo.On("Update", mock.Anything).Return(updatedActor, err)
+				actorsRepo.On("Update", mock.Anything, ac.UserID).Return(updatedActor, err)
 			},
 			getExpectedActor: func() domain.Actor {
 				return domain.Actor{}
@@ -369,8 +379,8 @@ func TestModify(t *testing.T) {
 			actorsRepo := new(mocks.ActorsRepository)
 			test.setActorsRepoExpectations(actorsRepo, test.getOldActor(), test.getExpectedActor(), test.expectedError)
 
-			actorsUsecase := usecase.NewActorsUsecase(actorsRepo)
-			updatedActor, err := actorsUsecase.Modify(test.getNewActor())
+			actorsUsecase := usecase.NewActorsUsecase(actorsRepo, allowingAudit(), allowingEvents())
+			updatedActor, err := actorsUsecase.Modify(test.getNewActor(), ac)
 
 			assert.Equal(t, test.getExpectedActor(), updatedActor)
 			assert.Equal(t, test.expectedError, err)
@@ -421,7 +431,7 @@ func TestGetAll(t *testing.T) {
 			actorsRepo := new(mocks.ActorsRepository)
 			test.setActorsRepoExpectations(actorsRepo, test.getExpectedActors(), test.expectedError)
 
-			actorsUsecase := usecase.NewActorsUsecase(actorsRepo)
+			actorsUsecase := usecase.NewActorsUsecase(actorsRepo, allowingAudit(), allowingEvents())
 			actors, err := actorsUsecase.GetAll()
 
 			assert.Equal(t, test.getExpectedActors(), actors)
@@ -431,3 +441,322 @@ func TestGetAll(t *testing.T) {
 		})
 	}
 }
+
+var ac = domain.AuditContext{UserID: 1, RequestID: "request"}
+
+func allowingAudit() *mocks.AuditUsecase {
+	audit := new(mocks.AuditUsecase)
+	audit.On("Record", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	return audit
+}
+
+func allowingEvents() *mocks.EventEmitter {
+	events := new(mocks.EventEmitter)
+	events.On("Emit", mock.Anything, mock.Anything).Maybe()
+	return events
+}
+
+func TestAudit(t *testing.T) {
+	actorsRepo := new(mocks.ActorsRepository)
+	audit := new(mocks.AuditUsecase)
+	old := domain.Actor{ID: 1}
+	actorsRepo.On("SelectById", 1).Return(old, nil)
+	actorsRepo.On("Delete", 1).Return(nil)
+	audit.On("Record", ac, domain.AuditDelete, domain.AuditActor, 1, old, nil).Once()
+
+	err := usecase.NewActorsUsecase(actorsRepo, audit, allowingEvents()).Remove(1, ac)
+
+	assert.NoError(t, err)
+	actorsRepo.AssertExpectations(t)
+	audit.AssertExpectations(t)
+}
+
+func TestRevert(t *testing.T) {
+	tests := []struct {
+		name                string
+		revision            int
+		version             int
+		setRepoExpectations func(actorsRepo *mocks.ActorsRepository)
+		err                 error
+	}{
+		{
+			name:     "GoodCase/Common",
+			revision: 2,
+			setRepoExpectations: func(actorsRepo *mocks.ActorsRepository) {
+				actorsRepo.On("SelectById", 1).Return(domain.Actor{ID: 1, Name: "Keanu"}, nil)
+				actorsRepo.On("Revert", 1, 2, 0, ac.UserID).Return(domain.Actor{ID: 1, Name: "Keanu Reeves"}, nil)
+			},
+		},
+		{
+			name:     "GoodCase/Version",
+			revision: 2,
+			version:  3,
+			setRepoExpectations: func(actorsRepo *mocks.ActorsRepository) {
+				actorsRepo.On("SelectById", 1).Return(domain.Actor{ID: 1, Name: "Keanu", Version: 3}, nil)
+				actorsRepo.On("Revert", 1, 2, 3, ac.UserID).Return(domain.Actor{ID: 1, Name: "Keanu Reeves", Version: 4}, nil)
+			},
+		},
+		{
+			name:     "BadCase/VersionChanged",
+			revision: 2,
+			version:  2,
+			setRepoExpectations: func(actorsRepo *mocks.ActorsRepository) {
+				actorsRepo.On("SelectById", 1).Return(domain.Actor{ID: 1, Name: "Keanu", Version: 3}, nil)
+			},
+			err: domain.ErrPreconditionFailed,
+		},
+		{
+			name:                "BadCase/InvalidRevision",
+			revision:            0,
+			setRepoExpectations: func(actorsRepo *mocks.ActorsRepository) {},
+			err:                 domain.ErrNotFound,
+		},
+		{
+			name:     "BadCase/RevisionNotFound",
+			revision: 7,
+			setRepoExpectations: func(actorsRepo *mocks.ActorsRepository) {
+				actorsRepo.On("SelectById", 1).Return(domain.Actor{ID: 1}, nil)
+				actorsRepo.On("Revert", 1, 7, 0, ac.UserID).Return(domain.Actor{}, domain.ErrNotFound)
+			},
+			err: domain.ErrNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actorsRepo := new(mocks.ActorsRepository)
+			test.setRepoExpectations(actorsRepo)
+
+			_, err := usecase.NewActorsUsecase(actorsRepo, allowingAudit(), allowingEvents()).Revert(1, test.revision, test.version, ac)
+
+			assert.Equal(t, test.err, err)
+			actorsRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRestore(t *testing.T) {
+	actorsRepo := new(mocks.ActorsRepository)
+	audit := new(mocks.AuditUsecase)
+	restored := domain.Actor{ID: 1, Name: "Keanu Reeves"}
+	actorsRepo.On("Restore", 1).Return(restored, nil)
+	audit.On("Record", ac, domain.AuditRestore, domain.AuditActor, 1, nil, restored).Once()
+
+	actor, err := usecase.NewActorsUsecase(actorsRepo, audit, allowingEvents()).Restore(1, ac)
+
+	assert.NoError(t, err)
+	assert.Equal(t, restored, actor)
+	actorsRepo.AssertExpectations(t)
+	audit.AssertExpectations(t)
+}
+
+func TestPatch(t *testing.T) {
+	var d pgtype.Date
+	d.Scan("1964-09-02")
+	old := domain.Actor{ID: 1, Name: "Keanu Reeves", Sex: domain.M, Birthdate: d, Version: 2}
+
+	tests := []struct {
+		name    string
+		patch   domain.Patch
+		updated *domain.Actor
+		err     error
+	}{
+		{
+			name:    "GoodCase/Merge",
+			patch:   domain.Patch{Type: domain.MergePatch, Body: []byte(`{"name": "Keanu Charles Reeves"}`)},
+			updated: &domain.Actor{ID: 1, Name: "Keanu Charles Reeves", Sex: domain.M, Birthdate: d, Version: 2},
+		},
+		{
+			name:  "BadCase/EmptyName",
+			patch: domain.Patch{Type: domain.MergePatch, Body: []byte(`{"name": ""}`)},
+			err:   domain.ErrBadRequest,
+		},
+		{
+			name:  "BadCase/InvalidSex",
+			patch: domain.Patch{Type: domain.JSONPatch, Body: []byte(`[{"op": "replace", "path": "/sex", "value": "X"}]`)},
+			err:   domain.ErrBadRequest,
+		},
+		{
+			name:  "BadCase/RemoveBirthdate",
+			patch: domain.Patch{Type: domain.JSONPatch, Body: []byte(`[{"op": "remove", "path": "/birthdate"}]`)},
+			err:   domain.ErrBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actorsRepo := new(mocks.ActorsRepository)
+			actorsRepo.On("SelectById", 1).Return(old, nil)
+			if test.updated != nil {
+				actorsRepo.On("Update", *test.updated, ac.UserID).Return(*test.updated, nil)
+			}
+
+			_, err := usecase.NewActorsUsecase(actorsRepo, allowingAudit(), allowingEvents()).Patch(1, test.patch, ac)
+
+			assert.ErrorIs(t, err, test.err)
+			actorsRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetById(t *testing.T) {
+	tests := []struct {
+		name                string
+		setRepoExpectations func(actorsRepo *mocks.ActorsRepository)
+		actor               domain.Actor
+		err                 error
+	}{
+		{
+			name: "GoodCase/Common",
+			setRepoExpectations: func(actorsRepo *mocks.ActorsRepository) {
+				actorsRepo.On("SelectById", 3).Return(domain.Actor{ID: 3, Name: "Keanu Reeves"}, nil)
+			},
+			actor: domain.Actor{ID: 3, Name: "Keanu Reeves"},
+		},
+		{
+			name: "GoodCase/Merged",
+			setRepoExpectations: func(actorsRepo *mocks.ActorsRepository) {
+				actorsRepo.On("SelectById", 3).Return(domain.Actor{}, domain.ErrNotFound)
+				actorsRepo.On("SelectRedirect", 3).Return(5, nil)
+				actorsRepo.On("SelectById", 5).Return(domain.Actor{ID: 5, Name: "Keanu Reeves"}, nil)
+			},
+			actor: domain.Actor{ID: 5, Name: "Keanu Reeves"},
+		},
+		{
+			name: "BadCase/NotFound",
+			setRepoExpectations: func(actorsRepo *mocks.ActorsRepository) {
+				actorsRepo.On("SelectById", 3).Return(domain.Actor{}, domain.ErrNotFound)
+				actorsRepo.On("SelectRedirect", 3).Return(0, domain.ErrNotFound)
+			},
+			err: domain.ErrNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actorsRepo := new(mocks.ActorsRepository)
+			test.setRepoExpectations(actorsRepo)
+
+			actor, err := usecase.NewActorsUsecase(actorsRepo, allowingAudit(), allowingEvents()).GetById(3)
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.actor, actor)
+			actorsRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetDuplicates(t *testing.T) {
+	var d1, d2 pgtype.Date
+	d1.Scan("1964-09-02")
+	d2.Scan("1967-08-21")
+	keanu := domain.Actor{ID: 1, Name: "Keanu Reeves", Sex: domain.M, Birthdate: d1}
+	reeves := domain.Actor{ID: 2, Name: "Reeves, Keanu", Sex: domain.M, Birthdate: d1}
+	keanuC := domain.Actor{ID: 3, Name: "Keanu C. Reeves", Sex: domain.M, Birthdate: d1}
+	other := domain.Actor{ID: 4, Name: "Sandra Bullock", Sex: domain.F, Birthdate: d1}
+	carrie := domain.Actor{ID: 5, Name: "Carrie-Anne Moss", Sex: domain.F, Birthdate: d2}
+	carrieTypo := domain.Actor{ID: 6, Name: "Carrie Ann Moss", Sex: domain.F, Birthdate: d2}
+	actors := []domain.Actor{keanu, reeves, keanuC, other, carrie, carrieTypo}
+
+	tests := []struct {
+		name       string
+		threshold  float64
+		duplicates []domain.ActorDuplicate
+		err        error
+	}{
+		{
+			name:      "GoodCase/Default",
+			threshold: domain.DefaultDuplicateThreshold,
+			duplicates: []domain.ActorDuplicate{
+				{Actors: [2]domain.Actor{keanu, reeves}, Similarity: 1},
+				{Actors: [2]domain.Actor{carrie, carrieTypo}, Similarity: 0.94},
+				{Actors: [2]domain.Actor{keanu, keanuC}, Similarity: 0.86},
+				{Actors: [2]domain.Actor{reeves, keanuC}, Similarity: 0.86},
+			},
+		},
+		{
+			name:      "GoodCase/Exact",
+			threshold: 1,
+			duplicates: []domain.ActorDuplicate{
+				{Actors: [2]domain.Actor{keanu, reeves}, Similarity: 1},
+			},
+		},
+		{
+			name:      "BadCase/InvalidThreshold",
+			threshold: 1.5,
+			err:       domain.ErrBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actorsRepo := new(mocks.ActorsRepository)
+			actorsRepo.On("SelectSameBirthdate").Return(actors, nil).Maybe()
+
+			duplicates, err := usecase.NewActorsUsecase(actorsRepo, allowingAudit(), allowingEvents()).GetDuplicates(test.threshold)
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.duplicates, duplicates)
+			actorsRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	duplicate := domain.Actor{ID: 2, Name: "Reeves, Keanu"}
+	merged := domain.Actor{ID: 1, Name: "Keanu Reeves"}
+
+	tests := []struct {
+		name                string
+		duplicateID         int
+		setRepoExpectations func(actorsRepo *mocks.ActorsRepository, audit *mocks.AuditUsecase)
+		actor               domain.Actor
+		err                 error
+	}{
+		{
+			name:        "GoodCase/Common",
+			duplicateID: 2,
+			setRepoExpectations: func(actorsRepo *mocks.ActorsRepository, audit *mocks.AuditUsecase) {
+				actorsRepo.On("SelectById", 2).Return(duplicate, nil)
+				actorsRepo.On("Merge", 1, 2, ac.UserID).Return(merged, nil)
+				audit.On("Record", ac, domain.AuditMerge, domain.AuditActor, 2, duplicate, merged).Once()
+			},
+			actor: merged,
+		},
+		{
+			name:                "BadCase/Itself",
+			duplicateID:         1,
+			setRepoExpectations: func(actorsRepo *mocks.ActorsRepository, audit *mocks.AuditUsecase) {},
+			err:                 domain.ErrBadRequest,
+		},
+		{
+			name:        "BadCase/ActorNotFound",
+			duplicateID: 2,
+			setRepoExpectations: func(actorsRepo *mocks.ActorsRepository, audit *mocks.AuditUsecase) {
+				actorsRepo.On("SelectById", 2).Return(duplicate, nil)
+				actorsRepo.On("Merge", 1, 2, ac.UserID).Return(domain.Actor{}, domain.ErrNotFound)
+			},
+			err: domain.ErrNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actorsRepo := new(mocks.ActorsRepository)
+			audit := new(mocks.AuditUsecase)
+			events := new(mocks.EventEmitter)
+			test.setRepoExpectations(actorsRepo, audit)
+			if test.err == nil {
+				events.On("Emit", domain.ActorMerged, domain.MergedActor{Actor: merged, DuplicateID: 2}).Once()
+			}
+
+			actor, err := usecase.NewActorsUsecase(actorsRepo, audit, events).Merge(1, test.duplicateID, ac)
+
+			assert.ErrorIs(t, err, test.err)
+			assert.Equal(t, test.actor, actor)
+			actorsRepo.AssertExpectations(t)
+			audit.AssertExpectations(t)
+			events.AssertExpectations(t)
+		})
+	}
+}
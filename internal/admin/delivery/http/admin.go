@@ -0,0 +1,272 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+	"github.com/ellexo2456/FilmLib/internal/middleware"
+)
+
+type AdminHandler struct {
+	AdminUsecase domain.AdminUsecase
+}
+
+func NewAdminHandler(mux *http.ServeMux, au domain.AdminUsecase) {
+	handler := &AdminHandler{
+		AdminUsecase: au,
+	}
+
+	mux.Handle("GET /admin/users", middleware.Require(domain.UsersManage, handler.GetUsers))
+	mux.Handle("PUT /admin/users/{id}/role", middleware.Require(domain.UsersManage, handler.SetRole))
+	mux.Handle("POST /admin/users/{id}/disable", middleware.Require(domain.UsersManage, handler.Disable))
+	mux.Handle("POST /admin/users/{id}/enable", middleware.Require(domain.UsersManage, handler.Enable))
+	mux.Handle("DELETE /admin/users/{id}/sessions", middleware.Require(domain.UsersManage, handler.Logout))
+	mux.Handle("PUT /admin/roles/{role}/2fa", middleware.Require(domain.UsersManage, handler.SetTwoFactorPolicy))
+}
+
+// GetUsers godoc
+//
+//	@Summary		Gets users.
+//	@Description	Gets users ordered by id. Requires users:manage permission.
+//	@Tags			Admin
+//	@Param			searchStr	query	string	false	"Part of the email to search for"
+//	@Param			role		query	int		false	"Role to filter by: 0 - user, 1 - moderator, 2 - admin"
+//	@Param			limit		query	int		false	"Max users count, 50 by default"
+//	@Param			offset		query	int		false	"Users count to skip"
+//	@Produce		json
+//	@Success		200	{object}	object{body=object{users=[]domain.UserInfo}}
+//	@Failure		400	{object}	object{err=string}
+//	@Failure		403	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/admin/users [get]
+func (h *AdminHandler) GetUsers(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseFilter(r)
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "admin/http", "GetUsers", err, err.Error())
+		return
+	}
+
+	users, err := h.AdminUsecase.GetUsers(filter)
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "admin/http", "GetUsers", err, err.Error())
+		return
+	}
+
+	domain.WriteResponse(
+		w,
+		map[string]interface{}{
+			"users": users,
+		},
+		http.StatusOK,
+	)
+}
+
+// SetRole godoc
+//
+//	@Summary		Changes a user role.
+//	@Description	Changes a user role and logs the user out. Requires users:manage permission, admins can`t change their own role.
+//	@Tags			Admin
+//	@Param			id		path	int					true	"User id"
+//	@Param			body	body	domain.RoleChange	true	"New role: 0 - user, 1 - moderator, 2 - admin"
+//	@Success		204
+//	@Failure		400	{object}	object{err=string}
+//	@Failure		403	{object}	object{err=string}
+//	@Failure		404	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/admin/users/{id}/role [put]
+func (h *AdminHandler) SetRole(w http.ResponseWriter, r *http.Request) {
+	sc, ok := sessionContext(w, r, "SetRole")
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "admin/http", "SetRole", err, err.Error())
+		return
+	}
+
+	var change domain.RoleChange
+	err = json.NewDecoder(r.Body).Decode(&change)
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "admin/http", "SetRole", err, err.Error())
+		return
+	}
+	defer domain.CloseAndAlert(r.Body, "admin/http", "SetRole")
+
+	if err = h.AdminUsecase.SetRole(sc.UserID, id, change.Role); err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "admin/http", "SetRole", err, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Disable godoc
+//
+//	@Summary		Disables a user.
+//	@Description	Disables a user account and logs the user out. Requires users:manage permission.
+//	@Tags			Admin
+//	@Param			id	path	int	true	"User id"
+//	@Success		204
+//	@Failure		400	{object}	object{err=string}
+//	@Failure		403	{object}	object{err=string}
+//	@Failure		404	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/admin/users/{id}/disable [post]
+func (h *AdminHandler) Disable(w http.ResponseWriter, r *http.Request) {
+	h.setDisabled(w, r, true, "Disable")
+}
+
+// Enable godoc
+//
+//	@Summary		Enables a user.
+//	@Description	Enables a previously disabled user account. Requires users:manage permission.
+//	@Tags			Admin
+//	@Param			id	path	int	true	"User id"
+//	@Success		204
+//	@Failure		400	{object}	object{err=string}
+//	@Failure		403	{object}	object{err=string}
+//	@Failure		404	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/admin/users/{id}/enable [post]
+func (h *AdminHandler) Enable(w http.ResponseWriter, r *http.Request) {
+	h.setDisabled(w, r, false, "Enable")
+}
+
+// Logout godoc
+//
+//	@Summary		Logs a user out.
+//	@Description	Deletes all sessions of a user. Requires users:manage permission.
+//	@Tags			Admin
+//	@Param			id	path	int	true	"User id"
+//	@Success		204
+//	@Failure		400	{object}	object{err=string}
+//	@Failure		403	{object}	object{err=string}
+//	@Failure		404	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/admin/users/{id}/sessions [delete]
+func (h *AdminHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "admin/http", "Logout", err, err.Error())
+		return
+	}
+
+	if err = h.AdminUsecase.Logout(id); err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "admin/http", "Logout", err, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SetTwoFactorPolicy godoc
+//
+//	@Summary		Sets the 2FA policy of a role.
+//	@Description	Makes 2FA mandatory for a role or optional again. Users of the role without 2FA enroll on their next login. Requires users:manage permission.
+//	@Tags			Admin
+//	@Param			role	path	int						true	"Role: 0 - user, 1 - moderator, 2 - admin"
+//	@Param			body	body	domain.TwoFactorPolicy	true	"Whether 2FA is required"
+//	@Success		204
+//	@Failure		400	{object}	object{err=string}
+//	@Failure		403	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/admin/roles/{role}/2fa [put]
+func (h *AdminHandler) SetTwoFactorPolicy(w http.ResponseWriter, r *http.Request) {
+	role, err := strconv.Atoi(r.PathValue("role"))
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "admin/http", "SetTwoFactorPolicy", err, err.Error())
+		return
+	}
+
+	var policy domain.TwoFactorPolicy
+	err = json.NewDecoder(r.Body).Decode(&policy)
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "admin/http", "SetTwoFactorPolicy", err, err.Error())
+		return
+	}
+	defer domain.CloseAndAlert(r.Body, "admin/http", "SetTwoFactorPolicy")
+
+	if err = h.AdminUsecase.SetTwoFactorRequired(domain.Role(role), policy.Required); err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "admin/http", "SetTwoFactorPolicy", err, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AdminHandler) setDisabled(w http.ResponseWriter, r *http.Request, disabled bool, funcName string) {
+	sc, ok := sessionContext(w, r, funcName)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "admin/http", funcName, err, err.Error())
+		return
+	}
+
+	if err = h.AdminUsecase.SetDisabled(sc.UserID, id, disabled); err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "admin/http", funcName, err, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func sessionContext(w http.ResponseWriter, r *http.Request, funcName string) (domain.SessionContext, bool) {
+	sc, ok := r.Context().Value(domain.SessionContextKey).(domain.SessionContext)
+	if !ok {
+		domain.WriteError(w, "can`t find user", http.StatusInternalServerError)
+		logs.LogError(logs.Logger, "admin/http", funcName, errors.New("can`t find user"), "can`t find user")
+		return domain.SessionContext{}, false
+	}
+
+	return sc, true
+}
+
+func parseFilter(r *http.Request) (domain.UsersFilter, error) {
+	queryParams := r.URL.Query()
+	filter := domain.UsersFilter{
+		Search: queryParams.Get(domain.SearchParam),
+	}
+
+	var err error
+	if v := queryParams.Get(domain.RoleParam); v != "" {
+		var role int
+		if role, err = strconv.Atoi(v); err != nil {
+			return domain.UsersFilter{}, err
+		}
+		filter.Role = (*domain.Role)(&role)
+	}
+	if v := queryParams.Get(domain.LimitParam); v != "" {
+		if filter.Limit, err = strconv.Atoi(v); err != nil {
+			return domain.UsersFilter{}, err
+		}
+	}
+	if v := queryParams.Get(domain.OffsetParam); v != "" {
+		if filter.Offset, err = strconv.Atoi(v); err != nil {
+			return domain.UsersFilter{}, err
+		}
+	}
+
+	return filter, nil
+}
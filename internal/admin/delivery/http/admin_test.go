@@ -0,0 +1,337 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	admin_http "github.com/ellexo2456/FilmLib/internal/admin/delivery/http"
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	"github.com/ellexo2456/FilmLib/internal/domain/mocks"
+)
+
+var adminCtx = context.WithValue(context.Background(), domain.SessionContextKey,
+	domain.SessionContext{UserID: 1, Role: domain.Admin, Verified: true})
+
+func TestGetUsers(t *testing.T) {
+	moder := domain.Moder
+
+	tests := []struct {
+		name                 string
+		query                string
+		setUCaseExpectations func(usecase *mocks.AdminUsecase)
+		ctx                  context.Context
+		status               int
+	}{
+		{
+			name:  "GoodCase/Common",
+			query: "?searchStr=mail&role=1&limit=10&offset=5",
+			setUCaseExpectations: func(usecase *mocks.AdminUsecase) {
+				usecase.On("GetUsers", domain.UsersFilter{Search: "mail", Role: &moder, Limit: 10, Offset: 5}).
+					Return([]domain.UserInfo{{ID: 2}}, nil)
+			},
+			ctx:    adminCtx,
+			status: http.StatusOK,
+		},
+		{
+			name:                 "BadCase/InvalidLimit",
+			query:                "?limit=ten",
+			setUCaseExpectations: func(usecase *mocks.AdminUsecase) {},
+			ctx:                  adminCtx,
+			status:               http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockUsecase := new(mocks.AdminUsecase)
+			test.setUCaseExpectations(mockUsecase)
+
+			req := httptest.NewRequest("GET", "/api/v1/admin/users"+test.query, nil)
+			req = req.WithContext(test.ctx)
+			rec := httptest.NewRecorder()
+
+			handler := &admin_http.AdminHandler{AdminUsecase: mockUsecase}
+			handler.GetUsers(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSetRole(t *testing.T) {
+	tests := []struct {
+		name                 string
+		id                   string
+		body                 string
+		setUCaseExpectations func(usecase *mocks.AdminUsecase)
+		status               int
+	}{
+		{
+			name: "GoodCase/Common",
+			id:   "2",
+			body: `{"role": 1}`,
+			setUCaseExpectations: func(usecase *mocks.AdminUsecase) {
+				usecase.On("SetRole", 1, 2, domain.Moder).Return(nil)
+			},
+			status: http.StatusNoContent,
+		},
+		{
+			name:                 "BadCase/InvalidID",
+			id:                   "two",
+			body:                 `{"role": 1}`,
+			setUCaseExpectations: func(usecase *mocks.AdminUsecase) {},
+			status:               http.StatusBadRequest,
+		},
+		{
+			name:                 "BadCase/InvalidBody",
+			id:                   "2",
+			body:                 `{"role": "moder"}`,
+			setUCaseExpectations: func(usecase *mocks.AdminUsecase) {},
+			status:               http.StatusBadRequest,
+		},
+		{
+			name: "BadCase/Self",
+			id:   "1",
+			body: `{"role": 0}`,
+			setUCaseExpectations: func(usecase *mocks.AdminUsecase) {
+				usecase.On("SetRole", 1, 1, domain.Usr).Return(domain.ErrForbidden)
+			},
+			status: http.StatusForbidden,
+		},
+		{
+			name: "BadCase/NotFound",
+			id:   "5",
+			body: `{"role": 2}`,
+			setUCaseExpectations: func(usecase *mocks.AdminUsecase) {
+				usecase.On("SetRole", 1, 5, domain.Admin).Return(domain.ErrNotFound)
+			},
+			status: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockUsecase := new(mocks.AdminUsecase)
+			test.setUCaseExpectations(mockUsecase)
+
+			req := httptest.NewRequest("PUT", "/api/v1/admin/users/"+test.id+"/role", bytes.NewReader([]byte(test.body)))
+			req = req.WithContext(adminCtx)
+			req.SetPathValue("id", test.id)
+			rec := httptest.NewRecorder()
+
+			handler := &admin_http.AdminHandler{AdminUsecase: mockUsecase}
+			handler.SetRole(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDisableEnable(t *testing.T) {
+	tests := []struct {
+		name                 string
+		disable              bool
+		id                   string
+		setUCaseExpectations func(usecase *mocks.AdminUsecase)
+		status               int
+	}{
+		{
+			name:    "GoodCase/Disable",
+			disable: true,
+			id:      "2",
+			setUCaseExpectations: func(usecase *mocks.AdminUsecase) {
+				usecase.On("SetDisabled", 1, 2, true).Return(nil)
+			},
+			status: http.StatusNoContent,
+		},
+		{
+			name: "GoodCase/Enable",
+			id:   "2",
+			setUCaseExpectations: func(usecase *mocks.AdminUsecase) {
+				usecase.On("SetDisabled", 1, 2, false).Return(nil)
+			},
+			status: http.StatusNoContent,
+		},
+		{
+			name:                 "BadCase/InvalidID",
+			disable:              true,
+			id:                   "x",
+			setUCaseExpectations: func(usecase *mocks.AdminUsecase) {},
+			status:               http.StatusBadRequest,
+		},
+		{
+			name:    "BadCase/UsecaseError",
+			disable: true,
+			id:      "2",
+			setUCaseExpectations: func(usecase *mocks.AdminUsecase) {
+				usecase.On("SetDisabled", mock.Anything, mock.Anything, mock.Anything).Return(domain.ErrInternalServerError)
+			},
+			status: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockUsecase := new(mocks.AdminUsecase)
+			test.setUCaseExpectations(mockUsecase)
+
+			req := httptest.NewRequest("POST", "/api/v1/admin/users/"+test.id, nil)
+			req = req.WithContext(adminCtx)
+			req.SetPathValue("id", test.id)
+			rec := httptest.NewRecorder()
+
+			handler := &admin_http.AdminHandler{AdminUsecase: mockUsecase}
+			if test.disable {
+				handler.Disable(rec, req)
+			} else {
+				handler.Enable(rec, req)
+			}
+
+			assert.Equal(t, test.status, rec.Code)
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLogout(t *testing.T) {
+	tests := []struct {
+		name                 string
+		id                   string
+		setUCaseExpectations func(usecase *mocks.AdminUsecase)
+		status               int
+	}{
+		{
+			name: "GoodCase/Common",
+			id:   "2",
+			setUCaseExpectations: func(usecase *mocks.AdminUsecase) {
+				usecase.On("Logout", 2).Return(nil)
+			},
+			status: http.StatusNoContent,
+		},
+		{
+			name: "BadCase/NotFound",
+			id:   "0",
+			setUCaseExpectations: func(usecase *mocks.AdminUsecase) {
+				usecase.On("Logout", 0).Return(domain.ErrNotFound)
+			},
+			status: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockUsecase := new(mocks.AdminUsecase)
+			test.setUCaseExpectations(mockUsecase)
+
+			req := httptest.NewRequest("DELETE", "/api/v1/admin/users/"+test.id+"/sessions", nil)
+			req = req.WithContext(adminCtx)
+			req.SetPathValue("id", test.id)
+			rec := httptest.NewRecorder()
+
+			handler := &admin_http.AdminHandler{AdminUsecase: mockUsecase}
+			handler.Logout(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSetTwoFactorPolicy(t *testing.T) {
+	tests := []struct {
+		name                 string
+		role                 string
+		body                 string
+		setUCaseExpectations func(usecase *mocks.AdminUsecase)
+		status               int
+	}{
+		{
+			name: "GoodCase/Common",
+			role: "1",
+			body: `{"required": true}`,
+			setUCaseExpectations: func(usecase *mocks.AdminUsecase) {
+				usecase.On("SetTwoFactorRequired", domain.Moder, true).Return(nil)
+			},
+			status: http.StatusNoContent,
+		},
+		{
+			name:                 "BadCase/InvalidRole",
+			role:                 "moder",
+			body:                 `{"required": true}`,
+			setUCaseExpectations: func(usecase *mocks.AdminUsecase) {},
+			status:               http.StatusBadRequest,
+		},
+		{
+			name:                 "BadCase/InvalidJson",
+			role:                 "1",
+			body:                 `{"required": `,
+			setUCaseExpectations: func(usecase *mocks.AdminUsecase) {},
+			status:               http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockUsecase := new(mocks.AdminUsecase)
+			test.setUCaseExpectations(mockUsecase)
+
+			req := httptest.NewRequest("PUT", "/api/v1/admin/roles/"+test.role+"/2fa", bytes.NewReader([]byte(test.body)))
+			req = req.WithContext(adminCtx)
+			req.SetPathValue("role", test.role)
+			rec := httptest.NewRecorder()
+
+			handler := &admin_http.AdminHandler{AdminUsecase: mockUsecase}
+			handler.SetTwoFactorPolicy(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRoutesPermissions(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		target string
+		role   domain.Role
+		status int
+	}{
+		{name: "BadCase/ModerGetUsers", method: "GET", target: "/admin/users", role: domain.Moder, status: http.StatusForbidden},
+		{name: "BadCase/UserSetRole", method: "PUT", target: "/admin/users/2/role", role: domain.Usr, status: http.StatusForbidden},
+		{name: "BadCase/ModerDisable", method: "POST", target: "/admin/users/2/disable", role: domain.Moder, status: http.StatusForbidden},
+		{name: "BadCase/ModerEnable", method: "POST", target: "/admin/users/2/enable", role: domain.Moder, status: http.StatusForbidden},
+		{name: "BadCase/ModerLogout", method: "DELETE", target: "/admin/users/2/sessions", role: domain.Moder, status: http.StatusForbidden},
+		{name: "GoodCase/AdminLogout", method: "DELETE", target: "/admin/users/2/sessions", role: domain.Admin, status: http.StatusNoContent},
+		{name: "BadCase/ModerTwoFactorPolicy", method: "PUT", target: "/admin/roles/1/2fa", role: domain.Moder, status: http.StatusForbidden},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockUsecase := new(mocks.AdminUsecase)
+			if test.status == http.StatusNoContent {
+				mockUsecase.On("Logout", 2).Return(nil)
+			}
+
+			mux := http.NewServeMux()
+			admin_http.NewAdminHandler(mux, mockUsecase)
+
+			req := httptest.NewRequest(test.method, test.target, nil)
+			req = req.WithContext(context.WithValue(context.Background(), domain.SessionContextKey,
+				domain.SessionContext{UserID: 1, Role: test.role, Verified: true}))
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
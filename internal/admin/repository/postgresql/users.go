@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+)
+
+const selectQuery = `
+	SELECT id, email, role, verified, disabled, created_at
+	FROM "user"
+	WHERE email ILIKE $1 ESCAPE '\'
+	  AND ($2::INT IS NULL OR role = $2)
+	ORDER BY id
+	LIMIT $3 OFFSET $4
+`
+
+const updateRoleQuery = `
+	UPDATE "user"
+	SET role = $1
+	WHERE id = $2
+`
+
+const updateDisabledQuery = `
+	UPDATE "user"
+	SET disabled = $1
+	WHERE id = $2
+`
+
+// likeEscaper makes the search match the wildcards of LIKE literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+type usersPostgresqlRepository struct {
+	db  domain.PgxPoolIface
+	ctx context.Context
+}
+
+func NewUsersPostgresqlRepository(pool domain.PgxPoolIface, ctx context.Context) domain.UsersRepository {
+	return &usersPostgresqlRepository{
+		db:  pool,
+		ctx: ctx,
+	}
+}
+
+func (r *usersPostgresqlRepository) Select(filter domain.UsersFilter) ([]domain.UserInfo, error) {
+	rows, err := r.db.Query(r.ctx, selectQuery, "%"+likeEscaper.Replace(filter.Search)+"%", filter.Role, filter.Limit, filter.Offset)
+	if err != nil {
+		logs.LogError(logs.Logger, "admin/postgres", "Select", err, err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []domain.UserInfo{}
+	var user domain.UserInfo
+	for rows.Next() {
+		err = rows.Scan(
+			&user.ID,
+			&user.Email,
+			&user.Role,
+			&user.Verified,
+			&user.Disabled,
+			&user.CreatedAt,
+		)
+		if err != nil {
+			logs.LogError(logs.Logger, "admin/postgres", "Select", err, err.Error())
+			return nil, err
+		}
+
+		users = append(users, user)
+	}
+	if err = rows.Err(); err != nil {
+		logs.LogError(logs.Logger, "admin/postgres", "Select", err, err.Error())
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (r *usersPostgresqlRepository) UpdateRole(userID int, role domain.Role) error {
+	res, err := r.db.Exec(r.ctx, updateRoleQuery, role, userID)
+	if err != nil {
+		logs.LogError(logs.Logger, "admin/postgres", "UpdateRole", err, err.Error())
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *usersPostgresqlRepository) UpdateDisabled(userID int, disabled bool) error {
+	res, err := r.db.Exec(r.ctx, updateDisabledQuery, disabled, userID)
+	if err != nil {
+		logs.LogError(logs.Logger, "admin/postgres", "UpdateDisabled", err, err.Error())
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,210 @@
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/require"
+
+	postgres "github.com/ellexo2456/FilmLib/internal/admin/repository/postgresql"
+	"github.com/ellexo2456/FilmLib/internal/domain"
+)
+
+const selectQueryTest = `
+	SELECT id, email, role, verified, disabled, created_at
+	FROM "user"
+`
+
+const updateRoleQueryTest = `
+	UPDATE "user"
+	SET role = \$1
+`
+
+const updateDisabledQueryTest = `
+	UPDATE "user"
+	SET disabled = \$1
+`
+
+func TestSelect(t *testing.T) {
+	moder := domain.Moder
+	created := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		filter  domain.UsersFilter
+		pattern string
+		users   []domain.UserInfo
+		rowErr  error
+		err     error
+	}{
+		{
+			name:    "GoodCase/Common",
+			filter:  domain.UsersFilter{Search: "mail", Role: &moder, Limit: 10},
+			pattern: "%mail%",
+			users: []domain.UserInfo{
+				{ID: 1, Email: "a@mail.ru", Role: domain.Moder, Verified: true, CreatedAt: created},
+				{ID: 4, Email: "b@mail.ru", Role: domain.Moder, Disabled: true, CreatedAt: created},
+			},
+		},
+		{
+			name:    "GoodCase/Empty",
+			filter:  domain.UsersFilter{Limit: 10},
+			pattern: "%%",
+			users:   []domain.UserInfo{},
+		},
+		{
+			name:    "GoodCase/Wildcards",
+			filter:  domain.UsersFilter{Search: `a_b%c\`, Limit: 10},
+			pattern: `%a\_b\%c\\%`,
+			users:   []domain.UserInfo{},
+		},
+		{
+			name:    "BadCase/DBError",
+			filter:  domain.UsersFilter{Limit: 10},
+			pattern: "%%",
+			err:     errors.New("some error"),
+		},
+		{
+			name:    "BadCase/RowsError",
+			filter:  domain.UsersFilter{Limit: 10},
+			pattern: "%%",
+			users:   []domain.UserInfo{{ID: 1, Email: "a@mail.ru", CreatedAt: created}},
+			rowErr:  errors.New("connection reset"),
+			err:     errors.New("connection reset"),
+		},
+	}
+
+	mockDB, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockDB.Close()
+	r := postgres.NewUsersPostgresqlRepository(mockDB, context.Background())
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rows := mockDB.NewRows([]string{"id", "email", "role", "verified", "disabled", "created_at"})
+			for _, u := range test.users {
+				rows.AddRow(u.ID, u.Email, u.Role, u.Verified, u.Disabled, u.CreatedAt)
+			}
+			if test.rowErr != nil {
+				rows.RowError(0, test.rowErr)
+			}
+
+			eq := mockDB.ExpectQuery(selectQueryTest).
+				WithArgs(test.pattern, test.filter.Role, test.filter.Limit, test.filter.Offset)
+			if test.err != nil && test.rowErr == nil {
+				eq.WillReturnError(test.err)
+			} else {
+				eq.WillReturnRows(rows)
+			}
+
+			users, err := r.Select(test.filter)
+			if test.err == nil {
+				require.Nil(t, err)
+				require.Equal(t, test.users, users)
+			} else {
+				require.NotNil(t, err)
+			}
+
+			err = mockDB.ExpectationsWereMet()
+			require.Nil(t, err)
+		})
+	}
+}
+
+func TestUpdateRole(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   int
+		affected int64
+		err      error
+	}{
+		{
+			name:     "GoodCase/Common",
+			userID:   1,
+			affected: 1,
+		},
+		{
+			name:   "BadCase/NotFound",
+			userID: 2,
+			err:    domain.ErrNotFound,
+		},
+	}
+
+	mockDB, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockDB.Close()
+	r := postgres.NewUsersPostgresqlRepository(mockDB, context.Background())
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockDB.ExpectExec(updateRoleQueryTest).
+				WithArgs(domain.Moder, test.userID).
+				WillReturnResult(pgxmock.NewResult("UPDATE", test.affected))
+
+			err := r.UpdateRole(test.userID, domain.Moder)
+			require.ErrorIs(t, err, test.err)
+
+			err = mockDB.ExpectationsWereMet()
+			require.Nil(t, err)
+		})
+	}
+}
+
+func TestUpdateDisabled(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   int
+		affected int64
+		dbErr    error
+		err      error
+	}{
+		{
+			name:     "GoodCase/Common",
+			userID:   1,
+			affected: 1,
+		},
+		{
+			name:   "BadCase/NotFound",
+			userID: 2,
+			err:    domain.ErrNotFound,
+		},
+		{
+			name:   "BadCase/DBError",
+			userID: 3,
+			dbErr:  domain.ErrInternalServerError,
+			err:    domain.ErrInternalServerError,
+		},
+	}
+
+	mockDB, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockDB.Close()
+	r := postgres.NewUsersPostgresqlRepository(mockDB, context.Background())
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ee := mockDB.ExpectExec(updateDisabledQueryTest).
+				WithArgs(true, test.userID)
+			if test.dbErr != nil {
+				ee.WillReturnError(test.dbErr)
+			} else {
+				ee.WillReturnResult(pgxmock.NewResult("UPDATE", test.affected))
+			}
+
+			err := r.UpdateDisabled(test.userID, true)
+			require.ErrorIs(t, err, test.err)
+
+			err = mockDB.ExpectationsWereMet()
+			require.Nil(t, err)
+		})
+	}
+}
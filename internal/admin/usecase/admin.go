@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+)
+
+const maxLimit = 500
+
+type adminUsecase struct {
+	usersRepo    domain.UsersRepository
+	sessionRepo  domain.SessionRepository
+	refreshRepo  domain.RefreshTokenRepository
+	twoFactor    domain.TwoFactorRepository
+	rememberRepo domain.RememberTokenRepository
+}
+
+func NewAdminUsecase(ur domain.UsersRepository, sr domain.SessionRepository, rr domain.RefreshTokenRepository,
+	tfr domain.TwoFactorRepository, rmr domain.RememberTokenRepository) domain.AdminUsecase {
+	return &adminUsecase{
+		usersRepo:    ur,
+		sessionRepo:  sr,
+		refreshRepo:  rr,
+		twoFactor:    tfr,
+		rememberRepo: rmr,
+	}
+}
+
+func (u *adminUsecase) GetUsers(filter domain.UsersFilter) ([]domain.UserInfo, error) {
+	if filter.Limit < 0 || filter.Offset < 0 || filter.Limit > maxLimit {
+		return nil, domain.ErrBadRequest
+	}
+	if filter.Role != nil && !filter.Role.Valid() {
+		return nil, domain.ErrBadRequest
+	}
+	if filter.Limit == 0 {
+		filter.Limit = domain.DefaultLimit
+	}
+
+	users, err := u.usersRepo.Select(filter)
+	if err != nil {
+		logs.LogError(logs.Logger, "admin/usecase", "GetUsers", err, err.Error())
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// SetRole changes the role and drops the user sessions, because
+// the role is cached in them.
+func (u *adminUsecase) SetRole(adminID, userID int, role domain.Role) error {
+	if userID <= 0 {
+		return domain.ErrNotFound
+	}
+	if !role.Valid() {
+		return domain.ErrBadRequest
+	}
+	if adminID == userID {
+		return domain.ErrForbidden
+	}
+
+	if err := u.usersRepo.UpdateRole(userID, role); err != nil {
+		logs.LogError(logs.Logger, "admin/usecase", "SetRole", err, err.Error())
+		return err
+	}
+
+	return u.Logout(userID)
+}
+
+func (u *adminUsecase) SetDisabled(adminID, userID int, disabled bool) error {
+	if userID <= 0 {
+		return domain.ErrNotFound
+	}
+	if adminID == userID {
+		return domain.ErrForbidden
+	}
+
+	if err := u.usersRepo.UpdateDisabled(userID, disabled); err != nil {
+		logs.LogError(logs.Logger, "admin/usecase", "SetDisabled", err, err.Error())
+		return err
+	}
+
+	if !disabled {
+		return nil
+	}
+	return u.Logout(userID)
+}
+
+func (u *adminUsecase) Logout(userID int) error {
+	if userID <= 0 {
+		return domain.ErrNotFound
+	}
+
+	if err := u.sessionRepo.DeleteByUserID(userID); err != nil {
+		logs.LogError(logs.Logger, "admin/usecase", "Logout", err, err.Error())
+		return err
+	}
+
+	// access tokens of the jwt mode can`t be revoked, they die
+	// on their own once they can`t be refreshed
+	if err := u.refreshRepo.DeleteByUserID(userID); err != nil {
+		logs.LogError(logs.Logger, "admin/usecase", "Logout", err, err.Error())
+		return err
+	}
+
+	if err := u.rememberRepo.DeleteByUserID(userID); err != nil {
+		logs.LogError(logs.Logger, "admin/usecase", "Logout", err, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// SetTwoFactorRequired makes 2FA mandatory for the role. Users who
+// haven`t enrolled yet have to do it on their next login.
+func (u *adminUsecase) SetTwoFactorRequired(role domain.Role, required bool) error {
+	if !role.Valid() {
+		return domain.ErrBadRequest
+	}
+
+	if err := u.twoFactor.SetRequired(role, required); err != nil {
+		logs.LogError(logs.Logger, "admin/usecase", "SetTwoFactorRequired", err, err.Error())
+		return err
+	}
+
+	return nil
+}
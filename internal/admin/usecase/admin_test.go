@@ -0,0 +1,335 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ellexo2456/FilmLib/internal/admin/usecase"
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	"github.com/ellexo2456/FilmLib/internal/domain/mocks"
+)
+
+func TestGetUsers(t *testing.T) {
+	moder := domain.Moder
+	invalid := domain.Role(10)
+
+	tests := []struct {
+		name            string
+		filter          domain.UsersFilter
+		setExpectations func(ur *mocks.UsersRepository)
+		err             error
+	}{
+		{
+			name:   "GoodCase/DefaultLimit",
+			filter: domain.UsersFilter{Search: "mail"},
+			setExpectations: func(ur *mocks.UsersRepository) {
+				ur.On("Select", domain.UsersFilter{Search: "mail", Limit: domain.DefaultLimit}).
+					Return([]domain.UserInfo{{ID: 1}}, nil)
+			},
+		},
+		{
+			name:   "GoodCase/ByRole",
+			filter: domain.UsersFilter{Role: &moder, Limit: 10, Offset: 20},
+			setExpectations: func(ur *mocks.UsersRepository) {
+				ur.On("Select", domain.UsersFilter{Role: &moder, Limit: 10, Offset: 20}).
+					Return([]domain.UserInfo{}, nil)
+			},
+		},
+		{
+			name:            "BadCase/InvalidRole",
+			filter:          domain.UsersFilter{Role: &invalid},
+			setExpectations: func(ur *mocks.UsersRepository) {},
+			err:             domain.ErrBadRequest,
+		},
+		{
+			name:            "BadCase/NegativeOffset",
+			filter:          domain.UsersFilter{Offset: -1},
+			setExpectations: func(ur *mocks.UsersRepository) {},
+			err:             domain.ErrBadRequest,
+		},
+		{
+			name:            "BadCase/TooBigLimit",
+			filter:          domain.UsersFilter{Limit: 100000},
+			setExpectations: func(ur *mocks.UsersRepository) {},
+			err:             domain.ErrBadRequest,
+		},
+		{
+			name:   "BadCase/DBError",
+			filter: domain.UsersFilter{},
+			setExpectations: func(ur *mocks.UsersRepository) {
+				ur.On("Select", mock.Anything).Return(nil, domain.ErrInternalServerError)
+			},
+			err: domain.ErrInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ur := new(mocks.UsersRepository)
+			sr := new(mocks.SessionRepository)
+			rr := new(mocks.RefreshTokenRepository)
+			test.setExpectations(ur)
+
+			users, err := usecase.NewAdminUsecase(ur, sr, rr, new(mocks.TwoFactorRepository), new(mocks.RememberTokenRepository)).GetUsers(test.filter)
+
+			assert.ErrorIs(t, err, test.err)
+			if test.err == nil {
+				assert.NotNil(t, users)
+			}
+			ur.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSetRole(t *testing.T) {
+	tests := []struct {
+		name            string
+		adminID         int
+		userID          int
+		role            domain.Role
+		setExpectations func(ur *mocks.UsersRepository, sr *mocks.SessionRepository)
+		err             error
+	}{
+		{
+			name:    "GoodCase/Common",
+			adminID: 1,
+			userID:  2,
+			role:    domain.Moder,
+			setExpectations: func(ur *mocks.UsersRepository, sr *mocks.SessionRepository) {
+				ur.On("UpdateRole", 2, domain.Moder).Return(nil)
+				sr.On("DeleteByUserID", 2).Return(nil)
+			},
+		},
+		{
+			name:            "BadCase/Self",
+			adminID:         1,
+			userID:          1,
+			role:            domain.Usr,
+			setExpectations: func(ur *mocks.UsersRepository, sr *mocks.SessionRepository) {},
+			err:             domain.ErrForbidden,
+		},
+		{
+			name:            "BadCase/InvalidRole",
+			adminID:         1,
+			userID:          2,
+			role:            domain.Role(-1),
+			setExpectations: func(ur *mocks.UsersRepository, sr *mocks.SessionRepository) {},
+			err:             domain.ErrBadRequest,
+		},
+		{
+			name:    "BadCase/NotFound",
+			adminID: 1,
+			userID:  2,
+			role:    domain.Admin,
+			setExpectations: func(ur *mocks.UsersRepository, sr *mocks.SessionRepository) {
+				ur.On("UpdateRole", 2, domain.Admin).Return(domain.ErrNotFound)
+			},
+			err: domain.ErrNotFound,
+		},
+		{
+			name:    "BadCase/RedisError",
+			adminID: 1,
+			userID:  2,
+			role:    domain.Admin,
+			setExpectations: func(ur *mocks.UsersRepository, sr *mocks.SessionRepository) {
+				ur.On("UpdateRole", 2, domain.Admin).Return(nil)
+				sr.On("DeleteByUserID", 2).Return(domain.ErrInternalServerError)
+			},
+			err: domain.ErrInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ur := new(mocks.UsersRepository)
+			sr := new(mocks.SessionRepository)
+			rr := new(mocks.RefreshTokenRepository)
+			rr.On("DeleteByUserID", test.userID).Return(nil).Maybe()
+			rmr := new(mocks.RememberTokenRepository)
+			rmr.On("DeleteByUserID", test.userID).Return(nil).Maybe()
+			test.setExpectations(ur, sr)
+
+			err := usecase.NewAdminUsecase(ur, sr, rr, new(mocks.TwoFactorRepository), rmr).SetRole(test.adminID, test.userID, test.role)
+
+			assert.ErrorIs(t, err, test.err)
+			ur.AssertExpectations(t)
+			sr.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSetDisabled(t *testing.T) {
+	tests := []struct {
+		name            string
+		adminID         int
+		userID          int
+		disabled        bool
+		setExpectations func(ur *mocks.UsersRepository, sr *mocks.SessionRepository)
+		err             error
+	}{
+		{
+			name:     "GoodCase/Disable",
+			adminID:  1,
+			userID:   2,
+			disabled: true,
+			setExpectations: func(ur *mocks.UsersRepository, sr *mocks.SessionRepository) {
+				ur.On("UpdateDisabled", 2, true).Return(nil)
+				sr.On("DeleteByUserID", 2).Return(nil)
+			},
+		},
+		{
+			name:     "GoodCase/Enable",
+			adminID:  1,
+			userID:   2,
+			disabled: false,
+			setExpectations: func(ur *mocks.UsersRepository, sr *mocks.SessionRepository) {
+				ur.On("UpdateDisabled", 2, false).Return(nil)
+			},
+		},
+		{
+			name:            "BadCase/Self",
+			adminID:         1,
+			userID:          1,
+			disabled:        true,
+			setExpectations: func(ur *mocks.UsersRepository, sr *mocks.SessionRepository) {},
+			err:             domain.ErrForbidden,
+		},
+		{
+			name:     "BadCase/DBError",
+			adminID:  1,
+			userID:   2,
+			disabled: true,
+			setExpectations: func(ur *mocks.UsersRepository, sr *mocks.SessionRepository) {
+				ur.On("UpdateDisabled", 2, true).Return(errors.New("some db error"))
+			},
+			err: errors.New("some db error"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ur := new(mocks.UsersRepository)
+			sr := new(mocks.SessionRepository)
+			rr := new(mocks.RefreshTokenRepository)
+			rr.On("DeleteByUserID", test.userID).Return(nil).Maybe()
+			rmr := new(mocks.RememberTokenRepository)
+			rmr.On("DeleteByUserID", test.userID).Return(nil).Maybe()
+			test.setExpectations(ur, sr)
+
+			err := usecase.NewAdminUsecase(ur, sr, rr, new(mocks.TwoFactorRepository), rmr).SetDisabled(test.adminID, test.userID, test.disabled)
+
+			assert.Equal(t, test.err, err)
+			ur.AssertExpectations(t)
+			sr.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLogout(t *testing.T) {
+	tests := []struct {
+		name            string
+		userID          int
+		setExpectations func(sr *mocks.SessionRepository, rr *mocks.RefreshTokenRepository, rmr *mocks.RememberTokenRepository)
+		err             error
+	}{
+		{
+			name:   "GoodCase/Common",
+			userID: 2,
+			setExpectations: func(sr *mocks.SessionRepository, rr *mocks.RefreshTokenRepository, rmr *mocks.RememberTokenRepository) {
+				sr.On("DeleteByUserID", 2).Return(nil)
+				rr.On("DeleteByUserID", 2).Return(nil)
+				rmr.On("DeleteByUserID", 2).Return(nil)
+			},
+		},
+		{
+			name:   "BadCase/InvalidID",
+			userID: 0,
+			setExpectations: func(sr *mocks.SessionRepository, rr *mocks.RefreshTokenRepository, rmr *mocks.RememberTokenRepository) {
+			},
+			err: domain.ErrNotFound,
+		},
+		{
+			name:   "BadCase/RefreshTokensError",
+			userID: 2,
+			setExpectations: func(sr *mocks.SessionRepository, rr *mocks.RefreshTokenRepository, rmr *mocks.RememberTokenRepository) {
+				sr.On("DeleteByUserID", 2).Return(nil)
+				rr.On("DeleteByUserID", 2).Return(domain.ErrInternalServerError)
+			},
+			err: domain.ErrInternalServerError,
+		},
+		{
+			name:   "BadCase/RememberTokensError",
+			userID: 2,
+			setExpectations: func(sr *mocks.SessionRepository, rr *mocks.RefreshTokenRepository, rmr *mocks.RememberTokenRepository) {
+				sr.On("DeleteByUserID", 2).Return(nil)
+				rr.On("DeleteByUserID", 2).Return(nil)
+				rmr.On("DeleteByUserID", 2).Return(domain.ErrInternalServerError)
+			},
+			err: domain.ErrInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ur := new(mocks.UsersRepository)
+			sr := new(mocks.SessionRepository)
+			rr := new(mocks.RefreshTokenRepository)
+			rmr := new(mocks.RememberTokenRepository)
+			test.setExpectations(sr, rr, rmr)
+
+			err := usecase.NewAdminUsecase(ur, sr, rr, new(mocks.TwoFactorRepository), rmr).Logout(test.userID)
+
+			assert.ErrorIs(t, err, test.err)
+			sr.AssertExpectations(t)
+			rr.AssertExpectations(t)
+			rmr.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSetTwoFactorRequired(t *testing.T) {
+	tests := []struct {
+		name            string
+		role            domain.Role
+		setExpectations func(tfr *mocks.TwoFactorRepository)
+		err             error
+	}{
+		{
+			name: "GoodCase/Common",
+			role: domain.Moder,
+			setExpectations: func(tfr *mocks.TwoFactorRepository) {
+				tfr.On("SetRequired", domain.Moder, true).Return(nil)
+			},
+		},
+		{
+			name:            "BadCase/InvalidRole",
+			role:            domain.Role(10),
+			setExpectations: func(tfr *mocks.TwoFactorRepository) {},
+			err:             domain.ErrBadRequest,
+		},
+		{
+			name: "BadCase/DBError",
+			role: domain.Moder,
+			setExpectations: func(tfr *mocks.TwoFactorRepository) {
+				tfr.On("SetRequired", domain.Moder, true).Return(domain.ErrInternalServerError)
+			},
+			err: domain.ErrInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tfr := new(mocks.TwoFactorRepository)
+			test.setExpectations(tfr)
+
+			err := usecase.NewAdminUsecase(new(mocks.UsersRepository), new(mocks.SessionRepository),
+				new(mocks.RefreshTokenRepository), tfr, new(mocks.RememberTokenRepository)).SetTwoFactorRequired(test.role, true)
+
+			assert.ErrorIs(t, err, test.err)
+			tfr.AssertExpectations(t)
+		})
+	}
+}
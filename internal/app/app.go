@@ -2,14 +2,20 @@ package app
 
 import (
 	"context"
+	"crypto/rand"
+	"errors"
 	"github.com/ellexo2456/FilmLib/internal/middleware"
 	"net/http"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
+	goredis "github.com/redis/go-redis/v9"
 	"github.com/swaggo/http-swagger"
 
 	auth_http "github.com/ellexo2456/FilmLib/internal/auth/delivery/http"
+	auth_memory "github.com/ellexo2456/FilmLib/internal/auth/repository/memory"
 	auth_postgres "github.com/ellexo2456/FilmLib/internal/auth/repository/postgresql"
 	auth_redis "github.com/ellexo2456/FilmLib/internal/auth/repository/redis"
 	auth_usecase "github.com/ellexo2456/FilmLib/internal/auth/usecase"
@@ -18,14 +24,41 @@ import (
 	films_postgres "github.com/ellexo2456/FilmLib/internal/films/repository/postgresql"
 	films_usecase "github.com/ellexo2456/FilmLib/internal/films/usecase"
 
+	admin_http "github.com/ellexo2456/FilmLib/internal/admin/delivery/http"
+	admin_postgres "github.com/ellexo2456/FilmLib/internal/admin/repository/postgresql"
+	admin_usecase "github.com/ellexo2456/FilmLib/internal/admin/usecase"
+
+	tokens_http "github.com/ellexo2456/FilmLib/internal/tokens/delivery/http"
+	tokens_postgres "github.com/ellexo2456/FilmLib/internal/tokens/repository/postgresql"
+	tokens_usecase "github.com/ellexo2456/FilmLib/internal/tokens/usecase"
+
 	actors_http "github.com/ellexo2456/FilmLib/internal/actors/delivery/http"
 	actors_postgres "github.com/ellexo2456/FilmLib/internal/actors/repository/postgresql"
 	actors_usecase "github.com/ellexo2456/FilmLib/internal/actors/usecase"
 
+	audit_http "github.com/ellexo2456/FilmLib/internal/audit/delivery/http"
+	audit_postgres "github.com/ellexo2456/FilmLib/internal/audit/repository/postgresql"
+	audit_usecase "github.com/ellexo2456/FilmLib/internal/audit/usecase"
+
+	catalog_http "github.com/ellexo2456/FilmLib/internal/catalog/delivery/http"
+	catalog_postgres "github.com/ellexo2456/FilmLib/internal/catalog/repository/postgresql"
+	catalog_usecase "github.com/ellexo2456/FilmLib/internal/catalog/usecase"
+
+	idempotency_redis "github.com/ellexo2456/FilmLib/internal/idempotency/repository/redis"
+	idempotency_usecase "github.com/ellexo2456/FilmLib/internal/idempotency/usecase"
+
+	webhooks_http "github.com/ellexo2456/FilmLib/internal/webhooks/delivery/http"
+	webhooks_postgres "github.com/ellexo2456/FilmLib/internal/webhooks/repository/postgresql"
+	webhooks_usecase "github.com/ellexo2456/FilmLib/internal/webhooks/usecase"
+
 	_ "github.com/ellexo2456/FilmLib/docs"
 	"github.com/ellexo2456/FilmLib/internal/connectors/postgres"
 	"github.com/ellexo2456/FilmLib/internal/connectors/redis"
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	"github.com/ellexo2456/FilmLib/internal/jwt"
 	logs "github.com/ellexo2456/FilmLib/internal/logger"
+	"github.com/ellexo2456/FilmLib/internal/mailer"
+	"github.com/ellexo2456/FilmLib/internal/oidc"
 )
 
 func StartServer() {
@@ -40,35 +73,253 @@ func StartServer() {
 	rc := redis.Connect()
 	defer rc.Close()
 
-	sr := auth_redis.NewSessionRedisRepository(rc)
+	sr := sessionRepository(ctx, pc, rc)
+	rr := auth_redis.NewResetTokenRedisRepository(rc)
+	rtr := auth_redis.NewRefreshTokenRedisRepository(rc)
+	str := auth_redis.NewOIDCStateRedisRepository(rc)
+	mcr := auth_redis.NewMFAChallengeRedisRepository(rc)
+	atr := auth_redis.NewAttemptsRedisRepository(rc)
+	rmr := auth_redis.NewRememberTokenRedisRepository(rc)
+	ir := auth_postgres.NewIdentityPostgresqlRepository(pc, ctx)
 	ar := auth_postgres.NewAuthPostgresqlRepository(pc, ctx)
+	tfr := auth_postgres.NewTwoFactorPostgresqlRepository(pc, ctx)
 	acr := actors_postgres.NewActorsPostgresqlRepository(pc, ctx)
 	fr := films_postgres.NewFilmsPostgresqlRepository(pc, ctx)
+	ur := admin_postgres.NewUsersPostgresqlRepository(pc, ctx)
+	tr := tokens_postgres.NewTokensPostgresqlRepository(pc, ctx)
+	aur := audit_postgres.NewAuditPostgresqlRepository(pc, ctx)
+	cr := catalog_postgres.NewCatalogPostgresqlRepository(pc, ctx)
+	idr := idempotency_redis.NewIdempotencyRedisRepository(rc)
+	wr := webhooks_postgres.NewWebhooksPostgresqlRepository(pc, ctx)
 
-	au := auth_usecase.NewAuthUsecase(ar, sr)
-	acu := actors_usecase.NewActorsUsecase(acr)
-	fu := films_usecase.NewFilmsUsecase(fr)
+	m := mailer.New()
+	vu := auth_usecase.NewVerificationUsecase(ar, m, secretFromEnv("EMAIL_VERIFICATION_SECRET"),
+		os.Getenv("EMAIL_VERIFICATION_URL"), durationFromEnv("EMAIL_VERIFICATION_TTL", 24*time.Hour))
+	policy := domain.SessionPolicy{
+		IdleTimeout:     durationFromEnv("SESSION_IDLE_TIMEOUT", 24*time.Hour),
+		AbsoluteTimeout: durationFromEnv("SESSION_ABSOLUTE_TIMEOUT", 7*24*time.Hour),
+		RememberTTL:     durationFromEnv("REMEMBER_ME_TTL", 30*24*time.Hour),
+	}
+	tfu := auth_usecase.NewTwoFactorUsecase(ar, tfr, mcr, sr, rmr, envOr("TOTP_ISSUER", "FilmLib"), policy)
+	au := auth_usecase.NewAuthUsecase(ar, sr, vu, tfu, rmr, policy)
+	lg := auth_usecase.NewLoginGuard(atr,
+		policyFromEnv("LOGIN_EMAIL", domain.ThrottlePolicy{
+			FreeAttempts:    3,
+			BaseDelay:       time.Second,
+			MaxDelay:        time.Minute,
+			LockoutAfter:    10,
+			LockoutDuration: 15 * time.Minute,
+			Window:          time.Hour,
+		}),
+		policyFromEnv("LOGIN_IP", domain.ThrottlePolicy{
+			FreeAttempts:    20,
+			BaseDelay:       time.Second,
+			MaxDelay:        time.Minute,
+			LockoutAfter:    100,
+			LockoutDuration: 15 * time.Minute,
+			Window:          time.Hour,
+		}),
+		domain.SystemClock{})
+	su := auth_usecase.NewSessionsUsecase(sr, rtr, rmr)
+	pu := auth_usecase.NewPasswordUsecase(ar, rr, su, tr, m,
+		os.Getenv("PASSWORD_RESET_URL"), durationFromEnv("PASSWORD_RESET_TTL", time.Hour))
+	auu := audit_usecase.NewAuditUsecase(aur)
+	wu := webhooks_usecase.NewWebhooksUsecase(wr, webhooks_usecase.NewClient(10*time.Second), domain.SystemClock{})
+	acu := actors_usecase.NewActorsUsecase(acr, auu, wu)
+	fu := films_usecase.NewFilmsUsecase(fr, auu, wu)
+	cu := catalog_usecase.NewCatalogUsecase(cr, auu, wu)
+	adu := admin_usecase.NewAdminUsecase(ur, sr, rtr, tfr, rmr)
+	tu := tokens_usecase.NewTokensUsecase(tr)
+	idu := idempotency_usecase.NewIdempotencyUsecase(idr, durationFromEnv("IDEMPOTENCY_TTL", 24*time.Hour))
+	if purgeInterval := durationFromEnv("TRASH_PURGE_INTERVAL", time.Hour); purgeInterval > 0 {
+		go purgeTrash(ctx, purgeInterval, durationFromEnv("TRASH_RETENTION", 30*24*time.Hour), fu, acu)
+	}
+	if dispatchInterval := durationFromEnv("WEBHOOK_DISPATCH_INTERVAL", 5*time.Second); dispatchInterval > 0 {
+		go dispatchWebhooks(ctx, dispatchInterval, wu)
+	}
 
 	authMux := http.NewServeMux()
 	apiMux := http.NewServeMux()
 
-	auth_http.NewAuthHandler(authMux, au)
+	auth_http.NewAuthHandler(authMux, au, lg)
+	auth_http.NewPasswordHandler(authMux, pu)
+	auth_http.NewVerificationHandler(authMux, vu)
+	auth_http.NewTwoFactorHandler(authMux, tfu, lg)
+	auth_http.NewTwoFactorSettingsHandler(apiMux, tfu)
+	auth_http.NewSessionsHandler(apiMux, su)
 	actors_http.NewActorsHandler(apiMux, acu)
 	films_http.NewFilmsHandler(apiMux, fu)
+	admin_http.NewAdminHandler(apiMux, adu)
+	tokens_http.NewTokensHandler(apiMux, tu)
+	audit_http.NewAuditHandler(apiMux, auu)
+	catalog_http.NewCatalogHandler(apiMux, cu)
+	webhooks_http.NewWebhooksHandler(apiMux, wu)
 	mux.HandleFunc("/swagger/*", httpSwagger.WrapHandler)
 
-	amw := middleware.NewAuth(au)
+	oidcClients, err := oidc.New()
+	if err != nil {
+		logs.LogFatal(logs.Logger, "app", "main", err, err.Error())
+	}
+	if len(oidcClients) != 0 {
+		ou := auth_usecase.NewOIDCUsecase(oidcClients, ar, ir, str, sr, su, tr, tfu, policy)
+		auth_http.NewOIDCHandler(authMux, ou)
+	}
+
+	// in the jwt mode requests can be authorized with signed access
+	// tokens, sessions keep working alongside them
+	var tau domain.TokenAuthUsecase
+	if os.Getenv("AUTH_MODE") == "jwt" {
+		keyring, err := jwt.New()
+		if err != nil {
+			logs.LogFatal(logs.Logger, "app", "main", err, err.Error())
+		}
+		tau = auth_usecase.NewTokenAuthUsecase(ar, rtr, tfu, keyring,
+			durationFromEnv("JWT_ACCESS_TTL", 15*time.Minute), durationFromEnv("JWT_REFRESH_TTL", 30*24*time.Hour))
+		auth_http.NewTokenHandler(authMux, tau, lg)
+	}
+
+	domain.SecureCookies = os.Getenv("COOKIE_SECURE") != "false"
+	amw := middleware.NewAuth(au, vu, tu, tau)
+	imw := middleware.NewIdempotency(idu)
 	logger := middleware.NewLogger(logs.Logger)
 
-	mux.Handle("/api/v1/auth/", http.StripPrefix("/api/v1/auth", authMux))
-	mux.Handle("/api/v1/", http.StripPrefix("/api/v1", amw.IsAuth(apiMux)))
+	mux.Handle("/api/v1/auth/", http.StripPrefix("/api/v1/auth", middleware.CSRF(authMux)))
+	mux.Handle("/api/v1/", http.StripPrefix("/api/v1", middleware.CSRF(amw.IsAuth(amw.RefreshVerified(imw.Handle(apiMux))))))
 
 	port := ":" + os.Getenv("HTTP_SERVER_PORT")
 	logs.Logger.Info("start listening on port" + port)
-	err = http.ListenAndServe(port, logger.AccessLogMiddleware(mux))
+	err = http.ListenAndServe(port, middleware.RequestID(logger.AccessLogMiddleware(mux)))
 	if err != nil {
 		logs.LogFatal(logs.Logger, "app", "main", err, err.Error())
 	}
 
 	logs.Logger.Info("server stopped")
 }
+
+// sessionRepository picks the session store by SESSION_STORE. The memory
+// one suits a single instance, its sessions are lost on restart.
+func sessionRepository(ctx context.Context, pc domain.PgxPoolIface, rc *goredis.Client) domain.SessionRepository {
+	sweep := durationFromEnv("SESSION_SWEEP_INTERVAL", 10*time.Minute)
+
+	switch store := envOr("SESSION_STORE", "redis"); store {
+	case "redis":
+		return auth_redis.NewSessionRedisRepository(rc)
+	case "postgres":
+		return auth_postgres.NewSessionPostgresqlRepository(pc, ctx, sweep)
+	case "memory":
+		return auth_memory.NewSessionMemoryRepository(ctx, domain.SystemClock{}, sweep)
+	default:
+		err := errors.New("unknown SESSION_STORE " + store)
+		logs.LogFatal(logs.Logger, "app", "sessionRepository", err, err.Error())
+		return nil
+	}
+}
+
+// purgeTrash permanently removes entities which are in the trash longer
+// than the retention, every interval until ctx is done. The interval
+// must be positive.
+func purgeTrash(ctx context.Context, interval, retention time.Duration, purgers ...domain.TrashPurger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, p := range purgers {
+				count, err := p.Purge(retention)
+				if err != nil {
+					continue
+				}
+				logs.Logger.Debug("purgeTrash: entities purged:", count)
+			}
+		}
+	}
+}
+
+// dispatchWebhooks sends the due webhook deliveries every interval until
+// ctx is done, batches are sent one after another while there are any.
+// The interval must be positive.
+func dispatchWebhooks(ctx context.Context, interval time.Duration, wu domain.WebhooksUsecase) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for ctx.Err() == nil {
+				count, err := wu.Dispatch()
+				if err != nil || count == 0 {
+					break
+				}
+				logs.Logger.Debug("dispatchWebhooks: deliveries sent:", count)
+			}
+		}
+	}
+}
+
+func durationFromEnv(name string, def time.Duration) time.Duration {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		logs.LogError(logs.Logger, "app", "durationFromEnv", err, "invalid duration in "+name+", using default")
+		return def
+	}
+
+	return d
+}
+
+func intFromEnv(name string, def int) int {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		logs.LogError(logs.Logger, "app", "intFromEnv", err, "invalid number in "+name+", using default")
+		return def
+	}
+
+	return i
+}
+
+// policyFromEnv reads <prefix>_FREE_ATTEMPTS, <prefix>_BASE_DELAY and so on.
+func policyFromEnv(prefix string, def domain.ThrottlePolicy) domain.ThrottlePolicy {
+	return domain.ThrottlePolicy{
+		FreeAttempts:    intFromEnv(prefix+"_FREE_ATTEMPTS", def.FreeAttempts),
+		BaseDelay:       durationFromEnv(prefix+"_BASE_DELAY", def.BaseDelay),
+		MaxDelay:        durationFromEnv(prefix+"_MAX_DELAY", def.MaxDelay),
+		LockoutAfter:    intFromEnv(prefix+"_LOCKOUT_AFTER", def.LockoutAfter),
+		LockoutDuration: durationFromEnv(prefix+"_LOCKOUT_DURATION", def.LockoutDuration),
+		Window:          durationFromEnv(prefix+"_WINDOW", def.Window),
+	}
+}
+
+func envOr(name, def string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+
+	return def
+}
+
+// secretFromEnv falls back to a random secret, which is fine for a single
+// instance but invalidates everything signed with it on restart.
+func secretFromEnv(name string) []byte {
+	if v := os.Getenv(name); v != "" {
+		return []byte(v)
+	}
+
+	logs.Logger.Warn(name + " is not set, using a random secret")
+	secret := make([]byte, 32)
+	rand.Read(secret)
+	return secret
+}
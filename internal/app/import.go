@@ -0,0 +1,83 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/joho/godotenv"
+
+	audit_postgres "github.com/ellexo2456/FilmLib/internal/audit/repository/postgresql"
+	audit_usecase "github.com/ellexo2456/FilmLib/internal/audit/usecase"
+	catalog_postgres "github.com/ellexo2456/FilmLib/internal/catalog/repository/postgresql"
+	catalog_usecase "github.com/ellexo2456/FilmLib/internal/catalog/usecase"
+	"github.com/ellexo2456/FilmLib/internal/connectors/postgres"
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	webhooks_postgres "github.com/ellexo2456/FilmLib/internal/webhooks/repository/postgresql"
+	webhooks_usecase "github.com/ellexo2456/FilmLib/internal/webhooks/usecase"
+)
+
+// Import runs the import command, the same as POST /api/v1/import but
+// without the size limit. The report is printed to stdout, the exit
+// code is 1 if any row has failed.
+func Import(args []string) int {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	format := flags.String("format", "", "csv or ndjson, taken from the file extension by default")
+	commit := flags.Bool("commit", false, "save the changes, the dry run is made by default")
+	userID := flags.Int("user", 0, "id of the user the changes are audited for")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: film_lib import [-commit] [-format csv|ndjson] [-user id] file")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	path := flags.Arg(0)
+	if *format == "" {
+		*format = strings.TrimPrefix(filepath.Ext(path), ".")
+	}
+	if !domain.CatalogFormat(*format).Importable() {
+		fmt.Fprintln(os.Stderr, "format must be csv or ndjson")
+		return 2
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer file.Close()
+
+	godotenv.Load()
+	ctx := context.Background()
+	pc := postgres.Connect(ctx, postgres.GetDbParams())
+	defer pc.Close()
+
+	// the events are only queued here, the server sends them
+	wu := webhooks_usecase.NewWebhooksUsecase(webhooks_postgres.NewWebhooksPostgresqlRepository(pc, ctx),
+		http.DefaultClient, domain.SystemClock{})
+	cu := catalog_usecase.NewCatalogUsecase(catalog_postgres.NewCatalogPostgresqlRepository(pc, ctx),
+		audit_usecase.NewAuditUsecase(audit_postgres.NewAuditPostgresqlRepository(pc, ctx)), wu)
+	report, err := cu.Import(file, domain.CatalogFormat(*format), *commit, domain.AuditContext{UserID: *userID, RequestID: "cli"})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(report)
+	if report.Failed != 0 {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,104 @@
+package app
+
+import (
+	"compress/gzip"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	catalog_postgres "github.com/ellexo2456/FilmLib/internal/catalog/repository/postgresql"
+	catalog_usecase "github.com/ellexo2456/FilmLib/internal/catalog/usecase"
+	"github.com/ellexo2456/FilmLib/internal/connectors/postgres"
+	"github.com/ellexo2456/FilmLib/internal/domain"
+)
+
+var datasetFlags = map[domain.DatasetKind]string{
+	domain.TitlesDataset:     "titles",
+	domain.RatingsDataset:    "ratings",
+	domain.NamesDataset:      "names",
+	domain.PrincipalsDataset: "principals",
+}
+
+// ImportTSV runs the import-tsv command. The given dumps are imported in
+// the order of domain.DatasetKinds, an interrupted one is resumed on the
+// next run. The changes aren`t put to the audit log.
+func ImportTSV(args []string) int {
+	flags := flag.NewFlagSet("import-tsv", flag.ExitOnError)
+	paths := make(map[domain.DatasetKind]*string, len(domain.DatasetKinds))
+	for kind, name := range datasetFlags {
+		paths[kind] = flags.String(name, "", "path to the "+string(kind)+".tsv(.gz) file")
+	}
+	batch := flags.Int("batch", 50000, "rows per transaction")
+	restart := flags.Bool("restart", false, "import the files from the beginning")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: film_lib import-tsv [-titles file] [-ratings file] [-names file] [-principals file]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 0 || *batch <= 0 {
+		flags.Usage()
+		return 2
+	}
+
+	godotenv.Load()
+	ctx := context.Background()
+	pc := postgres.Connect(ctx, postgres.GetDbParams())
+	defer pc.Close()
+
+	du := catalog_usecase.NewDatasetUsecase(catalog_postgres.NewDatasetPostgresqlRepository(pc, ctx), *batch)
+	for _, kind := range domain.DatasetKinds {
+		if *paths[kind] == "" {
+			continue
+		}
+
+		if err := importDataset(du, kind, *paths[kind], *restart); err != nil {
+			fmt.Fprintln(os.Stderr, kind, err)
+			return 1
+		}
+	}
+
+	return 0
+}
+
+func importDataset(du domain.DatasetUsecase, kind domain.DatasetKind, path string, restart bool) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	var r io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	start := time.Now()
+	report := func(p domain.DatasetProgress) {
+		fmt.Fprintf(os.Stderr, "%s: %d lines, %d imported, %d skipped, %s\n",
+			p.Kind, p.Lines, p.Imported, p.Skipped, time.Since(start).Round(time.Second))
+	}
+	p, err := du.Import(kind, r, info.Size(), restart, report)
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(os.Stderr, "done ")
+	report(p)
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+	"github.com/ellexo2456/FilmLib/internal/middleware"
+)
+
+type AuditHandler struct {
+	AuditUsecase domain.AuditUsecase
+}
+
+func NewAuditHandler(mux *http.ServeMux, au domain.AuditUsecase) {
+	handler := &AuditHandler{
+		AuditUsecase: au,
+	}
+
+	mux.Handle("GET /admin/audit", middleware.Require(domain.AuditRead, handler.GetEntries))
+}
+
+// GetEntries godoc
+//
+//	@Summary		Gets the audit log.
+//	@Description	Gets changes of films and actors, newest first. Requires audit:read permission.
+//	@Tags			Admin
+//	@Param			userId		query	int		false	"Id of the user who made the change"
+//	@Param			entity		query	string	false	"film or actor"
+//	@Param			entityId	query	int		false	"Id of the changed entity"
+//	@Param			from		query	string	false	"Start of the time range, RFC 3339"
+//	@Param			to			query	string	false	"End of the time range (exclusive), RFC 3339"
+//	@Param			limit		query	int		false	"Max entries count, 50 by default"
+//	@Param			offset		query	int		false	"Entries count to skip"
+//	@Produce		json
+//	@Success		200	{object}	object{body=object{entries=[]domain.AuditEntry}}
+//	@Failure		400	{object}	object{err=string}
+//	@Failure		403	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/admin/audit [get]
+func (h *AuditHandler) GetEntries(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseFilter(r)
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "audit/http", "GetEntries", err, err.Error())
+		return
+	}
+
+	entries, err := h.AuditUsecase.GetAll(filter)
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "audit/http", "GetEntries", err, err.Error())
+		return
+	}
+
+	domain.WriteResponse(
+		w,
+		map[string]interface{}{
+			"entries": entries,
+		},
+		http.StatusOK,
+	)
+}
+
+func parseFilter(r *http.Request) (domain.AuditFilter, error) {
+	queryParams := r.URL.Query()
+	filter := domain.AuditFilter{
+		Entity: domain.AuditEntity(queryParams.Get(domain.EntityParam)),
+	}
+
+	ints := map[string]*int{
+		domain.UserIDParam:   &filter.UserID,
+		domain.EntityIDParam: &filter.EntityID,
+		domain.LimitParam:    &filter.Limit,
+		domain.OffsetParam:   &filter.Offset,
+	}
+	var err error
+	for name, dst := range ints {
+		if v := queryParams.Get(name); v != "" {
+			if *dst, err = strconv.Atoi(v); err != nil {
+				return domain.AuditFilter{}, err
+			}
+		}
+	}
+
+	times := map[string]*time.Time{
+		domain.FromParam: &filter.From,
+		domain.ToParam:   &filter.To,
+	}
+	for name, dst := range times {
+		if v := queryParams.Get(name); v != "" {
+			if *dst, err = time.Parse(time.RFC3339, v); err != nil {
+				return domain.AuditFilter{}, err
+			}
+		}
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,76 @@
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	audit_http "github.com/ellexo2456/FilmLib/internal/audit/delivery/http"
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	"github.com/ellexo2456/FilmLib/internal/domain/mocks"
+)
+
+func TestGetEntries(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                 string
+		query                string
+		sc                   domain.SessionContext
+		setUCaseExpectations func(usecase *mocks.AuditUsecase)
+		status               int
+	}{
+		{
+			name:  "GoodCase/Common",
+			query: "?userId=2&entity=film&entityId=1&from=2024-03-01T00:00:00Z&to=2024-03-02T00:00:00Z&limit=10&offset=5",
+			sc:    domain.SessionContext{UserID: 1, Role: domain.Admin, Verified: true},
+			setUCaseExpectations: func(usecase *mocks.AuditUsecase) {
+				usecase.On("GetAll", domain.AuditFilter{
+					UserID:   2,
+					Entity:   domain.AuditFilm,
+					EntityID: 1,
+					From:     from,
+					To:       from.Add(24 * time.Hour),
+					Limit:    10,
+					Offset:   5,
+				}).Return([]domain.AuditEntry{{ID: 1}}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:                 "BadCase/InvalidTime",
+			query:                "?from=yesterday",
+			sc:                   domain.SessionContext{UserID: 1, Role: domain.Admin, Verified: true},
+			setUCaseExpectations: func(usecase *mocks.AuditUsecase) {},
+			status:               http.StatusBadRequest,
+		},
+		{
+			name:                 "BadCase/Moderator",
+			sc:                   domain.SessionContext{UserID: 1, Role: domain.Moder, Verified: true},
+			setUCaseExpectations: func(usecase *mocks.AuditUsecase) {},
+			status:               http.StatusForbidden,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockUsecase := new(mocks.AuditUsecase)
+			test.setUCaseExpectations(mockUsecase)
+
+			mux := http.NewServeMux()
+			audit_http.NewAuditHandler(mux, mockUsecase)
+
+			req := httptest.NewRequest("GET", "/admin/audit"+test.query, nil)
+			req = req.WithContext(context.WithValue(context.Background(), domain.SessionContextKey, test.sc))
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
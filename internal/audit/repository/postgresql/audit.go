@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+)
+
+const insertQuery = `
+	INSERT INTO audit_log (user_id, action, entity, entity_id, diff, request_id)
+	VALUES ($1, $2, $3, $4, $5, $6)
+`
+
+const selectQuery = `
+	SELECT id, user_id, action, entity, entity_id, diff, request_id, created_at
+	FROM audit_log
+	WHERE ($1::INT = 0 OR user_id = $1)
+	  AND ($2::TEXT = '' OR entity = $2)
+	  AND ($3::INT = 0 OR entity_id = $3)
+	  AND ($4::TIMESTAMPTZ IS NULL OR created_at >= $4)
+	  AND ($5::TIMESTAMPTZ IS NULL OR created_at < $5)
+	ORDER BY id DESC
+	LIMIT $6 OFFSET $7
+`
+
+type auditPostgresqlRepository struct {
+	db  domain.PgxPoolIface
+	ctx context.Context
+}
+
+func NewAuditPostgresqlRepository(pool domain.PgxPoolIface, ctx context.Context) domain.AuditRepository {
+	return &auditPostgresqlRepository{
+		db:  pool,
+		ctx: ctx,
+	}
+}
+
+func (r *auditPostgresqlRepository) Insert(entry domain.AuditEntry) error {
+	diff, err := json.Marshal(entry.Diff)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(r.ctx, insertQuery,
+		entry.UserID, entry.Action, entry.Entity, entry.EntityID, diff, entry.RequestID)
+	if err != nil {
+		logs.LogError(logs.Logger, "audit/postgres", "Insert", err, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (r *auditPostgresqlRepository) Select(filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+	rows, err := r.db.Query(r.ctx, selectQuery,
+		filter.UserID, filter.Entity, filter.EntityID, nullTime(filter.From), nullTime(filter.To),
+		filter.Limit, filter.Offset)
+	if err != nil {
+		logs.LogError(logs.Logger, "audit/postgres", "Select", err, err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []domain.AuditEntry{}
+	for rows.Next() {
+		var entry domain.AuditEntry
+		var diff []byte
+		err = rows.Scan(
+			&entry.ID,
+			&entry.UserID,
+			&entry.Action,
+			&entry.Entity,
+			&entry.EntityID,
+			&diff,
+			&entry.RequestID,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			logs.LogError(logs.Logger, "audit/postgres", "Select", err, err.Error())
+			return nil, err
+		}
+		if err = json.Unmarshal(diff, &entry.Diff); err != nil {
+			logs.LogError(logs.Logger, "audit/postgres", "Select", err, err.Error())
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+	if err = rows.Err(); err != nil {
+		logs.LogError(logs.Logger, "audit/postgres", "Select", err, err.Error())
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
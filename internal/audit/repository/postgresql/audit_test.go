@@ -0,0 +1,133 @@
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/require"
+
+	postgres "github.com/ellexo2456/FilmLib/internal/audit/repository/postgresql"
+	"github.com/ellexo2456/FilmLib/internal/domain"
+)
+
+const insertQueryTest = `
+	INSERT INTO audit_log
+`
+
+const selectQueryTest = `
+	SELECT id, user_id, action, entity, entity_id, diff, request_id, created_at
+	FROM audit_log
+`
+
+func TestInsert(t *testing.T) {
+	tests := []struct {
+		name  string
+		dbErr error
+	}{
+		{
+			name: "GoodCase/Common",
+		},
+		{
+			name:  "BadCase/DbError",
+			dbErr: errors.New("some db error"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockDB, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mockDB.Close()
+
+			entry := domain.AuditEntry{
+				UserID:    2,
+				Action:    domain.AuditUpdate,
+				Entity:    domain.AuditFilm,
+				EntityID:  1,
+				Diff:      map[string]domain.FieldChange{"title": {Before: "a", After: "b"}},
+				RequestID: "request",
+			}
+			eq := mockDB.ExpectExec(insertQueryTest).
+				WithArgs(2, domain.AuditUpdate, domain.AuditFilm, 1,
+					[]byte(`{"title":{"before":"a","after":"b"}}`), "request")
+			if test.dbErr != nil {
+				eq.WillReturnError(test.dbErr)
+			} else {
+				eq.WillReturnResult(pgxmock.NewResult("INSERT", 1))
+			}
+
+			r := postgres.NewAuditPostgresqlRepository(mockDB, context.Background())
+			err = r.Insert(entry)
+
+			require.ErrorIs(t, err, test.dbErr)
+			require.NoError(t, mockDB.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSelect(t *testing.T) {
+	created := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		filter domain.AuditFilter
+		args   []interface{}
+		dbErr  error
+	}{
+		{
+			name:   "GoodCase/AllFilters",
+			filter: domain.AuditFilter{UserID: 2, Entity: domain.AuditFilm, EntityID: 1, From: created, To: created.Add(time.Hour), Limit: 10},
+			args:   []interface{}{2, domain.AuditFilm, 1, pgxmock.AnyArg(), pgxmock.AnyArg(), 10, 0},
+		},
+		{
+			name:   "GoodCase/NoTimeRange",
+			filter: domain.AuditFilter{Limit: 10},
+			args:   []interface{}{0, domain.AuditEntity(""), 0, (*time.Time)(nil), (*time.Time)(nil), 10, 0},
+		},
+		{
+			name:   "BadCase/DbError",
+			filter: domain.AuditFilter{Limit: 10},
+			args:   []interface{}{0, domain.AuditEntity(""), 0, (*time.Time)(nil), (*time.Time)(nil), 10, 0},
+			dbErr:  errors.New("some db error"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockDB, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mockDB.Close()
+
+			eq := mockDB.ExpectQuery(selectQueryTest).WithArgs(test.args...)
+			if test.dbErr != nil {
+				eq.WillReturnError(test.dbErr)
+			} else {
+				eq.WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "action", "entity", "entity_id",
+					"diff", "request_id", "created_at"}).
+					AddRow(int64(5), 2, domain.AuditDelete, domain.AuditFilm, 1,
+						[]byte(`{"title":{"before":"a"}}`), "request", created))
+			}
+
+			r := postgres.NewAuditPostgresqlRepository(mockDB, context.Background())
+			entries, err := r.Select(test.filter)
+
+			require.ErrorIs(t, err, test.dbErr)
+			if test.dbErr == nil {
+				require.Equal(t, []domain.AuditEntry{{
+					ID:        5,
+					UserID:    2,
+					Action:    domain.AuditDelete,
+					Entity:    domain.AuditFilm,
+					EntityID:  1,
+					Diff:      map[string]domain.FieldChange{"title": {Before: "a"}},
+					RequestID: "request",
+					CreatedAt: created,
+				}}, entries)
+			}
+			require.NoError(t, mockDB.ExpectationsWereMet())
+		})
+	}
+}
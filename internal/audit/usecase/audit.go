@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"encoding/json"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+)
+
+const maxLimit = 500
+
+type auditUsecase struct {
+	auditRepo domain.AuditRepository
+}
+
+func NewAuditUsecase(ar domain.AuditRepository) domain.AuditUsecase {
+	return &auditUsecase{
+		auditRepo: ar,
+	}
+}
+
+// Record is called after the change has been made, so a failure can`t
+// roll it back. The entry is logged instead to be restored by hand.
+func (u *auditUsecase) Record(ac domain.AuditContext, action domain.AuditAction, entity domain.AuditEntity,
+	entityID int, before, after interface{}) {
+	entry := domain.AuditEntry{
+		UserID:    ac.UserID,
+		Action:    action,
+		Entity:    entity,
+		EntityID:  entityID,
+		RequestID: ac.RequestID,
+	}
+
+	var err error
+	if entry.Diff, err = domain.Diff(before, after); err == nil {
+		err = u.auditRepo.Insert(entry)
+	}
+	if err != nil {
+		raw, _ := json.Marshal(entry)
+		logs.LogError(logs.Logger, "audit/usecase", "Record", err, "failed to record "+string(raw))
+	}
+}
+
+func (u *auditUsecase) GetAll(filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+	if filter.Limit < 0 || filter.Offset < 0 || filter.Limit > maxLimit {
+		return nil, domain.ErrBadRequest
+	}
+	if filter.Entity != "" && !filter.Entity.Valid() {
+		return nil, domain.ErrBadRequest
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, domain.ErrBadRequest
+	}
+	if filter.Limit == 0 {
+		filter.Limit = domain.DefaultLimit
+	}
+
+	entries, err := u.auditRepo.Select(filter)
+	if err != nil {
+		logs.LogError(logs.Logger, "audit/usecase", "GetAll", err, err.Error())
+		return nil, err
+	}
+
+	return entries, nil
+}
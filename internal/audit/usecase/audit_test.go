@@ -0,0 +1,136 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ellexo2456/FilmLib/internal/audit/usecase"
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	"github.com/ellexo2456/FilmLib/internal/domain/mocks"
+)
+
+func TestRecord(t *testing.T) {
+	ac := domain.AuditContext{UserID: 2, RequestID: "request"}
+	date := pgtype.Date{Time: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+	actor := domain.Actor{ID: 1, Name: "john", Sex: domain.M, Birthdate: date}
+	renamed := actor
+	renamed.Name = "jack"
+
+	tests := []struct {
+		name          string
+		action        domain.AuditAction
+		before, after interface{}
+		diff          map[string]domain.FieldChange
+		repoErr       error
+	}{
+		{
+			name:   "GoodCase/Create",
+			action: domain.AuditCreate,
+			after:  actor,
+			diff: map[string]domain.FieldChange{
+				"id":        {After: float64(1)},
+				"name":      {After: "john"},
+				"sex":       {After: "M"},
+				"birthdate": {After: "2000-01-01"},
+			},
+		},
+		{
+			name:   "GoodCase/Update",
+			action: domain.AuditUpdate,
+			before: actor,
+			after:  renamed,
+			diff: map[string]domain.FieldChange{
+				"name": {Before: "john", After: "jack"},
+			},
+		},
+		{
+			name:   "GoodCase/Delete",
+			action: domain.AuditDelete,
+			before: actor,
+			diff: map[string]domain.FieldChange{
+				"id":        {Before: float64(1)},
+				"name":      {Before: "john"},
+				"sex":       {Before: "M"},
+				"birthdate": {Before: "2000-01-01"},
+			},
+		},
+		{
+			// the change is already made, so the error is only logged
+			name:    "BadCase/RepoError",
+			action:  domain.AuditCreate,
+			after:   actor,
+			repoErr: errors.New("some db error"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ar := new(mocks.AuditRepository)
+			ar.On("Insert", mock.MatchedBy(func(e domain.AuditEntry) bool {
+				if test.diff != nil && !assert.Equal(t, test.diff, e.Diff) {
+					return false
+				}
+				return e.UserID == 2 && e.RequestID == "request" && e.Action == test.action &&
+					e.Entity == domain.AuditActor && e.EntityID == 1
+			})).Return(test.repoErr)
+
+			usecase.NewAuditUsecase(ar).Record(ac, test.action, domain.AuditActor, 1, test.before, test.after)
+
+			ar.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetAll(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		filter domain.AuditFilter
+		want   domain.AuditFilter
+		err    error
+	}{
+		{
+			name:   "GoodCase/DefaultLimit",
+			filter: domain.AuditFilter{UserID: 2, Entity: domain.AuditFilm, From: from},
+			want:   domain.AuditFilter{UserID: 2, Entity: domain.AuditFilm, From: from, Limit: domain.DefaultLimit},
+		},
+		{
+			name:   "BadCase/UnknownEntity",
+			filter: domain.AuditFilter{Entity: "user"},
+			err:    domain.ErrBadRequest,
+		},
+		{
+			name:   "BadCase/EmptyRange",
+			filter: domain.AuditFilter{From: from, To: from},
+			err:    domain.ErrBadRequest,
+		},
+		{
+			name:   "BadCase/TooBigLimit",
+			filter: domain.AuditFilter{Limit: 1000},
+			err:    domain.ErrBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ar := new(mocks.AuditRepository)
+			if test.err == nil {
+				ar.On("Select", test.want).Return([]domain.AuditEntry{{ID: 1}}, nil)
+			}
+
+			entries, err := usecase.NewAuditUsecase(ar).GetAll(test.filter)
+
+			assert.ErrorIs(t, err, test.err)
+			if test.err == nil {
+				assert.Len(t, entries, 1)
+			}
+			ar.AssertExpectations(t)
+		})
+	}
+}
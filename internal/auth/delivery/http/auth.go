@@ -14,30 +14,64 @@ import (
 
 type AuthHandler struct {
 	AuthUsecase domain.AuthUsecase
+	LoginGuard  domain.LoginGuard
 }
 
-func NewAuthHandler(mux *http.ServeMux, u domain.AuthUsecase) {
+func NewAuthHandler(mux *http.ServeMux, u domain.AuthUsecase, g domain.LoginGuard) {
 	handler := &AuthHandler{
 		AuthUsecase: u,
+		LoginGuard:  g,
 	}
 
 	mux.HandleFunc("POST /login", handler.Login)
 	mux.HandleFunc("POST /register", handler.Register)
 	mux.HandleFunc("POST /logout", handler.Logout)
 	mux.HandleFunc("POST /check", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) })
+	mux.HandleFunc("GET /csrf", handler.CSRFToken)
+}
+
+// CSRFToken godoc
+//
+//	@Summary		get csrf token
+//	@Description	return the current csrf token or issue a new one. Cookie-authorized POST, PUT, PATCH and DELETE requests must send it in the X-CSRF-Token header
+//	@Tags			Auth
+//	@Produce		json
+//	@Success		200	{object}	object{body=object{csrfToken=string}}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/auth/csrf [get]
+func (a *AuthHandler) CSRFToken(w http.ResponseWriter, r *http.Request) {
+	token := cookieValue(r, domain.CSRFCookie)
+	if token == "" {
+		var err error
+		token, err = domain.IssueCSRFToken(w)
+		if err != nil {
+			domain.WriteError(w, err.Error(), http.StatusInternalServerError)
+			logs.LogError(logs.Logger, "auth_http", "CSRFToken", err, "failed to issue csrf token")
+			return
+		}
+	}
+
+	domain.WriteResponse(
+		w,
+		map[string]interface{}{
+			"csrfToken": token,
+		},
+		http.StatusOK,
+	)
 }
 
 // Login godoc
 //
 //	@Summary		login user
-//	@Description	create user session and put it into cookie
+//	@Description	create user session and put it into cookie. With rememberMe a remember_token cookie is set as well, it starts a new session once the current one expires. If the user has 2FA, no cookie is set and the returned mfaToken is sent to /login/2fa with the code
 //	@Tags			Auth
 //	@Accept			json
 //	@Param			body	body		domain.Credentials	true	"user credentials"
-//	@Success		200		{object}	object{body=object{id=int}}
+//	@Success		200		{object}	object{body=object{id=int,mfaRequired=bool,mfaToken=string}}
 //	@Failure		400		{object}	object{err=string}
 //	@Failure		403		{object}	object{err=string}
 //	@Failure		404		{object}	object{err=string}
+//	@Failure		429		{object}	object{err=string}
 //	@Failure		500		{object}	object{err=string}
 //	@Router			/api/v1/auth/login [post]
 func (a *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
@@ -66,7 +100,17 @@ func (a *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	}
 	credentials.Email = strings.TrimSpace(credentials.Email)
 
-	session, userID, err := a.AuthUsecase.Login(credentials)
+	ip := domain.ClientIP(r)
+	if !allowAttempt(w, a.LoginGuard, credentials.Email, ip, "Login") {
+		return
+	}
+
+	session, userID, err := a.AuthUsecase.Login(credentials, domain.RequestClient(r))
+	// the right password of a user with 2FA doesn`t reset the counters,
+	// it is done once the code is accepted
+	if err != nil || !session.MFARequired {
+		reportAttempt(w, a.LoginGuard, credentials.Email, ip, err)
+	}
 	if err != nil {
 		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
 		logs.LogError(logs.Logger, "auth_http", "Login", err, "Failed to login")
@@ -74,27 +118,13 @@ func (a *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	}
 	logs.Logger.Debug("Login: session:", session)
 
-	http.SetCookie(w, &http.Cookie{
-		Name:     "session_token",
-		Value:    session.Token,
-		Expires:  session.ExpiresAt,
-		Path:     "/",
-		HttpOnly: true,
-	})
-
-	domain.WriteResponse(
-		w,
-		map[string]interface{}{
-			"id": userID,
-		},
-		http.StatusOK,
-	)
+	writeLogin(w, session, userID)
 }
 
 // Logout godoc
 //
 //	@Summary		logout user
-//	@Description	delete current session and nullify cookie
+//	@Description	delete current session with its remember-me tokens and nullify cookies
 //	@Tags			Auth
 //	@Success		204
 //	@Failure		400	{object}	object{err=string}
@@ -110,24 +140,19 @@ func (a *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	c, err := r.Cookie("session_token")
+	c, err := r.Cookie(domain.SessionCookie)
 	sessionToken := c.Value
 	logs.Logger.Debug("Logout: session token:", c)
 
-	if err = a.AuthUsecase.Logout(sessionToken); err != nil {
+	if err = a.AuthUsecase.Logout(sessionToken, cookieValue(r, domain.RememberCookie)); err != nil {
 		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
 		logs.LogError(logs.Logger, "auth_http", "Logout", err, "Failed to logout")
 		return
 	}
 
-	http.SetCookie(w, &http.Cookie{
-		Name:     "session_token",
-		Value:    "",
-		Expires:  time.Now(),
-		Path:     "/",
-		HttpOnly: true,
-	})
-
+	domain.ClearCookie(w, domain.SessionCookie)
+	domain.ClearCookie(w, domain.RememberCookie)
+	domain.ClearCookie(w, domain.CSRFCookie)
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -139,9 +164,10 @@ func (a *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 //	@Produce		json
 //	@Accept			json
 //	@Param			body	body		domain.Credentials	true	"user credentials"
-//	@Success		200		{object}	object{body=object{id=int}}
+//	@Success		200		{object}	object{body=object{id=int,mfaRequired=bool,mfaToken=string}}
 //	@Failure		400		{object}	object{err=string}
 //	@Failure		403		{object}	object{err=string}
+//	@Failure		429		{object}	object{err=string}
 //	@Failure		500		{object}	object{err=string}
 //	@Router			/api/v1/auth/register [post]
 func (a *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
@@ -169,38 +195,34 @@ func (a *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var id int
-	if id, err = a.AuthUsecase.Register(user); err != nil {
+	// only the ip is throttled, failures here tell nothing about
+	// the email owner
+	ip := domain.ClientIP(r)
+	if !allowAttempt(w, a.LoginGuard, "", ip, "Register") {
+		return
+	}
+
+	id, err := a.AuthUsecase.Register(user)
+	reportAttempt(w, a.LoginGuard, "", ip, err)
+	if err != nil {
 		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
 		logs.LogError(logs.Logger, "auth_http", "Register.register", err, "Failed to register")
 		return
 	}
 
-	session, _, err := a.AuthUsecase.Login(domain.Credentials{Email: user.Email, Password: user.Password})
+	session, _, err := a.AuthUsecase.Login(domain.Credentials{Email: user.Email, Password: user.Password},
+		domain.RequestClient(r))
 	if err != nil {
 		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
 		logs.LogError(logs.Logger, "auth_http", "Register.login", err, "Failed to login")
 		return
 	}
-	http.SetCookie(w, &http.Cookie{
-		Name:     "session_token",
-		Value:    session.Token,
-		Expires:  session.ExpiresAt,
-		Path:     "/",
-		HttpOnly: true,
-	})
 
-	domain.WriteResponse(
-		w,
-		map[string]interface{}{
-			"id": id,
-		},
-		http.StatusOK,
-	)
+	writeLogin(w, session, id)
 }
 
 func (a *AuthHandler) auth(r *http.Request) (bool, error) {
-	c, err := r.Cookie("session_token")
+	c, err := r.Cookie(domain.SessionCookie)
 	if err != nil {
 		if errors.Is(err, http.ErrNoCookie) {
 			return false, domain.ErrUnauthorized
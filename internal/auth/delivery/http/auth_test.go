@@ -24,6 +24,7 @@ func TestLogin(t *testing.T) {
 		setUCaseExpectations func(session *domain.Session, uCase *mocks.AuthUsecase)
 		status               int
 		wantCookie           bool
+		wantMFAToken         bool
 		setAuth              func(r *http.Request, uCase *mocks.AuthUsecase, session *domain.Session)
 	}{
 		{
@@ -38,13 +39,27 @@ func TestLogin(t *testing.T) {
 			setUCaseExpectations: func(session *domain.Session, uCase *mocks.AuthUsecase) {
 				err := faker.FakeData(session)
 				assert.NoError(t, err)
+				session.MFARequired = false
 				session.ExpiresAt = time.Now().Add(24 * time.Hour)
 
-				uCase.On("Login", mock.Anything).Return(*session, 1, nil)
+				uCase.On("Login", mock.Anything, mock.Anything).Return(*session, 1, nil)
 			},
 			status:     http.StatusOK,
 			wantCookie: true,
 		},
+		{
+			name: "GoodCase/TwoFactor",
+			getBody: func() []byte {
+				jsonBody, _ := json.Marshal(domain.Credentials{Email: "ferfg@fsf.ru", Password: []byte{123}})
+				return jsonBody
+			},
+			setUCaseExpectations: func(session *domain.Session, uCase *mocks.AuthUsecase) {
+				*session = domain.Session{Token: "mfa", MFARequired: true}
+				uCase.On("Login", mock.Anything, mock.Anything).Return(*session, 1, nil)
+			},
+			status:       http.StatusOK,
+			wantMFAToken: true,
+		},
 		{
 			name: "BadCase/EmptyCredentials",
 			getBody: func() []byte {
@@ -53,7 +68,7 @@ func TestLogin(t *testing.T) {
 			},
 			setUCaseExpectations: func(session *domain.Session, uCase *mocks.AuthUsecase) {
 				*session = domain.Session{}
-				uCase.On("Login", mock.Anything).Return(*session, 0, domain.ErrWrongCredentials).Maybe()
+				uCase.On("Login", mock.Anything, mock.Anything).Return(*session, 0, domain.ErrWrongCredentials).Maybe()
 			},
 			status: http.StatusBadRequest,
 		},
@@ -68,7 +83,7 @@ func TestLogin(t *testing.T) {
 			},
 			setUCaseExpectations: func(session *domain.Session, uCase *mocks.AuthUsecase) {
 				*session = domain.Session{}
-				uCase.On("Login", mock.Anything).Return(*session, 0, domain.ErrWrongCredentials).Maybe()
+				uCase.On("Login", mock.Anything, mock.Anything).Return(*session, 0, domain.ErrWrongCredentials).Maybe()
 			},
 			status: http.StatusBadRequest,
 		},
@@ -79,7 +94,7 @@ func TestLogin(t *testing.T) {
 			},
 			setUCaseExpectations: func(session *domain.Session, uCase *mocks.AuthUsecase) {
 				*session = domain.Session{}
-				uCase.On("Login", mock.Anything).Return(*session, 0, domain.ErrWrongCredentials).Maybe()
+				uCase.On("Login", mock.Anything, mock.Anything).Return(*session, 0, domain.ErrWrongCredentials).Maybe()
 			},
 			status: http.StatusBadRequest,
 		},
@@ -90,7 +105,7 @@ func TestLogin(t *testing.T) {
 			},
 			setUCaseExpectations: func(session *domain.Session, uCase *mocks.AuthUsecase) {
 				*session = domain.Session{}
-				uCase.On("Login", mock.Anything).Return(*session, 0, domain.ErrWrongCredentials).Maybe()
+				uCase.On("Login", mock.Anything, mock.Anything).Return(*session, 0, domain.ErrWrongCredentials).Maybe()
 			},
 			status: http.StatusBadRequest,
 		},
@@ -101,7 +116,7 @@ func TestLogin(t *testing.T) {
 			},
 			setUCaseExpectations: func(session *domain.Session, uCase *mocks.AuthUsecase) {
 				*session = domain.Session{}
-				uCase.On("Login", mock.Anything).Return(*session, 0, domain.ErrWrongCredentials).Maybe()
+				uCase.On("Login", mock.Anything, mock.Anything).Return(*session, 0, domain.ErrWrongCredentials).Maybe()
 			},
 			status: http.StatusBadRequest,
 		},
@@ -122,7 +137,7 @@ func TestLogin(t *testing.T) {
 				session.ExpiresAt = time.Now().Add(24 * time.Hour)
 				session.Role = domain.Usr
 
-				uCase.On("Login", mock.Anything).Return(*session, 0, nil).Maybe()
+				uCase.On("Login", mock.Anything, mock.Anything).Return(*session, 0, nil).Maybe()
 			},
 			status: http.StatusConflict,
 			setAuth: func(r *http.Request, uCase *mocks.AuthUsecase, session *domain.Session) {
@@ -153,12 +168,13 @@ func TestLogin(t *testing.T) {
 			setUCaseExpectations: func(session *domain.Session, uCase *mocks.AuthUsecase) {
 				err := faker.FakeData(session)
 				assert.NoError(t, err)
+				session.MFARequired = false
 
 				session.UserID = 1
 				session.ExpiresAt = time.Now()
 				session.Role = domain.Usr
 
-				uCase.On("Login", mock.Anything).Return(*session, 1, nil)
+				uCase.On("Login", mock.Anything, mock.Anything).Return(*session, 1, nil)
 			},
 			status:     http.StatusOK,
 			wantCookie: true,
@@ -189,11 +205,12 @@ func TestLogin(t *testing.T) {
 			setUCaseExpectations: func(session *domain.Session, uCase *mocks.AuthUsecase) {
 				err := faker.FakeData(session)
 				assert.NoError(t, err)
+				session.MFARequired = false
 
 				session.ExpiresAt = time.Now().Add(24 * time.Hour)
 				session.Role = domain.Usr
 
-				uCase.On("Login", mock.Anything).Return(*session, 1, nil).Maybe()
+				uCase.On("Login", mock.Anything, mock.Anything).Return(*session, 1, nil).Maybe()
 			},
 			status:     http.StatusOK,
 			wantCookie: true,
@@ -224,7 +241,7 @@ func TestLogin(t *testing.T) {
 			setUCaseExpectations: func(session *domain.Session, uCase *mocks.AuthUsecase) {
 				*session = domain.Session{}
 
-				uCase.On("Login", mock.Anything).Return(*session, 0, domain.ErrNotFound).Maybe()
+				uCase.On("Login", mock.Anything, mock.Anything).Return(*session, 0, domain.ErrNotFound).Maybe()
 			},
 			status: http.StatusNotFound,
 		},
@@ -245,7 +262,7 @@ func TestLogin(t *testing.T) {
 				test.setAuth(req, mockUsecase, &session)
 			}
 
-			handler := &auth_http.AuthHandler{AuthUsecase: mockUsecase}
+			handler := &auth_http.AuthHandler{AuthUsecase: mockUsecase, LoginGuard: allowingGuard()}
 			handler.Login(rec, req)
 
 			assert.Equal(t, test.status, rec.Code)
@@ -255,6 +272,10 @@ func TestLogin(t *testing.T) {
 				assert.NotEmpty(t, cookies)
 				assert.Equal(t, "session_token", cookies[0].Name)
 			}
+			if test.wantMFAToken {
+				assert.Empty(t, rec.Result().Cookies())
+				assert.Contains(t, rec.Body.String(), `"mfaToken":"mfa"`)
+			}
 
 			mockUsecase.AssertExpectations(t)
 		})
@@ -264,6 +285,7 @@ func TestLogin(t *testing.T) {
 func TestLogout(t *testing.T) {
 	tests := []struct {
 		name                 string
+		rememberToken        string
 		setUCaseExpectations func(session *domain.Session, uCase *mocks.AuthUsecase)
 		status               int
 	}{
@@ -278,7 +300,18 @@ func TestLogout(t *testing.T) {
 				}
 
 				uCase.On("RetrieveSessionContext", mock.Anything).Return(domain.SessionContext{UserID: 1, Role: domain.Usr}, nil)
-				uCase.On("Logout", mock.Anything).Return(nil)
+				uCase.On("Logout", mock.Anything, "").Return(nil)
+			},
+			status: http.StatusNoContent,
+		},
+		{
+			name:          "GoodCase/RememberToken",
+			rememberToken: "remember",
+			setUCaseExpectations: func(session *domain.Session, uCase *mocks.AuthUsecase) {
+				*session = domain.Session{Token: "session_token"}
+
+				uCase.On("RetrieveSessionContext", mock.Anything).Return(domain.SessionContext{UserID: 1, Role: domain.Usr}, nil)
+				uCase.On("Logout", "session_token", "remember").Return(nil)
 			},
 			status: http.StatusNoContent,
 		},
@@ -292,7 +325,7 @@ func TestLogout(t *testing.T) {
 						UserID: 0,
 						Role:   domain.Usr,
 					}, domain.ErrUnauthorized)
-				uCase.On("Logout", "session_token").Return(nil).Maybe()
+				uCase.On("Logout", "session_token", "").Return(nil).Maybe()
 
 			},
 			status: http.StatusUnauthorized,
@@ -307,10 +340,13 @@ func TestLogout(t *testing.T) {
 
 			req := httptest.NewRequest("POST", "/logout", nil)
 			req.AddCookie(&http.Cookie{Name: "session_token", Value: session.Token})
+			if test.rememberToken != "" {
+				req.AddCookie(&http.Cookie{Name: "remember_token", Value: test.rememberToken})
+			}
 			rec := httptest.NewRecorder()
 
 			mux := http.NewServeMux()
-			auth_http.NewAuthHandler(mux, mockUsecase)
+			auth_http.NewAuthHandler(mux, mockUsecase, allowingGuard())
 
 			mux.ServeHTTP(rec, req)
 
@@ -320,6 +356,60 @@ func TestLogout(t *testing.T) {
 	}
 }
 
+func TestCSRFToken(t *testing.T) {
+	tests := []struct {
+		name      string
+		cookie    string
+		wantToken string
+		wantIssue bool
+	}{
+		{
+			name:      "GoodCase/Existing",
+			cookie:    "csrf",
+			wantToken: "csrf",
+		},
+		{
+			name:      "GoodCase/Issued",
+			wantIssue: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/csrf", nil)
+			if test.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "csrf_token", Value: test.cookie})
+			}
+			rec := httptest.NewRecorder()
+
+			mux := http.NewServeMux()
+			auth_http.NewAuthHandler(mux, new(mocks.AuthUsecase), allowingGuard())
+			mux.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			var resp struct {
+				Body struct {
+					CSRFToken string `json:"csrfToken"`
+				} `json:"body"`
+			}
+			assert.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+
+			cookies := rec.Result().Cookies()
+			if test.wantIssue {
+				assert.Len(t, cookies, 1)
+				assert.Equal(t, "csrf_token", cookies[0].Name)
+				assert.Equal(t, cookies[0].Value, resp.Body.CSRFToken)
+				assert.Equal(t, http.SameSiteStrictMode, cookies[0].SameSite)
+				assert.True(t, cookies[0].HttpOnly)
+				return
+			}
+			assert.Empty(t, cookies)
+			assert.Equal(t, test.wantToken, resp.Body.CSRFToken)
+		})
+	}
+}
+
 func TestRegister(t *testing.T) {
 	tests := []struct {
 		name                 string
@@ -346,7 +436,7 @@ func TestRegister(t *testing.T) {
 				assert.NoError(t, err)
 				session.ExpiresAt = time.Now().Add(24 * time.Hour)
 				session.UserID = 1
-				uCase.On("Login", mock.Anything).Return(*session, 1, nil)
+				uCase.On("Login", mock.Anything, mock.Anything).Return(*session, 1, nil)
 			},
 			status: http.StatusOK,
 		},
@@ -364,7 +454,7 @@ func TestRegister(t *testing.T) {
 
 				err := faker.FakeData(session)
 				assert.NoError(t, err)
-				uCase.On("Login", mock.Anything).Return(*session, 1, nil).Maybe()
+				uCase.On("Login", mock.Anything, mock.Anything).Return(*session, 1, nil).Maybe()
 			},
 			status: http.StatusConflict,
 		},
@@ -375,7 +465,7 @@ func TestRegister(t *testing.T) {
 			},
 			setUCaseExpectations: func(uCase *mocks.AuthUsecase, session *domain.Session) {
 				uCase.On("Register", mock.Anything).Return(0, nil).Maybe()
-				uCase.On("Login", mock.Anything).Return(*session, 1, domain.ErrWrongCredentials).Maybe()
+				uCase.On("Login", mock.Anything, mock.Anything).Return(*session, 1, domain.ErrWrongCredentials).Maybe()
 			},
 			status: http.StatusBadRequest,
 		},
@@ -386,7 +476,7 @@ func TestRegister(t *testing.T) {
 			},
 			setUCaseExpectations: func(uCase *mocks.AuthUsecase, session *domain.Session) {
 				uCase.On("Register", mock.Anything).Return(0, nil).Maybe()
-				uCase.On("Login", mock.Anything).Return(*session, 1, domain.ErrBadRequest).Maybe()
+				uCase.On("Login", mock.Anything, mock.Anything).Return(*session, 1, domain.ErrBadRequest).Maybe()
 			},
 			status: http.StatusBadRequest,
 		},
@@ -397,7 +487,7 @@ func TestRegister(t *testing.T) {
 			},
 			setUCaseExpectations: func(uCase *mocks.AuthUsecase, session *domain.Session) {
 				uCase.On("Register", mock.Anything).Return(0, nil).Maybe()
-				uCase.On("Login", mock.Anything).Return(*session, 1, domain.ErrBadRequest).Maybe()
+				uCase.On("Login", mock.Anything, mock.Anything).Return(*session, 1, domain.ErrBadRequest).Maybe()
 			},
 			status: http.StatusBadRequest,
 		},
@@ -418,7 +508,7 @@ func TestRegister(t *testing.T) {
 				session.Role = domain.Usr
 
 				assert.NoError(t, err)
-				uCase.On("Login", mock.Anything).Return(*session, 0, nil).Maybe()
+				uCase.On("Login", mock.Anything, mock.Anything).Return(*session, 0, nil).Maybe()
 			},
 			status: http.StatusConflict,
 			auth:   true,
@@ -453,7 +543,7 @@ func TestRegister(t *testing.T) {
 				session.Role = domain.Usr
 
 				assert.NoError(t, err)
-				uCase.On("Login", mock.Anything).Return(*session, 1, nil)
+				uCase.On("Login", mock.Anything, mock.Anything).Return(*session, 1, nil)
 			},
 			status: http.StatusOK,
 			auth:   true,
@@ -486,7 +576,7 @@ func TestRegister(t *testing.T) {
 				err := faker.FakeData(session)
 				session.ExpiresAt = time.Now().Add(24 * time.Hour)
 				assert.NoError(t, err)
-				uCase.On("Login", mock.Anything).Return(*session, 1, nil)
+				uCase.On("Login", mock.Anything, mock.Anything).Return(*session, 1, nil)
 			},
 			status: http.StatusOK,
 			auth:   true,
@@ -518,6 +608,7 @@ func TestRegister(t *testing.T) {
 			rec := httptest.NewRecorder()
 			handler := &auth_http.AuthHandler{
 				AuthUsecase: mockUCase,
+				LoginGuard:  allowingGuard(),
 			}
 
 			handler.Register(rec, req)
@@ -0,0 +1,37 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+)
+
+// allowAttempt writes 429 with Retry-After if the email or the ip
+// has failed too many times.
+func allowAttempt(w http.ResponseWriter, g domain.LoginGuard, email, ip, funcName string) bool {
+	wait, err := g.Check(email, ip)
+	if err != nil {
+		domain.SetRetryAfter(w, wait)
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "auth_http", funcName, err, "attempt is throttled")
+		return false
+	}
+
+	return true
+}
+
+// reportAttempt counts a failure or resets the counter on success. It
+// has to be called before the response is written, as it may set
+// Retry-After.
+func reportAttempt(w http.ResponseWriter, g domain.LoginGuard, email, ip string, err error) {
+	switch {
+	case err == nil:
+		g.Succeed(email, ip)
+	case errors.Is(err, domain.ErrWrongCredentials), errors.Is(err, domain.ErrNotFound),
+		errors.Is(err, domain.ErrAlreadyExists):
+		wait, _ := g.Fail(email, ip)
+		domain.SetRetryAfter(w, wait)
+	}
+}
@@ -0,0 +1,217 @@
+package http_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	auth_http "github.com/ellexo2456/FilmLib/internal/auth/delivery/http"
+	"github.com/ellexo2456/FilmLib/internal/auth/usecase"
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	"github.com/ellexo2456/FilmLib/internal/domain/mocks"
+)
+
+func allowingGuard() *mocks.LoginGuard {
+	g := new(mocks.LoginGuard)
+	g.On("Check", mock.Anything, mock.Anything).Return(time.Duration(0), nil).Maybe()
+	g.On("Fail", mock.Anything, mock.Anything).Return(time.Duration(0), nil).Maybe()
+	g.On("Succeed", mock.Anything, mock.Anything).Return(nil).Maybe()
+	return g
+}
+
+func TestLoginThrottling(t *testing.T) {
+	body := `{"email": "uvybini@mail.ru", "password": "MTIz"}`
+
+	tests := []struct {
+		name                 string
+		setGuardExpectations func(g *mocks.LoginGuard)
+		setUCaseExpectations func(uCase *mocks.AuthUsecase)
+		status               int
+		retryAfter           string
+	}{
+		{
+			name: "GoodCase/Succeed",
+			setGuardExpectations: func(g *mocks.LoginGuard) {
+				g.On("Check", "uvybini@mail.ru", "192.0.2.1").Return(time.Duration(0), nil)
+				g.On("Succeed", "uvybini@mail.ru", "192.0.2.1").Return(nil)
+			},
+			setUCaseExpectations: func(uCase *mocks.AuthUsecase) {
+				uCase.On("Login", mock.Anything, mock.Anything).Return(domain.Session{Token: "s"}, 1, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name: "BadCase/Throttled",
+			setGuardExpectations: func(g *mocks.LoginGuard) {
+				g.On("Check", "uvybini@mail.ru", "192.0.2.1").Return(1500*time.Millisecond, domain.ErrTooManyRequests)
+			},
+			setUCaseExpectations: func(uCase *mocks.AuthUsecase) {},
+			status:               http.StatusTooManyRequests,
+			retryAfter:           "2",
+		},
+		{
+			name: "BadCase/WrongPassword",
+			setGuardExpectations: func(g *mocks.LoginGuard) {
+				g.On("Check", "uvybini@mail.ru", "192.0.2.1").Return(time.Duration(0), nil)
+				g.On("Fail", "uvybini@mail.ru", "192.0.2.1").Return(4*time.Second, nil)
+			},
+			setUCaseExpectations: func(uCase *mocks.AuthUsecase) {
+				uCase.On("Login", mock.Anything, mock.Anything).Return(domain.Session{}, 0, domain.ErrWrongCredentials)
+			},
+			status:     http.StatusBadRequest,
+			retryAfter: "4",
+		},
+		{
+			name: "BadCase/ServerError",
+			setGuardExpectations: func(g *mocks.LoginGuard) {
+				g.On("Check", "uvybini@mail.ru", "192.0.2.1").Return(time.Duration(0), nil)
+			},
+			setUCaseExpectations: func(uCase *mocks.AuthUsecase) {
+				uCase.On("Login", mock.Anything, mock.Anything).Return(domain.Session{}, 0, domain.ErrInternalServerError)
+			},
+			status: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockUsecase := new(mocks.AuthUsecase)
+			mockGuard := new(mocks.LoginGuard)
+			test.setUCaseExpectations(mockUsecase)
+			test.setGuardExpectations(mockGuard)
+
+			req := httptest.NewRequest("POST", "/api/v1/auth/login", bytes.NewReader([]byte(body)))
+			req.RemoteAddr = "192.0.2.1:5000"
+			rec := httptest.NewRecorder()
+
+			handler := &auth_http.AuthHandler{AuthUsecase: mockUsecase, LoginGuard: mockGuard}
+			handler.Login(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			assert.Equal(t, test.retryAfter, rec.Header().Get("Retry-After"))
+			mockUsecase.AssertExpectations(t)
+			mockGuard.AssertExpectations(t)
+		})
+	}
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// memoryAttempts keeps the counters like redis does, without the ttl.
+type memoryAttempts map[string]domain.Attempts
+
+func (m memoryAttempts) Get(key string) (domain.Attempts, error) {
+	return m[key], nil
+}
+
+func (m memoryAttempts) Fail(key string, at time.Time, ttl time.Duration) (domain.Attempts, error) {
+	a := m[key]
+	a.Failures++
+	a.LastFailure = at
+	m[key] = a
+	return a, nil
+}
+
+func (m memoryAttempts) Reset(key string) error {
+	delete(m, key)
+	return nil
+}
+
+// TestTwoFactorThrottling guesses the code of a user whose password is
+// known, logging in anew for every guess.
+func TestTwoFactorThrottling(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)}
+	guard := usecase.NewLoginGuard(memoryAttempts{},
+		domain.ThrottlePolicy{
+			FreeAttempts:    2,
+			BaseDelay:       time.Second,
+			MaxDelay:        4 * time.Second,
+			LockoutAfter:    3,
+			LockoutDuration: time.Minute,
+			Window:          time.Hour,
+		},
+		domain.ThrottlePolicy{FreeAttempts: 100, LockoutAfter: 1000, Window: time.Hour},
+		clock)
+
+	authUsecase := new(mocks.AuthUsecase)
+	authUsecase.On("Login", mock.Anything, mock.Anything).Return(domain.Session{Token: "mfa", MFARequired: true}, 1, nil)
+	twoFactorUsecase := new(mocks.TwoFactorUsecase)
+	twoFactorUsecase.On("ChallengeEmail", "mfa").Return("uvybini@mail.ru", nil)
+	twoFactorUsecase.On("CompleteLogin", "mfa", "000000", false, mock.Anything).Return(domain.Session{}, nil, domain.ErrWrongCredentials)
+
+	authHandler := &auth_http.AuthHandler{AuthUsecase: authUsecase, LoginGuard: guard}
+	twoFactorHandler := &auth_http.TwoFactorHandler{TwoFactorUsecase: twoFactorUsecase, LoginGuard: guard}
+
+	login := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/v1/auth/login",
+			bytes.NewReader([]byte(`{"email": "uvybini@mail.ru", "password": "MTIz"}`)))
+		req.RemoteAddr = "192.0.2.1:5000"
+		rec := httptest.NewRecorder()
+		authHandler.Login(rec, req)
+		return rec
+	}
+	guess := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/v1/auth/login/2fa",
+			bytes.NewReader([]byte(`{"mfaToken": "mfa", "code": "000000"}`)))
+		req.RemoteAddr = "192.0.2.1:5000"
+		rec := httptest.NewRecorder()
+		twoFactorHandler.CompleteLogin(rec, req)
+		return rec
+	}
+
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, http.StatusOK, login().Code)
+		assert.Equal(t, http.StatusBadRequest, guess().Code)
+	}
+
+	// the right password doesn`t reset the failed codes
+	rec := login()
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+
+	clock.Advance(time.Second)
+	assert.Equal(t, http.StatusOK, login().Code)
+	rec = guess()
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "60", rec.Header().Get("Retry-After"))
+
+	clock.Advance(30 * time.Second)
+	assert.Equal(t, http.StatusTooManyRequests, login().Code)
+	assert.Equal(t, http.StatusTooManyRequests, guess().Code)
+	twoFactorUsecase.AssertNumberOfCalls(t, "CompleteLogin", 3)
+}
+
+func TestRegisterThrottling(t *testing.T) {
+	mockUsecase := new(mocks.AuthUsecase)
+	mockGuard := new(mocks.LoginGuard)
+	mockGuard.On("Check", "", "192.0.2.1").Return(time.Duration(0), nil)
+	mockGuard.On("Fail", "", "192.0.2.1").Return(time.Second, nil)
+	mockUsecase.On("Register", mock.Anything).Return(0, domain.ErrAlreadyExists)
+
+	req := httptest.NewRequest("POST", "/api/v1/auth/register",
+		bytes.NewReader([]byte(`{"email": "uvybini@mail.ru", "password": "MTIz"}`)))
+	req.RemoteAddr = "192.0.2.1:5000"
+	rec := httptest.NewRecorder()
+
+	handler := &auth_http.AuthHandler{AuthUsecase: mockUsecase, LoginGuard: mockGuard}
+	handler.Register(rec, req)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+	mockGuard.AssertExpectations(t)
+}
@@ -0,0 +1,98 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+)
+
+// oidcStateCookie binds the login to the browser which started it,
+// so a callback url can`t be used to log someone into another account.
+const oidcStateCookie = "oidc_state"
+
+type OIDCHandler struct {
+	OIDCUsecase domain.OIDCUsecase
+}
+
+func NewOIDCHandler(mux *http.ServeMux, u domain.OIDCUsecase) {
+	handler := &OIDCHandler{
+		OIDCUsecase: u,
+	}
+
+	mux.HandleFunc("GET /oidc/{provider}/login", handler.Begin)
+	mux.HandleFunc("GET /oidc/{provider}/callback", handler.Callback)
+}
+
+// Begin godoc
+//
+//	@Summary		login with external provider
+//	@Description	redirect to the OpenID Connect provider to log in
+//	@Tags			Auth
+//	@Param			provider	path	string	true	"provider name"
+//	@Success		302
+//	@Failure		404	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/auth/oidc/{provider}/login [get]
+func (h *OIDCHandler) Begin(w http.ResponseWriter, r *http.Request) {
+	url, state, err := h.OIDCUsecase.Begin(r.PathValue("provider"))
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "auth_http", "OIDCBegin", err, "Failed to begin login")
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    state,
+		MaxAge:   600,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   domain.SecureCookies,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, url, http.StatusFound)
+}
+
+// Callback godoc
+//
+//	@Summary		external provider callback
+//	@Description	finish the login with the provider, link the identity to the account with the same verified email or create a new one, and put the session into cookie
+//	@Tags			Auth
+//	@Param			provider	path		string	true	"provider name"
+//	@Param			state		query		string	true	"state"
+//	@Param			code		query		string	true	"authorization code"
+//	@Success		200			{object}	object{body=object{id=int,mfaRequired=bool,mfaToken=string}}
+//	@Failure		400			{object}	object{err=string}
+//	@Failure		401			{object}	object{err=string}
+//	@Failure		403			{object}	object{err=string}
+//	@Failure		404			{object}	object{err=string}
+//	@Failure		500			{object}	object{err=string}
+//	@Router			/api/v1/auth/oidc/{provider}/callback [get]
+func (h *OIDCHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if e := q.Get("error"); e != "" {
+		domain.WriteError(w, e, http.StatusUnauthorized)
+		logs.LogError(logs.Logger, "auth_http", "OIDCCallback", errors.New(e), "provider refused the login")
+		return
+	}
+
+	state := q.Get("state")
+	c, err := r.Cookie(oidcStateCookie)
+	if err != nil || state == "" || c.Value != state {
+		domain.WriteError(w, domain.ErrInvalidToken.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "auth_http", "OIDCCallback", domain.ErrInvalidToken, "state doesn`t match")
+		return
+	}
+	http.SetCookie(w, &http.Cookie{Name: oidcStateCookie, Path: "/", MaxAge: -1})
+
+	session, err := h.OIDCUsecase.Complete(r.PathValue("provider"), state, q.Get("code"), domain.RequestClient(r))
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "auth_http", "OIDCCallback", err, "Failed to login")
+		return
+	}
+
+	writeLogin(w, session, session.UserID)
+}
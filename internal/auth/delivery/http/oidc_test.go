@@ -0,0 +1,131 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	auth_http "github.com/ellexo2456/FilmLib/internal/auth/delivery/http"
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	"github.com/ellexo2456/FilmLib/internal/domain/mocks"
+)
+
+func TestOIDCBegin(t *testing.T) {
+	tests := []struct {
+		name                 string
+		provider             string
+		setUCaseExpectations func(uCase *mocks.OIDCUsecase)
+		status               int
+	}{
+		{
+			name:     "GoodCase/Common",
+			provider: "fake",
+			setUCaseExpectations: func(uCase *mocks.OIDCUsecase) {
+				uCase.On("Begin", "fake").Return("https://provider/authorize?state=s", "s", nil)
+			},
+			status: http.StatusFound,
+		},
+		{
+			name:     "BadCase/UnknownProvider",
+			provider: "other",
+			setUCaseExpectations: func(uCase *mocks.OIDCUsecase) {
+				uCase.On("Begin", "other").Return("", "", domain.ErrNotFound)
+			},
+			status: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockUsecase := new(mocks.OIDCUsecase)
+			test.setUCaseExpectations(mockUsecase)
+
+			mux := http.NewServeMux()
+			auth_http.NewOIDCHandler(mux, mockUsecase)
+			req := httptest.NewRequest("GET", "/oidc/"+test.provider+"/login", nil)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			if test.status == http.StatusFound {
+				assert.Equal(t, "https://provider/authorize?state=s", rec.Header().Get("Location"))
+				assert.Contains(t, rec.Header().Get("Set-Cookie"), "oidc_state=s")
+				assert.Contains(t, rec.Header().Get("Set-Cookie"), "Secure")
+			}
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOIDCCallback(t *testing.T) {
+	tests := []struct {
+		name                 string
+		query                string
+		cookie               string
+		setUCaseExpectations func(uCase *mocks.OIDCUsecase)
+		status               int
+	}{
+		{
+			name:   "GoodCase/Common",
+			query:  "?state=s&code=c",
+			cookie: "s",
+			setUCaseExpectations: func(uCase *mocks.OIDCUsecase) {
+				uCase.On("Complete", "fake", "s", "c", mock.Anything).
+					Return(domain.Session{Token: "t", UserID: 5, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:                 "BadCase/NoStateCookie",
+			query:                "?state=s&code=c",
+			setUCaseExpectations: func(uCase *mocks.OIDCUsecase) {},
+			status:               http.StatusBadRequest,
+		},
+		{
+			name:                 "BadCase/OtherState",
+			query:                "?state=s&code=c",
+			cookie:               "other",
+			setUCaseExpectations: func(uCase *mocks.OIDCUsecase) {},
+			status:               http.StatusBadRequest,
+		},
+		{
+			name:                 "BadCase/ProviderError",
+			query:                "?error=access_denied&state=s",
+			cookie:               "s",
+			setUCaseExpectations: func(uCase *mocks.OIDCUsecase) {},
+			status:               http.StatusUnauthorized,
+		},
+		{
+			name:   "BadCase/NotVerified",
+			query:  "?state=s&code=c",
+			cookie: "s",
+			setUCaseExpectations: func(uCase *mocks.OIDCUsecase) {
+				uCase.On("Complete", "fake", "s", "c", mock.Anything).Return(domain.Session{}, domain.ErrNotVerified)
+			},
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockUsecase := new(mocks.OIDCUsecase)
+			test.setUCaseExpectations(mockUsecase)
+
+			mux := http.NewServeMux()
+			auth_http.NewOIDCHandler(mux, mockUsecase)
+			req := httptest.NewRequest("GET", "/oidc/fake/callback"+test.query, nil)
+			if test.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "oidc_state", Value: test.cookie})
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+)
+
+type PasswordHandler struct {
+	PasswordUsecase domain.PasswordUsecase
+}
+
+func NewPasswordHandler(mux *http.ServeMux, u domain.PasswordUsecase) {
+	handler := &PasswordHandler{
+		PasswordUsecase: u,
+	}
+
+	mux.HandleFunc("POST /password/forgot", handler.Forgot)
+	mux.HandleFunc("POST /password/reset", handler.Reset)
+}
+
+// Forgot godoc
+//
+//	@Summary		request password reset
+//	@Description	send a single-use reset link to the email. Responds the same way whether the email is registered or not
+//	@Tags			Auth
+//	@Accept			json
+//	@Param			body	body	domain.EmailRequest	true	"user email"
+//	@Success		204
+//	@Failure		400	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/auth/password/forgot [post]
+func (h *PasswordHandler) Forgot(w http.ResponseWriter, r *http.Request) {
+	var req domain.EmailRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "auth_http", "Forgot", err, "Failed to decode json from body")
+		return
+	}
+	defer domain.CloseAndAlert(r.Body, "auth/http", "Forgot")
+
+	req.Email = strings.TrimSpace(req.Email)
+	if !valid(req.Email) {
+		domain.WriteError(w, domain.ErrBadRequest.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "auth_http", "Forgot", domain.ErrBadRequest, "email is invalid")
+		return
+	}
+
+	if err = h.PasswordUsecase.RequestReset(req.Email); err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "auth_http", "Forgot", err, "Failed to request reset")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Reset godoc
+//
+//	@Summary		reset password
+//	@Description	set a new password using the token from the reset link. The token can be used only once, all sessions, refresh, remember-me and API tokens of the user are revoked
+//	@Tags			Auth
+//	@Accept			json
+//	@Param			body	body	domain.PasswordReset	true	"reset token and new password"
+//	@Success		204
+//	@Failure		400	{object}	object{err=string}
+//	@Failure		404	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/auth/password/reset [post]
+func (h *PasswordHandler) Reset(w http.ResponseWriter, r *http.Request) {
+	var reset domain.PasswordReset
+	err := json.NewDecoder(r.Body).Decode(&reset)
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "auth_http", "Reset", err, "Failed to decode json from body")
+		return
+	}
+	defer domain.CloseAndAlert(r.Body, "auth/http", "Reset")
+
+	if err = h.PasswordUsecase.Reset(reset); err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "auth_http", "Reset", err, "Failed to reset password")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
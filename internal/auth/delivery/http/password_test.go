@@ -0,0 +1,117 @@
+package http_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	auth_http "github.com/ellexo2456/FilmLib/internal/auth/delivery/http"
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	"github.com/ellexo2456/FilmLib/internal/domain/mocks"
+)
+
+func TestForgot(t *testing.T) {
+	tests := []struct {
+		name                 string
+		body                 string
+		setUCaseExpectations func(uCase *mocks.PasswordUsecase)
+		status               int
+	}{
+		{
+			name: "GoodCase/Common",
+			body: `{"email": " uvybini@mail.ru "}`,
+			setUCaseExpectations: func(uCase *mocks.PasswordUsecase) {
+				uCase.On("RequestReset", "uvybini@mail.ru").Return(nil)
+			},
+			status: http.StatusNoContent,
+		},
+		{
+			name:                 "BadCase/InvalidEmail",
+			body:                 `{"email": "not an email"}`,
+			setUCaseExpectations: func(uCase *mocks.PasswordUsecase) {},
+			status:               http.StatusBadRequest,
+		},
+		{
+			name:                 "BadCase/InvalidJson",
+			body:                 `{"email": `,
+			setUCaseExpectations: func(uCase *mocks.PasswordUsecase) {},
+			status:               http.StatusBadRequest,
+		},
+		{
+			name: "BadCase/UsecaseError",
+			body: `{"email": "uvybini@mail.ru"}`,
+			setUCaseExpectations: func(uCase *mocks.PasswordUsecase) {
+				uCase.On("RequestReset", mock.Anything).Return(domain.ErrInternalServerError)
+			},
+			status: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockUsecase := new(mocks.PasswordUsecase)
+			test.setUCaseExpectations(mockUsecase)
+
+			req := httptest.NewRequest("POST", "/api/v1/auth/password/forgot", bytes.NewReader([]byte(test.body)))
+			rec := httptest.NewRecorder()
+
+			handler := &auth_http.PasswordHandler{PasswordUsecase: mockUsecase}
+			handler.Forgot(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReset(t *testing.T) {
+	tests := []struct {
+		name                 string
+		body                 string
+		setUCaseExpectations func(uCase *mocks.PasswordUsecase)
+		status               int
+	}{
+		{
+			name: "GoodCase/Common",
+			body: `{"token": "abc", "password": "cGFzc3dvcmQ="}`,
+			setUCaseExpectations: func(uCase *mocks.PasswordUsecase) {
+				uCase.On("Reset", domain.PasswordReset{Token: "abc", Password: []byte("password")}).Return(nil)
+			},
+			status: http.StatusNoContent,
+		},
+		{
+			name: "BadCase/InvalidToken",
+			body: `{"token": "abc", "password": "cGFzc3dvcmQ="}`,
+			setUCaseExpectations: func(uCase *mocks.PasswordUsecase) {
+				uCase.On("Reset", mock.Anything).Return(domain.ErrInvalidToken)
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:                 "BadCase/InvalidJson",
+			body:                 `token`,
+			setUCaseExpectations: func(uCase *mocks.PasswordUsecase) {},
+			status:               http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockUsecase := new(mocks.PasswordUsecase)
+			test.setUCaseExpectations(mockUsecase)
+
+			req := httptest.NewRequest("POST", "/api/v1/auth/password/reset", bytes.NewReader([]byte(test.body)))
+			rec := httptest.NewRecorder()
+
+			handler := &auth_http.PasswordHandler{PasswordUsecase: mockUsecase}
+			handler.Reset(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
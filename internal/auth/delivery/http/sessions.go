@@ -0,0 +1,129 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+)
+
+type SessionsHandler struct {
+	SessionsUsecase domain.SessionsUsecase
+}
+
+// NewSessionsHandler registers the sessions of the current user.
+func NewSessionsHandler(mux *http.ServeMux, u domain.SessionsUsecase) {
+	handler := &SessionsHandler{
+		SessionsUsecase: u,
+	}
+
+	mux.HandleFunc("GET /me/sessions", handler.GetSessions)
+	mux.HandleFunc("DELETE /me/sessions", handler.RevokeAll)
+	mux.HandleFunc("DELETE /me/sessions/{id}", handler.Revoke)
+}
+
+// GetSessions godoc
+//
+//	@Summary		get sessions
+//	@Description	get the active sessions of the current user with the user agent, IP, creation and last seen time. The session of the request is marked as current. Can`t be called with an API token
+//	@Tags			Auth
+//	@Produce		json
+//	@Success		200	{object}	object{body=object{sessions=[]domain.SessionInfo}}
+//	@Failure		401	{object}	object{err=string}
+//	@Failure		403	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/me/sessions [get]
+func (h *SessionsHandler) GetSessions(w http.ResponseWriter, r *http.Request) {
+	sc, ok := sessionOnly(w, r, "GetSessions")
+	if !ok {
+		return
+	}
+
+	sessions, err := h.SessionsUsecase.GetAll(sc.UserID, currentSessionToken(r))
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "auth_http", "GetSessions", err, err.Error())
+		return
+	}
+
+	domain.WriteResponse(
+		w,
+		map[string]interface{}{
+			"sessions": sessions,
+		},
+		http.StatusOK,
+	)
+}
+
+// Revoke godoc
+//
+//	@Summary		revoke session
+//	@Description	log out the session with the id. Revoking the current session also nullifies the cookie. Can`t be called with an API token
+//	@Tags			Auth
+//	@Param			id	path	string	true	"session id"
+//	@Success		204
+//	@Failure		401	{object}	object{err=string}
+//	@Failure		403	{object}	object{err=string}
+//	@Failure		404	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/me/sessions/{id} [delete]
+func (h *SessionsHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	sc, ok := sessionOnly(w, r, "Revoke")
+	if !ok {
+		return
+	}
+
+	id := r.PathValue("id")
+	if err := h.SessionsUsecase.Revoke(sc.UserID, id); err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "auth_http", "Revoke", err, err.Error())
+		return
+	}
+
+	if token := currentSessionToken(r); token != "" && domain.SessionID(token) == id {
+		domain.ClearCookie(w, domain.SessionCookie)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RevokeAll godoc
+//
+//	@Summary		log out everywhere
+//	@Description	delete all sessions, refresh and remember-me tokens of the current user, including the current one. Can`t be called with an API token
+//	@Tags			Auth
+//	@Success		204
+//	@Failure		401	{object}	object{err=string}
+//	@Failure		403	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/me/sessions [delete]
+func (h *SessionsHandler) RevokeAll(w http.ResponseWriter, r *http.Request) {
+	sc, ok := sessionOnly(w, r, "RevokeAll")
+	if !ok {
+		return
+	}
+
+	if err := h.SessionsUsecase.RevokeAll(sc.UserID); err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "auth_http", "RevokeAll", err, err.Error())
+		return
+	}
+
+	domain.ClearCookie(w, domain.SessionCookie)
+	domain.ClearCookie(w, domain.RememberCookie)
+	domain.ClearCookie(w, domain.CSRFCookie)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func currentSessionToken(r *http.Request) string {
+	return cookieValue(r, domain.SessionCookie)
+}
+
+func cookieValue(r *http.Request, name string) string {
+	c, err := r.Cookie(name)
+	if err != nil {
+		return ""
+	}
+
+	return c.Value
+}
@@ -0,0 +1,113 @@
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	auth_http "github.com/ellexo2456/FilmLib/internal/auth/delivery/http"
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	"github.com/ellexo2456/FilmLib/internal/domain/mocks"
+)
+
+func TestSessionsHandler(t *testing.T) {
+	tests := []struct {
+		name                 string
+		method               string
+		target               string
+		sc                   domain.SessionContext
+		setUCaseExpectations func(uCase *mocks.SessionsUsecase)
+		status               int
+		cookieCleared        bool
+	}{
+		{
+			name:   "GoodCase/GetSessions",
+			method: "GET",
+			target: "/me/sessions",
+			sc:     domain.SessionContext{UserID: 1},
+			setUCaseExpectations: func(uCase *mocks.SessionsUsecase) {
+				uCase.On("GetAll", 1, "current").Return([]domain.SessionInfo{{ID: domain.SessionID("current"), Current: true}}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:                 "BadCase/GetSessionsWithAPIToken",
+			method:               "GET",
+			target:               "/me/sessions",
+			sc:                   domain.SessionContext{UserID: 1, TokenID: 3},
+			setUCaseExpectations: func(uCase *mocks.SessionsUsecase) {},
+			status:               http.StatusForbidden,
+		},
+		{
+			name:   "GoodCase/RevokeOther",
+			method: "DELETE",
+			target: "/me/sessions/" + domain.SessionID("other"),
+			sc:     domain.SessionContext{UserID: 1},
+			setUCaseExpectations: func(uCase *mocks.SessionsUsecase) {
+				uCase.On("Revoke", 1, domain.SessionID("other")).Return(nil)
+			},
+			status: http.StatusNoContent,
+		},
+		{
+			name:   "GoodCase/RevokeCurrent",
+			method: "DELETE",
+			target: "/me/sessions/" + domain.SessionID("current"),
+			sc:     domain.SessionContext{UserID: 1},
+			setUCaseExpectations: func(uCase *mocks.SessionsUsecase) {
+				uCase.On("Revoke", 1, domain.SessionID("current")).Return(nil)
+			},
+			status:        http.StatusNoContent,
+			cookieCleared: true,
+		},
+		{
+			name:   "BadCase/RevokeUnknown",
+			method: "DELETE",
+			target: "/me/sessions/unknown",
+			sc:     domain.SessionContext{UserID: 1},
+			setUCaseExpectations: func(uCase *mocks.SessionsUsecase) {
+				uCase.On("Revoke", 1, "unknown").Return(domain.ErrNotFound)
+			},
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "GoodCase/RevokeAll",
+			method: "DELETE",
+			target: "/me/sessions",
+			sc:     domain.SessionContext{UserID: 1},
+			setUCaseExpectations: func(uCase *mocks.SessionsUsecase) {
+				uCase.On("RevokeAll", 1).Return(nil)
+			},
+			status:        http.StatusNoContent,
+			cookieCleared: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockUsecase := new(mocks.SessionsUsecase)
+			test.setUCaseExpectations(mockUsecase)
+
+			mux := http.NewServeMux()
+			auth_http.NewSessionsHandler(mux, mockUsecase)
+
+			req := httptest.NewRequest(test.method, test.target, nil)
+			req.AddCookie(&http.Cookie{Name: "session_token", Value: "current"})
+			req = req.WithContext(context.WithValue(context.Background(), domain.SessionContextKey, test.sc))
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			cleared := false
+			for _, c := range rec.Result().Cookies() {
+				if c.Name == "session_token" && c.Value == "" {
+					cleared = true
+				}
+			}
+			assert.Equal(t, test.cookieCleared, cleared)
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+)
+
+type TokenHandler struct {
+	TokenAuthUsecase domain.TokenAuthUsecase
+	LoginGuard       domain.LoginGuard
+}
+
+func NewTokenHandler(mux *http.ServeMux, u domain.TokenAuthUsecase, g domain.LoginGuard) {
+	handler := &TokenHandler{
+		TokenAuthUsecase: u,
+		LoginGuard:       g,
+	}
+
+	mux.HandleFunc("POST /token", handler.Login)
+	mux.HandleFunc("POST /token/refresh", handler.Refresh)
+	mux.HandleFunc("POST /token/revoke", handler.Revoke)
+}
+
+// Login godoc
+//
+//	@Summary		get access token
+//	@Description	issue a signed access token and a refresh token. Only available in the jwt auth mode. The access token is sent as "Authorization: Bearer <token>"
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		domain.Credentials	true	"user credentials"
+//	@Success		200		{object}	object{body=domain.TokenPair}
+//	@Failure		400		{object}	object{err=string}
+//	@Failure		403		{object}	object{err=string}
+//	@Failure		404		{object}	object{err=string}
+//	@Failure		429		{object}	object{err=string}
+//	@Failure		500		{object}	object{err=string}
+//	@Router			/api/v1/auth/token [post]
+func (h *TokenHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var credentials domain.Credentials
+	err := json.NewDecoder(r.Body).Decode(&credentials)
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "auth_http", "TokenLogin", err, "Failed to decode json from body")
+		return
+	}
+	defer domain.CloseAndAlert(r.Body, "auth/http", "TokenLogin")
+
+	credentials.Email = strings.TrimSpace(credentials.Email)
+	if err = checkCredentials(credentials); err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "auth_http", "TokenLogin", err, "Credentials are incorrect")
+		return
+	}
+
+	ip := domain.ClientIP(r)
+	if !allowAttempt(w, h.LoginGuard, credentials.Email, ip, "TokenLogin") {
+		return
+	}
+
+	pair, err := h.TokenAuthUsecase.Login(credentials)
+	reportAttempt(w, h.LoginGuard, credentials.Email, ip, err)
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "auth_http", "TokenLogin", err, "Failed to login")
+		return
+	}
+
+	writeTokenPair(w, pair)
+}
+
+// Refresh godoc
+//
+//	@Summary		refresh access token
+//	@Description	exchange a refresh token for a new token pair. Every refresh token can be used only once
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		domain.RefreshRequest	true	"refresh token"
+//	@Success		200		{object}	object{body=domain.TokenPair}
+//	@Failure		400		{object}	object{err=string}
+//	@Failure		403		{object}	object{err=string}
+//	@Failure		500		{object}	object{err=string}
+//	@Router			/api/v1/auth/token/refresh [post]
+func (h *TokenHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	token, ok := decodeRefreshToken(w, r, "Refresh")
+	if !ok {
+		return
+	}
+
+	pair, err := h.TokenAuthUsecase.Refresh(token)
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "auth_http", "Refresh", err, "Failed to refresh token")
+		return
+	}
+
+	writeTokenPair(w, pair)
+}
+
+// Revoke godoc
+//
+//	@Summary		revoke refresh token
+//	@Description	revoke a refresh token. Access tokens issued with it stay valid until they expire
+//	@Tags			Auth
+//	@Accept			json
+//	@Param			body	body	domain.RefreshRequest	true	"refresh token"
+//	@Success		204
+//	@Failure		400	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/auth/token/revoke [post]
+func (h *TokenHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	token, ok := decodeRefreshToken(w, r, "Revoke")
+	if !ok {
+		return
+	}
+
+	if err := h.TokenAuthUsecase.Revoke(token); err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "auth_http", "Revoke", err, "Failed to revoke token")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func decodeRefreshToken(w http.ResponseWriter, r *http.Request, funcName string) (string, bool) {
+	var req domain.RefreshRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "auth_http", funcName, err, "Failed to decode json from body")
+		return "", false
+	}
+	defer domain.CloseAndAlert(r.Body, "auth/http", funcName)
+
+	if req.RefreshToken == "" {
+		domain.WriteError(w, domain.ErrInvalidToken.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "auth_http", funcName, domain.ErrInvalidToken, "token is empty")
+		return "", false
+	}
+
+	return req.RefreshToken, true
+}
+
+func writeTokenPair(w http.ResponseWriter, pair domain.TokenPair) {
+	domain.WriteResponse(
+		w,
+		map[string]interface{}{
+			"accessToken":  pair.AccessToken,
+			"refreshToken": pair.RefreshToken,
+			"expiresAt":    pair.ExpiresAt,
+		},
+		http.StatusOK,
+	)
+}
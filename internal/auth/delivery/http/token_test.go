@@ -0,0 +1,158 @@
+package http_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	auth_http "github.com/ellexo2456/FilmLib/internal/auth/delivery/http"
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	"github.com/ellexo2456/FilmLib/internal/domain/mocks"
+)
+
+func TestTokenLogin(t *testing.T) {
+	tests := []struct {
+		name                 string
+		body                 string
+		setUCaseExpectations func(uCase *mocks.TokenAuthUsecase)
+		status               int
+	}{
+		{
+			name: "GoodCase/Common",
+			body: `{"email": " uvybini@mail.ru ", "password": "MTIz"}`,
+			setUCaseExpectations: func(uCase *mocks.TokenAuthUsecase) {
+				uCase.On("Login", domain.Credentials{Email: "uvybini@mail.ru", Password: []byte("123")}).
+					Return(domain.TokenPair{AccessToken: "a", RefreshToken: "r"}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:                 "BadCase/InvalidEmail",
+			body:                 `{"email": "not an email", "password": "MTIz"}`,
+			setUCaseExpectations: func(uCase *mocks.TokenAuthUsecase) {},
+			status:               http.StatusBadRequest,
+		},
+		{
+			name:                 "BadCase/InvalidJson",
+			body:                 `{"email": `,
+			setUCaseExpectations: func(uCase *mocks.TokenAuthUsecase) {},
+			status:               http.StatusBadRequest,
+		},
+		{
+			name: "BadCase/Disabled",
+			body: `{"email": "uvybini@mail.ru", "password": "MTIz"}`,
+			setUCaseExpectations: func(uCase *mocks.TokenAuthUsecase) {
+				uCase.On("Login", mock.Anything).Return(domain.TokenPair{}, domain.ErrDisabled)
+			},
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockUsecase := new(mocks.TokenAuthUsecase)
+			test.setUCaseExpectations(mockUsecase)
+
+			req := httptest.NewRequest("POST", "/api/v1/auth/token", bytes.NewReader([]byte(test.body)))
+			rec := httptest.NewRecorder()
+
+			handler := &auth_http.TokenHandler{TokenAuthUsecase: mockUsecase, LoginGuard: allowingGuard()}
+			handler.Login(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTokenRefresh(t *testing.T) {
+	tests := []struct {
+		name                 string
+		body                 string
+		setUCaseExpectations func(uCase *mocks.TokenAuthUsecase)
+		status               int
+	}{
+		{
+			name: "GoodCase/Common",
+			body: `{"refreshToken": "r"}`,
+			setUCaseExpectations: func(uCase *mocks.TokenAuthUsecase) {
+				uCase.On("Refresh", "r").Return(domain.TokenPair{AccessToken: "a", RefreshToken: "r2"}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:                 "BadCase/EmptyToken",
+			body:                 `{}`,
+			setUCaseExpectations: func(uCase *mocks.TokenAuthUsecase) {},
+			status:               http.StatusBadRequest,
+		},
+		{
+			name: "BadCase/UsedToken",
+			body: `{"refreshToken": "r"}`,
+			setUCaseExpectations: func(uCase *mocks.TokenAuthUsecase) {
+				uCase.On("Refresh", "r").Return(domain.TokenPair{}, domain.ErrInvalidToken)
+			},
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockUsecase := new(mocks.TokenAuthUsecase)
+			test.setUCaseExpectations(mockUsecase)
+
+			req := httptest.NewRequest("POST", "/api/v1/auth/token/refresh", bytes.NewReader([]byte(test.body)))
+			rec := httptest.NewRecorder()
+
+			handler := &auth_http.TokenHandler{TokenAuthUsecase: mockUsecase}
+			handler.Refresh(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTokenRevoke(t *testing.T) {
+	tests := []struct {
+		name                 string
+		body                 string
+		setUCaseExpectations func(uCase *mocks.TokenAuthUsecase)
+		status               int
+	}{
+		{
+			name: "GoodCase/Common",
+			body: `{"refreshToken": "r"}`,
+			setUCaseExpectations: func(uCase *mocks.TokenAuthUsecase) {
+				uCase.On("Revoke", "r").Return(nil)
+			},
+			status: http.StatusNoContent,
+		},
+		{
+			name:                 "BadCase/InvalidJson",
+			body:                 `{"refreshToken": `,
+			setUCaseExpectations: func(uCase *mocks.TokenAuthUsecase) {},
+			status:               http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockUsecase := new(mocks.TokenAuthUsecase)
+			test.setUCaseExpectations(mockUsecase)
+
+			req := httptest.NewRequest("POST", "/api/v1/auth/token/revoke", bytes.NewReader([]byte(test.body)))
+			rec := httptest.NewRecorder()
+
+			handler := &auth_http.TokenHandler{TokenAuthUsecase: mockUsecase}
+			handler.Revoke(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,325 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+)
+
+type TwoFactorHandler struct {
+	TwoFactorUsecase domain.TwoFactorUsecase
+	LoginGuard       domain.LoginGuard
+}
+
+// NewTwoFactorHandler registers the second login step. The wrong codes
+// are throttled the same way as the wrong passwords.
+func NewTwoFactorHandler(mux *http.ServeMux, u domain.TwoFactorUsecase, g domain.LoginGuard) {
+	handler := &TwoFactorHandler{
+		TwoFactorUsecase: u,
+		LoginGuard:       g,
+	}
+
+	mux.HandleFunc("POST /login/2fa", handler.CompleteLogin)
+	mux.HandleFunc("POST /login/2fa/setup", handler.SetupByChallenge)
+}
+
+// NewTwoFactorSettingsHandler registers the 2FA settings of the current user.
+func NewTwoFactorSettingsHandler(mux *http.ServeMux, u domain.TwoFactorUsecase) {
+	handler := &TwoFactorHandler{
+		TwoFactorUsecase: u,
+	}
+
+	mux.HandleFunc("POST /me/2fa/setup", handler.Setup)
+	mux.HandleFunc("POST /me/2fa/enable", handler.Enable)
+	mux.HandleFunc("POST /me/2fa/disable", handler.Disable)
+}
+
+// CompleteLogin godoc
+//
+//	@Summary		finish login with 2FA
+//	@Description	check the TOTP or recovery code for the mfaToken returned by the login and put the session into cookie. The mfaToken allows one attempt. A user who enrolls during the login gets recovery codes. With rememberMe a remember_token cookie is set as well
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		domain.TwoFactorLogin	true	"mfa token and code"
+//	@Success		200		{object}	object{body=object{id=int,csrfToken=string,recoveryCodes=[]string}}
+//	@Failure		400		{object}	object{err=string}
+//	@Failure		403		{object}	object{err=string}
+//	@Failure		429		{object}	object{err=string}
+//	@Failure		500		{object}	object{err=string}
+//	@Router			/api/v1/auth/login/2fa [post]
+func (h *TwoFactorHandler) CompleteLogin(w http.ResponseWriter, r *http.Request) {
+	var req domain.TwoFactorLogin
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "auth_http", "CompleteLogin", err, "Failed to decode json from body")
+		return
+	}
+	defer domain.CloseAndAlert(r.Body, "auth/http", "CompleteLogin")
+
+	if req.MFAToken == "" || req.Code == "" {
+		domain.WriteError(w, domain.ErrBadRequest.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "auth_http", "CompleteLogin", domain.ErrBadRequest, "token or code is empty")
+		return
+	}
+
+	email, err := h.TwoFactorUsecase.ChallengeEmail(req.MFAToken)
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "auth_http", "CompleteLogin", err, "Failed to get challenge")
+		return
+	}
+
+	ip := domain.ClientIP(r)
+	if !allowAttempt(w, h.LoginGuard, email, ip, "CompleteLogin") {
+		return
+	}
+
+	session, codes, err := h.TwoFactorUsecase.CompleteLogin(req.MFAToken, req.Code, req.RememberMe, domain.RequestClient(r))
+	reportAttempt(w, h.LoginGuard, email, ip, err)
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "auth_http", "CompleteLogin", err, "Failed to login")
+		return
+	}
+
+	body := map[string]interface{}{
+		"id":        session.UserID,
+		"csrfToken": setSessionCookie(w, session),
+	}
+	if codes != nil {
+		body["recoveryCodes"] = codes
+	}
+
+	domain.WriteResponse(w, body, http.StatusOK)
+}
+
+// SetupByChallenge godoc
+//
+//	@Summary		set up 2FA during login
+//	@Description	generate a TOTP secret for a user whose role requires 2FA but who hasn`t enrolled yet. The code is then sent to /login/2fa
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		domain.MFATokenRequest	true	"mfa token"
+//	@Success		200		{object}	object{body=object{totp=domain.TOTPSetup}}
+//	@Failure		400		{object}	object{err=string}
+//	@Failure		409		{object}	object{err=string}
+//	@Failure		500		{object}	object{err=string}
+//	@Router			/api/v1/auth/login/2fa/setup [post]
+func (h *TwoFactorHandler) SetupByChallenge(w http.ResponseWriter, r *http.Request) {
+	var req domain.MFATokenRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "auth_http", "SetupByChallenge", err, "Failed to decode json from body")
+		return
+	}
+	defer domain.CloseAndAlert(r.Body, "auth/http", "SetupByChallenge")
+
+	setup, err := h.TwoFactorUsecase.SetupByChallenge(req.MFAToken)
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "auth_http", "SetupByChallenge", err, err.Error())
+		return
+	}
+
+	domain.WriteResponse(
+		w,
+		map[string]interface{}{
+			"totp": setup,
+		},
+		http.StatusOK,
+	)
+}
+
+// Setup godoc
+//
+//	@Summary		set up 2FA
+//	@Description	generate a new TOTP secret and its provisioning uri for the QR code. 2FA is enabled after the first code is confirmed. Can`t be called with an API token
+//	@Tags			Auth
+//	@Produce		json
+//	@Success		200	{object}	object{body=object{totp=domain.TOTPSetup}}
+//	@Failure		401	{object}	object{err=string}
+//	@Failure		403	{object}	object{err=string}
+//	@Failure		409	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/me/2fa/setup [post]
+func (h *TwoFactorHandler) Setup(w http.ResponseWriter, r *http.Request) {
+	sc, ok := sessionOnly(w, r, "Setup")
+	if !ok {
+		return
+	}
+
+	setup, err := h.TwoFactorUsecase.Setup(sc.UserID)
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "auth_http", "Setup", err, err.Error())
+		return
+	}
+
+	domain.WriteResponse(
+		w,
+		map[string]interface{}{
+			"totp": setup,
+		},
+		http.StatusOK,
+	)
+}
+
+// Enable godoc
+//
+//	@Summary		enable 2FA
+//	@Description	confirm the TOTP secret with a code and get one-time recovery codes. They are shown only once. Can`t be called with an API token
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		domain.CodeRequest	true	"TOTP code"
+//	@Success		200		{object}	object{body=object{recoveryCodes=[]string}}
+//	@Failure		400		{object}	object{err=string}
+//	@Failure		401		{object}	object{err=string}
+//	@Failure		403		{object}	object{err=string}
+//	@Failure		409		{object}	object{err=string}
+//	@Failure		500		{object}	object{err=string}
+//	@Router			/api/v1/me/2fa/enable [post]
+func (h *TwoFactorHandler) Enable(w http.ResponseWriter, r *http.Request) {
+	sc, ok := sessionOnly(w, r, "Enable")
+	if !ok {
+		return
+	}
+
+	code, ok := decodeCode(w, r, "Enable")
+	if !ok {
+		return
+	}
+
+	codes, err := h.TwoFactorUsecase.Enable(sc.UserID, code)
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "auth_http", "Enable", err, err.Error())
+		return
+	}
+
+	domain.WriteResponse(
+		w,
+		map[string]interface{}{
+			"recoveryCodes": codes,
+		},
+		http.StatusOK,
+	)
+}
+
+// Disable godoc
+//
+//	@Summary		disable 2FA
+//	@Description	disable 2FA with a TOTP or recovery code. Forbidden if 2FA is required for the user role. Can`t be called with an API token
+//	@Tags			Auth
+//	@Accept			json
+//	@Param			body	body	domain.CodeRequest	true	"TOTP or recovery code"
+//	@Success		204
+//	@Failure		400	{object}	object{err=string}
+//	@Failure		401	{object}	object{err=string}
+//	@Failure		403	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/me/2fa/disable [post]
+func (h *TwoFactorHandler) Disable(w http.ResponseWriter, r *http.Request) {
+	sc, ok := sessionOnly(w, r, "Disable")
+	if !ok {
+		return
+	}
+
+	code, ok := decodeCode(w, r, "Disable")
+	if !ok {
+		return
+	}
+
+	if err := h.TwoFactorUsecase.Disable(sc.UserID, sc.Role, code); err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "auth_http", "Disable", err, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// sessionOnly doesn`t let an API token change the 2FA settings.
+func sessionOnly(w http.ResponseWriter, r *http.Request, funcName string) (domain.SessionContext, bool) {
+	sc, ok := r.Context().Value(domain.SessionContextKey).(domain.SessionContext)
+	if !ok {
+		domain.WriteError(w, "can`t find user", http.StatusInternalServerError)
+		logs.LogError(logs.Logger, "auth_http", funcName, errors.New("can`t find user"), "can`t find user")
+		return domain.SessionContext{}, false
+	}
+	if sc.TokenID != 0 {
+		domain.WriteError(w, domain.ErrForbidden.Error(), http.StatusForbidden)
+		logs.LogError(logs.Logger, "auth_http", funcName, domain.ErrForbidden, "called with an API token")
+		return domain.SessionContext{}, false
+	}
+
+	return sc, true
+}
+
+func decodeCode(w http.ResponseWriter, r *http.Request, funcName string) (string, bool) {
+	var req domain.CodeRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "auth_http", funcName, err, "Failed to decode json from body")
+		return "", false
+	}
+	defer domain.CloseAndAlert(r.Body, "auth/http", funcName)
+
+	if req.Code == "" {
+		domain.WriteError(w, domain.ErrBadRequest.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "auth_http", funcName, domain.ErrBadRequest, "code is empty")
+		return "", false
+	}
+
+	return req.Code, true
+}
+
+// writeLogin sets the session cookie, or returns the challenge if the
+// login needs the second step.
+func writeLogin(w http.ResponseWriter, session domain.Session, userID int) {
+	if session.MFARequired {
+		domain.WriteResponse(
+			w,
+			map[string]interface{}{
+				"mfaRequired": true,
+				"mfaToken":    session.Token,
+			},
+			http.StatusOK,
+		)
+		return
+	}
+
+	domain.WriteResponse(
+		w,
+		map[string]interface{}{
+			"id":        userID,
+			"csrfToken": setSessionCookie(w, session),
+		},
+		http.StatusOK,
+	)
+}
+
+// setSessionCookie also issues a new CSRF token and returns it. If that
+// fails the client can still get a token from /csrf.
+func setSessionCookie(w http.ResponseWriter, session domain.Session) string {
+	domain.SetSessionCookie(w, session.Token, session.ExpiresAt)
+	if session.RememberToken != "" {
+		domain.SetRememberCookie(w, session.RememberToken, session.RememberExpiresAt)
+	}
+
+	token, err := domain.IssueCSRFToken(w)
+	if err != nil {
+		logs.LogError(logs.Logger, "auth_http", "setSessionCookie", err, "failed to issue csrf token")
+		return ""
+	}
+
+	return token
+}
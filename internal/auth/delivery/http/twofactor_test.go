@@ -0,0 +1,184 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	auth_http "github.com/ellexo2456/FilmLib/internal/auth/delivery/http"
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	"github.com/ellexo2456/FilmLib/internal/domain/mocks"
+)
+
+func TestCompleteLogin(t *testing.T) {
+	tests := []struct {
+		name                 string
+		body                 string
+		setUCaseExpectations func(uCase *mocks.TwoFactorUsecase)
+		status               int
+		wantCookie           bool
+	}{
+		{
+			name: "GoodCase/Common",
+			body: `{"mfaToken": "mfa", "code": "123456"}`,
+			setUCaseExpectations: func(uCase *mocks.TwoFactorUsecase) {
+				uCase.On("ChallengeEmail", "mfa").Return("uvybini@mail.ru", nil)
+				uCase.On("CompleteLogin", "mfa", "123456", false, mock.Anything).Return(domain.Session{Token: "s", UserID: 1}, nil, nil)
+			},
+			status:     http.StatusOK,
+			wantCookie: true,
+		},
+		{
+			name: "GoodCase/RememberMe",
+			body: `{"mfaToken": "mfa", "code": "123456", "rememberMe": true}`,
+			setUCaseExpectations: func(uCase *mocks.TwoFactorUsecase) {
+				uCase.On("ChallengeEmail", "mfa").Return("uvybini@mail.ru", nil)
+				uCase.On("CompleteLogin", "mfa", "123456", true, mock.Anything).
+					Return(domain.Session{Token: "s", UserID: 1, RememberToken: "r"}, nil, nil)
+			},
+			status:     http.StatusOK,
+			wantCookie: true,
+		},
+		{
+			name:                 "BadCase/EmptyCode",
+			body:                 `{"mfaToken": "mfa"}`,
+			setUCaseExpectations: func(uCase *mocks.TwoFactorUsecase) {},
+			status:               http.StatusBadRequest,
+		},
+		{
+			name:                 "BadCase/InvalidJson",
+			body:                 `{"mfaToken": `,
+			setUCaseExpectations: func(uCase *mocks.TwoFactorUsecase) {},
+			status:               http.StatusBadRequest,
+		},
+		{
+			name: "BadCase/UnknownChallenge",
+			body: `{"mfaToken": "mfa", "code": "123456"}`,
+			setUCaseExpectations: func(uCase *mocks.TwoFactorUsecase) {
+				uCase.On("ChallengeEmail", "mfa").Return("", domain.ErrInvalidToken)
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "BadCase/WrongCode",
+			body: `{"mfaToken": "mfa", "code": "000000"}`,
+			setUCaseExpectations: func(uCase *mocks.TwoFactorUsecase) {
+				uCase.On("ChallengeEmail", "mfa").Return("uvybini@mail.ru", nil)
+				uCase.On("CompleteLogin", "mfa", "000000", false, mock.Anything).Return(domain.Session{}, nil, domain.ErrWrongCredentials)
+			},
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockUsecase := new(mocks.TwoFactorUsecase)
+			test.setUCaseExpectations(mockUsecase)
+
+			req := httptest.NewRequest("POST", "/api/v1/auth/login/2fa", bytes.NewReader([]byte(test.body)))
+			rec := httptest.NewRecorder()
+
+			handler := &auth_http.TwoFactorHandler{TwoFactorUsecase: mockUsecase, LoginGuard: allowingGuard()}
+			handler.CompleteLogin(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			if test.wantCookie {
+				cookies := rec.Result().Cookies()
+				assert.NotEmpty(t, cookies)
+				assert.Equal(t, "session_token", cookies[0].Name)
+				assert.Equal(t, "csrf_token", cookies[len(cookies)-1].Name)
+				assert.Contains(t, rec.Body.String(), cookies[len(cookies)-1].Value)
+			}
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTwoFactorSettings(t *testing.T) {
+	tests := []struct {
+		name                 string
+		target               string
+		body                 string
+		sc                   domain.SessionContext
+		setUCaseExpectations func(uCase *mocks.TwoFactorUsecase)
+		status               int
+	}{
+		{
+			name:   "GoodCase/Setup",
+			target: "/me/2fa/setup",
+			sc:     domain.SessionContext{UserID: 1},
+			setUCaseExpectations: func(uCase *mocks.TwoFactorUsecase) {
+				uCase.On("Setup", 1).Return(domain.TOTPSetup{Secret: "s", URI: "otpauth://totp/x"}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:                 "BadCase/SetupWithAPIToken",
+			target:               "/me/2fa/setup",
+			sc:                   domain.SessionContext{UserID: 1, TokenID: 3},
+			setUCaseExpectations: func(uCase *mocks.TwoFactorUsecase) {},
+			status:               http.StatusForbidden,
+		},
+		{
+			name:   "GoodCase/Enable",
+			target: "/me/2fa/enable",
+			body:   `{"code": "123456"}`,
+			sc:     domain.SessionContext{UserID: 1},
+			setUCaseExpectations: func(uCase *mocks.TwoFactorUsecase) {
+				uCase.On("Enable", 1, "123456").Return([]string{"abcd-efgh"}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:                 "BadCase/EnableEmptyCode",
+			target:               "/me/2fa/enable",
+			body:                 `{}`,
+			sc:                   domain.SessionContext{UserID: 1},
+			setUCaseExpectations: func(uCase *mocks.TwoFactorUsecase) {},
+			status:               http.StatusBadRequest,
+		},
+		{
+			name:   "GoodCase/Disable",
+			target: "/me/2fa/disable",
+			body:   `{"code": "abcd-efgh"}`,
+			sc:     domain.SessionContext{UserID: 1, Role: domain.Usr},
+			setUCaseExpectations: func(uCase *mocks.TwoFactorUsecase) {
+				uCase.On("Disable", 1, domain.Usr, "abcd-efgh").Return(nil)
+			},
+			status: http.StatusNoContent,
+		},
+		{
+			name:   "BadCase/DisableRequired",
+			target: "/me/2fa/disable",
+			body:   `{"code": "123456"}`,
+			sc:     domain.SessionContext{UserID: 1, Role: domain.Moder},
+			setUCaseExpectations: func(uCase *mocks.TwoFactorUsecase) {
+				uCase.On("Disable", 1, domain.Moder, mock.Anything).Return(domain.ErrForbidden)
+			},
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockUsecase := new(mocks.TwoFactorUsecase)
+			test.setUCaseExpectations(mockUsecase)
+
+			mux := http.NewServeMux()
+			auth_http.NewTwoFactorSettingsHandler(mux, mockUsecase)
+
+			req := httptest.NewRequest("POST", test.target, bytes.NewReader([]byte(test.body)))
+			req = req.WithContext(context.WithValue(context.Background(), domain.SessionContextKey, test.sc))
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+)
+
+type VerificationHandler struct {
+	VerificationUsecase domain.VerificationUsecase
+}
+
+func NewVerificationHandler(mux *http.ServeMux, u domain.VerificationUsecase) {
+	handler := &VerificationHandler{
+		VerificationUsecase: u,
+	}
+
+	mux.HandleFunc("GET /verify", handler.Verify)
+	mux.HandleFunc("POST /verify/resend", handler.Resend)
+}
+
+// Verify godoc
+//
+//	@Summary		verify email
+//	@Description	mark the user email as verified using the token from the verification link
+//	@Tags			Auth
+//	@Param			token	query	string	true	"verification token"
+//	@Success		204
+//	@Failure		400	{object}	object{err=string}
+//	@Failure		404	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/auth/verify [get]
+func (h *VerificationHandler) Verify(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		domain.WriteError(w, domain.ErrInvalidToken.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "auth_http", "Verify", domain.ErrInvalidToken, "token is empty")
+		return
+	}
+
+	if err := h.VerificationUsecase.Verify(token); err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "auth_http", "Verify", err, "Failed to verify email")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Resend godoc
+//
+//	@Summary		resend verification link
+//	@Description	send the verification link again. Responds the same way whether the email is registered or not
+//	@Tags			Auth
+//	@Accept			json
+//	@Param			body	body	domain.EmailRequest	true	"user email"
+//	@Success		204
+//	@Failure		400	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/auth/verify/resend [post]
+func (h *VerificationHandler) Resend(w http.ResponseWriter, r *http.Request) {
+	var req domain.EmailRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "auth_http", "Resend", err, "Failed to decode json from body")
+		return
+	}
+	defer domain.CloseAndAlert(r.Body, "auth/http", "Resend")
+
+	req.Email = strings.TrimSpace(req.Email)
+	if !valid(req.Email) {
+		domain.WriteError(w, domain.ErrBadRequest.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "auth_http", "Resend", domain.ErrBadRequest, "email is invalid")
+		return
+	}
+
+	if err = h.VerificationUsecase.Resend(req.Email); err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "auth_http", "Resend", err, "Failed to resend verification")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,109 @@
+package http_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	auth_http "github.com/ellexo2456/FilmLib/internal/auth/delivery/http"
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	"github.com/ellexo2456/FilmLib/internal/domain/mocks"
+)
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name                 string
+		query                string
+		setUCaseExpectations func(uCase *mocks.VerificationUsecase)
+		status               int
+	}{
+		{
+			name:  "GoodCase/Common",
+			query: "?token=abc.def",
+			setUCaseExpectations: func(uCase *mocks.VerificationUsecase) {
+				uCase.On("Verify", "abc.def").Return(nil)
+			},
+			status: http.StatusNoContent,
+		},
+		{
+			name:                 "BadCase/NoToken",
+			setUCaseExpectations: func(uCase *mocks.VerificationUsecase) {},
+			status:               http.StatusBadRequest,
+		},
+		{
+			name:  "BadCase/InvalidToken",
+			query: "?token=abc.def",
+			setUCaseExpectations: func(uCase *mocks.VerificationUsecase) {
+				uCase.On("Verify", "abc.def").Return(domain.ErrInvalidToken)
+			},
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockUsecase := new(mocks.VerificationUsecase)
+			test.setUCaseExpectations(mockUsecase)
+
+			req := httptest.NewRequest("GET", "/api/v1/auth/verify"+test.query, nil)
+			rec := httptest.NewRecorder()
+
+			handler := &auth_http.VerificationHandler{VerificationUsecase: mockUsecase}
+			handler.Verify(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestResend(t *testing.T) {
+	tests := []struct {
+		name                 string
+		body                 string
+		setUCaseExpectations func(uCase *mocks.VerificationUsecase)
+		status               int
+	}{
+		{
+			name: "GoodCase/Common",
+			body: `{"email": "uvybini@mail.ru"}`,
+			setUCaseExpectations: func(uCase *mocks.VerificationUsecase) {
+				uCase.On("Resend", "uvybini@mail.ru").Return(nil)
+			},
+			status: http.StatusNoContent,
+		},
+		{
+			name:                 "BadCase/InvalidEmail",
+			body:                 `{"email": "uvybini"}`,
+			setUCaseExpectations: func(uCase *mocks.VerificationUsecase) {},
+			status:               http.StatusBadRequest,
+		},
+		{
+			name: "BadCase/UsecaseError",
+			body: `{"email": "uvybini@mail.ru"}`,
+			setUCaseExpectations: func(uCase *mocks.VerificationUsecase) {
+				uCase.On("Resend", "uvybini@mail.ru").Return(domain.ErrInternalServerError)
+			},
+			status: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockUsecase := new(mocks.VerificationUsecase)
+			test.setUCaseExpectations(mockUsecase)
+
+			req := httptest.NewRequest("POST", "/api/v1/auth/verify/resend", bytes.NewReader([]byte(test.body)))
+			rec := httptest.NewRecorder()
+
+			handler := &auth_http.VerificationHandler{VerificationUsecase: mockUsecase}
+			handler.Resend(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
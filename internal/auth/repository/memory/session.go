@@ -0,0 +1,211 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+)
+
+type sessionData struct {
+	domain.SessionContext
+	client     domain.Client
+	createdAt  time.Time
+	lastSeenAt time.Time
+	expiresAt  time.Time
+	deadline   time.Time
+}
+
+type sessionMemoryRepository struct {
+	mu       sync.Mutex
+	sessions map[string]*sessionData
+	clock    domain.Clock
+}
+
+// NewSessionMemoryRepository keeps the sessions in the process, so they
+// are lost on restart and aren`t shared between instances. Expired
+// sessions are never returned, they are deleted every sweepInterval
+// until ctx is done. A zero interval turns the sweeper off.
+func NewSessionMemoryRepository(ctx context.Context, clock domain.Clock,
+	sweepInterval time.Duration) domain.SessionRepository {
+	r := &sessionMemoryRepository{
+		sessions: make(map[string]*sessionData),
+		clock:    clock,
+	}
+	if sweepInterval > 0 {
+		go r.sweep(ctx, sweepInterval)
+	}
+
+	return r
+}
+
+func (r *sessionMemoryRepository) Add(session domain.Session) error {
+	if session.Token == "" {
+		return domain.ErrInvalidToken
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sessions[session.Token] = &sessionData{
+		SessionContext: domain.SessionContext{
+			UserID:   session.UserID,
+			Role:     session.Role,
+			Verified: session.Verified,
+		},
+		client:     session.Client,
+		createdAt:  session.CreatedAt,
+		lastSeenAt: session.CreatedAt,
+		expiresAt:  session.ExpiresAt,
+		deadline:   session.Deadline,
+	}
+
+	return nil
+}
+
+func (r *sessionMemoryRepository) DeleteByToken(token string) error {
+	if token == "" {
+		return domain.ErrInvalidToken
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.sessions, token)
+	return nil
+}
+
+func (r *sessionMemoryRepository) DeleteByUserID(userID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for token, data := range r.sessions {
+		if data.UserID == userID {
+			delete(r.sessions, token)
+		}
+	}
+
+	return nil
+}
+
+func (r *sessionMemoryRepository) GetSessionContext(token string) (domain.SessionContext, error) {
+	if token == "" {
+		return domain.SessionContext{}, domain.ErrInvalidToken
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, ok := r.get(token)
+	if !ok {
+		return domain.SessionContext{}, domain.ErrNotFound
+	}
+
+	return data.SessionContext, nil
+}
+
+func (r *sessionMemoryRepository) GetByUserID(userID int) ([]domain.SessionInfo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.clock.Now()
+	sessions := []domain.SessionInfo{}
+	for token, data := range r.sessions {
+		if data.UserID != userID || !now.Before(data.expiresAt) {
+			continue
+		}
+
+		sessions = append(sessions, domain.SessionInfo{
+			ID:         domain.SessionID(token),
+			UserAgent:  data.client.UserAgent,
+			IP:         data.client.IP,
+			CreatedAt:  data.createdAt,
+			LastSeenAt: data.lastSeenAt,
+			ExpiresAt:  data.expiresAt,
+		})
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.After(sessions[j].CreatedAt)
+	})
+
+	return sessions, nil
+}
+
+func (r *sessionMemoryRepository) DeleteByID(userID int, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for token, data := range r.sessions {
+		if data.UserID == userID && domain.SessionID(token) == id {
+			delete(r.sessions, token)
+			return nil
+		}
+	}
+
+	return domain.ErrNotFound
+}
+
+// Touch updates the last seen time and slides the expiry. It returns
+// the new expiry, or zero time if the session hasn`t been extended.
+func (r *sessionMemoryRepository) Touch(token string, at time.Time, idle time.Duration) (time.Time, error) {
+	if token == "" {
+		return time.Time{}, domain.ErrInvalidToken
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, ok := r.get(token)
+	if !ok {
+		return time.Time{}, domain.ErrNotFound
+	}
+	if at.Sub(data.lastSeenAt) < domain.LastSeenPrecision {
+		return time.Time{}, nil
+	}
+
+	expiresAt := domain.SlideExpiry(data.expiresAt, data.deadline, at, idle)
+	if !expiresAt.IsZero() {
+		data.expiresAt = expiresAt
+	}
+	data.lastSeenAt = at
+
+	return expiresAt, nil
+}
+
+// get must be called with the lock held.
+func (r *sessionMemoryRepository) get(token string) (*sessionData, bool) {
+	data, ok := r.sessions[token]
+	if !ok {
+		return nil, false
+	}
+	if !r.clock.Now().Before(data.expiresAt) {
+		delete(r.sessions, token)
+		return nil, false
+	}
+
+	return data, true
+}
+
+func (r *sessionMemoryRepository) sweep(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.mu.Lock()
+			now := r.clock.Now()
+			for token, data := range r.sessions {
+				if !now.Before(data.expiresAt) {
+					delete(r.sessions, token)
+				}
+			}
+			r.mu.Unlock()
+		}
+	}
+}
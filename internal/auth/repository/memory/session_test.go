@@ -0,0 +1,57 @@
+package memory_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ellexo2456/FilmLib/internal/auth/repository/memory"
+	"github.com/ellexo2456/FilmLib/internal/auth/repository/sessiontest"
+	"github.com/ellexo2456/FilmLib/internal/domain"
+)
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestSessionContract(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	sessiontest.Run(t, func(t *testing.T) domain.SessionRepository {
+		return memory.NewSessionMemoryRepository(context.Background(), clock, 0)
+	}, clock.Advance)
+}
+
+func TestSweep(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clock := &fakeClock{now: time.Now()}
+	r := memory.NewSessionMemoryRepository(ctx, clock, time.Millisecond)
+	now := clock.Now()
+	err := r.Add(domain.Session{Token: "t", UserID: 1, CreatedAt: now, ExpiresAt: now.Add(time.Minute)})
+	assert.NoError(t, err)
+
+	clock.Advance(2 * time.Minute)
+	// the sweeper deletes it, going back in time can`t revive it
+	time.Sleep(20 * time.Millisecond)
+	clock.Advance(-2 * time.Minute)
+
+	_, err = r.GetSessionContext("t")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
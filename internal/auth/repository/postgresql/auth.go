@@ -11,14 +11,20 @@ import (
 )
 
 const getByEmailQuery = `
-	SELECT id, email, password, role
+	SELECT id, email, password, role, verified, disabled
 	FROM "user"
 	WHERE email = $1
 `
 
+const getByIDQuery = `
+	SELECT id, email, password, role, verified, disabled
+	FROM "user"
+	WHERE id = $1
+`
+
 const addUserQuery = `
-	INSERT INTO "user" (password, email, role)
-	VALUES ($1, $2, $3)
+	INSERT INTO "user" (password, email, role, verified)
+	VALUES ($1, $2, $3, $4)
 	RETURNING id
 `
 
@@ -28,6 +34,18 @@ const userExistsQuery = `
 				  WHERE email = $1)
 `
 
+const updatePasswordQuery = `
+	UPDATE "user"
+	SET password = $1
+	WHERE id = $2
+`
+
+const setVerifiedQuery = `
+	UPDATE "user"
+	SET verified = TRUE
+	WHERE id = $1
+`
+
 type authPostgresqlRepository struct {
 	db  domain.PgxPoolIface
 	ctx context.Context
@@ -51,6 +69,8 @@ func (r *authPostgresqlRepository) GetByEmail(email string) (domain.User, error)
 		&user.Email,
 		&user.Password,
 		&user.Role,
+		&user.Verified,
+		&user.Disabled,
 	)
 
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -65,6 +85,31 @@ func (r *authPostgresqlRepository) GetByEmail(email string) (domain.User, error)
 	return user, nil
 }
 
+func (r *authPostgresqlRepository) GetByID(id int) (domain.User, error) {
+	result := r.db.QueryRow(r.ctx, getByIDQuery, id)
+
+	var user domain.User
+	err := result.Scan(
+		&user.ID,
+		&user.Email,
+		&user.Password,
+		&user.Role,
+		&user.Verified,
+		&user.Disabled,
+	)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		logs.LogError(logs.Logger, "auth_postgres", "GetByID", err, err.Error())
+		return domain.User{}, domain.ErrNotFound
+	}
+	if err != nil {
+		logs.LogError(logs.Logger, "auth_postgres", "GetByID", err, err.Error())
+		return domain.User{}, err
+	}
+
+	return user, nil
+}
+
 func (r *authPostgresqlRepository) AddUser(user domain.User) (int, error) {
 	if user.Email == "" || len(user.Password) == 0 {
 		return 0, domain.ErrBadRequest
@@ -74,6 +119,7 @@ func (r *authPostgresqlRepository) AddUser(user domain.User) (int, error) {
 		user.Password,
 		user.Email,
 		user.Role,
+		user.Verified,
 	)
 
 	logs.Logger.Debug("AddUser queryRow result:", result)
@@ -97,3 +143,35 @@ func (r *authPostgresqlRepository) UserExists(email string) (bool, error) {
 
 	return exist, nil
 }
+
+func (r *authPostgresqlRepository) UpdatePassword(userID int, password []byte) error {
+	if len(password) == 0 {
+		return domain.ErrBadRequest
+	}
+
+	res, err := r.db.Exec(r.ctx, updatePasswordQuery, password, userID)
+	if err != nil {
+		logs.LogError(logs.Logger, "auth_postgres", "UpdatePassword", err, err.Error())
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *authPostgresqlRepository) SetVerified(userID int) error {
+	res, err := r.db.Exec(r.ctx, setVerifiedQuery, userID)
+	if err != nil {
+		logs.LogError(logs.Logger, "auth_postgres", "SetVerified", err, err.Error())
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
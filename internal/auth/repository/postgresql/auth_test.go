@@ -12,8 +12,20 @@ import (
 )
 
 const getByEmailQueryTest = `
-	SELECT id, email, password, role
+	SELECT id, email, password, role, verified, disabled
 	FROM "user"
+	WHERE email = \$1
+`
+
+const getByIDQueryTest = `
+	SELECT id, email, password, role, verified, disabled
+	FROM "user"
+	WHERE id = \$1
+`
+
+const setVerifiedQueryTest = `
+	UPDATE "user"
+	SET verified = TRUE
 `
 
 const addUserQueryTest = `
@@ -26,6 +38,11 @@ const userExistsQueryTest = `
 				  WHERE email = \$1\)
 `
 
+const updatePasswordQueryTest = `
+	UPDATE "user"
+	SET password = \$1
+`
+
 func TestGetByEmail(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -67,8 +84,8 @@ func TestGetByEmail(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 
-			row := mockDB.NewRows([]string{"id", "email", "password", "role"}).
-				AddRow(test.user.ID, test.user.Email, test.user.Password, test.user.Role)
+			row := mockDB.NewRows([]string{"id", "email", "password", "role", "verified", "disabled"}).
+				AddRow(test.user.ID, test.user.Email, test.user.Password, test.user.Role, test.user.Verified, test.user.Disabled)
 
 			eq := mockDB.ExpectQuery(getByEmailQueryTest).
 				WithArgs(test.user.Email)
@@ -138,7 +155,7 @@ func TestAddUser(t *testing.T) {
 			row := mockDB.NewRows([]string{"id"}).
 				AddRow(test.user.ID)
 			eq := mockDB.ExpectQuery(addUserQueryTest).
-				WithArgs(test.user.Password, test.user.Email, test.user.Role)
+				WithArgs(test.user.Password, test.user.Email, test.user.Role, test.user.Verified)
 			if test.good {
 				eq.WillReturnRows(row)
 			} else {
@@ -219,3 +236,195 @@ func TestUserExists(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdatePassword(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   int
+		password []byte
+		affected int64
+		dbErr    error
+		err      error
+	}{
+		{
+			name:     "GoodCase/Common",
+			userID:   1,
+			password: []byte{123},
+			affected: 1,
+		},
+		{
+			name:     "BadCase/UserNotFound",
+			userID:   2,
+			password: []byte{123},
+			err:      domain.ErrNotFound,
+		},
+		{
+			name:   "BadCase/EmptyPassword",
+			userID: 1,
+			err:    domain.ErrBadRequest,
+		},
+		{
+			name:     "BadCase/DBError",
+			userID:   1,
+			password: []byte{123},
+			dbErr:    errors.New("some error"),
+			err:      errors.New("some error"),
+		},
+	}
+
+	mockDB, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockDB.Close()
+	r := postgres.NewAuthPostgresqlRepository(mockDB, context.Background())
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if len(test.password) != 0 {
+				ee := mockDB.ExpectExec(updatePasswordQueryTest).
+					WithArgs(test.password, test.userID)
+				if test.dbErr != nil {
+					ee.WillReturnError(test.dbErr)
+				} else {
+					ee.WillReturnResult(pgxmock.NewResult("UPDATE", test.affected))
+				}
+			}
+
+			err := r.UpdatePassword(test.userID, test.password)
+			if test.err == nil {
+				require.Nil(t, err)
+			} else {
+				require.EqualError(t, err, test.err.Error())
+			}
+
+			err = mockDB.ExpectationsWereMet()
+			require.Nil(t, err)
+		})
+	}
+}
+
+func TestGetByID(t *testing.T) {
+	tests := []struct {
+		name string
+		id   int
+		user domain.User
+		good bool
+		err  error
+	}{
+		{
+			name: "GoodCase/Common",
+			id:   1,
+			user: domain.User{
+				ID:       1,
+				Email:    "uvybini@mail.ru",
+				Password: []byte{123},
+				Role:     domain.Moder,
+				Verified: true,
+			},
+			good: true,
+		},
+		{
+			name: "BadCase/NotFound",
+			id:   2,
+			err:  pgx.ErrNoRows,
+		},
+		{
+			name: "BadCase/DBError",
+			id:   3,
+			err:  errors.New("some error"),
+		},
+	}
+
+	mockDB, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockDB.Close()
+	r := postgres.NewAuthPostgresqlRepository(mockDB, context.Background())
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			row := mockDB.NewRows([]string{"id", "email", "password", "role", "verified", "disabled"}).
+				AddRow(test.user.ID, test.user.Email, test.user.Password, test.user.Role, test.user.Verified, test.user.Disabled)
+
+			eq := mockDB.ExpectQuery(getByIDQueryTest).
+				WithArgs(test.id)
+			if test.good {
+				eq.WillReturnRows(row)
+			} else {
+				eq.WillReturnError(test.err)
+			}
+
+			user, err := r.GetByID(test.id)
+			if test.good {
+				require.Nil(t, err)
+				require.Equal(t, test.user, user)
+			} else {
+				require.NotNil(t, err)
+			}
+			if errors.Is(test.err, pgx.ErrNoRows) {
+				require.ErrorIs(t, err, domain.ErrNotFound)
+			}
+
+			err = mockDB.ExpectationsWereMet()
+			require.Nil(t, err)
+		})
+	}
+}
+
+func TestSetVerified(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   int
+		affected int64
+		dbErr    error
+		err      error
+	}{
+		{
+			name:     "GoodCase/Common",
+			userID:   1,
+			affected: 1,
+		},
+		{
+			name:   "BadCase/UserNotFound",
+			userID: 2,
+			err:    domain.ErrNotFound,
+		},
+		{
+			name:   "BadCase/DBError",
+			userID: 1,
+			dbErr:  errors.New("some error"),
+			err:    errors.New("some error"),
+		},
+	}
+
+	mockDB, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockDB.Close()
+	r := postgres.NewAuthPostgresqlRepository(mockDB, context.Background())
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ee := mockDB.ExpectExec(setVerifiedQueryTest).
+				WithArgs(test.userID)
+			if test.dbErr != nil {
+				ee.WillReturnError(test.dbErr)
+			} else {
+				ee.WillReturnResult(pgxmock.NewResult("UPDATE", test.affected))
+			}
+
+			err := r.SetVerified(test.userID)
+			if test.err == nil {
+				require.Nil(t, err)
+			} else {
+				require.EqualError(t, err, test.err.Error())
+			}
+
+			err = mockDB.ExpectationsWereMet()
+			require.Nil(t, err)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+)
+
+const getUserIDQuery = `
+	SELECT user_id
+	FROM user_identity
+	WHERE provider = $1
+	  AND subject = $2
+`
+
+const linkQuery = `
+	INSERT INTO user_identity (user_id, provider, subject, email)
+	VALUES ($1, $2, $3, $4)
+`
+
+type identityPostgresqlRepository struct {
+	db  domain.PgxPoolIface
+	ctx context.Context
+}
+
+func NewIdentityPostgresqlRepository(pool domain.PgxPoolIface, ctx context.Context) domain.IdentityRepository {
+	return &identityPostgresqlRepository{
+		db:  pool,
+		ctx: ctx,
+	}
+}
+
+func (r *identityPostgresqlRepository) GetUserID(provider, subject string) (int, error) {
+	var userID int
+	err := r.db.QueryRow(r.ctx, getUserIDQuery, provider, subject).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, domain.ErrNotFound
+	}
+	if err != nil {
+		logs.LogError(logs.Logger, "auth_postgres", "GetUserID", err, err.Error())
+		return 0, err
+	}
+
+	return userID, nil
+}
+
+func (r *identityPostgresqlRepository) Link(userID int, identity domain.Identity) error {
+	_, err := r.db.Exec(r.ctx, linkQuery, userID, identity.Provider, identity.Subject, identity.Email)
+	if err != nil {
+		logs.LogError(logs.Logger, "auth_postgres", "Link", err, err.Error())
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/require"
+
+	postgres "github.com/ellexo2456/FilmLib/internal/auth/repository/postgresql"
+	"github.com/ellexo2456/FilmLib/internal/domain"
+)
+
+const getUserIDQueryTest = `
+	SELECT user_id
+	FROM user_identity
+`
+
+const linkQueryTest = `
+	INSERT INTO user_identity
+`
+
+func TestGetUserID(t *testing.T) {
+	tests := []struct {
+		name   string
+		userID int
+		dbErr  error
+		err    error
+	}{
+		{
+			name:   "GoodCase/Common",
+			userID: 5,
+		},
+		{
+			name:  "BadCase/NotLinked",
+			dbErr: pgx.ErrNoRows,
+			err:   domain.ErrNotFound,
+		},
+		{
+			name:  "BadCase/DBError",
+			dbErr: errors.New("some error"),
+			err:   errors.New("some error"),
+		},
+	}
+
+	mockDB, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockDB.Close()
+	r := postgres.NewIdentityPostgresqlRepository(mockDB, context.Background())
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			eq := mockDB.ExpectQuery(getUserIDQueryTest).
+				WithArgs("fake", "42")
+			if test.dbErr != nil {
+				eq.WillReturnError(test.dbErr)
+			} else {
+				eq.WillReturnRows(mockDB.NewRows([]string{"user_id"}).AddRow(test.userID))
+			}
+
+			userID, err := r.GetUserID("fake", "42")
+			if test.err == nil {
+				require.Nil(t, err)
+				require.Equal(t, test.userID, userID)
+			} else {
+				require.EqualError(t, err, test.err.Error())
+			}
+
+			err = mockDB.ExpectationsWereMet()
+			require.Nil(t, err)
+		})
+	}
+}
+
+func TestLink(t *testing.T) {
+	identity := domain.Identity{Provider: "fake", Subject: "42", Email: "uvybini@mail.ru", EmailVerified: true}
+
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{
+			name: "GoodCase/Common",
+		},
+		{
+			name: "BadCase/AlreadyLinked",
+			err:  errors.New("duplicate key value violates unique constraint"),
+		},
+	}
+
+	mockDB, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockDB.Close()
+	r := postgres.NewIdentityPostgresqlRepository(mockDB, context.Background())
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ee := mockDB.ExpectExec(linkQueryTest).
+				WithArgs(5, identity.Provider, identity.Subject, identity.Email)
+			if test.err != nil {
+				ee.WillReturnError(test.err)
+			} else {
+				ee.WillReturnResult(pgxmock.NewResult("INSERT", 1))
+			}
+
+			err := r.Link(5, identity)
+			if test.err == nil {
+				require.Nil(t, err)
+			} else {
+				require.NotNil(t, err)
+			}
+
+			err = mockDB.ExpectationsWereMet()
+			require.Nil(t, err)
+		})
+	}
+}
@@ -0,0 +1,271 @@
+package postgres
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+)
+
+const insertSessionQuery = `
+	INSERT INTO session (token_hash, id, user_id, role, verified, user_agent, ip,
+	                     created_at, last_seen_at, expires_at, deadline)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8, $9, $10)
+`
+
+const deleteSessionQuery = `
+	DELETE
+	FROM session
+	WHERE token_hash = $1
+`
+
+const deleteUserSessionsQuery = `
+	DELETE
+	FROM session
+	WHERE user_id = $1
+`
+
+const deleteSessionByIDQuery = `
+	DELETE
+	FROM session
+	WHERE user_id = $1
+	  AND id = $2
+	  AND expires_at > CURRENT_TIMESTAMP
+`
+
+const getSessionContextQuery = `
+	SELECT user_id, role, verified
+	FROM session
+	WHERE token_hash = $1
+	  AND expires_at > CURRENT_TIMESTAMP
+`
+
+const selectUserSessionsQuery = `
+	SELECT id, user_agent, ip, created_at, last_seen_at, expires_at
+	FROM session
+	WHERE user_id = $1
+	  AND expires_at > CURRENT_TIMESTAMP
+	ORDER BY created_at DESC
+`
+
+const getSessionExpiryQuery = `
+	SELECT last_seen_at, expires_at, deadline
+	FROM session
+	WHERE token_hash = $1
+	  AND expires_at > CURRENT_TIMESTAMP
+`
+
+const touchSessionQuery = `
+	UPDATE session
+	SET last_seen_at = $1,
+	    expires_at   = $2
+	WHERE token_hash = $3
+`
+
+const deleteExpiredSessionsQuery = `
+	DELETE
+	FROM session
+	WHERE expires_at <= CURRENT_TIMESTAMP
+`
+
+type sessionPostgresqlRepository struct {
+	db  domain.PgxPoolIface
+	ctx context.Context
+}
+
+// NewSessionPostgresqlRepository keeps only a hash of the token. Expired
+// sessions are never returned, they are deleted every sweepInterval
+// until ctx is done. A zero interval turns the sweeper off.
+func NewSessionPostgresqlRepository(pool domain.PgxPoolIface, ctx context.Context,
+	sweepInterval time.Duration) domain.SessionRepository {
+	r := &sessionPostgresqlRepository{
+		db:  pool,
+		ctx: ctx,
+	}
+	if sweepInterval > 0 {
+		go r.sweep(sweepInterval)
+	}
+
+	return r
+}
+
+func (r *sessionPostgresqlRepository) Add(session domain.Session) error {
+	if session.Token == "" {
+		return domain.ErrInvalidToken
+	}
+
+	_, err := r.db.Exec(r.ctx, insertSessionQuery,
+		tokenHash(session.Token),
+		domain.SessionID(session.Token),
+		session.UserID,
+		session.Role,
+		session.Verified,
+		session.Client.UserAgent,
+		session.Client.IP,
+		session.CreatedAt,
+		session.ExpiresAt,
+		session.Deadline,
+	)
+	if err != nil {
+		logs.LogError(logs.Logger, "auth_postgres", "AddSession", err, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (r *sessionPostgresqlRepository) DeleteByToken(token string) error {
+	if token == "" {
+		return domain.ErrInvalidToken
+	}
+
+	if _, err := r.db.Exec(r.ctx, deleteSessionQuery, tokenHash(token)); err != nil {
+		logs.LogError(logs.Logger, "auth_postgres", "DeleteByToken", err, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (r *sessionPostgresqlRepository) DeleteByUserID(userID int) error {
+	if _, err := r.db.Exec(r.ctx, deleteUserSessionsQuery, userID); err != nil {
+		logs.LogError(logs.Logger, "auth_postgres", "DeleteByUserID", err, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (r *sessionPostgresqlRepository) GetSessionContext(token string) (domain.SessionContext, error) {
+	if token == "" {
+		return domain.SessionContext{}, domain.ErrInvalidToken
+	}
+
+	var sc domain.SessionContext
+	err := r.db.QueryRow(r.ctx, getSessionContextQuery, tokenHash(token)).Scan(&sc.UserID, &sc.Role, &sc.Verified)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.SessionContext{}, domain.ErrNotFound
+	}
+	if err != nil {
+		logs.LogError(logs.Logger, "auth_postgres", "GetSessionContext", err, err.Error())
+		return domain.SessionContext{}, err
+	}
+
+	return sc, nil
+}
+
+func (r *sessionPostgresqlRepository) GetByUserID(userID int) ([]domain.SessionInfo, error) {
+	rows, err := r.db.Query(r.ctx, selectUserSessionsQuery, userID)
+	if err != nil {
+		logs.LogError(logs.Logger, "auth_postgres", "GetByUserID", err, err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []domain.SessionInfo{}
+	for rows.Next() {
+		var s domain.SessionInfo
+		err = rows.Scan(
+			&s.ID,
+			&s.UserAgent,
+			&s.IP,
+			&s.CreatedAt,
+			&s.LastSeenAt,
+			&s.ExpiresAt,
+		)
+		if err != nil {
+			logs.LogError(logs.Logger, "auth_postgres", "GetByUserID", err, err.Error())
+			return nil, err
+		}
+
+		sessions = append(sessions, s)
+	}
+	if err = rows.Err(); err != nil {
+		logs.LogError(logs.Logger, "auth_postgres", "GetByUserID", err, err.Error())
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+func (r *sessionPostgresqlRepository) DeleteByID(userID int, id string) error {
+	res, err := r.db.Exec(r.ctx, deleteSessionByIDQuery, userID, id)
+	if err != nil {
+		logs.LogError(logs.Logger, "auth_postgres", "DeleteByID", err, err.Error())
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// Touch updates the last seen time and slides the expiry. It returns
+// the new expiry, or zero time if the session hasn`t been extended.
+func (r *sessionPostgresqlRepository) Touch(token string, at time.Time, idle time.Duration) (time.Time, error) {
+	if token == "" {
+		return time.Time{}, domain.ErrInvalidToken
+	}
+
+	hash := tokenHash(token)
+	var lastSeenAt, expiresAt, deadline time.Time
+	err := r.db.QueryRow(r.ctx, getSessionExpiryQuery, hash).Scan(&lastSeenAt, &expiresAt, &deadline)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return time.Time{}, domain.ErrNotFound
+	}
+	if err != nil {
+		logs.LogError(logs.Logger, "auth_postgres", "Touch", err, err.Error())
+		return time.Time{}, err
+	}
+	if at.Sub(lastSeenAt) < domain.LastSeenPrecision {
+		return time.Time{}, nil
+	}
+
+	next := domain.SlideExpiry(expiresAt, deadline, at, idle)
+	if !next.IsZero() {
+		expiresAt = next
+	}
+
+	res, err := r.db.Exec(r.ctx, touchSessionQuery, at, expiresAt, hash)
+	if err != nil {
+		logs.LogError(logs.Logger, "auth_postgres", "Touch", err, err.Error())
+		return time.Time{}, err
+	}
+	// deleted in the meantime
+	if res.RowsAffected() == 0 {
+		return time.Time{}, domain.ErrNotFound
+	}
+
+	return next, nil
+}
+
+func (r *sessionPostgresqlRepository) sweep(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-ticker.C:
+			res, err := r.db.Exec(r.ctx, deleteExpiredSessionsQuery)
+			if err != nil {
+				logs.LogError(logs.Logger, "auth_postgres", "sweep", err, "failed to delete expired sessions")
+				continue
+			}
+			logs.Logger.Debug("sweep: expired sessions deleted:", res.RowsAffected())
+		}
+	}
+}
+
+func tokenHash(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
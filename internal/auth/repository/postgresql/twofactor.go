@@ -0,0 +1,227 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+)
+
+const getTOTPQuery = `
+	SELECT totp_secret, totp_enabled, totp_last_step
+	FROM "user"
+	WHERE id = $1
+`
+
+const setTOTPSecretQuery = `
+	UPDATE "user"
+	SET totp_secret    = $1,
+	    totp_enabled   = FALSE,
+	    totp_last_step = 0
+	WHERE id = $2
+`
+
+const enableTOTPQuery = `
+	UPDATE "user"
+	SET totp_enabled = TRUE
+	WHERE id = $1
+	  AND totp_secret IS NOT NULL
+`
+
+const disableTOTPQuery = `
+	UPDATE "user"
+	SET totp_secret    = NULL,
+	    totp_enabled   = FALSE,
+	    totp_last_step = 0
+	WHERE id = $1
+`
+
+const deleteRecoveryCodesQuery = `
+	DELETE
+	FROM recovery_code
+	WHERE user_id = $1
+`
+
+const updateLastStepQuery = `
+	UPDATE "user"
+	SET totp_last_step = $1
+	WHERE id = $2
+	  AND totp_last_step < $1
+`
+
+const useRecoveryCodeQuery = `
+	UPDATE recovery_code
+	SET used_at = CURRENT_TIMESTAMP
+	WHERE user_id = $1
+	  AND hash = $2
+	  AND used_at IS NULL
+`
+
+const isRequiredQuery = `
+	SELECT EXISTS(SELECT 1
+				  FROM role_policy
+				  WHERE role = $1
+				    AND require_2fa)
+`
+
+const setRequiredQuery = `
+	INSERT INTO role_policy (role, require_2fa)
+	VALUES ($1, $2)
+	ON CONFLICT (role) DO UPDATE SET require_2fa = excluded.require_2fa
+`
+
+type twoFactorPostgresqlRepository struct {
+	db  domain.PgxPoolIface
+	ctx context.Context
+}
+
+func NewTwoFactorPostgresqlRepository(pool domain.PgxPoolIface, ctx context.Context) domain.TwoFactorRepository {
+	return &twoFactorPostgresqlRepository{
+		db:  pool,
+		ctx: ctx,
+	}
+}
+
+func (r *twoFactorPostgresqlRepository) Get(userID int) (domain.TOTP, error) {
+	var t domain.TOTP
+	err := r.db.QueryRow(r.ctx, getTOTPQuery, userID).Scan(&t.Secret, &t.Enabled, &t.LastStep)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.TOTP{}, domain.ErrNotFound
+	}
+	if err != nil {
+		logs.LogError(logs.Logger, "auth_postgres", "GetTOTP", err, err.Error())
+		return domain.TOTP{}, err
+	}
+
+	return t, nil
+}
+
+func (r *twoFactorPostgresqlRepository) SetSecret(userID int, secret []byte) error {
+	res, err := r.db.Exec(r.ctx, setTOTPSecretQuery, secret, userID)
+	if err != nil {
+		logs.LogError(logs.Logger, "auth_postgres", "SetSecret", err, err.Error())
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// Enable turns 2FA on and replaces the recovery codes in one transaction.
+func (r *twoFactorPostgresqlRepository) Enable(userID int, recoveryCodes [][]byte) error {
+	tx, err := r.db.Begin(r.ctx)
+	if err != nil {
+		logs.LogError(logs.Logger, "auth_postgres", "Enable", err, err.Error())
+		return err
+	}
+	defer tx.Rollback(r.ctx)
+
+	res, err := tx.Exec(r.ctx, enableTOTPQuery, userID)
+	if err != nil {
+		logs.LogError(logs.Logger, "auth_postgres", "Enable", err, err.Error())
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	if _, err = tx.Exec(r.ctx, deleteRecoveryCodesQuery, userID); err != nil {
+		logs.LogError(logs.Logger, "auth_postgres", "Enable", err, err.Error())
+		return err
+	}
+
+	var rows [][]interface{}
+	for _, code := range recoveryCodes {
+		rows = append(rows, []interface{}{userID, code})
+	}
+	_, err = tx.CopyFrom(
+		r.ctx,
+		pgx.Identifier{"recovery_code"},
+		[]string{"user_id", "hash"},
+		pgx.CopyFromRows(rows),
+	)
+	if err != nil {
+		logs.LogError(logs.Logger, "auth_postgres", "Enable", err, err.Error())
+		return err
+	}
+
+	if err = tx.Commit(r.ctx); err != nil {
+		logs.LogError(logs.Logger, "auth_postgres", "Enable", err, "can`t commit changes")
+		return err
+	}
+
+	return nil
+}
+
+func (r *twoFactorPostgresqlRepository) Disable(userID int) error {
+	tx, err := r.db.Begin(r.ctx)
+	if err != nil {
+		logs.LogError(logs.Logger, "auth_postgres", "Disable", err, err.Error())
+		return err
+	}
+	defer tx.Rollback(r.ctx)
+
+	if _, err = tx.Exec(r.ctx, disableTOTPQuery, userID); err != nil {
+		logs.LogError(logs.Logger, "auth_postgres", "Disable", err, err.Error())
+		return err
+	}
+	if _, err = tx.Exec(r.ctx, deleteRecoveryCodesQuery, userID); err != nil {
+		logs.LogError(logs.Logger, "auth_postgres", "Disable", err, err.Error())
+		return err
+	}
+
+	if err = tx.Commit(r.ctx); err != nil {
+		logs.LogError(logs.Logger, "auth_postgres", "Disable", err, "can`t commit changes")
+		return err
+	}
+
+	return nil
+}
+
+// UpdateLastStep stores the step of an accepted code. It reports false
+// when the step isn`t newer than the stored one, so every code can be
+// used only once.
+func (r *twoFactorPostgresqlRepository) UpdateLastStep(userID int, step int64) (bool, error) {
+	res, err := r.db.Exec(r.ctx, updateLastStepQuery, step, userID)
+	if err != nil {
+		logs.LogError(logs.Logger, "auth_postgres", "UpdateLastStep", err, err.Error())
+		return false, err
+	}
+
+	return res.RowsAffected() != 0, nil
+}
+
+func (r *twoFactorPostgresqlRepository) UseRecoveryCode(userID int, hash []byte) (bool, error) {
+	res, err := r.db.Exec(r.ctx, useRecoveryCodeQuery, userID, hash)
+	if err != nil {
+		logs.LogError(logs.Logger, "auth_postgres", "UseRecoveryCode", err, err.Error())
+		return false, err
+	}
+
+	return res.RowsAffected() != 0, nil
+}
+
+func (r *twoFactorPostgresqlRepository) IsRequired(role domain.Role) (bool, error) {
+	var required bool
+	if err := r.db.QueryRow(r.ctx, isRequiredQuery, role).Scan(&required); err != nil {
+		logs.LogError(logs.Logger, "auth_postgres", "IsRequired", err, err.Error())
+		return false, err
+	}
+
+	return required, nil
+}
+
+func (r *twoFactorPostgresqlRepository) SetRequired(role domain.Role, required bool) error {
+	if _, err := r.db.Exec(r.ctx, setRequiredQuery, role, required); err != nil {
+		logs.LogError(logs.Logger, "auth_postgres", "SetRequired", err, err.Error())
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,252 @@
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/require"
+
+	postgres "github.com/ellexo2456/FilmLib/internal/auth/repository/postgresql"
+	"github.com/ellexo2456/FilmLib/internal/domain"
+)
+
+const getTOTPQueryTest = `
+	SELECT totp_secret, totp_enabled, totp_last_step
+	FROM "user"
+`
+
+const enableTOTPQueryTest = `
+	UPDATE "user"
+	SET totp_enabled = TRUE
+`
+
+const deleteRecoveryCodesQueryTest = `
+	DELETE
+	FROM recovery_code
+`
+
+const updateLastStepQueryTest = `
+	UPDATE "user"
+	SET totp_last_step = \$1
+`
+
+const useRecoveryCodeQueryTest = `
+	UPDATE recovery_code
+	SET used_at = CURRENT_TIMESTAMP
+`
+
+func TestGetTOTP(t *testing.T) {
+	tests := []struct {
+		name  string
+		totp  domain.TOTP
+		dbErr error
+		err   error
+	}{
+		{
+			name: "GoodCase/Enabled",
+			totp: domain.TOTP{Secret: []byte("secret"), Enabled: true, LastStep: 100},
+		},
+		{
+			name: "GoodCase/NotSetUp",
+			totp: domain.TOTP{},
+		},
+		{
+			name:  "BadCase/NotFound",
+			dbErr: pgx.ErrNoRows,
+			err:   domain.ErrNotFound,
+		},
+		{
+			name:  "BadCase/DBError",
+			dbErr: errors.New("some error"),
+			err:   errors.New("some error"),
+		},
+	}
+
+	mockDB, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockDB.Close()
+	r := postgres.NewTwoFactorPostgresqlRepository(mockDB, context.Background())
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			eq := mockDB.ExpectQuery(getTOTPQueryTest).WithArgs(1)
+			if test.dbErr != nil {
+				eq.WillReturnError(test.dbErr)
+			} else {
+				eq.WillReturnRows(mockDB.NewRows([]string{"totp_secret", "totp_enabled", "totp_last_step"}).
+					AddRow(test.totp.Secret, test.totp.Enabled, test.totp.LastStep))
+			}
+
+			totp, err := r.Get(1)
+			if test.err == nil {
+				require.Nil(t, err)
+				require.Equal(t, test.totp, totp)
+			} else {
+				require.EqualError(t, err, test.err.Error())
+			}
+
+			err = mockDB.ExpectationsWereMet()
+			require.Nil(t, err)
+		})
+	}
+}
+
+func TestEnableTOTP(t *testing.T) {
+	codes := [][]byte{[]byte("hash1"), []byte("hash2")}
+
+	tests := []struct {
+		name            string
+		setExpectations func(mockDB pgxmock.PgxPoolIface)
+		err             error
+	}{
+		{
+			name: "GoodCase/Common",
+			setExpectations: func(mockDB pgxmock.PgxPoolIface) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectExec(enableTOTPQueryTest).WithArgs(1).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+				mockDB.ExpectExec(deleteRecoveryCodesQueryTest).WithArgs(1).
+					WillReturnResult(pgxmock.NewResult("DELETE", 10))
+				mockDB.ExpectCopyFrom(pgx.Identifier{"recovery_code"}, []string{"user_id", "hash"}).
+					WillReturnResult(2)
+				mockDB.ExpectCommit()
+			},
+		},
+		{
+			name: "BadCase/NoSecret",
+			setExpectations: func(mockDB pgxmock.PgxPoolIface) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectExec(enableTOTPQueryTest).WithArgs(1).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+				mockDB.ExpectRollback()
+			},
+			err: domain.ErrNotFound,
+		},
+		{
+			name: "BadCase/CopyError",
+			setExpectations: func(mockDB pgxmock.PgxPoolIface) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectExec(enableTOTPQueryTest).WithArgs(1).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+				mockDB.ExpectExec(deleteRecoveryCodesQueryTest).WithArgs(1).
+					WillReturnResult(pgxmock.NewResult("DELETE", 0))
+				mockDB.ExpectCopyFrom(pgx.Identifier{"recovery_code"}, []string{"user_id", "hash"}).
+					WillReturnError(domain.ErrInternalServerError)
+				mockDB.ExpectRollback()
+			},
+			err: domain.ErrInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockDB, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mockDB.Close()
+			r := postgres.NewTwoFactorPostgresqlRepository(mockDB, context.Background())
+			test.setExpectations(mockDB)
+
+			err = r.Enable(1, codes)
+			require.ErrorIs(t, err, test.err)
+
+			err = mockDB.ExpectationsWereMet()
+			require.Nil(t, err)
+		})
+	}
+}
+
+func TestUpdateLastStep(t *testing.T) {
+	tests := []struct {
+		name     string
+		affected int64
+		updated  bool
+	}{
+		{
+			name:     "GoodCase/NewStep",
+			affected: 1,
+			updated:  true,
+		},
+		{
+			name:     "GoodCase/Replayed",
+			affected: 0,
+			updated:  false,
+		},
+	}
+
+	mockDB, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockDB.Close()
+	r := postgres.NewTwoFactorPostgresqlRepository(mockDB, context.Background())
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockDB.ExpectExec(updateLastStepQueryTest).
+				WithArgs(int64(100), 1).
+				WillReturnResult(pgxmock.NewResult("UPDATE", test.affected))
+
+			updated, err := r.UpdateLastStep(1, 100)
+			require.Nil(t, err)
+			require.Equal(t, test.updated, updated)
+
+			err = mockDB.ExpectationsWereMet()
+			require.Nil(t, err)
+		})
+	}
+}
+
+func TestUseRecoveryCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		affected int64
+		dbErr    error
+		used     bool
+	}{
+		{
+			name:     "GoodCase/Unused",
+			affected: 1,
+			used:     true,
+		},
+		{
+			name:     "GoodCase/AlreadyUsed",
+			affected: 0,
+		},
+		{
+			name:  "BadCase/DBError",
+			dbErr: errors.New("some error"),
+		},
+	}
+
+	mockDB, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockDB.Close()
+	r := postgres.NewTwoFactorPostgresqlRepository(mockDB, context.Background())
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ee := mockDB.ExpectExec(useRecoveryCodeQueryTest).WithArgs(1, []byte("hash"))
+			if test.dbErr != nil {
+				ee.WillReturnError(test.dbErr)
+			} else {
+				ee.WillReturnResult(pgxmock.NewResult("UPDATE", test.affected))
+			}
+
+			used, err := r.UseRecoveryCode(1, []byte("hash"))
+			require.Equal(t, test.dbErr, err)
+			require.Equal(t, test.used, used)
+
+			err = mockDB.ExpectationsWereMet()
+			require.Nil(t, err)
+		})
+	}
+}
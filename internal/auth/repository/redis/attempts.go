@@ -0,0 +1,66 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+)
+
+const attemptsKeyPrefix = "login_attempts:"
+
+type attemptsRedisRepository struct {
+	client *redis.Client
+}
+
+func NewAttemptsRedisRepository(client *redis.Client) domain.AttemptsRepository {
+	return &attemptsRedisRepository{client}
+}
+
+func (r *attemptsRedisRepository) Get(key string) (domain.Attempts, error) {
+	res, err := r.client.HGetAll(context.Background(), attemptsKeyPrefix+key).Result()
+	if err != nil {
+		return domain.Attempts{}, err
+	}
+	if len(res) == 0 {
+		return domain.Attempts{}, nil
+	}
+
+	failures, err := strconv.Atoi(res["failures"])
+	if err != nil {
+		return domain.Attempts{}, err
+	}
+	last, err := strconv.ParseInt(res["last"], 10, 64)
+	if err != nil {
+		return domain.Attempts{}, err
+	}
+
+	return domain.Attempts{Failures: failures, LastFailure: time.UnixMilli(last)}, nil
+}
+
+// Fail counts a failure at the given time. The counter lives for ttl
+// after the last failure.
+func (r *attemptsRedisRepository) Fail(key string, at time.Time, ttl time.Duration) (domain.Attempts, error) {
+	ctx := context.Background()
+	key = attemptsKeyPrefix + key
+
+	var failures *redis.IntCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		failures = pipe.HIncrBy(ctx, key, "failures", 1)
+		pipe.HSet(ctx, key, "last", at.UnixMilli())
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+	if err != nil {
+		return domain.Attempts{}, err
+	}
+
+	return domain.Attempts{Failures: int(failures.Val()), LastFailure: at}, nil
+}
+
+func (r *attemptsRedisRepository) Reset(key string) error {
+	return r.client.Del(context.Background(), attemptsKeyPrefix+key).Err()
+}
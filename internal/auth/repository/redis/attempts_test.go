@@ -0,0 +1,80 @@
+package redis_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ellexo2456/FilmLib/internal/auth/repository/redis"
+	"github.com/ellexo2456/FilmLib/internal/domain"
+)
+
+func TestAttemptsGet(t *testing.T) {
+	last := time.UnixMilli(1709294400000)
+
+	tests := []struct {
+		name      string
+		setExpect func(mock redismock.ClientMock)
+		attempts  domain.Attempts
+		err       error
+	}{
+		{
+			name: "GoodCase/Common",
+			setExpect: func(mock redismock.ClientMock) {
+				mock.ExpectHGetAll("login_attempts:ip:192.0.2.1").
+					SetVal(map[string]string{"failures": "3", "last": "1709294400000"})
+			},
+			attempts: domain.Attempts{Failures: 3, LastFailure: last},
+		},
+		{
+			name: "GoodCase/NoFailures",
+			setExpect: func(mock redismock.ClientMock) {
+				mock.ExpectHGetAll("login_attempts:ip:192.0.2.1").SetVal(map[string]string{})
+			},
+		},
+		{
+			name: "BadCase/RedisError",
+			setExpect: func(mock redismock.ClientMock) {
+				mock.ExpectHGetAll("login_attempts:ip:192.0.2.1").SetErr(errors.New("some error"))
+			},
+			err: errors.New("some error"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+			defer db.Close()
+			r := redis.NewAttemptsRedisRepository(db)
+			test.setExpect(mock)
+
+			attempts, err := r.Get("ip:192.0.2.1")
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.attempts, attempts)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAttemptsFail(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	defer db.Close()
+	r := redis.NewAttemptsRedisRepository(db)
+	at := time.UnixMilli(1709294400000)
+
+	mock.ExpectTxPipeline()
+	mock.ExpectHIncrBy("login_attempts:ip:192.0.2.1", "failures", 1).SetVal(4)
+	mock.ExpectHSet("login_attempts:ip:192.0.2.1", "last", at.UnixMilli()).SetVal(0)
+	mock.ExpectExpire("login_attempts:ip:192.0.2.1", time.Hour).SetVal(true)
+	mock.ExpectTxPipelineExec()
+
+	attempts, err := r.Fail("ip:192.0.2.1", at, time.Hour)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.Attempts{Failures: 4, LastFailure: at}, attempts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -11,6 +13,20 @@ import (
 	"github.com/ellexo2456/FilmLib/internal/domain"
 )
 
+const userSessionsKeyPrefix = "user_sessions:"
+
+// sessionData is stored under the token. It embeds the context, so
+// sessions stored before the metadata was added still decode.
+type sessionData struct {
+	domain.SessionContext
+	UserAgent  string
+	IP         string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	ExpiresAt  time.Time
+	Deadline   time.Time
+}
+
 type sessionRedisRepository struct {
 	client *redis.Client
 }
@@ -24,16 +40,38 @@ func (s *sessionRedisRepository) Add(session domain.Session) error {
 		return domain.ErrInvalidToken
 	}
 
-	jsonData, err := json.Marshal(domain.SessionContext{
-		UserID: session.UserID,
-		Role:   session.Role,
+	jsonData, err := json.Marshal(sessionData{
+		SessionContext: domain.SessionContext{
+			UserID:   session.UserID,
+			Role:     session.Role,
+			Verified: session.Verified,
+		},
+		UserAgent:  session.Client.UserAgent,
+		IP:         session.Client.IP,
+		CreatedAt:  session.CreatedAt,
+		LastSeenAt: session.CreatedAt,
+		ExpiresAt:  session.ExpiresAt,
+		Deadline:   session.Deadline,
 	})
 	if err != nil {
 		return domain.ErrInvalidToken
 	}
 
+	// the index has to outlive every session in it, whichever of them
+	// is extended the longest
+	indexTTL := session.Deadline.Sub(time.Now())
 	duration := session.ExpiresAt.Sub(time.Now())
-	err = s.client.Set(context.TODO(), session.Token, jsonData, duration).Err()
+	if indexTTL < duration {
+		indexTTL = duration
+	}
+	userKey := userSessionsKey(session.UserID)
+	_, err = s.client.TxPipelined(context.TODO(), func(pipe redis.Pipeliner) error {
+		pipe.Set(context.TODO(), session.Token, jsonData, duration)
+		pipe.SAdd(context.TODO(), userKey, session.Token)
+		pipe.ExpireNX(context.TODO(), userKey, indexTTL)
+		pipe.ExpireGT(context.TODO(), userKey, indexTTL)
+		return nil
+	})
 	if err != nil {
 		return err
 	}
@@ -53,6 +91,23 @@ func (s *sessionRedisRepository) DeleteByToken(token string) error {
 	return nil
 }
 
+// DeleteByUserID drops every session of the user. Tokens of sessions
+// which have already expired may still be in the index, deleting them is a no-op.
+func (s *sessionRedisRepository) DeleteByUserID(userID int) error {
+	userKey := userSessionsKey(userID)
+	tokens, err := s.client.SMembers(context.Background(), userKey).Result()
+	if err != nil {
+		return err
+	}
+
+	err = s.client.Del(context.Background(), append(tokens, userKey)...).Err()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (s *sessionRedisRepository) GetSessionContext(token string) (domain.SessionContext, error) {
 	if token == "" {
 		return domain.SessionContext{}, domain.ErrInvalidToken
@@ -74,3 +129,129 @@ func (s *sessionRedisRepository) GetSessionContext(token string) (domain.Session
 
 	return sc, nil
 }
+
+// GetByUserID returns the live sessions, newest first. Tokens of
+// expired or deleted sessions are removed from the index on the way.
+func (s *sessionRedisRepository) GetByUserID(userID int) ([]domain.SessionInfo, error) {
+	ctx := context.Background()
+	userKey := userSessionsKey(userID)
+	tokens, err := s.client.SMembers(ctx, userKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return []domain.SessionInfo{}, nil
+	}
+
+	values, err := s.client.MGet(ctx, tokens...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]domain.SessionInfo, 0, len(tokens))
+	var stale []interface{}
+	for i, v := range values {
+		raw, ok := v.(string)
+		if !ok {
+			stale = append(stale, tokens[i])
+			continue
+		}
+
+		var data sessionData
+		if err = json.Unmarshal([]byte(raw), &data); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, domain.SessionInfo{
+			ID:         domain.SessionID(tokens[i]),
+			UserAgent:  data.UserAgent,
+			IP:         data.IP,
+			CreatedAt:  data.CreatedAt,
+			LastSeenAt: data.LastSeenAt,
+			ExpiresAt:  data.ExpiresAt,
+		})
+	}
+
+	if len(stale) != 0 {
+		if err = s.client.SRem(ctx, userKey, stale...).Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.After(sessions[j].CreatedAt)
+	})
+
+	return sessions, nil
+}
+
+func (s *sessionRedisRepository) DeleteByID(userID int, id string) error {
+	ctx := context.Background()
+	userKey := userSessionsKey(userID)
+	tokens, err := s.client.SMembers(ctx, userKey).Result()
+	if err != nil {
+		return err
+	}
+
+	for _, token := range tokens {
+		if domain.SessionID(token) != id {
+			continue
+		}
+
+		_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, token)
+			pipe.SRem(ctx, userKey, token)
+			return nil
+		})
+		return err
+	}
+
+	return domain.ErrNotFound
+}
+
+// Touch updates the last seen time and slides the expiry. It returns
+// the new expiry, or zero time if the session hasn`t been extended.
+// SetXX keeps a session deleted in the meantime from coming back.
+func (s *sessionRedisRepository) Touch(token string, at time.Time, idle time.Duration) (time.Time, error) {
+	if token == "" {
+		return time.Time{}, domain.ErrInvalidToken
+	}
+
+	ctx := context.Background()
+	r, err := s.client.Get(ctx, token).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return time.Time{}, domain.ErrNotFound
+		}
+		return time.Time{}, err
+	}
+
+	var data sessionData
+	if err = json.Unmarshal([]byte(r), &data); err != nil {
+		return time.Time{}, err
+	}
+	if at.Sub(data.LastSeenAt) < domain.LastSeenPrecision {
+		return time.Time{}, nil
+	}
+
+	var ttl time.Duration = redis.KeepTTL
+	expiresAt := domain.SlideExpiry(data.ExpiresAt, data.Deadline, at, idle)
+	if !expiresAt.IsZero() {
+		data.ExpiresAt = expiresAt
+		ttl = expiresAt.Sub(at)
+	}
+	data.LastSeenAt = at
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err = s.client.SetXX(ctx, token, jsonData, ttl).Err(); err != nil {
+		return time.Time{}, err
+	}
+
+	return expiresAt, nil
+}
+
+func userSessionsKey(userID int) string {
+	return userSessionsKeyPrefix + strconv.Itoa(userID)
+}
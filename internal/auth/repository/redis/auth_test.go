@@ -2,15 +2,41 @@ package redis_test
 
 import (
 	"encoding/json"
+	"errors"
 	"github.com/ellexo2456/FilmLib/internal/auth/repository/redis"
+	"github.com/ellexo2456/FilmLib/internal/auth/repository/sessiontest"
 	"github.com/ellexo2456/FilmLib/internal/domain"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/go-redis/redismock/v9"
+	goredis "github.com/redis/go-redis/v9"
 )
 
+// storedSession mirrors what the repository keeps under the token.
+type storedSession struct {
+	domain.SessionContext
+	UserAgent  string
+	IP         string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	ExpiresAt  time.Time
+	Deadline   time.Time
+}
+
+func marshalSession(t *testing.T, s storedSession) string {
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
 func TestAdd(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -25,6 +51,9 @@ func TestAdd(t *testing.T) {
 				ExpiresAt: time.Now().Add(24 * time.Hour),
 				UserID:    1,
 				Role:      domain.Usr,
+				Client:    domain.Client{UserAgent: "curl/8.0", IP: "10.0.0.1"},
+				CreatedAt: time.Now(),
+				Deadline:  time.Now().Add(7 * 24 * time.Hour),
 			},
 			good: true,
 			err:  nil,
@@ -52,11 +81,29 @@ func TestAdd(t *testing.T) {
 			r := redis.NewSessionRedisRepository(db)
 
 			if test.good {
-				jsonData, _ := json.Marshal(domain.SessionContext{
-					UserID: test.session.UserID,
-					Role:   test.session.Role,
+				jsonData, _ := json.Marshal(storedSession{
+					SessionContext: domain.SessionContext{
+						UserID: test.session.UserID,
+						Role:   test.session.Role,
+					},
+					UserAgent:  test.session.Client.UserAgent,
+					IP:         test.session.Client.IP,
+					CreatedAt:  test.session.CreatedAt,
+					LastSeenAt: test.session.CreatedAt,
+					ExpiresAt:  test.session.ExpiresAt,
+					Deadline:   test.session.Deadline,
 				})
+				indexTTL := test.session.ExpiresAt.Sub(time.Now())
+				if !test.session.Deadline.IsZero() {
+					indexTTL = test.session.Deadline.Sub(time.Now())
+				}
+				mock.MatchExpectationsInOrder(true)
+				mock.ExpectTxPipeline()
 				mock.ExpectSet(test.session.Token, jsonData, test.session.ExpiresAt.Sub(time.Now())).SetVal("")
+				mock.ExpectSAdd("user_sessions:1", test.session.Token).SetVal(1)
+				mock.ExpectExpireNX("user_sessions:1", indexTTL).SetVal(true)
+				mock.ExpectExpireGT("user_sessions:1", indexTTL).SetVal(false)
+				mock.ExpectTxPipelineExec()
 			}
 
 			err := r.Add(test.session)
@@ -115,3 +162,284 @@ func TestDeleteByToken(t *testing.T) {
 		})
 	}
 }
+
+func TestDeleteByUserID(t *testing.T) {
+	tests := []struct {
+		name   string
+		userID int
+		tokens []string
+		err    error
+	}{
+		{
+			name:   "GoodCase/Common",
+			userID: 1,
+			tokens: []string{"a", "b"},
+		},
+		{
+			name:   "GoodCase/NoSessions",
+			userID: 2,
+			tokens: []string{},
+		},
+		{
+			name:   "BadCase/RedisError",
+			userID: 3,
+			err:    errors.New("some redis error"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+			defer db.Close()
+			r := redis.NewSessionRedisRepository(db)
+
+			userKey := "user_sessions:" + strconv.Itoa(test.userID)
+			if test.err != nil {
+				mock.ExpectSMembers(userKey).SetErr(test.err)
+			} else {
+				mock.ExpectSMembers(userKey).SetVal(test.tokens)
+				mock.ExpectDel(append(test.tokens, userKey)...).SetVal(int64(len(test.tokens)))
+			}
+
+			err := r.DeleteByUserID(test.userID)
+
+			assert.Equal(t, test.err, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetByUserID(t *testing.T) {
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	older := storedSession{
+		SessionContext: domain.SessionContext{UserID: 1},
+		UserAgent:      "curl/8.0",
+		IP:             "10.0.0.1",
+		CreatedAt:      created,
+		LastSeenAt:     created.Add(time.Hour),
+		ExpiresAt:      created.Add(24 * time.Hour),
+	}
+	newer := older
+	newer.CreatedAt = created.Add(2 * time.Hour)
+
+	tests := []struct {
+		name            string
+		setExpectations func(mock redismock.ClientMock)
+		ids             []string
+		err             error
+	}{
+		{
+			name: "GoodCase/Common",
+			setExpectations: func(mock redismock.ClientMock) {
+				mock.ExpectSMembers("user_sessions:1").SetVal([]string{"a", "b"})
+				mock.ExpectMGet("a", "b").SetVal([]interface{}{marshalSession(t, older), marshalSession(t, newer)})
+			},
+			ids: []string{domain.SessionID("b"), domain.SessionID("a")},
+		},
+		{
+			name: "GoodCase/ExpiredRemoved",
+			setExpectations: func(mock redismock.ClientMock) {
+				mock.ExpectSMembers("user_sessions:1").SetVal([]string{"a", "gone"})
+				mock.ExpectMGet("a", "gone").SetVal([]interface{}{marshalSession(t, older), nil})
+				mock.ExpectSRem("user_sessions:1", "gone").SetVal(1)
+			},
+			ids: []string{domain.SessionID("a")},
+		},
+		{
+			name: "GoodCase/NoSessions",
+			setExpectations: func(mock redismock.ClientMock) {
+				mock.ExpectSMembers("user_sessions:1").SetVal([]string{})
+			},
+			ids: []string{},
+		},
+		{
+			name: "BadCase/RedisError",
+			setExpectations: func(mock redismock.ClientMock) {
+				mock.ExpectSMembers("user_sessions:1").SetErr(errors.New("some redis error"))
+			},
+			err: errors.New("some redis error"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+			defer db.Close()
+			r := redis.NewSessionRedisRepository(db)
+			test.setExpectations(mock)
+
+			sessions, err := r.GetByUserID(1)
+
+			assert.Equal(t, test.err, err)
+			if test.err == nil {
+				ids := make([]string, 0, len(sessions))
+				for _, s := range sessions {
+					ids = append(ids, s.ID)
+					assert.Equal(t, "curl/8.0", s.UserAgent)
+					assert.Equal(t, older.LastSeenAt, s.LastSeenAt)
+				}
+				assert.Equal(t, test.ids, ids)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDeleteByID(t *testing.T) {
+	tests := []struct {
+		name            string
+		id              string
+		setExpectations func(mock redismock.ClientMock)
+		err             error
+	}{
+		{
+			name: "GoodCase/Common",
+			id:   domain.SessionID("b"),
+			setExpectations: func(mock redismock.ClientMock) {
+				mock.ExpectSMembers("user_sessions:1").SetVal([]string{"a", "b"})
+				mock.ExpectTxPipeline()
+				mock.ExpectDel("b").SetVal(1)
+				mock.ExpectSRem("user_sessions:1", "b").SetVal(1)
+				mock.ExpectTxPipelineExec()
+			},
+		},
+		{
+			name: "BadCase/OtherUser",
+			id:   domain.SessionID("c"),
+			setExpectations: func(mock redismock.ClientMock) {
+				mock.ExpectSMembers("user_sessions:1").SetVal([]string{"a", "b"})
+			},
+			err: domain.ErrNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+			defer db.Close()
+			mock.MatchExpectationsInOrder(true)
+			r := redis.NewSessionRedisRepository(db)
+			test.setExpectations(mock)
+
+			err := r.DeleteByID(1, test.id)
+
+			assert.ErrorIs(t, err, test.err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTouch(t *testing.T) {
+	lastSeen := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	stored := storedSession{
+		SessionContext: domain.SessionContext{UserID: 1},
+		CreatedAt:      lastSeen,
+		LastSeenAt:     lastSeen,
+		ExpiresAt:      lastSeen.Add(time.Hour),
+		Deadline:       lastSeen.Add(24 * time.Hour),
+	}
+
+	at := lastSeen.Add(5 * time.Minute)
+	extended := stored
+	extended.LastSeenAt = at
+	extended.ExpiresAt = at.Add(time.Hour)
+
+	nearDeadline := stored
+	nearDeadline.Deadline = lastSeen.Add(time.Hour + 2*time.Minute)
+	capped := nearDeadline
+	capped.LastSeenAt = at
+	capped.ExpiresAt = nearDeadline.Deadline
+
+	legacy := stored
+	legacy.Deadline = time.Time{}
+	legacyTouched := legacy
+	legacyTouched.LastSeenAt = at
+
+	tests := []struct {
+		name            string
+		at              time.Time
+		setExpectations func(mock redismock.ClientMock)
+		expiresAt       time.Time
+		err             error
+	}{
+		{
+			name: "GoodCase/Extended",
+			at:   at,
+			setExpectations: func(mock redismock.ClientMock) {
+				mock.ExpectGet("token").SetVal(marshalSession(t, stored))
+				mock.ExpectSetXX("token", []byte(marshalSession(t, extended)), time.Hour).SetVal(true)
+			},
+			expiresAt: extended.ExpiresAt,
+		},
+		{
+			name: "GoodCase/CappedByDeadline",
+			at:   at,
+			setExpectations: func(mock redismock.ClientMock) {
+				mock.ExpectGet("token").SetVal(marshalSession(t, nearDeadline))
+				mock.ExpectSetXX("token", []byte(marshalSession(t, capped)), capped.ExpiresAt.Sub(at)).SetVal(true)
+			},
+			expiresAt: nearDeadline.Deadline,
+		},
+		{
+			name: "GoodCase/NoDeadline",
+			at:   at,
+			setExpectations: func(mock redismock.ClientMock) {
+				mock.ExpectGet("token").SetVal(marshalSession(t, legacy))
+				mock.ExpectSetXX("token", []byte(marshalSession(t, legacyTouched)), goredis.KeepTTL).SetVal(true)
+			},
+		},
+		{
+			name: "GoodCase/RecentlySeen",
+			at:   lastSeen.Add(10 * time.Second),
+			setExpectations: func(mock redismock.ClientMock) {
+				mock.ExpectGet("token").SetVal(marshalSession(t, stored))
+			},
+		},
+		{
+			name: "BadCase/NotFound",
+			at:   lastSeen,
+			setExpectations: func(mock redismock.ClientMock) {
+				mock.ExpectGet("token").RedisNil()
+			},
+			err: domain.ErrNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+			defer db.Close()
+			r := redis.NewSessionRedisRepository(db)
+			test.setExpectations(mock)
+
+			expiresAt, err := r.Touch("token", test.at, time.Hour)
+
+			if test.err == nil {
+				require.NoError(t, err)
+				assert.Equal(t, test.expiresAt, expiresAt)
+			} else {
+				assert.ErrorIs(t, err, test.err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestSessionContract needs a Redis which can be written to, for
+// example TEST_REDIS_ADDR=localhost:6379
+func TestSessionContract(t *testing.T) {
+	addr := os.Getenv("TEST_REDIS_ADDR")
+	if addr == "" {
+		t.Skip("TEST_REDIS_ADDR is not set")
+	}
+
+	client := goredis.NewClient(&goredis.Options{Addr: addr})
+	defer client.Close()
+
+	sessiontest.Run(t, func(t *testing.T) domain.SessionRepository {
+		r := redis.NewSessionRedisRepository(client)
+		require.NoError(t, r.DeleteByUserID(sessiontest.UserID))
+		require.NoError(t, r.DeleteByUserID(sessiontest.OtherUserID))
+		return r
+	}, time.Sleep)
+}
@@ -0,0 +1,71 @@
+package redis
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+)
+
+const mfaChallengeKeyPrefix = "mfa_challenge:"
+
+type mfaChallengeRedisRepository struct {
+	client *redis.Client
+}
+
+func NewMFAChallengeRedisRepository(client *redis.Client) domain.MFAChallengeRepository {
+	return &mfaChallengeRedisRepository{client}
+}
+
+func (r *mfaChallengeRedisRepository) Add(token string, userID int, ttl time.Duration) error {
+	if token == "" {
+		return domain.ErrInvalidToken
+	}
+
+	return r.client.Set(context.Background(), mfaChallengeKey(token), userID, ttl).Err()
+}
+
+func (r *mfaChallengeRedisRepository) Get(token string) (int, error) {
+	if token == "" {
+		return 0, domain.ErrInvalidToken
+	}
+
+	return parseUserID(r.client.Get(context.Background(), mfaChallengeKey(token)).Result())
+}
+
+// Pop removes the challenge, so every challenge allows one attempt
+// to enter the code.
+func (r *mfaChallengeRedisRepository) Pop(token string) (int, error) {
+	if token == "" {
+		return 0, domain.ErrInvalidToken
+	}
+
+	return parseUserID(r.client.GetDel(context.Background(), mfaChallengeKey(token)).Result())
+}
+
+func parseUserID(res string, err error) (int, error) {
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, domain.ErrInvalidToken
+		}
+		return 0, err
+	}
+
+	userID, err := strconv.Atoi(res)
+	if err != nil {
+		return 0, domain.ErrInvalidToken
+	}
+
+	return userID, nil
+}
+
+func mfaChallengeKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return mfaChallengeKeyPrefix + hex.EncodeToString(sum[:])
+}
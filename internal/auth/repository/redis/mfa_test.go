@@ -0,0 +1,87 @@
+package redis_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ellexo2456/FilmLib/internal/auth/repository/redis"
+	"github.com/ellexo2456/FilmLib/internal/domain"
+)
+
+func mfaChallengeKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "mfa_challenge:" + hex.EncodeToString(sum[:])
+}
+
+func TestMFAChallengeAdd(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	defer db.Close()
+	r := redis.NewMFAChallengeRedisRepository(db)
+
+	mock.ExpectSet(mfaChallengeKey("abc"), 1, time.Minute).SetVal("OK")
+
+	assert.NoError(t, r.Add("abc", 1, time.Minute))
+	assert.ErrorIs(t, r.Add("", 1, time.Minute), domain.ErrInvalidToken)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMFAChallengePop(t *testing.T) {
+	tests := []struct {
+		name      string
+		token     string
+		setExpect func(mock redismock.ClientMock, key string)
+		userID    int
+		err       error
+	}{
+		{
+			name:  "GoodCase/Common",
+			token: "abc",
+			setExpect: func(mock redismock.ClientMock, key string) {
+				mock.ExpectGetDel(key).SetVal("7")
+			},
+			userID: 7,
+		},
+		{
+			name:  "BadCase/Unknown",
+			token: "abc",
+			setExpect: func(mock redismock.ClientMock, key string) {
+				mock.ExpectGetDel(key).RedisNil()
+			},
+			err: domain.ErrInvalidToken,
+		},
+		{
+			name:  "BadCase/RedisError",
+			token: "abc",
+			setExpect: func(mock redismock.ClientMock, key string) {
+				mock.ExpectGetDel(key).SetErr(errors.New("some error"))
+			},
+			err: errors.New("some error"),
+		},
+		{
+			name:      "BadCase/EmptyToken",
+			setExpect: func(mock redismock.ClientMock, key string) {},
+			err:       domain.ErrInvalidToken,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+			defer db.Close()
+			r := redis.NewMFAChallengeRedisRepository(db)
+			test.setExpect(mock, mfaChallengeKey(test.token))
+
+			userID, err := r.Pop(test.token)
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.userID, userID)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+)
+
+const oidcStateKeyPrefix = "oidc_state:"
+
+type oidcStateRedisRepository struct {
+	client *redis.Client
+}
+
+func NewOIDCStateRedisRepository(client *redis.Client) domain.OIDCStateRepository {
+	return &oidcStateRedisRepository{client}
+}
+
+func (r *oidcStateRedisRepository) Add(state string, s domain.OIDCState, ttl time.Duration) error {
+	if state == "" {
+		return domain.ErrInvalidToken
+	}
+
+	jsonData, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	return r.client.Set(context.Background(), oidcStateKeyPrefix+state, jsonData, ttl).Err()
+}
+
+// Pop removes the state, so a callback can`t be replayed.
+func (r *oidcStateRedisRepository) Pop(state string) (domain.OIDCState, error) {
+	if state == "" {
+		return domain.OIDCState{}, domain.ErrInvalidToken
+	}
+
+	res, err := r.client.GetDel(context.Background(), oidcStateKeyPrefix+state).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return domain.OIDCState{}, domain.ErrInvalidToken
+		}
+		return domain.OIDCState{}, err
+	}
+
+	var s domain.OIDCState
+	if err = json.Unmarshal([]byte(res), &s); err != nil {
+		return domain.OIDCState{}, domain.ErrInvalidToken
+	}
+
+	return s, nil
+}
@@ -0,0 +1,119 @@
+package redis_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ellexo2456/FilmLib/internal/auth/repository/redis"
+	"github.com/ellexo2456/FilmLib/internal/domain"
+)
+
+func TestOIDCStateAdd(t *testing.T) {
+	s := domain.OIDCState{Provider: "fake", Nonce: "n", Verifier: "v"}
+	data, _ := json.Marshal(s)
+
+	tests := []struct {
+		name  string
+		state string
+		good  bool
+		err   error
+	}{
+		{
+			name:  "GoodCase/Common",
+			state: "abc",
+			good:  true,
+		},
+		{
+			name: "BadCase/EmptyState",
+			err:  domain.ErrInvalidToken,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+			defer db.Close()
+			r := redis.NewOIDCStateRedisRepository(db)
+
+			if test.good {
+				mock.ExpectSet("oidc_state:"+test.state, data, time.Minute).SetVal("OK")
+			}
+
+			err := r.Add(test.state, s, time.Minute)
+
+			if test.good {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, test.err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestOIDCStatePop(t *testing.T) {
+	s := domain.OIDCState{Provider: "fake", Nonce: "n", Verifier: "v"}
+	data, _ := json.Marshal(s)
+
+	tests := []struct {
+		name      string
+		state     string
+		setExpect func(mock redismock.ClientMock)
+		result    domain.OIDCState
+		err       error
+	}{
+		{
+			name:  "GoodCase/Common",
+			state: "abc",
+			setExpect: func(mock redismock.ClientMock) {
+				mock.ExpectGetDel("oidc_state:abc").SetVal(string(data))
+			},
+			result: s,
+		},
+		{
+			name:  "BadCase/UsedOrExpired",
+			state: "abc",
+			setExpect: func(mock redismock.ClientMock) {
+				mock.ExpectGetDel("oidc_state:abc").RedisNil()
+			},
+			err: domain.ErrInvalidToken,
+		},
+		{
+			name:  "BadCase/RedisError",
+			state: "abc",
+			setExpect: func(mock redismock.ClientMock) {
+				mock.ExpectGetDel("oidc_state:abc").SetErr(errors.New("some redis error"))
+			},
+			err: errors.New("some redis error"),
+		},
+		{
+			name:      "BadCase/EmptyState",
+			setExpect: func(mock redismock.ClientMock) {},
+			err:       domain.ErrInvalidToken,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+			defer db.Close()
+			r := redis.NewOIDCStateRedisRepository(db)
+			test.setExpect(mock)
+
+			result, err := r.Pop(test.state)
+
+			if test.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.err.Error())
+			}
+			assert.Equal(t, test.result, result)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package redis
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+)
+
+const (
+	refreshKeyPrefix        = "refresh_token:"
+	userRefreshTokensPrefix = "user_refresh_tokens:"
+)
+
+type refreshTokenRedisRepository struct {
+	client *redis.Client
+}
+
+func NewRefreshTokenRedisRepository(client *redis.Client) domain.RefreshTokenRepository {
+	return &refreshTokenRedisRepository{client}
+}
+
+func (r *refreshTokenRedisRepository) Add(token string, userID int, ttl time.Duration) error {
+	if token == "" {
+		return domain.ErrInvalidToken
+	}
+
+	key := refreshKey(token)
+	userKey := userRefreshTokensKey(userID)
+	_, err := r.client.TxPipelined(context.TODO(), func(pipe redis.Pipeliner) error {
+		pipe.Set(context.TODO(), key, userID, ttl)
+		pipe.SAdd(context.TODO(), userKey, key)
+		pipe.Expire(context.TODO(), userKey, ttl)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Pop returns the owner of the token and removes it, so a refresh
+// token can be exchanged only once.
+func (r *refreshTokenRedisRepository) Pop(token string) (int, error) {
+	if token == "" {
+		return 0, domain.ErrInvalidToken
+	}
+
+	key := refreshKey(token)
+	res, err := r.client.GetDel(context.Background(), key).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, domain.ErrInvalidToken
+		}
+		return 0, err
+	}
+
+	userID, err := strconv.Atoi(res)
+	if err != nil {
+		return 0, domain.ErrInvalidToken
+	}
+
+	// a stale index entry is harmless, so the error is ignored
+	r.client.SRem(context.Background(), userRefreshTokensKey(userID), key)
+
+	return userID, nil
+}
+
+func (r *refreshTokenRedisRepository) DeleteByUserID(userID int) error {
+	userKey := userRefreshTokensKey(userID)
+	keys, err := r.client.SMembers(context.Background(), userKey).Result()
+	if err != nil {
+		return err
+	}
+
+	err = r.client.Del(context.Background(), append(keys, userKey)...).Err()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func refreshKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return refreshKeyPrefix + hex.EncodeToString(sum[:])
+}
+
+func userRefreshTokensKey(userID int) string {
+	return userRefreshTokensPrefix + strconv.Itoa(userID)
+}
@@ -0,0 +1,167 @@
+package redis_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ellexo2456/FilmLib/internal/auth/repository/redis"
+	"github.com/ellexo2456/FilmLib/internal/domain"
+)
+
+func refreshKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "refresh_token:" + hex.EncodeToString(sum[:])
+}
+
+func TestRefreshAdd(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+		good  bool
+		err   error
+	}{
+		{
+			name:  "GoodCase/Common",
+			token: "abc",
+			good:  true,
+		},
+		{
+			name: "BadCase/EmptyToken",
+			err:  domain.ErrInvalidToken,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+			defer db.Close()
+			r := redis.NewRefreshTokenRedisRepository(db)
+
+			if test.good {
+				key := refreshKey(test.token)
+				mock.ExpectTxPipeline()
+				mock.ExpectSet(key, 1, time.Hour).SetVal("OK")
+				mock.ExpectSAdd("user_refresh_tokens:1", key).SetVal(1)
+				mock.ExpectExpire("user_refresh_tokens:1", time.Hour).SetVal(true)
+				mock.ExpectTxPipelineExec()
+			}
+
+			err := r.Add(test.token, 1, time.Hour)
+
+			if test.good {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, test.err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRefreshPop(t *testing.T) {
+	tests := []struct {
+		name      string
+		token     string
+		setExpect func(mock redismock.ClientMock, key string)
+		userID    int
+		err       error
+	}{
+		{
+			name:  "GoodCase/Common",
+			token: "abc",
+			setExpect: func(mock redismock.ClientMock, key string) {
+				mock.ExpectGetDel(key).SetVal("7")
+				mock.ExpectSRem("user_refresh_tokens:7", key).SetVal(1)
+			},
+			userID: 7,
+		},
+		{
+			name:  "BadCase/UsedOrExpired",
+			token: "abc",
+			setExpect: func(mock redismock.ClientMock, key string) {
+				mock.ExpectGetDel(key).RedisNil()
+			},
+			err: domain.ErrInvalidToken,
+		},
+		{
+			name:  "BadCase/RedisError",
+			token: "abc",
+			setExpect: func(mock redismock.ClientMock, key string) {
+				mock.ExpectGetDel(key).SetErr(errors.New("some redis error"))
+			},
+			err: errors.New("some redis error"),
+		},
+		{
+			name:      "BadCase/EmptyToken",
+			setExpect: func(mock redismock.ClientMock, key string) {},
+			err:       domain.ErrInvalidToken,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+			defer db.Close()
+			r := redis.NewRefreshTokenRedisRepository(db)
+			test.setExpect(mock, refreshKey(test.token))
+
+			userID, err := r.Pop(test.token)
+
+			if test.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.err.Error())
+			}
+			assert.Equal(t, test.userID, userID)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRefreshDeleteByUserID(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []string
+		err  error
+	}{
+		{
+			name: "GoodCase/Common",
+			keys: []string{refreshKey("a"), refreshKey("b")},
+		},
+		{
+			name: "BadCase/RedisError",
+			err:  errors.New("some redis error"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+			defer db.Close()
+			r := redis.NewRefreshTokenRedisRepository(db)
+
+			userKey := "user_refresh_tokens:3"
+			if test.err != nil {
+				mock.ExpectSMembers(userKey).SetErr(test.err)
+			} else {
+				mock.ExpectSMembers(userKey).SetVal(test.keys)
+				mock.ExpectDel(append(test.keys, userKey)...).SetVal(int64(len(test.keys)))
+			}
+
+			err := r.DeleteByUserID(3)
+
+			if test.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.err.Error())
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package redis
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+)
+
+const (
+	rememberKeyPrefix          = "remember_token:"
+	rememberFamilyKeyPrefix    = "remember_family:"
+	userRememberFamiliesPrefix = "user_remember_families:"
+)
+
+type rememberTokenRedisRepository struct {
+	client *redis.Client
+}
+
+func NewRememberTokenRedisRepository(client *redis.Client) domain.RememberTokenRepository {
+	return &rememberTokenRedisRepository{client}
+}
+
+// Add stores the token in a hash with its owner and family. The family
+// and user indexes get the ttl of the newest token.
+func (r *rememberTokenRedisRepository) Add(token string, rt domain.RememberToken, ttl time.Duration) error {
+	if token == "" || rt.Family == "" {
+		return domain.ErrInvalidToken
+	}
+
+	ctx := context.Background()
+	key := rememberKey(token)
+	familyKey := rememberFamilyKeyPrefix + rt.Family
+	userKey := userRememberFamiliesKey(rt.UserID)
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, "user", rt.UserID, "family", rt.Family)
+		pipe.Expire(ctx, key, ttl)
+		pipe.SAdd(ctx, familyKey, key)
+		pipe.Expire(ctx, familyKey, ttl)
+		pipe.SAdd(ctx, userKey, rt.Family)
+		pipe.Expire(ctx, userKey, ttl)
+		return nil
+	})
+
+	return err
+}
+
+// Use marks the token as used. A used token is kept until it expires,
+// so presenting it again returns domain.ErrTokenReused along with the
+// family to revoke.
+func (r *rememberTokenRedisRepository) Use(token string) (domain.RememberToken, error) {
+	if token == "" {
+		return domain.RememberToken{}, domain.ErrInvalidToken
+	}
+
+	ctx := context.Background()
+	key := rememberKey(token)
+	fields, err := r.client.HGetAll(ctx, key).Result()
+	if err != nil {
+		return domain.RememberToken{}, err
+	}
+	if len(fields) == 0 {
+		return domain.RememberToken{}, domain.ErrInvalidToken
+	}
+
+	userID, err := strconv.Atoi(fields["user"])
+	if err != nil || fields["family"] == "" {
+		return domain.RememberToken{}, domain.ErrInvalidToken
+	}
+	rt := domain.RememberToken{UserID: userID, Family: fields["family"]}
+
+	first, err := r.client.HSetNX(ctx, key, "used", 1).Result()
+	if err != nil {
+		return domain.RememberToken{}, err
+	}
+	if !first {
+		return rt, domain.ErrTokenReused
+	}
+
+	return rt, nil
+}
+
+func (r *rememberTokenRedisRepository) DeleteFamily(family string) error {
+	if family == "" {
+		return domain.ErrInvalidToken
+	}
+
+	familyKey := rememberFamilyKeyPrefix + family
+	keys, err := r.client.SMembers(context.Background(), familyKey).Result()
+	if err != nil {
+		return err
+	}
+
+	return r.client.Del(context.Background(), append(keys, familyKey)...).Err()
+}
+
+// DeleteByUserID revokes every family of the user. The user index
+// isn`t cleaned when a single family is revoked, a stale entry is harmless.
+func (r *rememberTokenRedisRepository) DeleteByUserID(userID int) error {
+	ctx := context.Background()
+	userKey := userRememberFamiliesKey(userID)
+	families, err := r.client.SMembers(ctx, userKey).Result()
+	if err != nil {
+		return err
+	}
+
+	keys := []string{userKey}
+	for _, family := range families {
+		familyKey := rememberFamilyKeyPrefix + family
+		tokens, err := r.client.SMembers(ctx, familyKey).Result()
+		if err != nil {
+			return err
+		}
+		keys = append(keys, familyKey)
+		keys = append(keys, tokens...)
+	}
+
+	return r.client.Del(ctx, keys...).Err()
+}
+
+func rememberKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return rememberKeyPrefix + hex.EncodeToString(sum[:])
+}
+
+func userRememberFamiliesKey(userID int) string {
+	return userRememberFamiliesPrefix + strconv.Itoa(userID)
+}
@@ -0,0 +1,132 @@
+package redis_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ellexo2456/FilmLib/internal/auth/repository/redis"
+	"github.com/ellexo2456/FilmLib/internal/domain"
+)
+
+func rememberKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "remember_token:" + hex.EncodeToString(sum[:])
+}
+
+func TestRememberAdd(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	defer db.Close()
+	mock.MatchExpectationsInOrder(true)
+	r := redis.NewRememberTokenRedisRepository(db)
+
+	key := rememberKey("abc")
+	mock.ExpectTxPipeline()
+	mock.ExpectHSet(key, "user", 1, "family", "f").SetVal(2)
+	mock.ExpectExpire(key, time.Hour).SetVal(true)
+	mock.ExpectSAdd("remember_family:f", key).SetVal(1)
+	mock.ExpectExpire("remember_family:f", time.Hour).SetVal(true)
+	mock.ExpectSAdd("user_remember_families:1", "f").SetVal(1)
+	mock.ExpectExpire("user_remember_families:1", time.Hour).SetVal(true)
+	mock.ExpectTxPipelineExec()
+
+	assert.NoError(t, r.Add("abc", domain.RememberToken{UserID: 1, Family: "f"}, time.Hour))
+	assert.ErrorIs(t, r.Add("", domain.RememberToken{UserID: 1, Family: "f"}, time.Hour), domain.ErrInvalidToken)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRememberUse(t *testing.T) {
+	key := rememberKey("abc")
+	fields := map[string]string{"user": "1", "family": "f"}
+
+	tests := []struct {
+		name            string
+		setExpectations func(mock redismock.ClientMock)
+		rt              domain.RememberToken
+		err             error
+	}{
+		{
+			name: "GoodCase/Common",
+			setExpectations: func(mock redismock.ClientMock) {
+				mock.ExpectHGetAll(key).SetVal(fields)
+				mock.ExpectHSetNX(key, "used", 1).SetVal(true)
+			},
+			rt: domain.RememberToken{UserID: 1, Family: "f"},
+		},
+		{
+			name: "BadCase/Reused",
+			setExpectations: func(mock redismock.ClientMock) {
+				mock.ExpectHGetAll(key).SetVal(fields)
+				mock.ExpectHSetNX(key, "used", 1).SetVal(false)
+			},
+			rt:  domain.RememberToken{UserID: 1, Family: "f"},
+			err: domain.ErrTokenReused,
+		},
+		{
+			name: "BadCase/Expired",
+			setExpectations: func(mock redismock.ClientMock) {
+				mock.ExpectHGetAll(key).SetVal(map[string]string{})
+			},
+			err: domain.ErrInvalidToken,
+		},
+		{
+			name: "BadCase/RedisError",
+			setExpectations: func(mock redismock.ClientMock) {
+				mock.ExpectHGetAll(key).SetErr(errors.New("some redis error"))
+			},
+			err: errors.New("some redis error"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+			defer db.Close()
+			r := redis.NewRememberTokenRedisRepository(db)
+			test.setExpectations(mock)
+
+			rt, err := r.Use("abc")
+
+			if test.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.err.Error())
+			}
+			assert.Equal(t, test.rt, rt)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRememberDeleteFamily(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	defer db.Close()
+	r := redis.NewRememberTokenRedisRepository(db)
+
+	mock.ExpectSMembers("remember_family:f").SetVal([]string{"remember_token:a", "remember_token:b"})
+	mock.ExpectDel("remember_token:a", "remember_token:b", "remember_family:f").SetVal(3)
+
+	assert.NoError(t, r.DeleteFamily("f"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRememberDeleteByUserID(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	defer db.Close()
+	mock.MatchExpectationsInOrder(true)
+	r := redis.NewRememberTokenRedisRepository(db)
+
+	mock.ExpectSMembers("user_remember_families:1").SetVal([]string{"f", "g"})
+	mock.ExpectSMembers("remember_family:f").SetVal([]string{"remember_token:a"})
+	mock.ExpectSMembers("remember_family:g").SetVal([]string{"remember_token:b"})
+	mock.ExpectDel("user_remember_families:1", "remember_family:f", "remember_token:a",
+		"remember_family:g", "remember_token:b").SetVal(5)
+
+	assert.NoError(t, r.DeleteByUserID(1))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,62 @@
+package redis
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+)
+
+const resetKeyPrefix = "password_reset:"
+
+type resetTokenRedisRepository struct {
+	client *redis.Client
+}
+
+func NewResetTokenRedisRepository(client *redis.Client) domain.ResetTokenRepository {
+	return &resetTokenRedisRepository{client}
+}
+
+func (r *resetTokenRedisRepository) Add(token string, userID int, ttl time.Duration) error {
+	if token == "" {
+		return domain.ErrInvalidToken
+	}
+
+	return r.client.Set(context.Background(), resetKey(token), userID, ttl).Err()
+}
+
+// Pop returns the owner of the token and removes it, so every token
+// can be used only once.
+func (r *resetTokenRedisRepository) Pop(token string) (int, error) {
+	if token == "" {
+		return 0, domain.ErrInvalidToken
+	}
+
+	res, err := r.client.GetDel(context.Background(), resetKey(token)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, domain.ErrInvalidToken
+		}
+		return 0, err
+	}
+
+	userID, err := strconv.Atoi(res)
+	if err != nil {
+		return 0, domain.ErrInvalidToken
+	}
+
+	return userID, nil
+}
+
+// resetKey stores only a hash of the token, so a leaked dump of redis
+// can`t be used to reset passwords.
+func resetKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return resetKeyPrefix + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,119 @@
+package redis_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ellexo2456/FilmLib/internal/auth/repository/redis"
+	"github.com/ellexo2456/FilmLib/internal/domain"
+)
+
+func resetKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "password_reset:" + hex.EncodeToString(sum[:])
+}
+
+func TestResetAdd(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+		good  bool
+		err   error
+	}{
+		{
+			name:  "GoodCase/Common",
+			token: "abc",
+			good:  true,
+		},
+		{
+			name: "BadCase/EmptyToken",
+			err:  domain.ErrInvalidToken,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+			defer db.Close()
+			r := redis.NewResetTokenRedisRepository(db)
+
+			if test.good {
+				mock.ExpectSet(resetKey(test.token), 1, time.Hour).SetVal("OK")
+			}
+
+			err := r.Add(test.token, 1, time.Hour)
+
+			if test.good {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, test.err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestResetPop(t *testing.T) {
+	tests := []struct {
+		name      string
+		token     string
+		setExpect func(mock redismock.ClientMock, key string)
+		userID    int
+		err       error
+	}{
+		{
+			name:  "GoodCase/Common",
+			token: "abc",
+			setExpect: func(mock redismock.ClientMock, key string) {
+				mock.ExpectGetDel(key).SetVal("7")
+			},
+			userID: 7,
+		},
+		{
+			name:  "BadCase/UsedOrExpired",
+			token: "abc",
+			setExpect: func(mock redismock.ClientMock, key string) {
+				mock.ExpectGetDel(key).RedisNil()
+			},
+			err: domain.ErrInvalidToken,
+		},
+		{
+			name:  "BadCase/RedisError",
+			token: "abc",
+			setExpect: func(mock redismock.ClientMock, key string) {
+				mock.ExpectGetDel(key).SetErr(errors.New("some redis error"))
+			},
+			err: errors.New("some redis error"),
+		},
+		{
+			name:      "BadCase/EmptyToken",
+			setExpect: func(mock redismock.ClientMock, key string) {},
+			err:       domain.ErrInvalidToken,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+			defer db.Close()
+			r := redis.NewResetTokenRedisRepository(db)
+			test.setExpect(mock, resetKey(test.token))
+
+			userID, err := r.Pop(test.token)
+
+			if test.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.err.Error())
+			}
+			assert.Equal(t, test.userID, userID)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,220 @@
+// Package sessiontest is the contract every domain.SessionRepository
+// implementation is tested against.
+package sessiontest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+)
+
+// Sessions of these users are created by the suite, stores with a
+// foreign key on the user must have them.
+const (
+	UserID      = 1
+	OtherUserID = 2
+)
+
+// timestamps may lose precision in the store
+const precision = time.Millisecond
+
+// Run runs the suite. newRepo must return a store without sessions of
+// UserID and OtherUserID, wait has to let d pass for the store.
+func Run(t *testing.T, newRepo func(t *testing.T) domain.SessionRepository, wait func(d time.Duration)) {
+	t.Run("AddAndGet", func(t *testing.T) {
+		r := newRepo(t)
+		s := newSession(UserID, time.Hour)
+		s.Role = domain.Moder
+		s.Verified = true
+		require.NoError(t, r.Add(s))
+
+		sc, err := r.GetSessionContext(s.Token)
+		require.NoError(t, err)
+		assert.Equal(t, UserID, sc.UserID)
+		assert.Equal(t, domain.Moder, sc.Role)
+		assert.True(t, sc.Verified)
+	})
+
+	t.Run("EmptyToken", func(t *testing.T) {
+		r := newRepo(t)
+
+		assert.ErrorIs(t, r.Add(domain.Session{UserID: UserID}), domain.ErrInvalidToken)
+		_, err := r.GetSessionContext("")
+		assert.ErrorIs(t, err, domain.ErrInvalidToken)
+		assert.ErrorIs(t, r.DeleteByToken(""), domain.ErrInvalidToken)
+		_, err = r.Touch("", time.Now(), time.Hour)
+		assert.ErrorIs(t, err, domain.ErrInvalidToken)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		r := newRepo(t)
+
+		_, err := r.GetSessionContext(uuid.NewString())
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		_, err = r.Touch(uuid.NewString(), time.Now(), time.Hour)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.ErrorIs(t, r.DeleteByID(UserID, domain.SessionID(uuid.NewString())), domain.ErrNotFound)
+	})
+
+	t.Run("DeleteByToken", func(t *testing.T) {
+		r := newRepo(t)
+		s := newSession(UserID, time.Hour)
+		require.NoError(t, r.Add(s))
+
+		require.NoError(t, r.DeleteByToken(s.Token))
+		_, err := r.GetSessionContext(s.Token)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		// deleting twice is fine
+		assert.NoError(t, r.DeleteByToken(s.Token))
+	})
+
+	t.Run("Expiry", func(t *testing.T) {
+		r := newRepo(t)
+		s := newSession(UserID, time.Second)
+		require.NoError(t, r.Add(s))
+
+		wait(1500 * time.Millisecond)
+
+		_, err := r.GetSessionContext(s.Token)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		sessions, err := r.GetByUserID(UserID)
+		require.NoError(t, err)
+		assert.Empty(t, sessions)
+	})
+
+	t.Run("GetByUserID", func(t *testing.T) {
+		r := newRepo(t)
+		older := newSession(UserID, time.Hour)
+		older.CreatedAt = older.CreatedAt.Add(-time.Minute)
+		older.Client = domain.Client{UserAgent: "curl", IP: "10.0.0.1"}
+		newer := newSession(UserID, time.Hour)
+		other := newSession(OtherUserID, time.Hour)
+		for _, s := range []domain.Session{older, newer, other} {
+			require.NoError(t, r.Add(s))
+		}
+
+		sessions, err := r.GetByUserID(UserID)
+		require.NoError(t, err)
+		require.Len(t, sessions, 2)
+		assert.Equal(t, domain.SessionID(newer.Token), sessions[0].ID)
+		assert.Equal(t, domain.SessionID(older.Token), sessions[1].ID)
+		assert.Equal(t, "curl", sessions[1].UserAgent)
+		assert.Equal(t, "10.0.0.1", sessions[1].IP)
+		assert.WithinDuration(t, older.CreatedAt, sessions[1].CreatedAt, precision)
+		assert.WithinDuration(t, older.CreatedAt, sessions[1].LastSeenAt, precision)
+		assert.WithinDuration(t, older.ExpiresAt, sessions[1].ExpiresAt, precision)
+
+		sessions, err = r.GetByUserID(999)
+		require.NoError(t, err)
+		assert.NotNil(t, sessions)
+		assert.Empty(t, sessions)
+	})
+
+	t.Run("DeleteByID", func(t *testing.T) {
+		r := newRepo(t)
+		s := newSession(UserID, time.Hour)
+		kept := newSession(UserID, time.Hour)
+		require.NoError(t, r.Add(s))
+		require.NoError(t, r.Add(kept))
+
+		// only the owner can delete it
+		assert.ErrorIs(t, r.DeleteByID(OtherUserID, domain.SessionID(s.Token)), domain.ErrNotFound)
+		require.NoError(t, r.DeleteByID(UserID, domain.SessionID(s.Token)))
+
+		_, err := r.GetSessionContext(s.Token)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		_, err = r.GetSessionContext(kept.Token)
+		assert.NoError(t, err)
+	})
+
+	t.Run("DeleteByUserID", func(t *testing.T) {
+		r := newRepo(t)
+		first := newSession(UserID, time.Hour)
+		second := newSession(UserID, time.Hour)
+		other := newSession(OtherUserID, time.Hour)
+		for _, s := range []domain.Session{first, second, other} {
+			require.NoError(t, r.Add(s))
+		}
+
+		require.NoError(t, r.DeleteByUserID(UserID))
+
+		for _, s := range []domain.Session{first, second} {
+			_, err := r.GetSessionContext(s.Token)
+			assert.ErrorIs(t, err, domain.ErrNotFound)
+		}
+		_, err := r.GetSessionContext(other.Token)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Touch", func(t *testing.T) {
+		r := newRepo(t)
+		s := newSession(UserID, time.Hour)
+		require.NoError(t, r.Add(s))
+
+		// too soon after the last one
+		expiresAt, err := r.Touch(s.Token, s.CreatedAt.Add(time.Second), time.Hour)
+		require.NoError(t, err)
+		assert.True(t, expiresAt.IsZero())
+
+		at := s.CreatedAt.Add(2 * time.Minute)
+		expiresAt, err = r.Touch(s.Token, at, time.Hour)
+		require.NoError(t, err)
+		assert.WithinDuration(t, at.Add(time.Hour), expiresAt, precision)
+
+		sessions, err := r.GetByUserID(UserID)
+		require.NoError(t, err)
+		require.Len(t, sessions, 1)
+		assert.WithinDuration(t, at, sessions[0].LastSeenAt, precision)
+		assert.WithinDuration(t, at.Add(time.Hour), sessions[0].ExpiresAt, precision)
+	})
+
+	t.Run("TouchDeadline", func(t *testing.T) {
+		r := newRepo(t)
+		s := newSession(UserID, time.Hour)
+		s.Deadline = s.CreatedAt.Add(90 * time.Minute)
+		require.NoError(t, r.Add(s))
+
+		expiresAt, err := r.Touch(s.Token, s.CreatedAt.Add(2*time.Minute), 2*time.Hour)
+		require.NoError(t, err)
+		assert.WithinDuration(t, s.Deadline, expiresAt, precision)
+
+		// nothing to extend any more, only the last seen time moves
+		at := s.CreatedAt.Add(5 * time.Minute)
+		expiresAt, err = r.Touch(s.Token, at, 2*time.Hour)
+		require.NoError(t, err)
+		assert.True(t, expiresAt.IsZero())
+
+		sessions, err := r.GetByUserID(UserID)
+		require.NoError(t, err)
+		require.Len(t, sessions, 1)
+		assert.WithinDuration(t, at, sessions[0].LastSeenAt, precision)
+	})
+
+	t.Run("TouchWithoutDeadline", func(t *testing.T) {
+		r := newRepo(t)
+		s := newSession(UserID, time.Hour)
+		s.Deadline = time.Time{}
+		require.NoError(t, r.Add(s))
+
+		expiresAt, err := r.Touch(s.Token, s.CreatedAt.Add(2*time.Minute), 2*time.Hour)
+		require.NoError(t, err)
+		assert.True(t, expiresAt.IsZero())
+	})
+}
+
+func newSession(userID int, ttl time.Duration) domain.Session {
+	now := time.Now()
+	return domain.Session{
+		Token:     uuid.NewString(),
+		UserID:    userID,
+		Role:      domain.Usr,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+		Deadline:  now.Add(24 * time.Hour),
+	}
+}
@@ -1,55 +1,63 @@
 package usecase
 
 import (
-	"bytes"
-	"crypto/rand"
+	"errors"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/uuid"
-	"golang.org/x/crypto/argon2"
 
 	"github.com/ellexo2456/FilmLib/internal/domain"
 	logs "github.com/ellexo2456/FilmLib/internal/logger"
 )
 
 type authUsecase struct {
-	authRepo    domain.AuthRepository
-	sessionRepo domain.SessionRepository
+	authRepo     domain.AuthRepository
+	sessionRepo  domain.SessionRepository
+	verification domain.VerificationUsecase
+	twoFactor    domain.TwoFactorUsecase
+	rememberRepo domain.RememberTokenRepository
+	policy       domain.SessionPolicy
 }
 
-func NewAuthUsecase(ar domain.AuthRepository, sr domain.SessionRepository) domain.AuthUsecase {
+func NewAuthUsecase(ar domain.AuthRepository, sr domain.SessionRepository, vu domain.VerificationUsecase,
+	tfu domain.TwoFactorUsecase, rmr domain.RememberTokenRepository, policy domain.SessionPolicy) domain.AuthUsecase {
 	return &authUsecase{
-		authRepo:    ar,
-		sessionRepo: sr,
+		authRepo:     ar,
+		sessionRepo:  sr,
+		verification: vu,
+		twoFactor:    tfu,
+		rememberRepo: rmr,
+		policy:       policy,
 	}
 }
 
-func (u *authUsecase) Login(credentials domain.Credentials) (domain.Session, int, error) {
-	expectedUser, err := u.authRepo.GetByEmail(credentials.Email)
+// Login remembers the user only once the session is started, a user
+// with 2FA asks for it again at the second step.
+func (u *authUsecase) Login(credentials domain.Credentials, client domain.Client) (domain.Session, int, error) {
+	expectedUser, err := authenticate(u.authRepo, credentials)
 	if err != nil {
 		return domain.Session{}, 0, err
 	}
-	logs.Logger.Debug("Usecase Login expected user:", expectedUser)
 
-	if !checkPasswords(expectedUser.Password, credentials.Password) {
-		return domain.Session{}, 0, domain.ErrWrongCredentials
+	session, err := loginSession(u.twoFactor, u.sessionRepo, u.policy, expectedUser, client)
+	if err != nil {
+		return domain.Session{}, 0, err
 	}
 
-	session := domain.Session{
-		Token:     uuid.NewString(),
-		ExpiresAt: time.Now().Add(24 * time.Hour),
-		UserID:    expectedUser.ID,
-		Role:      expectedUser.Role,
-	}
-	if err = u.sessionRepo.Add(session); err != nil {
-		return domain.Session{}, 0, err
+	if credentials.RememberMe && !session.MFARequired {
+		session, err = rememberSession(u.rememberRepo, u.policy, session, "")
+		if err != nil {
+			return domain.Session{}, 0, err
+		}
 	}
 
 	return session, expectedUser.ID, nil
 }
 
-func (u *authUsecase) Logout(token string) error {
+// Logout also revokes the remember-me family of the device, otherwise
+// the next request would resume the session.
+func (u *authUsecase) Logout(token, rememberToken string) error {
 	if token == "" {
 		return domain.ErrInvalidToken
 	}
@@ -58,7 +66,59 @@ func (u *authUsecase) Logout(token string) error {
 		return err
 	}
 
-	return nil
+	if rememberToken == "" {
+		return nil
+	}
+
+	rt, err := u.rememberRepo.Use(rememberToken)
+	if err != nil && !errors.Is(err, domain.ErrTokenReused) {
+		// an expired token has nothing to revoke
+		if errors.Is(err, domain.ErrInvalidToken) {
+			return nil
+		}
+		return err
+	}
+
+	return u.rememberRepo.DeleteFamily(rt.Family)
+}
+
+// Resume starts a new session with a remember-me token and replaces the
+// token with the next one. A token presented twice means that someone
+// else holds a copy, so the whole family is revoked.
+func (u *authUsecase) Resume(rememberToken string, client domain.Client) (domain.Session, error) {
+	if rememberToken == "" {
+		return domain.Session{}, domain.ErrInvalidToken
+	}
+
+	rt, err := u.rememberRepo.Use(rememberToken)
+	if errors.Is(err, domain.ErrTokenReused) {
+		logs.LogError(logs.Logger, "auth/usecase", "Resume", err, "remember-me token is reused, revoking the family")
+		if err := u.rememberRepo.DeleteFamily(rt.Family); err != nil {
+			return domain.Session{}, err
+		}
+		return domain.Session{}, domain.ErrTokenReused
+	}
+	if err != nil {
+		return domain.Session{}, err
+	}
+
+	user, err := u.authRepo.GetByID(rt.UserID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.Session{}, domain.ErrInvalidToken
+	}
+	if err != nil {
+		return domain.Session{}, err
+	}
+	if user.Disabled {
+		return domain.Session{}, domain.ErrDisabled
+	}
+
+	session, err := startSession(u.sessionRepo, u.policy, user, client)
+	if err != nil {
+		return domain.Session{}, err
+	}
+
+	return rememberSession(u.rememberRepo, u.policy, session, rt.Family)
 }
 
 func (u *authUsecase) Register(user domain.User) (int, error) {
@@ -74,16 +134,26 @@ func (u *authUsecase) Register(user domain.User) (int, error) {
 		return 0, err
 	}
 
-	salt := make([]byte, 8)
-	rand.Read(salt)
-	user.Password = HashPassword(salt, user.Password)
+	user.Password, err = HashPassword(user.Password)
+	if err != nil {
+		logs.LogError(logs.Logger, "auth/usecase", "Register", err, "failed to hash the password")
+		return 0, err
+	}
 
 	user.Role = domain.Usr
+	user.Verified = false
 	id, err := u.authRepo.AddUser(user)
 	if err != nil {
 		return 0, err
 	}
 
+	// the account is already created, so a failed mail isn`t fatal:
+	// the user can ask to resend it
+	user.ID = id
+	if err = u.verification.Send(user); err != nil {
+		logs.LogError(logs.Logger, "auth/usecase", "Register", err, "failed to send verification mail")
+	}
+
 	return id, nil
 
 }
@@ -99,17 +169,88 @@ func (u *authUsecase) RetrieveSessionContext(token string) (domain.SessionContex
 		return domain.SessionContext{}, err
 	}
 
+	// a failed update leaves the session to expire a bit earlier,
+	// that doesn`t fail the request
+	expiresAt, err := u.sessionRepo.Touch(token, time.Now(), u.policy.IdleTimeout)
+	if err != nil {
+		logs.LogError(logs.Logger, "auth/usecase", "RetrieveSessionContext", err, "failed to touch the session")
+	}
+	auth.ExpiresAt = expiresAt
+
 	return auth, nil
 }
 
-func HashPassword(salt []byte, password []byte) []byte {
-	hashedPass := argon2.IDKey(password, salt, 1, 64*1024, 4, 32)
-	return append(salt, hashedPass...)
+// authenticate is shared by the session and the token logins.
+func authenticate(ar domain.AuthRepository, credentials domain.Credentials) (domain.User, error) {
+	expectedUser, err := ar.GetByEmail(credentials.Email)
+	if err != nil {
+		return domain.User{}, err
+	}
+	logs.Logger.Debug("Usecase Login expected user:", expectedUser)
+
+	ok, rehash := checkPassword(expectedUser.Password, credentials.Password)
+	if !ok {
+		return domain.User{}, domain.ErrWrongCredentials
+	}
+	if expectedUser.Disabled {
+		return domain.User{}, domain.ErrDisabled
+	}
+
+	// the plain password is only known here, so outdated hashes are
+	// replaced on login. A failure keeps the old hash until the next one.
+	if rehash {
+		if hash, err := HashPassword(credentials.Password); err != nil {
+			logs.LogError(logs.Logger, "auth/usecase", "authenticate", err, "failed to rehash the password")
+		} else if err = ar.UpdatePassword(expectedUser.ID, hash); err != nil {
+			logs.LogError(logs.Logger, "auth/usecase", "authenticate", err, "failed to update the password hash")
+		}
+	}
+
+	return expectedUser, nil
 }
 
-func checkPasswords(passHash []byte, plainPassword []byte) bool {
-	salt := make([]byte, 8)
-	_ = copy(salt, passHash)
-	userPassHash := HashPassword(salt, plainPassword)
-	return bytes.Equal(userPassHash, passHash)
+func startSession(sr domain.SessionRepository, policy domain.SessionPolicy, user domain.User,
+	client domain.Client) (domain.Session, error) {
+	now := time.Now()
+	session := domain.Session{
+		Token:     uuid.NewString(),
+		ExpiresAt: now.Add(policy.IdleTimeout),
+		UserID:    user.ID,
+		Role:      user.Role,
+		Verified:  user.Verified,
+		Client:    client,
+		CreatedAt: now,
+		Deadline:  now.Add(policy.AbsoluteTimeout),
+	}
+	if session.ExpiresAt.After(session.Deadline) {
+		session.ExpiresAt = session.Deadline
+	}
+	if err := sr.Add(session); err != nil {
+		return domain.Session{}, err
+	}
+
+	return session, nil
+}
+
+// rememberSession issues a remember-me token of the family, a new family
+// is started if it is empty.
+func rememberSession(rmr domain.RememberTokenRepository, policy domain.SessionPolicy, session domain.Session,
+	family string) (domain.Session, error) {
+	token, err := newToken()
+	if err != nil {
+		logs.LogError(logs.Logger, "auth/usecase", "rememberSession", err, err.Error())
+		return domain.Session{}, err
+	}
+	if family == "" {
+		family = uuid.NewString()
+	}
+
+	rt := domain.RememberToken{UserID: session.UserID, Family: family}
+	if err = rmr.Add(token, rt, policy.RememberTTL); err != nil {
+		return domain.Session{}, err
+	}
+
+	session.RememberToken = token
+	session.RememberExpiresAt = time.Now().Add(policy.RememberTTL)
+	return session, nil
 }
@@ -1,12 +1,15 @@
 package usecase_test
 
 import (
+	"bytes"
 	"crypto/rand"
+	"encoding/base64"
 	"errors"
 	"github.com/ellexo2456/FilmLib/internal/auth/usecase"
 	"github.com/ellexo2456/FilmLib/internal/domain"
 	"github.com/ellexo2456/FilmLib/internal/domain/mocks"
 	"testing"
+	"time"
 
 	"github.com/bxcodec/faker"
 	"github.com/google/uuid"
@@ -15,6 +18,31 @@ import (
 	"golang.org/x/crypto/argon2"
 )
 
+var sessionPolicy = domain.SessionPolicy{
+	IdleTimeout:     time.Hour,
+	AbsoluteTimeout: 24 * time.Hour,
+	RememberTTL:     30 * 24 * time.Hour,
+}
+
+func hashPassword(t *testing.T, password []byte) []byte {
+	hash, err := usecase.HashPassword(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return hash
+}
+
+func TestHashPassword(t *testing.T) {
+	first := hashPassword(t, []byte("password"))
+	second := hashPassword(t, []byte("password"))
+
+	assert.True(t, bytes.HasPrefix(first, []byte("$argon2id$v=19$m=65536,t=3,p=4$")))
+	assert.Len(t, bytes.Split(first, []byte("$")), 6)
+	// the salt is random
+	assert.NotEqual(t, first, second)
+}
+
 func TestLogin(t *testing.T) {
 	salt := make([]byte, 8)
 	rand.Read(salt)
@@ -35,15 +63,89 @@ func TestLogin(t *testing.T) {
 			setAuRepoExpectations: func(creds domain.Credentials, auRepo *mocks.AuthRepository, user *domain.User) {
 				faker.FakeData(user)
 				user.Email = creds.Email
+				user.Disabled = false
+				user.Password, _ = usecase.HashPassword([]byte{123})
+				auRepo.On("GetByEmail", mock.Anything).Return(*user, nil)
+			},
+			setSessionRepoExpectations: func(sessionRepo *mocks.SessionRepository) {
+				sessionRepo.On("Add", mock.Anything).Return(nil)
+			},
+			good: true,
+		},
+		{
+			name: "GoodCase/LegacyHash",
+			creds: domain.Credentials{
+				Email:    "uvybini@mail.ru",
+				Password: []byte{123},
+			},
+			setAuRepoExpectations: func(creds domain.Credentials, auRepo *mocks.AuthRepository, user *domain.User) {
+				faker.FakeData(user)
+				user.Email = creds.Email
+				user.Disabled = false
 				hashedPass := argon2.IDKey([]byte{123}, salt, 1, 64*1024, 4, 32)
 				user.Password = append(salt, hashedPass...)
 				auRepo.On("GetByEmail", mock.Anything).Return(*user, nil)
+				auRepo.On("UpdatePassword", user.ID, mock.MatchedBy(func(hash []byte) bool {
+					return bytes.HasPrefix(hash, []byte("$argon2id$v=19$m=65536,t=3,p=4$"))
+				})).Return(nil)
+			},
+			setSessionRepoExpectations: func(sessionRepo *mocks.SessionRepository) {
+				sessionRepo.On("Add", mock.Anything).Return(nil)
+			},
+			good: true,
+		},
+		{
+			name: "GoodCase/OutdatedParams",
+			creds: domain.Credentials{
+				Email:    "uvybini@mail.ru",
+				Password: []byte{123},
+			},
+			setAuRepoExpectations: func(creds domain.Credentials, auRepo *mocks.AuthRepository, user *domain.User) {
+				faker.FakeData(user)
+				user.Email = creds.Email
+				user.Disabled = false
+				hashedPass := argon2.IDKey([]byte{123}, salt, 2, 32*1024, 2, 16)
+				user.Password = []byte("$argon2id$v=19$m=32768,t=2,p=2$" + base64.RawStdEncoding.EncodeToString(salt) +
+					"$" + base64.RawStdEncoding.EncodeToString(hashedPass))
+				auRepo.On("GetByEmail", mock.Anything).Return(*user, nil)
+				// a failed update doesn`t fail the login
+				auRepo.On("UpdatePassword", user.ID, mock.Anything).Return(errors.New("some db error"))
 			},
 			setSessionRepoExpectations: func(sessionRepo *mocks.SessionRepository) {
 				sessionRepo.On("Add", mock.Anything).Return(nil)
 			},
 			good: true,
 		},
+		{
+			name: "BadCase/MalformedHash",
+			creds: domain.Credentials{
+				Email:    "uvybini@mail.ru",
+				Password: []byte{123},
+			},
+			setAuRepoExpectations: func(creds domain.Credentials, auRepo *mocks.AuthRepository, user *domain.User) {
+				faker.FakeData(user)
+				user.Email = creds.Email
+				user.Password = []byte("$argon2id$v=19$m=65536,t=3,p=4$c2FsdA")
+				auRepo.On("GetByEmail", mock.Anything).Return(*user, nil)
+			},
+			setSessionRepoExpectations: func(sessionRepo *mocks.SessionRepository) {},
+		},
+		{
+			name: "BadCase/Disabled",
+			creds: domain.Credentials{
+				Email:    "uvybini@mail.ru",
+				Password: []byte{123},
+			},
+			setAuRepoExpectations: func(creds domain.Credentials, auRepo *mocks.AuthRepository, user *domain.User) {
+				faker.FakeData(user)
+				user.Email = creds.Email
+				user.Disabled = true
+				hashedPass := argon2.IDKey([]byte{123}, salt, 1, 64*1024, 4, 32)
+				user.Password = append(salt, hashedPass...)
+				auRepo.On("GetByEmail", mock.Anything).Return(*user, nil)
+			},
+			setSessionRepoExpectations: func(sessionRepo *mocks.SessionRepository) {},
+		},
 		{
 			name: "BadCase/UserNotFound",
 			creds: domain.Credentials{
@@ -88,9 +190,11 @@ func TestLogin(t *testing.T) {
 				faker.FakeData(user)
 				user.ID = -1
 				user.Email = creds.Email
+				user.Disabled = false
 				hashedPass := argon2.IDKey(creds.Password, salt, 1, 64*1024, 4, 32)
 				user.Password = append(salt, hashedPass...)
 				auRepo.On("GetByEmail", mock.Anything).Return(*user, nil)
+				auRepo.On("UpdatePassword", -1, mock.Anything).Return(nil)
 			},
 			setSessionRepoExpectations: func(sessionRepo *mocks.SessionRepository) {
 				sessionRepo.On("Add", mock.Anything).Return(errors.New("another db error"))
@@ -108,8 +212,11 @@ func TestLogin(t *testing.T) {
 			test.setAuRepoExpectations(test.creds, ar, &user)
 			test.setSessionRepoExpectations(sr)
 
-			auCase := usecase.NewAuthUsecase(ar, sr)
-			session, id, err := auCase.Login(test.creds)
+			vu := new(mocks.VerificationUsecase)
+			tfu := new(mocks.TwoFactorUsecase)
+			tfu.On("Required", mock.Anything).Return(false, nil).Maybe()
+			auCase := usecase.NewAuthUsecase(ar, sr, vu, tfu, new(mocks.RememberTokenRepository), sessionPolicy)
+			session, id, err := auCase.Login(test.creds, domain.Client{})
 
 			if test.good {
 				assert.Nil(t, err)
@@ -129,10 +236,12 @@ func TestLogin(t *testing.T) {
 
 func TestLogout(t *testing.T) {
 	tests := []struct {
-		name                       string
-		token                      string
-		setSessionRepoExpectations func(sessionRepo *mocks.SessionRepository)
-		good                       bool
+		name                        string
+		token                       string
+		rememberToken               string
+		setSessionRepoExpectations  func(sessionRepo *mocks.SessionRepository)
+		setRememberRepoExpectations func(rememberRepo *mocks.RememberTokenRepository)
+		good                        bool
 	}{
 		{
 			name:  "GoodCase/Common",
@@ -142,6 +251,31 @@ func TestLogout(t *testing.T) {
 			},
 			good: true,
 		},
+		{
+			name:          "GoodCase/WithRememberToken",
+			token:         uuid.NewString(),
+			rememberToken: "remember",
+			setSessionRepoExpectations: func(sessionRepo *mocks.SessionRepository) {
+				sessionRepo.On("DeleteByToken", mock.Anything).Return(nil)
+			},
+			setRememberRepoExpectations: func(rememberRepo *mocks.RememberTokenRepository) {
+				rememberRepo.On("Use", "remember").Return(domain.RememberToken{UserID: 1, Family: "f"}, nil)
+				rememberRepo.On("DeleteFamily", "f").Return(nil)
+			},
+			good: true,
+		},
+		{
+			name:          "GoodCase/ExpiredRememberToken",
+			token:         uuid.NewString(),
+			rememberToken: "remember",
+			setSessionRepoExpectations: func(sessionRepo *mocks.SessionRepository) {
+				sessionRepo.On("DeleteByToken", mock.Anything).Return(nil)
+			},
+			setRememberRepoExpectations: func(rememberRepo *mocks.RememberTokenRepository) {
+				rememberRepo.On("Use", "remember").Return(domain.RememberToken{}, domain.ErrInvalidToken)
+			},
+			good: true,
+		},
 		{
 			name:  "BadCase/EmptyToken",
 			token: "",
@@ -164,10 +298,17 @@ func TestLogout(t *testing.T) {
 
 			ar := new(mocks.AuthRepository)
 			sr := new(mocks.SessionRepository)
+			rmr := new(mocks.RememberTokenRepository)
 			test.setSessionRepoExpectations(sr)
+			if test.setRememberRepoExpectations != nil {
+				test.setRememberRepoExpectations(rmr)
+			}
 
-			auCase := usecase.NewAuthUsecase(ar, sr)
-			err := auCase.Logout(test.token)
+			vu := new(mocks.VerificationUsecase)
+			tfu := new(mocks.TwoFactorUsecase)
+			tfu.On("Required", mock.Anything).Return(false, nil).Maybe()
+			auCase := usecase.NewAuthUsecase(ar, sr, vu, tfu, rmr, sessionPolicy)
+			err := auCase.Logout(test.token, test.rememberToken)
 
 			if test.good {
 				assert.Nil(t, err)
@@ -176,6 +317,7 @@ func TestLogout(t *testing.T) {
 			}
 
 			sr.AssertExpectations(t)
+			rmr.AssertExpectations(t)
 		})
 	}
 }
@@ -186,6 +328,7 @@ func TestRegister(t *testing.T) {
 		id                          int
 		getUser                     func() domain.User
 		setUserAuthRepoExpectations func(ar *mocks.AuthRepository, id int)
+		setVerificationExpectations func(vu *mocks.VerificationUsecase)
 		good                        bool
 	}{
 		{
@@ -197,10 +340,35 @@ func TestRegister(t *testing.T) {
 				return user
 			},
 			id: 10,
+			setUserAuthRepoExpectations: func(ar *mocks.AuthRepository, id int) {
+				ar.On("UserExists", mock.Anything).Return(false, nil)
+				ar.On("AddUser", mock.MatchedBy(func(user domain.User) bool {
+					return !user.Verified && user.Role == domain.Usr
+				})).Return(id, nil)
+			},
+			setVerificationExpectations: func(vu *mocks.VerificationUsecase) {
+				vu.On("Send", mock.MatchedBy(func(user domain.User) bool {
+					return user.ID == 10 && user.Email == "uvybini@mail.ru"
+				})).Return(nil)
+			},
+			good: true,
+		},
+		{
+			name: "GoodCase/MailerDown",
+			getUser: func() domain.User {
+				var user domain.User
+				faker.FakeData(&user)
+				user.Email = "uvybini@mail.ru"
+				return user
+			},
+			id: 10,
 			setUserAuthRepoExpectations: func(ar *mocks.AuthRepository, id int) {
 				ar.On("UserExists", mock.Anything).Return(false, nil)
 				ar.On("AddUser", mock.Anything).Return(id, nil)
 			},
+			setVerificationExpectations: func(vu *mocks.VerificationUsecase) {
+				vu.On("Send", mock.Anything).Return(errors.New("smtp is down"))
+			},
 			good: true,
 		},
 		{
@@ -266,8 +434,12 @@ func TestRegister(t *testing.T) {
 			ar := new(mocks.AuthRepository)
 			sr := new(mocks.SessionRepository)
 			test.setUserAuthRepoExpectations(ar, test.id)
+			vu := new(mocks.VerificationUsecase)
+			if test.setVerificationExpectations != nil {
+				test.setVerificationExpectations(vu)
+			}
 
-			auCase := usecase.NewAuthUsecase(ar, sr)
+			auCase := usecase.NewAuthUsecase(ar, sr, vu, new(mocks.TwoFactorUsecase), new(mocks.RememberTokenRepository), sessionPolicy)
 			id, err := auCase.Register(test.getUser())
 
 			if test.good {
@@ -279,6 +451,7 @@ func TestRegister(t *testing.T) {
 			}
 
 			ar.AssertExpectations(t)
+			vu.AssertExpectations(t)
 		})
 	}
 }
@@ -296,6 +469,34 @@ func TestRetrieveSessionContext(t *testing.T) {
 			token: "valid_token",
 			setSessionRepoExpectations: func(sessionRepo *mocks.SessionRepository, sessionContext domain.SessionContext, err error) {
 				sessionRepo.On("GetSessionContext", "valid_token").Return(sessionContext, err)
+				sessionRepo.On("Touch", "valid_token", mock.AnythingOfType("time.Time"), sessionPolicy.IdleTimeout).
+					Return(time.Time{}, nil)
+			},
+			expectedSessionContext: domain.SessionContext{
+				UserID: 1,
+				Role:   domain.Usr,
+			},
+			expectedError: nil,
+		},
+		{
+			name:  "GoodCase/Extended",
+			token: "valid_token",
+			setSessionRepoExpectations: func(sessionRepo *mocks.SessionRepository, sessionContext domain.SessionContext, err error) {
+				sessionRepo.On("GetSessionContext", "valid_token").Return(domain.SessionContext{UserID: 1}, err)
+				sessionRepo.On("Touch", "valid_token", mock.Anything, sessionPolicy.IdleTimeout).Return(sessionContext.ExpiresAt, nil)
+			},
+			expectedSessionContext: domain.SessionContext{
+				UserID:    1,
+				ExpiresAt: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+			},
+			expectedError: nil,
+		},
+		{
+			name:  "GoodCase/TouchFailed",
+			token: "valid_token",
+			setSessionRepoExpectations: func(sessionRepo *mocks.SessionRepository, sessionContext domain.SessionContext, err error) {
+				sessionRepo.On("GetSessionContext", "valid_token").Return(sessionContext, err)
+				sessionRepo.On("Touch", "valid_token", mock.Anything, mock.Anything).Return(time.Time{}, errors.New("some db error"))
 			},
 			expectedSessionContext: domain.SessionContext{
 				UserID: 1,
@@ -329,7 +530,8 @@ func TestRetrieveSessionContext(t *testing.T) {
 			ar := new(mocks.AuthRepository)
 			test.setSessionRepoExpectations(sr, test.expectedSessionContext, test.expectedError)
 
-			authUsecase := usecase.NewAuthUsecase(ar, sr)
+			vu := new(mocks.VerificationUsecase)
+			authUsecase := usecase.NewAuthUsecase(ar, sr, vu, new(mocks.TwoFactorUsecase), new(mocks.RememberTokenRepository), sessionPolicy)
 			sessionContext, err := authUsecase.RetrieveSessionContext(test.token)
 
 			assert.Equal(t, test.expectedSessionContext, sessionContext)
@@ -339,3 +541,124 @@ func TestRetrieveSessionContext(t *testing.T) {
 		})
 	}
 }
+
+func TestLoginTwoFactor(t *testing.T) {
+	user := domain.User{
+		ID:       1,
+		Email:    "uvybini@mail.ru",
+		Password: hashPassword(t, []byte{123}),
+		Role:     domain.Moder,
+	}
+
+	ar := new(mocks.AuthRepository)
+	sr := new(mocks.SessionRepository)
+	tfu := new(mocks.TwoFactorUsecase)
+	ar.On("GetByEmail", user.Email).Return(user, nil)
+	tfu.On("Required", user).Return(true, nil)
+	tfu.On("Challenge", user).Return(domain.Session{Token: "mfa", UserID: 1, MFARequired: true}, nil)
+
+	session, id, err := usecase.NewAuthUsecase(ar, sr, new(mocks.VerificationUsecase), tfu, new(mocks.RememberTokenRepository), sessionPolicy).
+		Login(domain.Credentials{Email: user.Email, Password: []byte{123}}, domain.Client{})
+
+	assert.Nil(t, err)
+	assert.True(t, session.MFARequired)
+	assert.Equal(t, 1, id)
+	// no session is started until the second step
+	sr.AssertNotCalled(t, "Add", mock.Anything)
+	tfu.AssertExpectations(t)
+}
+
+func TestLoginRememberMe(t *testing.T) {
+	user := domain.User{
+		ID:       1,
+		Email:    "uvybini@mail.ru",
+		Password: hashPassword(t, []byte{123}),
+	}
+
+	ar := new(mocks.AuthRepository)
+	sr := new(mocks.SessionRepository)
+	rmr := new(mocks.RememberTokenRepository)
+	tfu := new(mocks.TwoFactorUsecase)
+	ar.On("GetByEmail", user.Email).Return(user, nil)
+	tfu.On("Required", user).Return(false, nil)
+	sr.On("Add", mock.MatchedBy(func(s domain.Session) bool {
+		return !s.ExpiresAt.After(s.Deadline) && s.Deadline.Sub(s.CreatedAt) == sessionPolicy.AbsoluteTimeout
+	})).Return(nil)
+	rmr.On("Add", mock.AnythingOfType("string"), mock.MatchedBy(func(rt domain.RememberToken) bool {
+		return rt.UserID == 1 && rt.Family != ""
+	}), sessionPolicy.RememberTTL).Return(nil)
+
+	session, _, err := usecase.NewAuthUsecase(ar, sr, new(mocks.VerificationUsecase), tfu, rmr, sessionPolicy).
+		Login(domain.Credentials{Email: user.Email, Password: []byte{123}, RememberMe: true}, domain.Client{})
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, session.RememberToken)
+	assert.True(t, session.RememberExpiresAt.After(session.ExpiresAt))
+	sr.AssertExpectations(t)
+	rmr.AssertExpectations(t)
+}
+
+func TestResume(t *testing.T) {
+	rt := domain.RememberToken{UserID: 1, Family: "f"}
+
+	tests := []struct {
+		name            string
+		setExpectations func(ar *mocks.AuthRepository, sr *mocks.SessionRepository, rmr *mocks.RememberTokenRepository)
+		err             error
+	}{
+		{
+			name: "GoodCase/Common",
+			setExpectations: func(ar *mocks.AuthRepository, sr *mocks.SessionRepository, rmr *mocks.RememberTokenRepository) {
+				rmr.On("Use", "remember").Return(rt, nil)
+				ar.On("GetByID", 1).Return(domain.User{ID: 1, Verified: true}, nil)
+				sr.On("Add", mock.Anything).Return(nil)
+				rmr.On("Add", mock.AnythingOfType("string"), rt, sessionPolicy.RememberTTL).Return(nil)
+			},
+		},
+		{
+			name: "BadCase/Reused",
+			setExpectations: func(ar *mocks.AuthRepository, sr *mocks.SessionRepository, rmr *mocks.RememberTokenRepository) {
+				rmr.On("Use", "remember").Return(rt, domain.ErrTokenReused)
+				rmr.On("DeleteFamily", "f").Return(nil)
+			},
+			err: domain.ErrTokenReused,
+		},
+		{
+			name: "BadCase/Expired",
+			setExpectations: func(ar *mocks.AuthRepository, sr *mocks.SessionRepository, rmr *mocks.RememberTokenRepository) {
+				rmr.On("Use", "remember").Return(domain.RememberToken{}, domain.ErrInvalidToken)
+			},
+			err: domain.ErrInvalidToken,
+		},
+		{
+			name: "BadCase/Disabled",
+			setExpectations: func(ar *mocks.AuthRepository, sr *mocks.SessionRepository, rmr *mocks.RememberTokenRepository) {
+				rmr.On("Use", "remember").Return(rt, nil)
+				ar.On("GetByID", 1).Return(domain.User{ID: 1, Disabled: true}, nil)
+			},
+			err: domain.ErrDisabled,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ar := new(mocks.AuthRepository)
+			sr := new(mocks.SessionRepository)
+			rmr := new(mocks.RememberTokenRepository)
+			test.setExpectations(ar, sr, rmr)
+
+			session, err := usecase.NewAuthUsecase(ar, sr, new(mocks.VerificationUsecase), new(mocks.TwoFactorUsecase),
+				rmr, sessionPolicy).Resume("remember", domain.Client{})
+
+			assert.ErrorIs(t, err, test.err)
+			if test.err == nil {
+				assert.Equal(t, 1, session.UserID)
+				assert.NotEmpty(t, session.RememberToken)
+				assert.NotEqual(t, "remember", session.RememberToken)
+			}
+			ar.AssertExpectations(t)
+			sr.AssertExpectations(t)
+			rmr.AssertExpectations(t)
+		})
+	}
+}
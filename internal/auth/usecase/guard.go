@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+)
+
+type throttledKey struct {
+	key    string
+	policy domain.ThrottlePolicy
+}
+
+type loginGuard struct {
+	attemptsRepo domain.AttemptsRepository
+	emailPolicy  domain.ThrottlePolicy
+	ipPolicy     domain.ThrottlePolicy
+	clock        domain.Clock
+}
+
+// NewLoginGuard takes separate policies because many users can share
+// an ip, so it should tolerate more failures than a single email.
+func NewLoginGuard(ar domain.AttemptsRepository, emailPolicy, ipPolicy domain.ThrottlePolicy,
+	clock domain.Clock) domain.LoginGuard {
+	return &loginGuard{
+		attemptsRepo: ar,
+		emailPolicy:  emailPolicy,
+		ipPolicy:     ipPolicy,
+		clock:        clock,
+	}
+}
+
+// Check returns ErrTooManyRequests if the email or the ip has to wait.
+// An expired lockout is lifted here.
+func (g *loginGuard) Check(email, ip string) (time.Duration, error) {
+	now := g.clock.Now()
+
+	var wait time.Duration
+	for _, k := range g.keys(email, ip) {
+		a, err := g.attemptsRepo.Get(k.key)
+		if err != nil {
+			logs.LogError(logs.Logger, "auth/usecase", "Check", err, err.Error())
+			return 0, err
+		}
+
+		w := retryAfter(k.policy, a, now)
+		if w == 0 && locked(k.policy, a) {
+			if err = g.attemptsRepo.Reset(k.key); err != nil {
+				logs.LogError(logs.Logger, "auth/usecase", "Check", err, err.Error())
+				return 0, err
+			}
+			logs.Logger.WithFields(logrus.Fields{"key": k.key}).Info("login unlocked")
+		}
+		if w > wait {
+			wait = w
+		}
+	}
+
+	if wait > 0 {
+		return wait, domain.ErrTooManyRequests
+	}
+	return 0, nil
+}
+
+func (g *loginGuard) Fail(email, ip string) (time.Duration, error) {
+	now := g.clock.Now()
+
+	var wait time.Duration
+	for _, k := range g.keys(email, ip) {
+		a, err := g.attemptsRepo.Fail(k.key, now, k.policy.Window)
+		if err != nil {
+			logs.LogError(logs.Logger, "auth/usecase", "Fail", err, err.Error())
+			return 0, err
+		}
+
+		if k.policy.LockoutAfter > 0 && a.Failures == k.policy.LockoutAfter {
+			logs.Logger.WithFields(logrus.Fields{
+				"key":      k.key,
+				"failures": a.Failures,
+				"until":    now.Add(k.policy.LockoutDuration),
+			}).Warn("login locked")
+		}
+		if w := retryAfter(k.policy, a, now); w > wait {
+			wait = w
+		}
+	}
+
+	return wait, nil
+}
+
+// Succeed forgets the failures of the email only, otherwise one valid
+// account would let an attacker reset the counter of their ip.
+func (g *loginGuard) Succeed(email, ip string) error {
+	if email == "" {
+		return nil
+	}
+
+	if err := g.attemptsRepo.Reset(emailKey(email)); err != nil {
+		logs.LogError(logs.Logger, "auth/usecase", "Succeed", err, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (g *loginGuard) keys(email, ip string) []throttledKey {
+	var keys []throttledKey
+	if email != "" {
+		keys = append(keys, throttledKey{emailKey(email), g.emailPolicy})
+	}
+	if ip != "" {
+		keys = append(keys, throttledKey{"ip:" + ip, g.ipPolicy})
+	}
+
+	return keys
+}
+
+func emailKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func locked(p domain.ThrottlePolicy, a domain.Attempts) bool {
+	return p.LockoutAfter > 0 && a.Failures >= p.LockoutAfter
+}
+
+// retryAfter is how long the key has to wait after its last failure.
+func retryAfter(p domain.ThrottlePolicy, a domain.Attempts, now time.Time) time.Duration {
+	if a.Failures < p.FreeAttempts || a.Failures == 0 {
+		return 0
+	}
+
+	var delay time.Duration
+	if locked(p, a) {
+		delay = p.LockoutDuration
+	} else {
+		delay = p.BaseDelay
+		for i := p.FreeAttempts; i < a.Failures && delay < p.MaxDelay; i++ {
+			delay *= 2
+		}
+		if delay > p.MaxDelay {
+			delay = p.MaxDelay
+		}
+	}
+
+	wait := a.LastFailure.Add(delay).Sub(now)
+	if wait < 0 {
+		return 0
+	}
+	return wait
+}
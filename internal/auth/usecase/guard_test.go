@@ -0,0 +1,153 @@
+package usecase_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ellexo2456/FilmLib/internal/auth/usecase"
+	"github.com/ellexo2456/FilmLib/internal/domain"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// memoryAttempts keeps the counters like redis does, without the ttl.
+type memoryAttempts map[string]domain.Attempts
+
+func (m memoryAttempts) Get(key string) (domain.Attempts, error) {
+	return m[key], nil
+}
+
+func (m memoryAttempts) Fail(key string, at time.Time, ttl time.Duration) (domain.Attempts, error) {
+	a := m[key]
+	a.Failures++
+	a.LastFailure = at
+	m[key] = a
+	return a, nil
+}
+
+func (m memoryAttempts) Reset(key string) error {
+	delete(m, key)
+	return nil
+}
+
+var testPolicy = domain.ThrottlePolicy{
+	FreeAttempts:    2,
+	BaseDelay:       time.Second,
+	MaxDelay:        4 * time.Second,
+	LockoutAfter:    6,
+	LockoutDuration: time.Minute,
+	Window:          time.Hour,
+}
+
+var lenientPolicy = domain.ThrottlePolicy{
+	FreeAttempts: 100,
+	LockoutAfter: 1000,
+	Window:       time.Hour,
+}
+
+func newGuard(emailPolicy, ipPolicy domain.ThrottlePolicy) (domain.LoginGuard, memoryAttempts, *fakeClock) {
+	attempts := memoryAttempts{}
+	clock := &fakeClock{now: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)}
+	return usecase.NewLoginGuard(attempts, emailPolicy, ipPolicy, clock), attempts, clock
+}
+
+func TestGuardBackoff(t *testing.T) {
+	g, _, clock := newGuard(testPolicy, lenientPolicy)
+
+	// free attempts
+	for i := 0; i < 2; i++ {
+		wait, err := g.Check("uvybini@mail.ru", "192.0.2.1")
+		require.NoError(t, err)
+		assert.Zero(t, wait)
+		_, err = g.Fail("uvybini@mail.ru", "192.0.2.1")
+		require.NoError(t, err)
+	}
+
+	// the delay doubles with every failure and is capped
+	for _, expected := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second} {
+		wait, err := g.Check("uvybini@mail.ru", "192.0.2.1")
+		assert.ErrorIs(t, err, domain.ErrTooManyRequests)
+		assert.Equal(t, expected, wait)
+
+		clock.Advance(expected - time.Millisecond)
+		_, err = g.Check("uvybini@mail.ru", "192.0.2.1")
+		assert.ErrorIs(t, err, domain.ErrTooManyRequests)
+
+		clock.Advance(time.Millisecond)
+		wait, err = g.Check("uvybini@mail.ru", "192.0.2.1")
+		require.NoError(t, err)
+		assert.Zero(t, wait)
+
+		_, err = g.Fail("uvybini@mail.ru", "192.0.2.1")
+		require.NoError(t, err)
+	}
+
+	// the other email isn`t affected
+	_, err := g.Check("other@mail.ru", "192.0.2.1")
+	assert.NoError(t, err)
+}
+
+func TestGuardLockout(t *testing.T) {
+	g, attempts, clock := newGuard(testPolicy, lenientPolicy)
+	attempts["email:uvybini@mail.ru"] = domain.Attempts{Failures: 5, LastFailure: clock.Now()}
+
+	wait, err := g.Fail("Uvybini@mail.ru ", "192.0.2.1")
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, wait)
+
+	clock.Advance(30 * time.Second)
+	wait, err = g.Check("uvybini@mail.ru", "192.0.2.2")
+	assert.ErrorIs(t, err, domain.ErrTooManyRequests)
+	assert.Equal(t, 30*time.Second, wait)
+
+	// the lockout expires and the counter starts over
+	clock.Advance(30 * time.Second)
+	wait, err = g.Check("uvybini@mail.ru", "192.0.2.2")
+	require.NoError(t, err)
+	assert.Zero(t, wait)
+	assert.NotContains(t, attempts, "email:uvybini@mail.ru")
+}
+
+func TestGuardIP(t *testing.T) {
+	g, _, _ := newGuard(lenientPolicy, testPolicy)
+
+	for _, email := range []string{"a@mail.ru", "b@mail.ru"} {
+		_, err := g.Fail(email, "192.0.2.1")
+		require.NoError(t, err)
+	}
+	wait, err := g.Fail("c@mail.ru", "192.0.2.1")
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Second, wait)
+
+	_, err = g.Check("d@mail.ru", "192.0.2.1")
+	assert.ErrorIs(t, err, domain.ErrTooManyRequests)
+	_, err = g.Check("d@mail.ru", "192.0.2.2")
+	assert.NoError(t, err)
+}
+
+func TestGuardSucceed(t *testing.T) {
+	g, attempts, _ := newGuard(testPolicy, testPolicy)
+
+	for i := 0; i < 2; i++ {
+		_, err := g.Fail("uvybini@mail.ru", "192.0.2.1")
+		require.NoError(t, err)
+	}
+	require.NoError(t, g.Succeed("uvybini@mail.ru", "192.0.2.1"))
+
+	assert.NotContains(t, attempts, "email:uvybini@mail.ru")
+	// a valid account doesn`t reset the counter of the ip
+	assert.Equal(t, 2, attempts["ip:192.0.2.1"].Failures)
+}
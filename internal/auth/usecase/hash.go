@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+type argonParams struct {
+	memory  uint32
+	time    uint32
+	threads uint8
+	saltLen int
+	keyLen  uint32
+}
+
+// hashParams are used for the new hashes. They are stored in the hash
+// itself, so they can be raised at any time: the old hashes still verify
+// and are upgraded on the next login.
+var hashParams = argonParams{
+	memory:  64 * 1024,
+	time:    3,
+	threads: 4,
+	saltLen: 16,
+	keyLen:  32,
+}
+
+// legacyParams are the ones of the hashes without the encoded parameters:
+// an 8 byte salt followed by the key.
+var legacyParams = argonParams{
+	memory:  64 * 1024,
+	time:    1,
+	threads: 4,
+	saltLen: 8,
+	keyLen:  32,
+}
+
+const hashPrefix = "$argon2id$"
+
+var errInvalidHash = errors.New("invalid password hash")
+
+// HashPassword returns the hash in the PHC string format:
+// $argon2id$v=19$m=65536,t=3,p=4$<salt>$<key>, base64 without padding.
+func HashPassword(password []byte) ([]byte, error) {
+	salt := make([]byte, hashParams.saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	key := argon2.IDKey(password, salt, hashParams.time, hashParams.memory, hashParams.threads, hashParams.keyLen)
+
+	return []byte(fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", hashPrefix, argon2.Version,
+		hashParams.memory, hashParams.time, hashParams.threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key))), nil
+}
+
+// checkPassword also reports whether the hash is made with outdated
+// parameters and has to be replaced.
+func checkPassword(passHash []byte, plainPassword []byte) (ok bool, rehash bool) {
+	params, salt, key, err := decodeHash(passHash)
+	if err != nil {
+		return false, false
+	}
+
+	actual := argon2.IDKey(plainPassword, salt, params.time, params.memory, params.threads, params.keyLen)
+	if subtle.ConstantTimeCompare(actual, key) != 1 {
+		return false, false
+	}
+
+	return true, params != hashParams
+}
+
+func decodeHash(passHash []byte) (argonParams, []byte, []byte, error) {
+	if !bytes.HasPrefix(passHash, []byte(hashPrefix)) {
+		if len(passHash) != legacyParams.saltLen+int(legacyParams.keyLen) {
+			return argonParams{}, nil, nil, errInvalidHash
+		}
+		return legacyParams, passHash[:legacyParams.saltLen], passHash[legacyParams.saltLen:], nil
+	}
+
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", salt, key
+	parts := strings.Split(string(passHash), "$")
+	if len(parts) != 6 {
+		return argonParams{}, nil, nil, errInvalidHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return argonParams{}, nil, nil, errInvalidHash
+	}
+
+	var params argonParams
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.time, &params.threads); err != nil {
+		return argonParams{}, nil, nil, errInvalidHash
+	}
+	if params.time == 0 || params.threads == 0 {
+		return argonParams{}, nil, nil, errInvalidHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return argonParams{}, nil, nil, errInvalidHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return argonParams{}, nil, nil, errInvalidHash
+	}
+	params.saltLen = len(salt)
+	params.keyLen = uint32(len(key))
+
+	return params, salt, key, nil
+}
@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+)
+
+// how long a user has to finish the login at the provider
+const oidcStateTTL = 10 * time.Minute
+
+type oidcUsecase struct {
+	clients      map[string]domain.OIDCClient
+	authRepo     domain.AuthRepository
+	identityRepo domain.IdentityRepository
+	stateRepo    domain.OIDCStateRepository
+	sessionRepo  domain.SessionRepository
+	sessions     domain.SessionsUsecase
+	tokenRepo    domain.APITokensRepository
+	twoFactor    domain.TwoFactorUsecase
+	policy       domain.SessionPolicy
+}
+
+func NewOIDCUsecase(clients map[string]domain.OIDCClient, ar domain.AuthRepository, ir domain.IdentityRepository,
+	str domain.OIDCStateRepository, sr domain.SessionRepository, su domain.SessionsUsecase,
+	tr domain.APITokensRepository, tfu domain.TwoFactorUsecase, policy domain.SessionPolicy) domain.OIDCUsecase {
+	return &oidcUsecase{
+		clients:      clients,
+		authRepo:     ar,
+		identityRepo: ir,
+		stateRepo:    str,
+		sessionRepo:  sr,
+		sessions:     su,
+		tokenRepo:    tr,
+		twoFactor:    tfu,
+		policy:       policy,
+	}
+}
+
+// Begin returns the url of the provider to redirect the user to and
+// the state. The state, nonce and PKCE verifier are kept until the callback.
+func (u *oidcUsecase) Begin(provider string) (string, string, error) {
+	client, ok := u.clients[provider]
+	if !ok {
+		return "", "", domain.ErrNotFound
+	}
+
+	var s domain.OIDCState
+	state, err := newToken()
+	if err == nil {
+		s.Nonce, err = newToken()
+	}
+	if err == nil {
+		s.Verifier, err = newToken()
+	}
+	if err != nil {
+		logs.LogError(logs.Logger, "auth/usecase", "Begin", err, err.Error())
+		return "", "", err
+	}
+	s.Provider = provider
+
+	if err = u.stateRepo.Add(state, s, oidcStateTTL); err != nil {
+		logs.LogError(logs.Logger, "auth/usecase", "Begin", err, err.Error())
+		return "", "", err
+	}
+
+	url, err := client.AuthCodeURL(state, s.Nonce, codeChallenge(s.Verifier))
+	if err != nil {
+		logs.LogError(logs.Logger, "auth/usecase", "Begin", err, err.Error())
+		return "", "", err
+	}
+
+	return url, state, nil
+}
+
+func (u *oidcUsecase) Complete(provider, state, code string, client domain.Client) (domain.Session, error) {
+	s, err := u.stateRepo.Pop(state)
+	if err != nil {
+		return domain.Session{}, err
+	}
+	if s.Provider != provider {
+		return domain.Session{}, domain.ErrInvalidToken
+	}
+
+	oidcClient, ok := u.clients[provider]
+	if !ok {
+		return domain.Session{}, domain.ErrNotFound
+	}
+
+	identity, err := oidcClient.Exchange(code, s.Verifier, s.Nonce)
+	if err != nil {
+		logs.LogError(logs.Logger, "auth/usecase", "Complete", err, "code exchange failed")
+		return domain.Session{}, domain.ErrUnauthorized
+	}
+
+	user, err := u.resolveUser(identity)
+	if err != nil {
+		return domain.Session{}, err
+	}
+	if user.Disabled {
+		return domain.Session{}, domain.ErrDisabled
+	}
+
+	return loginSession(u.twoFactor, u.sessionRepo, u.policy, user, client)
+}
+
+// resolveUser finds the user linked to the identity. An unknown identity
+// is linked to the account with the same email, or a new account is
+// created, but only if the provider has verified the email.
+func (u *oidcUsecase) resolveUser(identity domain.Identity) (domain.User, error) {
+	userID, err := u.identityRepo.GetUserID(identity.Provider, identity.Subject)
+	if err == nil {
+		return u.authRepo.GetByID(userID)
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return domain.User{}, err
+	}
+
+	if identity.Email == "" || !identity.EmailVerified {
+		return domain.User{}, domain.ErrNotVerified
+	}
+
+	user, err := u.authRepo.GetByEmail(identity.Email)
+	switch {
+	case err == nil:
+		if !user.Verified {
+			if err = u.takeOver(user); err != nil {
+				return domain.User{}, err
+			}
+			user.Verified = true
+		}
+	case errors.Is(err, domain.ErrNotFound):
+		user, err = u.register(identity.Email)
+		if err != nil {
+			return domain.User{}, err
+		}
+	default:
+		return domain.User{}, err
+	}
+
+	if err = u.identityRepo.Link(user.ID, identity); err != nil {
+		return domain.User{}, err
+	}
+
+	return user, nil
+}
+
+// takeOver protects the owner of the email from someone who registered
+// it before them: the unverified password and API tokens are dropped and
+// they are logged out everywhere, including the refresh tokens of the
+// jwt mode.
+func (u *oidcUsecase) takeOver(user domain.User) error {
+	password, err := randomPassword()
+	if err != nil {
+		return err
+	}
+	if err = u.authRepo.UpdatePassword(user.ID, password); err != nil {
+		return err
+	}
+	if err = u.authRepo.SetVerified(user.ID); err != nil {
+		return err
+	}
+
+	if err = u.sessions.RevokeAll(user.ID); err != nil {
+		return err
+	}
+
+	return u.tokenRepo.DeleteByUser(user.ID)
+}
+
+// register creates an account without a usable password, the user
+// can set one with the password reset.
+func (u *oidcUsecase) register(email string) (domain.User, error) {
+	password, err := randomPassword()
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	user := domain.User{
+		Email:    email,
+		Password: password,
+		Role:     domain.Usr,
+		Verified: true,
+	}
+	user.ID, err = u.authRepo.AddUser(user)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	return user, nil
+}
+
+func randomPassword() ([]byte, error) {
+	password := make([]byte, 32)
+	if _, err := rand.Read(password); err != nil {
+		return nil, err
+	}
+
+	return HashPassword(password)
+}
+
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
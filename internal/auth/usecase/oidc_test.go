@@ -0,0 +1,273 @@
+package usecase_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ellexo2456/FilmLib/internal/auth/usecase"
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	"github.com/ellexo2456/FilmLib/internal/domain/mocks"
+	"github.com/ellexo2456/FilmLib/internal/oidc"
+	"github.com/ellexo2456/FilmLib/internal/oidc/oidctest"
+)
+
+type oidcMocks struct {
+	client   *mocks.OIDCClient
+	auth     *mocks.AuthRepository
+	identity *mocks.IdentityRepository
+	state    *mocks.OIDCStateRepository
+	session  *mocks.SessionRepository
+	refresh  *mocks.RefreshTokenRepository
+	remember *mocks.RememberTokenRepository
+	token    *mocks.APITokensRepository
+	tfa      *mocks.TwoFactorUsecase
+}
+
+func newOIDCMocks() oidcMocks {
+	m := oidcMocks{
+		client:   new(mocks.OIDCClient),
+		auth:     new(mocks.AuthRepository),
+		identity: new(mocks.IdentityRepository),
+		state:    new(mocks.OIDCStateRepository),
+		session:  new(mocks.SessionRepository),
+		refresh:  new(mocks.RefreshTokenRepository),
+		remember: new(mocks.RememberTokenRepository),
+		token:    new(mocks.APITokensRepository),
+		tfa:      new(mocks.TwoFactorUsecase),
+	}
+	m.tfa.On("Required", mock.Anything).Return(false, nil).Maybe()
+
+	return m
+}
+
+func (m oidcMocks) usecase() domain.OIDCUsecase {
+	return usecase.NewOIDCUsecase(map[string]domain.OIDCClient{"fake": m.client},
+		m.auth, m.identity, m.state, m.session, m.sessions(), m.token, m.tfa, sessionPolicy)
+}
+
+func (m oidcMocks) sessions() domain.SessionsUsecase {
+	return usecase.NewSessionsUsecase(m.session, m.refresh, m.remember)
+}
+
+func (m oidcMocks) assert(t *testing.T) {
+	m.client.AssertExpectations(t)
+	m.auth.AssertExpectations(t)
+	m.identity.AssertExpectations(t)
+	m.state.AssertExpectations(t)
+	m.session.AssertExpectations(t)
+	m.refresh.AssertExpectations(t)
+	m.remember.AssertExpectations(t)
+	m.token.AssertExpectations(t)
+}
+
+func TestOIDCComplete(t *testing.T) {
+	state := domain.OIDCState{Provider: "fake", Nonce: "n", Verifier: "v"}
+	identity := domain.Identity{Provider: "fake", Subject: "42", Email: "uvybini@mail.ru", EmailVerified: true}
+
+	tests := []struct {
+		name            string
+		provider        string
+		setExpectations func(m oidcMocks)
+		userID          int
+		err             error
+	}{
+		{
+			name:     "GoodCase/Linked",
+			provider: "fake",
+			setExpectations: func(m oidcMocks) {
+				m.state.On("Pop", "s").Return(state, nil)
+				m.client.On("Exchange", "c", "v", "n").Return(identity, nil)
+				m.identity.On("GetUserID", "fake", "42").Return(5, nil)
+				m.auth.On("GetByID", 5).Return(domain.User{ID: 5, Verified: true}, nil)
+				m.session.On("Add", mock.MatchedBy(func(s domain.Session) bool { return s.UserID == 5 })).Return(nil)
+			},
+			userID: 5,
+		},
+		{
+			name:     "GoodCase/LinkByEmail",
+			provider: "fake",
+			setExpectations: func(m oidcMocks) {
+				m.state.On("Pop", "s").Return(state, nil)
+				m.client.On("Exchange", "c", "v", "n").Return(identity, nil)
+				m.identity.On("GetUserID", "fake", "42").Return(0, domain.ErrNotFound)
+				m.auth.On("GetByEmail", identity.Email).Return(domain.User{ID: 6, Verified: true}, nil)
+				m.identity.On("Link", 6, identity).Return(nil)
+				m.session.On("Add", mock.Anything).Return(nil)
+			},
+			userID: 6,
+		},
+		{
+			name:     "GoodCase/TakeOverUnverified",
+			provider: "fake",
+			setExpectations: func(m oidcMocks) {
+				m.state.On("Pop", "s").Return(state, nil)
+				m.client.On("Exchange", "c", "v", "n").Return(identity, nil)
+				m.identity.On("GetUserID", "fake", "42").Return(0, domain.ErrNotFound)
+				m.auth.On("GetByEmail", identity.Email).Return(domain.User{ID: 6}, nil)
+				m.auth.On("UpdatePassword", 6, mock.Anything).Return(nil)
+				m.auth.On("SetVerified", 6).Return(nil)
+				m.session.On("DeleteByUserID", 6).Return(nil)
+				m.refresh.On("DeleteByUserID", 6).Return(nil)
+				m.remember.On("DeleteByUserID", 6).Return(nil)
+				m.token.On("DeleteByUser", 6).Return(nil)
+				m.identity.On("Link", 6, identity).Return(nil)
+				m.session.On("Add", mock.MatchedBy(func(s domain.Session) bool { return s.Verified })).Return(nil)
+			},
+			userID: 6,
+		},
+		{
+			name:     "GoodCase/Register",
+			provider: "fake",
+			setExpectations: func(m oidcMocks) {
+				m.state.On("Pop", "s").Return(state, nil)
+				m.client.On("Exchange", "c", "v", "n").Return(identity, nil)
+				m.identity.On("GetUserID", "fake", "42").Return(0, domain.ErrNotFound)
+				m.auth.On("GetByEmail", identity.Email).Return(domain.User{}, domain.ErrNotFound)
+				m.auth.On("AddUser", mock.MatchedBy(func(u domain.User) bool {
+					return u.Email == identity.Email && u.Verified && u.Role == domain.Usr && len(u.Password) != 0
+				})).Return(7, nil)
+				m.identity.On("Link", 7, identity).Return(nil)
+				m.session.On("Add", mock.Anything).Return(nil)
+			},
+			userID: 7,
+		},
+		{
+			name:     "BadCase/UnverifiedEmail",
+			provider: "fake",
+			setExpectations: func(m oidcMocks) {
+				m.state.On("Pop", "s").Return(state, nil)
+				m.client.On("Exchange", "c", "v", "n").
+					Return(domain.Identity{Provider: "fake", Subject: "42", Email: identity.Email}, nil)
+				m.identity.On("GetUserID", "fake", "42").Return(0, domain.ErrNotFound)
+			},
+			err: domain.ErrNotVerified,
+		},
+		{
+			name:     "BadCase/TakeOverRefreshNotRevoked",
+			provider: "fake",
+			setExpectations: func(m oidcMocks) {
+				m.state.On("Pop", "s").Return(state, nil)
+				m.client.On("Exchange", "c", "v", "n").Return(identity, nil)
+				m.identity.On("GetUserID", "fake", "42").Return(0, domain.ErrNotFound)
+				m.auth.On("GetByEmail", identity.Email).Return(domain.User{ID: 6}, nil)
+				m.auth.On("UpdatePassword", 6, mock.Anything).Return(nil)
+				m.auth.On("SetVerified", 6).Return(nil)
+				m.session.On("DeleteByUserID", 6).Return(nil)
+				m.refresh.On("DeleteByUserID", 6).Return(domain.ErrInternalServerError)
+			},
+			err: domain.ErrInternalServerError,
+		},
+		{
+			name:     "BadCase/Disabled",
+			provider: "fake",
+			setExpectations: func(m oidcMocks) {
+				m.state.On("Pop", "s").Return(state, nil)
+				m.client.On("Exchange", "c", "v", "n").Return(identity, nil)
+				m.identity.On("GetUserID", "fake", "42").Return(5, nil)
+				m.auth.On("GetByID", 5).Return(domain.User{ID: 5, Disabled: true}, nil)
+			},
+			err: domain.ErrDisabled,
+		},
+		{
+			name:     "BadCase/UnknownState",
+			provider: "fake",
+			setExpectations: func(m oidcMocks) {
+				m.state.On("Pop", "s").Return(domain.OIDCState{}, domain.ErrInvalidToken)
+			},
+			err: domain.ErrInvalidToken,
+		},
+		{
+			name:     "BadCase/OtherProvider",
+			provider: "other",
+			setExpectations: func(m oidcMocks) {
+				m.state.On("Pop", "s").Return(state, nil)
+			},
+			err: domain.ErrInvalidToken,
+		},
+		{
+			name:     "BadCase/ExchangeFailed",
+			provider: "fake",
+			setExpectations: func(m oidcMocks) {
+				m.state.On("Pop", "s").Return(state, nil)
+				m.client.On("Exchange", "c", "v", "n").Return(domain.Identity{}, oidc.ErrInvalidIDToken)
+			},
+			err: domain.ErrUnauthorized,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := newOIDCMocks()
+			test.setExpectations(m)
+
+			session, err := m.usecase().Complete(test.provider, "s", "c", domain.Client{})
+
+			assert.ErrorIs(t, err, test.err)
+			assert.Equal(t, test.userID, session.UserID)
+			m.assert(t)
+		})
+	}
+}
+
+func TestOIDCBegin(t *testing.T) {
+	m := newOIDCMocks()
+	m.state.On("Add", mock.AnythingOfType("string"), mock.MatchedBy(func(s domain.OIDCState) bool {
+		return s.Provider == "fake" && s.Nonce != "" && s.Verifier != ""
+	}), mock.Anything).Return(nil)
+	m.client.On("AuthCodeURL", mock.Anything, mock.Anything, mock.Anything).Return("https://provider/authorize", nil)
+
+	u, state, err := m.usecase().Begin("fake")
+	require.NoError(t, err)
+	assert.Equal(t, "https://provider/authorize", u)
+	assert.NotEmpty(t, state)
+	m.assert(t)
+
+	_, _, err = m.usecase().Begin("unknown")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+// TestOIDCFakeIssuer runs the whole flow against the in-process issuer,
+// checking that the state, nonce and PKCE verifier fit together.
+func TestOIDCFakeIssuer(t *testing.T) {
+	issuer := oidctest.NewIssuer("filmlib", "secret")
+	defer issuer.Close()
+	issuer.SetUser(oidctest.User{Subject: "42", Email: "uvybini@mail.ru", EmailVerified: true})
+
+	client := oidc.NewClient(domain.OIDCProviderConfig{
+		Name:         "fake",
+		Issuer:       issuer.URL(),
+		ClientID:     "filmlib",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost/callback",
+	}, nil)
+
+	m := newOIDCMocks()
+	states := map[string]domain.OIDCState{}
+	m.state.On("Add", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		states[args.String(0)] = args.Get(1).(domain.OIDCState)
+	}).Return(nil)
+	m.state.On("Pop", mock.Anything).Return(func(s string) domain.OIDCState { return states[s] }, nil)
+	m.identity.On("GetUserID", "fake", "42").Return(5, nil)
+	m.auth.On("GetByID", 5).Return(domain.User{ID: 5, Verified: true}, nil)
+	m.session.On("Add", mock.Anything).Return(nil)
+
+	u := usecase.NewOIDCUsecase(map[string]domain.OIDCClient{"fake": client}, m.auth, m.identity, m.state, m.session,
+		m.sessions(), m.token, m.tfa, sessionPolicy)
+
+	authURL, state, err := u.Begin("fake")
+	require.NoError(t, err)
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, state, parsed.Query().Get("state"))
+
+	code, returnedState, err := issuer.Authorize(authURL)
+	require.NoError(t, err)
+
+	session, err := u.Complete("fake", returnedState, code, domain.Client{})
+	require.NoError(t, err)
+	assert.Equal(t, 5, session.UserID)
+}
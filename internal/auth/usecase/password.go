@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+)
+
+const resetMailSubject = "FilmLib password reset"
+
+type passwordUsecase struct {
+	authRepo  domain.AuthRepository
+	resetRepo domain.ResetTokenRepository
+	sessions  domain.SessionsUsecase
+	tokenRepo domain.APITokensRepository
+	mailer    domain.Mailer
+	resetURL  string
+	ttl       time.Duration
+}
+
+// NewPasswordUsecase creates a usecase which mails reset links of the form
+// resetURL + token. Every token lives for ttl and can be used only once.
+func NewPasswordUsecase(ar domain.AuthRepository, rr domain.ResetTokenRepository, su domain.SessionsUsecase,
+	tr domain.APITokensRepository, m domain.Mailer, resetURL string, ttl time.Duration) domain.PasswordUsecase {
+	return &passwordUsecase{
+		authRepo:  ar,
+		resetRepo: rr,
+		sessions:  su,
+		tokenRepo: tr,
+		mailer:    m,
+		resetURL:  resetURL,
+		ttl:       ttl,
+	}
+}
+
+// RequestReset doesn`t tell whether the email is registered,
+// so unknown addresses are silently ignored.
+func (u *passwordUsecase) RequestReset(email string) error {
+	if email == "" {
+		return domain.ErrBadRequest
+	}
+
+	user, err := u.authRepo.GetByEmail(email)
+	if errors.Is(err, domain.ErrNotFound) {
+		logs.Logger.Debug("auth/usecase RequestReset unknown email:", email)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return err
+	}
+
+	if err = u.resetRepo.Add(token, user.ID, u.ttl); err != nil {
+		logs.LogError(logs.Logger, "auth/usecase", "RequestReset", err, err.Error())
+		return err
+	}
+
+	err = u.mailer.Send(domain.Mail{
+		To:      user.Email,
+		Subject: resetMailSubject,
+		Body: "To set a new password follow the link below. It expires in " + u.ttl.String() + ".\n\n" +
+			u.resetURL + token + "\n\nIf you didn`t ask for a reset, just ignore this mail.",
+	})
+	if err != nil {
+		logs.LogError(logs.Logger, "auth/usecase", "RequestReset", err, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Reset logs the user out everywhere and deletes their API tokens after
+// the password is changed, a reset is how an account is taken back
+// after it is compromised.
+func (u *passwordUsecase) Reset(reset domain.PasswordReset) error {
+	if reset.Token == "" {
+		return domain.ErrInvalidToken
+	}
+	if len(reset.Password) == 0 {
+		return domain.ErrBadRequest
+	}
+
+	userID, err := u.resetRepo.Pop(reset.Token)
+	if err != nil {
+		logs.LogError(logs.Logger, "auth/usecase", "Reset", err, err.Error())
+		return err
+	}
+
+	hash, err := HashPassword(reset.Password)
+	if err != nil {
+		logs.LogError(logs.Logger, "auth/usecase", "Reset", err, err.Error())
+		return err
+	}
+
+	if err = u.authRepo.UpdatePassword(userID, hash); err != nil {
+		logs.LogError(logs.Logger, "auth/usecase", "Reset", err, err.Error())
+		return err
+	}
+
+	if err = u.sessions.RevokeAll(userID); err != nil {
+		logs.LogError(logs.Logger, "auth/usecase", "Reset", err, err.Error())
+		return err
+	}
+
+	if err = u.tokenRepo.DeleteByUser(userID); err != nil {
+		logs.LogError(logs.Logger, "auth/usecase", "Reset", err, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,180 @@
+package usecase_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ellexo2456/FilmLib/internal/auth/usecase"
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	"github.com/ellexo2456/FilmLib/internal/domain/mocks"
+)
+
+func TestRequestReset(t *testing.T) {
+	tests := []struct {
+		name            string
+		email           string
+		setExpectations func(ar *mocks.AuthRepository, rr *mocks.ResetTokenRepository, m *mocks.Mailer)
+		good            bool
+	}{
+		{
+			name:  "GoodCase/Common",
+			email: "uvybini@mail.ru",
+			setExpectations: func(ar *mocks.AuthRepository, rr *mocks.ResetTokenRepository, m *mocks.Mailer) {
+				ar.On("GetByEmail", "uvybini@mail.ru").Return(domain.User{ID: 3, Email: "uvybini@mail.ru"}, nil)
+				rr.On("Add", mock.AnythingOfType("string"), 3, time.Hour).Return(nil)
+				m.On("Send", mock.MatchedBy(func(mail domain.Mail) bool {
+					return mail.To == "uvybini@mail.ru" && strings.Contains(mail.Body, "http://front/reset?token=")
+				})).Return(nil)
+			},
+			good: true,
+		},
+		{
+			name:  "GoodCase/UnknownEmail",
+			email: "nobody@mail.ru",
+			setExpectations: func(ar *mocks.AuthRepository, rr *mocks.ResetTokenRepository, m *mocks.Mailer) {
+				ar.On("GetByEmail", "nobody@mail.ru").Return(domain.User{}, domain.ErrNotFound)
+			},
+			good: true,
+		},
+		{
+			name:            "BadCase/EmptyEmail",
+			email:           "",
+			setExpectations: func(ar *mocks.AuthRepository, rr *mocks.ResetTokenRepository, m *mocks.Mailer) {},
+		},
+		{
+			name:  "BadCase/RedisError",
+			email: "uvybini@mail.ru",
+			setExpectations: func(ar *mocks.AuthRepository, rr *mocks.ResetTokenRepository, m *mocks.Mailer) {
+				ar.On("GetByEmail", "uvybini@mail.ru").Return(domain.User{ID: 3, Email: "uvybini@mail.ru"}, nil)
+				rr.On("Add", mock.Anything, 3, time.Hour).Return(errors.New("some redis error"))
+			},
+		},
+		{
+			name:  "BadCase/MailerError",
+			email: "uvybini@mail.ru",
+			setExpectations: func(ar *mocks.AuthRepository, rr *mocks.ResetTokenRepository, m *mocks.Mailer) {
+				ar.On("GetByEmail", "uvybini@mail.ru").Return(domain.User{ID: 3, Email: "uvybini@mail.ru"}, nil)
+				rr.On("Add", mock.Anything, 3, time.Hour).Return(nil)
+				m.On("Send", mock.Anything).Return(errors.New("smtp is down"))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ar := new(mocks.AuthRepository)
+			rr := new(mocks.ResetTokenRepository)
+			m := new(mocks.Mailer)
+			test.setExpectations(ar, rr, m)
+
+			pu := usecase.NewPasswordUsecase(ar, rr, new(mocks.SessionsUsecase), new(mocks.APITokensRepository), m, "http://front/reset?token=", time.Hour)
+			err := pu.RequestReset(test.email)
+
+			if test.good {
+				assert.Nil(t, err)
+			} else {
+				assert.NotNil(t, err)
+			}
+
+			ar.AssertExpectations(t)
+			rr.AssertExpectations(t)
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReset(t *testing.T) {
+	tests := []struct {
+		name            string
+		reset           domain.PasswordReset
+		setExpectations func(ar *mocks.AuthRepository, rr *mocks.ResetTokenRepository, su *mocks.SessionsUsecase, tr *mocks.APITokensRepository)
+		err             error
+	}{
+		{
+			name:  "GoodCase/Common",
+			reset: domain.PasswordReset{Token: "token", Password: []byte("new password")},
+			setExpectations: func(ar *mocks.AuthRepository, rr *mocks.ResetTokenRepository, su *mocks.SessionsUsecase, tr *mocks.APITokensRepository) {
+				rr.On("Pop", "token").Return(3, nil)
+				ar.On("UpdatePassword", 3, mock.AnythingOfType("[]uint8")).Return(nil)
+				su.On("RevokeAll", 3).Return(nil)
+				tr.On("DeleteByUser", 3).Return(nil)
+			},
+		},
+		{
+			name:  "BadCase/TokensNotDeleted",
+			reset: domain.PasswordReset{Token: "token", Password: []byte("new password")},
+			setExpectations: func(ar *mocks.AuthRepository, rr *mocks.ResetTokenRepository, su *mocks.SessionsUsecase, tr *mocks.APITokensRepository) {
+				rr.On("Pop", "token").Return(3, nil)
+				ar.On("UpdatePassword", 3, mock.Anything).Return(nil)
+				su.On("RevokeAll", 3).Return(nil)
+				tr.On("DeleteByUser", 3).Return(domain.ErrInternalServerError)
+			},
+			err: domain.ErrInternalServerError,
+		},
+		{
+			name:  "BadCase/RevokeFailed",
+			reset: domain.PasswordReset{Token: "token", Password: []byte("new password")},
+			setExpectations: func(ar *mocks.AuthRepository, rr *mocks.ResetTokenRepository, su *mocks.SessionsUsecase, tr *mocks.APITokensRepository) {
+				rr.On("Pop", "token").Return(3, nil)
+				ar.On("UpdatePassword", 3, mock.Anything).Return(nil)
+				su.On("RevokeAll", 3).Return(domain.ErrInternalServerError)
+			},
+			err: domain.ErrInternalServerError,
+		},
+		{
+			name:  "BadCase/EmptyToken",
+			reset: domain.PasswordReset{Password: []byte("new password")},
+			setExpectations: func(ar *mocks.AuthRepository, rr *mocks.ResetTokenRepository, su *mocks.SessionsUsecase, tr *mocks.APITokensRepository) {
+			},
+			err: domain.ErrInvalidToken,
+		},
+		{
+			name:  "BadCase/EmptyPassword",
+			reset: domain.PasswordReset{Token: "token"},
+			setExpectations: func(ar *mocks.AuthRepository, rr *mocks.ResetTokenRepository, su *mocks.SessionsUsecase, tr *mocks.APITokensRepository) {
+			},
+			err: domain.ErrBadRequest,
+		},
+		{
+			name:  "BadCase/UsedToken",
+			reset: domain.PasswordReset{Token: "token", Password: []byte("new password")},
+			setExpectations: func(ar *mocks.AuthRepository, rr *mocks.ResetTokenRepository, su *mocks.SessionsUsecase, tr *mocks.APITokensRepository) {
+				rr.On("Pop", "token").Return(0, domain.ErrInvalidToken)
+			},
+			err: domain.ErrInvalidToken,
+		},
+		{
+			name:  "BadCase/UserDeleted",
+			reset: domain.PasswordReset{Token: "token", Password: []byte("new password")},
+			setExpectations: func(ar *mocks.AuthRepository, rr *mocks.ResetTokenRepository, su *mocks.SessionsUsecase, tr *mocks.APITokensRepository) {
+				rr.On("Pop", "token").Return(3, nil)
+				ar.On("UpdatePassword", 3, mock.Anything).Return(domain.ErrNotFound)
+			},
+			err: domain.ErrNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ar := new(mocks.AuthRepository)
+			rr := new(mocks.ResetTokenRepository)
+			su := new(mocks.SessionsUsecase)
+			tr := new(mocks.APITokensRepository)
+			test.setExpectations(ar, rr, su, tr)
+
+			pu := usecase.NewPasswordUsecase(ar, rr, su, tr, new(mocks.Mailer), "", time.Hour)
+			err := pu.Reset(test.reset)
+
+			assert.ErrorIs(t, err, test.err)
+			ar.AssertExpectations(t)
+			rr.AssertExpectations(t)
+			su.AssertExpectations(t)
+			tr.AssertExpectations(t)
+		})
+	}
+}
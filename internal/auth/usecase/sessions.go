@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+)
+
+type sessionsUsecase struct {
+	sessionRepo  domain.SessionRepository
+	refreshRepo  domain.RefreshTokenRepository
+	rememberRepo domain.RememberTokenRepository
+}
+
+func NewSessionsUsecase(sr domain.SessionRepository, rr domain.RefreshTokenRepository,
+	rmr domain.RememberTokenRepository) domain.SessionsUsecase {
+	return &sessionsUsecase{
+		sessionRepo:  sr,
+		refreshRepo:  rr,
+		rememberRepo: rmr,
+	}
+}
+
+// GetAll marks the session of currentToken, so the client can tell
+// which one it is.
+func (u *sessionsUsecase) GetAll(userID int, currentToken string) ([]domain.SessionInfo, error) {
+	sessions, err := u.sessionRepo.GetByUserID(userID)
+	if err != nil {
+		logs.LogError(logs.Logger, "auth/usecase", "GetAll", err, err.Error())
+		return nil, err
+	}
+
+	if currentToken != "" {
+		current := domain.SessionID(currentToken)
+		for i := range sessions {
+			sessions[i].Current = sessions[i].ID == current
+		}
+	}
+
+	return sessions, nil
+}
+
+func (u *sessionsUsecase) Revoke(userID int, id string) error {
+	if id == "" {
+		return domain.ErrNotFound
+	}
+
+	return u.sessionRepo.DeleteByID(userID, id)
+}
+
+// RevokeAll logs the user out everywhere, including the refresh
+// tokens of the jwt mode and the remember-me tokens.
+func (u *sessionsUsecase) RevokeAll(userID int) error {
+	if err := u.sessionRepo.DeleteByUserID(userID); err != nil {
+		logs.LogError(logs.Logger, "auth/usecase", "RevokeAll", err, err.Error())
+		return err
+	}
+
+	if err := u.refreshRepo.DeleteByUserID(userID); err != nil {
+		logs.LogError(logs.Logger, "auth/usecase", "RevokeAll", err, err.Error())
+		return err
+	}
+
+	if err := u.rememberRepo.DeleteByUserID(userID); err != nil {
+		logs.LogError(logs.Logger, "auth/usecase", "RevokeAll", err, err.Error())
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ellexo2456/FilmLib/internal/auth/usecase"
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	"github.com/ellexo2456/FilmLib/internal/domain/mocks"
+)
+
+func TestSessionsGetAll(t *testing.T) {
+	sr := new(mocks.SessionRepository)
+	sr.On("GetByUserID", 1).Return([]domain.SessionInfo{
+		{ID: domain.SessionID("other")},
+		{ID: domain.SessionID("current")},
+	}, nil)
+
+	sessions, err := usecase.NewSessionsUsecase(sr, new(mocks.RefreshTokenRepository), new(mocks.RememberTokenRepository)).GetAll(1, "current")
+
+	assert.NoError(t, err)
+	assert.False(t, sessions[0].Current)
+	assert.True(t, sessions[1].Current)
+	sr.AssertExpectations(t)
+}
+
+func TestSessionsRevoke(t *testing.T) {
+	sr := new(mocks.SessionRepository)
+	sr.On("DeleteByID", 1, "abc").Return(domain.ErrNotFound)
+	u := usecase.NewSessionsUsecase(sr, new(mocks.RefreshTokenRepository), new(mocks.RememberTokenRepository))
+
+	assert.ErrorIs(t, u.Revoke(1, "abc"), domain.ErrNotFound)
+	assert.ErrorIs(t, u.Revoke(1, ""), domain.ErrNotFound)
+	sr.AssertExpectations(t)
+}
+
+func TestSessionsRevokeAll(t *testing.T) {
+	tests := []struct {
+		name            string
+		setExpectations func(sr *mocks.SessionRepository, rr *mocks.RefreshTokenRepository, rmr *mocks.RememberTokenRepository)
+		err             error
+	}{
+		{
+			name: "GoodCase/Common",
+			setExpectations: func(sr *mocks.SessionRepository, rr *mocks.RefreshTokenRepository, rmr *mocks.RememberTokenRepository) {
+				sr.On("DeleteByUserID", 1).Return(nil)
+				rr.On("DeleteByUserID", 1).Return(nil)
+				rmr.On("DeleteByUserID", 1).Return(nil)
+			},
+		},
+		{
+			name: "BadCase/SessionsError",
+			setExpectations: func(sr *mocks.SessionRepository, rr *mocks.RefreshTokenRepository, rmr *mocks.RememberTokenRepository) {
+				sr.On("DeleteByUserID", 1).Return(errors.New("some redis error"))
+			},
+			err: errors.New("some redis error"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sr := new(mocks.SessionRepository)
+			rr := new(mocks.RefreshTokenRepository)
+			rmr := new(mocks.RememberTokenRepository)
+			test.setExpectations(sr, rr, rmr)
+
+			err := usecase.NewSessionsUsecase(sr, rr, rmr).RevokeAll(1)
+
+			assert.Equal(t, test.err, err)
+			sr.AssertExpectations(t)
+			rr.AssertExpectations(t)
+			rmr.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	"github.com/ellexo2456/FilmLib/internal/jwt"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+)
+
+type accessClaims struct {
+	Subject   string      `json:"sub"`
+	Role      domain.Role `json:"role"`
+	Verified  bool        `json:"verified"`
+	IssuedAt  int64       `json:"iat"`
+	ExpiresAt int64       `json:"exp"`
+}
+
+// tokenAuthUsecase is the stateless alternative to sessions: access
+// tokens are verified by signature only, so a role change or a disable
+// takes effect when the access token expires and has to be refreshed.
+type tokenAuthUsecase struct {
+	authRepo    domain.AuthRepository
+	refreshRepo domain.RefreshTokenRepository
+	twoFactor   domain.TwoFactorUsecase
+	keyring     *jwt.Keyring
+	accessTTL   time.Duration
+	refreshTTL  time.Duration
+}
+
+func NewTokenAuthUsecase(ar domain.AuthRepository, rr domain.RefreshTokenRepository, tfu domain.TwoFactorUsecase,
+	k *jwt.Keyring, accessTTL, refreshTTL time.Duration) domain.TokenAuthUsecase {
+	return &tokenAuthUsecase{
+		authRepo:    ar,
+		refreshRepo: rr,
+		twoFactor:   tfu,
+		keyring:     k,
+		accessTTL:   accessTTL,
+		refreshTTL:  refreshTTL,
+	}
+}
+
+// Login has no second step, accounts with 2FA have to log in with
+// a session.
+func (u *tokenAuthUsecase) Login(credentials domain.Credentials) (domain.TokenPair, error) {
+	user, err := authenticate(u.authRepo, credentials)
+	if err != nil {
+		return domain.TokenPair{}, err
+	}
+
+	required, err := u.twoFactor.Required(user)
+	if err != nil {
+		return domain.TokenPair{}, err
+	}
+	if required {
+		return domain.TokenPair{}, domain.ErrTwoFactorRequired
+	}
+
+	return u.issue(user)
+}
+
+// Refresh exchanges a refresh token for a new pair. The old refresh
+// token is consumed, and the user is re-read so the new access token
+// carries the current role.
+func (u *tokenAuthUsecase) Refresh(refreshToken string) (domain.TokenPair, error) {
+	userID, err := u.refreshRepo.Pop(refreshToken)
+	if err != nil {
+		return domain.TokenPair{}, err
+	}
+
+	user, err := u.authRepo.GetByID(userID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.TokenPair{}, domain.ErrInvalidToken
+	}
+	if err != nil {
+		logs.LogError(logs.Logger, "auth/usecase", "Refresh", err, err.Error())
+		return domain.TokenPair{}, err
+	}
+	if user.Disabled {
+		return domain.TokenPair{}, domain.ErrDisabled
+	}
+
+	return u.issue(user)
+}
+
+func (u *tokenAuthUsecase) Revoke(refreshToken string) error {
+	if _, err := u.refreshRepo.Pop(refreshToken); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (u *tokenAuthUsecase) RetrieveSessionContext(accessToken string) (domain.SessionContext, error) {
+	var claims accessClaims
+	if err := u.keyring.Verify(accessToken, &claims); err != nil {
+		return domain.SessionContext{}, err
+	}
+	if time.Now().Unix() >= claims.ExpiresAt {
+		return domain.SessionContext{}, domain.ErrUnauthorized
+	}
+
+	userID, err := strconv.Atoi(claims.Subject)
+	if err != nil || userID <= 0 {
+		return domain.SessionContext{}, domain.ErrUnauthorized
+	}
+
+	return domain.SessionContext{
+		UserID:   userID,
+		Role:     claims.Role,
+		Verified: claims.Verified,
+	}, nil
+}
+
+func (u *tokenAuthUsecase) issue(user domain.User) (domain.TokenPair, error) {
+	now := time.Now()
+	expiresAt := now.Add(u.accessTTL)
+
+	access, err := u.keyring.Sign(accessClaims{
+		Subject:   strconv.Itoa(user.ID),
+		Role:      user.Role,
+		Verified:  user.Verified,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		logs.LogError(logs.Logger, "auth/usecase", "issue", err, err.Error())
+		return domain.TokenPair{}, err
+	}
+
+	refresh, err := newToken()
+	if err != nil {
+		logs.LogError(logs.Logger, "auth/usecase", "issue", err, err.Error())
+		return domain.TokenPair{}, err
+	}
+	if err = u.refreshRepo.Add(refresh, user.ID, u.refreshTTL); err != nil {
+		logs.LogError(logs.Logger, "auth/usecase", "issue", err, err.Error())
+		return domain.TokenPair{}, err
+	}
+
+	return domain.TokenPair{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		ExpiresAt:    expiresAt,
+	}, nil
+}
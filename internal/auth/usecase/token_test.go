@@ -0,0 +1,211 @@
+package usecase_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ellexo2456/FilmLib/internal/auth/usecase"
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	"github.com/ellexo2456/FilmLib/internal/domain/mocks"
+	"github.com/ellexo2456/FilmLib/internal/jwt"
+)
+
+func newKeyring(t *testing.T) *jwt.Keyring {
+	k, err := jwt.NewKeyring("k1", jwt.NewHMACKey("k1", []byte("secret")))
+	require.NoError(t, err)
+	return k
+}
+
+func TestTokenLogin(t *testing.T) {
+	user := domain.User{
+		ID:       3,
+		Email:    "uvybini@mail.ru",
+		Password: hashPassword(t, []byte{123}),
+		Role:     domain.Moder,
+		Verified: true,
+	}
+
+	tests := []struct {
+		name            string
+		creds           domain.Credentials
+		setExpectations func(ar *mocks.AuthRepository, rr *mocks.RefreshTokenRepository)
+		twoFactor       bool
+		err             error
+	}{
+		{
+			name:  "GoodCase/Common",
+			creds: domain.Credentials{Email: user.Email, Password: []byte{123}},
+			setExpectations: func(ar *mocks.AuthRepository, rr *mocks.RefreshTokenRepository) {
+				ar.On("GetByEmail", user.Email).Return(user, nil)
+				rr.On("Add", mock.AnythingOfType("string"), 3, 24*time.Hour).Return(nil)
+			},
+		},
+		{
+			name:  "BadCase/WrongPassword",
+			creds: domain.Credentials{Email: user.Email, Password: []byte{1}},
+			setExpectations: func(ar *mocks.AuthRepository, rr *mocks.RefreshTokenRepository) {
+				ar.On("GetByEmail", user.Email).Return(user, nil)
+			},
+			err: domain.ErrWrongCredentials,
+		},
+		{
+			name:  "BadCase/TwoFactorRequired",
+			creds: domain.Credentials{Email: user.Email, Password: []byte{123}},
+			setExpectations: func(ar *mocks.AuthRepository, rr *mocks.RefreshTokenRepository) {
+				ar.On("GetByEmail", user.Email).Return(user, nil)
+			},
+			twoFactor: true,
+			err:       domain.ErrTwoFactorRequired,
+		},
+		{
+			name:  "BadCase/RedisError",
+			creds: domain.Credentials{Email: user.Email, Password: []byte{123}},
+			setExpectations: func(ar *mocks.AuthRepository, rr *mocks.RefreshTokenRepository) {
+				ar.On("GetByEmail", user.Email).Return(user, nil)
+				rr.On("Add", mock.Anything, 3, 24*time.Hour).Return(domain.ErrInternalServerError)
+			},
+			err: domain.ErrInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ar := new(mocks.AuthRepository)
+			rr := new(mocks.RefreshTokenRepository)
+			test.setExpectations(ar, rr)
+			tfu := new(mocks.TwoFactorUsecase)
+			tfu.On("Required", user).Return(test.twoFactor, nil).Maybe()
+			u := usecase.NewTokenAuthUsecase(ar, rr, tfu, newKeyring(t), time.Minute, 24*time.Hour)
+
+			pair, err := u.Login(test.creds)
+
+			assert.ErrorIs(t, err, test.err)
+			if test.err == nil {
+				sc, err := u.RetrieveSessionContext(pair.AccessToken)
+				require.NoError(t, err)
+				assert.Equal(t, domain.SessionContext{UserID: 3, Role: domain.Moder, Verified: true}, sc)
+				assert.NotEmpty(t, pair.RefreshToken)
+			}
+			ar.AssertExpectations(t)
+			rr.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTokenRefresh(t *testing.T) {
+	tests := []struct {
+		name            string
+		setExpectations func(ar *mocks.AuthRepository, rr *mocks.RefreshTokenRepository)
+		err             error
+	}{
+		{
+			name: "GoodCase/Common",
+			setExpectations: func(ar *mocks.AuthRepository, rr *mocks.RefreshTokenRepository) {
+				rr.On("Pop", "old").Return(3, nil)
+				ar.On("GetByID", 3).Return(domain.User{ID: 3, Role: domain.Admin}, nil)
+				rr.On("Add", mock.AnythingOfType("string"), 3, 24*time.Hour).Return(nil)
+			},
+		},
+		{
+			name: "BadCase/UsedToken",
+			setExpectations: func(ar *mocks.AuthRepository, rr *mocks.RefreshTokenRepository) {
+				rr.On("Pop", "old").Return(0, domain.ErrInvalidToken)
+			},
+			err: domain.ErrInvalidToken,
+		},
+		{
+			name: "BadCase/UserDeleted",
+			setExpectations: func(ar *mocks.AuthRepository, rr *mocks.RefreshTokenRepository) {
+				rr.On("Pop", "old").Return(3, nil)
+				ar.On("GetByID", 3).Return(domain.User{}, domain.ErrNotFound)
+			},
+			err: domain.ErrInvalidToken,
+		},
+		{
+			name: "BadCase/Disabled",
+			setExpectations: func(ar *mocks.AuthRepository, rr *mocks.RefreshTokenRepository) {
+				rr.On("Pop", "old").Return(3, nil)
+				ar.On("GetByID", 3).Return(domain.User{ID: 3, Disabled: true}, nil)
+			},
+			err: domain.ErrDisabled,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ar := new(mocks.AuthRepository)
+			rr := new(mocks.RefreshTokenRepository)
+			test.setExpectations(ar, rr)
+			u := usecase.NewTokenAuthUsecase(ar, rr, new(mocks.TwoFactorUsecase), newKeyring(t), time.Minute, 24*time.Hour)
+
+			pair, err := u.Refresh("old")
+
+			assert.ErrorIs(t, err, test.err)
+			if test.err == nil {
+				sc, err := u.RetrieveSessionContext(pair.AccessToken)
+				require.NoError(t, err)
+				assert.Equal(t, domain.Admin, sc.Role)
+			}
+			ar.AssertExpectations(t)
+			rr.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTokenRetrieveSessionContext(t *testing.T) {
+	user := domain.User{ID: 3, Role: domain.Usr}
+	other, err := jwt.NewKeyring("k2", jwt.NewHMACKey("k2", []byte("other")))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		keyring   *jwt.Keyring
+		accessTTL time.Duration
+		err       error
+	}{
+		{
+			name:      "GoodCase/Common",
+			keyring:   newKeyring(t),
+			accessTTL: time.Minute,
+		},
+		{
+			name:      "BadCase/Expired",
+			keyring:   newKeyring(t),
+			accessTTL: -time.Minute,
+			err:       domain.ErrUnauthorized,
+		},
+		{
+			name:      "BadCase/ForeignKey",
+			keyring:   other,
+			accessTTL: time.Minute,
+			err:       domain.ErrUnauthorized,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ar := new(mocks.AuthRepository)
+			rr := new(mocks.RefreshTokenRepository)
+			rr.On("Add", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			rr.On("Pop", "old").Return(3, nil)
+			ar.On("GetByID", 3).Return(user, nil)
+			tfu := new(mocks.TwoFactorUsecase)
+
+			issuer := usecase.NewTokenAuthUsecase(ar, rr, tfu, test.keyring, test.accessTTL, time.Hour)
+			pair, err := issuer.Refresh("old")
+			require.NoError(t, err)
+
+			checker := usecase.NewTokenAuthUsecase(ar, rr, tfu, newKeyring(t), time.Minute, time.Hour)
+			sc, err := checker.RetrieveSessionContext(pair.AccessToken)
+
+			assert.ErrorIs(t, err, test.err)
+			if test.err == nil {
+				assert.Equal(t, 3, sc.UserID)
+			}
+		})
+	}
+}
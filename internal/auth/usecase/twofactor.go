@@ -0,0 +1,325 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+	"github.com/ellexo2456/FilmLib/internal/totp"
+)
+
+const (
+	// how long a user has to enter the code after the password
+	mfaChallengeTTL   = 5 * time.Minute
+	recoveryCodeCount = 10
+	recoveryCodeSize  = 5
+)
+
+var recoveryEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+type twoFactorUsecase struct {
+	authRepo      domain.AuthRepository
+	twoFactorRepo domain.TwoFactorRepository
+	challengeRepo domain.MFAChallengeRepository
+	sessionRepo   domain.SessionRepository
+	rememberRepo  domain.RememberTokenRepository
+	issuer        string
+	policy        domain.SessionPolicy
+}
+
+func NewTwoFactorUsecase(ar domain.AuthRepository, tfr domain.TwoFactorRepository, cr domain.MFAChallengeRepository,
+	sr domain.SessionRepository, rmr domain.RememberTokenRepository, issuer string,
+	policy domain.SessionPolicy) domain.TwoFactorUsecase {
+	return &twoFactorUsecase{
+		authRepo:      ar,
+		twoFactorRepo: tfr,
+		challengeRepo: cr,
+		sessionRepo:   sr,
+		rememberRepo:  rmr,
+		issuer:        issuer,
+		policy:        policy,
+	}
+}
+
+// Required reports whether the login of the user needs the second step:
+// either the user has enabled 2FA or it is mandatory for their role.
+func (u *twoFactorUsecase) Required(user domain.User) (bool, error) {
+	t, err := u.twoFactorRepo.Get(user.ID)
+	if err != nil {
+		return false, err
+	}
+	if t.Enabled {
+		return true, nil
+	}
+
+	return u.twoFactorRepo.IsRequired(user.Role)
+}
+
+func (u *twoFactorUsecase) Challenge(user domain.User) (domain.Session, error) {
+	token, err := newToken()
+	if err != nil {
+		logs.LogError(logs.Logger, "auth/usecase", "Challenge", err, err.Error())
+		return domain.Session{}, err
+	}
+
+	if err = u.challengeRepo.Add(token, user.ID, mfaChallengeTTL); err != nil {
+		return domain.Session{}, err
+	}
+
+	return domain.Session{
+		Token:       token,
+		ExpiresAt:   time.Now().Add(mfaChallengeTTL),
+		UserID:      user.ID,
+		Role:        user.Role,
+		Verified:    user.Verified,
+		MFARequired: true,
+	}, nil
+}
+
+// Setup generates a new secret. 2FA stays disabled until the first
+// code is confirmed with Enable.
+func (u *twoFactorUsecase) Setup(userID int) (domain.TOTPSetup, error) {
+	t, err := u.twoFactorRepo.Get(userID)
+	if err != nil {
+		return domain.TOTPSetup{}, err
+	}
+	if t.Enabled {
+		return domain.TOTPSetup{}, domain.ErrAlreadyExists
+	}
+
+	user, err := u.authRepo.GetByID(userID)
+	if err != nil {
+		return domain.TOTPSetup{}, err
+	}
+
+	secret, err := totp.NewSecret()
+	if err != nil {
+		logs.LogError(logs.Logger, "auth/usecase", "Setup", err, err.Error())
+		return domain.TOTPSetup{}, err
+	}
+	if err = u.twoFactorRepo.SetSecret(userID, secret); err != nil {
+		return domain.TOTPSetup{}, err
+	}
+
+	return domain.TOTPSetup{
+		Secret: totp.EncodeSecret(secret),
+		URI:    totp.URI(u.issuer, user.Email, secret),
+	}, nil
+}
+
+// Enable confirms the secret with a code and returns the recovery codes.
+// They are shown only once, the database keeps the hashes.
+func (u *twoFactorUsecase) Enable(userID int, code string) ([]string, error) {
+	t, err := u.twoFactorRepo.Get(userID)
+	if err != nil {
+		return nil, err
+	}
+	if t.Enabled {
+		return nil, domain.ErrAlreadyExists
+	}
+	if t.Secret == nil {
+		return nil, domain.ErrBadRequest
+	}
+
+	if err = u.checkTOTP(userID, t, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		logs.LogError(logs.Logger, "auth/usecase", "Enable", err, err.Error())
+		return nil, err
+	}
+	if err = u.twoFactorRepo.Enable(userID, hashes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+func (u *twoFactorUsecase) Disable(userID int, role domain.Role, code string) error {
+	required, err := u.twoFactorRepo.IsRequired(role)
+	if err != nil {
+		return err
+	}
+	if required {
+		return domain.ErrForbidden
+	}
+
+	t, err := u.twoFactorRepo.Get(userID)
+	if err != nil {
+		return err
+	}
+	if !t.Enabled {
+		return domain.ErrBadRequest
+	}
+
+	if err = u.checkCode(userID, t, code); err != nil {
+		return err
+	}
+
+	return u.twoFactorRepo.Disable(userID)
+}
+
+// SetupByChallenge lets a user whose role requires 2FA enroll during
+// the login, before they have a session.
+func (u *twoFactorUsecase) SetupByChallenge(mfaToken string) (domain.TOTPSetup, error) {
+	userID, err := u.challengeRepo.Get(mfaToken)
+	if err != nil {
+		return domain.TOTPSetup{}, err
+	}
+
+	return u.Setup(userID)
+}
+
+// CompleteLogin checks the code and starts the session. The challenge
+// is consumed by the first attempt, so a wrong code means a new login.
+// A user who enrolls during the login gets the recovery codes as well.
+func (u *twoFactorUsecase) ChallengeEmail(mfaToken string) (string, error) {
+	userID, err := u.challengeRepo.Get(mfaToken)
+	if err != nil {
+		return "", err
+	}
+
+	user, err := u.authRepo.GetByID(userID)
+	if err != nil {
+		return "", err
+	}
+
+	return user.Email, nil
+}
+
+func (u *twoFactorUsecase) CompleteLogin(mfaToken, code string, rememberMe bool,
+	client domain.Client) (domain.Session, []string, error) {
+	userID, err := u.challengeRepo.Get(mfaToken)
+	if err != nil {
+		return domain.Session{}, nil, err
+	}
+
+	t, err := u.twoFactorRepo.Get(userID)
+	if err != nil {
+		return domain.Session{}, nil, err
+	}
+	// the secret must be set up first, don`t burn the challenge
+	if !t.Enabled && t.Secret == nil {
+		return domain.Session{}, nil, domain.ErrBadRequest
+	}
+
+	if _, err = u.challengeRepo.Pop(mfaToken); err != nil {
+		return domain.Session{}, nil, err
+	}
+
+	var codes []string
+	if t.Enabled {
+		err = u.checkCode(userID, t, code)
+	} else {
+		codes, err = u.Enable(userID, code)
+	}
+	if err != nil {
+		return domain.Session{}, nil, err
+	}
+
+	user, err := u.authRepo.GetByID(userID)
+	if err != nil {
+		return domain.Session{}, nil, err
+	}
+	if user.Disabled {
+		return domain.Session{}, nil, domain.ErrDisabled
+	}
+
+	session, err := startSession(u.sessionRepo, u.policy, user, client)
+	if err != nil {
+		return domain.Session{}, nil, err
+	}
+
+	if rememberMe {
+		session, err = rememberSession(u.rememberRepo, u.policy, session, "")
+		if err != nil {
+			return domain.Session{}, nil, err
+		}
+	}
+
+	return session, codes, nil
+}
+
+// checkCode accepts a TOTP code or an unused recovery code.
+func (u *twoFactorUsecase) checkCode(userID int, t domain.TOTP, code string) error {
+	err := u.checkTOTP(userID, t, code)
+	if !errors.Is(err, domain.ErrWrongCredentials) {
+		return err
+	}
+
+	used, err := u.twoFactorRepo.UseRecoveryCode(userID, hashRecoveryCode(code))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return domain.ErrWrongCredentials
+	}
+
+	return nil
+}
+
+// checkTOTP also rejects a code whose step has been used already.
+func (u *twoFactorUsecase) checkTOTP(userID int, t domain.TOTP, code string) error {
+	step, ok := totp.Validate(t.Secret, code, time.Now())
+	if !ok {
+		return domain.ErrWrongCredentials
+	}
+
+	updated, err := u.twoFactorRepo.UpdateLastStep(userID, step)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return domain.ErrWrongCredentials
+	}
+
+	return nil
+}
+
+// loginSession starts the session or, if the user needs the second
+// step, a challenge for it.
+func loginSession(tfu domain.TwoFactorUsecase, sr domain.SessionRepository, policy domain.SessionPolicy,
+	user domain.User, client domain.Client) (domain.Session, error) {
+	required, err := tfu.Required(user)
+	if err != nil {
+		return domain.Session{}, err
+	}
+	if required {
+		return tfu.Challenge(user)
+	}
+
+	return startSession(sr, policy, user, client)
+}
+
+func newRecoveryCodes() ([]string, [][]byte, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([][]byte, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, recoveryCodeSize)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+
+		code := strings.ToLower(recoveryEncoding.EncodeToString(b))
+		code = code[:4] + "-" + code[4:]
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode ignores the case and the dash, so the code can be
+// typed either way.
+func hashRecoveryCode(code string) []byte {
+	code = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(code))
+	return sum[:]
+}
@@ -0,0 +1,390 @@
+package usecase_test
+
+import (
+	"crypto/sha256"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ellexo2456/FilmLib/internal/auth/usecase"
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	"github.com/ellexo2456/FilmLib/internal/domain/mocks"
+	"github.com/ellexo2456/FilmLib/internal/totp"
+)
+
+type twoFactorMocks struct {
+	auth      *mocks.AuthRepository
+	twoFactor *mocks.TwoFactorRepository
+	challenge *mocks.MFAChallengeRepository
+	session   *mocks.SessionRepository
+	remember  *mocks.RememberTokenRepository
+}
+
+func newTwoFactorMocks() twoFactorMocks {
+	return twoFactorMocks{
+		auth:      new(mocks.AuthRepository),
+		twoFactor: new(mocks.TwoFactorRepository),
+		challenge: new(mocks.MFAChallengeRepository),
+		session:   new(mocks.SessionRepository),
+		remember:  new(mocks.RememberTokenRepository),
+	}
+}
+
+func (m twoFactorMocks) usecase() domain.TwoFactorUsecase {
+	return usecase.NewTwoFactorUsecase(m.auth, m.twoFactor, m.challenge, m.session, m.remember, "FilmLib", sessionPolicy)
+}
+
+func (m twoFactorMocks) assert(t *testing.T) {
+	m.auth.AssertExpectations(t)
+	m.twoFactor.AssertExpectations(t)
+	m.challenge.AssertExpectations(t)
+	m.session.AssertExpectations(t)
+	m.remember.AssertExpectations(t)
+}
+
+var totpSecret = []byte("12345678901234567890")
+
+func currentCode() string {
+	return totp.Code(totpSecret, totp.Step(time.Now()))
+}
+
+func recoveryHash(code string) []byte {
+	sum := sha256.Sum256([]byte(code))
+	return sum[:]
+}
+
+func TestTwoFactorRequired(t *testing.T) {
+	tests := []struct {
+		name            string
+		setExpectations func(m twoFactorMocks)
+		required        bool
+	}{
+		{
+			name: "GoodCase/Enabled",
+			setExpectations: func(m twoFactorMocks) {
+				m.twoFactor.On("Get", 1).Return(domain.TOTP{Secret: totpSecret, Enabled: true}, nil)
+			},
+			required: true,
+		},
+		{
+			name: "GoodCase/RequiredForRole",
+			setExpectations: func(m twoFactorMocks) {
+				m.twoFactor.On("Get", 1).Return(domain.TOTP{}, nil)
+				m.twoFactor.On("IsRequired", domain.Moder).Return(true, nil)
+			},
+			required: true,
+		},
+		{
+			name: "GoodCase/NotRequired",
+			setExpectations: func(m twoFactorMocks) {
+				m.twoFactor.On("Get", 1).Return(domain.TOTP{}, nil)
+				m.twoFactor.On("IsRequired", domain.Moder).Return(false, nil)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := newTwoFactorMocks()
+			test.setExpectations(m)
+
+			required, err := m.usecase().Required(domain.User{ID: 1, Role: domain.Moder})
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.required, required)
+			m.assert(t)
+		})
+	}
+}
+
+func TestTwoFactorSetup(t *testing.T) {
+	m := newTwoFactorMocks()
+	m.twoFactor.On("Get", 1).Return(domain.TOTP{}, nil)
+	m.auth.On("GetByID", 1).Return(domain.User{ID: 1, Email: "uvybini@mail.ru"}, nil)
+	m.twoFactor.On("SetSecret", 1, mock.MatchedBy(func(s []byte) bool { return len(s) == totp.SecretSize })).Return(nil)
+
+	setup, err := m.usecase().Setup(1)
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, setup.Secret)
+	assert.True(t, strings.HasPrefix(setup.URI, "otpauth://totp/FilmLib:uvybini@mail.ru?"))
+	assert.Contains(t, setup.URI, "secret="+setup.Secret)
+	m.assert(t)
+
+	m = newTwoFactorMocks()
+	m.twoFactor.On("Get", 1).Return(domain.TOTP{Secret: totpSecret, Enabled: true}, nil)
+
+	_, err = m.usecase().Setup(1)
+
+	assert.ErrorIs(t, err, domain.ErrAlreadyExists)
+	m.assert(t)
+}
+
+func TestTwoFactorEnable(t *testing.T) {
+	tests := []struct {
+		name            string
+		code            string
+		setExpectations func(m twoFactorMocks)
+		err             error
+	}{
+		{
+			name: "GoodCase/Common",
+			code: currentCode(),
+			setExpectations: func(m twoFactorMocks) {
+				m.twoFactor.On("Get", 1).Return(domain.TOTP{Secret: totpSecret}, nil)
+				m.twoFactor.On("UpdateLastStep", 1, totp.Step(time.Now())).Return(true, nil)
+				m.twoFactor.On("Enable", 1, mock.MatchedBy(func(h [][]byte) bool { return len(h) == 10 })).Return(nil)
+			},
+		},
+		{
+			name: "BadCase/NotSetUp",
+			code: currentCode(),
+			setExpectations: func(m twoFactorMocks) {
+				m.twoFactor.On("Get", 1).Return(domain.TOTP{}, nil)
+			},
+			err: domain.ErrBadRequest,
+		},
+		{
+			name: "BadCase/AlreadyEnabled",
+			code: currentCode(),
+			setExpectations: func(m twoFactorMocks) {
+				m.twoFactor.On("Get", 1).Return(domain.TOTP{Secret: totpSecret, Enabled: true}, nil)
+			},
+			err: domain.ErrAlreadyExists,
+		},
+		{
+			name: "BadCase/WrongCode",
+			code: "000000x",
+			setExpectations: func(m twoFactorMocks) {
+				m.twoFactor.On("Get", 1).Return(domain.TOTP{Secret: totpSecret}, nil)
+			},
+			err: domain.ErrWrongCredentials,
+		},
+		{
+			name: "BadCase/Replayed",
+			code: currentCode(),
+			setExpectations: func(m twoFactorMocks) {
+				m.twoFactor.On("Get", 1).Return(domain.TOTP{Secret: totpSecret}, nil)
+				m.twoFactor.On("UpdateLastStep", 1, mock.Anything).Return(false, nil)
+			},
+			err: domain.ErrWrongCredentials,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := newTwoFactorMocks()
+			test.setExpectations(m)
+
+			codes, err := m.usecase().Enable(1, test.code)
+
+			assert.ErrorIs(t, err, test.err)
+			if test.err == nil {
+				assert.Len(t, codes, 10)
+			}
+			m.assert(t)
+		})
+	}
+}
+
+func TestTwoFactorDisable(t *testing.T) {
+	tests := []struct {
+		name            string
+		code            string
+		setExpectations func(m twoFactorMocks)
+		err             error
+	}{
+		{
+			name: "GoodCase/TOTP",
+			code: currentCode(),
+			setExpectations: func(m twoFactorMocks) {
+				m.twoFactor.On("IsRequired", domain.Moder).Return(false, nil)
+				m.twoFactor.On("Get", 1).Return(domain.TOTP{Secret: totpSecret, Enabled: true}, nil)
+				m.twoFactor.On("UpdateLastStep", 1, mock.Anything).Return(true, nil)
+				m.twoFactor.On("Disable", 1).Return(nil)
+			},
+		},
+		{
+			name: "GoodCase/RecoveryCode",
+			code: "ABCD-EFGH",
+			setExpectations: func(m twoFactorMocks) {
+				m.twoFactor.On("IsRequired", domain.Moder).Return(false, nil)
+				m.twoFactor.On("Get", 1).Return(domain.TOTP{Secret: totpSecret, Enabled: true}, nil)
+				m.twoFactor.On("UseRecoveryCode", 1, recoveryHash("abcdefgh")).Return(true, nil)
+				m.twoFactor.On("Disable", 1).Return(nil)
+			},
+		},
+		{
+			name: "BadCase/RequiredForRole",
+			code: currentCode(),
+			setExpectations: func(m twoFactorMocks) {
+				m.twoFactor.On("IsRequired", domain.Moder).Return(true, nil)
+			},
+			err: domain.ErrForbidden,
+		},
+		{
+			name: "BadCase/WrongCode",
+			code: "abcd-efgh",
+			setExpectations: func(m twoFactorMocks) {
+				m.twoFactor.On("IsRequired", domain.Moder).Return(false, nil)
+				m.twoFactor.On("Get", 1).Return(domain.TOTP{Secret: totpSecret, Enabled: true}, nil)
+				m.twoFactor.On("UseRecoveryCode", 1, mock.Anything).Return(false, nil)
+			},
+			err: domain.ErrWrongCredentials,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := newTwoFactorMocks()
+			test.setExpectations(m)
+
+			err := m.usecase().Disable(1, domain.Moder, test.code)
+
+			assert.ErrorIs(t, err, test.err)
+			m.assert(t)
+		})
+	}
+}
+
+func TestTwoFactorCompleteLogin(t *testing.T) {
+	user := domain.User{ID: 1, Role: domain.Moder, Verified: true}
+
+	tests := []struct {
+		name            string
+		code            string
+		rememberMe      bool
+		setExpectations func(m twoFactorMocks)
+		recoveryCodes   bool
+		err             error
+	}{
+		{
+			name: "GoodCase/TOTP",
+			code: currentCode(),
+			setExpectations: func(m twoFactorMocks) {
+				m.challenge.On("Get", "mfa").Return(1, nil)
+				m.twoFactor.On("Get", 1).Return(domain.TOTP{Secret: totpSecret, Enabled: true}, nil)
+				m.challenge.On("Pop", "mfa").Return(1, nil)
+				m.twoFactor.On("UpdateLastStep", 1, mock.Anything).Return(true, nil)
+				m.auth.On("GetByID", 1).Return(user, nil)
+				m.session.On("Add", mock.MatchedBy(func(s domain.Session) bool { return s.UserID == 1 })).Return(nil)
+			},
+		},
+		{
+			name: "GoodCase/Enroll",
+			code: currentCode(),
+			setExpectations: func(m twoFactorMocks) {
+				m.challenge.On("Get", "mfa").Return(1, nil)
+				m.twoFactor.On("Get", 1).Return(domain.TOTP{Secret: totpSecret}, nil)
+				m.challenge.On("Pop", "mfa").Return(1, nil)
+				m.twoFactor.On("UpdateLastStep", 1, mock.Anything).Return(true, nil)
+				m.twoFactor.On("Enable", 1, mock.Anything).Return(nil)
+				m.auth.On("GetByID", 1).Return(user, nil)
+				m.session.On("Add", mock.Anything).Return(nil)
+			},
+			recoveryCodes: true,
+		},
+		{
+			name:       "GoodCase/RememberMe",
+			code:       currentCode(),
+			rememberMe: true,
+			setExpectations: func(m twoFactorMocks) {
+				m.challenge.On("Get", "mfa").Return(1, nil)
+				m.twoFactor.On("Get", 1).Return(domain.TOTP{Secret: totpSecret, Enabled: true}, nil)
+				m.challenge.On("Pop", "mfa").Return(1, nil)
+				m.twoFactor.On("UpdateLastStep", 1, mock.Anything).Return(true, nil)
+				m.auth.On("GetByID", 1).Return(user, nil)
+				m.session.On("Add", mock.Anything).Return(nil)
+				m.remember.On("Add", mock.AnythingOfType("string"), mock.MatchedBy(func(rt domain.RememberToken) bool {
+					return rt.UserID == 1
+				}), sessionPolicy.RememberTTL).Return(nil)
+			},
+		},
+		{
+			name: "BadCase/NotSetUp",
+			code: currentCode(),
+			setExpectations: func(m twoFactorMocks) {
+				m.challenge.On("Get", "mfa").Return(1, nil)
+				m.twoFactor.On("Get", 1).Return(domain.TOTP{}, nil)
+			},
+			err: domain.ErrBadRequest,
+		},
+		{
+			name: "BadCase/UnknownChallenge",
+			code: currentCode(),
+			setExpectations: func(m twoFactorMocks) {
+				m.challenge.On("Get", "mfa").Return(0, domain.ErrInvalidToken)
+			},
+			err: domain.ErrInvalidToken,
+		},
+		{
+			name: "BadCase/WrongCode",
+			code: "abcd-efgh",
+			setExpectations: func(m twoFactorMocks) {
+				m.challenge.On("Get", "mfa").Return(1, nil)
+				m.twoFactor.On("Get", 1).Return(domain.TOTP{Secret: totpSecret, Enabled: true}, nil)
+				m.challenge.On("Pop", "mfa").Return(1, nil)
+				m.twoFactor.On("UseRecoveryCode", 1, mock.Anything).Return(false, nil)
+			},
+			err: domain.ErrWrongCredentials,
+		},
+		{
+			name: "BadCase/Disabled",
+			code: currentCode(),
+			setExpectations: func(m twoFactorMocks) {
+				m.challenge.On("Get", "mfa").Return(1, nil)
+				m.twoFactor.On("Get", 1).Return(domain.TOTP{Secret: totpSecret, Enabled: true}, nil)
+				m.challenge.On("Pop", "mfa").Return(1, nil)
+				m.twoFactor.On("UpdateLastStep", 1, mock.Anything).Return(true, nil)
+				m.auth.On("GetByID", 1).Return(domain.User{ID: 1, Disabled: true}, nil)
+			},
+			err: domain.ErrDisabled,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := newTwoFactorMocks()
+			test.setExpectations(m)
+
+			session, codes, err := m.usecase().CompleteLogin("mfa", test.code, test.rememberMe, domain.Client{})
+
+			assert.ErrorIs(t, err, test.err)
+			if test.err == nil {
+				assert.Equal(t, 1, session.UserID)
+				assert.False(t, session.MFARequired)
+				assert.Equal(t, test.recoveryCodes, codes != nil)
+				assert.Equal(t, test.rememberMe, session.RememberToken != "")
+			}
+			m.assert(t)
+		})
+	}
+}
+
+func TestTwoFactorChallenge(t *testing.T) {
+	m := newTwoFactorMocks()
+	m.challenge.On("Add", mock.AnythingOfType("string"), 1, 5*time.Minute).Return(nil)
+
+	session, err := m.usecase().Challenge(domain.User{ID: 1, Role: domain.Moder})
+
+	require.NoError(t, err)
+	assert.True(t, session.MFARequired)
+	assert.NotEmpty(t, session.Token)
+	m.assert(t)
+}
+
+func TestTwoFactorChallengeEmail(t *testing.T) {
+	m := newTwoFactorMocks()
+	m.challenge.On("Get", "mfa").Return(1, nil)
+	m.auth.On("GetByID", 1).Return(domain.User{ID: 1, Email: "uvybini@mail.ru"}, nil)
+
+	email, err := m.usecase().ChallengeEmail("mfa")
+
+	require.NoError(t, err)
+	assert.Equal(t, "uvybini@mail.ru", email)
+	m.assert(t)
+}
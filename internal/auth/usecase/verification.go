@@ -0,0 +1,161 @@
+package usecase
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+)
+
+const verificationMailSubject = "Confirm your FilmLib email"
+
+type verificationUsecase struct {
+	authRepo  domain.AuthRepository
+	mailer    domain.Mailer
+	secret    []byte
+	verifyURL string
+	ttl       time.Duration
+}
+
+// NewVerificationUsecase creates a usecase which mails links of the form
+// verifyURL + token. Tokens are signed with secret and aren`t stored anywhere.
+func NewVerificationUsecase(ar domain.AuthRepository, m domain.Mailer, secret []byte,
+	verifyURL string, ttl time.Duration) domain.VerificationUsecase {
+	return &verificationUsecase{
+		authRepo:  ar,
+		mailer:    m,
+		secret:    secret,
+		verifyURL: verifyURL,
+		ttl:       ttl,
+	}
+}
+
+func (u *verificationUsecase) Send(user domain.User) error {
+	if user.ID <= 0 || user.Email == "" {
+		return domain.ErrBadRequest
+	}
+
+	token := u.sign(user.ID, user.Email, time.Now().Add(u.ttl))
+	err := u.mailer.Send(domain.Mail{
+		To:      user.Email,
+		Subject: verificationMailSubject,
+		Body: "To confirm your email follow the link below. It expires in " + u.ttl.String() + ".\n\n" +
+			u.verifyURL + token,
+	})
+	if err != nil {
+		logs.LogError(logs.Logger, "auth/usecase", "Send", err, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Resend doesn`t tell whether the email is registered or already verified.
+func (u *verificationUsecase) Resend(email string) error {
+	if email == "" {
+		return domain.ErrBadRequest
+	}
+
+	user, err := u.authRepo.GetByEmail(email)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if user.Verified {
+		return nil
+	}
+
+	return u.Send(user)
+}
+
+func (u *verificationUsecase) Verify(token string) error {
+	userID, email, err := u.parse(token)
+	if err != nil {
+		return err
+	}
+
+	user, err := u.authRepo.GetByID(userID)
+	if err != nil {
+		logs.LogError(logs.Logger, "auth/usecase", "Verify", err, err.Error())
+		return err
+	}
+	if user.Email != email {
+		return domain.ErrInvalidToken
+	}
+	if user.Verified {
+		return nil
+	}
+
+	if err = u.authRepo.SetVerified(userID); err != nil {
+		logs.LogError(logs.Logger, "auth/usecase", "Verify", err, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (u *verificationUsecase) IsVerified(userID int) (bool, error) {
+	user, err := u.authRepo.GetByID(userID)
+	if err != nil {
+		return false, err
+	}
+
+	return user.Verified, nil
+}
+
+// sign builds a token of the form payload.signature, where payload is
+// "userID:expiresAt:email". The email is signed too, so changing it
+// invalidates links sent to the old address.
+func (u *verificationUsecase) sign(userID int, email string, expiresAt time.Time) string {
+	payload := strconv.Itoa(userID) + ":" + strconv.FormatInt(expiresAt.Unix(), 10) + ":" + email
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(u.mac(encoded))
+}
+
+func (u *verificationUsecase) parse(token string) (int, string, error) {
+	encoded, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return 0, "", domain.ErrInvalidToken
+	}
+
+	rawSig, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(rawSig, u.mac(encoded)) {
+		return 0, "", domain.ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return 0, "", domain.ErrInvalidToken
+	}
+
+	parts := strings.SplitN(string(payload), ":", 3)
+	if len(parts) != 3 {
+		return 0, "", domain.ErrInvalidToken
+	}
+
+	userID, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, "", domain.ErrInvalidToken
+	}
+	expiresAt, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return 0, "", domain.ErrInvalidToken
+	}
+
+	return userID, parts[2], nil
+}
+
+func (u *verificationUsecase) mac(data string) []byte {
+	h := hmac.New(sha256.New, u.secret)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
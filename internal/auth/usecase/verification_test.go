@@ -0,0 +1,222 @@
+package usecase_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ellexo2456/FilmLib/internal/auth/usecase"
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	"github.com/ellexo2456/FilmLib/internal/domain/mocks"
+)
+
+const verifyURL = "http://front/verify?token="
+
+// sentToken sends a verification mail and returns the token from its link.
+func sentToken(t *testing.T, secret string, ttl time.Duration, user domain.User) string {
+	m := new(mocks.Mailer)
+	var token string
+	m.On("Send", mock.Anything).Run(func(args mock.Arguments) {
+		body := args.Get(0).(domain.Mail).Body
+		token = strings.TrimSpace(body[strings.Index(body, verifyURL)+len(verifyURL):])
+	}).Return(nil)
+
+	vu := usecase.NewVerificationUsecase(new(mocks.AuthRepository), m, []byte(secret), verifyURL, ttl)
+	require.NoError(t, vu.Send(user))
+
+	return token
+}
+
+func TestVerificationSend(t *testing.T) {
+	tests := []struct {
+		name            string
+		user            domain.User
+		setExpectations func(m *mocks.Mailer)
+		err             error
+	}{
+		{
+			name: "GoodCase/Common",
+			user: domain.User{ID: 1, Email: "uvybini@mail.ru"},
+			setExpectations: func(m *mocks.Mailer) {
+				m.On("Send", mock.MatchedBy(func(mail domain.Mail) bool {
+					return mail.To == "uvybini@mail.ru" && strings.Contains(mail.Body, verifyURL)
+				})).Return(nil)
+			},
+		},
+		{
+			name:            "BadCase/NoID",
+			user:            domain.User{Email: "uvybini@mail.ru"},
+			setExpectations: func(m *mocks.Mailer) {},
+			err:             domain.ErrBadRequest,
+		},
+		{
+			name: "BadCase/MailerError",
+			user: domain.User{ID: 1, Email: "uvybini@mail.ru"},
+			setExpectations: func(m *mocks.Mailer) {
+				m.On("Send", mock.Anything).Return(domain.ErrInternalServerError)
+			},
+			err: domain.ErrInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := new(mocks.Mailer)
+			test.setExpectations(m)
+
+			vu := usecase.NewVerificationUsecase(new(mocks.AuthRepository), m, []byte("secret"), verifyURL, time.Hour)
+			err := vu.Send(test.user)
+
+			assert.ErrorIs(t, err, test.err)
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	user := domain.User{ID: 1, Email: "uvybini@mail.ru"}
+
+	tests := []struct {
+		name            string
+		getToken        func() string
+		setExpectations func(ar *mocks.AuthRepository)
+		err             error
+	}{
+		{
+			name: "GoodCase/Common",
+			getToken: func() string {
+				return sentToken(t, "secret", time.Hour, user)
+			},
+			setExpectations: func(ar *mocks.AuthRepository) {
+				ar.On("GetByID", 1).Return(user, nil)
+				ar.On("SetVerified", 1).Return(nil)
+			},
+		},
+		{
+			name: "GoodCase/AlreadyVerified",
+			getToken: func() string {
+				return sentToken(t, "secret", time.Hour, user)
+			},
+			setExpectations: func(ar *mocks.AuthRepository) {
+				ar.On("GetByID", 1).Return(domain.User{ID: 1, Email: user.Email, Verified: true}, nil)
+			},
+		},
+		{
+			name: "BadCase/Expired",
+			getToken: func() string {
+				return sentToken(t, "secret", -time.Minute, user)
+			},
+			setExpectations: func(ar *mocks.AuthRepository) {},
+			err:             domain.ErrInvalidToken,
+		},
+		{
+			name: "BadCase/OtherSecret",
+			getToken: func() string {
+				return sentToken(t, "other secret", time.Hour, user)
+			},
+			setExpectations: func(ar *mocks.AuthRepository) {},
+			err:             domain.ErrInvalidToken,
+		},
+		{
+			name: "BadCase/Malformed",
+			getToken: func() string {
+				return "not a token"
+			},
+			setExpectations: func(ar *mocks.AuthRepository) {},
+			err:             domain.ErrInvalidToken,
+		},
+		{
+			name: "BadCase/EmailChanged",
+			getToken: func() string {
+				return sentToken(t, "secret", time.Hour, user)
+			},
+			setExpectations: func(ar *mocks.AuthRepository) {
+				ar.On("GetByID", 1).Return(domain.User{ID: 1, Email: "new@mail.ru"}, nil)
+			},
+			err: domain.ErrInvalidToken,
+		},
+		{
+			name: "BadCase/UserDeleted",
+			getToken: func() string {
+				return sentToken(t, "secret", time.Hour, user)
+			},
+			setExpectations: func(ar *mocks.AuthRepository) {
+				ar.On("GetByID", 1).Return(domain.User{}, domain.ErrNotFound)
+			},
+			err: domain.ErrNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ar := new(mocks.AuthRepository)
+			test.setExpectations(ar)
+
+			vu := usecase.NewVerificationUsecase(ar, new(mocks.Mailer), []byte("secret"), verifyURL, time.Hour)
+			err := vu.Verify(test.getToken())
+
+			assert.ErrorIs(t, err, test.err)
+			ar.AssertExpectations(t)
+		})
+	}
+}
+
+func TestResend(t *testing.T) {
+	tests := []struct {
+		name            string
+		email           string
+		setExpectations func(ar *mocks.AuthRepository, m *mocks.Mailer)
+		err             error
+	}{
+		{
+			name:  "GoodCase/Common",
+			email: "uvybini@mail.ru",
+			setExpectations: func(ar *mocks.AuthRepository, m *mocks.Mailer) {
+				ar.On("GetByEmail", "uvybini@mail.ru").Return(domain.User{ID: 1, Email: "uvybini@mail.ru"}, nil)
+				m.On("Send", mock.Anything).Return(nil)
+			},
+		},
+		{
+			name:  "GoodCase/AlreadyVerified",
+			email: "uvybini@mail.ru",
+			setExpectations: func(ar *mocks.AuthRepository, m *mocks.Mailer) {
+				ar.On("GetByEmail", "uvybini@mail.ru").Return(domain.User{ID: 1, Email: "uvybini@mail.ru", Verified: true}, nil)
+			},
+		},
+		{
+			name:  "GoodCase/UnknownEmail",
+			email: "nobody@mail.ru",
+			setExpectations: func(ar *mocks.AuthRepository, m *mocks.Mailer) {
+				ar.On("GetByEmail", "nobody@mail.ru").Return(domain.User{}, domain.ErrNotFound)
+			},
+		},
+		{
+			name:  "BadCase/DBError",
+			email: "uvybini@mail.ru",
+			setExpectations: func(ar *mocks.AuthRepository, m *mocks.Mailer) {
+				ar.On("GetByEmail", "uvybini@mail.ru").Return(domain.User{}, errors.New("some db error"))
+			},
+			err: errors.New("some db error"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ar := new(mocks.AuthRepository)
+			m := new(mocks.Mailer)
+			test.setExpectations(ar, m)
+
+			vu := usecase.NewVerificationUsecase(ar, m, []byte("secret"), verifyURL, time.Hour)
+			err := vu.Resend(test.email)
+
+			assert.Equal(t, test.err, err)
+			ar.AssertExpectations(t)
+			m.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package http
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+	"github.com/ellexo2456/FilmLib/internal/middleware"
+)
+
+// maxImportSize limits the import body, larger archives go through the cli.
+const maxImportSize = 32 << 20
+
+var formatsByContentType = map[string]domain.CatalogFormat{
+	"text/csv":             domain.CSVFormat,
+	"application/x-ndjson": domain.NDJSONFormat,
+}
+
+var contentTypesByFormat = map[domain.CatalogFormat]string{
+	domain.CSVFormat:    "text/csv; charset=utf-8",
+	domain.NDJSONFormat: "application/x-ndjson",
+	domain.JSONFormat:   "application/json",
+}
+
+type CatalogHandler struct {
+	CatalogUsecase domain.CatalogUsecase
+}
+
+func NewCatalogHandler(mux *http.ServeMux, cu domain.CatalogUsecase) {
+	handler := &CatalogHandler{
+		CatalogUsecase: cu,
+	}
+
+	mux.Handle("POST /import", middleware.Require(domain.CatalogImport, handler.Import))
+	mux.Handle("GET /export", middleware.Require(domain.CatalogExport, handler.Export))
+}
+
+// Import godoc
+//
+//	@Summary		Imports films, actors and credits.
+//	@Description	Creates or updates films matched by the title and the release date and actors matched by the name and the birthdate, credits link them. Every row is validated and reported. Nothing is saved unless commit is true. Requires catalog:import permission.
+//	@Tags			Catalog
+//	@Accept			text/csv
+//	@Accept			application/x-ndjson
+//	@Param			format	query	string	false	"csv or ndjson, taken from the Content-Type by default"
+//	@Param			commit	query	bool	false	"Saves the changes, the dry run is made by default"
+//	@Param			body	body	string	true	"Rows with type (film, actor or credit), title, description, releaseDate, rating, name, sex and birthdate"
+//	@Produce		json
+//	@Success		200	{object}	object{body=object{report=domain.ImportReport}}
+//	@Failure		400	{object}	object{err=string}
+//	@Failure		401	{object}	object{err=string}
+//	@Failure		403	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/import [post]
+func (h *CatalogHandler) Import(w http.ResponseWriter, r *http.Request) {
+	defer domain.CloseAndAlert(r.Body, "catalog/http", "Import")
+
+	format := domain.CatalogFormat(r.URL.Query().Get(domain.FormatParam))
+	if format == "" {
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		format = formatsByContentType[mediaType]
+	}
+	if !format.Importable() {
+		domain.WriteError(w, "format must be csv or ndjson", http.StatusBadRequest)
+		logs.LogError(logs.Logger, "catalog/http", "Import", domain.ErrBadRequest, "unknown format "+string(format))
+		return
+	}
+
+	commit := r.URL.Query().Get(domain.CommitParam) == "true"
+	body := http.MaxBytesReader(w, r.Body, maxImportSize)
+
+	report, err := h.CatalogUsecase.Import(body, format, commit, domain.RequestAuditContext(r))
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "catalog/http", "Import", err, err.Error())
+		return
+	}
+
+	domain.WriteResponse(
+		w,
+		map[string]interface{}{
+			"report": report,
+		},
+		http.StatusOK,
+	)
+}
+
+// Export godoc
+//
+//	@Summary		Exports films, actors and credits.
+//	@Description	Streams the catalog, films first, then actors and credits. With updatedSince only the changed entities are exported, deleted ones included with deletedAt, and the whole cast of every changed film. Requires catalog:export permission.
+//	@Tags			Catalog
+//	@Param			format			query	string		false	"csv, ndjson or json, ndjson by default"
+//	@Param			entity			query	[]string	false	"film, actor or credit, everything by default"	collectionFormat(csv)
+//	@Param			releasedFrom	query	string		false	"First release date of the films, YYYY-MM-DD"
+//	@Param			releasedTo		query	string		false	"Last release date of the films, YYYY-MM-DD"
+//	@Param			updatedSince	query	string		false	"Exports the changes made since the time, RFC 3339"
+//	@Produce		text/csv
+//	@Produce		application/x-ndjson
+//	@Produce		json
+//	@Success		200	{array}		domain.ExportedRecord
+//	@Failure		400	{object}	object{err=string}
+//	@Failure		401	{object}	object{err=string}
+//	@Failure		403	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/export [get]
+func (h *CatalogHandler) Export(w http.ResponseWriter, r *http.Request) {
+	format := domain.CatalogFormat(r.URL.Query().Get(domain.FormatParam))
+	if format == "" {
+		format = domain.NDJSONFormat
+	}
+
+	filter, err := parseExportFilter(r)
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "catalog/http", "Export", err, err.Error())
+		return
+	}
+
+	ew := &exportWriter{w: w, contentType: contentTypesByFormat[format]}
+	err = h.CatalogUsecase.Export(filter, format, ew)
+	if err != nil && !ew.started {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "catalog/http", "Export", err, err.Error())
+		return
+	}
+	if err != nil {
+		// the status is already sent, the client gets a truncated body
+		logs.LogError(logs.Logger, "catalog/http", "Export", err, "export is interrupted: "+err.Error())
+	}
+}
+
+func parseExportFilter(r *http.Request) (domain.ExportFilter, error) {
+	queryParams := r.URL.Query()
+
+	var filter domain.ExportFilter
+	for _, param := range queryParams[domain.EntityParam] {
+		for _, t := range strings.Split(param, ",") {
+			filter.Types = append(filter.Types, domain.ImportRecordType(strings.TrimSpace(t)))
+		}
+	}
+
+	var err error
+	dates := map[string]*time.Time{
+		domain.ReleasedFromParam: &filter.ReleasedFrom,
+		domain.ReleasedToParam:   &filter.ReleasedTo,
+	}
+	for name, dst := range dates {
+		if v := queryParams.Get(name); v != "" {
+			if *dst, err = time.Parse(time.DateOnly, v); err != nil {
+				return domain.ExportFilter{}, err
+			}
+		}
+	}
+	if v := queryParams.Get(domain.UpdatedSinceParam); v != "" {
+		if filter.UpdatedSince, err = time.Parse(time.RFC3339, v); err != nil {
+			return domain.ExportFilter{}, err
+		}
+	}
+
+	return filter, nil
+}
+
+// exportWriter sends the headers with the first bytes of the export, so
+// an error before them is still reported with its status.
+type exportWriter struct {
+	w           http.ResponseWriter
+	contentType string
+	started     bool
+}
+
+func (ew *exportWriter) Write(p []byte) (int, error) {
+	if !ew.started {
+		ew.w.Header().Set("Content-Type", ew.contentType)
+		ew.w.WriteHeader(http.StatusOK)
+		ew.started = true
+	}
+
+	return ew.w.Write(p)
+}
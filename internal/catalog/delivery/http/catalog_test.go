@@ -0,0 +1,217 @@
+package http_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	catalog_http "github.com/ellexo2456/FilmLib/internal/catalog/delivery/http"
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	"github.com/ellexo2456/FilmLib/internal/domain/mocks"
+)
+
+func TestImport(t *testing.T) {
+	moderCtx := context.WithValue(context.Background(), domain.SessionContextKey,
+		domain.SessionContext{UserID: 1, Role: domain.Moder, Verified: true})
+	userCtx := context.WithValue(context.Background(), domain.SessionContextKey,
+		domain.SessionContext{UserID: 2, Role: domain.Usr, Verified: true})
+
+	tests := []struct {
+		name                 string
+		target               string
+		contentType          string
+		ctx                  context.Context
+		setUCaseExpectations func(usecase *mocks.CatalogUsecase)
+		status               int
+	}{
+		{
+			name:        "GoodCase/DryRunByContentType",
+			target:      "/import",
+			contentType: "text/csv; charset=utf-8",
+			ctx:         moderCtx,
+			setUCaseExpectations: func(usecase *mocks.CatalogUsecase) {
+				usecase.On("Import", mock.Anything, domain.CSVFormat, false, mock.Anything).
+					Return(domain.ImportReport{Created: 1}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:        "GoodCase/Commit",
+			target:      "/import?format=ndjson&commit=true",
+			contentType: "application/octet-stream",
+			ctx:         moderCtx,
+			setUCaseExpectations: func(usecase *mocks.CatalogUsecase) {
+				usecase.On("Import", mock.Anything, domain.NDJSONFormat, true, mock.Anything).
+					Return(domain.ImportReport{Committed: true, Failed: 1}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:                 "BadCase/UnknownFormat",
+			target:               "/import",
+			contentType:          "application/xml",
+			ctx:                  moderCtx,
+			setUCaseExpectations: func(usecase *mocks.CatalogUsecase) {},
+			status:               http.StatusBadRequest,
+		},
+		{
+			name:        "BadCase/InvalidHeader",
+			target:      "/import?format=csv",
+			contentType: "text/csv",
+			ctx:         moderCtx,
+			setUCaseExpectations: func(usecase *mocks.CatalogUsecase) {
+				usecase.On("Import", mock.Anything, domain.CSVFormat, false, mock.Anything).
+					Return(domain.ImportReport{}, domain.ErrBadRequest)
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:                 "BadCase/User",
+			target:               "/import?format=csv",
+			contentType:          "text/csv",
+			ctx:                  userCtx,
+			setUCaseExpectations: func(usecase *mocks.CatalogUsecase) {},
+			status:               http.StatusForbidden,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockUsecase := new(mocks.CatalogUsecase)
+			test.setUCaseExpectations(mockUsecase)
+
+			mux := http.NewServeMux()
+			catalog_http.NewCatalogHandler(mux, mockUsecase)
+
+			req := httptest.NewRequest("POST", test.target, strings.NewReader("type,title\n"))
+			req.Header.Set("Content-Type", test.contentType)
+			req = req.WithContext(test.ctx)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestExport(t *testing.T) {
+	moderCtx := context.WithValue(context.Background(), domain.SessionContextKey,
+		domain.SessionContext{UserID: 1, Role: domain.Moder, Verified: true})
+	userCtx := context.WithValue(context.Background(), domain.SessionContextKey,
+		domain.SessionContext{UserID: 2, Role: domain.Usr, Verified: true})
+
+	tests := []struct {
+		name                 string
+		target               string
+		ctx                  context.Context
+		setUCaseExpectations func(usecase *mocks.CatalogUsecase)
+		status               int
+		contentType          string
+		body                 string
+	}{
+		{
+			name:   "GoodCase/Filters",
+			target: "/export?format=csv&entity=film,credit&releasedFrom=1999-01-01&updatedSince=2024-03-01T00:00:00Z",
+			ctx:    moderCtx,
+			setUCaseExpectations: func(usecase *mocks.CatalogUsecase) {
+				filter := domain.ExportFilter{
+					Types:        []domain.ImportRecordType{domain.FilmRecord, domain.CreditRecord},
+					ReleasedFrom: time.Date(1999, 1, 1, 0, 0, 0, 0, time.UTC),
+					UpdatedSince: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+				}
+				usecase.On("Export", filter, domain.CSVFormat, mock.Anything).
+					Run(func(args mock.Arguments) {
+						args.Get(2).(io.Writer).Write([]byte("type\n"))
+					}).
+					Return(nil)
+			},
+			status:      http.StatusOK,
+			contentType: "text/csv; charset=utf-8",
+			body:        "type\n",
+		},
+		{
+			name:   "GoodCase/NDJSONByDefault",
+			target: "/export",
+			ctx:    moderCtx,
+			setUCaseExpectations: func(usecase *mocks.CatalogUsecase) {
+				usecase.On("Export", domain.ExportFilter{}, domain.NDJSONFormat, mock.Anything).
+					Run(func(args mock.Arguments) {
+						args.Get(2).(io.Writer).Write([]byte("{}\n"))
+					}).
+					Return(nil)
+			},
+			status:      http.StatusOK,
+			contentType: "application/x-ndjson",
+			body:        "{}\n",
+		},
+		{
+			name:   "BadCase/Interrupted",
+			target: "/export?format=json",
+			ctx:    moderCtx,
+			setUCaseExpectations: func(usecase *mocks.CatalogUsecase) {
+				usecase.On("Export", domain.ExportFilter{}, domain.JSONFormat, mock.Anything).
+					Run(func(args mock.Arguments) {
+						args.Get(2).(io.Writer).Write([]byte("[\n{}"))
+					}).
+					Return(domain.ErrInternalServerError)
+			},
+			status:      http.StatusOK,
+			contentType: "application/json",
+			body:        "[\n{}",
+		},
+		{
+			name:   "BadCase/UnknownFormat",
+			target: "/export?format=xml",
+			ctx:    moderCtx,
+			setUCaseExpectations: func(usecase *mocks.CatalogUsecase) {
+				usecase.On("Export", domain.ExportFilter{}, domain.CatalogFormat("xml"), mock.Anything).
+					Return(domain.ErrBadRequest)
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:                 "BadCase/WrongUpdatedSince",
+			target:               "/export?updatedSince=yesterday",
+			ctx:                  moderCtx,
+			setUCaseExpectations: func(usecase *mocks.CatalogUsecase) {},
+			status:               http.StatusBadRequest,
+		},
+		{
+			name:                 "BadCase/User",
+			target:               "/export",
+			ctx:                  userCtx,
+			setUCaseExpectations: func(usecase *mocks.CatalogUsecase) {},
+			status:               http.StatusForbidden,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockUsecase := new(mocks.CatalogUsecase)
+			test.setUCaseExpectations(mockUsecase)
+
+			mux := http.NewServeMux()
+			catalog_http.NewCatalogHandler(mux, mockUsecase)
+
+			req := httptest.NewRequest("GET", test.target, nil)
+			req = req.WithContext(test.ctx)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			if test.contentType != "" {
+				assert.Equal(t, test.contentType, rec.Header().Get("Content-Type"))
+				assert.Equal(t, test.body, rec.Body.String())
+			}
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,487 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+)
+
+const selectFilmQuery = `
+	SELECT id, description, rating
+	FROM film
+	WHERE title = $1
+	  AND release_date = $2
+	  AND deleted_at IS NULL
+	ORDER BY id
+	LIMIT 1
+`
+
+const insertFilmQuery = `
+	INSERT INTO film (title, description, release_date, rating)
+	VALUES ($1, $2, $3, $4)
+	RETURNING id
+`
+
+const updateFilmQuery = `
+	UPDATE film
+	SET description = $1, rating = $2, version = version + 1
+	WHERE id = $3
+`
+
+const selectActorQuery = `
+	SELECT id, sex
+	FROM actor
+	WHERE name = $1
+	  AND birthdate = $2
+	  AND deleted_at IS NULL
+	ORDER BY id
+	LIMIT 1
+`
+
+const insertActorQuery = `
+	INSERT INTO actor (name, sex, birthdate)
+	VALUES ($1, $2, $3)
+	RETURNING id
+`
+
+const updateActorQuery = `
+	UPDATE actor
+	SET sex = $1, version = version + 1
+	WHERE id = $2
+`
+
+const insertCreditQuery = `
+	INSERT INTO film_actor (film_id, actor_id)
+	VALUES ($1, $2)
+	ON CONFLICT DO NOTHING
+`
+
+const bumpFilmVersionQuery = `
+	UPDATE film
+	SET version = version + 1
+	WHERE id = $1
+`
+
+// the revisions are the same as the ones of the films and actors
+// repositories, an imported change is numbered like any other
+const filmSnapshot = `
+	jsonb_build_object(
+		'id', f.id,
+		'title', f.title,
+		'description', f.description,
+		'releaseDate', f.release_date,
+		'rating', f.rating,
+		'actors', COALESCE((SELECT jsonb_agg(jsonb_build_object(
+		                               'id', a.id,
+		                               'name', a.name,
+		                               'sex', a.sex,
+		                               'birthdate', a.birthdate) ORDER BY a.id)
+		                    FROM film_actor fa
+		                             JOIN actor a ON a.id = fa.actor_id
+		                    WHERE fa.film_id = f.id
+		                      AND a.deleted_at IS NULL), '[]'::JSONB))
+`
+
+const insertFilmRevisionQuery = `
+	INSERT INTO film_revision (film_id, revision, user_id, data)
+	SELECT f.id,
+	       COALESCE((SELECT MAX(revision) FROM film_revision WHERE film_id = f.id), 0) + 1,
+	       NULLIF($2, 0),` + filmSnapshot + `
+	FROM film f
+	WHERE f.id = $1
+`
+
+const insertFilmBaseRevisionQuery = `
+	INSERT INTO film_revision (film_id, revision, user_id, data)
+	SELECT f.id, 1, NULL,` + filmSnapshot + `
+	FROM film f
+	WHERE f.id = $1
+	  AND NOT EXISTS(SELECT 1 FROM film_revision WHERE film_id = f.id)
+	ON CONFLICT DO NOTHING
+`
+
+const actorSnapshot = `
+	jsonb_build_object(
+		'id', a.id,
+		'name', a.name,
+		'sex', a.sex,
+		'birthdate', a.birthdate)
+`
+
+const insertActorRevisionQuery = `
+	INSERT INTO actor_revision (actor_id, revision, user_id, data)
+	SELECT a.id,
+	       COALESCE((SELECT MAX(revision) FROM actor_revision WHERE actor_id = a.id), 0) + 1,
+	       NULLIF($2, 0),` + actorSnapshot + `
+	FROM actor a
+	WHERE a.id = $1
+`
+
+const insertActorBaseRevisionQuery = `
+	INSERT INTO actor_revision (actor_id, revision, user_id, data)
+	SELECT a.id, 1, NULL,` + actorSnapshot + `
+	FROM actor a
+	WHERE a.id = $1
+	  AND NOT EXISTS(SELECT 1 FROM actor_revision WHERE actor_id = a.id)
+	ON CONFLICT DO NOTHING
+`
+
+// the export queries take the updatedSince time, releasedFrom and
+// releasedTo dates, each may be NULL. Deleted rows are exported only
+// for the incremental sync.
+const exportFilmsQuery = `
+	SELECT f.id, f.title, f.description, f.release_date, f.rating, f.updated_at, f.deleted_at
+	FROM film f
+	WHERE (($1::TIMESTAMPTZ IS NULL AND f.deleted_at IS NULL) OR f.updated_at >= $1)
+	  AND ($2::DATE IS NULL OR f.release_date >= $2)
+	  AND ($3::DATE IS NULL OR f.release_date <= $3)
+	ORDER BY f.id
+`
+
+const exportActorsQuery = `
+	SELECT a.id, a.name, a.sex, a.birthdate, a.updated_at, a.deleted_at
+	FROM actor a
+	WHERE (($1::TIMESTAMPTZ IS NULL AND a.deleted_at IS NULL) OR a.updated_at >= $1)
+	  AND (($2::DATE IS NULL AND $3::DATE IS NULL) OR EXISTS(SELECT 1
+	                                                         FROM film_actor fa
+	                                                                  JOIN film f ON f.id = fa.film_id
+	                                                         WHERE fa.actor_id = a.id
+	                                                           AND ($2 IS NULL OR f.release_date >= $2)
+	                                                           AND ($3 IS NULL OR f.release_date <= $3)))
+	ORDER BY a.id
+`
+
+// the whole cast of a changed film is exported, so a copy can replace it
+const exportCreditsQuery = `
+	SELECT fa.film_id, fa.actor_id, GREATEST(fa.created_at, f.updated_at)
+	FROM film_actor fa
+	         JOIN film f ON f.id = fa.film_id
+	         JOIN actor a ON a.id = fa.actor_id
+	WHERE f.deleted_at IS NULL
+	  AND a.deleted_at IS NULL
+	  AND ($1::TIMESTAMPTZ IS NULL OR fa.created_at >= $1 OR f.updated_at >= $1)
+	  AND ($2::DATE IS NULL OR f.release_date >= $2)
+	  AND ($3::DATE IS NULL OR f.release_date <= $3)
+	ORDER BY fa.film_id, fa.actor_id
+`
+
+type catalogPostgresqlRepository struct {
+	db  domain.PgxPoolIface
+	ctx context.Context
+}
+
+func NewCatalogPostgresqlRepository(pool domain.PgxPoolIface, ctx context.Context) domain.CatalogRepository {
+	return &catalogPostgresqlRepository{
+		db:  pool,
+		ctx: ctx,
+	}
+}
+
+// Import runs every record within a savepoint, so a failed one doesn`t
+// abort the transaction. The dry run is the same import rolled back in
+// the end, which makes its report exact.
+func (r *catalogPostgresqlRepository) Import(records []domain.ImportRecord, commit bool, userID int) ([]domain.ImportResult, error) {
+	tx, err := r.db.Begin(r.ctx)
+	if err != nil {
+		logs.LogError(logs.Logger, "catalog/postgres", "Import", err, err.Error())
+		return nil, err
+	}
+	defer tx.Rollback(r.ctx)
+
+	results := make([]domain.ImportResult, 0, len(records))
+	for _, record := range records {
+		sp, err := tx.Begin(r.ctx)
+		if err != nil {
+			logs.LogError(logs.Logger, "catalog/postgres", "Import", err, err.Error())
+			return nil, err
+		}
+
+		result, err := r.importRecord(sp, record, userID)
+		if err != nil {
+			if rbErr := sp.Rollback(r.ctx); rbErr != nil {
+				logs.LogError(logs.Logger, "catalog/postgres", "Import", rbErr, rbErr.Error())
+				return nil, rbErr
+			}
+			result = domain.ImportResult{Status: domain.ImportFailed, Reason: reason(err)}
+		} else if err = sp.Commit(r.ctx); err != nil {
+			logs.LogError(logs.Logger, "catalog/postgres", "Import", err, err.Error())
+			return nil, err
+		}
+
+		result.Line = record.Line
+		result.Type = record.Type
+		results = append(results, result)
+	}
+
+	if !commit {
+		return results, nil
+	}
+	if err = tx.Commit(r.ctx); err != nil {
+		logs.LogError(logs.Logger, "catalog/postgres", "Import", err, "can`t commit changes")
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (r *catalogPostgresqlRepository) importRecord(tx pgx.Tx, record domain.ImportRecord, userID int) (domain.ImportResult, error) {
+	switch record.Type {
+	case domain.FilmRecord:
+		return r.importFilm(tx, record.Film(), userID)
+	case domain.ActorRecord:
+		return r.importActor(tx, record.Actor(), userID)
+	case domain.CreditRecord:
+		return r.importCredit(tx, record, userID)
+	default:
+		return domain.ImportResult{}, domain.ErrBadRequest
+	}
+}
+
+func (r *catalogPostgresqlRepository) importFilm(tx pgx.Tx, film domain.Film, userID int) (domain.ImportResult, error) {
+	old := film
+	err := tx.QueryRow(r.ctx, selectFilmQuery, film.Title, film.ReleaseDate).Scan(&old.ID, &old.Description, &old.Rating)
+	if errors.Is(err, pgx.ErrNoRows) {
+		if err = tx.QueryRow(r.ctx, insertFilmQuery, film.Title, film.Description, film.ReleaseDate, film.Rating).Scan(&film.ID); err != nil {
+			return domain.ImportResult{}, err
+		}
+		if _, err = tx.Exec(r.ctx, insertFilmRevisionQuery, film.ID, userID); err != nil {
+			return domain.ImportResult{}, err
+		}
+		return domain.ImportResult{Status: domain.ImportCreated, ID: film.ID, After: film}, nil
+	}
+	if err != nil {
+		return domain.ImportResult{}, err
+	}
+
+	// the rating is stored as a real, it is compared the way it is shown
+	old.Rating = math.Trunc(old.Rating*10) / 10
+	film.ID = old.ID
+	if old.Description == film.Description && old.Rating == film.Rating {
+		return domain.ImportResult{Status: domain.ImportSkipped, ID: film.ID, Reason: "film is unchanged"}, nil
+	}
+
+	if _, err = tx.Exec(r.ctx, insertFilmBaseRevisionQuery, film.ID); err != nil {
+		return domain.ImportResult{}, err
+	}
+	if _, err = tx.Exec(r.ctx, updateFilmQuery, film.Description, film.Rating, film.ID); err != nil {
+		return domain.ImportResult{}, err
+	}
+	if _, err = tx.Exec(r.ctx, insertFilmRevisionQuery, film.ID, userID); err != nil {
+		return domain.ImportResult{}, err
+	}
+
+	return domain.ImportResult{Status: domain.ImportUpdated, ID: film.ID, Before: old, After: film}, nil
+}
+
+func (r *catalogPostgresqlRepository) importActor(tx pgx.Tx, actor domain.Actor, userID int) (domain.ImportResult, error) {
+	old := actor
+	err := tx.QueryRow(r.ctx, selectActorQuery, actor.Name, actor.Birthdate).Scan(&old.ID, &old.Sex)
+	if errors.Is(err, pgx.ErrNoRows) {
+		if err = tx.QueryRow(r.ctx, insertActorQuery, actor.Name, actor.Sex, actor.Birthdate).Scan(&actor.ID); err != nil {
+			return domain.ImportResult{}, err
+		}
+		if _, err = tx.Exec(r.ctx, insertActorRevisionQuery, actor.ID, userID); err != nil {
+			return domain.ImportResult{}, err
+		}
+		return domain.ImportResult{Status: domain.ImportCreated, ID: actor.ID, After: actor}, nil
+	}
+	if err != nil {
+		return domain.ImportResult{}, err
+	}
+
+	actor.ID = old.ID
+	if old.Sex == actor.Sex {
+		return domain.ImportResult{Status: domain.ImportSkipped, ID: actor.ID, Reason: "actor is unchanged"}, nil
+	}
+
+	if _, err = tx.Exec(r.ctx, insertActorBaseRevisionQuery, actor.ID); err != nil {
+		return domain.ImportResult{}, err
+	}
+	if _, err = tx.Exec(r.ctx, updateActorQuery, actor.Sex, actor.ID); err != nil {
+		return domain.ImportResult{}, err
+	}
+	if _, err = tx.Exec(r.ctx, insertActorRevisionQuery, actor.ID, userID); err != nil {
+		return domain.ImportResult{}, err
+	}
+
+	return domain.ImportResult{Status: domain.ImportUpdated, ID: actor.ID, Before: old, After: actor}, nil
+}
+
+// importCredit changes the film cast, so the film gets a new version and
+// a new revision.
+func (r *catalogPostgresqlRepository) importCredit(tx pgx.Tx, record domain.ImportRecord, userID int) (domain.ImportResult, error) {
+	var film domain.Film
+	err := tx.QueryRow(r.ctx, selectFilmQuery, record.Title, record.ReleaseDate).Scan(&film.ID, &film.Description, &film.Rating)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ImportResult{Status: domain.ImportFailed, Reason: "film is not found"}, nil
+	}
+	if err != nil {
+		return domain.ImportResult{}, err
+	}
+
+	var actor domain.Actor
+	err = tx.QueryRow(r.ctx, selectActorQuery, record.Name, record.Birthdate).Scan(&actor.ID, &actor.Sex)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ImportResult{Status: domain.ImportFailed, Reason: "actor is not found"}, nil
+	}
+	if err != nil {
+		return domain.ImportResult{}, err
+	}
+
+	if _, err = tx.Exec(r.ctx, insertFilmBaseRevisionQuery, film.ID); err != nil {
+		return domain.ImportResult{}, err
+	}
+	res, err := tx.Exec(r.ctx, insertCreditQuery, film.ID, actor.ID)
+	if err != nil {
+		return domain.ImportResult{}, err
+	}
+	if res.RowsAffected() == 0 {
+		return domain.ImportResult{Status: domain.ImportSkipped, ID: film.ID, Reason: "actor is already credited"}, nil
+	}
+	if _, err = tx.Exec(r.ctx, bumpFilmVersionQuery, film.ID); err != nil {
+		return domain.ImportResult{}, err
+	}
+	if _, err = tx.Exec(r.ctx, insertFilmRevisionQuery, film.ID, userID); err != nil {
+		return domain.ImportResult{}, err
+	}
+
+	return domain.ImportResult{Status: domain.ImportCreated, ID: film.ID}, nil
+}
+
+// Export reads every query with a cursor of pgx rows, so the memory
+// doesn`t depend on the catalog size.
+func (r *catalogPostgresqlRepository) Export(filter domain.ExportFilter, fn func(domain.ExportRecord) error) error {
+	tx, err := r.db.BeginTx(r.ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		logs.LogError(logs.Logger, "catalog/postgres", "Export", err, err.Error())
+		return err
+	}
+	defer tx.Rollback(r.ctx)
+
+	args := []interface{}{
+		nullTime(filter.UpdatedSince),
+		nullDate(filter.ReleasedFrom),
+		nullDate(filter.ReleasedTo),
+	}
+	exports := []struct {
+		recordType domain.ImportRecordType
+		query      string
+		scan       func(pgx.Rows) (domain.ExportRecord, error)
+	}{
+		{domain.FilmRecord, exportFilmsQuery, scanFilm},
+		{domain.ActorRecord, exportActorsQuery, scanActor},
+		{domain.CreditRecord, exportCreditsQuery, scanCredit},
+	}
+
+	for _, export := range exports {
+		if !filter.Has(export.recordType) {
+			continue
+		}
+
+		if err = r.export(tx, export.query, args, export.scan, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *catalogPostgresqlRepository) export(tx pgx.Tx, query string, args []interface{},
+	scan func(pgx.Rows) (domain.ExportRecord, error), fn func(domain.ExportRecord) error) error {
+	rows, err := tx.Query(r.ctx, query, args...)
+	if err != nil {
+		logs.LogError(logs.Logger, "catalog/postgres", "Export", err, err.Error())
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		record, err := scan(rows)
+		if err != nil {
+			logs.LogError(logs.Logger, "catalog/postgres", "Export", err, err.Error())
+			return err
+		}
+		if err = fn(record); err != nil {
+			return err
+		}
+	}
+	if err = rows.Err(); err != nil {
+		logs.LogError(logs.Logger, "catalog/postgres", "Export", err, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func scanFilm(rows pgx.Rows) (domain.ExportRecord, error) {
+	record := domain.ExportRecord{Type: domain.FilmRecord, Film: &domain.Film{}}
+	err := rows.Scan(
+		&record.Film.ID,
+		&record.Film.Title,
+		&record.Film.Description,
+		&record.Film.ReleaseDate,
+		&record.Film.Rating,
+		&record.UpdatedAt,
+		&record.Film.DeletedAt,
+	)
+	record.Film.Rating = math.Trunc(record.Film.Rating*10) / 10
+
+	return record, err
+}
+
+func scanActor(rows pgx.Rows) (domain.ExportRecord, error) {
+	record := domain.ExportRecord{Type: domain.ActorRecord, Actor: &domain.Actor{}}
+	err := rows.Scan(
+		&record.Actor.ID,
+		&record.Actor.Name,
+		&record.Actor.Sex,
+		&record.Actor.Birthdate,
+		&record.UpdatedAt,
+		&record.Actor.DeletedAt,
+	)
+
+	return record, err
+}
+
+func scanCredit(rows pgx.Rows) (domain.ExportRecord, error) {
+	record := domain.ExportRecord{Type: domain.CreditRecord, Credit: &domain.Credit{}}
+	err := rows.Scan(
+		&record.Credit.FilmID,
+		&record.Credit.ActorID,
+		&record.UpdatedAt,
+	)
+
+	return record, err
+}
+
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
+
+func nullDate(t time.Time) pgtype.Date {
+	return pgtype.Date{Time: t, Valid: !t.IsZero()}
+}
+
+// reason keeps the database details out of the report unless it is a
+// violated constraint.
+func reason(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.ConstraintName != "" {
+		return "violates " + pgErr.ConstraintName
+	}
+
+	logs.LogError(logs.Logger, "catalog/postgres", "Import", err, err.Error())
+	return domain.ErrInternalServerError.Error()
+}
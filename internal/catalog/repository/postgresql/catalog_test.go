@@ -0,0 +1,355 @@
+package postgres_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/require"
+
+	postgres "github.com/ellexo2456/FilmLib/internal/catalog/repository/postgresql"
+	"github.com/ellexo2456/FilmLib/internal/domain"
+)
+
+const selectFilmQuery = `
+	SELECT id, description, rating
+	FROM film
+`
+
+const insertFilmQuery = `
+	INSERT INTO film
+`
+
+const updateFilmQuery = `
+	UPDATE film
+	SET description = \$1, rating = \$2, version = version \+ 1
+`
+
+const selectActorQuery = `
+	SELECT id, sex
+	FROM actor
+`
+
+const insertCreditQuery = `
+	INSERT INTO film_actor
+`
+
+const insertFilmRevisionQuery = `
+	INSERT INTO film_revision \(film_id, revision, user_id, data\)
+	SELECT f.id,
+	       COALESCE
+`
+
+const bumpFilmVersionQuery = `
+	UPDATE film
+	SET version = version \+ 1
+`
+
+const insertFilmBaseRevisionQuery = `
+	INSERT INTO film_revision \(film_id, revision, user_id, data\)
+	SELECT f.id, 1, NULL,
+`
+
+func TestImport(t *testing.T) {
+	var released, born pgtype.Date
+	released.Scan("1999-03-31")
+	born.Scan("1964-09-02")
+
+	film := domain.ImportRecord{Line: 2, Type: domain.FilmRecord, Title: "Matrix", Description: "desc", ReleaseDate: released, Rating: 8.7}
+	actor := domain.ImportRecord{Line: 3, Type: domain.ActorRecord, Name: "Keanu Reeves", Sex: domain.M, Birthdate: born}
+	credit := domain.ImportRecord{Line: 4, Type: domain.CreditRecord, Title: "Matrix", ReleaseDate: released, Name: "Keanu Reeves", Birthdate: born}
+
+	tests := []struct {
+		name    string
+		records []domain.ImportRecord
+		commit  bool
+		setMock func(mockDB pgxmock.PgxPoolIface)
+		results []domain.ImportResult
+	}{
+		{
+			name:    "GoodCase/DryRunCreated",
+			records: []domain.ImportRecord{film},
+			setMock: func(mockDB pgxmock.PgxPoolIface) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectQuery(selectFilmQuery).
+					WithArgs(film.Title, film.ReleaseDate).
+					WillReturnError(pgx.ErrNoRows)
+				mockDB.ExpectQuery(insertFilmQuery).
+					WithArgs(film.Title, film.Description, film.ReleaseDate, film.Rating).
+					WillReturnRows(mockDB.NewRows([]string{"id"}).AddRow(7))
+				mockDB.ExpectExec(insertFilmRevisionQuery).
+					WithArgs(7, 1).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mockDB.ExpectCommit()
+				mockDB.ExpectRollback()
+			},
+			results: []domain.ImportResult{
+				{Line: 2, Type: domain.FilmRecord, Status: domain.ImportCreated, ID: 7, After: domain.Film{ID: 7, Title: "Matrix", Description: "desc", ReleaseDate: released, Rating: 8.7}},
+			},
+		},
+		{
+			name:    "GoodCase/UpdatedAndSkipped",
+			records: []domain.ImportRecord{film, actor},
+			commit:  true,
+			setMock: func(mockDB pgxmock.PgxPoolIface) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectQuery(selectFilmQuery).
+					WithArgs(film.Title, film.ReleaseDate).
+					WillReturnRows(mockDB.NewRows([]string{"id", "description", "rating"}).AddRow(1, "desc", 7.0))
+				mockDB.ExpectExec(insertFilmBaseRevisionQuery).
+					WithArgs(1).
+					WillReturnResult(pgxmock.NewResult("INSERT", 0))
+				mockDB.ExpectExec(updateFilmQuery).
+					WithArgs(film.Description, film.Rating, 1).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+				mockDB.ExpectExec(insertFilmRevisionQuery).
+					WithArgs(1, 1).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mockDB.ExpectCommit()
+				mockDB.ExpectBegin()
+				mockDB.ExpectQuery(selectActorQuery).
+					WithArgs(actor.Name, actor.Birthdate).
+					WillReturnRows(mockDB.NewRows([]string{"id", "sex"}).AddRow(5, domain.M))
+				mockDB.ExpectCommit()
+				mockDB.ExpectCommit()
+			},
+			results: []domain.ImportResult{
+				{Line: 2, Type: domain.FilmRecord, Status: domain.ImportUpdated, ID: 1,
+					Before: domain.Film{ID: 1, Title: "Matrix", Description: "desc", ReleaseDate: released, Rating: 7},
+					After:  domain.Film{ID: 1, Title: "Matrix", Description: "desc", ReleaseDate: released, Rating: 8.7}},
+				{Line: 3, Type: domain.ActorRecord, Status: domain.ImportSkipped, ID: 5, Reason: "actor is unchanged"},
+			},
+		},
+		{
+			name:    "GoodCase/CreditCreated",
+			records: []domain.ImportRecord{credit},
+			commit:  true,
+			setMock: func(mockDB pgxmock.PgxPoolIface) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectQuery(selectFilmQuery).
+					WithArgs(credit.Title, credit.ReleaseDate).
+					WillReturnRows(mockDB.NewRows([]string{"id", "description", "rating"}).AddRow(1, "desc", 8.7))
+				mockDB.ExpectQuery(selectActorQuery).
+					WithArgs(credit.Name, credit.Birthdate).
+					WillReturnRows(mockDB.NewRows([]string{"id", "sex"}).AddRow(5, domain.M))
+				mockDB.ExpectExec(insertFilmBaseRevisionQuery).
+					WithArgs(1).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mockDB.ExpectExec(insertCreditQuery).
+					WithArgs(1, 5).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mockDB.ExpectExec(bumpFilmVersionQuery).
+					WithArgs(1).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+				mockDB.ExpectExec(insertFilmRevisionQuery).
+					WithArgs(1, 1).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mockDB.ExpectCommit()
+				mockDB.ExpectCommit()
+			},
+			results: []domain.ImportResult{
+				{Line: 4, Type: domain.CreditRecord, Status: domain.ImportCreated, ID: 1},
+			},
+		},
+		{
+			name:    "GoodCase/CreditAlreadyExists",
+			records: []domain.ImportRecord{credit},
+			commit:  true,
+			setMock: func(mockDB pgxmock.PgxPoolIface) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectQuery(selectFilmQuery).
+					WithArgs(credit.Title, credit.ReleaseDate).
+					WillReturnRows(mockDB.NewRows([]string{"id", "description", "rating"}).AddRow(1, "desc", 8.7))
+				mockDB.ExpectQuery(selectActorQuery).
+					WithArgs(credit.Name, credit.Birthdate).
+					WillReturnRows(mockDB.NewRows([]string{"id", "sex"}).AddRow(5, domain.M))
+				mockDB.ExpectExec(insertFilmBaseRevisionQuery).
+					WithArgs(1).
+					WillReturnResult(pgxmock.NewResult("INSERT", 0))
+				mockDB.ExpectExec(insertCreditQuery).
+					WithArgs(1, 5).
+					WillReturnResult(pgxmock.NewResult("INSERT", 0))
+				mockDB.ExpectCommit()
+				mockDB.ExpectCommit()
+			},
+			results: []domain.ImportResult{
+				{Line: 4, Type: domain.CreditRecord, Status: domain.ImportSkipped, ID: 1, Reason: "actor is already credited"},
+			},
+		},
+		{
+			name:    "BadCase/FilmNotFound",
+			records: []domain.ImportRecord{credit},
+			commit:  true,
+			setMock: func(mockDB pgxmock.PgxPoolIface) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectQuery(selectFilmQuery).
+					WithArgs(credit.Title, credit.ReleaseDate).
+					WillReturnError(pgx.ErrNoRows)
+				mockDB.ExpectCommit()
+				mockDB.ExpectCommit()
+			},
+			results: []domain.ImportResult{
+				{Line: 4, Type: domain.CreditRecord, Status: domain.ImportFailed, Reason: "film is not found"},
+			},
+		},
+		{
+			name:    "BadCase/ConstraintViolated",
+			records: []domain.ImportRecord{film},
+			commit:  true,
+			setMock: func(mockDB pgxmock.PgxPoolIface) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectQuery(selectFilmQuery).
+					WithArgs(film.Title, film.ReleaseDate).
+					WillReturnError(pgx.ErrNoRows)
+				mockDB.ExpectQuery(insertFilmQuery).
+					WithArgs(film.Title, film.Description, film.ReleaseDate, film.Rating).
+					WillReturnError(&pgconn.PgError{Code: domain.DateOutOfRangeErrCode, ConstraintName: "release_date_range"})
+				mockDB.ExpectRollback()
+				mockDB.ExpectCommit()
+			},
+			results: []domain.ImportResult{
+				{Line: 2, Type: domain.FilmRecord, Status: domain.ImportFailed, Reason: "violates release_date_range"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockDB, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mockDB.Close()
+
+			mockDB.ExpectBegin()
+			test.setMock(mockDB)
+
+			r := postgres.NewCatalogPostgresqlRepository(mockDB, context.Background())
+			results, err := r.Import(test.records, test.commit, 1)
+
+			require.Nil(t, err)
+			require.Equal(t, test.results, results)
+			require.Nil(t, mockDB.ExpectationsWereMet())
+		})
+	}
+}
+
+const exportFilmsQuery = `
+	SELECT f.id, f.title, f.description, f.release_date, f.rating, f.updated_at, f.deleted_at
+	FROM film f
+`
+
+const exportActorsQuery = `
+	SELECT a.id, a.name, a.sex, a.birthdate, a.updated_at, a.deleted_at
+	FROM actor a
+`
+
+const exportCreditsQuery = `
+	SELECT fa.film_id, fa.actor_id
+`
+
+func TestExport(t *testing.T) {
+	var released, born pgtype.Date
+	released.Scan("1999-03-31")
+	born.Scan("1964-09-02")
+	since := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	updated := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+	deleted := time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		filter  domain.ExportFilter
+		fnErr   error
+		setMock func(mockDB pgxmock.PgxPoolIface)
+		records []domain.ExportRecord
+		err     error
+	}{
+		{
+			name:   "GoodCase/Incremental",
+			filter: domain.ExportFilter{UpdatedSince: since},
+			setMock: func(mockDB pgxmock.PgxPoolIface) {
+				mockDB.ExpectQuery(exportFilmsQuery).
+					WithArgs(&since, pgtype.Date{}, pgtype.Date{}).
+					WillReturnRows(mockDB.NewRows([]string{"id", "title", "description", "release_date", "rating", "updated_at", "deleted_at"}).
+						AddRow(1, "Matrix", "desc", released, 8.7, &updated, nil).
+						AddRow(2, "Speed", "desc", released, 7.2, &deleted, &deleted))
+				mockDB.ExpectQuery(exportActorsQuery).
+					WithArgs(&since, pgtype.Date{}, pgtype.Date{}).
+					WillReturnRows(mockDB.NewRows([]string{"id", "name", "sex", "birthdate", "updated_at", "deleted_at"}).
+						AddRow(5, "Keanu Reeves", domain.M, born, &updated, nil))
+				mockDB.ExpectQuery(exportCreditsQuery).
+					WithArgs(&since, pgtype.Date{}, pgtype.Date{}).
+					WillReturnRows(mockDB.NewRows([]string{"film_id", "actor_id", "updated_at"}).
+						AddRow(1, 5, &updated))
+				mockDB.ExpectRollback()
+			},
+			records: []domain.ExportRecord{
+				{Type: domain.FilmRecord, Film: &domain.Film{ID: 1, Title: "Matrix", Description: "desc", ReleaseDate: released, Rating: 8.7}, UpdatedAt: &updated},
+				{Type: domain.FilmRecord, Film: &domain.Film{ID: 2, Title: "Speed", Description: "desc", ReleaseDate: released, Rating: 7.2, DeletedAt: &deleted}, UpdatedAt: &deleted},
+				{Type: domain.ActorRecord, Actor: &domain.Actor{ID: 5, Name: "Keanu Reeves", Sex: domain.M, Birthdate: born}, UpdatedAt: &updated},
+				{Type: domain.CreditRecord, Credit: &domain.Credit{FilmID: 1, ActorID: 5}, UpdatedAt: &updated},
+			},
+		},
+		{
+			name: "GoodCase/OnlyActorsOfReleased",
+			filter: domain.ExportFilter{Types: []domain.ImportRecordType{domain.ActorRecord},
+				ReleasedFrom: released.Time, ReleasedTo: released.Time},
+			setMock: func(mockDB pgxmock.PgxPoolIface) {
+				mockDB.ExpectQuery(exportActorsQuery).
+					WithArgs((*time.Time)(nil), released, released).
+					WillReturnRows(mockDB.NewRows([]string{"id", "name", "sex", "birthdate", "updated_at", "deleted_at"}).
+						AddRow(5, "Keanu Reeves", domain.M, born, &updated, nil))
+				mockDB.ExpectRollback()
+			},
+			records: []domain.ExportRecord{
+				{Type: domain.ActorRecord, Actor: &domain.Actor{ID: 5, Name: "Keanu Reeves", Sex: domain.M, Birthdate: born}, UpdatedAt: &updated},
+			},
+		},
+		{
+			name:   "BadCase/WriteFailed",
+			filter: domain.ExportFilter{},
+			fnErr:  io.ErrClosedPipe,
+			setMock: func(mockDB pgxmock.PgxPoolIface) {
+				mockDB.ExpectQuery(exportFilmsQuery).
+					WithArgs((*time.Time)(nil), pgtype.Date{}, pgtype.Date{}).
+					WillReturnRows(mockDB.NewRows([]string{"id", "title", "description", "release_date", "rating", "updated_at", "deleted_at"}).
+						AddRow(1, "Matrix", "desc", released, 8.7, &updated, nil).
+						AddRow(2, "Speed", "desc", released, 7.2, &updated, nil))
+				mockDB.ExpectRollback()
+			},
+			records: []domain.ExportRecord{
+				{Type: domain.FilmRecord, Film: &domain.Film{ID: 1, Title: "Matrix", Description: "desc", ReleaseDate: released, Rating: 8.7}, UpdatedAt: &updated},
+			},
+			err: io.ErrClosedPipe,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockDB, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mockDB.Close()
+
+			mockDB.ExpectBeginTx(pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+			test.setMock(mockDB)
+
+			var records []domain.ExportRecord
+			r := postgres.NewCatalogPostgresqlRepository(mockDB, context.Background())
+			err = r.Export(test.filter, func(record domain.ExportRecord) error {
+				records = append(records, record)
+				return test.fnErr
+			})
+
+			require.Equal(t, test.err, err)
+			require.Equal(t, test.records, records)
+			require.Nil(t, mockDB.ExpectationsWereMet())
+		})
+	}
+}
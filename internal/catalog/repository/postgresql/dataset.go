@@ -0,0 +1,310 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+)
+
+const selectOffsetQuery = `
+	SELECT line
+	FROM dataset_progress
+	WHERE kind = $1
+	  AND size = $2
+`
+
+const upsertOffsetQuery = `
+	INSERT INTO dataset_progress (kind, size, line)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (kind) DO UPDATE SET size = $2, line = $3, updated_at = CURRENT_TIMESTAMP
+`
+
+// the batch is copied to the stage first, the catalog is changed from it
+// by one statement
+const createStageQuery = `
+	CREATE TEMP TABLE dataset_stage
+	(
+	    source_id       TEXT,
+	    title           TEXT,
+	    name            TEXT,
+	    sex             CHAR(1),
+	    date            DATE,
+	    rating          FLOAT,
+	    film_source_id  TEXT,
+	    actor_source_id TEXT
+	) ON COMMIT DROP
+`
+
+// ids of the films or actors changed by the batch, the new revisions are
+// taken from them once the merge is done
+const createChangedQuery = `
+	CREATE TEMP TABLE dataset_changed
+	(
+	    id INTEGER
+	) ON COMMIT DROP
+`
+
+// every merge query saves the changed ids to dataset_changed, the result
+// counts the changed rows
+const upsertTitlesQuery = `
+	WITH changed AS (
+	    INSERT INTO film (source_id, title, description, release_date, rating)
+	        SELECT DISTINCT ON (source_id) source_id, title, '', date, 0
+	        FROM dataset_stage
+	        ORDER BY source_id
+	    ON CONFLICT (source_id) DO UPDATE
+	        SET title = EXCLUDED.title, release_date = EXCLUDED.release_date, version = film.version + 1
+	        WHERE (film.title, film.release_date) IS DISTINCT FROM (EXCLUDED.title, EXCLUDED.release_date)
+	    RETURNING id)
+	INSERT INTO dataset_changed (id)
+	SELECT id
+	FROM changed
+`
+
+const updateRatingsQuery = `
+	WITH changed AS (
+	    UPDATE film f
+	        SET rating = s.rating, version = f.version + 1
+	        FROM dataset_stage s
+	        WHERE f.source_id = s.source_id
+	          AND f.rating <> s.rating::REAL
+	        RETURNING f.id)
+	INSERT INTO dataset_changed (id)
+	SELECT id
+	FROM changed
+`
+
+const upsertNamesQuery = `
+	WITH changed AS (
+	    INSERT INTO actor (source_id, name, sex, birthdate)
+	        SELECT DISTINCT ON (source_id) source_id, name, sex, date
+	        FROM dataset_stage
+	        ORDER BY source_id
+	    ON CONFLICT (source_id) DO UPDATE
+	        SET name = EXCLUDED.name, sex = EXCLUDED.sex, birthdate = EXCLUDED.birthdate, version = actor.version + 1
+	        WHERE (actor.name, actor.sex, actor.birthdate) IS DISTINCT FROM (EXCLUDED.name, EXCLUDED.sex, EXCLUDED.birthdate)
+	    RETURNING id)
+	INSERT INTO dataset_changed (id)
+	SELECT id
+	FROM changed
+`
+
+// principals of the titles or the names which aren`t imported are dropped,
+// a film is changed once for every new credit
+const insertPrincipalsQuery = `
+	WITH changed AS (
+	    INSERT INTO film_actor (film_id, actor_id)
+	        SELECT f.id, a.id
+	        FROM dataset_stage s
+	                 JOIN film f ON f.source_id = s.film_source_id
+	                 JOIN actor a ON a.source_id = s.actor_source_id
+	    ON CONFLICT DO NOTHING
+	    RETURNING film_id)
+	INSERT INTO dataset_changed (id)
+	SELECT film_id
+	FROM changed
+`
+
+// the base revisions keep the state of the rows which are about to change
+// for the first time since the history was kept, they are taken before the
+// merge with the same conditions
+const insertTitlesBaseRevisionQuery = `
+	INSERT INTO film_revision (film_id, revision, user_id, data)
+	SELECT f.id, 1, NULL,` + filmSnapshot + `
+	FROM film f
+	WHERE EXISTS(SELECT 1
+	             FROM dataset_stage s
+	             WHERE s.source_id = f.source_id
+	               AND (f.title, f.release_date) IS DISTINCT FROM (s.title, s.date))
+	  AND NOT EXISTS(SELECT 1 FROM film_revision WHERE film_id = f.id)
+	ON CONFLICT DO NOTHING
+`
+
+const insertRatingsBaseRevisionQuery = `
+	INSERT INTO film_revision (film_id, revision, user_id, data)
+	SELECT f.id, 1, NULL,` + filmSnapshot + `
+	FROM film f
+	WHERE EXISTS(SELECT 1
+	             FROM dataset_stage s
+	             WHERE s.source_id = f.source_id
+	               AND f.rating <> s.rating::REAL)
+	  AND NOT EXISTS(SELECT 1 FROM film_revision WHERE film_id = f.id)
+	ON CONFLICT DO NOTHING
+`
+
+const insertNamesBaseRevisionQuery = `
+	INSERT INTO actor_revision (actor_id, revision, user_id, data)
+	SELECT a.id, 1, NULL,` + actorSnapshot + `
+	FROM actor a
+	WHERE EXISTS(SELECT 1
+	             FROM dataset_stage s
+	             WHERE s.source_id = a.source_id
+	               AND (a.name, a.sex, a.birthdate) IS DISTINCT FROM (s.name, s.sex, s.date))
+	  AND NOT EXISTS(SELECT 1 FROM actor_revision WHERE actor_id = a.id)
+	ON CONFLICT DO NOTHING
+`
+
+const insertPrincipalsBaseRevisionQuery = `
+	INSERT INTO film_revision (film_id, revision, user_id, data)
+	SELECT f.id, 1, NULL,` + filmSnapshot + `
+	FROM film f
+	WHERE EXISTS(SELECT 1
+	             FROM dataset_stage s
+	                      JOIN actor a ON a.source_id = s.actor_source_id
+	             WHERE s.film_source_id = f.source_id
+	               AND NOT EXISTS(SELECT 1 FROM film_actor WHERE film_id = f.id AND actor_id = a.id))
+	  AND NOT EXISTS(SELECT 1 FROM film_revision WHERE film_id = f.id)
+	ON CONFLICT DO NOTHING
+`
+
+// the dataset has no user, so the revisions are saved without one
+const insertFilmsRevisionQuery = `
+	INSERT INTO film_revision (film_id, revision, user_id, data)
+	SELECT f.id,
+	       COALESCE((SELECT MAX(revision) FROM film_revision WHERE film_id = f.id), 0) + 1,
+	       NULL,` + filmSnapshot + `
+	FROM film f
+	WHERE f.id IN (SELECT id FROM dataset_changed)
+`
+
+const insertActorsRevisionQuery = `
+	INSERT INTO actor_revision (actor_id, revision, user_id, data)
+	SELECT a.id,
+	       COALESCE((SELECT MAX(revision) FROM actor_revision WHERE actor_id = a.id), 0) + 1,
+	       NULL,` + actorSnapshot + `
+	FROM actor a
+	WHERE a.id IN (SELECT id FROM dataset_changed)
+`
+
+var stageColumns = map[domain.DatasetKind][]string{
+	domain.TitlesDataset:     {"source_id", "title", "date"},
+	domain.RatingsDataset:    {"source_id", "rating"},
+	domain.NamesDataset:      {"source_id", "name", "sex", "date"},
+	domain.PrincipalsDataset: {"film_source_id", "actor_source_id"},
+}
+
+var baseRevisionQueries = map[domain.DatasetKind]string{
+	domain.TitlesDataset:     insertTitlesBaseRevisionQuery,
+	domain.RatingsDataset:    insertRatingsBaseRevisionQuery,
+	domain.NamesDataset:      insertNamesBaseRevisionQuery,
+	domain.PrincipalsDataset: insertPrincipalsBaseRevisionQuery,
+}
+
+var mergeQueries = map[domain.DatasetKind]string{
+	domain.TitlesDataset:     upsertTitlesQuery,
+	domain.RatingsDataset:    updateRatingsQuery,
+	domain.NamesDataset:      upsertNamesQuery,
+	domain.PrincipalsDataset: insertPrincipalsQuery,
+}
+
+var revisionQueries = map[domain.DatasetKind]string{
+	domain.TitlesDataset:     insertFilmsRevisionQuery,
+	domain.RatingsDataset:    insertFilmsRevisionQuery,
+	domain.NamesDataset:      insertActorsRevisionQuery,
+	domain.PrincipalsDataset: insertFilmsRevisionQuery,
+}
+
+type datasetPostgresqlRepository struct {
+	db  domain.PgxPoolIface
+	ctx context.Context
+}
+
+func NewDatasetPostgresqlRepository(pool domain.PgxPoolIface, ctx context.Context) domain.DatasetRepository {
+	return &datasetPostgresqlRepository{
+		db:  pool,
+		ctx: ctx,
+	}
+}
+
+func (r *datasetPostgresqlRepository) Offset(kind domain.DatasetKind, size int64) (int64, error) {
+	var line int64
+	err := r.db.QueryRow(r.ctx, selectOffsetQuery, kind, size).Scan(&line)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		logs.LogError(logs.Logger, "catalog/postgres", "Offset", err, err.Error())
+		return 0, err
+	}
+
+	return line, nil
+}
+
+func (r *datasetPostgresqlRepository) ImportBatch(batch domain.DatasetBatch) (int64, error) {
+	columns, ok := stageColumns[batch.Kind]
+	if !ok {
+		return 0, domain.ErrBadRequest
+	}
+
+	tx, err := r.db.Begin(r.ctx)
+	if err != nil {
+		logs.LogError(logs.Logger, "catalog/postgres", "ImportBatch", err, err.Error())
+		return 0, err
+	}
+	defer tx.Rollback(r.ctx)
+
+	var imported int64
+	if len(batch.Rows) != 0 {
+		if _, err = tx.Exec(r.ctx, createStageQuery); err != nil {
+			logs.LogError(logs.Logger, "catalog/postgres", "ImportBatch", err, err.Error())
+			return 0, err
+		}
+		if _, err = tx.Exec(r.ctx, createChangedQuery); err != nil {
+			logs.LogError(logs.Logger, "catalog/postgres", "ImportBatch", err, err.Error())
+			return 0, err
+		}
+
+		rows := make([][]interface{}, 0, len(batch.Rows))
+		for _, row := range batch.Rows {
+			rows = append(rows, stageRow(batch.Kind, row))
+		}
+		if _, err = tx.CopyFrom(r.ctx, pgx.Identifier{"dataset_stage"}, columns, pgx.CopyFromRows(rows)); err != nil {
+			logs.LogError(logs.Logger, "catalog/postgres", "ImportBatch", err, err.Error())
+			return 0, err
+		}
+
+		if _, err = tx.Exec(r.ctx, baseRevisionQueries[batch.Kind]); err != nil {
+			logs.LogError(logs.Logger, "catalog/postgres", "ImportBatch", err, err.Error())
+			return 0, err
+		}
+		res, err := tx.Exec(r.ctx, mergeQueries[batch.Kind])
+		if err != nil {
+			logs.LogError(logs.Logger, "catalog/postgres", "ImportBatch", err, err.Error())
+			return 0, err
+		}
+		imported = res.RowsAffected()
+		if _, err = tx.Exec(r.ctx, revisionQueries[batch.Kind]); err != nil {
+			logs.LogError(logs.Logger, "catalog/postgres", "ImportBatch", err, err.Error())
+			return 0, err
+		}
+	}
+
+	if _, err = tx.Exec(r.ctx, upsertOffsetQuery, batch.Kind, batch.Size, batch.Offset); err != nil {
+		logs.LogError(logs.Logger, "catalog/postgres", "ImportBatch", err, err.Error())
+		return 0, err
+	}
+
+	if err = tx.Commit(r.ctx); err != nil {
+		logs.LogError(logs.Logger, "catalog/postgres", "ImportBatch", err, "can`t commit changes")
+		return 0, err
+	}
+
+	return imported, nil
+}
+
+func stageRow(kind domain.DatasetKind, row domain.DatasetRow) []interface{} {
+	switch kind {
+	case domain.TitlesDataset:
+		return []interface{}{row.SourceID, row.Title, row.Date}
+	case domain.RatingsDataset:
+		return []interface{}{row.SourceID, row.Rating}
+	case domain.NamesDataset:
+		return []interface{}{row.SourceID, row.Name, string(row.Sex), row.Date}
+	default:
+		return []interface{}{row.FilmSourceID, row.ActorSourceID}
+	}
+}
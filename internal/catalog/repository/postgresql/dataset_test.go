@@ -0,0 +1,268 @@
+package postgres_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/require"
+
+	postgres "github.com/ellexo2456/FilmLib/internal/catalog/repository/postgresql"
+	"github.com/ellexo2456/FilmLib/internal/domain"
+)
+
+const selectOffsetQuery = `
+	SELECT line
+	FROM dataset_progress
+`
+
+const upsertOffsetQuery = `
+	INSERT INTO dataset_progress
+`
+
+const createStageQuery = `
+	CREATE TEMP TABLE dataset_stage
+`
+
+const createChangedQuery = `
+	CREATE TEMP TABLE dataset_changed
+`
+
+const upsertTitlesQuery = `
+	INSERT INTO film \(source_id, title, description, release_date, rating\)
+`
+
+const upsertNamesQuery = `
+	INSERT INTO actor \(source_id, name, sex, birthdate\)
+`
+
+const insertPrincipalsQuery = `
+	INSERT INTO film_actor \(film_id, actor_id\)
+`
+
+const insertFilmsRevisionQuery = `
+	INSERT INTO film_revision \(film_id, revision, user_id, data\)
+	SELECT f.id,
+	       COALESCE\(\(SELECT MAX\(revision\) FROM film_revision WHERE film_id = f.id\), 0\) \+ 1,
+	       NULL,
+`
+
+const insertActorBaseRevisionQuery = `
+	INSERT INTO actor_revision \(actor_id, revision, user_id, data\)
+	SELECT a.id, 1, NULL,
+`
+
+const insertActorsRevisionQuery = `
+	INSERT INTO actor_revision \(actor_id, revision, user_id, data\)
+	SELECT a.id,
+	       COALESCE\(\(SELECT MAX\(revision\) FROM actor_revision WHERE actor_id = a.id\), 0\) \+ 1,
+	       NULL,
+`
+
+func TestOffset(t *testing.T) {
+	tests := []struct {
+		name    string
+		setMock func(mockDB pgxmock.PgxPoolIface)
+		offset  int64
+		err     error
+	}{
+		{
+			name: "GoodCase/Saved",
+			setMock: func(mockDB pgxmock.PgxPoolIface) {
+				mockDB.ExpectQuery(selectOffsetQuery).
+					WithArgs(domain.TitlesDataset, int64(1024)).
+					WillReturnRows(mockDB.NewRows([]string{"line"}).AddRow(int64(50000)))
+			},
+			offset: 50000,
+		},
+		{
+			name: "GoodCase/NotSaved",
+			setMock: func(mockDB pgxmock.PgxPoolIface) {
+				mockDB.ExpectQuery(selectOffsetQuery).
+					WithArgs(domain.TitlesDataset, int64(1024)).
+					WillReturnError(pgx.ErrNoRows)
+			},
+		},
+		{
+			name: "BadCase/DatabaseError",
+			setMock: func(mockDB pgxmock.PgxPoolIface) {
+				mockDB.ExpectQuery(selectOffsetQuery).
+					WithArgs(domain.TitlesDataset, int64(1024)).
+					WillReturnError(io.ErrUnexpectedEOF)
+			},
+			err: io.ErrUnexpectedEOF,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockDB, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mockDB.Close()
+
+			test.setMock(mockDB)
+
+			r := postgres.NewDatasetPostgresqlRepository(mockDB, context.Background())
+			offset, err := r.Offset(domain.TitlesDataset, 1024)
+
+			require.Equal(t, test.err, err)
+			require.Equal(t, test.offset, offset)
+			require.Nil(t, mockDB.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestImportBatch(t *testing.T) {
+	var released pgtype.Date
+	released.Scan("1999-01-01")
+
+	tests := []struct {
+		name     string
+		batch    domain.DatasetBatch
+		setMock  func(mockDB pgxmock.PgxPoolIface)
+		imported int64
+		err      error
+	}{
+		{
+			name: "GoodCase/Titles",
+			batch: domain.DatasetBatch{Kind: domain.TitlesDataset, Size: 1024, Offset: 2, Rows: []domain.DatasetRow{
+				{SourceID: "tt0133093", Title: "The Matrix", Date: released},
+				{SourceID: "tt0234215", Title: "The Matrix Reloaded", Date: released},
+			}},
+			setMock: func(mockDB pgxmock.PgxPoolIface) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectExec(createStageQuery).
+					WillReturnResult(pgxmock.NewResult("CREATE", 0))
+				mockDB.ExpectExec(createChangedQuery).
+					WillReturnResult(pgxmock.NewResult("CREATE", 0))
+				mockDB.ExpectCopyFrom(pgx.Identifier{"dataset_stage"}, []string{"source_id", "title", "date"}).
+					WillReturnResult(2)
+				mockDB.ExpectExec(insertFilmBaseRevisionQuery).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mockDB.ExpectExec(upsertTitlesQuery).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mockDB.ExpectExec(insertFilmsRevisionQuery).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mockDB.ExpectExec(upsertOffsetQuery).
+					WithArgs(domain.TitlesDataset, int64(1024), int64(2)).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mockDB.ExpectCommit()
+				mockDB.ExpectRollback()
+			},
+			imported: 1,
+		},
+		{
+			name: "GoodCase/Names",
+			batch: domain.DatasetBatch{Kind: domain.NamesDataset, Size: 1024, Offset: 1, Rows: []domain.DatasetRow{
+				{SourceID: "nm0000206", Name: "Keanu Reeves", Sex: domain.M, Date: released},
+			}},
+			setMock: func(mockDB pgxmock.PgxPoolIface) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectExec(createStageQuery).
+					WillReturnResult(pgxmock.NewResult("CREATE", 0))
+				mockDB.ExpectExec(createChangedQuery).
+					WillReturnResult(pgxmock.NewResult("CREATE", 0))
+				mockDB.ExpectCopyFrom(pgx.Identifier{"dataset_stage"}, []string{"source_id", "name", "sex", "date"}).
+					WillReturnResult(1)
+				mockDB.ExpectExec(insertActorBaseRevisionQuery).
+					WillReturnResult(pgxmock.NewResult("INSERT", 0))
+				mockDB.ExpectExec(upsertNamesQuery).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mockDB.ExpectExec(insertActorsRevisionQuery).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mockDB.ExpectExec(upsertOffsetQuery).
+					WithArgs(domain.NamesDataset, int64(1024), int64(1)).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mockDB.ExpectCommit()
+				mockDB.ExpectRollback()
+			},
+			imported: 1,
+		},
+		{
+			name:  "GoodCase/OnlyOffset",
+			batch: domain.DatasetBatch{Kind: domain.NamesDataset, Size: 1024, Offset: 3, Rows: []domain.DatasetRow{}},
+			setMock: func(mockDB pgxmock.PgxPoolIface) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectExec(upsertOffsetQuery).
+					WithArgs(domain.NamesDataset, int64(1024), int64(3)).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mockDB.ExpectCommit()
+				mockDB.ExpectRollback()
+			},
+		},
+		{
+			name: "BadCase/MergeFailed",
+			batch: domain.DatasetBatch{Kind: domain.PrincipalsDataset, Size: 1024, Offset: 1, Rows: []domain.DatasetRow{
+				{FilmSourceID: "tt0133093", ActorSourceID: "nm0000206"},
+			}},
+			setMock: func(mockDB pgxmock.PgxPoolIface) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectExec(createStageQuery).
+					WillReturnResult(pgxmock.NewResult("CREATE", 0))
+				mockDB.ExpectExec(createChangedQuery).
+					WillReturnResult(pgxmock.NewResult("CREATE", 0))
+				mockDB.ExpectCopyFrom(pgx.Identifier{"dataset_stage"}, []string{"film_source_id", "actor_source_id"}).
+					WillReturnResult(1)
+				mockDB.ExpectExec(insertFilmBaseRevisionQuery).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mockDB.ExpectExec(insertPrincipalsQuery).
+					WillReturnError(io.ErrUnexpectedEOF)
+				mockDB.ExpectRollback()
+			},
+			err: io.ErrUnexpectedEOF,
+		},
+		{
+			name: "BadCase/RevisionFailed",
+			batch: domain.DatasetBatch{Kind: domain.RatingsDataset, Size: 1024, Offset: 1, Rows: []domain.DatasetRow{
+				{SourceID: "tt0133093", Rating: 8.7},
+			}},
+			setMock: func(mockDB pgxmock.PgxPoolIface) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectExec(createStageQuery).
+					WillReturnResult(pgxmock.NewResult("CREATE", 0))
+				mockDB.ExpectExec(createChangedQuery).
+					WillReturnResult(pgxmock.NewResult("CREATE", 0))
+				mockDB.ExpectCopyFrom(pgx.Identifier{"dataset_stage"}, []string{"source_id", "rating"}).
+					WillReturnResult(1)
+				mockDB.ExpectExec(insertFilmBaseRevisionQuery).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mockDB.ExpectExec("UPDATE film f").
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mockDB.ExpectExec(insertFilmsRevisionQuery).
+					WillReturnError(io.ErrUnexpectedEOF)
+				mockDB.ExpectRollback()
+			},
+			err: io.ErrUnexpectedEOF,
+		},
+		{
+			name:    "BadCase/UnknownKind",
+			batch:   domain.DatasetBatch{Kind: "title.akas"},
+			setMock: func(mockDB pgxmock.PgxPoolIface) {},
+			err:     domain.ErrBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockDB, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mockDB.Close()
+
+			test.setMock(mockDB)
+
+			r := postgres.NewDatasetPostgresqlRepository(mockDB, context.Background())
+			imported, err := r.ImportBatch(test.batch)
+
+			require.Equal(t, test.err, err)
+			require.Equal(t, test.imported, imported)
+			require.Nil(t, mockDB.ExpectationsWereMet())
+		})
+	}
+}
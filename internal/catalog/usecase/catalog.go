@@ -0,0 +1,430 @@
+package usecase
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+)
+
+// maxLineSize limits an ndjson line, longer ones fail the whole import.
+const maxLineSize = 1 << 20
+
+type catalogUsecase struct {
+	catalogRepo domain.CatalogRepository
+	audit       domain.AuditUsecase
+	events      domain.EventEmitter
+}
+
+func NewCatalogUsecase(cr domain.CatalogRepository, au domain.AuditUsecase, ee domain.EventEmitter) domain.CatalogUsecase {
+	return &catalogUsecase{
+		catalogRepo: cr,
+		audit:       au,
+		events:      ee,
+	}
+}
+
+func (u *catalogUsecase) Import(r io.Reader, format domain.CatalogFormat, commit bool, ac domain.AuditContext) (domain.ImportReport, error) {
+	var records []domain.ImportRecord
+	var results []domain.ImportResult
+	var err error
+	switch format {
+	case domain.CSVFormat:
+		records, results, err = decodeCSV(r)
+	case domain.NDJSONFormat:
+		records, results, err = decodeNDJSON(r)
+	default:
+		err = domain.ErrBadRequest
+	}
+	if err != nil {
+		logs.LogError(logs.Logger, "catalog/usecase", "Import", err, err.Error())
+		return domain.ImportReport{}, err
+	}
+
+	valid := make([]domain.ImportRecord, 0, len(records))
+	for _, record := range records {
+		if err = record.Validate(); err != nil {
+			results = append(results, failed(record.Line, record.Type, err))
+			continue
+		}
+		valid = append(valid, record)
+	}
+
+	if len(valid) != 0 {
+		imported, err := u.catalogRepo.Import(valid, commit, ac.UserID)
+		if err != nil {
+			logs.LogError(logs.Logger, "catalog/usecase", "Import", err, err.Error())
+			return domain.ImportReport{}, err
+		}
+		results = append(results, imported...)
+	}
+	slices.SortStableFunc(results, func(a, b domain.ImportResult) int {
+		return cmp.Compare(a.Line, b.Line)
+	})
+
+	report := domain.ImportReport{Committed: commit, Rows: results}
+	for i, result := range results {
+		switch result.Status {
+		case domain.ImportCreated:
+			report.Created++
+		case domain.ImportUpdated:
+			report.Updated++
+		case domain.ImportSkipped:
+			report.Skipped++
+		case domain.ImportFailed:
+			report.Failed++
+		}
+
+		// ids of the rows created in the dry run are rolled back
+		if !commit && result.Status == domain.ImportCreated {
+			report.Rows[i].ID = 0
+		}
+		if commit {
+			u.record(ac, result)
+		}
+	}
+	logs.Logger.Debug("catalog/usecase Import:", report.Created, report.Updated, report.Skipped, report.Failed)
+
+	return report, nil
+}
+
+// record puts created and updated films and actors to the audit log and
+// emits their events, credits are a part of the film cast which isn`t
+// audited.
+func (u *catalogUsecase) record(ac domain.AuditContext, result domain.ImportResult) {
+	var entity domain.AuditEntity
+	var created, updated domain.EventType
+	switch result.Type {
+	case domain.FilmRecord:
+		entity, created, updated = domain.AuditFilm, domain.FilmCreated, domain.FilmUpdated
+	case domain.ActorRecord:
+		entity, created, updated = domain.AuditActor, domain.ActorCreated, domain.ActorUpdated
+	default:
+		return
+	}
+
+	switch result.Status {
+	case domain.ImportCreated:
+		u.audit.Record(ac, domain.AuditCreate, entity, result.ID, nil, result.After)
+		u.events.Emit(created, result.After)
+	case domain.ImportUpdated:
+		u.audit.Record(ac, domain.AuditUpdate, entity, result.ID, result.Before, result.After)
+		u.events.Emit(updated, result.After)
+	}
+}
+
+func (u *catalogUsecase) Export(filter domain.ExportFilter, format domain.CatalogFormat, w io.Writer) error {
+	if !format.Valid() {
+		return fmt.Errorf("%w: format must be csv, ndjson or json", domain.ErrBadRequest)
+	}
+	for _, t := range filter.Types {
+		if !t.Valid() {
+			return fmt.Errorf("%w: unknown type %q", domain.ErrBadRequest, t)
+		}
+	}
+	if !filter.ReleasedFrom.IsZero() && !filter.ReleasedTo.IsZero() && filter.ReleasedTo.Before(filter.ReleasedFrom) {
+		return fmt.Errorf("%w: releasedTo is before releasedFrom", domain.ErrBadRequest)
+	}
+
+	// the buffer holds the beginning of the export back, so a failure
+	// before it is flushed still can be reported by the caller
+	bw := bufio.NewWriter(w)
+	encoder := newEncoder(format, bw)
+
+	count := 0
+	err := u.catalogRepo.Export(filter, func(record domain.ExportRecord) error {
+		count++
+		return encoder.encode(record)
+	})
+	if err == nil {
+		err = encoder.close()
+	}
+	if err == nil {
+		err = bw.Flush()
+	}
+	if err != nil {
+		logs.LogError(logs.Logger, "catalog/usecase", "Export", err, err.Error())
+		return err
+	}
+	logs.Logger.Debug("catalog/usecase Export:", count)
+
+	return nil
+}
+
+type recordEncoder interface {
+	encode(record domain.ExportRecord) error
+	close() error
+}
+
+func newEncoder(format domain.CatalogFormat, w io.Writer) recordEncoder {
+	switch format {
+	case domain.CSVFormat:
+		return &csvEncoder{w: csv.NewWriter(w)}
+	case domain.JSONFormat:
+		return &jsonEncoder{w: w}
+	default:
+		return &ndjsonEncoder{e: json.NewEncoder(w)}
+	}
+}
+
+var exportColumns = []string{"type", "id", "title", "description", "releaseDate", "rating", "name", "sex", "birthdate",
+	"filmId", "actorId", "updatedAt", "deletedAt"}
+
+// csvEncoder writes a row of the exportColumns, the columns of the other
+// types are left empty.
+type csvEncoder struct {
+	w      *csv.Writer
+	header bool
+}
+
+func (e *csvEncoder) encode(record domain.ExportRecord) error {
+	if !e.header {
+		if err := e.w.Write(exportColumns); err != nil {
+			return err
+		}
+		e.header = true
+	}
+
+	row := make([]string, len(exportColumns))
+	row[0] = string(record.Type)
+	switch {
+	case record.Film != nil:
+		f := record.Film
+		row[1], row[2], row[3] = strconv.Itoa(f.ID), f.Title, f.Description
+		row[4], row[5] = formatDate(f.ReleaseDate), strconv.FormatFloat(f.Rating, 'f', -1, 64)
+		row[12] = formatTime(f.DeletedAt)
+	case record.Actor != nil:
+		a := record.Actor
+		row[1], row[6], row[7], row[8] = strconv.Itoa(a.ID), a.Name, string(a.Sex), formatDate(a.Birthdate)
+		row[12] = formatTime(a.DeletedAt)
+	case record.Credit != nil:
+		row[9], row[10] = strconv.Itoa(record.Credit.FilmID), strconv.Itoa(record.Credit.ActorID)
+	}
+	row[11] = formatTime(record.UpdatedAt)
+
+	return e.w.Write(row)
+}
+
+func (e *csvEncoder) close() error {
+	if !e.header {
+		if err := e.w.Write(exportColumns); err != nil {
+			return err
+		}
+	}
+	e.w.Flush()
+
+	return e.w.Error()
+}
+
+type ndjsonEncoder struct {
+	e *json.Encoder
+}
+
+func (e *ndjsonEncoder) encode(record domain.ExportRecord) error {
+	return e.e.Encode(record)
+}
+
+func (e *ndjsonEncoder) close() error {
+	return nil
+}
+
+// jsonEncoder writes the records as elements of an array.
+type jsonEncoder struct {
+	w     io.Writer
+	count int
+}
+
+func (e *jsonEncoder) encode(record domain.ExportRecord) error {
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	sep := ",\n"
+	if e.count == 0 {
+		sep = "[\n"
+	}
+	e.count++
+	if _, err = io.WriteString(e.w, sep); err != nil {
+		return err
+	}
+	_, err = e.w.Write(raw)
+
+	return err
+}
+
+func (e *jsonEncoder) close() error {
+	end := "\n]\n"
+	if e.count == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(e.w, end)
+
+	return err
+}
+
+func formatDate(d pgtype.Date) string {
+	if !d.Valid {
+		return ""
+	}
+
+	return d.Time.Format(time.DateOnly)
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
+
+var csvColumns = []string{"type", "title", "description", "releaseDate", "rating", "name", "sex", "birthdate"}
+
+// decodeCSV reads the records by the header, which must have the type
+// column. A row which can`t be read is reported as failed.
+func decodeCSV(r io.Reader) ([]domain.ImportRecord, []domain.ImportResult, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, fmt.Errorf("%w: header is missing", domain.ErrBadRequest)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", domain.ErrBadRequest, err.Error())
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if !slices.Contains(csvColumns, name) {
+			return nil, nil, fmt.Errorf("%w: unknown column %q", domain.ErrBadRequest, name)
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["type"]; !ok {
+		return nil, nil, fmt.Errorf("%w: type column is missing", domain.ErrBadRequest)
+	}
+
+	var records []domain.ImportRecord
+	var results []domain.ImportResult
+	for {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) && errors.Is(err, csv.ErrFieldCount) {
+			results = append(results, failed(parseErr.StartLine, "", err))
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %s", domain.ErrBadRequest, err.Error())
+		}
+		line, _ := reader.FieldPos(0)
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok {
+				return strings.TrimSpace(fields[i])
+			}
+			return ""
+		}
+
+		record := domain.ImportRecord{
+			Line:        line,
+			Type:        domain.ImportRecordType(field("type")),
+			Title:       field("title"),
+			Description: field("description"),
+			Name:        field("name"),
+			Sex:         domain.Sex(field("sex")),
+		}
+		if record.ReleaseDate, err = parseDate(field("releaseDate")); err != nil {
+			results = append(results, failed(line, record.Type, fmt.Errorf("invalid release date: %w", err)))
+			continue
+		}
+		if record.Birthdate, err = parseDate(field("birthdate")); err != nil {
+			results = append(results, failed(line, record.Type, fmt.Errorf("invalid birthdate: %w", err)))
+			continue
+		}
+		if rating := field("rating"); rating != "" {
+			if record.Rating, err = strconv.ParseFloat(rating, 64); err != nil {
+				results = append(results, failed(line, record.Type, errors.New("invalid rating")))
+				continue
+			}
+		}
+
+		records = append(records, record)
+	}
+
+	return records, results, nil
+}
+
+// decodeNDJSON reads a json record from every non-empty line.
+func decodeNDJSON(r io.Reader) ([]domain.ImportRecord, []domain.ImportResult, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	var records []domain.ImportRecord
+	var results []domain.ImportResult
+	for line := 1; scanner.Scan(); line++ {
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		decoder.DisallowUnknownFields()
+
+		var record domain.ImportRecord
+		if err := decoder.Decode(&record); err != nil {
+			results = append(results, failed(line, "", fmt.Errorf("invalid json: %w", err)))
+			continue
+		}
+		record.Line = line
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", domain.ErrBadRequest, err.Error())
+	}
+
+	return records, results, nil
+}
+
+func parseDate(s string) (pgtype.Date, error) {
+	if s == "" {
+		return pgtype.Date{}, nil
+	}
+
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return pgtype.Date{}, errors.New("expected YYYY-MM-DD")
+	}
+
+	return pgtype.Date{Time: t, Valid: true}, nil
+}
+
+// failed makes the result of the record which isn`t imported, the reason
+// is told without the generic bad request prefix.
+func failed(line int, recordType domain.ImportRecordType, err error) domain.ImportResult {
+	return domain.ImportResult{
+		Line:   line,
+		Type:   recordType,
+		Status: domain.ImportFailed,
+		Reason: strings.TrimPrefix(err.Error(), domain.ErrBadRequest.Error()+": "),
+	}
+}
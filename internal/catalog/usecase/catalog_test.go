@@ -0,0 +1,284 @@
+package usecase_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ellexo2456/FilmLib/internal/catalog/usecase"
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	"github.com/ellexo2456/FilmLib/internal/domain/mocks"
+)
+
+var ac = domain.AuditContext{UserID: 1, RequestID: "request"}
+
+func TestImport(t *testing.T) {
+	var released, born pgtype.Date
+	released.Scan("1999-03-31")
+	born.Scan("1964-09-02")
+
+	film := domain.ImportRecord{Line: 2, Type: domain.FilmRecord, Title: "Matrix", Description: "desc", ReleaseDate: released, Rating: 8.7}
+	actor := domain.ImportRecord{Line: 3, Type: domain.ActorRecord, Name: "Keanu Reeves", Sex: domain.M, Birthdate: born}
+	credit := domain.ImportRecord{Line: 4, Type: domain.CreditRecord, Title: "Matrix", ReleaseDate: released, Name: "Keanu Reeves", Birthdate: born}
+
+	csv := `type,title,description,releaseDate,rating,name,sex,birthdate
+film,Matrix,desc,1999-03-31,8.7,,,
+actor,,,,,Keanu Reeves,M,1964-09-02
+credit,Matrix,,1999-03-31,,Keanu Reeves,,1964-09-02
+`
+
+	tests := []struct {
+		name         string
+		input        string
+		format       domain.CatalogFormat
+		commit       bool
+		setRepoMock  func(repo *mocks.CatalogRepository)
+		setAuditMock func(audit *mocks.AuditUsecase)
+		events       []domain.EventType
+		report       domain.ImportReport
+		err          error
+	}{
+		{
+			name:   "GoodCase/CSVDryRun",
+			input:  csv,
+			format: domain.CSVFormat,
+			setRepoMock: func(repo *mocks.CatalogRepository) {
+				repo.On("Import", []domain.ImportRecord{film, actor, credit}, false, ac.UserID).Return([]domain.ImportResult{
+					{Line: 2, Type: domain.FilmRecord, Status: domain.ImportCreated, ID: 7},
+					{Line: 3, Type: domain.ActorRecord, Status: domain.ImportSkipped, ID: 5, Reason: "actor is unchanged"},
+					{Line: 4, Type: domain.CreditRecord, Status: domain.ImportCreated, ID: 7},
+				}, nil)
+			},
+			setAuditMock: func(audit *mocks.AuditUsecase) {},
+			report: domain.ImportReport{Created: 2, Skipped: 1, Rows: []domain.ImportResult{
+				{Line: 2, Type: domain.FilmRecord, Status: domain.ImportCreated},
+				{Line: 3, Type: domain.ActorRecord, Status: domain.ImportSkipped, ID: 5, Reason: "actor is unchanged"},
+				{Line: 4, Type: domain.CreditRecord, Status: domain.ImportCreated},
+			}},
+		},
+		{
+			name: "GoodCase/NDJSONCommit",
+			input: `{"type": "film", "title": "Matrix", "description": "desc", "releaseDate": "1999-03-31", "rating": 8.7}
+
+{"type": "actor", "name": "Keanu Reeves", "sex": "M", "birthdate": "1964-09-02"}
+`,
+			format: domain.NDJSONFormat,
+			commit: true,
+			setRepoMock: func(repo *mocks.CatalogRepository) {
+				ndFilm, ndActor := film, actor
+				ndFilm.Line, ndActor.Line = 1, 3
+				repo.On("Import", []domain.ImportRecord{ndFilm, ndActor}, true, ac.UserID).Return([]domain.ImportResult{
+					{Line: 1, Type: domain.FilmRecord, Status: domain.ImportCreated, ID: 7, After: ndFilm.Film()},
+					{Line: 3, Type: domain.ActorRecord, Status: domain.ImportUpdated, ID: 5, Before: domain.Actor{ID: 5}, After: ndActor.Actor()},
+				}, nil)
+			},
+			setAuditMock: func(audit *mocks.AuditUsecase) {
+				audit.On("Record", ac, domain.AuditCreate, domain.AuditFilm, 7, nil, film.Film()).Once()
+				audit.On("Record", ac, domain.AuditUpdate, domain.AuditActor, 5, domain.Actor{ID: 5}, actor.Actor()).Once()
+			},
+			events: []domain.EventType{domain.FilmCreated, domain.ActorUpdated},
+			report: domain.ImportReport{Committed: true, Created: 1, Updated: 1, Rows: []domain.ImportResult{
+				{Line: 1, Type: domain.FilmRecord, Status: domain.ImportCreated, ID: 7, After: film.Film()},
+				{Line: 3, Type: domain.ActorRecord, Status: domain.ImportUpdated, ID: 5, Before: domain.Actor{ID: 5}, After: actor.Actor()},
+			}},
+		},
+		{
+			name: "GoodCase/InvalidRows",
+			input: `type,title,releaseDate,rating,name,sex,birthdate
+film,Matrix,1999-03-31,11,,,
+actor,,,,Keanu Reeves,X,1964-09-02
+film,Matrix,31.03.1999,8,,,
+actor,,,,Keanu Reeves,M
+credit,Matrix,1999-03-31,,,,1964-09-02
+series,Friends,1994-09-22,9,,,
+`,
+			format:       domain.CSVFormat,
+			commit:       true,
+			setRepoMock:  func(repo *mocks.CatalogRepository) {},
+			setAuditMock: func(audit *mocks.AuditUsecase) {},
+			report: domain.ImportReport{Committed: true, Failed: 6, Rows: []domain.ImportResult{
+				{Line: 2, Type: domain.FilmRecord, Status: domain.ImportFailed, Reason: "rating must be from 0 to 10"},
+				{Line: 3, Type: domain.ActorRecord, Status: domain.ImportFailed, Reason: "sex must be M or F"},
+				{Line: 4, Type: domain.FilmRecord, Status: domain.ImportFailed, Reason: "invalid release date: expected YYYY-MM-DD"},
+				{Line: 5, Status: domain.ImportFailed, Reason: "record on line 5: wrong number of fields"},
+				{Line: 6, Type: domain.CreditRecord, Status: domain.ImportFailed, Reason: "credit needs the actor name and birthdate"},
+				{Line: 7, Type: "series", Status: domain.ImportFailed, Reason: `unknown type "series"`},
+			}},
+		},
+		{
+			name:         "BadCase/UnknownColumn",
+			input:        "type,title,budget\nfilm,Matrix,63000000\n",
+			format:       domain.CSVFormat,
+			setRepoMock:  func(repo *mocks.CatalogRepository) {},
+			setAuditMock: func(audit *mocks.AuditUsecase) {},
+			err:          domain.ErrBadRequest,
+		},
+		{
+			name:         "BadCase/NoTypeColumn",
+			input:        "title\nMatrix\n",
+			format:       domain.CSVFormat,
+			setRepoMock:  func(repo *mocks.CatalogRepository) {},
+			setAuditMock: func(audit *mocks.AuditUsecase) {},
+			err:          domain.ErrBadRequest,
+		},
+		{
+			name:         "BadCase/UnknownFormat",
+			input:        csv,
+			format:       "xml",
+			setRepoMock:  func(repo *mocks.CatalogRepository) {},
+			setAuditMock: func(audit *mocks.AuditUsecase) {},
+			err:          domain.ErrBadRequest,
+		},
+		{
+			name:   "BadCase/RepoError",
+			input:  csv,
+			format: domain.CSVFormat,
+			setRepoMock: func(repo *mocks.CatalogRepository) {
+				repo.On("Import", mock.Anything, false, ac.UserID).Return(nil, errors.New("connection refused"))
+			},
+			setAuditMock: func(audit *mocks.AuditUsecase) {},
+			err:          errors.New("connection refused"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			catalogRepo := new(mocks.CatalogRepository)
+			test.setRepoMock(catalogRepo)
+			audit := new(mocks.AuditUsecase)
+			test.setAuditMock(audit)
+			events := new(mocks.EventEmitter)
+			for _, event := range test.events {
+				events.On("Emit", event, mock.Anything).Once()
+			}
+
+			report, err := usecase.NewCatalogUsecase(catalogRepo, audit, events).
+				Import(strings.NewReader(test.input), test.format, test.commit, ac)
+
+			if test.err != nil {
+				assert.ErrorContains(t, err, test.err.Error())
+			} else {
+				assert.Nil(t, err)
+			}
+			assert.Equal(t, test.report, report)
+			catalogRepo.AssertExpectations(t)
+			audit.AssertExpectations(t)
+			events.AssertExpectations(t)
+		})
+	}
+}
+
+func TestExport(t *testing.T) {
+	var released, born pgtype.Date
+	released.Scan("1999-03-31")
+	born.Scan("1964-09-02")
+	updated := time.Date(2024, 3, 2, 10, 0, 0, 0, time.UTC)
+	deleted := time.Date(2024, 3, 3, 10, 0, 0, 0, time.UTC)
+
+	records := []domain.ExportRecord{
+		{Type: domain.FilmRecord, Film: &domain.Film{ID: 1, Title: "Matrix", Description: "Neo, Trinity", ReleaseDate: released, Rating: 8.7}, UpdatedAt: &updated},
+		{Type: domain.ActorRecord, Actor: &domain.Actor{ID: 5, Name: "Keanu Reeves", Sex: domain.M, Birthdate: born, DeletedAt: &deleted}, UpdatedAt: &deleted},
+		{Type: domain.CreditRecord, Credit: &domain.Credit{FilmID: 1, ActorID: 5}, UpdatedAt: &updated},
+	}
+
+	tests := []struct {
+		name        string
+		filter      domain.ExportFilter
+		format      domain.CatalogFormat
+		records     []domain.ExportRecord
+		setRepoMock bool
+		repoErr     error
+		output      string
+		err         error
+	}{
+		{
+			name:        "GoodCase/CSV",
+			format:      domain.CSVFormat,
+			records:     records,
+			setRepoMock: true,
+			output: `type,id,title,description,releaseDate,rating,name,sex,birthdate,filmId,actorId,updatedAt,deletedAt
+film,1,Matrix,"Neo, Trinity",1999-03-31,8.7,,,,,,2024-03-02T10:00:00Z,
+actor,5,,,,,Keanu Reeves,M,1964-09-02,,,2024-03-03T10:00:00Z,2024-03-03T10:00:00Z
+credit,,,,,,,,,1,5,2024-03-02T10:00:00Z,
+`,
+		},
+		{
+			name:        "GoodCase/NDJSON",
+			format:      domain.NDJSONFormat,
+			records:     records[2:],
+			setRepoMock: true,
+			output: `{"type":"credit","credit":{"filmId":1,"actorId":5},"updatedAt":"2024-03-02T10:00:00Z"}
+`,
+		},
+		{
+			name:        "GoodCase/JSON",
+			format:      domain.JSONFormat,
+			records:     records[2:],
+			setRepoMock: true,
+			output: `[
+{"type":"credit","credit":{"filmId":1,"actorId":5},"updatedAt":"2024-03-02T10:00:00Z"}
+]
+`,
+		},
+		{
+			name:        "GoodCase/EmptyJSON",
+			format:      domain.JSONFormat,
+			setRepoMock: true,
+			output:      "[]\n",
+		},
+		{
+			name:   "BadCase/UnknownFormat",
+			format: "xml",
+			err:    domain.ErrBadRequest,
+		},
+		{
+			name:   "BadCase/UnknownType",
+			filter: domain.ExportFilter{Types: []domain.ImportRecordType{"series"}},
+			format: domain.CSVFormat,
+			err:    domain.ErrBadRequest,
+		},
+		{
+			name:   "BadCase/WrongReleaseRange",
+			filter: domain.ExportFilter{ReleasedFrom: released.Time, ReleasedTo: released.Time.AddDate(0, 0, -1)},
+			format: domain.CSVFormat,
+			err:    domain.ErrBadRequest,
+		},
+		{
+			name:        "BadCase/RepoError",
+			format:      domain.CSVFormat,
+			records:     records[:1],
+			setRepoMock: true,
+			repoErr:     domain.ErrInternalServerError,
+			err:         domain.ErrInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			catalogRepo := new(mocks.CatalogRepository)
+			if test.setRepoMock {
+				catalogRepo.On("Export", test.filter, mock.Anything).
+					Run(func(args mock.Arguments) {
+						fn := args.Get(1).(func(domain.ExportRecord) error)
+						for _, record := range test.records {
+							fn(record)
+						}
+					}).
+					Return(test.repoErr)
+			}
+
+			var out bytes.Buffer
+			err := usecase.NewCatalogUsecase(catalogRepo, new(mocks.AuditUsecase), new(mocks.EventEmitter)).Export(test.filter, test.format, &out)
+
+			assert.ErrorIs(t, err, test.err)
+			assert.Equal(t, test.output, out.String())
+			catalogRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package usecase
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+)
+
+// nullValue marks an empty field of the dump.
+const nullValue = `\N`
+
+// maxTitleLength is the size of the film title column.
+const maxTitleLength = 150
+
+type datasetUsecase struct {
+	datasetRepo domain.DatasetRepository
+	batchSize   int
+}
+
+func NewDatasetUsecase(dr domain.DatasetRepository, batchSize int) domain.DatasetUsecase {
+	return &datasetUsecase{
+		datasetRepo: dr,
+		batchSize:   batchSize,
+	}
+}
+
+// Import reads the file line by line, the lines before the saved offset
+// are only skipped, as a gzip stream can`t be seeked.
+func (u *datasetUsecase) Import(kind domain.DatasetKind, r io.Reader, size int64, restart bool,
+	progress func(domain.DatasetProgress)) (domain.DatasetProgress, error) {
+	parse, ok := datasetParsers[kind]
+	if !ok {
+		return domain.DatasetProgress{}, fmt.Errorf("%w: unknown dataset %q", domain.ErrBadRequest, kind)
+	}
+
+	var offset int64
+	if !restart {
+		var err error
+		if offset, err = u.datasetRepo.Offset(kind, size); err != nil {
+			logs.LogError(logs.Logger, "catalog/usecase", "ImportDataset", err, err.Error())
+			return domain.DatasetProgress{}, err
+		}
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	if !scanner.Scan() {
+		return domain.DatasetProgress{}, fmt.Errorf("%w: header is missing", domain.ErrBadRequest)
+	}
+	columns := make(map[string]int)
+	for i, name := range strings.Split(scanner.Text(), "\t") {
+		columns[name] = i
+	}
+	for _, name := range datasetColumns[kind] {
+		if _, ok := columns[name]; !ok {
+			return domain.DatasetProgress{}, fmt.Errorf("%w: %s column is missing", domain.ErrBadRequest, name)
+		}
+	}
+
+	p := domain.DatasetProgress{Kind: kind}
+	batch := domain.DatasetBatch{Kind: kind, Size: size, Rows: make([]domain.DatasetRow, 0, u.batchSize)}
+	pending := false
+	flush := func() error {
+		imported, err := u.datasetRepo.ImportBatch(batch)
+		if err != nil {
+			return err
+		}
+
+		p.Imported += imported
+		p.Skipped += int64(len(batch.Rows)) - imported
+		if progress != nil {
+			progress(p)
+		}
+		batch.Rows = batch.Rows[:0]
+		pending = false
+		return nil
+	}
+
+	for scanner.Scan() {
+		p.Lines++
+		if p.Lines <= offset {
+			continue
+		}
+
+		fields := strings.Split(scanner.Text(), "\t")
+		field := func(name string) string {
+			if i := columns[name]; i < len(fields) && fields[i] != nullValue {
+				return fields[i]
+			}
+			return ""
+		}
+
+		batch.Offset = p.Lines
+		pending = true
+		if row, ok := parse(field); ok {
+			batch.Rows = append(batch.Rows, row)
+		} else {
+			p.Skipped++
+		}
+
+		if len(batch.Rows) == u.batchSize {
+			if err := flush(); err != nil {
+				logs.LogError(logs.Logger, "catalog/usecase", "ImportDataset", err, err.Error())
+				return p, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		logs.LogError(logs.Logger, "catalog/usecase", "ImportDataset", err, err.Error())
+		return p, err
+	}
+
+	// the last batch saves the offset even if there are no rows left
+	if pending {
+		if err := flush(); err != nil {
+			logs.LogError(logs.Logger, "catalog/usecase", "ImportDataset", err, err.Error())
+			return p, err
+		}
+	}
+
+	return p, nil
+}
+
+var datasetColumns = map[domain.DatasetKind][]string{
+	domain.TitlesDataset:     {"tconst", "titleType", "primaryTitle", "startYear"},
+	domain.RatingsDataset:    {"tconst", "averageRating"},
+	domain.NamesDataset:      {"nconst", "primaryName", "birthYear", "primaryProfession"},
+	domain.PrincipalsDataset: {"tconst", "nconst", "category"},
+}
+
+// the parsers skip the rows which can`t be put into the catalog: not
+// movies, people who don`t act and ones without the dates
+var datasetParsers = map[domain.DatasetKind]func(field func(string) string) (domain.DatasetRow, bool){
+	domain.TitlesDataset: func(field func(string) string) (domain.DatasetRow, bool) {
+		date, ok := yearDate(field("startYear"))
+		title := []rune(field("primaryTitle"))
+		if field("titleType") != "movie" || !ok || len(title) == 0 {
+			return domain.DatasetRow{}, false
+		}
+		if len(title) > maxTitleLength {
+			title = title[:maxTitleLength]
+		}
+
+		return domain.DatasetRow{SourceID: field("tconst"), Title: string(title), Date: date}, true
+	},
+	domain.RatingsDataset: func(field func(string) string) (domain.DatasetRow, bool) {
+		rating, err := strconv.ParseFloat(field("averageRating"), 64)
+		if err != nil || rating < 0 || rating > 10 {
+			return domain.DatasetRow{}, false
+		}
+
+		return domain.DatasetRow{SourceID: field("tconst"), Rating: rating}, true
+	},
+	domain.NamesDataset: func(field func(string) string) (domain.DatasetRow, bool) {
+		date, ok := yearDate(field("birthYear"))
+		name := field("primaryName")
+		if !ok || name == "" {
+			return domain.DatasetRow{}, false
+		}
+
+		row := domain.DatasetRow{SourceID: field("nconst"), Name: name, Date: date}
+		for _, profession := range strings.Split(field("primaryProfession"), ",") {
+			switch profession {
+			case "actress":
+				row.Sex = domain.F
+			case "actor":
+				row.Sex = domain.M
+			}
+		}
+
+		return row, row.Sex != ""
+	},
+	domain.PrincipalsDataset: func(field func(string) string) (domain.DatasetRow, bool) {
+		if category := field("category"); category != "actor" && category != "actress" {
+			return domain.DatasetRow{}, false
+		}
+
+		return domain.DatasetRow{FilmSourceID: field("tconst"), ActorSourceID: field("nconst")}, true
+	},
+}
+
+// yearDate makes the first day of the year, the dumps have no exact dates.
+func yearDate(year string) (pgtype.Date, bool) {
+	y, err := strconv.Atoi(year)
+	if err != nil || y < 1800 || y > time.Now().Year() {
+		return pgtype.Date{}, false
+	}
+
+	return pgtype.Date{Time: time.Date(y, time.January, 1, 0, 0, 0, 0, time.UTC), Valid: true}, true
+}
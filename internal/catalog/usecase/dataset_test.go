@@ -0,0 +1,151 @@
+package usecase_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ellexo2456/FilmLib/internal/catalog/usecase"
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	"github.com/ellexo2456/FilmLib/internal/domain/mocks"
+)
+
+func yearDate(year int) pgtype.Date {
+	return pgtype.Date{Time: time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+}
+
+func TestImportDataset(t *testing.T) {
+	titles := "tconst\ttitleType\tprimaryTitle\toriginalTitle\tisAdult\tstartYear\tendYear\truntimeMinutes\tgenres\n" +
+		"tt0133093\tmovie\tThe Matrix\tThe Matrix\t0\t1999\t\\N\t136\tAction,Sci-Fi\n" +
+		"tt0108778\ttvSeries\tFriends\tFriends\t0\t1994\t2004\t22\tComedy\n" +
+		"tt0111161\tmovie\tThe Shawshank Redemption\tThe Shawshank Redemption\t0\t1994\t\\N\t142\tDrama\n" +
+		"tt9999999\tmovie\tUnknown\tUnknown\t0\t\\N\t\\N\t\\N\t\\N\n"
+	names := "nconst\tprimaryName\tbirthYear\tdeathYear\tprimaryProfession\tknownForTitles\n" +
+		"nm0000206\tKeanu Reeves\t1964\t\\N\tactor,producer,director\ttt0133093\n" +
+		"nm0005251\tCarrie-Anne Moss\t1967\t\\N\tactress,producer\ttt0133093\n" +
+		"nm0905154\tLana Wachowski\t1965\t\\N\twriter,director,producer\ttt0133093\n"
+	principals := "tconst\tordering\tnconst\tcategory\tjob\tcharacters\n" +
+		"tt0133093\t1\tnm0000206\tactor\t\\N\t[\"Neo\"]\n" +
+		"tt0133093\t5\tnm0905154\tdirector\t\\N\t\\N\n"
+
+	matrix := domain.DatasetRow{SourceID: "tt0133093", Title: "The Matrix", Date: yearDate(1999)}
+	shawshank := domain.DatasetRow{SourceID: "tt0111161", Title: "The Shawshank Redemption", Date: yearDate(1994)}
+
+	tests := []struct {
+		name        string
+		kind        domain.DatasetKind
+		input       string
+		restart     bool
+		setRepoMock func(repo *mocks.DatasetRepository)
+		progress    []domain.DatasetProgress
+		result      domain.DatasetProgress
+		err         error
+	}{
+		{
+			name:  "GoodCase/TitlesInBatches",
+			kind:  domain.TitlesDataset,
+			input: titles,
+			setRepoMock: func(repo *mocks.DatasetRepository) {
+				repo.On("Offset", domain.TitlesDataset, int64(100)).Return(int64(0), nil)
+				repo.On("ImportBatch", domain.DatasetBatch{Kind: domain.TitlesDataset, Size: 100,
+					Rows: []domain.DatasetRow{matrix, shawshank}, Offset: 3}).Return(int64(2), nil).Once()
+				repo.On("ImportBatch", domain.DatasetBatch{Kind: domain.TitlesDataset, Size: 100,
+					Rows: []domain.DatasetRow{}, Offset: 4}).Return(int64(0), nil).Once()
+			},
+			progress: []domain.DatasetProgress{
+				{Kind: domain.TitlesDataset, Lines: 3, Imported: 2, Skipped: 1},
+				{Kind: domain.TitlesDataset, Lines: 4, Imported: 2, Skipped: 2},
+			},
+			result: domain.DatasetProgress{Kind: domain.TitlesDataset, Lines: 4, Imported: 2, Skipped: 2},
+		},
+		{
+			name:  "GoodCase/Resumed",
+			kind:  domain.TitlesDataset,
+			input: titles,
+			setRepoMock: func(repo *mocks.DatasetRepository) {
+				repo.On("Offset", domain.TitlesDataset, int64(100)).Return(int64(2), nil)
+				repo.On("ImportBatch", domain.DatasetBatch{Kind: domain.TitlesDataset, Size: 100,
+					Rows: []domain.DatasetRow{shawshank}, Offset: 4}).Return(int64(1), nil).Once()
+			},
+			progress: []domain.DatasetProgress{
+				{Kind: domain.TitlesDataset, Lines: 4, Imported: 1, Skipped: 1},
+			},
+			result: domain.DatasetProgress{Kind: domain.TitlesDataset, Lines: 4, Imported: 1, Skipped: 1},
+		},
+		{
+			name:    "GoodCase/NamesRestarted",
+			kind:    domain.NamesDataset,
+			input:   names,
+			restart: true,
+			setRepoMock: func(repo *mocks.DatasetRepository) {
+				repo.On("ImportBatch", domain.DatasetBatch{Kind: domain.NamesDataset, Size: 100, Rows: []domain.DatasetRow{
+					{SourceID: "nm0000206", Name: "Keanu Reeves", Sex: domain.M, Date: yearDate(1964)},
+					{SourceID: "nm0005251", Name: "Carrie-Anne Moss", Sex: domain.F, Date: yearDate(1967)},
+				}, Offset: 2}).Return(int64(1), nil).Once()
+				repo.On("ImportBatch", domain.DatasetBatch{Kind: domain.NamesDataset, Size: 100,
+					Rows: []domain.DatasetRow{}, Offset: 3}).Return(int64(0), nil).Once()
+			},
+			progress: []domain.DatasetProgress{
+				{Kind: domain.NamesDataset, Lines: 2, Imported: 1, Skipped: 1},
+				{Kind: domain.NamesDataset, Lines: 3, Imported: 1, Skipped: 2},
+			},
+			result: domain.DatasetProgress{Kind: domain.NamesDataset, Lines: 3, Imported: 1, Skipped: 2},
+		},
+		{
+			name:  "GoodCase/Principals",
+			kind:  domain.PrincipalsDataset,
+			input: principals,
+			setRepoMock: func(repo *mocks.DatasetRepository) {
+				repo.On("Offset", domain.PrincipalsDataset, int64(100)).Return(int64(0), nil)
+				repo.On("ImportBatch", domain.DatasetBatch{Kind: domain.PrincipalsDataset, Size: 100, Rows: []domain.DatasetRow{
+					{FilmSourceID: "tt0133093", ActorSourceID: "nm0000206"},
+				}, Offset: 2}).Return(int64(1), nil).Once()
+			},
+			progress: []domain.DatasetProgress{
+				{Kind: domain.PrincipalsDataset, Lines: 2, Imported: 1, Skipped: 1},
+			},
+			result: domain.DatasetProgress{Kind: domain.PrincipalsDataset, Lines: 2, Imported: 1, Skipped: 1},
+		},
+		{
+			name:  "BadCase/ColumnMissing",
+			kind:  domain.RatingsDataset,
+			input: "tconst\tnumVotes\ntt0133093\t2000000\n",
+			setRepoMock: func(repo *mocks.DatasetRepository) {
+				repo.On("Offset", domain.RatingsDataset, int64(100)).Return(int64(0), nil)
+			},
+			err: domain.ErrBadRequest,
+		},
+		{
+			name:        "BadCase/UnknownKind",
+			kind:        "title.akas",
+			input:       titles,
+			setRepoMock: func(repo *mocks.DatasetRepository) {},
+			err:         domain.ErrBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			datasetRepo := new(mocks.DatasetRepository)
+			test.setRepoMock(datasetRepo)
+
+			var progress []domain.DatasetProgress
+			result, err := usecase.NewDatasetUsecase(datasetRepo, 2).Import(test.kind, strings.NewReader(test.input), 100,
+				test.restart, func(p domain.DatasetProgress) {
+					progress = append(progress, p)
+				})
+
+			assert.ErrorIs(t, err, test.err)
+			assert.Equal(t, test.result, result)
+			assert.Equal(t, test.progress, progress)
+			datasetRepo.AssertExpectations(t)
+			datasetRepo.AssertNotCalled(t, "ImportBatch", mock.MatchedBy(func(b domain.DatasetBatch) bool {
+				return b.Offset == 0
+			}))
+		})
+	}
+}
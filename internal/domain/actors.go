@@ -1,6 +1,11 @@
 package domain
 
-import "github.com/jackc/pgx/v5/pgtype"
+import (
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
 
 type Sex string
 
@@ -15,19 +20,88 @@ type Actor struct {
 	Sex       Sex         `json:"sex"`
 	Birthdate pgtype.Date `json:"birthdate"`
 	Films     []Film      `json:"films,omitempty"`
+	DeletedAt *time.Time  `json:"deletedAt,omitempty"`
+	Version   int         `json:"-"`
+}
+
+// Validate checks the actor against the constraints of the actor table,
+// the error wraps ErrBadRequest and tells what is wrong.
+func (a Actor) Validate() error {
+	if a.Name == "" {
+		return fmt.Errorf("%w: name is empty", ErrBadRequest)
+	}
+	if a.Sex != M && a.Sex != F {
+		return fmt.Errorf("%w: sex must be M or F", ErrBadRequest)
+	}
+	if !validDate(a.Birthdate) {
+		return fmt.Errorf("%w: birthdate must be from 1800-01-01 to today", ErrBadRequest)
+	}
+
+	return nil
+}
+
+const ThresholdParam = "threshold"
+
+// DefaultDuplicateThreshold is the name similarity the duplicates are
+// looked for with if no threshold is given.
+const DefaultDuplicateThreshold = 0.8
+
+// ActorDuplicate is a pair of actors born the same day whose normalized
+// names are alike, Similarity is from 0 to 1.
+type ActorDuplicate struct {
+	Actors     [2]Actor `json:"actors"`
+	Similarity float64  `json:"similarity"`
 }
 
 type ActorsRepository interface {
-	Insert(actor Actor) (int, error)
+	// Insert and Update save a new revision of the actor made by userID.
+	Insert(actor Actor, userID int) (int, error)
+	// Delete moves the actor to the trash, its film links are kept.
 	Delete(id int) error
-	Update(actor Actor) (Actor, error)
+	// Update increases the actor version. A non-zero Version is the
+	// expected one, ErrPreconditionFailed is returned if it has changed.
+	Update(actor Actor, userID int) (Actor, error)
 	SelectById(id int) (Actor, error)
 	SelectAll() ([]Actor, error)
+	SelectRevisions(actorID int) ([]ActorRevision, error)
+	SelectRevision(actorID, revision int) (ActorRevision, error)
+	// Revert restores the actor from the revision and saves it as a new one.
+	Revert(actorID, revision, version, userID int) (Actor, error)
+	SelectDeleted() ([]Actor, error)
+	Restore(id int) (Actor, error)
+	// Purge permanently removes the actors deleted before the time.
+	Purge(before time.Time) (int, error)
+	// SelectSameBirthdate returns the actors who share the birthdate with
+	// another one, ordered by the birthdate.
+	SelectSameBirthdate() ([]Actor, error)
+	// Merge moves the film links of the duplicate onto the actor, deletes
+	// the duplicate and redirects its id to the actor. The films of the
+	// duplicate get a new revision by the user.
+	Merge(id, duplicateID, userID int) (Actor, error)
+	// SelectRedirect returns the id the merged actor id points to.
+	SelectRedirect(id int) (int, error)
 }
 
 type ActorsUsecase interface {
-	Add(actor Actor) (int, error)
-	Remove(id int) error
-	Modify(actor Actor) (Actor, error)
+	Add(actor Actor, ac AuditContext) (int, error)
+	Remove(id int, ac AuditContext) error
+	// GetById resolves the id of a merged actor, the returned actor has the
+	// id it was merged into then.
+	GetById(id int) (Actor, error)
+	// Modify checks the actor Version the same way as the repository Update.
+	Modify(actor Actor, ac AuditContext) (Actor, error)
+	// Patch changes only the fields present in the patch, it is validated
+	// before the actor is updated.
+	Patch(id int, patch Patch, ac AuditContext) (Actor, error)
 	GetAll() ([]Actor, error)
+	GetRevisions(actorID int) ([]ActorRevision, error)
+	DiffRevisions(actorID, from, to int) (RevisionDiff, error)
+	Revert(actorID, revision, version int, ac AuditContext) (Actor, error)
+	GetDeleted() ([]Actor, error)
+	Restore(id int, ac AuditContext) (Actor, error)
+	Purge(retention time.Duration) (int, error)
+	GetDuplicates(threshold float64) ([]ActorDuplicate, error)
+	// Merge keeps the actor with the id, the duplicate is kept in the
+	// audit log only.
+	Merge(id, duplicateID int, ac AuditContext) (Actor, error)
 }
@@ -0,0 +1,47 @@
+package domain
+
+import "time"
+
+const (
+	RoleParam   = "role"
+	LimitParam  = "limit"
+	OffsetParam = "offset"
+)
+
+const DefaultLimit = 50
+
+type UserInfo struct {
+	ID        int       `json:"id"`
+	Email     string    `json:"email"`
+	Role      Role      `json:"role"`
+	Verified  bool      `json:"verified"`
+	Disabled  bool      `json:"disabled"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// UsersFilter selects users whose email contains Search. A nil Role
+// matches any role.
+type UsersFilter struct {
+	Search string
+	Role   *Role
+	Limit  int
+	Offset int
+}
+
+type RoleChange struct {
+	Role Role `json:"role"`
+}
+
+type AdminUsecase interface {
+	GetUsers(filter UsersFilter) ([]UserInfo, error)
+	SetRole(adminID, userID int, role Role) error
+	SetDisabled(adminID, userID int, disabled bool) error
+	Logout(userID int) error
+	SetTwoFactorRequired(role Role, required bool) error
+}
+
+type UsersRepository interface {
+	Select(filter UsersFilter) ([]UserInfo, error)
+	UpdateRole(userID int, role Role) error
+	UpdateDisabled(userID int, disabled bool) error
+}
@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"net/http"
+	"time"
+)
+
+const (
+	RequestIDHeader = "Request-ID"
+
+	UserIDParam   = "userId"
+	EntityParam   = "entity"
+	EntityIDParam = "entityId"
+	FromParam     = "from"
+	ToParam       = "to"
+)
+
+type AuditAction string
+
+const (
+	AuditCreate  AuditAction = "create"
+	AuditUpdate  AuditAction = "update"
+	AuditDelete  AuditAction = "delete"
+	AuditRestore AuditAction = "restore"
+	AuditMerge   AuditAction = "merge"
+)
+
+type AuditEntity string
+
+const (
+	AuditFilm  AuditEntity = "film"
+	AuditActor AuditEntity = "actor"
+)
+
+func (e AuditEntity) Valid() bool {
+	return e == AuditFilm || e == AuditActor
+}
+
+// AuditContext is who makes a change and within which request.
+type AuditContext struct {
+	UserID    int
+	RequestID string
+}
+
+// RequestAuditContext must be called after the auth middleware.
+func RequestAuditContext(r *http.Request) AuditContext {
+	sc, _ := r.Context().Value(SessionContextKey).(SessionContext)
+	return AuditContext{UserID: sc.UserID, RequestID: r.Header.Get(RequestIDHeader)}
+}
+
+// FieldChange holds the json values of a field, a nil one means that
+// there was no such field or entity.
+type FieldChange struct {
+	Before interface{} `json:"before,omitempty"`
+	After  interface{} `json:"after,omitempty"`
+}
+
+type AuditEntry struct {
+	ID        int64                  `json:"id"`
+	UserID    int                    `json:"userId"`
+	Action    AuditAction            `json:"action"`
+	Entity    AuditEntity            `json:"entity"`
+	EntityID  int                    `json:"entityId"`
+	Diff      map[string]FieldChange `json:"diff"`
+	RequestID string                 `json:"requestId"`
+	CreatedAt time.Time              `json:"createdAt"`
+}
+
+// AuditFilter matches entries made in [From, To). Zero values match
+// everything.
+type AuditFilter struct {
+	UserID   int
+	Entity   AuditEntity
+	EntityID int
+	From     time.Time
+	To       time.Time
+	Limit    int
+	Offset   int
+}
+
+type AuditUsecase interface {
+	// Record takes the entity before and after the change, nil when
+	// there is no such one.
+	Record(ac AuditContext, action AuditAction, entity AuditEntity, entityID int, before, after interface{})
+	GetAll(filter AuditFilter) ([]AuditEntry, error)
+}
+
+type AuditRepository interface {
+	Insert(entry AuditEntry) error
+	Select(filter AuditFilter) ([]AuditEntry, error)
+}
@@ -9,20 +9,35 @@ type Role int
 const (
 	Usr Role = iota
 	Moder
+	Admin
 )
 
+func (r Role) Valid() bool {
+	return r >= Usr && r <= Admin
+}
+
 type Key string
 
 const SessionContextKey Key = "SessionContextKey"
 
+// SessionContext of a request made with an API token has TokenID set
+// and is limited to Scopes on top of the role permissions.
+//
+// ExpiresAt is set when a session has been extended by the request,
+// so its cookie has to be reissued.
 type SessionContext struct {
-	UserID int
-	Role   Role
+	UserID    int
+	Role      Role
+	Verified  bool
+	TokenID   int
+	Scopes    []Permission
+	ExpiresAt time.Time `json:"-"`
 }
 
 type Credentials struct {
-	Password []byte `json:"password"`
-	Email    string `json:"email"`
+	Password   []byte `json:"password"`
+	Email      string `json:"email"`
+	RememberMe bool   `json:"rememberMe"`
 }
 
 type User struct {
@@ -33,30 +48,110 @@ type User struct {
 	ImagePath string `json:"imagePath"`
 	ImageData []byte `json:"imageData"`
 	Role      Role
+	Verified  bool `json:"-"`
+	Disabled  bool `json:"-"`
 }
 
+type EmailRequest struct {
+	Email string `json:"email"`
+}
+
+type PasswordReset struct {
+	Token    string `json:"token"`
+	Password []byte `json:"password"`
+}
+
+// Session with MFARequired isn`t a session yet: its token is a
+// challenge for the second login step.
+//
+// ExpiresAt moves forward while the session is used, but never past
+// Deadline. RememberToken is set if the user asked to be remembered.
 type Session struct {
-	Token     string    `json:"token"`
-	ExpiresAt time.Time `json:"expiresAt"`
-	UserID    int       `json:"-"`
-	Role      Role      `json:"-"`
+	Token             string    `json:"token"`
+	ExpiresAt         time.Time `json:"expiresAt"`
+	UserID            int       `json:"-"`
+	Role              Role      `json:"-"`
+	Verified          bool      `json:"-"`
+	MFARequired       bool      `json:"-"`
+	Client            Client    `json:"-"`
+	CreatedAt         time.Time `json:"-"`
+	Deadline          time.Time `json:"-"`
+	RememberToken     string    `json:"-"`
+	RememberExpiresAt time.Time `json:"-"`
+}
+
+// TokenPair is issued in the jwt auth mode. ExpiresAt is the expiry
+// of the access token.
+type TokenPair struct {
+	AccessToken  string    `json:"accessToken"`
+	RefreshToken string    `json:"refreshToken"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken"`
 }
 
 type AuthUsecase interface {
-	Login(credentials Credentials) (Session, int, error)
-	Logout(token string) error
+	Login(credentials Credentials, client Client) (Session, int, error)
+	Logout(token, rememberToken string) error
 	Register(user User) (int, error)
 	RetrieveSessionContext(token string) (SessionContext, error)
+	Resume(rememberToken string, client Client) (Session, error)
+}
+
+type TokenAuthUsecase interface {
+	Login(credentials Credentials) (TokenPair, error)
+	Refresh(refreshToken string) (TokenPair, error)
+	Revoke(refreshToken string) error
+	RetrieveSessionContext(accessToken string) (SessionContext, error)
+}
+
+type PasswordUsecase interface {
+	RequestReset(email string) error
+	Reset(reset PasswordReset) error
+}
+
+type VerificationUsecase interface {
+	Send(user User) error
+	Resend(email string) error
+	Verify(token string) error
+	IsVerified(userID int) (bool, error)
 }
 
 type AuthRepository interface {
 	GetByEmail(email string) (User, error)
+	GetByID(id int) (User, error)
 	AddUser(user User) (int, error)
 	UserExists(email string) (bool, error)
+	UpdatePassword(userID int, password []byte) error
+	SetVerified(userID int) error
 }
 
 type SessionRepository interface {
 	Add(session Session) error
 	DeleteByToken(token string) error
+	DeleteByUserID(userID int) error
 	GetSessionContext(token string) (SessionContext, error)
+	GetByUserID(userID int) ([]SessionInfo, error)
+	DeleteByID(userID int, id string) error
+	Touch(token string, at time.Time, idle time.Duration) (time.Time, error)
+}
+
+type ResetTokenRepository interface {
+	Add(token string, userID int, ttl time.Duration) error
+	Pop(token string) (int, error)
+}
+
+type RememberTokenRepository interface {
+	Add(token string, rt RememberToken, ttl time.Duration) error
+	Use(token string) (RememberToken, error)
+	DeleteFamily(family string) error
+	DeleteByUserID(userID int) error
+}
+
+type RefreshTokenRepository interface {
+	Add(token string, userID int, ttl time.Duration) error
+	Pop(token string) (int, error)
+	DeleteByUserID(userID int) error
 }
@@ -0,0 +1,178 @@
+package domain
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	FormatParam       = "format"
+	CommitParam       = "commit"
+	ReleasedFromParam = "releasedFrom"
+	ReleasedToParam   = "releasedTo"
+	UpdatedSinceParam = "updatedSince"
+)
+
+type CatalogFormat string
+
+const (
+	CSVFormat    CatalogFormat = "csv"
+	NDJSONFormat CatalogFormat = "ndjson"
+	JSONFormat   CatalogFormat = "json"
+)
+
+func (f CatalogFormat) Valid() bool {
+	return f == CSVFormat || f == NDJSONFormat || f == JSONFormat
+}
+
+// Importable tells if the format can be read row by row.
+func (f CatalogFormat) Importable() bool {
+	return f == CSVFormat || f == NDJSONFormat
+}
+
+type ImportRecordType string
+
+const (
+	FilmRecord   ImportRecordType = "film"
+	ActorRecord  ImportRecordType = "actor"
+	CreditRecord ImportRecordType = "credit"
+)
+
+func (t ImportRecordType) Valid() bool {
+	return t == FilmRecord || t == ActorRecord || t == CreditRecord
+}
+
+// ImportRecord is a row of an import. Films are matched by the title and
+// the release date, actors by the name and the birthdate. A credit links
+// the film and the actor found the same way, so it has fields of both.
+type ImportRecord struct {
+	Line        int              `json:"-"`
+	Type        ImportRecordType `json:"type"`
+	Title       string           `json:"title"`
+	Description string           `json:"description"`
+	ReleaseDate pgtype.Date      `json:"releaseDate"`
+	Rating      float64          `json:"rating"`
+	Name        string           `json:"name"`
+	Sex         Sex              `json:"sex"`
+	Birthdate   pgtype.Date      `json:"birthdate"`
+}
+
+func (r ImportRecord) Film() Film {
+	return Film{Title: r.Title, Description: r.Description, ReleaseDate: r.ReleaseDate, Rating: r.Rating}
+}
+
+func (r ImportRecord) Actor() Actor {
+	return Actor{Name: r.Name, Sex: r.Sex, Birthdate: r.Birthdate}
+}
+
+func (r ImportRecord) Validate() error {
+	switch r.Type {
+	case FilmRecord:
+		return r.Film().Validate()
+	case ActorRecord:
+		return r.Actor().Validate()
+	case CreditRecord:
+		if r.Title == "" || !r.ReleaseDate.Valid {
+			return fmt.Errorf("%w: credit needs the film title and release date", ErrBadRequest)
+		}
+		if r.Name == "" || !r.Birthdate.Valid {
+			return fmt.Errorf("%w: credit needs the actor name and birthdate", ErrBadRequest)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown type %q", ErrBadRequest, r.Type)
+	}
+}
+
+type ImportStatus string
+
+const (
+	ImportCreated ImportStatus = "created"
+	ImportUpdated ImportStatus = "updated"
+	ImportSkipped ImportStatus = "skipped"
+	ImportFailed  ImportStatus = "failed"
+)
+
+// ImportResult is the outcome of the record on the line. Before and After
+// are the entity around the change, they go to the audit log.
+type ImportResult struct {
+	Line   int              `json:"line"`
+	Type   ImportRecordType `json:"type,omitempty"`
+	Status ImportStatus     `json:"status"`
+	ID     int              `json:"id,omitempty"`
+	Reason string           `json:"reason,omitempty"`
+	Before interface{}      `json:"-"`
+	After  interface{}      `json:"-"`
+}
+
+type ImportReport struct {
+	Committed bool           `json:"committed"`
+	Created   int            `json:"created"`
+	Updated   int            `json:"updated"`
+	Skipped   int            `json:"skipped"`
+	Failed    int            `json:"failed"`
+	Rows      []ImportResult `json:"rows"`
+}
+
+type Credit struct {
+	FilmID  int `json:"filmId"`
+	ActorID int `json:"actorId"`
+}
+
+// ExportRecord is a row of the export, only the field of its type is set.
+// UpdatedAt of a credit is the time it or its film was changed.
+type ExportRecord struct {
+	Type      ImportRecordType `json:"type"`
+	Film      *Film            `json:"film,omitempty"`
+	Actor     *Actor           `json:"actor,omitempty"`
+	Credit    *Credit          `json:"credit,omitempty"`
+	UpdatedAt *time.Time       `json:"updatedAt,omitempty"`
+}
+
+// ExportFilter zero values match everything. With UpdatedSince set the
+// entities deleted since then are exported too, so a copy can drop them.
+// The release dates are inclusive, they also select the credits and the
+// actors of the films.
+type ExportFilter struct {
+	Types        []ImportRecordType
+	ReleasedFrom time.Time
+	ReleasedTo   time.Time
+	UpdatedSince time.Time
+}
+
+func (f ExportFilter) Has(t ImportRecordType) bool {
+	if len(f.Types) == 0 {
+		return true
+	}
+	for _, tt := range f.Types {
+		if tt == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+type CatalogRepository interface {
+	// Import applies the records in one transaction, a record which fails
+	// is rolled back alone. Nothing is saved unless commit is set. Every
+	// change is saved as a revision of the user.
+	Import(records []ImportRecord, commit bool, userID int) ([]ImportResult, error)
+	// Export passes the records to fn while they are read from the
+	// database, films first, then actors and credits, all from the same
+	// snapshot. It stops at the first error of fn.
+	Export(filter ExportFilter, fn func(ExportRecord) error) error
+}
+
+type CatalogUsecase interface {
+	// Import reads and validates every record, the valid ones are applied
+	// in the order of the input. In the dry run the report is the same,
+	// but nothing is saved.
+	Import(r io.Reader, format CatalogFormat, commit bool, ac AuditContext) (ImportReport, error)
+	// Export writes the records one by one, nothing is written if the
+	// filter or the format is invalid.
+	Export(filter ExportFilter, format CatalogFormat, w io.Writer) error
+}
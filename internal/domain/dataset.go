@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"io"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// DatasetKind is a file of the IMDb-style dumps, named the same way.
+type DatasetKind string
+
+const (
+	TitlesDataset     DatasetKind = "title.basics"
+	RatingsDataset    DatasetKind = "title.ratings"
+	NamesDataset      DatasetKind = "name.basics"
+	PrincipalsDataset DatasetKind = "title.principals"
+)
+
+// DatasetKinds are in the order the files must be imported, principals
+// link the films and the actors which are already there.
+var DatasetKinds = []DatasetKind{TitlesDataset, RatingsDataset, NamesDataset, PrincipalsDataset}
+
+// DatasetRow is a line of the dump mapped onto the catalog. Titles have
+// SourceID, Title and Date, ratings SourceID and Rating, names SourceID,
+// Name, Sex and Date, principals FilmSourceID and ActorSourceID.
+type DatasetRow struct {
+	SourceID      string
+	Title         string
+	Name          string
+	Sex           Sex
+	Date          pgtype.Date
+	Rating        float64
+	FilmSourceID  string
+	ActorSourceID string
+}
+
+// DatasetBatch is the rows read from the file up to the Offset line.
+type DatasetBatch struct {
+	Kind   DatasetKind
+	Size   int64
+	Rows   []DatasetRow
+	Offset int64
+}
+
+type DatasetProgress struct {
+	Kind DatasetKind `json:"kind"`
+	// Lines are read from the beginning of the file, the header excluded
+	Lines int64 `json:"lines"`
+	// Imported and Skipped are counted since the start of this run
+	Imported int64 `json:"imported"`
+	Skipped  int64 `json:"skipped"`
+}
+
+type DatasetRepository interface {
+	// Offset returns the count of lines of the file which are imported.
+	// It is kept per kind and is zero for a file of another size.
+	Offset(kind DatasetKind, size int64) (int64, error)
+	// ImportBatch copies the rows and saves the offset in one transaction.
+	// It returns the count of the rows which changed the catalog.
+	ImportBatch(batch DatasetBatch) (int64, error)
+}
+
+type DatasetUsecase interface {
+	// Import resumes from the saved offset unless restart is set, the
+	// progress is reported after every batch.
+	Import(kind DatasetKind, r io.Reader, size int64, restart bool, progress func(DatasetProgress)) (DatasetProgress, error)
+}
@@ -16,6 +16,16 @@ var (
 	ErrInvalidToken        = errors.New("session token is invalid")
 	ErrAlreadyExists       = errors.New("resource already exists")
 	ErrOutOfRange          = errors.New("id is out of range")
+	ErrForbidden           = errors.New("forbidden")
+	ErrNotVerified         = errors.New("email is not verified")
+	ErrDisabled            = errors.New("account is disabled")
+	ErrTwoFactorRequired   = errors.New("two-factor authentication is required")
+	ErrTooManyRequests     = errors.New("too many attempts, try again later")
+	ErrTokenReused         = errors.New("token has already been used")
+	ErrPreconditionFailed  = errors.New("resource has been changed")
+	ErrKeyReused           = errors.New("idempotency key is already used with another request")
+	ErrRequestInProgress   = errors.New("request with the idempotency key is in progress")
+	ErrResponseTooLarge    = errors.New("response is too large to replay")
 )
 
 func GetStatusCode(err error) int {
@@ -38,6 +48,26 @@ func GetStatusCode(err error) int {
 		return http.StatusNotFound
 	case errors.Is(err, ErrAlreadyExists):
 		return http.StatusConflict
+	case errors.Is(err, ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, ErrNotVerified):
+		return http.StatusForbidden
+	case errors.Is(err, ErrDisabled):
+		return http.StatusForbidden
+	case errors.Is(err, ErrTwoFactorRequired):
+		return http.StatusForbidden
+	case errors.Is(err, ErrTokenReused):
+		return http.StatusUnauthorized
+	case errors.Is(err, ErrPreconditionFailed):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, ErrKeyReused):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, ErrRequestInProgress):
+		return http.StatusConflict
+	case errors.Is(err, ErrResponseTooLarge):
+		return http.StatusConflict
+	case errors.Is(err, ErrTooManyRequests):
+		return http.StatusTooManyRequests
 	default:
 		return http.StatusInternalServerError
 	}
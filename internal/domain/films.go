@@ -1,6 +1,12 @@
 package domain
 
-import "github.com/jackc/pgx/v5/pgtype"
+import (
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
 
 type SortDirection string
 
@@ -22,21 +28,66 @@ type Film struct {
 	ReleaseDate pgtype.Date `json:"releaseDate"`
 	Rating      float64     `json:"rating"`
 	Actors      []Actor     `json:"actors,omitempty"`
+	DeletedAt   *time.Time  `json:"deletedAt,omitempty"`
+	Version     int         `json:"-"`
+}
+
+// Validate checks the film against the constraints of the film table,
+// the error wraps ErrBadRequest and tells what is wrong.
+func (f Film) Validate() error {
+	if n := utf8.RuneCountInString(f.Title); n < 1 || n > 150 {
+		return fmt.Errorf("%w: title must be from 1 to 150 characters", ErrBadRequest)
+	}
+	if utf8.RuneCountInString(f.Description) > 1000 {
+		return fmt.Errorf("%w: description must be up to 1000 characters", ErrBadRequest)
+	}
+	if !validDate(f.ReleaseDate) {
+		return fmt.Errorf("%w: release date must be from 1800-01-01 to today", ErrBadRequest)
+	}
+	if f.Rating < 0 || f.Rating > 10 {
+		return fmt.Errorf("%w: rating must be from 0 to 10", ErrBadRequest)
+	}
+
+	return nil
 }
 
 type FilmsRepository interface {
-	Insert(film Film) (int, error)
+	// Insert and Update save a new revision of the film made by userID.
+	Insert(film Film, userID int) (int, error)
 	SelectAll() ([]Film, error)
 	Search(searchStr string) ([]Film, error)
+	// Delete moves the film to the trash, its cast links are kept.
 	Delete(id int) error
-	Update(film Film) (Film, error)
+	// Update increases the film version. A non-zero Version is the
+	// expected one, ErrPreconditionFailed is returned if it has changed.
+	Update(film Film, userID int) (Film, error)
 	SelectById(id int) (Film, error)
+	SelectRevisions(filmID int) ([]FilmRevision, error)
+	SelectRevision(filmID, revision int) (FilmRevision, error)
+	// Revert restores the film and its cast from the revision, actors
+	// deleted since then are left out. It is saved as a new revision.
+	Revert(filmID, revision, version, userID int) (Film, error)
+	SelectDeleted() ([]Film, error)
+	Restore(id int) (Film, error)
+	// Purge permanently removes the films deleted before the time.
+	Purge(before time.Time) (int, error)
 }
 
 type FilmsUsecase interface {
-	Add(film Film) (int, error)
+	Add(film Film, ac AuditContext) (int, error)
 	GetAll(title, releaseDate SortDirection) ([]Film, error)
 	Search(searchStr string) ([]Film, error)
-	Remove(id int) error
-	Modify(film Film) (Film, error)
+	Remove(id int, ac AuditContext) error
+	GetById(id int) (Film, error)
+	// Modify checks the film Version the same way as the repository Update.
+	Modify(film Film, ac AuditContext) (Film, error)
+	// Patch changes only the fields present in the patch, it is validated
+	// before the film is updated.
+	Patch(id int, patch Patch, ac AuditContext) (Film, error)
+	GetRevisions(filmID int) ([]FilmRevision, error)
+	DiffRevisions(filmID, from, to int) (RevisionDiff, error)
+	Revert(filmID, revision, version int, ac AuditContext) (Film, error)
+	GetDeleted() ([]Film, error)
+	Restore(id int, ac AuditContext) (Film, error)
+	Purge(retention time.Duration) (int, error)
 }
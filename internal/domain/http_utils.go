@@ -1,12 +1,35 @@
 package domain
 
 import (
+	"crypto/rand"
+	"encoding/base64"
 	"encoding/json"
 	logs "github.com/ellexo2456/FilmLib/internal/logger"
 	"io"
+	"math"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 )
 
+const (
+	SessionCookie  = "session_token"
+	RememberCookie = "remember_token"
+	CSRFCookie     = "csrf_token"
+	CSRFHeader     = "X-CSRF-Token"
+
+	ETagHeader        = "ETag"
+	IfMatchHeader     = "If-Match"
+	IfNoneMatchHeader = "If-None-Match"
+)
+
+// SecureCookies adds the Secure attribute to the cookies. Browsers
+// accept such cookies from http://localhost too, so it is turned off
+// only when the server is reached over plain http by another host.
+var SecureCookies = true
+
 type Response struct {
 	Body interface{} `json:"body,omitempty"`
 	Err  string      `json:"err,omitempty"`
@@ -28,3 +51,130 @@ func CloseAndAlert(body io.ReadCloser, packageName, funcName string) {
 		logs.LogError(logs.Logger, packageName, funcName, err, err.Error())
 	}
 }
+
+// ClientIP is the address of the peer, the server isn`t expected to run
+// behind a proxy.
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// SetRetryAfter rounds the wait up to whole seconds.
+func SetRetryAfter(w http.ResponseWriter, wait time.Duration) {
+	if wait <= 0 {
+		return
+	}
+
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+}
+
+// ETag is a strong entity tag of the version.
+func ETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+func SetETag(w http.ResponseWriter, version int) {
+	w.Header().Set(ETagHeader, ETag(version))
+}
+
+// IfMatchVersion reads the expected version from If-Match. Zero means
+// that the header is missing or is "*", so any version will do. Tags
+// which aren`t issued by ETag never match.
+func IfMatchVersion(r *http.Request) (int, error) {
+	tag := strings.TrimSpace(r.Header.Get(IfMatchHeader))
+	if tag == "" || tag == "*" {
+		return 0, nil
+	}
+
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, ErrPreconditionFailed
+	}
+	version, err := strconv.Atoi(tag[1 : len(tag)-1])
+	if err != nil || version <= 0 {
+		return 0, ErrPreconditionFailed
+	}
+
+	return version, nil
+}
+
+// NotModified reports whether one of the If-None-Match tags is the tag
+// of the version, the tags are compared weakly.
+func NotModified(r *http.Request, version int) bool {
+	header := r.Header.Get(IfNoneMatchHeader)
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+
+	etag := ETag(version)
+	for _, tag := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+func SetSessionCookie(w http.ResponseWriter, token string, expiresAt time.Time) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookie,
+		Value:    token,
+		Expires:  expiresAt,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   SecureCookies,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// SetRememberCookie is sent with every request, since the middleware
+// resumes the session wherever it has expired.
+func SetRememberCookie(w http.ResponseWriter, token string, expiresAt time.Time) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     RememberCookie,
+		Value:    token,
+		Expires:  expiresAt,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   SecureCookies,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// IssueCSRFToken sets a new token for the double-submit check. The
+// cookie is HttpOnly, clients take the token from the response body
+// and send it back in the CSRFHeader.
+func IssueCSRFToken(w http.ResponseWriter) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     CSRFCookie,
+		Value:    token,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   SecureCookies,
+		SameSite: http.SameSiteStrictMode,
+	})
+
+	return token, nil
+}
+
+func ClearCookie(w http.ResponseWriter, name string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    "",
+		Expires:  time.Now(),
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   SecureCookies,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
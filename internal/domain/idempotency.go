@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"net/http"
+	"time"
+)
+
+const (
+	IdempotencyKeyHeader    = "Idempotency-Key"
+	IdempotentReplayHeader  = "Idempotent-Replayed"
+	MaxIdempotencyKeyLength = 255
+	// MaxIdempotentResponseSize is the largest response body which is
+	// stored, only the status is kept for a larger one.
+	MaxIdempotentResponseSize = 1 << 20
+	// IdempotencyLease is how long a key is held by the request being
+	// handled, the key is kept for the whole ttl once it is answered.
+	// The lease is extended while the request runs and frees the key if
+	// the server goes down in the middle.
+	IdempotencyLease = 5 * time.Minute
+)
+
+// IdempotentResponse is the response stored for a key. A zero Status
+// means the first request with the key is still being handled. Hash is
+// of the method, the path and the body of that request. TooLarge marks
+// a response whose body wasn`t stored.
+type IdempotentResponse struct {
+	Hash     string      `json:"hash"`
+	Status   int         `json:"status,omitempty"`
+	Header   http.Header `json:"header,omitempty"`
+	Body     []byte      `json:"body,omitempty"`
+	TooLarge bool        `json:"tooLarge,omitempty"`
+}
+
+type IdempotencyRepository interface {
+	// Reserve saves the pending response unless the key is taken, false
+	// and the stored response are returned then.
+	Reserve(key string, pending IdempotentResponse, ttl time.Duration) (IdempotentResponse, bool, error)
+	Save(key string, response IdempotentResponse, ttl time.Duration) error
+	// Extend sets the ttl of the key anew.
+	Extend(key string, ttl time.Duration) error
+	Release(key string) error
+}
+
+type IdempotencyUsecase interface {
+	// Begin returns nil if the request is to be handled, the stored
+	// response to replay otherwise. It fails with ErrKeyReused
+	// for a key sent with another request and with ErrRequestInProgress
+	// while the first request is handled. A response too large to store
+	// isn`t replayed, the retries fail with ErrResponseTooLarge.
+	Begin(userID int, key, hash string) (*IdempotentResponse, error)
+	// Extend renews the lease of the key held by the request being
+	// handled.
+	Extend(userID int, key string) error
+	// Finish stores the response. The key is released after a server
+	// error, so the request can be retried.
+	Finish(userID int, key string, response IdempotentResponse) error
+}
@@ -0,0 +1,152 @@
+// Code generated by mockery v2.34.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "github.com/ellexo2456/FilmLib/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// APITokensRepository is an autogenerated mock type for the APITokensRepository type
+type APITokensRepository struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: userID, tokenID
+func (_m *APITokensRepository) Delete(userID int, tokenID int) error {
+	ret := _m.Called(userID, tokenID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int) error); ok {
+		r0 = rf(userID, tokenID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteByUser provides a mock function with given fields: userID
+func (_m *APITokensRepository) DeleteByUser(userID int) error {
+	ret := _m.Called(userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int) error); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByHash provides a mock function with given fields: hash
+func (_m *APITokensRepository) GetByHash(hash []byte) (domain.APIToken, domain.User, error) {
+	ret := _m.Called(hash)
+
+	var r0 domain.APIToken
+	var r1 domain.User
+	var r2 error
+	if rf, ok := ret.Get(0).(func([]byte) (domain.APIToken, domain.User, error)); ok {
+		return rf(hash)
+	}
+	if rf, ok := ret.Get(0).(func([]byte) domain.APIToken); ok {
+		r0 = rf(hash)
+	} else {
+		r0 = ret.Get(0).(domain.APIToken)
+	}
+
+	if rf, ok := ret.Get(1).(func([]byte) domain.User); ok {
+		r1 = rf(hash)
+	} else {
+		r1 = ret.Get(1).(domain.User)
+	}
+
+	if rf, ok := ret.Get(2).(func([]byte) error); ok {
+		r2 = rf(hash)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Insert provides a mock function with given fields: token
+func (_m *APITokensRepository) Insert(token domain.APIToken) (domain.APIToken, error) {
+	ret := _m.Called(token)
+
+	var r0 domain.APIToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(domain.APIToken) (domain.APIToken, error)); ok {
+		return rf(token)
+	}
+	if rf, ok := ret.Get(0).(func(domain.APIToken) domain.APIToken); ok {
+		r0 = rf(token)
+	} else {
+		r0 = ret.Get(0).(domain.APIToken)
+	}
+
+	if rf, ok := ret.Get(1).(func(domain.APIToken) error); ok {
+		r1 = rf(token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SelectByUser provides a mock function with given fields: userID
+func (_m *APITokensRepository) SelectByUser(userID int) ([]domain.APIToken, error) {
+	ret := _m.Called(userID)
+
+	var r0 []domain.APIToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) ([]domain.APIToken, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(int) []domain.APIToken); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.APIToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateLastUsed provides a mock function with given fields: tokenID, at
+func (_m *APITokensRepository) UpdateLastUsed(tokenID int, at time.Time) error {
+	ret := _m.Called(tokenID, at)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, time.Time) error); ok {
+		r0 = rf(tokenID, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewAPITokensRepository creates a new instance of APITokensRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAPITokensRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *APITokensRepository {
+	mock := &APITokensRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
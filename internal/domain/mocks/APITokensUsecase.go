@@ -0,0 +1,115 @@
+// Code generated by mockery v2.34.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "github.com/ellexo2456/FilmLib/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// APITokensUsecase is an autogenerated mock type for the APITokensUsecase type
+type APITokensUsecase struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: sc, token
+func (_m *APITokensUsecase) Create(sc domain.SessionContext, token domain.APITokenToAdd) (domain.CreatedAPIToken, error) {
+	ret := _m.Called(sc, token)
+
+	var r0 domain.CreatedAPIToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(domain.SessionContext, domain.APITokenToAdd) (domain.CreatedAPIToken, error)); ok {
+		return rf(sc, token)
+	}
+	if rf, ok := ret.Get(0).(func(domain.SessionContext, domain.APITokenToAdd) domain.CreatedAPIToken); ok {
+		r0 = rf(sc, token)
+	} else {
+		r0 = ret.Get(0).(domain.CreatedAPIToken)
+	}
+
+	if rf, ok := ret.Get(1).(func(domain.SessionContext, domain.APITokenToAdd) error); ok {
+		r1 = rf(sc, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAll provides a mock function with given fields: userID
+func (_m *APITokensUsecase) GetAll(userID int) ([]domain.APIToken, error) {
+	ret := _m.Called(userID)
+
+	var r0 []domain.APIToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) ([]domain.APIToken, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(int) []domain.APIToken); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.APIToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RetrieveSessionContext provides a mock function with given fields: token
+func (_m *APITokensUsecase) RetrieveSessionContext(token string) (domain.SessionContext, error) {
+	ret := _m.Called(token)
+
+	var r0 domain.SessionContext
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (domain.SessionContext, error)); ok {
+		return rf(token)
+	}
+	if rf, ok := ret.Get(0).(func(string) domain.SessionContext); ok {
+		r0 = rf(token)
+	} else {
+		r0 = ret.Get(0).(domain.SessionContext)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Revoke provides a mock function with given fields: userID, tokenID
+func (_m *APITokensUsecase) Revoke(userID int, tokenID int) error {
+	ret := _m.Called(userID, tokenID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int) error); ok {
+		r0 = rf(userID, tokenID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewAPITokensUsecase creates a new instance of APITokensUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAPITokensUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *APITokensUsecase {
+	mock := &APITokensUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
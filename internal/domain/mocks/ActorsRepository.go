@@ -5,6 +5,8 @@ package mocks
 import (
 	domain "github.com/ellexo2456/FilmLib/internal/domain"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // ActorsRepository is an autogenerated mock type for the ActorsRepository type
@@ -26,23 +28,119 @@ func (_m *ActorsRepository) Delete(id int) error {
 	return r0
 }
 
-// Insert provides a mock function with given fields: actor
-func (_m *ActorsRepository) Insert(actor domain.Actor) (int, error) {
-	ret := _m.Called(actor)
+// Insert provides a mock function with given fields: actor, userID
+func (_m *ActorsRepository) Insert(actor domain.Actor, userID int) (int, error) {
+	ret := _m.Called(actor, userID)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(domain.Actor, int) (int, error)); ok {
+		return rf(actor, userID)
+	}
+	if rf, ok := ret.Get(0).(func(domain.Actor, int) int); ok {
+		r0 = rf(actor, userID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(domain.Actor, int) error); ok {
+		r1 = rf(actor, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Merge provides a mock function with given fields: id, duplicateID, userID
+func (_m *ActorsRepository) Merge(id int, duplicateID int, userID int) (domain.Actor, error) {
+	ret := _m.Called(id, duplicateID, userID)
+
+	var r0 domain.Actor
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, int, int) (domain.Actor, error)); ok {
+		return rf(id, duplicateID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(int, int, int) domain.Actor); ok {
+		r0 = rf(id, duplicateID, userID)
+	} else {
+		r0 = ret.Get(0).(domain.Actor)
+	}
+
+	if rf, ok := ret.Get(1).(func(int, int, int) error); ok {
+		r1 = rf(id, duplicateID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Purge provides a mock function with given fields: before
+func (_m *ActorsRepository) Purge(before time.Time) (int, error) {
+	ret := _m.Called(before)
 
 	var r0 int
 	var r1 error
-	if rf, ok := ret.Get(0).(func(domain.Actor) (int, error)); ok {
-		return rf(actor)
+	if rf, ok := ret.Get(0).(func(time.Time) (int, error)); ok {
+		return rf(before)
 	}
-	if rf, ok := ret.Get(0).(func(domain.Actor) int); ok {
-		r0 = rf(actor)
+	if rf, ok := ret.Get(0).(func(time.Time) int); ok {
+		r0 = rf(before)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
 
-	if rf, ok := ret.Get(1).(func(domain.Actor) error); ok {
-		r1 = rf(actor)
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: id
+func (_m *ActorsRepository) Restore(id int) (domain.Actor, error) {
+	ret := _m.Called(id)
+
+	var r0 domain.Actor
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) (domain.Actor, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(int) domain.Actor); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(domain.Actor)
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Revert provides a mock function with given fields: actorID, revision, version, userID
+func (_m *ActorsRepository) Revert(actorID int, revision int, version int, userID int) (domain.Actor, error) {
+	ret := _m.Called(actorID, revision, version, userID)
+
+	var r0 domain.Actor
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, int, int, int) (domain.Actor, error)); ok {
+		return rf(actorID, revision, version, userID)
+	}
+	if rf, ok := ret.Get(0).(func(int, int, int, int) domain.Actor); ok {
+		r0 = rf(actorID, revision, version, userID)
+	} else {
+		r0 = ret.Get(0).(domain.Actor)
+	}
+
+	if rf, ok := ret.Get(1).(func(int, int, int, int) error); ok {
+		r1 = rf(actorID, revision, version, userID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -100,23 +198,149 @@ func (_m *ActorsRepository) SelectById(id int) (domain.Actor, error) {
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: actor
-func (_m *ActorsRepository) Update(actor domain.Actor) (domain.Actor, error) {
-	ret := _m.Called(actor)
+// SelectDeleted provides a mock function with given fields:
+func (_m *ActorsRepository) SelectDeleted() ([]domain.Actor, error) {
+	ret := _m.Called()
+
+	var r0 []domain.Actor
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]domain.Actor, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []domain.Actor); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Actor)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SelectRedirect provides a mock function with given fields: id
+func (_m *ActorsRepository) SelectRedirect(id int) (int, error) {
+	ret := _m.Called(id)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) (int, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(int) int); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SelectRevision provides a mock function with given fields: actorID, revision
+func (_m *ActorsRepository) SelectRevision(actorID int, revision int) (domain.ActorRevision, error) {
+	ret := _m.Called(actorID, revision)
+
+	var r0 domain.ActorRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, int) (domain.ActorRevision, error)); ok {
+		return rf(actorID, revision)
+	}
+	if rf, ok := ret.Get(0).(func(int, int) domain.ActorRevision); ok {
+		r0 = rf(actorID, revision)
+	} else {
+		r0 = ret.Get(0).(domain.ActorRevision)
+	}
+
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(actorID, revision)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SelectRevisions provides a mock function with given fields: actorID
+func (_m *ActorsRepository) SelectRevisions(actorID int) ([]domain.ActorRevision, error) {
+	ret := _m.Called(actorID)
+
+	var r0 []domain.ActorRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) ([]domain.ActorRevision, error)); ok {
+		return rf(actorID)
+	}
+	if rf, ok := ret.Get(0).(func(int) []domain.ActorRevision); ok {
+		r0 = rf(actorID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.ActorRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(actorID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SelectSameBirthdate provides a mock function with given fields:
+func (_m *ActorsRepository) SelectSameBirthdate() ([]domain.Actor, error) {
+	ret := _m.Called()
+
+	var r0 []domain.Actor
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]domain.Actor, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []domain.Actor); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Actor)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: actor, userID
+func (_m *ActorsRepository) Update(actor domain.Actor, userID int) (domain.Actor, error) {
+	ret := _m.Called(actor, userID)
 
 	var r0 domain.Actor
 	var r1 error
-	if rf, ok := ret.Get(0).(func(domain.Actor) (domain.Actor, error)); ok {
-		return rf(actor)
+	if rf, ok := ret.Get(0).(func(domain.Actor, int) (domain.Actor, error)); ok {
+		return rf(actor, userID)
 	}
-	if rf, ok := ret.Get(0).(func(domain.Actor) domain.Actor); ok {
-		r0 = rf(actor)
+	if rf, ok := ret.Get(0).(func(domain.Actor, int) domain.Actor); ok {
+		r0 = rf(actor, userID)
 	} else {
 		r0 = ret.Get(0).(domain.Actor)
 	}
 
-	if rf, ok := ret.Get(1).(func(domain.Actor) error); ok {
-		r1 = rf(actor)
+	if rf, ok := ret.Get(1).(func(domain.Actor, int) error); ok {
+		r1 = rf(actor, userID)
 	} else {
 		r1 = ret.Error(1)
 	}
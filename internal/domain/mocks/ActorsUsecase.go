@@ -5,6 +5,8 @@ package mocks
 import (
 	domain "github.com/ellexo2456/FilmLib/internal/domain"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // ActorsUsecase is an autogenerated mock type for the ActorsUsecase type
@@ -12,23 +14,47 @@ type ActorsUsecase struct {
 	mock.Mock
 }
 
-// Add provides a mock function with given fields: actor
-func (_m *ActorsUsecase) Add(actor domain.Actor) (int, error) {
-	ret := _m.Called(actor)
+// Add provides a mock function with given fields: actor, ac
+func (_m *ActorsUsecase) Add(actor domain.Actor, ac domain.AuditContext) (int, error) {
+	ret := _m.Called(actor, ac)
 
 	var r0 int
 	var r1 error
-	if rf, ok := ret.Get(0).(func(domain.Actor) (int, error)); ok {
-		return rf(actor)
+	if rf, ok := ret.Get(0).(func(domain.Actor, domain.AuditContext) (int, error)); ok {
+		return rf(actor, ac)
 	}
-	if rf, ok := ret.Get(0).(func(domain.Actor) int); ok {
-		r0 = rf(actor)
+	if rf, ok := ret.Get(0).(func(domain.Actor, domain.AuditContext) int); ok {
+		r0 = rf(actor, ac)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
 
-	if rf, ok := ret.Get(1).(func(domain.Actor) error); ok {
-		r1 = rf(actor)
+	if rf, ok := ret.Get(1).(func(domain.Actor, domain.AuditContext) error); ok {
+		r1 = rf(actor, ac)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DiffRevisions provides a mock function with given fields: actorID, from, to
+func (_m *ActorsUsecase) DiffRevisions(actorID int, from int, to int) (domain.RevisionDiff, error) {
+	ret := _m.Called(actorID, from, to)
+
+	var r0 domain.RevisionDiff
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, int, int) (domain.RevisionDiff, error)); ok {
+		return rf(actorID, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(int, int, int) domain.RevisionDiff); ok {
+		r0 = rf(actorID, from, to)
+	} else {
+		r0 = ret.Get(0).(domain.RevisionDiff)
+	}
+
+	if rf, ok := ret.Get(1).(func(int, int, int) error); ok {
+		r1 = rf(actorID, from, to)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -62,23 +88,23 @@ func (_m *ActorsUsecase) GetAll() ([]domain.Actor, error) {
 	return r0, r1
 }
 
-// Modify provides a mock function with given fields: actor
-func (_m *ActorsUsecase) Modify(actor domain.Actor) (domain.Actor, error) {
-	ret := _m.Called(actor)
+// GetById provides a mock function with given fields: id
+func (_m *ActorsUsecase) GetById(id int) (domain.Actor, error) {
+	ret := _m.Called(id)
 
 	var r0 domain.Actor
 	var r1 error
-	if rf, ok := ret.Get(0).(func(domain.Actor) (domain.Actor, error)); ok {
-		return rf(actor)
+	if rf, ok := ret.Get(0).(func(int) (domain.Actor, error)); ok {
+		return rf(id)
 	}
-	if rf, ok := ret.Get(0).(func(domain.Actor) domain.Actor); ok {
-		r0 = rf(actor)
+	if rf, ok := ret.Get(0).(func(int) domain.Actor); ok {
+		r0 = rf(id)
 	} else {
 		r0 = ret.Get(0).(domain.Actor)
 	}
 
-	if rf, ok := ret.Get(1).(func(domain.Actor) error); ok {
-		r1 = rf(actor)
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -86,13 +112,187 @@ func (_m *ActorsUsecase) Modify(actor domain.Actor) (domain.Actor, error) {
 	return r0, r1
 }
 
-// Remove provides a mock function with given fields: id
-func (_m *ActorsUsecase) Remove(id int) error {
-	ret := _m.Called(id)
+// GetDeleted provides a mock function with given fields:
+func (_m *ActorsUsecase) GetDeleted() ([]domain.Actor, error) {
+	ret := _m.Called()
+
+	var r0 []domain.Actor
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]domain.Actor, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []domain.Actor); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Actor)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDuplicates provides a mock function with given fields: threshold
+func (_m *ActorsUsecase) GetDuplicates(threshold float64) ([]domain.ActorDuplicate, error) {
+	ret := _m.Called(threshold)
+
+	var r0 []domain.ActorDuplicate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(float64) ([]domain.ActorDuplicate, error)); ok {
+		return rf(threshold)
+	}
+	if rf, ok := ret.Get(0).(func(float64) []domain.ActorDuplicate); ok {
+		r0 = rf(threshold)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.ActorDuplicate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(float64) error); ok {
+		r1 = rf(threshold)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRevisions provides a mock function with given fields: actorID
+func (_m *ActorsUsecase) GetRevisions(actorID int) ([]domain.ActorRevision, error) {
+	ret := _m.Called(actorID)
+
+	var r0 []domain.ActorRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) ([]domain.ActorRevision, error)); ok {
+		return rf(actorID)
+	}
+	if rf, ok := ret.Get(0).(func(int) []domain.ActorRevision); ok {
+		r0 = rf(actorID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.ActorRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(actorID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Merge provides a mock function with given fields: id, duplicateID, ac
+func (_m *ActorsUsecase) Merge(id int, duplicateID int, ac domain.AuditContext) (domain.Actor, error) {
+	ret := _m.Called(id, duplicateID, ac)
+
+	var r0 domain.Actor
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, int, domain.AuditContext) (domain.Actor, error)); ok {
+		return rf(id, duplicateID, ac)
+	}
+	if rf, ok := ret.Get(0).(func(int, int, domain.AuditContext) domain.Actor); ok {
+		r0 = rf(id, duplicateID, ac)
+	} else {
+		r0 = ret.Get(0).(domain.Actor)
+	}
+
+	if rf, ok := ret.Get(1).(func(int, int, domain.AuditContext) error); ok {
+		r1 = rf(id, duplicateID, ac)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Modify provides a mock function with given fields: actor, ac
+func (_m *ActorsUsecase) Modify(actor domain.Actor, ac domain.AuditContext) (domain.Actor, error) {
+	ret := _m.Called(actor, ac)
+
+	var r0 domain.Actor
+	var r1 error
+	if rf, ok := ret.Get(0).(func(domain.Actor, domain.AuditContext) (domain.Actor, error)); ok {
+		return rf(actor, ac)
+	}
+	if rf, ok := ret.Get(0).(func(domain.Actor, domain.AuditContext) domain.Actor); ok {
+		r0 = rf(actor, ac)
+	} else {
+		r0 = ret.Get(0).(domain.Actor)
+	}
+
+	if rf, ok := ret.Get(1).(func(domain.Actor, domain.AuditContext) error); ok {
+		r1 = rf(actor, ac)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Patch provides a mock function with given fields: id, patch, ac
+func (_m *ActorsUsecase) Patch(id int, patch domain.Patch, ac domain.AuditContext) (domain.Actor, error) {
+	ret := _m.Called(id, patch, ac)
+
+	var r0 domain.Actor
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, domain.Patch, domain.AuditContext) (domain.Actor, error)); ok {
+		return rf(id, patch, ac)
+	}
+	if rf, ok := ret.Get(0).(func(int, domain.Patch, domain.AuditContext) domain.Actor); ok {
+		r0 = rf(id, patch, ac)
+	} else {
+		r0 = ret.Get(0).(domain.Actor)
+	}
+
+	if rf, ok := ret.Get(1).(func(int, domain.Patch, domain.AuditContext) error); ok {
+		r1 = rf(id, patch, ac)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Purge provides a mock function with given fields: retention
+func (_m *ActorsUsecase) Purge(retention time.Duration) (int, error) {
+	ret := _m.Called(retention)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Duration) (int, error)); ok {
+		return rf(retention)
+	}
+	if rf, ok := ret.Get(0).(func(time.Duration) int); ok {
+		r0 = rf(retention)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Duration) error); ok {
+		r1 = rf(retention)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Remove provides a mock function with given fields: id, ac
+func (_m *ActorsUsecase) Remove(id int, ac domain.AuditContext) error {
+	ret := _m.Called(id, ac)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(int, domain.AuditContext) error); ok {
+		r0 = rf(id, ac)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -100,6 +300,54 @@ func (_m *ActorsUsecase) Remove(id int) error {
 	return r0
 }
 
+// Restore provides a mock function with given fields: id, ac
+func (_m *ActorsUsecase) Restore(id int, ac domain.AuditContext) (domain.Actor, error) {
+	ret := _m.Called(id, ac)
+
+	var r0 domain.Actor
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, domain.AuditContext) (domain.Actor, error)); ok {
+		return rf(id, ac)
+	}
+	if rf, ok := ret.Get(0).(func(int, domain.AuditContext) domain.Actor); ok {
+		r0 = rf(id, ac)
+	} else {
+		r0 = ret.Get(0).(domain.Actor)
+	}
+
+	if rf, ok := ret.Get(1).(func(int, domain.AuditContext) error); ok {
+		r1 = rf(id, ac)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Revert provides a mock function with given fields: actorID, revision, version, ac
+func (_m *ActorsUsecase) Revert(actorID int, revision int, version int, ac domain.AuditContext) (domain.Actor, error) {
+	ret := _m.Called(actorID, revision, version, ac)
+
+	var r0 domain.Actor
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, int, int, domain.AuditContext) (domain.Actor, error)); ok {
+		return rf(actorID, revision, version, ac)
+	}
+	if rf, ok := ret.Get(0).(func(int, int, int, domain.AuditContext) domain.Actor); ok {
+		r0 = rf(actorID, revision, version, ac)
+	} else {
+		r0 = ret.Get(0).(domain.Actor)
+	}
+
+	if rf, ok := ret.Get(1).(func(int, int, int, domain.AuditContext) error); ok {
+		r1 = rf(actorID, revision, version, ac)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewActorsUsecase creates a new instance of ActorsUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewActorsUsecase(t interface {
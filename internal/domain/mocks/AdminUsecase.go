@@ -0,0 +1,109 @@
+// Code generated by mockery v2.34.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "github.com/ellexo2456/FilmLib/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AdminUsecase is an autogenerated mock type for the AdminUsecase type
+type AdminUsecase struct {
+	mock.Mock
+}
+
+// GetUsers provides a mock function with given fields: filter
+func (_m *AdminUsecase) GetUsers(filter domain.UsersFilter) ([]domain.UserInfo, error) {
+	ret := _m.Called(filter)
+
+	var r0 []domain.UserInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(domain.UsersFilter) ([]domain.UserInfo, error)); ok {
+		return rf(filter)
+	}
+	if rf, ok := ret.Get(0).(func(domain.UsersFilter) []domain.UserInfo); ok {
+		r0 = rf(filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.UserInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(domain.UsersFilter) error); ok {
+		r1 = rf(filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Logout provides a mock function with given fields: userID
+func (_m *AdminUsecase) Logout(userID int) error {
+	ret := _m.Called(userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int) error); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetDisabled provides a mock function with given fields: adminID, userID, disabled
+func (_m *AdminUsecase) SetDisabled(adminID int, userID int, disabled bool) error {
+	ret := _m.Called(adminID, userID, disabled)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int, bool) error); ok {
+		r0 = rf(adminID, userID, disabled)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetRole provides a mock function with given fields: adminID, userID, role
+func (_m *AdminUsecase) SetRole(adminID int, userID int, role domain.Role) error {
+	ret := _m.Called(adminID, userID, role)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int, domain.Role) error); ok {
+		r0 = rf(adminID, userID, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetTwoFactorRequired provides a mock function with given fields: role, required
+func (_m *AdminUsecase) SetTwoFactorRequired(role domain.Role, required bool) error {
+	ret := _m.Called(role, required)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(domain.Role, bool) error); ok {
+		r0 = rf(role, required)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewAdminUsecase creates a new instance of AdminUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAdminUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AdminUsecase {
+	mock := &AdminUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
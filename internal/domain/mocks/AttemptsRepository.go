@@ -0,0 +1,91 @@
+// Code generated by mockery v2.34.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "github.com/ellexo2456/FilmLib/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// AttemptsRepository is an autogenerated mock type for the AttemptsRepository type
+type AttemptsRepository struct {
+	mock.Mock
+}
+
+// Fail provides a mock function with given fields: key, at, ttl
+func (_m *AttemptsRepository) Fail(key string, at time.Time, ttl time.Duration) (domain.Attempts, error) {
+	ret := _m.Called(key, at, ttl)
+
+	var r0 domain.Attempts
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, time.Time, time.Duration) (domain.Attempts, error)); ok {
+		return rf(key, at, ttl)
+	}
+	if rf, ok := ret.Get(0).(func(string, time.Time, time.Duration) domain.Attempts); ok {
+		r0 = rf(key, at, ttl)
+	} else {
+		r0 = ret.Get(0).(domain.Attempts)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, time.Time, time.Duration) error); ok {
+		r1 = rf(key, at, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Get provides a mock function with given fields: key
+func (_m *AttemptsRepository) Get(key string) (domain.Attempts, error) {
+	ret := _m.Called(key)
+
+	var r0 domain.Attempts
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (domain.Attempts, error)); ok {
+		return rf(key)
+	}
+	if rf, ok := ret.Get(0).(func(string) domain.Attempts); ok {
+		r0 = rf(key)
+	} else {
+		r0 = ret.Get(0).(domain.Attempts)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Reset provides a mock function with given fields: key
+func (_m *AttemptsRepository) Reset(key string) error {
+	ret := _m.Called(key)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewAttemptsRepository creates a new instance of AttemptsRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAttemptsRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AttemptsRepository {
+	mock := &AttemptsRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,58 @@
+// Code generated by mockery v2.34.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "github.com/ellexo2456/FilmLib/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AuditUsecase is an autogenerated mock type for the AuditUsecase type
+type AuditUsecase struct {
+	mock.Mock
+}
+
+// GetAll provides a mock function with given fields: filter
+func (_m *AuditUsecase) GetAll(filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+	ret := _m.Called(filter)
+
+	var r0 []domain.AuditEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(domain.AuditFilter) ([]domain.AuditEntry, error)); ok {
+		return rf(filter)
+	}
+	if rf, ok := ret.Get(0).(func(domain.AuditFilter) []domain.AuditEntry); ok {
+		r0 = rf(filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.AuditEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(domain.AuditFilter) error); ok {
+		r1 = rf(filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Record provides a mock function with given fields: ac, action, entity, entityID, before, after
+func (_m *AuditUsecase) Record(ac domain.AuditContext, action domain.AuditAction, entity domain.AuditEntity, entityID int, before interface{}, after interface{}) {
+	_m.Called(ac, action, entity, entityID, before, after)
+}
+
+// NewAuditUsecase creates a new instance of AuditUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuditUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AuditUsecase {
+	mock := &AuditUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
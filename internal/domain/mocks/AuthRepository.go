@@ -60,6 +60,58 @@ func (_m *AuthRepository) GetByEmail(email string) (domain.User, error) {
 	return r0, r1
 }
 
+// GetByID provides a mock function with given fields: id
+func (_m *AuthRepository) GetByID(id int) (domain.User, error) {
+	ret := _m.Called(id)
+
+	var r0 domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) (domain.User, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(int) domain.User); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetVerified provides a mock function with given fields: userID
+func (_m *AuthRepository) SetVerified(userID int) error {
+	ret := _m.Called(userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int) error); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePassword provides a mock function with given fields: userID, password
+func (_m *AuthRepository) UpdatePassword(userID int, password []byte) error {
+	ret := _m.Called(userID, password)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, []byte) error); ok {
+		r0 = rf(userID, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UserExists provides a mock function with given fields: email
 func (_m *AuthRepository) UserExists(email string) (bool, error) {
 	ret := _m.Called(email)
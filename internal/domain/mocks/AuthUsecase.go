@@ -12,30 +12,30 @@ type AuthUsecase struct {
 	mock.Mock
 }
 
-// Login provides a mock function with given fields: credentials
-func (_m *AuthUsecase) Login(credentials domain.Credentials) (domain.Session, int, error) {
-	ret := _m.Called(credentials)
+// Login provides a mock function with given fields: credentials, client
+func (_m *AuthUsecase) Login(credentials domain.Credentials, client domain.Client) (domain.Session, int, error) {
+	ret := _m.Called(credentials, client)
 
 	var r0 domain.Session
 	var r1 int
 	var r2 error
-	if rf, ok := ret.Get(0).(func(domain.Credentials) (domain.Session, int, error)); ok {
-		return rf(credentials)
+	if rf, ok := ret.Get(0).(func(domain.Credentials, domain.Client) (domain.Session, int, error)); ok {
+		return rf(credentials, client)
 	}
-	if rf, ok := ret.Get(0).(func(domain.Credentials) domain.Session); ok {
-		r0 = rf(credentials)
+	if rf, ok := ret.Get(0).(func(domain.Credentials, domain.Client) domain.Session); ok {
+		r0 = rf(credentials, client)
 	} else {
 		r0 = ret.Get(0).(domain.Session)
 	}
 
-	if rf, ok := ret.Get(1).(func(domain.Credentials) int); ok {
-		r1 = rf(credentials)
+	if rf, ok := ret.Get(1).(func(domain.Credentials, domain.Client) int); ok {
+		r1 = rf(credentials, client)
 	} else {
 		r1 = ret.Get(1).(int)
 	}
 
-	if rf, ok := ret.Get(2).(func(domain.Credentials) error); ok {
-		r2 = rf(credentials)
+	if rf, ok := ret.Get(2).(func(domain.Credentials, domain.Client) error); ok {
+		r2 = rf(credentials, client)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -43,13 +43,13 @@ func (_m *AuthUsecase) Login(credentials domain.Credentials) (domain.Session, in
 	return r0, r1, r2
 }
 
-// Logout provides a mock function with given fields: token
-func (_m *AuthUsecase) Logout(token string) error {
-	ret := _m.Called(token)
+// Logout provides a mock function with given fields: token, rememberToken
+func (_m *AuthUsecase) Logout(token string, rememberToken string) error {
+	ret := _m.Called(token, rememberToken)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(token)
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(token, rememberToken)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -81,6 +81,30 @@ func (_m *AuthUsecase) Register(user domain.User) (int, error) {
 	return r0, r1
 }
 
+// Resume provides a mock function with given fields: rememberToken, client
+func (_m *AuthUsecase) Resume(rememberToken string, client domain.Client) (domain.Session, error) {
+	ret := _m.Called(rememberToken, client)
+
+	var r0 domain.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, domain.Client) (domain.Session, error)); ok {
+		return rf(rememberToken, client)
+	}
+	if rf, ok := ret.Get(0).(func(string, domain.Client) domain.Session); ok {
+		r0 = rf(rememberToken, client)
+	} else {
+		r0 = ret.Get(0).(domain.Session)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, domain.Client) error); ok {
+		r1 = rf(rememberToken, client)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RetrieveSessionContext provides a mock function with given fields: token
 func (_m *AuthUsecase) RetrieveSessionContext(token string) (domain.SessionContext, error) {
 	ret := _m.Called(token)
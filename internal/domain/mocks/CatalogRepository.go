@@ -0,0 +1,67 @@
+// Code generated by mockery v2.34.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "github.com/ellexo2456/FilmLib/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CatalogRepository is an autogenerated mock type for the CatalogRepository type
+type CatalogRepository struct {
+	mock.Mock
+}
+
+// Export provides a mock function with given fields: filter, fn
+func (_m *CatalogRepository) Export(filter domain.ExportFilter, fn func(domain.ExportRecord) error) error {
+	ret := _m.Called(filter, fn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(domain.ExportFilter, func(domain.ExportRecord) error) error); ok {
+		r0 = rf(filter, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Import provides a mock function with given fields: records, commit, userID
+func (_m *CatalogRepository) Import(records []domain.ImportRecord, commit bool, userID int) ([]domain.ImportResult, error) {
+	ret := _m.Called(records, commit, userID)
+
+	var r0 []domain.ImportResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]domain.ImportRecord, bool, int) ([]domain.ImportResult, error)); ok {
+		return rf(records, commit, userID)
+	}
+	if rf, ok := ret.Get(0).(func([]domain.ImportRecord, bool, int) []domain.ImportResult); ok {
+		r0 = rf(records, commit, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.ImportResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]domain.ImportRecord, bool, int) error); ok {
+		r1 = rf(records, commit, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewCatalogRepository creates a new instance of CatalogRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCatalogRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CatalogRepository {
+	mock := &CatalogRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,68 @@
+// Code generated by mockery v2.34.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	io "io"
+
+	domain "github.com/ellexo2456/FilmLib/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CatalogUsecase is an autogenerated mock type for the CatalogUsecase type
+type CatalogUsecase struct {
+	mock.Mock
+}
+
+// Export provides a mock function with given fields: filter, format, w
+func (_m *CatalogUsecase) Export(filter domain.ExportFilter, format domain.CatalogFormat, w io.Writer) error {
+	ret := _m.Called(filter, format, w)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(domain.ExportFilter, domain.CatalogFormat, io.Writer) error); ok {
+		r0 = rf(filter, format, w)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Import provides a mock function with given fields: r, format, commit, ac
+func (_m *CatalogUsecase) Import(r io.Reader, format domain.CatalogFormat, commit bool, ac domain.AuditContext) (domain.ImportReport, error) {
+	ret := _m.Called(r, format, commit, ac)
+
+	var r0 domain.ImportReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(io.Reader, domain.CatalogFormat, bool, domain.AuditContext) (domain.ImportReport, error)); ok {
+		return rf(r, format, commit, ac)
+	}
+	if rf, ok := ret.Get(0).(func(io.Reader, domain.CatalogFormat, bool, domain.AuditContext) domain.ImportReport); ok {
+		r0 = rf(r, format, commit, ac)
+	} else {
+		r0 = ret.Get(0).(domain.ImportReport)
+	}
+
+	if rf, ok := ret.Get(1).(func(io.Reader, domain.CatalogFormat, bool, domain.AuditContext) error); ok {
+		r1 = rf(r, format, commit, ac)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewCatalogUsecase creates a new instance of CatalogUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCatalogUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CatalogUsecase {
+	mock := &CatalogUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
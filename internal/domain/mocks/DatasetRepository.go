@@ -0,0 +1,75 @@
+// Code generated by mockery v2.34.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "github.com/ellexo2456/FilmLib/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// DatasetRepository is an autogenerated mock type for the DatasetRepository type
+type DatasetRepository struct {
+	mock.Mock
+}
+
+// ImportBatch provides a mock function with given fields: batch
+func (_m *DatasetRepository) ImportBatch(batch domain.DatasetBatch) (int64, error) {
+	ret := _m.Called(batch)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(domain.DatasetBatch) (int64, error)); ok {
+		return rf(batch)
+	}
+	if rf, ok := ret.Get(0).(func(domain.DatasetBatch) int64); ok {
+		r0 = rf(batch)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(domain.DatasetBatch) error); ok {
+		r1 = rf(batch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Offset provides a mock function with given fields: kind, size
+func (_m *DatasetRepository) Offset(kind domain.DatasetKind, size int64) (int64, error) {
+	ret := _m.Called(kind, size)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(domain.DatasetKind, int64) (int64, error)); ok {
+		return rf(kind, size)
+	}
+	if rf, ok := ret.Get(0).(func(domain.DatasetKind, int64) int64); ok {
+		r0 = rf(kind, size)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(domain.DatasetKind, int64) error); ok {
+		r1 = rf(kind, size)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewDatasetRepository creates a new instance of DatasetRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDatasetRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DatasetRepository {
+	mock := &DatasetRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
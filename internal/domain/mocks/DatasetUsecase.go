@@ -0,0 +1,54 @@
+// Code generated by mockery v2.34.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	io "io"
+
+	domain "github.com/ellexo2456/FilmLib/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// DatasetUsecase is an autogenerated mock type for the DatasetUsecase type
+type DatasetUsecase struct {
+	mock.Mock
+}
+
+// Import provides a mock function with given fields: kind, r, size, restart, progress
+func (_m *DatasetUsecase) Import(kind domain.DatasetKind, r io.Reader, size int64, restart bool, progress func(domain.DatasetProgress)) (domain.DatasetProgress, error) {
+	ret := _m.Called(kind, r, size, restart, progress)
+
+	var r0 domain.DatasetProgress
+	var r1 error
+	if rf, ok := ret.Get(0).(func(domain.DatasetKind, io.Reader, int64, bool, func(domain.DatasetProgress)) (domain.DatasetProgress, error)); ok {
+		return rf(kind, r, size, restart, progress)
+	}
+	if rf, ok := ret.Get(0).(func(domain.DatasetKind, io.Reader, int64, bool, func(domain.DatasetProgress)) domain.DatasetProgress); ok {
+		r0 = rf(kind, r, size, restart, progress)
+	} else {
+		r0 = ret.Get(0).(domain.DatasetProgress)
+	}
+
+	if rf, ok := ret.Get(1).(func(domain.DatasetKind, io.Reader, int64, bool, func(domain.DatasetProgress)) error); ok {
+		r1 = rf(kind, r, size, restart, progress)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewDatasetUsecase creates a new instance of DatasetUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDatasetUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DatasetUsecase {
+	mock := &DatasetUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,32 @@
+// Code generated by mockery v2.34.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "github.com/ellexo2456/FilmLib/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EventEmitter is an autogenerated mock type for the EventEmitter type
+type EventEmitter struct {
+	mock.Mock
+}
+
+// Emit provides a mock function with given fields: event, data
+func (_m *EventEmitter) Emit(event domain.EventType, data interface{}) {
+	_m.Called(event, data)
+}
+
+// NewEventEmitter creates a new instance of EventEmitter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEventEmitter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *EventEmitter {
+	mock := &EventEmitter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -5,6 +5,8 @@ package mocks
 import (
 	domain "github.com/ellexo2456/FilmLib/internal/domain"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // FilmsRepository is an autogenerated mock type for the FilmsRepository type
@@ -26,23 +28,95 @@ func (_m *FilmsRepository) Delete(id int) error {
 	return r0
 }
 
-// Insert provides a mock function with given fields: film
-func (_m *FilmsRepository) Insert(film domain.Film) (int, error) {
-	ret := _m.Called(film)
+// Insert provides a mock function with given fields: film, userID
+func (_m *FilmsRepository) Insert(film domain.Film, userID int) (int, error) {
+	ret := _m.Called(film, userID)
 
 	var r0 int
 	var r1 error
-	if rf, ok := ret.Get(0).(func(domain.Film) (int, error)); ok {
-		return rf(film)
+	if rf, ok := ret.Get(0).(func(domain.Film, int) (int, error)); ok {
+		return rf(film, userID)
 	}
-	if rf, ok := ret.Get(0).(func(domain.Film) int); ok {
-		r0 = rf(film)
+	if rf, ok := ret.Get(0).(func(domain.Film, int) int); ok {
+		r0 = rf(film, userID)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
 
-	if rf, ok := ret.Get(1).(func(domain.Film) error); ok {
-		r1 = rf(film)
+	if rf, ok := ret.Get(1).(func(domain.Film, int) error); ok {
+		r1 = rf(film, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Purge provides a mock function with given fields: before
+func (_m *FilmsRepository) Purge(before time.Time) (int, error) {
+	ret := _m.Called(before)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time) (int, error)); ok {
+		return rf(before)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time) int); ok {
+		r0 = rf(before)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: id
+func (_m *FilmsRepository) Restore(id int) (domain.Film, error) {
+	ret := _m.Called(id)
+
+	var r0 domain.Film
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) (domain.Film, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(int) domain.Film); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(domain.Film)
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Revert provides a mock function with given fields: filmID, revision, version, userID
+func (_m *FilmsRepository) Revert(filmID int, revision int, version int, userID int) (domain.Film, error) {
+	ret := _m.Called(filmID, revision, version, userID)
+
+	var r0 domain.Film
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, int, int, int) (domain.Film, error)); ok {
+		return rf(filmID, revision, version, userID)
+	}
+	if rf, ok := ret.Get(0).(func(int, int, int, int) domain.Film); ok {
+		r0 = rf(filmID, revision, version, userID)
+	} else {
+		r0 = ret.Get(0).(domain.Film)
+	}
+
+	if rf, ok := ret.Get(1).(func(int, int, int, int) error); ok {
+		r1 = rf(filmID, revision, version, userID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -126,23 +200,99 @@ func (_m *FilmsRepository) SelectById(id int) (domain.Film, error) {
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: film
-func (_m *FilmsRepository) Update(film domain.Film) (domain.Film, error) {
-	ret := _m.Called(film)
+// SelectDeleted provides a mock function with given fields:
+func (_m *FilmsRepository) SelectDeleted() ([]domain.Film, error) {
+	ret := _m.Called()
+
+	var r0 []domain.Film
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]domain.Film, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []domain.Film); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Film)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SelectRevision provides a mock function with given fields: filmID, revision
+func (_m *FilmsRepository) SelectRevision(filmID int, revision int) (domain.FilmRevision, error) {
+	ret := _m.Called(filmID, revision)
+
+	var r0 domain.FilmRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, int) (domain.FilmRevision, error)); ok {
+		return rf(filmID, revision)
+	}
+	if rf, ok := ret.Get(0).(func(int, int) domain.FilmRevision); ok {
+		r0 = rf(filmID, revision)
+	} else {
+		r0 = ret.Get(0).(domain.FilmRevision)
+	}
+
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(filmID, revision)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SelectRevisions provides a mock function with given fields: filmID
+func (_m *FilmsRepository) SelectRevisions(filmID int) ([]domain.FilmRevision, error) {
+	ret := _m.Called(filmID)
+
+	var r0 []domain.FilmRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) ([]domain.FilmRevision, error)); ok {
+		return rf(filmID)
+	}
+	if rf, ok := ret.Get(0).(func(int) []domain.FilmRevision); ok {
+		r0 = rf(filmID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.FilmRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(filmID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: film, userID
+func (_m *FilmsRepository) Update(film domain.Film, userID int) (domain.Film, error) {
+	ret := _m.Called(film, userID)
 
 	var r0 domain.Film
 	var r1 error
-	if rf, ok := ret.Get(0).(func(domain.Film) (domain.Film, error)); ok {
-		return rf(film)
+	if rf, ok := ret.Get(0).(func(domain.Film, int) (domain.Film, error)); ok {
+		return rf(film, userID)
 	}
-	if rf, ok := ret.Get(0).(func(domain.Film) domain.Film); ok {
-		r0 = rf(film)
+	if rf, ok := ret.Get(0).(func(domain.Film, int) domain.Film); ok {
+		r0 = rf(film, userID)
 	} else {
 		r0 = ret.Get(0).(domain.Film)
 	}
 
-	if rf, ok := ret.Get(1).(func(domain.Film) error); ok {
-		r1 = rf(film)
+	if rf, ok := ret.Get(1).(func(domain.Film, int) error); ok {
+		r1 = rf(film, userID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -5,6 +5,8 @@ package mocks
 import (
 	domain "github.com/ellexo2456/FilmLib/internal/domain"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // FilmsUsecase is an autogenerated mock type for the FilmsUsecase type
@@ -12,23 +14,47 @@ type FilmsUsecase struct {
 	mock.Mock
 }
 
-// Add provides a mock function with given fields: film
-func (_m *FilmsUsecase) Add(film domain.Film) (int, error) {
-	ret := _m.Called(film)
+// Add provides a mock function with given fields: film, ac
+func (_m *FilmsUsecase) Add(film domain.Film, ac domain.AuditContext) (int, error) {
+	ret := _m.Called(film, ac)
 
 	var r0 int
 	var r1 error
-	if rf, ok := ret.Get(0).(func(domain.Film) (int, error)); ok {
-		return rf(film)
+	if rf, ok := ret.Get(0).(func(domain.Film, domain.AuditContext) (int, error)); ok {
+		return rf(film, ac)
 	}
-	if rf, ok := ret.Get(0).(func(domain.Film) int); ok {
-		r0 = rf(film)
+	if rf, ok := ret.Get(0).(func(domain.Film, domain.AuditContext) int); ok {
+		r0 = rf(film, ac)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
 
-	if rf, ok := ret.Get(1).(func(domain.Film) error); ok {
-		r1 = rf(film)
+	if rf, ok := ret.Get(1).(func(domain.Film, domain.AuditContext) error); ok {
+		r1 = rf(film, ac)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DiffRevisions provides a mock function with given fields: filmID, from, to
+func (_m *FilmsUsecase) DiffRevisions(filmID int, from int, to int) (domain.RevisionDiff, error) {
+	ret := _m.Called(filmID, from, to)
+
+	var r0 domain.RevisionDiff
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, int, int) (domain.RevisionDiff, error)); ok {
+		return rf(filmID, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(int, int, int) domain.RevisionDiff); ok {
+		r0 = rf(filmID, from, to)
+	} else {
+		r0 = ret.Get(0).(domain.RevisionDiff)
+	}
+
+	if rf, ok := ret.Get(1).(func(int, int, int) error); ok {
+		r1 = rf(filmID, from, to)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -62,23 +88,23 @@ func (_m *FilmsUsecase) GetAll(title domain.SortDirection, releaseDate domain.So
 	return r0, r1
 }
 
-// Modify provides a mock function with given fields: film
-func (_m *FilmsUsecase) Modify(film domain.Film) (domain.Film, error) {
-	ret := _m.Called(film)
+// GetById provides a mock function with given fields: id
+func (_m *FilmsUsecase) GetById(id int) (domain.Film, error) {
+	ret := _m.Called(id)
 
 	var r0 domain.Film
 	var r1 error
-	if rf, ok := ret.Get(0).(func(domain.Film) (domain.Film, error)); ok {
-		return rf(film)
+	if rf, ok := ret.Get(0).(func(int) (domain.Film, error)); ok {
+		return rf(id)
 	}
-	if rf, ok := ret.Get(0).(func(domain.Film) domain.Film); ok {
-		r0 = rf(film)
+	if rf, ok := ret.Get(0).(func(int) domain.Film); ok {
+		r0 = rf(id)
 	} else {
 		r0 = ret.Get(0).(domain.Film)
 	}
 
-	if rf, ok := ret.Get(1).(func(domain.Film) error); ok {
-		r1 = rf(film)
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -86,13 +112,137 @@ func (_m *FilmsUsecase) Modify(film domain.Film) (domain.Film, error) {
 	return r0, r1
 }
 
-// Remove provides a mock function with given fields: id
-func (_m *FilmsUsecase) Remove(id int) error {
-	ret := _m.Called(id)
+// GetDeleted provides a mock function with given fields:
+func (_m *FilmsUsecase) GetDeleted() ([]domain.Film, error) {
+	ret := _m.Called()
+
+	var r0 []domain.Film
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]domain.Film, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []domain.Film); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Film)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRevisions provides a mock function with given fields: filmID
+func (_m *FilmsUsecase) GetRevisions(filmID int) ([]domain.FilmRevision, error) {
+	ret := _m.Called(filmID)
+
+	var r0 []domain.FilmRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) ([]domain.FilmRevision, error)); ok {
+		return rf(filmID)
+	}
+	if rf, ok := ret.Get(0).(func(int) []domain.FilmRevision); ok {
+		r0 = rf(filmID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.FilmRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(filmID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Modify provides a mock function with given fields: film, ac
+func (_m *FilmsUsecase) Modify(film domain.Film, ac domain.AuditContext) (domain.Film, error) {
+	ret := _m.Called(film, ac)
+
+	var r0 domain.Film
+	var r1 error
+	if rf, ok := ret.Get(0).(func(domain.Film, domain.AuditContext) (domain.Film, error)); ok {
+		return rf(film, ac)
+	}
+	if rf, ok := ret.Get(0).(func(domain.Film, domain.AuditContext) domain.Film); ok {
+		r0 = rf(film, ac)
+	} else {
+		r0 = ret.Get(0).(domain.Film)
+	}
+
+	if rf, ok := ret.Get(1).(func(domain.Film, domain.AuditContext) error); ok {
+		r1 = rf(film, ac)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Patch provides a mock function with given fields: id, patch, ac
+func (_m *FilmsUsecase) Patch(id int, patch domain.Patch, ac domain.AuditContext) (domain.Film, error) {
+	ret := _m.Called(id, patch, ac)
+
+	var r0 domain.Film
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, domain.Patch, domain.AuditContext) (domain.Film, error)); ok {
+		return rf(id, patch, ac)
+	}
+	if rf, ok := ret.Get(0).(func(int, domain.Patch, domain.AuditContext) domain.Film); ok {
+		r0 = rf(id, patch, ac)
+	} else {
+		r0 = ret.Get(0).(domain.Film)
+	}
+
+	if rf, ok := ret.Get(1).(func(int, domain.Patch, domain.AuditContext) error); ok {
+		r1 = rf(id, patch, ac)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Purge provides a mock function with given fields: retention
+func (_m *FilmsUsecase) Purge(retention time.Duration) (int, error) {
+	ret := _m.Called(retention)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Duration) (int, error)); ok {
+		return rf(retention)
+	}
+	if rf, ok := ret.Get(0).(func(time.Duration) int); ok {
+		r0 = rf(retention)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Duration) error); ok {
+		r1 = rf(retention)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Remove provides a mock function with given fields: id, ac
+func (_m *FilmsUsecase) Remove(id int, ac domain.AuditContext) error {
+	ret := _m.Called(id, ac)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(int, domain.AuditContext) error); ok {
+		r0 = rf(id, ac)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -100,6 +250,54 @@ func (_m *FilmsUsecase) Remove(id int) error {
 	return r0
 }
 
+// Restore provides a mock function with given fields: id, ac
+func (_m *FilmsUsecase) Restore(id int, ac domain.AuditContext) (domain.Film, error) {
+	ret := _m.Called(id, ac)
+
+	var r0 domain.Film
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, domain.AuditContext) (domain.Film, error)); ok {
+		return rf(id, ac)
+	}
+	if rf, ok := ret.Get(0).(func(int, domain.AuditContext) domain.Film); ok {
+		r0 = rf(id, ac)
+	} else {
+		r0 = ret.Get(0).(domain.Film)
+	}
+
+	if rf, ok := ret.Get(1).(func(int, domain.AuditContext) error); ok {
+		r1 = rf(id, ac)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Revert provides a mock function with given fields: filmID, revision, version, ac
+func (_m *FilmsUsecase) Revert(filmID int, revision int, version int, ac domain.AuditContext) (domain.Film, error) {
+	ret := _m.Called(filmID, revision, version, ac)
+
+	var r0 domain.Film
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, int, int, domain.AuditContext) (domain.Film, error)); ok {
+		return rf(filmID, revision, version, ac)
+	}
+	if rf, ok := ret.Get(0).(func(int, int, int, domain.AuditContext) domain.Film); ok {
+		r0 = rf(filmID, revision, version, ac)
+	} else {
+		r0 = ret.Get(0).(domain.Film)
+	}
+
+	if rf, ok := ret.Get(1).(func(int, int, int, domain.AuditContext) error); ok {
+		r1 = rf(filmID, revision, version, ac)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Search provides a mock function with given fields: searchStr
 func (_m *FilmsUsecase) Search(searchStr string) ([]domain.Film, error) {
 	ret := _m.Called(searchStr)
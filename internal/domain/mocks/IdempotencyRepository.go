@@ -0,0 +1,102 @@
+// Code generated by mockery v2.34.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "github.com/ellexo2456/FilmLib/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// IdempotencyRepository is an autogenerated mock type for the IdempotencyRepository type
+type IdempotencyRepository struct {
+	mock.Mock
+}
+
+// Extend provides a mock function with given fields: key, ttl
+func (_m *IdempotencyRepository) Extend(key string, ttl time.Duration) error {
+	ret := _m.Called(key, ttl)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, time.Duration) error); ok {
+		r0 = rf(key, ttl)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Release provides a mock function with given fields: key
+func (_m *IdempotencyRepository) Release(key string) error {
+	ret := _m.Called(key)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Reserve provides a mock function with given fields: key, pending, ttl
+func (_m *IdempotencyRepository) Reserve(key string, pending domain.IdempotentResponse, ttl time.Duration) (domain.IdempotentResponse, bool, error) {
+	ret := _m.Called(key, pending, ttl)
+
+	var r0 domain.IdempotentResponse
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(string, domain.IdempotentResponse, time.Duration) (domain.IdempotentResponse, bool, error)); ok {
+		return rf(key, pending, ttl)
+	}
+	if rf, ok := ret.Get(0).(func(string, domain.IdempotentResponse, time.Duration) domain.IdempotentResponse); ok {
+		r0 = rf(key, pending, ttl)
+	} else {
+		r0 = ret.Get(0).(domain.IdempotentResponse)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, domain.IdempotentResponse, time.Duration) bool); ok {
+		r1 = rf(key, pending, ttl)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(string, domain.IdempotentResponse, time.Duration) error); ok {
+		r2 = rf(key, pending, ttl)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Save provides a mock function with given fields: key, response, ttl
+func (_m *IdempotencyRepository) Save(key string, response domain.IdempotentResponse, ttl time.Duration) error {
+	ret := _m.Called(key, response, ttl)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, domain.IdempotentResponse, time.Duration) error); ok {
+		r0 = rf(key, response, ttl)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewIdempotencyRepository creates a new instance of IdempotencyRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIdempotencyRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IdempotencyRepository {
+	mock := &IdempotencyRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
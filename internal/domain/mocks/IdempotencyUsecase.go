@@ -0,0 +1,81 @@
+// Code generated by mockery v2.34.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "github.com/ellexo2456/FilmLib/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// IdempotencyUsecase is an autogenerated mock type for the IdempotencyUsecase type
+type IdempotencyUsecase struct {
+	mock.Mock
+}
+
+// Begin provides a mock function with given fields: userID, key, hash
+func (_m *IdempotencyUsecase) Begin(userID int, key string, hash string) (*domain.IdempotentResponse, error) {
+	ret := _m.Called(userID, key, hash)
+
+	var r0 *domain.IdempotentResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, string, string) (*domain.IdempotentResponse, error)); ok {
+		return rf(userID, key, hash)
+	}
+	if rf, ok := ret.Get(0).(func(int, string, string) *domain.IdempotentResponse); ok {
+		r0 = rf(userID, key, hash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.IdempotentResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int, string, string) error); ok {
+		r1 = rf(userID, key, hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Extend provides a mock function with given fields: userID, key
+func (_m *IdempotencyUsecase) Extend(userID int, key string) error {
+	ret := _m.Called(userID, key)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, string) error); ok {
+		r0 = rf(userID, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Finish provides a mock function with given fields: userID, key, response
+func (_m *IdempotencyUsecase) Finish(userID int, key string, response domain.IdempotentResponse) error {
+	ret := _m.Called(userID, key, response)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, string, domain.IdempotentResponse) error); ok {
+		r0 = rf(userID, key, response)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewIdempotencyUsecase creates a new instance of IdempotencyUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIdempotencyUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IdempotencyUsecase {
+	mock := &IdempotencyUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,90 @@
+// Code generated by mockery v2.34.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// LoginGuard is an autogenerated mock type for the LoginGuard type
+type LoginGuard struct {
+	mock.Mock
+}
+
+// Check provides a mock function with given fields: email, ip
+func (_m *LoginGuard) Check(email string, ip string) (time.Duration, error) {
+	ret := _m.Called(email, ip)
+
+	var r0 time.Duration
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (time.Duration, error)); ok {
+		return rf(email, ip)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) time.Duration); ok {
+		r0 = rf(email, ip)
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(email, ip)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Fail provides a mock function with given fields: email, ip
+func (_m *LoginGuard) Fail(email string, ip string) (time.Duration, error) {
+	ret := _m.Called(email, ip)
+
+	var r0 time.Duration
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (time.Duration, error)); ok {
+		return rf(email, ip)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) time.Duration); ok {
+		r0 = rf(email, ip)
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(email, ip)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Succeed provides a mock function with given fields: email, ip
+func (_m *LoginGuard) Succeed(email string, ip string) error {
+	ret := _m.Called(email, ip)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(email, ip)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewLoginGuard creates a new instance of LoginGuard. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLoginGuard(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LoginGuard {
+	mock := &LoginGuard{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
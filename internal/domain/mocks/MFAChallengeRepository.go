@@ -0,0 +1,90 @@
+// Code generated by mockery v2.34.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MFAChallengeRepository is an autogenerated mock type for the MFAChallengeRepository type
+type MFAChallengeRepository struct {
+	mock.Mock
+}
+
+// Add provides a mock function with given fields: token, userID, ttl
+func (_m *MFAChallengeRepository) Add(token string, userID int, ttl time.Duration) error {
+	ret := _m.Called(token, userID, ttl)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, int, time.Duration) error); ok {
+		r0 = rf(token, userID, ttl)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields: token
+func (_m *MFAChallengeRepository) Get(token string) (int, error) {
+	ret := _m.Called(token)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (int, error)); ok {
+		return rf(token)
+	}
+	if rf, ok := ret.Get(0).(func(string) int); ok {
+		r0 = rf(token)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Pop provides a mock function with given fields: token
+func (_m *MFAChallengeRepository) Pop(token string) (int, error) {
+	ret := _m.Called(token)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (int, error)); ok {
+		return rf(token)
+	}
+	if rf, ok := ret.Get(0).(func(string) int); ok {
+		r0 = rf(token)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMFAChallengeRepository creates a new instance of MFAChallengeRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMFAChallengeRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MFAChallengeRepository {
+	mock := &MFAChallengeRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
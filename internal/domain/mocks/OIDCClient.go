@@ -0,0 +1,75 @@
+// Code generated by mockery v2.34.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "github.com/ellexo2456/FilmLib/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OIDCClient is an autogenerated mock type for the OIDCClient type
+type OIDCClient struct {
+	mock.Mock
+}
+
+// AuthCodeURL provides a mock function with given fields: state, nonce, codeChallenge
+func (_m *OIDCClient) AuthCodeURL(state string, nonce string, codeChallenge string) (string, error) {
+	ret := _m.Called(state, nonce, codeChallenge)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, string) (string, error)); ok {
+		return rf(state, nonce, codeChallenge)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, string) string); ok {
+		r0 = rf(state, nonce, codeChallenge)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
+		r1 = rf(state, nonce, codeChallenge)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Exchange provides a mock function with given fields: code, codeVerifier, nonce
+func (_m *OIDCClient) Exchange(code string, codeVerifier string, nonce string) (domain.Identity, error) {
+	ret := _m.Called(code, codeVerifier, nonce)
+
+	var r0 domain.Identity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, string) (domain.Identity, error)); ok {
+		return rf(code, codeVerifier, nonce)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, string) domain.Identity); ok {
+		r0 = rf(code, codeVerifier, nonce)
+	} else {
+		r0 = ret.Get(0).(domain.Identity)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
+		r1 = rf(code, codeVerifier, nonce)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewOIDCClient creates a new instance of OIDCClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOIDCClient(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OIDCClient {
+	mock := &OIDCClient{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,67 @@
+// Code generated by mockery v2.34.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "github.com/ellexo2456/FilmLib/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// OIDCStateRepository is an autogenerated mock type for the OIDCStateRepository type
+type OIDCStateRepository struct {
+	mock.Mock
+}
+
+// Add provides a mock function with given fields: state, s, ttl
+func (_m *OIDCStateRepository) Add(state string, s domain.OIDCState, ttl time.Duration) error {
+	ret := _m.Called(state, s, ttl)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, domain.OIDCState, time.Duration) error); ok {
+		r0 = rf(state, s, ttl)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Pop provides a mock function with given fields: state
+func (_m *OIDCStateRepository) Pop(state string) (domain.OIDCState, error) {
+	ret := _m.Called(state)
+
+	var r0 domain.OIDCState
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (domain.OIDCState, error)); ok {
+		return rf(state)
+	}
+	if rf, ok := ret.Get(0).(func(string) domain.OIDCState); ok {
+		r0 = rf(state)
+	} else {
+		r0 = ret.Get(0).(domain.OIDCState)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(state)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewOIDCStateRepository creates a new instance of OIDCStateRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOIDCStateRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OIDCStateRepository {
+	mock := &OIDCStateRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,82 @@
+// Code generated by mockery v2.34.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "github.com/ellexo2456/FilmLib/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OIDCUsecase is an autogenerated mock type for the OIDCUsecase type
+type OIDCUsecase struct {
+	mock.Mock
+}
+
+// Begin provides a mock function with given fields: provider
+func (_m *OIDCUsecase) Begin(provider string) (string, string, error) {
+	ret := _m.Called(provider)
+
+	var r0 string
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(string) (string, string, error)); ok {
+		return rf(provider)
+	}
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(provider)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) string); ok {
+		r1 = rf(provider)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(string) error); ok {
+		r2 = rf(provider)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Complete provides a mock function with given fields: provider, state, code, client
+func (_m *OIDCUsecase) Complete(provider string, state string, code string, client domain.Client) (domain.Session, error) {
+	ret := _m.Called(provider, state, code, client)
+
+	var r0 domain.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, string, domain.Client) (domain.Session, error)); ok {
+		return rf(provider, state, code, client)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, string, domain.Client) domain.Session); ok {
+		r0 = rf(provider, state, code, client)
+	} else {
+		r0 = ret.Get(0).(domain.Session)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, string, domain.Client) error); ok {
+		r1 = rf(provider, state, code, client)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewOIDCUsecase creates a new instance of OIDCUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOIDCUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OIDCUsecase {
+	mock := &OIDCUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
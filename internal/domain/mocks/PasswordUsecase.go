@@ -0,0 +1,55 @@
+// Code generated by mockery v2.34.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "github.com/ellexo2456/FilmLib/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PasswordUsecase is an autogenerated mock type for the PasswordUsecase type
+type PasswordUsecase struct {
+	mock.Mock
+}
+
+// RequestReset provides a mock function with given fields: email
+func (_m *PasswordUsecase) RequestReset(email string) error {
+	ret := _m.Called(email)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Reset provides a mock function with given fields: reset
+func (_m *PasswordUsecase) Reset(reset domain.PasswordReset) error {
+	ret := _m.Called(reset)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(domain.PasswordReset) error); ok {
+		r0 = rf(reset)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewPasswordUsecase creates a new instance of PasswordUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPasswordUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PasswordUsecase {
+	mock := &PasswordUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
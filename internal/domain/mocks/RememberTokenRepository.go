@@ -0,0 +1,95 @@
+// Code generated by mockery v2.34.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "github.com/ellexo2456/FilmLib/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// RememberTokenRepository is an autogenerated mock type for the RememberTokenRepository type
+type RememberTokenRepository struct {
+	mock.Mock
+}
+
+// Add provides a mock function with given fields: token, rt, ttl
+func (_m *RememberTokenRepository) Add(token string, rt domain.RememberToken, ttl time.Duration) error {
+	ret := _m.Called(token, rt, ttl)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, domain.RememberToken, time.Duration) error); ok {
+		r0 = rf(token, rt, ttl)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteByUserID provides a mock function with given fields: userID
+func (_m *RememberTokenRepository) DeleteByUserID(userID int) error {
+	ret := _m.Called(userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int) error); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteFamily provides a mock function with given fields: family
+func (_m *RememberTokenRepository) DeleteFamily(family string) error {
+	ret := _m.Called(family)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(family)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Use provides a mock function with given fields: token
+func (_m *RememberTokenRepository) Use(token string) (domain.RememberToken, error) {
+	ret := _m.Called(token)
+
+	var r0 domain.RememberToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (domain.RememberToken, error)); ok {
+		return rf(token)
+	}
+	if rf, ok := ret.Get(0).(func(string) domain.RememberToken); ok {
+		r0 = rf(token)
+	} else {
+		r0 = ret.Get(0).(domain.RememberToken)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewRememberTokenRepository creates a new instance of RememberTokenRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRememberTokenRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RememberTokenRepository {
+	mock := &RememberTokenRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,66 @@
+// Code generated by mockery v2.34.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ResetTokenRepository is an autogenerated mock type for the ResetTokenRepository type
+type ResetTokenRepository struct {
+	mock.Mock
+}
+
+// Add provides a mock function with given fields: token, userID, ttl
+func (_m *ResetTokenRepository) Add(token string, userID int, ttl time.Duration) error {
+	ret := _m.Called(token, userID, ttl)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, int, time.Duration) error); ok {
+		r0 = rf(token, userID, ttl)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Pop provides a mock function with given fields: token
+func (_m *ResetTokenRepository) Pop(token string) (int, error) {
+	ret := _m.Called(token)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (int, error)); ok {
+		return rf(token)
+	}
+	if rf, ok := ret.Get(0).(func(string) int); ok {
+		r0 = rf(token)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewResetTokenRepository creates a new instance of ResetTokenRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewResetTokenRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ResetTokenRepository {
+	mock := &ResetTokenRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
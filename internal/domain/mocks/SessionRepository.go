@@ -5,6 +5,8 @@ package mocks
 import (
 	domain "github.com/ellexo2456/FilmLib/internal/domain"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // SessionRepository is an autogenerated mock type for the SessionRepository type
@@ -26,6 +28,20 @@ func (_m *SessionRepository) Add(session domain.Session) error {
 	return r0
 }
 
+// DeleteByID provides a mock function with given fields: userID, id
+func (_m *SessionRepository) DeleteByID(userID int, id string) error {
+	ret := _m.Called(userID, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, string) error); ok {
+		r0 = rf(userID, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteByToken provides a mock function with given fields: token
 func (_m *SessionRepository) DeleteByToken(token string) error {
 	ret := _m.Called(token)
@@ -40,6 +56,46 @@ func (_m *SessionRepository) DeleteByToken(token string) error {
 	return r0
 }
 
+// DeleteByUserID provides a mock function with given fields: userID
+func (_m *SessionRepository) DeleteByUserID(userID int) error {
+	ret := _m.Called(userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int) error); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByUserID provides a mock function with given fields: userID
+func (_m *SessionRepository) GetByUserID(userID int) ([]domain.SessionInfo, error) {
+	ret := _m.Called(userID)
+
+	var r0 []domain.SessionInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) ([]domain.SessionInfo, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(int) []domain.SessionInfo); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.SessionInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetSessionContext provides a mock function with given fields: token
 func (_m *SessionRepository) GetSessionContext(token string) (domain.SessionContext, error) {
 	ret := _m.Called(token)
@@ -64,6 +120,30 @@ func (_m *SessionRepository) GetSessionContext(token string) (domain.SessionCont
 	return r0, r1
 }
 
+// Touch provides a mock function with given fields: token, at, idle
+func (_m *SessionRepository) Touch(token string, at time.Time, idle time.Duration) (time.Time, error) {
+	ret := _m.Called(token, at, idle)
+
+	var r0 time.Time
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, time.Time, time.Duration) (time.Time, error)); ok {
+		return rf(token, at, idle)
+	}
+	if rf, ok := ret.Get(0).(func(string, time.Time, time.Duration) time.Time); ok {
+		r0 = rf(token, at, idle)
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, time.Time, time.Duration) error); ok {
+		r1 = rf(token, at, idle)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewSessionRepository creates a new instance of SessionRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewSessionRepository(t interface {
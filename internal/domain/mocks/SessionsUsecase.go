@@ -0,0 +1,81 @@
+// Code generated by mockery v2.34.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "github.com/ellexo2456/FilmLib/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SessionsUsecase is an autogenerated mock type for the SessionsUsecase type
+type SessionsUsecase struct {
+	mock.Mock
+}
+
+// GetAll provides a mock function with given fields: userID, currentToken
+func (_m *SessionsUsecase) GetAll(userID int, currentToken string) ([]domain.SessionInfo, error) {
+	ret := _m.Called(userID, currentToken)
+
+	var r0 []domain.SessionInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, string) ([]domain.SessionInfo, error)); ok {
+		return rf(userID, currentToken)
+	}
+	if rf, ok := ret.Get(0).(func(int, string) []domain.SessionInfo); ok {
+		r0 = rf(userID, currentToken)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.SessionInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int, string) error); ok {
+		r1 = rf(userID, currentToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Revoke provides a mock function with given fields: userID, id
+func (_m *SessionsUsecase) Revoke(userID int, id string) error {
+	ret := _m.Called(userID, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, string) error); ok {
+		r0 = rf(userID, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeAll provides a mock function with given fields: userID
+func (_m *SessionsUsecase) RevokeAll(userID int) error {
+	ret := _m.Called(userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int) error); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewSessionsUsecase creates a new instance of SessionsUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSessionsUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SessionsUsecase {
+	mock := &SessionsUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
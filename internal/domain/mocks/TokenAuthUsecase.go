@@ -0,0 +1,113 @@
+// Code generated by mockery v2.34.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "github.com/ellexo2456/FilmLib/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TokenAuthUsecase is an autogenerated mock type for the TokenAuthUsecase type
+type TokenAuthUsecase struct {
+	mock.Mock
+}
+
+// Login provides a mock function with given fields: credentials
+func (_m *TokenAuthUsecase) Login(credentials domain.Credentials) (domain.TokenPair, error) {
+	ret := _m.Called(credentials)
+
+	var r0 domain.TokenPair
+	var r1 error
+	if rf, ok := ret.Get(0).(func(domain.Credentials) (domain.TokenPair, error)); ok {
+		return rf(credentials)
+	}
+	if rf, ok := ret.Get(0).(func(domain.Credentials) domain.TokenPair); ok {
+		r0 = rf(credentials)
+	} else {
+		r0 = ret.Get(0).(domain.TokenPair)
+	}
+
+	if rf, ok := ret.Get(1).(func(domain.Credentials) error); ok {
+		r1 = rf(credentials)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Refresh provides a mock function with given fields: refreshToken
+func (_m *TokenAuthUsecase) Refresh(refreshToken string) (domain.TokenPair, error) {
+	ret := _m.Called(refreshToken)
+
+	var r0 domain.TokenPair
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (domain.TokenPair, error)); ok {
+		return rf(refreshToken)
+	}
+	if rf, ok := ret.Get(0).(func(string) domain.TokenPair); ok {
+		r0 = rf(refreshToken)
+	} else {
+		r0 = ret.Get(0).(domain.TokenPair)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(refreshToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RetrieveSessionContext provides a mock function with given fields: accessToken
+func (_m *TokenAuthUsecase) RetrieveSessionContext(accessToken string) (domain.SessionContext, error) {
+	ret := _m.Called(accessToken)
+
+	var r0 domain.SessionContext
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (domain.SessionContext, error)); ok {
+		return rf(accessToken)
+	}
+	if rf, ok := ret.Get(0).(func(string) domain.SessionContext); ok {
+		r0 = rf(accessToken)
+	} else {
+		r0 = ret.Get(0).(domain.SessionContext)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(accessToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Revoke provides a mock function with given fields: refreshToken
+func (_m *TokenAuthUsecase) Revoke(refreshToken string) error {
+	ret := _m.Called(refreshToken)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(refreshToken)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewTokenAuthUsecase creates a new instance of TokenAuthUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTokenAuthUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TokenAuthUsecase {
+	mock := &TokenAuthUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,179 @@
+// Code generated by mockery v2.34.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "github.com/ellexo2456/FilmLib/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TwoFactorRepository is an autogenerated mock type for the TwoFactorRepository type
+type TwoFactorRepository struct {
+	mock.Mock
+}
+
+// Disable provides a mock function with given fields: userID
+func (_m *TwoFactorRepository) Disable(userID int) error {
+	ret := _m.Called(userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int) error); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Enable provides a mock function with given fields: userID, recoveryCodes
+func (_m *TwoFactorRepository) Enable(userID int, recoveryCodes [][]byte) error {
+	ret := _m.Called(userID, recoveryCodes)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, [][]byte) error); ok {
+		r0 = rf(userID, recoveryCodes)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields: userID
+func (_m *TwoFactorRepository) Get(userID int) (domain.TOTP, error) {
+	ret := _m.Called(userID)
+
+	var r0 domain.TOTP
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) (domain.TOTP, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(int) domain.TOTP); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Get(0).(domain.TOTP)
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsRequired provides a mock function with given fields: role
+func (_m *TwoFactorRepository) IsRequired(role domain.Role) (bool, error) {
+	ret := _m.Called(role)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(domain.Role) (bool, error)); ok {
+		return rf(role)
+	}
+	if rf, ok := ret.Get(0).(func(domain.Role) bool); ok {
+		r0 = rf(role)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(domain.Role) error); ok {
+		r1 = rf(role)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetRequired provides a mock function with given fields: role, required
+func (_m *TwoFactorRepository) SetRequired(role domain.Role, required bool) error {
+	ret := _m.Called(role, required)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(domain.Role, bool) error); ok {
+		r0 = rf(role, required)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetSecret provides a mock function with given fields: userID, secret
+func (_m *TwoFactorRepository) SetSecret(userID int, secret []byte) error {
+	ret := _m.Called(userID, secret)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, []byte) error); ok {
+		r0 = rf(userID, secret)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateLastStep provides a mock function with given fields: userID, step
+func (_m *TwoFactorRepository) UpdateLastStep(userID int, step int64) (bool, error) {
+	ret := _m.Called(userID, step)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, int64) (bool, error)); ok {
+		return rf(userID, step)
+	}
+	if rf, ok := ret.Get(0).(func(int, int64) bool); ok {
+		r0 = rf(userID, step)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(int, int64) error); ok {
+		r1 = rf(userID, step)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UseRecoveryCode provides a mock function with given fields: userID, hash
+func (_m *TwoFactorRepository) UseRecoveryCode(userID int, hash []byte) (bool, error) {
+	ret := _m.Called(userID, hash)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, []byte) (bool, error)); ok {
+		return rf(userID, hash)
+	}
+	if rf, ok := ret.Get(0).(func(int, []byte) bool); ok {
+		r0 = rf(userID, hash)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(int, []byte) error); ok {
+		r1 = rf(userID, hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewTwoFactorRepository creates a new instance of TwoFactorRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTwoFactorRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TwoFactorRepository {
+	mock := &TwoFactorRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
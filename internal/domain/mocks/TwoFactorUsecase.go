@@ -0,0 +1,220 @@
+// Code generated by mockery v2.34.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "github.com/ellexo2456/FilmLib/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TwoFactorUsecase is an autogenerated mock type for the TwoFactorUsecase type
+type TwoFactorUsecase struct {
+	mock.Mock
+}
+
+// Challenge provides a mock function with given fields: user
+func (_m *TwoFactorUsecase) Challenge(user domain.User) (domain.Session, error) {
+	ret := _m.Called(user)
+
+	var r0 domain.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(domain.User) (domain.Session, error)); ok {
+		return rf(user)
+	}
+	if rf, ok := ret.Get(0).(func(domain.User) domain.Session); ok {
+		r0 = rf(user)
+	} else {
+		r0 = ret.Get(0).(domain.Session)
+	}
+
+	if rf, ok := ret.Get(1).(func(domain.User) error); ok {
+		r1 = rf(user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ChallengeEmail provides a mock function with given fields: mfaToken
+func (_m *TwoFactorUsecase) ChallengeEmail(mfaToken string) (string, error) {
+	ret := _m.Called(mfaToken)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return rf(mfaToken)
+	}
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(mfaToken)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(mfaToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CompleteLogin provides a mock function with given fields: mfaToken, code, rememberMe, client
+func (_m *TwoFactorUsecase) CompleteLogin(mfaToken string, code string, rememberMe bool, client domain.Client) (domain.Session, []string, error) {
+	ret := _m.Called(mfaToken, code, rememberMe, client)
+
+	var r0 domain.Session
+	var r1 []string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(string, string, bool, domain.Client) (domain.Session, []string, error)); ok {
+		return rf(mfaToken, code, rememberMe, client)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, bool, domain.Client) domain.Session); ok {
+		r0 = rf(mfaToken, code, rememberMe, client)
+	} else {
+		r0 = ret.Get(0).(domain.Session)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, bool, domain.Client) []string); ok {
+		r1 = rf(mfaToken, code, rememberMe, client)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(string, string, bool, domain.Client) error); ok {
+		r2 = rf(mfaToken, code, rememberMe, client)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Disable provides a mock function with given fields: userID, role, code
+func (_m *TwoFactorUsecase) Disable(userID int, role domain.Role, code string) error {
+	ret := _m.Called(userID, role, code)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, domain.Role, string) error); ok {
+		r0 = rf(userID, role, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Enable provides a mock function with given fields: userID, code
+func (_m *TwoFactorUsecase) Enable(userID int, code string) ([]string, error) {
+	ret := _m.Called(userID, code)
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, string) ([]string, error)); ok {
+		return rf(userID, code)
+	}
+	if rf, ok := ret.Get(0).(func(int, string) []string); ok {
+		r0 = rf(userID, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int, string) error); ok {
+		r1 = rf(userID, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Required provides a mock function with given fields: user
+func (_m *TwoFactorUsecase) Required(user domain.User) (bool, error) {
+	ret := _m.Called(user)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(domain.User) (bool, error)); ok {
+		return rf(user)
+	}
+	if rf, ok := ret.Get(0).(func(domain.User) bool); ok {
+		r0 = rf(user)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(domain.User) error); ok {
+		r1 = rf(user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Setup provides a mock function with given fields: userID
+func (_m *TwoFactorUsecase) Setup(userID int) (domain.TOTPSetup, error) {
+	ret := _m.Called(userID)
+
+	var r0 domain.TOTPSetup
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) (domain.TOTPSetup, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(int) domain.TOTPSetup); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Get(0).(domain.TOTPSetup)
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetupByChallenge provides a mock function with given fields: mfaToken
+func (_m *TwoFactorUsecase) SetupByChallenge(mfaToken string) (domain.TOTPSetup, error) {
+	ret := _m.Called(mfaToken)
+
+	var r0 domain.TOTPSetup
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (domain.TOTPSetup, error)); ok {
+		return rf(mfaToken)
+	}
+	if rf, ok := ret.Get(0).(func(string) domain.TOTPSetup); ok {
+		r0 = rf(mfaToken)
+	} else {
+		r0 = ret.Get(0).(domain.TOTPSetup)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(mfaToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewTwoFactorUsecase creates a new instance of TwoFactorUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTwoFactorUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TwoFactorUsecase {
+	mock := &TwoFactorUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,81 @@
+// Code generated by mockery v2.34.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "github.com/ellexo2456/FilmLib/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// UsersRepository is an autogenerated mock type for the UsersRepository type
+type UsersRepository struct {
+	mock.Mock
+}
+
+// Select provides a mock function with given fields: filter
+func (_m *UsersRepository) Select(filter domain.UsersFilter) ([]domain.UserInfo, error) {
+	ret := _m.Called(filter)
+
+	var r0 []domain.UserInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(domain.UsersFilter) ([]domain.UserInfo, error)); ok {
+		return rf(filter)
+	}
+	if rf, ok := ret.Get(0).(func(domain.UsersFilter) []domain.UserInfo); ok {
+		r0 = rf(filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.UserInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(domain.UsersFilter) error); ok {
+		r1 = rf(filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateDisabled provides a mock function with given fields: userID, disabled
+func (_m *UsersRepository) UpdateDisabled(userID int, disabled bool) error {
+	ret := _m.Called(userID, disabled)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, bool) error); ok {
+		r0 = rf(userID, disabled)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateRole provides a mock function with given fields: userID, role
+func (_m *UsersRepository) UpdateRole(userID int, role domain.Role) error {
+	ret := _m.Called(userID, role)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, domain.Role) error); ok {
+		r0 = rf(userID, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewUsersRepository creates a new instance of UsersRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUsersRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UsersRepository {
+	mock := &UsersRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
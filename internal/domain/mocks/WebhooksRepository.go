@@ -0,0 +1,207 @@
+// Code generated by mockery v2.34.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "github.com/ellexo2456/FilmLib/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// WebhooksRepository is an autogenerated mock type for the WebhooksRepository type
+type WebhooksRepository struct {
+	mock.Mock
+}
+
+// ClaimDeliveries provides a mock function with given fields: limit, lease
+func (_m *WebhooksRepository) ClaimDeliveries(limit int, lease time.Duration) ([]domain.WebhookDelivery, error) {
+	ret := _m.Called(limit, lease)
+
+	var r0 []domain.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, time.Duration) ([]domain.WebhookDelivery, error)); ok {
+		return rf(limit, lease)
+	}
+	if rf, ok := ret.Get(0).(func(int, time.Duration) []domain.WebhookDelivery); ok {
+		r0 = rf(limit, lease)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.WebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int, time.Duration) error); ok {
+		r1 = rf(limit, lease)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: id
+func (_m *WebhooksRepository) Delete(id int) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Insert provides a mock function with given fields: webhook
+func (_m *WebhooksRepository) Insert(webhook domain.Webhook) (domain.Webhook, error) {
+	ret := _m.Called(webhook)
+
+	var r0 domain.Webhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(domain.Webhook) (domain.Webhook, error)); ok {
+		return rf(webhook)
+	}
+	if rf, ok := ret.Get(0).(func(domain.Webhook) domain.Webhook); ok {
+		r0 = rf(webhook)
+	} else {
+		r0 = ret.Get(0).(domain.Webhook)
+	}
+
+	if rf, ok := ret.Get(1).(func(domain.Webhook) error); ok {
+		r1 = rf(webhook)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InsertDeliveries provides a mock function with given fields: event, payload
+func (_m *WebhooksRepository) InsertDeliveries(event domain.EventType, payload []byte) (int, error) {
+	ret := _m.Called(event, payload)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(domain.EventType, []byte) (int, error)); ok {
+		return rf(event, payload)
+	}
+	if rf, ok := ret.Get(0).(func(domain.EventType, []byte) int); ok {
+		r0 = rf(event, payload)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(domain.EventType, []byte) error); ok {
+		r1 = rf(event, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Redeliver provides a mock function with given fields: deliveryID
+func (_m *WebhooksRepository) Redeliver(deliveryID int64) (domain.WebhookDelivery, error) {
+	ret := _m.Called(deliveryID)
+
+	var r0 domain.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (domain.WebhookDelivery, error)); ok {
+		return rf(deliveryID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) domain.WebhookDelivery); ok {
+		r0 = rf(deliveryID)
+	} else {
+		r0 = ret.Get(0).(domain.WebhookDelivery)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(deliveryID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SelectAll provides a mock function with given fields:
+func (_m *WebhooksRepository) SelectAll() ([]domain.Webhook, error) {
+	ret := _m.Called()
+
+	var r0 []domain.Webhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]domain.Webhook, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []domain.Webhook); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Webhook)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SelectDeliveries provides a mock function with given fields: webhookID, limit, offset
+func (_m *WebhooksRepository) SelectDeliveries(webhookID int, limit int, offset int) ([]domain.WebhookDelivery, error) {
+	ret := _m.Called(webhookID, limit, offset)
+
+	var r0 []domain.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, int, int) ([]domain.WebhookDelivery, error)); ok {
+		return rf(webhookID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(int, int, int) []domain.WebhookDelivery); ok {
+		r0 = rf(webhookID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.WebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int, int, int) error); ok {
+		r1 = rf(webhookID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateDelivery provides a mock function with given fields: delivery
+func (_m *WebhooksRepository) UpdateDelivery(delivery domain.WebhookDelivery) error {
+	ret := _m.Called(delivery)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(domain.WebhookDelivery) error); ok {
+		r0 = rf(delivery)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewWebhooksRepository creates a new instance of WebhooksRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWebhooksRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WebhooksRepository {
+	mock := &WebhooksRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,170 @@
+// Code generated by mockery v2.34.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "github.com/ellexo2456/FilmLib/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// WebhooksUsecase is an autogenerated mock type for the WebhooksUsecase type
+type WebhooksUsecase struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: webhook
+func (_m *WebhooksUsecase) Create(webhook domain.WebhookToAdd) (domain.CreatedWebhook, error) {
+	ret := _m.Called(webhook)
+
+	var r0 domain.CreatedWebhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(domain.WebhookToAdd) (domain.CreatedWebhook, error)); ok {
+		return rf(webhook)
+	}
+	if rf, ok := ret.Get(0).(func(domain.WebhookToAdd) domain.CreatedWebhook); ok {
+		r0 = rf(webhook)
+	} else {
+		r0 = ret.Get(0).(domain.CreatedWebhook)
+	}
+
+	if rf, ok := ret.Get(1).(func(domain.WebhookToAdd) error); ok {
+		r1 = rf(webhook)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Dispatch provides a mock function with given fields:
+func (_m *WebhooksUsecase) Dispatch() (int, error) {
+	ret := _m.Called()
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (int, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() int); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Emit provides a mock function with given fields: event, data
+func (_m *WebhooksUsecase) Emit(event domain.EventType, data interface{}) {
+	_m.Called(event, data)
+}
+
+// GetAll provides a mock function with given fields:
+func (_m *WebhooksUsecase) GetAll() ([]domain.Webhook, error) {
+	ret := _m.Called()
+
+	var r0 []domain.Webhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]domain.Webhook, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []domain.Webhook); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Webhook)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDeliveries provides a mock function with given fields: webhookID, limit, offset
+func (_m *WebhooksUsecase) GetDeliveries(webhookID int, limit int, offset int) ([]domain.WebhookDelivery, error) {
+	ret := _m.Called(webhookID, limit, offset)
+
+	var r0 []domain.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, int, int) ([]domain.WebhookDelivery, error)); ok {
+		return rf(webhookID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(int, int, int) []domain.WebhookDelivery); ok {
+		r0 = rf(webhookID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.WebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int, int, int) error); ok {
+		r1 = rf(webhookID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Redeliver provides a mock function with given fields: deliveryID
+func (_m *WebhooksUsecase) Redeliver(deliveryID int64) (domain.WebhookDelivery, error) {
+	ret := _m.Called(deliveryID)
+
+	var r0 domain.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (domain.WebhookDelivery, error)); ok {
+		return rf(deliveryID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) domain.WebhookDelivery); ok {
+		r0 = rf(deliveryID)
+	} else {
+		r0 = ret.Get(0).(domain.WebhookDelivery)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(deliveryID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Remove provides a mock function with given fields: id
+func (_m *WebhooksUsecase) Remove(id int) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewWebhooksUsecase creates a new instance of WebhooksUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWebhooksUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WebhooksUsecase {
+	mock := &WebhooksUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,50 @@
+package domain
+
+import "time"
+
+// OIDCProviderConfig describes one external identity provider. The
+// endpoints are discovered from the issuer.
+type OIDCProviderConfig struct {
+	Name         string   `json:"name"`
+	Issuer       string   `json:"issuer"`
+	ClientID     string   `json:"clientId"`
+	ClientSecret string   `json:"clientSecret"`
+	RedirectURL  string   `json:"redirectUrl"`
+	Scopes       []string `json:"scopes"`
+}
+
+// Identity is a user as seen by an external provider.
+type Identity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+}
+
+// OIDCState is kept between the redirect to the provider and the
+// callback, keyed by the state parameter.
+type OIDCState struct {
+	Provider string `json:"provider"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+}
+
+type OIDCClient interface {
+	AuthCodeURL(state, nonce, codeChallenge string) (string, error)
+	Exchange(code, codeVerifier, nonce string) (Identity, error)
+}
+
+type OIDCUsecase interface {
+	Begin(provider string) (url, state string, err error)
+	Complete(provider, state, code string, client Client) (Session, error)
+}
+
+type OIDCStateRepository interface {
+	Add(state string, s OIDCState, ttl time.Duration) error
+	Pop(state string) (OIDCState, error)
+}
+
+type IdentityRepository interface {
+	GetUserID(provider, subject string) (int, error)
+	Link(userID int, identity Identity) error
+}
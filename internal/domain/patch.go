@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/ellexo2456/FilmLib/internal/jsonpatch"
+)
+
+const AcceptPatchHeader = "Accept-Patch"
+
+type PatchType string
+
+const (
+	MergePatch PatchType = "application/merge-patch+json"
+	JSONPatch  PatchType = "application/json-patch+json"
+)
+
+func (t PatchType) Valid() bool {
+	return t == MergePatch || t == JSONPatch
+}
+
+// Patch is the body of a PATCH request. Version is the expected version
+// of the entity, zero matches any.
+type Patch struct {
+	Type    PatchType
+	Body    []byte
+	Version int
+}
+
+// ApplyTo patches the json fields of v. Only the editable fields can be
+// changed and none of them can be removed or set to null, a failed test
+// operation is reported as ErrPreconditionFailed.
+func (p Patch) ApplyTo(v interface{}, editable ...string) error {
+	doc, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	var patched []byte
+	switch p.Type {
+	case MergePatch:
+		patched, err = jsonpatch.Merge(doc, p.Body)
+	case JSONPatch:
+		patched, err = jsonpatch.Apply(doc, p.Body)
+	default:
+		return ErrBadRequest
+	}
+	if errors.Is(err, jsonpatch.ErrTestFailed) {
+		return ErrPreconditionFailed
+	}
+	if err != nil {
+		return ErrBadRequest
+	}
+
+	var before, after map[string]interface{}
+	if err = json.Unmarshal(doc, &before); err != nil {
+		return err
+	}
+	if err = json.Unmarshal(patched, &after); err != nil || after == nil {
+		return ErrBadRequest
+	}
+
+	isEditable := make(map[string]bool, len(editable))
+	for _, name := range editable {
+		if after[name] == nil {
+			return ErrBadRequest
+		}
+		isEditable[name] = true
+	}
+	for name, value := range after {
+		if !isEditable[name] && !reflect.DeepEqual(before[name], value) {
+			return ErrBadRequest
+		}
+	}
+	for name := range before {
+		if _, ok := after[name]; !ok {
+			return ErrBadRequest
+		}
+	}
+
+	if err = json.Unmarshal(patched, v); err != nil {
+		return ErrBadRequest
+	}
+
+	return nil
+}
+
+var minDate = time.Date(1800, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// validDate checks the date the same way as the database constraints.
+func validDate(d pgtype.Date) bool {
+	return d.Valid && !d.Time.Before(minDate) && !d.Time.After(time.Now())
+}
@@ -0,0 +1,81 @@
+package domain
+
+type Permission string
+
+const (
+	FilmsWrite     Permission = "films:write"
+	FilmsDelete    Permission = "films:delete"
+	ActorsWrite    Permission = "actors:write"
+	ActorsDelete   Permission = "actors:delete"
+	ActorsMerge    Permission = "actors:merge"
+	UsersManage    Permission = "users:manage"
+	AuditRead      Permission = "audit:read"
+	TrashManage    Permission = "trash:manage"
+	CatalogImport  Permission = "catalog:import"
+	CatalogExport  Permission = "catalog:export"
+	WebhooksManage Permission = "webhooks:manage"
+)
+
+var permissions = []Permission{
+	FilmsWrite,
+	FilmsDelete,
+	ActorsWrite,
+	ActorsDelete,
+	ActorsMerge,
+	UsersManage,
+	AuditRead,
+	TrashManage,
+	CatalogImport,
+	CatalogExport,
+	WebhooksManage,
+}
+
+var moderPermissions = []Permission{
+	FilmsWrite,
+	FilmsDelete,
+	ActorsWrite,
+	ActorsDelete,
+	ActorsMerge,
+	TrashManage,
+	CatalogImport,
+	CatalogExport,
+}
+
+var rolePermissions = map[Role][]Permission{
+	Usr:   {},
+	Moder: moderPermissions,
+	Admin: append([]Permission{UsersManage, AuditRead, WebhooksManage}, moderPermissions...),
+}
+
+func (r Role) Permissions() []Permission {
+	return rolePermissions[r]
+}
+
+func (r Role) Can(p Permission) bool {
+	return containsPermission(rolePermissions[r], p)
+}
+
+func (p Permission) Valid() bool {
+	return containsPermission(permissions, p)
+}
+
+func (sc SessionContext) Can(p Permission) bool {
+	if !sc.Role.Can(p) {
+		return false
+	}
+	if sc.TokenID == 0 {
+		return true
+	}
+
+	return containsPermission(sc.Scopes, p)
+}
+
+func containsPermission(ps []Permission, p Permission) bool {
+	for _, pp := range ps {
+		if pp == p {
+			return true
+		}
+	}
+
+	return false
+}
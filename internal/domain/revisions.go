@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+const (
+	RevisionFromParam = "from"
+	RevisionToParam   = "to"
+)
+
+// FilmRevision is a snapshot of the film with its cast taken after
+// every change. A zero UserID marks the state the film had before the
+// history was kept.
+type FilmRevision struct {
+	Revision  int       `json:"revision"`
+	UserID    int       `json:"userId"`
+	CreatedAt time.Time `json:"createdAt"`
+	Film      Film      `json:"film"`
+}
+
+type ActorRevision struct {
+	Revision  int       `json:"revision"`
+	UserID    int       `json:"userId"`
+	CreatedAt time.Time `json:"createdAt"`
+	Actor     Actor     `json:"actor"`
+}
+
+type RevisionDiff struct {
+	From    int                    `json:"from"`
+	To      int                    `json:"to"`
+	Changes map[string]FieldChange `json:"changes"`
+}
+
+// Diff compares the json fields of the values and keeps the changed
+// ones only. A nil value has no fields.
+func Diff(before, after interface{}) (map[string]FieldChange, error) {
+	beforeFields, err := jsonFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := jsonFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]FieldChange)
+	for name, b := range beforeFields {
+		if a, ok := afterFields[name]; !ok || !reflect.DeepEqual(a, b) {
+			changes[name] = FieldChange{Before: b, After: a}
+		}
+	}
+	for name, a := range afterFields {
+		if _, ok := beforeFields[name]; !ok {
+			changes[name] = FieldChange{After: a}
+		}
+	}
+
+	return changes, nil
+}
+
+func jsonFields(v interface{}) (map[string]interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]interface{}
+	if err = json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
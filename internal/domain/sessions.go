@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"time"
+)
+
+const (
+	maxUserAgentLength = 256
+	// LastSeenPrecision throttles the session writes: Touch is called
+	// on every request, more frequent updates aren`t worth it
+	LastSeenPrecision = time.Minute
+)
+
+// Client describes where a session was started from.
+type Client struct {
+	UserAgent string
+	IP        string
+}
+
+func RequestClient(r *http.Request) Client {
+	ua := r.UserAgent()
+	if len(ua) > maxUserAgentLength {
+		ua = ua[:maxUserAgentLength]
+	}
+
+	return Client{UserAgent: ua, IP: ClientIP(r)}
+}
+
+// SessionPolicy bounds the lifetime of a session: it expires after
+// IdleTimeout without requests and after AbsoluteTimeout in any case.
+// A remember-me token lives for RememberTTL since its last use.
+type SessionPolicy struct {
+	IdleTimeout     time.Duration
+	AbsoluteTimeout time.Duration
+	RememberTTL     time.Duration
+}
+
+// RememberToken is a link of a remember-me family. Every use replaces
+// the token with the next one of the same family, so a token presented
+// twice has been stolen and the whole family is revoked.
+type RememberToken struct {
+	UserID int
+	Family string
+}
+
+// SlideExpiry returns the expiry of a session used at the moment: idle
+// after it, but not past the deadline. Zero time means the expiry
+// doesn`t move. Sessions started before the expiry became sliding have
+// no deadline and keep their expiry.
+func SlideExpiry(expiresAt, deadline, at time.Time, idle time.Duration) time.Time {
+	if deadline.IsZero() {
+		deadline = expiresAt
+	}
+
+	next := at.Add(idle)
+	if next.After(deadline) {
+		next = deadline
+	}
+	if !next.After(expiresAt) {
+		return time.Time{}
+	}
+
+	return next
+}
+
+// SessionInfo is what a user sees about their sessions, the token
+// itself is never shown.
+type SessionInfo struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"userAgent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	Current    bool      `json:"current"`
+}
+
+// SessionID is the public id of a session. It can`t be turned back
+// into the token.
+func SessionID(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:8])
+}
+
+type SessionsUsecase interface {
+	GetAll(userID int, currentToken string) ([]SessionInfo, error)
+	Revoke(userID int, id string) error
+	RevokeAll(userID int) error
+}
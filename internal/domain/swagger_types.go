@@ -45,3 +45,73 @@ type FilmToAdd struct {
 	Rating      float64          `json:"rating"`
 	Actors      []ActorToFilmAdd `json:"actors"`
 }
+
+type FilmWithActors struct {
+	ID          int                 `json:"id"`
+	Title       string              `json:"title"`
+	Description string              `json:"description"`
+	ReleaseDate time.Time           `json:"releaseDate" format:"date"`
+	Rating      float64             `json:"rating"`
+	Actors      []ActorWithoutFilms `json:"actors"`
+}
+
+type FilmRevisionWithActors struct {
+	Revision  int            `json:"revision"`
+	UserID    int            `json:"userId"`
+	CreatedAt time.Time      `json:"createdAt"`
+	Film      FilmWithActors `json:"film"`
+}
+
+type ActorRevisionWithoutFilms struct {
+	Revision  int               `json:"revision"`
+	UserID    int               `json:"userId"`
+	CreatedAt time.Time         `json:"createdAt"`
+	Actor     ActorWithoutFilms `json:"actor"`
+}
+
+type DeletedFilm struct {
+	ID          int       `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	ReleaseDate time.Time `json:"releaseDate" format:"date"`
+	Rating      float64   `json:"rating"`
+	DeletedAt   time.Time `json:"deletedAt"`
+}
+
+type DeletedActor struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Sex       Sex       `json:"sex"`
+	Birthdate time.Time `json:"birthdate" format:"date"`
+	DeletedAt time.Time `json:"deletedAt"`
+}
+
+type ExportedFilm struct {
+	ID          int        `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	ReleaseDate time.Time  `json:"releaseDate" format:"date"`
+	Rating      float64    `json:"rating"`
+	DeletedAt   *time.Time `json:"deletedAt,omitempty"`
+}
+
+type ExportedActor struct {
+	ID        int        `json:"id"`
+	Name      string     `json:"name"`
+	Sex       Sex        `json:"sex"`
+	Birthdate time.Time  `json:"birthdate" format:"date"`
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+}
+
+type ExportedRecord struct {
+	Type      ImportRecordType `json:"type"`
+	Film      *ExportedFilm    `json:"film,omitempty"`
+	Actor     *ExportedActor   `json:"actor,omitempty"`
+	Credit    *Credit          `json:"credit,omitempty"`
+	UpdatedAt time.Time        `json:"updatedAt"`
+}
+
+type DuplicateActors struct {
+	Actors     [2]ActorWithoutFilms `json:"actors"`
+	Similarity float64              `json:"similarity"`
+}
@@ -0,0 +1,46 @@
+package domain
+
+import "time"
+
+// Clock is replaced with a fake one in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// ThrottlePolicy allows FreeAttempts failed logins, then every next attempt
+// has to wait BaseDelay doubled with each failure, up to MaxDelay. After
+// LockoutAfter failures the key is locked for LockoutDuration. Failures
+// are forgotten after Window without new ones.
+type ThrottlePolicy struct {
+	FreeAttempts    int
+	BaseDelay       time.Duration
+	MaxDelay        time.Duration
+	LockoutAfter    int
+	LockoutDuration time.Duration
+	Window          time.Duration
+}
+
+type Attempts struct {
+	Failures    int
+	LastFailure time.Time
+}
+
+// LoginGuard throttles logins per email and per ip. The returned
+// duration is the time to wait before the next attempt.
+type LoginGuard interface {
+	Check(email, ip string) (time.Duration, error)
+	Fail(email, ip string) (time.Duration, error)
+	Succeed(email, ip string) error
+}
+
+type AttemptsRepository interface {
+	Get(key string) (Attempts, error)
+	Fail(key string, at time.Time, ttl time.Duration) (Attempts, error)
+	Reset(key string) error
+}
@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+const APITokenPrefix = "flb_"
+
+type APIToken struct {
+	ID         int          `json:"id"`
+	Name       string       `json:"name"`
+	Scopes     []Permission `json:"scopes"`
+	ExpiresAt  *time.Time   `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time   `json:"lastUsedAt,omitempty"`
+	CreatedAt  time.Time    `json:"createdAt"`
+	UserID     int          `json:"-"`
+	Hash       []byte       `json:"-"`
+}
+
+type APITokenToAdd struct {
+	Name      string       `json:"name"`
+	Scopes    []Permission `json:"scopes"`
+	ExpiresAt *time.Time   `json:"expiresAt,omitempty"`
+}
+
+// CreatedAPIToken is the only place the plain token is ever shown.
+type CreatedAPIToken struct {
+	APIToken
+	Token string `json:"token"`
+}
+
+type APITokensUsecase interface {
+	Create(sc SessionContext, token APITokenToAdd) (CreatedAPIToken, error)
+	GetAll(userID int) ([]APIToken, error)
+	Revoke(userID, tokenID int) error
+	RetrieveSessionContext(token string) (SessionContext, error)
+}
+
+type APITokensRepository interface {
+	Insert(token APIToken) (APIToken, error)
+	SelectByUser(userID int) ([]APIToken, error)
+	Delete(userID, tokenID int) error
+	DeleteByUser(userID int) error
+	GetByHash(hash []byte) (APIToken, User, error)
+	UpdateLastUsed(tokenID int, at time.Time) error
+}
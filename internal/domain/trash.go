@@ -0,0 +1,9 @@
+package domain
+
+import "time"
+
+// TrashPurger permanently removes entities which are in the trash
+// longer than the retention.
+type TrashPurger interface {
+	Purge(retention time.Duration) (int, error)
+}
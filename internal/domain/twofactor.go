@@ -0,0 +1,64 @@
+package domain
+
+import "time"
+
+type TOTP struct {
+	Secret   []byte
+	Enabled  bool
+	LastStep int64
+}
+
+type TOTPSetup struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+type CodeRequest struct {
+	Code string `json:"code"`
+}
+
+// TwoFactorLogin is the second step of the login. Code is a TOTP code
+// or one of the recovery codes.
+type TwoFactorLogin struct {
+	MFAToken   string `json:"mfaToken"`
+	Code       string `json:"code"`
+	RememberMe bool   `json:"rememberMe"`
+}
+
+type MFATokenRequest struct {
+	MFAToken string `json:"mfaToken"`
+}
+
+type TwoFactorPolicy struct {
+	Required bool `json:"required"`
+}
+
+type TwoFactorUsecase interface {
+	Required(user User) (bool, error)
+	Challenge(user User) (Session, error)
+	Setup(userID int) (TOTPSetup, error)
+	Enable(userID int, code string) ([]string, error)
+	Disable(userID int, role Role, code string) error
+	SetupByChallenge(mfaToken string) (TOTPSetup, error)
+	// ChallengeEmail returns the email of the user the challenge is for,
+	// the wrong codes are counted against it like the wrong passwords.
+	ChallengeEmail(mfaToken string) (string, error)
+	CompleteLogin(mfaToken, code string, rememberMe bool, client Client) (Session, []string, error)
+}
+
+type TwoFactorRepository interface {
+	Get(userID int) (TOTP, error)
+	SetSecret(userID int, secret []byte) error
+	Enable(userID int, recoveryCodes [][]byte) error
+	Disable(userID int) error
+	UpdateLastStep(userID int, step int64) (bool, error)
+	UseRecoveryCode(userID int, hash []byte) (bool, error)
+	IsRequired(role Role) (bool, error)
+	SetRequired(role Role, required bool) error
+}
+
+type MFAChallengeRepository interface {
+	Add(token string, userID int, ttl time.Duration) error
+	Get(token string) (int, error)
+	Pop(token string) (int, error)
+}
@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	WebhookIDHeader        = "Webhook-Id"
+	WebhookEventHeader     = "Webhook-Event"
+	WebhookTimestampHeader = "Webhook-Timestamp"
+	// WebhookSignatureHeader holds "sha256=" and the hex HMAC of the
+	// timestamp, a dot and the body, keyed with the webhook secret.
+	WebhookSignatureHeader = "Webhook-Signature"
+)
+
+type EventType string
+
+const (
+	FilmCreated   EventType = "film.created"
+	FilmUpdated   EventType = "film.updated"
+	FilmDeleted   EventType = "film.deleted"
+	FilmRestored  EventType = "film.restored"
+	ActorCreated  EventType = "actor.created"
+	ActorUpdated  EventType = "actor.updated"
+	ActorDeleted  EventType = "actor.deleted"
+	ActorRestored EventType = "actor.restored"
+	ActorMerged   EventType = "actor.merged"
+)
+
+var eventTypes = []EventType{
+	FilmCreated,
+	FilmUpdated,
+	FilmDeleted,
+	FilmRestored,
+	ActorCreated,
+	ActorUpdated,
+	ActorDeleted,
+	ActorRestored,
+	ActorMerged,
+}
+
+func (t EventType) Valid() bool {
+	for _, tt := range eventTypes {
+		if tt == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WebhookEvent is the body of a delivery. The ID is kept on redeliveries,
+// so the receiver can drop the events it already has.
+type WebhookEvent struct {
+	ID        string      `json:"id"`
+	Event     EventType   `json:"event"`
+	CreatedAt time.Time   `json:"createdAt"`
+	Data      interface{} `json:"data"`
+}
+
+// MergedActor is the data of actor.merged, the duplicate is deleted and
+// its id is redirected to the actor.
+type MergedActor struct {
+	Actor       Actor `json:"actor"`
+	DuplicateID int   `json:"duplicateId"`
+}
+
+type Webhook struct {
+	ID        int         `json:"id"`
+	URL       string      `json:"url"`
+	Events    []EventType `json:"events"`
+	CreatedAt time.Time   `json:"createdAt"`
+	Secret    string      `json:"-"`
+}
+
+type WebhookToAdd struct {
+	URL    string      `json:"url"`
+	Events []EventType `json:"events"`
+}
+
+// CreatedWebhook is the only place the signing secret is ever shown.
+type CreatedWebhook struct {
+	Webhook
+	Secret string `json:"secret"`
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// WebhookDelivery is an event sent to a webhook. ResponseStatus and Error
+// are of the last attempt, a pending delivery is attempted again at
+// NextAttemptAt. URL and Secret are of the webhook, they are set only
+// for the deliveries being sent.
+type WebhookDelivery struct {
+	ID             int64           `json:"id"`
+	WebhookID      int             `json:"webhookId"`
+	Event          EventType       `json:"event"`
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"`
+	Status         DeliveryStatus  `json:"status"`
+	Attempts       int             `json:"attempts"`
+	ResponseStatus int             `json:"responseStatus,omitempty"`
+	Error          string          `json:"error,omitempty"`
+	NextAttemptAt  time.Time       `json:"nextAttemptAt"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	UpdatedAt      time.Time       `json:"updatedAt"`
+	URL            string          `json:"-"`
+	Secret         string          `json:"-"`
+}
+
+// EventEmitter is notified by the films and actors usecases of their
+// changes, data is the entity after the change or before the deletion.
+type EventEmitter interface {
+	Emit(event EventType, data interface{})
+}
+
+type WebhooksUsecase interface {
+	EventEmitter
+	Create(webhook WebhookToAdd) (CreatedWebhook, error)
+	GetAll() ([]Webhook, error)
+	Remove(id int) error
+	GetDeliveries(webhookID, limit, offset int) ([]WebhookDelivery, error)
+	// Redeliver queues a copy of the delivery, whatever its status.
+	Redeliver(deliveryID int64) (WebhookDelivery, error)
+	// Dispatch sends the deliveries which are due, a failed one is
+	// retried with an exponential backoff. It returns the count sent.
+	Dispatch() (int, error)
+}
+
+type WebhooksRepository interface {
+	Insert(webhook Webhook) (Webhook, error)
+	SelectAll() ([]Webhook, error)
+	Delete(id int) error
+	// InsertDeliveries queues the event for every webhook subscribed to it.
+	InsertDeliveries(event EventType, payload []byte) (int, error)
+	// ClaimDeliveries returns up to limit due deliveries, they aren`t due
+	// again until the lease is over, so other instances skip them.
+	ClaimDeliveries(limit int, lease time.Duration) ([]WebhookDelivery, error)
+	UpdateDelivery(delivery WebhookDelivery) error
+	SelectDeliveries(webhookID, limit, offset int) ([]WebhookDelivery, error)
+	Redeliver(deliveryID int64) (WebhookDelivery, error)
+}
@@ -2,9 +2,11 @@ package http
 
 import (
 	"encoding/json"
-	"errors"
 	"github.com/ellexo2456/FilmLib/internal/domain"
 	logs "github.com/ellexo2456/FilmLib/internal/logger"
+	"github.com/ellexo2456/FilmLib/internal/middleware"
+	"io"
+	"mime"
 	"net/http"
 	"strconv"
 )
@@ -18,39 +20,38 @@ func NewFilmsHandler(mux *http.ServeMux, fu domain.FilmsUsecase) {
 		FilmsUsecase: fu,
 	}
 
-	mux.HandleFunc("POST /films", handler.AddFilm)
+	mux.Handle("POST /films", middleware.Require(domain.FilmsWrite, handler.AddFilm))
 	mux.HandleFunc("GET /films", handler.GetFilms)
 	mux.HandleFunc("GET /films/search", handler.Search)
-	mux.HandleFunc("DELETE /films/{id}", handler.DeleteFilm)
-	mux.HandleFunc("PUT /films", handler.ModifyFilm)
+	mux.HandleFunc("GET /films/{id}", handler.GetFilm)
+	mux.Handle("DELETE /films/{id}", middleware.Require(domain.FilmsDelete, handler.DeleteFilm))
+	mux.Handle("PUT /films", middleware.Require(domain.FilmsWrite, handler.ModifyFilm))
+	mux.Handle("PATCH /films/{id}", middleware.Require(domain.FilmsWrite, handler.PatchFilm))
+	mux.Handle("GET /films/{id}/revisions", middleware.Require(domain.FilmsWrite, handler.GetFilmRevisions))
+	mux.Handle("GET /films/{id}/revisions/diff", middleware.Require(domain.FilmsWrite, handler.DiffFilmRevisions))
+	mux.Handle("POST /films/{id}/revisions/{revision}/revert", middleware.Require(domain.FilmsWrite, handler.RevertFilm))
+	mux.Handle("GET /trash/films", middleware.Require(domain.TrashManage, handler.GetDeletedFilms))
+	mux.Handle("POST /trash/films/{id}/restore", middleware.Require(domain.TrashManage, handler.RestoreFilm))
 
 }
 
 // AddFilm godoc
 //
 //	@Summary		Adds a new film.
-//	@Description	Adds a new film with provided data.
+//	@Description	Adds a new film with provided data. A retry with the same Idempotency-Key gets the first response.
 //	@Tags			Films
-//	@Param			body	body	domain.FilmToAdd	true	"film to add"
+//	@Param			Idempotency-Key	header	string				false	"Key of the request, unique per user"
+//	@Param			body			body	domain.FilmToAdd	true	"film to add"
 //	@Produce		json
 //	@Success		200	{object}	object{body=object{id=int}}
 //	@Failure		400	{object}	object{err=string}
+//	@Failure		403	{object}	object{err=string}
 //	@Failure		404	{object}	object{err=string}
+//	@Failure		409	{object}	object{err=string}
+//	@Failure		422	{object}	object{err=string}
 //	@Failure		500	{object}	object{err=string}
 //	@Router			/api/v1/films [post]
 func (h *FilmsHandler) AddFilm(w http.ResponseWriter, r *http.Request) {
-	sc, ok := r.Context().Value(domain.SessionContextKey).(domain.SessionContext)
-	if !ok {
-		domain.WriteError(w, "can`t find user", http.StatusInternalServerError)
-		logs.LogError(logs.Logger, "films/http", "AddFilm", errors.New("can`t find user"), "can`t find user")
-	}
-	logs.Logger.Debug("AddFilm session context\n: ", sc)
-
-	if sc.Role != domain.Moder {
-		domain.WriteError(w, "forbidden", http.StatusForbidden)
-		logs.LogError(logs.Logger, "films/http", "AddFilm", errors.New("forbidden"), "invalid role")
-	}
-
 	var film domain.Film
 	err := json.NewDecoder(r.Body).Decode(&film)
 	if err != nil {
@@ -61,7 +62,7 @@ func (h *FilmsHandler) AddFilm(w http.ResponseWriter, r *http.Request) {
 	logs.Logger.Debug("AddFilm film:\n", film)
 	defer domain.CloseAndAlert(r.Body, "films/http", "AddFilm")
 
-	id, err := h.FilmsUsecase.Add(film)
+	id, err := h.FilmsUsecase.Add(film, domain.RequestAuditContext(r))
 	if err != nil {
 		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
 		logs.LogError(logs.Logger, "films/http", "AddFilm", err, err.Error())
@@ -148,7 +149,7 @@ func (h *FilmsHandler) Search(w http.ResponseWriter, r *http.Request) {
 // DeleteFilm godoc
 //
 //	@Summary		Deletes a film.
-//	@Description	Deletes a film by id with all its relations with actors.
+//	@Description	Moves a film to the trash by id, its relations with actors are kept for a restore.
 //	@Tags			Films
 //	@Param			id	path	int	true	"Film id"
 //	@Produce		json
@@ -159,18 +160,6 @@ func (h *FilmsHandler) Search(w http.ResponseWriter, r *http.Request) {
 //	@Failure		500	{object}	object{err=string}
 //	@Router			/api/v1/films/{id} [delete]
 func (h *FilmsHandler) DeleteFilm(w http.ResponseWriter, r *http.Request) {
-	sc, ok := r.Context().Value(domain.SessionContextKey).(domain.SessionContext)
-	if !ok {
-		domain.WriteError(w, "can`t find user", http.StatusInternalServerError)
-		logs.LogError(logs.Logger, "films/http", "DeleteFilm", errors.New("can`t find user"), "can`t find user")
-	}
-	logs.Logger.Debug("DeleteFilm session context\n: ", sc)
-
-	if sc.Role != domain.Moder {
-		domain.WriteError(w, "forbidden", http.StatusForbidden)
-		logs.LogError(logs.Logger, "films/http", "DeleteFilm", errors.New("forbidden"), "invalid role")
-	}
-
 	id, err := strconv.Atoi(r.PathValue("id"))
 	if err != nil {
 		domain.WriteError(w, err.Error(), http.StatusBadRequest)
@@ -179,7 +168,7 @@ func (h *FilmsHandler) DeleteFilm(w http.ResponseWriter, r *http.Request) {
 	}
 	logs.Logger.Debug("DeleteFilm id:\n", id)
 
-	err = h.FilmsUsecase.Remove(id)
+	err = h.FilmsUsecase.Remove(id, domain.RequestAuditContext(r))
 	if err != nil {
 		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
 		logs.LogError(logs.Logger, "films/http", "DeleteFilm", err, err.Error())
@@ -189,31 +178,65 @@ func (h *FilmsHandler) DeleteFilm(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// GetFilm godoc
+//
+//	@Summary		Gets a film.
+//	@Description	Gets a film by id. The ETag header holds its version, a matching If-None-Match gets 304.
+//	@Tags			Films
+//	@Param			id				path	int		true	"Film id"
+//	@Param			If-None-Match	header	string	false	"ETag of the cached film"
+//	@Produce		json
+//	@Success		200	{object}	object{body=object{film=domain.FilmWithoutActors}}
+//	@Success		304
+//	@Failure		400	{object}	object{err=string}
+//	@Failure		404	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/films/{id} [get]
+func (h *FilmsHandler) GetFilm(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "films/http", "GetFilm", err, err.Error())
+		return
+	}
+
+	film, err := h.FilmsUsecase.GetById(id)
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "films/http", "GetFilm", err, err.Error())
+		return
+	}
+
+	domain.SetETag(w, film.Version)
+	if domain.NotModified(r, film.Version) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	domain.WriteResponse(
+		w,
+		map[string]interface{}{
+			"film": film,
+		},
+		http.StatusOK,
+	)
+}
+
 // ModifyFilm godoc
 //
 //	@Summary		Modify a film.
 //	@Description	Modify a film by id and retrieves a new film.
 //	@Tags			Films
+//	@Param			If-Match	header	string	false	"ETag of the film version being modified"
 //	@Param			body	body	domain.FilmWithoutActors	true	"Film to modify"
 //	@Produce		json
 //	@Success		200	{object}	object{body=object{film=domain.FilmWithoutActors}}
 //	@Failure		400	{object}	object{err=string}
 //	@Failure		403	{object}	object{err=string}
+//	@Failure		412	{object}	object{err=string}
 //	@Failure		500	{object}	object{err=string}
 //	@Router			/api/v1/films [put]
 func (h *FilmsHandler) ModifyFilm(w http.ResponseWriter, r *http.Request) {
-	sc, ok := r.Context().Value(domain.SessionContextKey).(domain.SessionContext)
-	if !ok {
-		domain.WriteError(w, "can`t find user", http.StatusInternalServerError)
-		logs.LogError(logs.Logger, "films/http", "ModifyFilm", errors.New("can`t find user"), "can`t find user")
-	}
-	logs.Logger.Debug("DeleteFilm session context\n: ", sc)
-
-	if sc.Role != domain.Moder {
-		domain.WriteError(w, "forbidden", http.StatusForbidden)
-		logs.LogError(logs.Logger, "films/http", "ModifyFilm", errors.New("forbidden"), "invalid role")
-	}
-
 	var film domain.Film
 	err := json.NewDecoder(r.Body).Decode(&film)
 	if err != nil {
@@ -224,7 +247,14 @@ func (h *FilmsHandler) ModifyFilm(w http.ResponseWriter, r *http.Request) {
 	logs.Logger.Debug("ModifyFilm new film:\n", film)
 	defer domain.CloseAndAlert(r.Body, "films/http", "ModifyFilm")
 
-	film, err = h.FilmsUsecase.Modify(film)
+	film.Version, err = domain.IfMatchVersion(r)
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "films/http", "ModifyFilm", err, err.Error())
+		return
+	}
+
+	film, err = h.FilmsUsecase.Modify(film, domain.RequestAuditContext(r))
 	if err != nil {
 		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
 		logs.LogError(logs.Logger, "films/http", "ModifyFilm", err, err.Error())
@@ -232,6 +262,283 @@ func (h *FilmsHandler) ModifyFilm(w http.ResponseWriter, r *http.Request) {
 	}
 	logs.Logger.Debug("ModifyFilm updated film:\n", film)
 
+	domain.SetETag(w, film.Version)
+	domain.WriteResponse(
+		w,
+		map[string]interface{}{
+			"film": film,
+		},
+		http.StatusOK,
+	)
+}
+
+// PatchFilm godoc
+//
+//	@Summary		Patches a film.
+//	@Description	Changes only the fields present in a JSON Merge Patch (RFC 7396) or the fields touched by a JSON Patch (RFC 6902). Fields can`t be removed.
+//	@Tags			Films
+//	@Param			id				path	int		true	"Film id"
+//	@Param			If-Match		header	string	false	"ETag of the film version being patched"
+//	@Param			Content-Type	header	string	true	"application/merge-patch+json or application/json-patch+json"
+//	@Param			body			body	object	true	"Patch"
+//	@Produce		json
+//	@Success		200	{object}	object{body=object{film=domain.FilmWithoutActors}}
+//	@Failure		400	{object}	object{err=string}
+//	@Failure		403	{object}	object{err=string}
+//	@Failure		404	{object}	object{err=string}
+//	@Failure		412	{object}	object{err=string}
+//	@Failure		415	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/films/{id} [patch]
+func (h *FilmsHandler) PatchFilm(w http.ResponseWriter, r *http.Request) {
+	defer domain.CloseAndAlert(r.Body, "films/http", "PatchFilm")
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "films/http", "PatchFilm", err, err.Error())
+		return
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	patch := domain.Patch{Type: domain.PatchType(mediaType)}
+	if !patch.Type.Valid() {
+		w.Header().Set(domain.AcceptPatchHeader, string(domain.MergePatch)+", "+string(domain.JSONPatch))
+		domain.WriteError(w, "unsupported patch type "+mediaType, http.StatusUnsupportedMediaType)
+		return
+	}
+
+	patch.Version, err = domain.IfMatchVersion(r)
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "films/http", "PatchFilm", err, err.Error())
+		return
+	}
+
+	patch.Body, err = io.ReadAll(r.Body)
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "films/http", "PatchFilm", err, err.Error())
+		return
+	}
+
+	film, err := h.FilmsUsecase.Patch(id, patch, domain.RequestAuditContext(r))
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "films/http", "PatchFilm", err, err.Error())
+		return
+	}
+
+	domain.SetETag(w, film.Version)
+	domain.WriteResponse(
+		w,
+		map[string]interface{}{
+			"film": film,
+		},
+		http.StatusOK,
+	)
+}
+
+// GetFilmRevisions godoc
+//
+//	@Summary		Gets film revisions.
+//	@Description	Gets all saved revisions of the film, newest first.
+//	@Tags			Films
+//	@Param			id	path	int	true	"Film id"
+//	@Produce		json
+//	@Success		200	{object}	object{body=object{revisions=[]domain.FilmRevisionWithActors}}
+//	@Failure		400	{object}	object{err=string}
+//	@Failure		403	{object}	object{err=string}
+//	@Failure		404	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/films/{id}/revisions [get]
+func (h *FilmsHandler) GetFilmRevisions(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "films/http", "GetFilmRevisions", err, err.Error())
+		return
+	}
+
+	revisions, err := h.FilmsUsecase.GetRevisions(id)
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "films/http", "GetFilmRevisions", err, err.Error())
+		return
+	}
+
+	domain.WriteResponse(
+		w,
+		map[string]interface{}{
+			"revisions": revisions,
+		},
+		http.StatusOK,
+	)
+}
+
+// DiffFilmRevisions godoc
+//
+//	@Summary		Compares two film revisions.
+//	@Description	Gets the fields changed between two revisions of the film.
+//	@Tags			Films
+//	@Param			id		path	int	true	"Film id"
+//	@Param			from	query	int	true	"Revision to compare from"
+//	@Param			to		query	int	true	"Revision to compare to"
+//	@Produce		json
+//	@Success		200	{object}	object{body=object{diff=domain.RevisionDiff}}
+//	@Failure		400	{object}	object{err=string}
+//	@Failure		403	{object}	object{err=string}
+//	@Failure		404	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/films/{id}/revisions/diff [get]
+func (h *FilmsHandler) DiffFilmRevisions(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "films/http", "DiffFilmRevisions", err, err.Error())
+		return
+	}
+
+	queryParams := r.URL.Query()
+	from, err := strconv.Atoi(queryParams.Get(domain.RevisionFromParam))
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "films/http", "DiffFilmRevisions", err, err.Error())
+		return
+	}
+	to, err := strconv.Atoi(queryParams.Get(domain.RevisionToParam))
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "films/http", "DiffFilmRevisions", err, err.Error())
+		return
+	}
+
+	diff, err := h.FilmsUsecase.DiffRevisions(id, from, to)
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "films/http", "DiffFilmRevisions", err, err.Error())
+		return
+	}
+
+	domain.WriteResponse(
+		w,
+		map[string]interface{}{
+			"diff": diff,
+		},
+		http.StatusOK,
+	)
+}
+
+// RevertFilm godoc
+//
+//	@Summary		Reverts a film.
+//	@Description	Restores the film from the revision. The result is saved as a new revision.
+//	@Tags			Films
+//	@Param			id			path	int		true	"Film id"
+//	@Param			revision	path	int		true	"Revision to restore"
+//	@Param			If-Match	header	string	false	"ETag of the film version being reverted"
+//	@Produce		json
+//	@Success		200	{object}	object{body=object{film=domain.FilmWithActors}}
+//	@Failure		400	{object}	object{err=string}
+//	@Failure		403	{object}	object{err=string}
+//	@Failure		404	{object}	object{err=string}
+//	@Failure		412	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/films/{id}/revisions/{revision}/revert [post]
+func (h *FilmsHandler) RevertFilm(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "films/http", "RevertFilm", err, err.Error())
+		return
+	}
+	revision, err := strconv.Atoi(r.PathValue("revision"))
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "films/http", "RevertFilm", err, err.Error())
+		return
+	}
+
+	version, err := domain.IfMatchVersion(r)
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "films/http", "RevertFilm", err, err.Error())
+		return
+	}
+
+	film, err := h.FilmsUsecase.Revert(id, revision, version, domain.RequestAuditContext(r))
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "films/http", "RevertFilm", err, err.Error())
+		return
+	}
+
+	domain.SetETag(w, film.Version)
+
+	domain.WriteResponse(
+		w,
+		map[string]interface{}{
+			"film": film,
+		},
+		http.StatusOK,
+	)
+}
+
+// GetDeletedFilms godoc
+//
+//	@Summary		Gets films in the trash.
+//	@Description	Gets deleted films, recently deleted first. They are purged after the retention period.
+//	@Tags			Films
+//	@Produce		json
+//	@Success		200	{object}	object{body=object{films=[]domain.DeletedFilm}}
+//	@Failure		403	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/trash/films [get]
+func (h *FilmsHandler) GetDeletedFilms(w http.ResponseWriter, r *http.Request) {
+	films, err := h.FilmsUsecase.GetDeleted()
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "films/http", "GetDeletedFilms", err, err.Error())
+		return
+	}
+
+	domain.WriteResponse(
+		w,
+		map[string]interface{}{
+			"films": films,
+		},
+		http.StatusOK,
+	)
+}
+
+// RestoreFilm godoc
+//
+//	@Summary		Restores a film.
+//	@Description	Restores a deleted film from the trash with its links.
+//	@Tags			Films
+//	@Param			id	path	int	true	"Film id"
+//	@Produce		json
+//	@Success		200	{object}	object{body=object{film=domain.FilmWithoutActors}}
+//	@Failure		400	{object}	object{err=string}
+//	@Failure		403	{object}	object{err=string}
+//	@Failure		404	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/trash/films/{id}/restore [post]
+func (h *FilmsHandler) RestoreFilm(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "films/http", "RestoreFilm", err, err.Error())
+		return
+	}
+
+	film, err := h.FilmsUsecase.Restore(id, domain.RequestAuditContext(r))
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "films/http", "RestoreFilm", err, err.Error())
+		return
+	}
+
 	domain.WriteResponse(
 		w,
 		map[string]interface{}{
@@ -32,9 +32,9 @@ func TestAddFilm(t *testing.T) {
 				return []byte(`{"title":"Film Title", "description": "Film Description", "releaseDate": "2022-01-01", "rating": 8.5, "actors": [{"id": 4}, {"id": 5}]}`)
 			},
 			setUCaseExpectations: func(usecase *mocks.FilmsUsecase, film domain.Film) {
-				usecase.On("Add", film).Return(1, nil)
+				usecase.On("Add", film, mock.Anything).Return(1, nil)
 			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder}),
+			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder, Verified: true}),
 			status: http.StatusOK,
 		},
 		{
@@ -43,9 +43,9 @@ func TestAddFilm(t *testing.T) {
 				return []byte(`{"title":"", "description": "Film Description", "releaseDate": "2022-01-01", "rating": 8.5, "actors": [{"id": 4}, {"id": 5}]}`)
 			},
 			setUCaseExpectations: func(usecase *mocks.FilmsUsecase, film domain.Film) {
-				usecase.On("Add", film).Return(0, domain.ErrBadRequest)
+				usecase.On("Add", film, mock.Anything).Return(0, domain.ErrBadRequest)
 			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder}),
+			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder, Verified: true}),
 			status: http.StatusBadRequest,
 		},
 		{
@@ -54,9 +54,9 @@ func TestAddFilm(t *testing.T) {
 				return []byte(`{"title":"Film Title", "description": "", "releaseDate": "2022-01-01", "rating": 8.5, "actors": [{"id": 4}, {"id": 5}]}`)
 			},
 			setUCaseExpectations: func(usecase *mocks.FilmsUsecase, film domain.Film) {
-				usecase.On("Add", film).Return(0, domain.ErrBadRequest)
+				usecase.On("Add", film, mock.Anything).Return(0, domain.ErrBadRequest)
 			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder}),
+			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder, Verified: true}),
 			status: http.StatusBadRequest,
 		},
 		{
@@ -65,9 +65,9 @@ func TestAddFilm(t *testing.T) {
 				return []byte(`{"title":"Film Title", "description": "Film Description", "releaseDate": "invalid_date", "rating": 8.5, "actors": [{"id": 4}, {"id": 5}]}`)
 			},
 			setUCaseExpectations: func(usecase *mocks.FilmsUsecase, film domain.Film) {
-				usecase.On("Add", film).Return(0, domain.ErrBadRequest).Maybe()
+				usecase.On("Add", film, mock.Anything).Return(0, domain.ErrBadRequest).Maybe()
 			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder}),
+			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder, Verified: true}),
 			status: http.StatusBadRequest,
 		},
 		{
@@ -76,33 +76,11 @@ func TestAddFilm(t *testing.T) {
 				return []byte(`{"title":"Film Title", "description": "Film Description", "releaseDate": "2022-01-01", "rating": -1.5, "actors": [{"id": 4}, {"id": 5}]}`)
 			},
 			setUCaseExpectations: func(usecase *mocks.FilmsUsecase, film domain.Film) {
-				usecase.On("Add", film).Return(0, domain.ErrBadRequest)
+				usecase.On("Add", film, mock.Anything).Return(0, domain.ErrBadRequest)
 			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder}),
+			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder, Verified: true}),
 			status: http.StatusBadRequest,
 		},
-		{
-			name: "BadCase/NoModerRole",
-			getBody: func() []byte {
-				return []byte(`{"title":"Film Title", "description": "Film Description", "releaseDate": "2022-01-01", "rating": 8.5, "actors": [{"id": 4}, {"id": 5}]}`)
-			},
-			setUCaseExpectations: func(usecase *mocks.FilmsUsecase, film domain.Film) {
-				usecase.On("Add", film).Return(0, domain.ErrBadRequest).Maybe()
-			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Usr}),
-			status: http.StatusForbidden,
-		},
-		{
-			name: "BadCase/NoUserContext",
-			getBody: func() []byte {
-				return []byte(`{"title":"Film Title", "description": "Film Description", "releaseDate": "2022-01-01", "rating": 8.5, "actors": [{"id": 4}, {"id": 5}]}`)
-			},
-			setUCaseExpectations: func(usecase *mocks.FilmsUsecase, film domain.Film) {
-				usecase.On("Add", film).Return(0, domain.ErrBadRequest).Maybe()
-			},
-			ctx:    context.Background(),
-			status: http.StatusInternalServerError,
-		},
 	}
 
 	for _, test := range tests {
@@ -263,63 +241,45 @@ func TestDeleteFilm(t *testing.T) {
 		{
 			name: "GoodCase/Common",
 			setUCaseExpectations: func(usecase *mocks.FilmsUsecase, id int) {
-				usecase.On("Remove", id).Return(nil)
+				usecase.On("Remove", id, mock.Anything).Return(nil)
 			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder}),
+			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder, Verified: true}),
 			id:     "1",
 			status: http.StatusNoContent,
 		},
-		{
-			name: "BadCase/InvalidRole",
-			setUCaseExpectations: func(usecase *mocks.FilmsUsecase, id int) {
-				usecase.On("Remove", id).Return(domain.ErrBadRequest).Maybe()
-			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Usr}),
-			id:     "1",
-			status: http.StatusForbidden,
-		},
 		{
 			name: "BadCase/InvalidID",
 			setUCaseExpectations: func(usecase *mocks.FilmsUsecase, id int) {
-				usecase.On("Remove", id).Return(domain.ErrBadRequest).Maybe()
+				usecase.On("Remove", id, mock.Anything).Return(domain.ErrBadRequest).Maybe()
 			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder}),
+			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder, Verified: true}),
 			id:     "invalid_id",
 			status: http.StatusBadRequest,
 		},
 		{
 			name: "BadCase/EmptyID",
 			setUCaseExpectations: func(usecase *mocks.FilmsUsecase, id int) {
-				usecase.On("Remove", id).Return(domain.ErrBadRequest).Maybe()
+				usecase.On("Remove", id, mock.Anything).Return(domain.ErrBadRequest).Maybe()
 			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder}),
+			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder, Verified: true}),
 			id:     "",
 			status: http.StatusNotFound,
 		},
-		{
-			name: "BadCase/NoUserContext",
-			setUCaseExpectations: func(usecase *mocks.FilmsUsecase, id int) {
-				usecase.On("Remove", id).Return(domain.ErrBadRequest).Maybe()
-			},
-			ctx:    context.Background(),
-			id:     "1",
-			status: http.StatusInternalServerError,
-		},
 		{
 			name: "BadCase/OutOfRangeFilmId",
 			setUCaseExpectations: func(usecase *mocks.FilmsUsecase, id int) {
-				usecase.On("Remove", id).Return(domain.ErrOutOfRange)
+				usecase.On("Remove", id, mock.Anything).Return(domain.ErrOutOfRange)
 			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder}),
+			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder, Verified: true}),
 			id:     "1234563456789",
 			status: http.StatusNotFound,
 		},
 		{
 			name: "BadCase/NegativeFilmId",
 			setUCaseExpectations: func(usecase *mocks.FilmsUsecase, id int) {
-				usecase.On("Remove", id).Return(domain.ErrOutOfRange)
+				usecase.On("Remove", id, mock.Anything).Return(domain.ErrOutOfRange)
 			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder}),
+			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder, Verified: true}),
 			id:     "-3",
 			status: http.StatusNotFound,
 		},
@@ -371,7 +331,7 @@ func TestModifyFilm(t *testing.T) {
 					Description: "New Description",
 					ReleaseDate: d,
 					Rating:      9.0,
-				}).Return(domain.Film{
+				}, mock.Anything).Return(domain.Film{
 					ID:          1,
 					Title:       "New Title",
 					Description: "New Description",
@@ -379,7 +339,7 @@ func TestModifyFilm(t *testing.T) {
 					Rating:      9.0,
 				}, nil)
 			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder}),
+			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder, Verified: true}),
 			status: http.StatusOK,
 		},
 		{
@@ -395,65 +355,47 @@ func TestModifyFilm(t *testing.T) {
 					Description: "New Description",
 					ReleaseDate: d,
 					Rating:      9.0,
-				}).Return(domain.Film{}, domain.ErrBadRequest)
+				}, mock.Anything).Return(domain.Film{}, domain.ErrBadRequest)
 			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder}),
+			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder, Verified: true}),
 			status: http.StatusBadRequest,
 		},
 		{
 			name:        "BadCase/InvalidReleaseDate",
 			requestBody: strings.NewReader(`{"id":1,"title":"New Title", "description": "New Description", "releaseDate": "invalid_date", "rating": 9.0}`),
 			setUCaseExpectations: func(usecase *mocks.FilmsUsecase) {
-				usecase.On("Modify", mock.Anything).Return(domain.Film{}, domain.ErrBadRequest).Maybe()
+				usecase.On("Modify", mock.Anything, mock.Anything).Return(domain.Film{}, domain.ErrBadRequest).Maybe()
 			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder}),
+			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder, Verified: true}),
 			status: http.StatusBadRequest,
 		},
 		{
 			name:        "BadCase/NegativeRating",
 			requestBody: strings.NewReader(`{"id":1,"title":"New Title", "description": "New Description", "releaseDate": "2023-01-01", "rating": -1.5}`),
 			setUCaseExpectations: func(usecase *mocks.FilmsUsecase) {
-				usecase.On("Modify", mock.Anything).Return(domain.Film{}, domain.ErrBadRequest)
+				usecase.On("Modify", mock.Anything, mock.Anything).Return(domain.Film{}, domain.ErrBadRequest)
 			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder}),
+			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder, Verified: true}),
 			status: http.StatusBadRequest,
 		},
-		{
-			name:        "BadCase/NoModerRole",
-			requestBody: strings.NewReader(`{"id":1,"title":"New Title", "description": "New Description", "releaseDate": "2023-01-01", "rating": 9.0}`),
-			setUCaseExpectations: func(usecase *mocks.FilmsUsecase) {
-				usecase.On("Modify", mock.Anything).Return(domain.Film{}, domain.ErrBadRequest)
-			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Usr}),
-			status: http.StatusForbidden,
-		},
 		{
 			name:        "BadCase/FutureReleaseDate",
 			requestBody: strings.NewReader(`{"id":1,"title":"New Title", "description": "New Description", "releaseDate": "3023-01-01", "rating": 9.0}`),
 			setUCaseExpectations: func(usecase *mocks.FilmsUsecase) {
-				usecase.On("Modify", mock.Anything).Return(domain.Film{}, domain.ErrOutOfRange)
+				usecase.On("Modify", mock.Anything, mock.Anything).Return(domain.Film{}, domain.ErrOutOfRange)
 			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder}),
+			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder, Verified: true}),
 			status: http.StatusNotFound,
 		},
 		{
 			name:        "BadCase/PastReleaseDate",
 			requestBody: strings.NewReader(`{"id":1,"title":"New Title", "description": "New Description", "releaseDate": "1000-01-01", "rating": 9.0}`),
 			setUCaseExpectations: func(usecase *mocks.FilmsUsecase) {
-				usecase.On("Modify", mock.Anything).Return(domain.Film{}, domain.ErrOutOfRange)
+				usecase.On("Modify", mock.Anything, mock.Anything).Return(domain.Film{}, domain.ErrOutOfRange)
 			},
-			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder}),
+			ctx:    context.WithValue(context.Background(), domain.SessionContextKey, domain.SessionContext{Role: domain.Moder, Verified: true}),
 			status: http.StatusNotFound,
 		},
-		{
-			name:        "BadCase/NoUserContext",
-			requestBody: strings.NewReader(`{"id":1,"title":"New Title", "description": "New Description", "releaseDate": "2023-01-01", "rating": 9.0}`),
-			setUCaseExpectations: func(usecase *mocks.FilmsUsecase) {
-				usecase.On("Modify", mock.Anything).Return(domain.Film{}, domain.ErrBadRequest).Maybe()
-			},
-			ctx:    context.Background(),
-			status: http.StatusInternalServerError,
-		},
 	}
 
 	for _, test := range tests {
@@ -473,3 +415,415 @@ func TestModifyFilm(t *testing.T) {
 		})
 	}
 }
+
+func TestRoutesPermissions(t *testing.T) {
+	userCtx := context.WithValue(context.Background(), domain.SessionContextKey,
+		domain.SessionContext{UserID: 1, Role: domain.Usr, Verified: true})
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		ctx    context.Context
+		status int
+	}{
+		{name: "BadCase/UserPost", method: "POST", target: "/films", ctx: userCtx, status: http.StatusForbidden},
+		{name: "BadCase/UserPut", method: "PUT", target: "/films", ctx: userCtx, status: http.StatusForbidden},
+		{name: "BadCase/UserPatch", method: "PATCH", target: "/films/1", ctx: userCtx, status: http.StatusForbidden},
+		{name: "BadCase/UserDelete", method: "DELETE", target: "/films/1", ctx: userCtx, status: http.StatusForbidden},
+		{name: "BadCase/NoUserContext", method: "DELETE", target: "/films/1", ctx: context.Background(), status: http.StatusUnauthorized},
+		{name: "BadCase/UserRevisions", method: "GET", target: "/films/1/revisions", ctx: userCtx, status: http.StatusForbidden},
+		{name: "BadCase/UserRevert", method: "POST", target: "/films/1/revisions/2/revert", ctx: userCtx, status: http.StatusForbidden},
+		{name: "BadCase/UserTrash", method: "GET", target: "/trash/films", ctx: userCtx, status: http.StatusForbidden},
+		{name: "BadCase/UserRestore", method: "POST", target: "/trash/films/1/restore", ctx: userCtx, status: http.StatusForbidden},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockUsecase := new(mocks.FilmsUsecase)
+
+			mux := http.NewServeMux()
+			films_http.NewFilmsHandler(mux, mockUsecase)
+
+			req := httptest.NewRequest(test.method, test.target, strings.NewReader(`{}`))
+			req = req.WithContext(test.ctx)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFilmRevisions(t *testing.T) {
+	moderCtx := context.WithValue(context.Background(), domain.SessionContextKey,
+		domain.SessionContext{UserID: 1, Role: domain.Moder, Verified: true})
+
+	tests := []struct {
+		name                 string
+		method               string
+		target               string
+		ifMatch              string
+		setUCaseExpectations func(usecase *mocks.FilmsUsecase)
+		status               int
+	}{
+		{
+			name:   "GoodCase/List",
+			method: "GET",
+			target: "/films/1/revisions",
+			setUCaseExpectations: func(usecase *mocks.FilmsUsecase) {
+				usecase.On("GetRevisions", 1).Return([]domain.FilmRevision{{Revision: 1}}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:   "GoodCase/Diff",
+			method: "GET",
+			target: "/films/1/revisions/diff?from=1&to=3",
+			setUCaseExpectations: func(usecase *mocks.FilmsUsecase) {
+				usecase.On("DiffRevisions", 1, 1, 3).Return(domain.RevisionDiff{From: 1, To: 3}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:                 "BadCase/DiffWithoutTo",
+			method:               "GET",
+			target:               "/films/1/revisions/diff?from=1",
+			setUCaseExpectations: func(usecase *mocks.FilmsUsecase) {},
+			status:               http.StatusBadRequest,
+		},
+		{
+			name:   "GoodCase/Revert",
+			method: "POST",
+			target: "/films/1/revisions/2/revert",
+			setUCaseExpectations: func(usecase *mocks.FilmsUsecase) {
+				usecase.On("Revert", 1, 2, 0, mock.Anything).Return(domain.Film{ID: 1}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:                 "BadCase/RevertInvalidRevision",
+			method:               "POST",
+			target:               "/films/1/revisions/last/revert",
+			setUCaseExpectations: func(usecase *mocks.FilmsUsecase) {},
+			status:               http.StatusBadRequest,
+		},
+		{
+			name:   "BadCase/RevertNotFound",
+			method: "POST",
+			target: "/films/1/revisions/9/revert",
+			setUCaseExpectations: func(usecase *mocks.FilmsUsecase) {
+				usecase.On("Revert", 1, 9, 0, mock.Anything).Return(domain.Film{}, domain.ErrNotFound)
+			},
+			status: http.StatusNotFound,
+		},
+		{
+			name:    "BadCase/RevertVersionChanged",
+			method:  "POST",
+			target:  "/films/1/revisions/2/revert",
+			ifMatch: `"3"`,
+			setUCaseExpectations: func(usecase *mocks.FilmsUsecase) {
+				usecase.On("Revert", 1, 2, 3, mock.Anything).Return(domain.Film{}, domain.ErrPreconditionFailed)
+			},
+			status: http.StatusPreconditionFailed,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockUsecase := new(mocks.FilmsUsecase)
+			test.setUCaseExpectations(mockUsecase)
+
+			mux := http.NewServeMux()
+			films_http.NewFilmsHandler(mux, mockUsecase)
+
+			req := httptest.NewRequest(test.method, test.target, nil)
+			if test.ifMatch != "" {
+				req.Header.Set(domain.IfMatchHeader, test.ifMatch)
+			}
+			req = req.WithContext(moderCtx)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTrash(t *testing.T) {
+	moderCtx := context.WithValue(context.Background(), domain.SessionContextKey,
+		domain.SessionContext{UserID: 1, Role: domain.Moder, Verified: true})
+
+	tests := []struct {
+		name                 string
+		method               string
+		target               string
+		setUCaseExpectations func(usecase *mocks.FilmsUsecase)
+		status               int
+	}{
+		{
+			name:   "GoodCase/List",
+			method: "GET",
+			target: "/trash/films",
+			setUCaseExpectations: func(usecase *mocks.FilmsUsecase) {
+				usecase.On("GetDeleted").Return([]domain.Film{{ID: 1}}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:   "GoodCase/Restore",
+			method: "POST",
+			target: "/trash/films/1/restore",
+			setUCaseExpectations: func(usecase *mocks.FilmsUsecase) {
+				usecase.On("Restore", 1, mock.Anything).Return(domain.Film{ID: 1}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:                 "BadCase/RestoreInvalidID",
+			method:               "POST",
+			target:               "/trash/films/one/restore",
+			setUCaseExpectations: func(usecase *mocks.FilmsUsecase) {},
+			status:               http.StatusBadRequest,
+		},
+		{
+			name:   "BadCase/RestoreNotInTrash",
+			method: "POST",
+			target: "/trash/films/5/restore",
+			setUCaseExpectations: func(usecase *mocks.FilmsUsecase) {
+				usecase.On("Restore", 5, mock.Anything).Return(domain.Film{}, domain.ErrNotFound)
+			},
+			status: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockUsecase := new(mocks.FilmsUsecase)
+			test.setUCaseExpectations(mockUsecase)
+
+			mux := http.NewServeMux()
+			films_http.NewFilmsHandler(mux, mockUsecase)
+
+			req := httptest.NewRequest(test.method, test.target, nil)
+			req = req.WithContext(moderCtx)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFilmETag(t *testing.T) {
+	moderCtx := context.WithValue(context.Background(), domain.SessionContextKey,
+		domain.SessionContext{UserID: 1, Role: domain.Moder, Verified: true})
+
+	tests := []struct {
+		name                 string
+		method               string
+		target               string
+		header               http.Header
+		setUCaseExpectations func(usecase *mocks.FilmsUsecase)
+		status               int
+		etag                 string
+	}{
+		{
+			name:   "GoodCase/Get",
+			method: "GET",
+			target: "/films/1",
+			setUCaseExpectations: func(usecase *mocks.FilmsUsecase) {
+				usecase.On("GetById", 1).Return(domain.Film{ID: 1, Version: 3}, nil)
+			},
+			status: http.StatusOK,
+			etag:   `"3"`,
+		},
+		{
+			name:   "GoodCase/NotModified",
+			method: "GET",
+			target: "/films/1",
+			header: http.Header{"If-None-Match": {`"2", W/"3"`}},
+			setUCaseExpectations: func(usecase *mocks.FilmsUsecase) {
+				usecase.On("GetById", 1).Return(domain.Film{ID: 1, Version: 3}, nil)
+			},
+			status: http.StatusNotModified,
+			etag:   `"3"`,
+		},
+		{
+			name:   "GoodCase/Modified",
+			method: "GET",
+			target: "/films/1",
+			header: http.Header{"If-None-Match": {`"2"`}},
+			setUCaseExpectations: func(usecase *mocks.FilmsUsecase) {
+				usecase.On("GetById", 1).Return(domain.Film{ID: 1, Version: 3}, nil)
+			},
+			status: http.StatusOK,
+			etag:   `"3"`,
+		},
+		{
+			name:   "BadCase/GetNotFound",
+			method: "GET",
+			target: "/films/9",
+			setUCaseExpectations: func(usecase *mocks.FilmsUsecase) {
+				usecase.On("GetById", 9).Return(domain.Film{}, domain.ErrNotFound)
+			},
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "GoodCase/PutIfMatch",
+			method: "PUT",
+			target: "/films",
+			header: http.Header{"If-Match": {`"3"`}},
+			setUCaseExpectations: func(usecase *mocks.FilmsUsecase) {
+				usecase.On("Modify", domain.Film{ID: 1, Title: "Matrix", Version: 3}, mock.Anything).
+					Return(domain.Film{ID: 1, Title: "Matrix", Version: 4}, nil)
+			},
+			status: http.StatusOK,
+			etag:   `"4"`,
+		},
+		{
+			name:   "BadCase/PutStale",
+			method: "PUT",
+			target: "/films",
+			header: http.Header{"If-Match": {`"2"`}},
+			setUCaseExpectations: func(usecase *mocks.FilmsUsecase) {
+				usecase.On("Modify", domain.Film{ID: 1, Title: "Matrix", Version: 2}, mock.Anything).
+					Return(domain.Film{}, domain.ErrPreconditionFailed)
+			},
+			status: http.StatusPreconditionFailed,
+		},
+		{
+			name:                 "BadCase/PutWeakTag",
+			method:               "PUT",
+			target:               "/films",
+			header:               http.Header{"If-Match": {`W/"3"`}},
+			setUCaseExpectations: func(usecase *mocks.FilmsUsecase) {},
+			status:               http.StatusPreconditionFailed,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockUsecase := new(mocks.FilmsUsecase)
+			test.setUCaseExpectations(mockUsecase)
+
+			mux := http.NewServeMux()
+			films_http.NewFilmsHandler(mux, mockUsecase)
+
+			req := httptest.NewRequest(test.method, test.target, strings.NewReader(`{"id": 1, "title": "Matrix"}`))
+			req = req.WithContext(moderCtx)
+			for name, values := range test.header {
+				req.Header[name] = values
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			assert.Equal(t, test.etag, rec.Header().Get("ETag"))
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPatchFilm(t *testing.T) {
+	moderCtx := context.WithValue(context.Background(), domain.SessionContextKey,
+		domain.SessionContext{UserID: 1, Role: domain.Moder, Verified: true})
+
+	tests := []struct {
+		name                 string
+		target               string
+		header               http.Header
+		body                 string
+		setUCaseExpectations func(usecase *mocks.FilmsUsecase)
+		status               int
+		etag                 string
+	}{
+		{
+			name:   "GoodCase/MergePatch",
+			target: "/films/1",
+			header: http.Header{"Content-Type": {"application/merge-patch+json; charset=utf-8"}},
+			body:   `{"rating": 0}`,
+			setUCaseExpectations: func(usecase *mocks.FilmsUsecase) {
+				patch := domain.Patch{Type: domain.MergePatch, Body: []byte(`{"rating": 0}`)}
+				usecase.On("Patch", 1, patch, mock.Anything).Return(domain.Film{ID: 1, Version: 4}, nil)
+			},
+			status: http.StatusOK,
+			etag:   `"4"`,
+		},
+		{
+			name:   "GoodCase/JSONPatchIfMatch",
+			target: "/films/1",
+			header: http.Header{"Content-Type": {"application/json-patch+json"}, "If-Match": {`"3"`}},
+			body:   `[{"op": "replace", "path": "/rating", "value": 0}]`,
+			setUCaseExpectations: func(usecase *mocks.FilmsUsecase) {
+				patch := domain.Patch{Type: domain.JSONPatch, Body: []byte(`[{"op": "replace", "path": "/rating", "value": 0}]`), Version: 3}
+				usecase.On("Patch", 1, patch, mock.Anything).Return(domain.Film{ID: 1, Version: 4}, nil)
+			},
+			status: http.StatusOK,
+			etag:   `"4"`,
+		},
+		{
+			name:                 "BadCase/UnsupportedType",
+			target:               "/films/1",
+			header:               http.Header{"Content-Type": {"application/json"}},
+			body:                 `{"rating": 0}`,
+			setUCaseExpectations: func(usecase *mocks.FilmsUsecase) {},
+			status:               http.StatusUnsupportedMediaType,
+		},
+		{
+			name:                 "BadCase/WrongID",
+			target:               "/films/first",
+			header:               http.Header{"Content-Type": {"application/merge-patch+json"}},
+			body:                 `{"rating": 0}`,
+			setUCaseExpectations: func(usecase *mocks.FilmsUsecase) {},
+			status:               http.StatusBadRequest,
+		},
+		{
+			name:   "BadCase/Stale",
+			target: "/films/1",
+			header: http.Header{"Content-Type": {"application/merge-patch+json"}, "If-Match": {`"2"`}},
+			body:   `{"rating": 0}`,
+			setUCaseExpectations: func(usecase *mocks.FilmsUsecase) {
+				patch := domain.Patch{Type: domain.MergePatch, Body: []byte(`{"rating": 0}`), Version: 2}
+				usecase.On("Patch", 1, patch, mock.Anything).Return(domain.Film{}, domain.ErrPreconditionFailed)
+			},
+			status: http.StatusPreconditionFailed,
+		},
+		{
+			name:   "BadCase/InvalidPatch",
+			target: "/films/1",
+			header: http.Header{"Content-Type": {"application/merge-patch+json"}},
+			body:   `{"title": null}`,
+			setUCaseExpectations: func(usecase *mocks.FilmsUsecase) {
+				patch := domain.Patch{Type: domain.MergePatch, Body: []byte(`{"title": null}`)}
+				usecase.On("Patch", 1, patch, mock.Anything).Return(domain.Film{}, domain.ErrBadRequest)
+			},
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockUsecase := new(mocks.FilmsUsecase)
+			test.setUCaseExpectations(mockUsecase)
+
+			mux := http.NewServeMux()
+			films_http.NewFilmsHandler(mux, mockUsecase)
+
+			req := httptest.NewRequest("PATCH", test.target, strings.NewReader(test.body))
+			req = req.WithContext(moderCtx)
+			for name, values := range test.header {
+				req.Header[name] = values
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			assert.Equal(t, test.etag, rec.Header().Get("ETag"))
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
@@ -2,12 +2,14 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"github.com/ellexo2456/FilmLib/internal/domain"
 	logs "github.com/ellexo2456/FilmLib/internal/logger"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"math"
+	"time"
 )
 
 const insertQuery = `
@@ -20,33 +22,142 @@ const insertQuery = `
 const selectAllQuery = `
 	SELECT id, title, description, release_date, rating 
 	FROM film
+	WHERE deleted_at IS NULL
 `
 
 const searchQuery = `
 	SELECT f.id, f.title, f.description, f.release_date, f.rating
 	FROM film f
          LEFT JOIN film_actor fa ON fa.film_id = f.id
-         LEFT JOIN actor a ON a.id = fa.actor_id
+         LEFT JOIN actor a ON a.id = fa.actor_id AND a.deleted_at IS NULL
 	WHERE (f.title ILIKE $1
     OR a.name ILIKE $1)
+	  AND f.deleted_at IS NULL
 `
 
 const deleteQuery = `
-	DELETE FROM film
+	UPDATE film
+	SET deleted_at = CURRENT_TIMESTAMP
 	WHERE id = $1
+	  AND deleted_at IS NULL
 `
 
 const updateQuery = `
 	UPDATE film
-	SET title = $1, description = $2, release_date = $3, rating = $4 
+	SET title = $1, description = $2, release_date = $3, rating = $4, version = version + 1
 	WHERE id = $5 
-	RETURNING id, title, description, release_date, rating
+	  AND deleted_at IS NULL
+	  AND ($6::INT = 0 OR version = $6)
+	RETURNING id, title, description, release_date, rating, version
 `
 
 const selectByIdQuery = `
-	SELECT id, title, description, release_date, rating
+	SELECT id, title, description, release_date, rating, version
+	FROM film
+	WHERE id = $1
+	  AND deleted_at IS NULL
+`
+
+const selectDeletedQuery = `
+	SELECT id, title, description, release_date, rating, deleted_at
 	FROM film
+	WHERE deleted_at IS NOT NULL
+	ORDER BY deleted_at DESC
+`
+
+const restoreQuery = `
+	UPDATE film
+	SET deleted_at = NULL
 	WHERE id = $1
+	  AND deleted_at IS NOT NULL
+	RETURNING id, title, description, release_date, rating
+`
+
+// the cast links and revisions are removed by the cascade
+const purgeQuery = `
+	DELETE
+	FROM film
+	WHERE deleted_at < $1
+`
+
+// filmSnapshot builds the json of the film f with its cast, actors in
+// the trash are left out.
+const filmSnapshot = `
+	jsonb_build_object(
+		'id', f.id,
+		'title', f.title,
+		'description', f.description,
+		'releaseDate', f.release_date,
+		'rating', f.rating,
+		'actors', COALESCE((SELECT jsonb_agg(jsonb_build_object(
+		                               'id', a.id,
+		                               'name', a.name,
+		                               'sex', a.sex,
+		                               'birthdate', a.birthdate) ORDER BY a.id)
+		                    FROM film_actor fa
+		                             JOIN actor a ON a.id = fa.actor_id
+		                    WHERE fa.film_id = f.id
+		                      AND a.deleted_at IS NULL), '[]'::JSONB))
+`
+
+const insertRevisionQuery = `
+	INSERT INTO film_revision (film_id, revision, user_id, data)
+	SELECT f.id,
+	       COALESCE((SELECT MAX(revision) FROM film_revision WHERE film_id = f.id), 0) + 1,
+	       NULLIF($2, 0),` + filmSnapshot + `
+	FROM film f
+	WHERE f.id = $1
+`
+
+// films created before the history was kept get their current state
+// as the first revision, it runs before the row is locked, so a concurrent
+// first edit may have saved it already
+const insertBaseRevisionQuery = `
+	INSERT INTO film_revision (film_id, revision, user_id, data)
+	SELECT f.id, 1, NULL,` + filmSnapshot + `
+	FROM film f
+	WHERE f.id = $1
+	  AND NOT EXISTS(SELECT 1 FROM film_revision WHERE film_id = f.id)
+	ON CONFLICT DO NOTHING
+`
+
+const selectRevisionsQuery = `
+	SELECT revision, COALESCE(user_id, 0), created_at, data
+	FROM film_revision
+	WHERE film_id = $1
+	ORDER BY revision DESC
+`
+
+const selectRevisionQuery = `
+	SELECT revision, COALESCE(user_id, 0), created_at, data
+	FROM film_revision
+	WHERE film_id = $1
+	  AND revision = $2
+`
+
+// links of the actors in the trash are kept, they come back with the actor
+const deleteCastQuery = `
+	DELETE
+	FROM film_actor
+	WHERE film_id = $1
+	  AND actor_id IN (SELECT id FROM actor WHERE deleted_at IS NULL)
+`
+
+// the snapshot may keep the ids of the merged actors, they are resolved
+// to the actors they were merged into
+const insertCastQuery = `
+	WITH film_cast AS (SELECT DISTINCT a.id, a.name, a.sex, a.birthdate
+	                   FROM unnest($2::INT[]) AS s(id)
+	                            LEFT JOIN actor_redirect r ON r.from_id = s.id
+	                            JOIN actor a ON a.id = COALESCE(r.to_id, s.id)
+	                   WHERE a.deleted_at IS NULL),
+	     inserted AS (INSERT INTO film_actor (film_id, actor_id)
+	                  SELECT $1, id
+	                  FROM film_cast
+	                  ON CONFLICT DO NOTHING)
+	SELECT id, name, sex, birthdate
+	FROM film_cast
+	ORDER BY id
 `
 
 type filmsPostgresqlRepository struct {
@@ -61,7 +172,7 @@ func NewFilmsPostgresqlRepository(pool domain.PgxPoolIface, ctx context.Context)
 	}
 }
 
-func (r *filmsPostgresqlRepository) Insert(film domain.Film) (int, error) {
+func (r *filmsPostgresqlRepository) Insert(film domain.Film, userID int) (int, error) {
 	tx, err := r.db.Begin(r.ctx)
 	if err != nil {
 		return 0, domain.ErrInternalServerError
@@ -105,6 +216,11 @@ func (r *filmsPostgresqlRepository) Insert(film domain.Film) (int, error) {
 		return 0, domain.ErrInternalServerError
 	}
 
+	if _, err = tx.Exec(r.ctx, insertRevisionQuery, id, userID); err != nil {
+		logs.LogError(logs.Logger, "films/postgres", "Insert", err, err.Error())
+		return 0, err
+	}
+
 	err = tx.Commit(r.ctx)
 	if err != nil {
 		logs.LogError(logs.Logger, "films/postgres", "Insert", domain.ErrInternalServerError, "can`t commit changes")
@@ -198,16 +314,32 @@ func (r *filmsPostgresqlRepository) Delete(id int) error {
 	return nil
 }
 
-func (r *filmsPostgresqlRepository) Update(film domain.Film) (domain.Film, error) {
-	row := r.db.QueryRow(r.ctx, updateQuery, film.Title, film.Description, film.ReleaseDate, film.Rating, film.ID)
+func (r *filmsPostgresqlRepository) Update(film domain.Film, userID int) (domain.Film, error) {
+	tx, err := r.db.Begin(r.ctx)
+	if err != nil {
+		logs.LogError(logs.Logger, "films/postgres", "Update", err, err.Error())
+		return domain.Film{}, err
+	}
+	defer tx.Rollback(r.ctx)
 
-	err := row.Scan(
+	if _, err = tx.Exec(r.ctx, insertBaseRevisionQuery, film.ID); err != nil {
+		logs.LogError(logs.Logger, "films/postgres", "Update", err, err.Error())
+		return domain.Film{}, err
+	}
+
+	row := tx.QueryRow(r.ctx, updateQuery, film.Title, film.Description, film.ReleaseDate, film.Rating, film.ID, film.Version)
+
+	err = row.Scan(
 		&film.ID,
 		&film.Title,
 		&film.Description,
 		&film.ReleaseDate,
 		&film.Rating,
+		&film.Version,
 	)
+	if errors.Is(err, pgx.ErrNoRows) && film.Version != 0 {
+		return domain.Film{}, domain.ErrPreconditionFailed
+	}
 	if errors.Is(err, pgx.ErrNoRows) {
 		logs.LogError(logs.Logger, "films/postgres", "Update", err, err.Error())
 		return domain.Film{}, domain.ErrNotFound
@@ -217,6 +349,16 @@ func (r *filmsPostgresqlRepository) Update(film domain.Film) (domain.Film, error
 		return domain.Film{}, err
 	}
 
+	if _, err = tx.Exec(r.ctx, insertRevisionQuery, film.ID, userID); err != nil {
+		logs.LogError(logs.Logger, "films/postgres", "Update", err, err.Error())
+		return domain.Film{}, err
+	}
+
+	if err = tx.Commit(r.ctx); err != nil {
+		logs.LogError(logs.Logger, "films/postgres", "Update", err, "can`t commit changes")
+		return domain.Film{}, err
+	}
+
 	return film, nil
 }
 
@@ -230,6 +372,7 @@ func (r *filmsPostgresqlRepository) SelectById(id int) (domain.Film, error) {
 		&film.Description,
 		&film.ReleaseDate,
 		&film.Rating,
+		&film.Version,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		logs.LogError(logs.Logger, "films/postgres", "SelectById", err, err.Error())
@@ -242,3 +385,200 @@ func (r *filmsPostgresqlRepository) SelectById(id int) (domain.Film, error) {
 
 	return film, nil
 }
+
+func (r *filmsPostgresqlRepository) SelectRevisions(filmID int) ([]domain.FilmRevision, error) {
+	rows, err := r.db.Query(r.ctx, selectRevisionsQuery, filmID)
+	if err != nil {
+		logs.LogError(logs.Logger, "films/postgres", "SelectRevisions", err, err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := []domain.FilmRevision{}
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			logs.LogError(logs.Logger, "films/postgres", "SelectRevisions", err, err.Error())
+			return nil, err
+		}
+
+		revisions = append(revisions, revision)
+	}
+	if err = rows.Err(); err != nil {
+		logs.LogError(logs.Logger, "films/postgres", "SelectRevisions", err, err.Error())
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+func (r *filmsPostgresqlRepository) SelectRevision(filmID, revision int) (domain.FilmRevision, error) {
+	rev, err := scanRevision(r.db.QueryRow(r.ctx, selectRevisionQuery, filmID, revision))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.FilmRevision{}, domain.ErrNotFound
+	}
+	if err != nil {
+		logs.LogError(logs.Logger, "films/postgres", "SelectRevision", err, err.Error())
+		return domain.FilmRevision{}, err
+	}
+
+	return rev, nil
+}
+
+func (r *filmsPostgresqlRepository) Revert(filmID, revision, version, userID int) (domain.Film, error) {
+	tx, err := r.db.Begin(r.ctx)
+	if err != nil {
+		logs.LogError(logs.Logger, "films/postgres", "Revert", err, err.Error())
+		return domain.Film{}, err
+	}
+	defer tx.Rollback(r.ctx)
+
+	rev, err := scanRevision(tx.QueryRow(r.ctx, selectRevisionQuery, filmID, revision))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Film{}, domain.ErrNotFound
+	}
+	if err != nil {
+		logs.LogError(logs.Logger, "films/postgres", "Revert", err, err.Error())
+		return domain.Film{}, err
+	}
+	old := rev.Film
+
+	var film domain.Film
+	err = tx.QueryRow(r.ctx, updateQuery, old.Title, old.Description, old.ReleaseDate, old.Rating, filmID, version).Scan(
+		&film.ID,
+		&film.Title,
+		&film.Description,
+		&film.ReleaseDate,
+		&film.Rating,
+		&film.Version,
+	)
+	if errors.Is(err, pgx.ErrNoRows) && version != 0 {
+		return domain.Film{}, domain.ErrPreconditionFailed
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Film{}, domain.ErrNotFound
+	}
+	if err != nil {
+		logs.LogError(logs.Logger, "films/postgres", "Revert", err, err.Error())
+		return domain.Film{}, err
+	}
+
+	if _, err = tx.Exec(r.ctx, deleteCastQuery, filmID); err != nil {
+		logs.LogError(logs.Logger, "films/postgres", "Revert", err, err.Error())
+		return domain.Film{}, err
+	}
+
+	actorIDs := make([]int, 0, len(old.Actors))
+	for _, a := range old.Actors {
+		actorIDs = append(actorIDs, a.ID)
+	}
+	rows, err := tx.Query(r.ctx, insertCastQuery, filmID, actorIDs)
+	if err != nil {
+		logs.LogError(logs.Logger, "films/postgres", "Revert", err, err.Error())
+		return domain.Film{}, err
+	}
+	for rows.Next() {
+		var actor domain.Actor
+		if err = rows.Scan(&actor.ID, &actor.Name, &actor.Sex, &actor.Birthdate); err != nil {
+			rows.Close()
+			logs.LogError(logs.Logger, "films/postgres", "Revert", err, err.Error())
+			return domain.Film{}, err
+		}
+		film.Actors = append(film.Actors, actor)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		logs.LogError(logs.Logger, "films/postgres", "Revert", err, err.Error())
+		return domain.Film{}, err
+	}
+
+	if _, err = tx.Exec(r.ctx, insertRevisionQuery, filmID, userID); err != nil {
+		logs.LogError(logs.Logger, "films/postgres", "Revert", err, err.Error())
+		return domain.Film{}, err
+	}
+
+	if err = tx.Commit(r.ctx); err != nil {
+		logs.LogError(logs.Logger, "films/postgres", "Revert", err, "can`t commit changes")
+		return domain.Film{}, err
+	}
+
+	return film, nil
+}
+
+func scanRevision(row pgx.Row) (domain.FilmRevision, error) {
+	var rev domain.FilmRevision
+	var data []byte
+	if err := row.Scan(&rev.Revision, &rev.UserID, &rev.CreatedAt, &data); err != nil {
+		return domain.FilmRevision{}, err
+	}
+	if err := json.Unmarshal(data, &rev.Film); err != nil {
+		return domain.FilmRevision{}, err
+	}
+
+	return rev, nil
+}
+
+func (r *filmsPostgresqlRepository) SelectDeleted() ([]domain.Film, error) {
+	rows, err := r.db.Query(r.ctx, selectDeletedQuery)
+	if err != nil {
+		logs.LogError(logs.Logger, "films/postgres", "SelectDeleted", err, err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	films := []domain.Film{}
+	for rows.Next() {
+		var film domain.Film
+		err = rows.Scan(
+			&film.ID,
+			&film.Title,
+			&film.Description,
+			&film.ReleaseDate,
+			&film.Rating,
+			&film.DeletedAt,
+		)
+		if err != nil {
+			logs.LogError(logs.Logger, "films/postgres", "SelectDeleted", err, err.Error())
+			return nil, err
+		}
+
+		film.Rating = math.Trunc(film.Rating*10) / 10
+		films = append(films, film)
+	}
+	if err = rows.Err(); err != nil {
+		logs.LogError(logs.Logger, "films/postgres", "SelectDeleted", err, err.Error())
+		return nil, err
+	}
+
+	return films, nil
+}
+
+func (r *filmsPostgresqlRepository) Restore(id int) (domain.Film, error) {
+	var film domain.Film
+	err := r.db.QueryRow(r.ctx, restoreQuery, id).Scan(
+		&film.ID,
+		&film.Title,
+		&film.Description,
+		&film.ReleaseDate,
+		&film.Rating,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Film{}, domain.ErrNotFound
+	}
+	if err != nil {
+		logs.LogError(logs.Logger, "films/postgres", "Restore", err, err.Error())
+		return domain.Film{}, err
+	}
+
+	return film, nil
+}
+
+func (r *filmsPostgresqlRepository) Purge(before time.Time) (int, error) {
+	res, err := r.db.Exec(r.ctx, purgeQuery, before)
+	if err != nil {
+		logs.LogError(logs.Logger, "films/postgres", "Purge", err, err.Error())
+		return 0, err
+	}
+
+	return int(res.RowsAffected()), nil
+}
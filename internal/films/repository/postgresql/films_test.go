@@ -11,6 +11,7 @@ import (
 	"github.com/pashagolub/pgxmock/v3"
 	"github.com/stretchr/testify/require"
 	"testing"
+	"time"
 )
 
 const insertQuery = `
@@ -22,11 +23,52 @@ const selectAllQuery = `
 	FROM film
 `
 
+const insertRevisionQuery = `
+	INSERT INTO film_revision
+`
+
+// a concurrent first edit may save the base revision first
+const insertBaseRevisionQuery = `
+	AND NOT EXISTS\(SELECT 1 FROM film_revision WHERE film_id = f.id\)
+	ON CONFLICT DO NOTHING
+`
+
+const selectRevisionQuery = `
+	SELECT revision, COALESCE\(user_id, 0\), created_at, data
+	FROM film_revision
+`
+
 const deleteQuery = `
-	DELETE FROM film
+	UPDATE film
+	SET deleted_at = CURRENT_TIMESTAMP
 	WHERE id = \$1
 `
 
+const updateQuery = `
+	UPDATE film
+	SET title = \$1, description = \$2, release_date = \$3, rating = \$4, version = version \+ 1
+`
+
+const restoreQuery = `
+	UPDATE film
+	SET deleted_at = NULL
+`
+
+const purgeQuery = `
+	DELETE
+	FROM film
+	WHERE deleted_at < \$1
+`
+
+const deleteCastQuery = `
+	DELETE
+	FROM film_actor
+`
+
+const insertCastQuery = `
+	WITH film_cast AS
+`
+
 func TestInsertIntoFilm(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -143,9 +185,13 @@ func TestInsertIntoFilm(t *testing.T) {
 				cp.WillReturnError(test.getCopyErr())
 			}
 
+			mockDB.ExpectExec(insertRevisionQuery).
+				WithArgs(film.ID, 1).
+				WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
 			mockDB.ExpectCommit()
 
-			id, err := r.Insert(film)
+			id, err := r.Insert(film, 1)
 			if test.getCopyErr == nil && test.getInsertErr == nil {
 				require.Nil(t, err)
 				require.Equal(t, id, film.ID)
@@ -296,3 +342,271 @@ func TestDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestSelectRevision(t *testing.T) {
+	createdAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		setMock  func(mockDB pgxmock.PgxPoolIface)
+		revision domain.FilmRevision
+		err      error
+	}{
+		{
+			name: "GoodCase/Common",
+			setMock: func(mockDB pgxmock.PgxPoolIface) {
+				rows := mockDB.NewRows([]string{"revision", "user_id", "created_at", "data"}).
+					AddRow(2, 5, createdAt, []byte(`{"id": 1, "title": "Matrix", "rating": 8.5, "actors": [{"id": 3, "name": "Keanu"}]}`))
+				mockDB.ExpectQuery(selectRevisionQuery).
+					WithArgs(1, 2).
+					WillReturnRows(rows)
+			},
+			revision: domain.FilmRevision{
+				Revision:  2,
+				UserID:    5,
+				CreatedAt: createdAt,
+				Film: domain.Film{
+					ID:     1,
+					Title:  "Matrix",
+					Rating: 8.5,
+					Actors: []domain.Actor{{ID: 3, Name: "Keanu"}},
+				},
+			},
+		},
+		{
+			name: "BadCase/NotFound",
+			setMock: func(mockDB pgxmock.PgxPoolIface) {
+				mockDB.ExpectQuery(selectRevisionQuery).
+					WithArgs(1, 2).
+					WillReturnError(pgx.ErrNoRows)
+			},
+			err: domain.ErrNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockDB, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mockDB.Close()
+
+			test.setMock(mockDB)
+			r := postgres.NewFilmsPostgresqlRepository(mockDB, context.Background())
+
+			revision, err := r.SelectRevision(1, 2)
+			require.Equal(t, test.err, err)
+			require.Equal(t, test.revision, revision)
+			require.Nil(t, mockDB.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRestore(t *testing.T) {
+	var d pgtype.Date
+	d.Scan("2000-01-01")
+	film := domain.Film{ID: 1, Title: "Matrix", Description: "desc", ReleaseDate: d, Rating: 8.5}
+
+	tests := []struct {
+		name    string
+		setMock func(mockDB pgxmock.PgxPoolIface)
+		film    domain.Film
+		err     error
+	}{
+		{
+			name: "GoodCase/Common",
+			setMock: func(mockDB pgxmock.PgxPoolIface) {
+				rows := mockDB.NewRows([]string{"id", "title", "description", "release_date", "rating"}).
+					AddRow(film.ID, film.Title, film.Description, film.ReleaseDate, film.Rating)
+				mockDB.ExpectQuery(restoreQuery).
+					WithArgs(1).
+					WillReturnRows(rows)
+			},
+			film: film,
+		},
+		{
+			name: "BadCase/NotInTrash",
+			setMock: func(mockDB pgxmock.PgxPoolIface) {
+				mockDB.ExpectQuery(restoreQuery).
+					WithArgs(1).
+					WillReturnError(pgx.ErrNoRows)
+			},
+			err: domain.ErrNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockDB, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mockDB.Close()
+
+			test.setMock(mockDB)
+			r := postgres.NewFilmsPostgresqlRepository(mockDB, context.Background())
+
+			film, err := r.Restore(1)
+			require.Equal(t, test.err, err)
+			require.Equal(t, test.film, film)
+			require.Nil(t, mockDB.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPurge(t *testing.T) {
+	mockDB, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockDB.Close()
+
+	before := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	mockDB.ExpectExec(purgeQuery).
+		WithArgs(before).
+		WillReturnResult(pgxmock.NewResult("DELETE", 3))
+
+	r := postgres.NewFilmsPostgresqlRepository(mockDB, context.Background())
+	count, err := r.Purge(before)
+
+	require.Nil(t, err)
+	require.Equal(t, 3, count)
+	require.Nil(t, mockDB.ExpectationsWereMet())
+}
+
+func TestUpdate(t *testing.T) {
+	var d pgtype.Date
+	d.Scan("2000-01-01")
+	film := domain.Film{ID: 1, Title: "Matrix", Description: "desc", ReleaseDate: d, Rating: 8.5}
+
+	tests := []struct {
+		name    string
+		version int
+		setMock func(mockDB pgxmock.PgxPoolIface, eq *pgxmock.ExpectedQuery)
+		film    domain.Film
+		err     error
+	}{
+		{
+			name:    "GoodCase/Common",
+			version: 3,
+			setMock: func(mockDB pgxmock.PgxPoolIface, eq *pgxmock.ExpectedQuery) {
+				eq.WillReturnRows(mockDB.NewRows([]string{"id", "title", "description", "release_date", "rating", "version"}).
+					AddRow(film.ID, film.Title, film.Description, film.ReleaseDate, film.Rating, 4))
+				mockDB.ExpectExec(insertRevisionQuery).
+					WithArgs(film.ID, 1).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mockDB.ExpectCommit()
+			},
+			film: domain.Film{ID: 1, Title: "Matrix", Description: "desc", ReleaseDate: d, Rating: 8.5, Version: 4},
+		},
+		{
+			name:    "BadCase/VersionChanged",
+			version: 3,
+			setMock: func(mockDB pgxmock.PgxPoolIface, eq *pgxmock.ExpectedQuery) {
+				eq.WillReturnError(pgx.ErrNoRows)
+				mockDB.ExpectRollback()
+			},
+			err: domain.ErrPreconditionFailed,
+		},
+		{
+			name:    "BadCase/NotFound",
+			version: 0,
+			setMock: func(mockDB pgxmock.PgxPoolIface, eq *pgxmock.ExpectedQuery) {
+				eq.WillReturnError(pgx.ErrNoRows)
+				mockDB.ExpectRollback()
+			},
+			err: domain.ErrNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockDB, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mockDB.Close()
+
+			mockDB.ExpectBegin()
+			mockDB.ExpectExec(insertBaseRevisionQuery).
+				WithArgs(film.ID).
+				WillReturnResult(pgxmock.NewResult("INSERT", 0))
+			eq := mockDB.ExpectQuery(updateQuery).
+				WithArgs(film.Title, film.Description, film.ReleaseDate, film.Rating, film.ID, test.version)
+			test.setMock(mockDB, eq)
+
+			r := postgres.NewFilmsPostgresqlRepository(mockDB, context.Background())
+
+			update := film
+			update.Version = test.version
+			updated, err := r.Update(update, 1)
+			require.Equal(t, test.err, err)
+			require.Equal(t, test.film, updated)
+			require.Nil(t, mockDB.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRevert(t *testing.T) {
+	mockDB, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockDB.Close()
+
+	// actor 3 is merged into actor 2 after the revision
+	mockDB.ExpectBegin()
+	mockDB.ExpectQuery(selectRevisionQuery).
+		WithArgs(1, 2).
+		WillReturnRows(mockDB.NewRows([]string{"revision", "user_id", "created_at", "data"}).
+			AddRow(2, 5, time.Now(), []byte(`{"id": 1, "title": "Matrix", "rating": 8.5, "actors": [{"id": 3, "name": "Keanu"}]}`)))
+	mockDB.ExpectQuery(updateQuery).
+		WithArgs("Matrix", "", pgtype.Date{}, 8.5, 1, 0).
+		WillReturnRows(mockDB.NewRows([]string{"id", "title", "description", "release_date", "rating", "version"}).
+			AddRow(1, "Matrix", "", pgtype.Date{}, 8.5, 4))
+	mockDB.ExpectExec(deleteCastQuery).
+		WithArgs(1).
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	mockDB.ExpectQuery(insertCastQuery).
+		WithArgs(1, []int{3}).
+		WillReturnRows(mockDB.NewRows([]string{"id", "name", "sex", "birthdate"}).
+			AddRow(2, "Keanu Reeves", domain.M, pgtype.Date{}))
+	mockDB.ExpectExec(insertRevisionQuery).
+		WithArgs(1, 1).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mockDB.ExpectCommit()
+	mockDB.ExpectRollback()
+
+	r := postgres.NewFilmsPostgresqlRepository(mockDB, context.Background())
+
+	film, err := r.Revert(1, 2, 0, 1)
+	require.Nil(t, err)
+	require.Equal(t, domain.Film{ID: 1, Title: "Matrix", Rating: 8.5, Version: 4,
+		Actors: []domain.Actor{{ID: 2, Name: "Keanu Reeves", Sex: domain.M}}}, film)
+	require.Nil(t, mockDB.ExpectationsWereMet())
+}
+
+func TestRevertVersionChanged(t *testing.T) {
+	mockDB, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockDB.Close()
+
+	mockDB.ExpectBegin()
+	mockDB.ExpectQuery(selectRevisionQuery).
+		WithArgs(1, 2).
+		WillReturnRows(mockDB.NewRows([]string{"revision", "user_id", "created_at", "data"}).
+			AddRow(2, 5, time.Now(), []byte(`{"id": 1, "title": "Matrix", "rating": 8.5}`)))
+	mockDB.ExpectQuery(updateQuery).
+		WithArgs("Matrix", "", pgtype.Date{}, 8.5, 1, 3).
+		WillReturnError(pgx.ErrNoRows)
+	mockDB.ExpectRollback()
+
+	r := postgres.NewFilmsPostgresqlRepository(mockDB, context.Background())
+
+	_, err = r.Revert(1, 2, 3, 1)
+	require.Equal(t, domain.ErrPreconditionFailed, err)
+	require.Nil(t, mockDB.ExpectationsWereMet())
+}
@@ -5,30 +5,39 @@ import (
 	"github.com/ellexo2456/FilmLib/internal/domain"
 	logs "github.com/ellexo2456/FilmLib/internal/logger"
 	"slices"
+	"time"
 )
 
 type filmsUsecase struct {
 	filmsRepo domain.FilmsRepository
+	audit     domain.AuditUsecase
+	events    domain.EventEmitter
 }
 
-func NewFilmsUsecase(fr domain.FilmsRepository) domain.FilmsUsecase {
+func NewFilmsUsecase(fr domain.FilmsRepository, au domain.AuditUsecase, ee domain.EventEmitter) domain.FilmsUsecase {
 	return &filmsUsecase{
 		filmsRepo: fr,
+		audit:     au,
+		events:    ee,
 	}
 }
 
-func (u *filmsUsecase) Add(film domain.Film) (int, error) {
+func (u *filmsUsecase) Add(film domain.Film, ac domain.AuditContext) (int, error) {
 	if isEmpty(film) {
 		return 0, domain.ErrBadRequest
 	}
 
-	id, err := u.filmsRepo.Insert(film)
+	id, err := u.filmsRepo.Insert(film, ac.UserID)
 	if err != nil {
 		logs.LogError(logs.Logger, "films/usecase", "Add", err, err.Error())
 		return 0, err
 	}
 
 	logs.Logger.Debug("films/usecase Add:", id)
+	film.ID = id
+	u.audit.Record(ac, domain.AuditCreate, domain.AuditFilm, id, nil, film)
+	u.events.Emit(domain.FilmCreated, film)
+
 	return id, nil
 }
 
@@ -58,21 +67,44 @@ func (u *filmsUsecase) Search(searchStr string) ([]domain.Film, error) {
 	return films, nil
 }
 
-func (u *filmsUsecase) Remove(id int) error {
+// Remove reads the film first, it is kept in the audit log.
+func (u *filmsUsecase) Remove(id int, ac domain.AuditContext) error {
 	if id <= 0 {
 		return domain.ErrNotFound
 	}
 
-	err := u.filmsRepo.Delete(id)
+	film, err := u.filmsRepo.SelectById(id)
 	if err != nil {
 		logs.LogError(logs.Logger, "films/usecase", "Remove", err, err.Error())
 		return err
 	}
 
+	err = u.filmsRepo.Delete(id)
+	if err != nil {
+		logs.LogError(logs.Logger, "films/usecase", "Remove", err, err.Error())
+		return err
+	}
+	u.audit.Record(ac, domain.AuditDelete, domain.AuditFilm, id, film, nil)
+	u.events.Emit(domain.FilmDeleted, film)
+
 	return nil
 }
 
-func (u *filmsUsecase) Modify(newFilm domain.Film) (domain.Film, error) {
+func (u *filmsUsecase) GetById(id int) (domain.Film, error) {
+	if id <= 0 {
+		return domain.Film{}, domain.ErrNotFound
+	}
+
+	film, err := u.filmsRepo.SelectById(id)
+	if err != nil {
+		logs.LogError(logs.Logger, "films/usecase", "GetById", err, err.Error())
+		return domain.Film{}, err
+	}
+
+	return film, nil
+}
+
+func (u *filmsUsecase) Modify(newFilm domain.Film, ac domain.AuditContext) (domain.Film, error) {
 	if newFilm.ID <= 0 {
 		return domain.Film{}, domain.ErrNotFound
 	}
@@ -83,18 +115,171 @@ func (u *filmsUsecase) Modify(newFilm domain.Film) (domain.Film, error) {
 		return domain.Film{}, err
 	}
 	logs.Logger.Debug("films/usecase Modify old actor:\n", oldFilm)
+	if newFilm.Version != 0 && newFilm.Version != oldFilm.Version {
+		return domain.Film{}, domain.ErrPreconditionFailed
+	}
 
 	newFilm = getOldFields(newFilm, oldFilm)
-	updatedActor, err := u.filmsRepo.Update(newFilm)
+	updatedActor, err := u.filmsRepo.Update(newFilm, ac.UserID)
 	if err != nil {
 		logs.LogError(logs.Logger, "films/usecase", "Modify", err, err.Error())
 		return domain.Film{}, err
 	}
 	logs.Logger.Debug("films/usecase Modify updated actor:\n", updatedActor)
 
+	// actors aren`t changed here, the ones from the request are just
+	// passed through
+	after := updatedActor
+	after.Actors = nil
+	u.audit.Record(ac, domain.AuditUpdate, domain.AuditFilm, after.ID, oldFilm, after)
+	u.events.Emit(domain.FilmUpdated, after)
+
 	return updatedActor, nil
 }
 
+// Patch updates the film on condition that it is still of the version the
+// patch was applied to, so concurrent changes aren`t lost.
+func (u *filmsUsecase) Patch(id int, patch domain.Patch, ac domain.AuditContext) (domain.Film, error) {
+	if id <= 0 {
+		return domain.Film{}, domain.ErrNotFound
+	}
+
+	oldFilm, err := u.filmsRepo.SelectById(id)
+	if err != nil {
+		logs.LogError(logs.Logger, "films/usecase", "Patch", err, err.Error())
+		return domain.Film{}, err
+	}
+	if patch.Version != 0 && patch.Version != oldFilm.Version {
+		return domain.Film{}, domain.ErrPreconditionFailed
+	}
+
+	newFilm := oldFilm
+	if err = patch.ApplyTo(&newFilm, "title", "description", "releaseDate", "rating"); err != nil {
+		logs.LogError(logs.Logger, "films/usecase", "Patch", err, err.Error())
+		return domain.Film{}, err
+	}
+	if err = newFilm.Validate(); err != nil {
+		logs.LogError(logs.Logger, "films/usecase", "Patch", err, err.Error())
+		return domain.Film{}, err
+	}
+
+	updated, err := u.filmsRepo.Update(newFilm, ac.UserID)
+	if err != nil {
+		logs.LogError(logs.Logger, "films/usecase", "Patch", err, err.Error())
+		return domain.Film{}, err
+	}
+	u.audit.Record(ac, domain.AuditUpdate, domain.AuditFilm, id, oldFilm, updated)
+	u.events.Emit(domain.FilmUpdated, updated)
+
+	return updated, nil
+}
+
+func (u *filmsUsecase) GetRevisions(filmID int) ([]domain.FilmRevision, error) {
+	if filmID <= 0 {
+		return nil, domain.ErrNotFound
+	}
+
+	revisions, err := u.filmsRepo.SelectRevisions(filmID)
+	if err != nil {
+		logs.LogError(logs.Logger, "films/usecase", "GetRevisions", err, err.Error())
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+func (u *filmsUsecase) DiffRevisions(filmID, from, to int) (domain.RevisionDiff, error) {
+	if filmID <= 0 || from <= 0 || to <= 0 {
+		return domain.RevisionDiff{}, domain.ErrBadRequest
+	}
+
+	fromRev, err := u.filmsRepo.SelectRevision(filmID, from)
+	if err != nil {
+		logs.LogError(logs.Logger, "films/usecase", "DiffRevisions", err, err.Error())
+		return domain.RevisionDiff{}, err
+	}
+	toRev, err := u.filmsRepo.SelectRevision(filmID, to)
+	if err != nil {
+		logs.LogError(logs.Logger, "films/usecase", "DiffRevisions", err, err.Error())
+		return domain.RevisionDiff{}, err
+	}
+
+	changes, err := domain.Diff(fromRev.Film, toRev.Film)
+	if err != nil {
+		logs.LogError(logs.Logger, "films/usecase", "DiffRevisions", err, err.Error())
+		return domain.RevisionDiff{}, domain.ErrInternalServerError
+	}
+
+	return domain.RevisionDiff{From: from, To: to, Changes: changes}, nil
+}
+
+// Revert reads the film first, the state it replaces is kept in the audit log.
+func (u *filmsUsecase) Revert(filmID, revision, version int, ac domain.AuditContext) (domain.Film, error) {
+	if filmID <= 0 || revision <= 0 {
+		return domain.Film{}, domain.ErrNotFound
+	}
+
+	oldFilm, err := u.filmsRepo.SelectById(filmID)
+	if err != nil {
+		logs.LogError(logs.Logger, "films/usecase", "Revert", err, err.Error())
+		return domain.Film{}, err
+	}
+	if version != 0 && version != oldFilm.Version {
+		return domain.Film{}, domain.ErrPreconditionFailed
+	}
+
+	reverted, err := u.filmsRepo.Revert(filmID, revision, version, ac.UserID)
+	if err != nil {
+		logs.LogError(logs.Logger, "films/usecase", "Revert", err, err.Error())
+		return domain.Film{}, err
+	}
+	u.audit.Record(ac, domain.AuditUpdate, domain.AuditFilm, filmID, oldFilm, reverted)
+	u.events.Emit(domain.FilmUpdated, reverted)
+
+	return reverted, nil
+}
+
+func (u *filmsUsecase) GetDeleted() ([]domain.Film, error) {
+	films, err := u.filmsRepo.SelectDeleted()
+	if err != nil {
+		logs.LogError(logs.Logger, "films/usecase", "GetDeleted", err, err.Error())
+		return nil, err
+	}
+
+	return films, nil
+}
+
+func (u *filmsUsecase) Restore(id int, ac domain.AuditContext) (domain.Film, error) {
+	if id <= 0 {
+		return domain.Film{}, domain.ErrNotFound
+	}
+
+	film, err := u.filmsRepo.Restore(id)
+	if err != nil {
+		logs.LogError(logs.Logger, "films/usecase", "Restore", err, err.Error())
+		return domain.Film{}, err
+	}
+	u.audit.Record(ac, domain.AuditRestore, domain.AuditFilm, id, nil, film)
+	u.events.Emit(domain.FilmRestored, film)
+
+	return film, nil
+}
+
+// Purge does nothing for a non-positive retention, the trash is kept forever then.
+func (u *filmsUsecase) Purge(retention time.Duration) (int, error) {
+	if retention <= 0 {
+		return 0, nil
+	}
+
+	count, err := u.filmsRepo.Purge(time.Now().Add(-retention))
+	if err != nil {
+		logs.LogError(logs.Logger, "films/usecase", "Purge", err, err.Error())
+		return 0, err
+	}
+
+	return count, nil
+}
+
 func getOldFields(newFilm, oldFilm domain.Film) domain.Film {
 	if newFilm.Title == "" {
 		newFilm.Title = oldFilm.Title
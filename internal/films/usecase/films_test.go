@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"testing"
+	"time"
 )
 
 func TestAdd(t *testing.T) {
@@ -33,7 +34,7 @@ func TestAdd(t *testing.T) {
 				}
 			},
 			setFilmsRepoExpectations: func(filmsRepo *mocks.FilmsRepository, id int, err error) {
-				filmsRepo.On("Insert", mock.Anything).Return(id, err)
+				filmsRepo.On("Insert", mock.Anything, ac.UserID).Return(id, err)
 			},
 			expectedID:    1,
 			expectedError: nil,
@@ -44,7 +45,7 @@ func TestAdd(t *testing.T) {
 				return domain.Film{}
 			},
 			setFilmsRepoExpectations: func(filmsRepo *mocks.FilmsRepository, id int, err error) {
-				filmsRepo.On("Insert", mock.Anything).Return(id, err).Maybe()
+				filmsRepo.On("Insert", mock.Anything, ac.UserID).Return(id, err).Maybe()
 			},
 			expectedID:    0,
 			expectedError: domain.ErrBadRequest,
@@ -62,7 +63,7 @@ func TestAdd(t *testing.T) {
 				}
 			},
 			setFilmsRepoExpectations: func(filmsRepo *mocks.FilmsRepository, id int, err error) {
-				filmsRepo.On("Insert", mock.Anything).Return(id, err).Maybe()
+				filmsRepo.On("Insert", mock.Anything, ac.UserID).Return(id, err).Maybe()
 			},
 			expectedID:    0,
 			expectedError: domain.ErrBadRequest,
@@ -80,7 +81,7 @@ func TestAdd(t *testing.T) {
 				}
 			},
 			setFilmsRepoExpectations: func(filmsRepo *mocks.FilmsRepository, id int, err error) {
-				filmsRepo.On("Insert", mock.Anything).Return(id, err).Maybe()
+				filmsRepo.On("Insert", mock.Anything, ac.UserID).Return(id, err).Maybe()
 			},
 			expectedID:    0,
 			expectedError: domain.ErrBadRequest,
@@ -98,7 +99,7 @@ func TestAdd(t *testing.T) {
 				}
 			},
 			setFilmsRepoExpectations: func(filmsRepo *mocks.FilmsRepository, id int, err error) {
-				filmsRepo.On("Insert", mock.Anything).Return(id, err).Maybe()
+				filmsRepo.On("Insert", mock.Anything, ac.UserID).Return(id, err).Maybe()
 			},
 			expectedID:    0,
 			expectedError: domain.ErrBadRequest,
@@ -116,7 +117,7 @@ func TestAdd(t *testing.T) {
 				}
 			},
 			setFilmsRepoExpectations: func(filmsRepo *mocks.FilmsRepository, id int, err error) {
-				filmsRepo.On("Insert", mock.Anything).Return(id, err).Maybe()
+				filmsRepo.On("Insert", mock.Anything, ac.UserID).Return(id, err).Maybe()
 			},
 			expectedID:    0,
 			expectedError: domain.ErrBadRequest,
@@ -135,7 +136,7 @@ func TestAdd(t *testing.T) {
 				}
 			},
 			setFilmsRepoExpectations: func(filmsRepo *mocks.FilmsRepository, id int, err error) {
-				filmsRepo.On("Insert", mock.Anything).Return(id, err)
+				filmsRepo.On("Insert", mock.Anything, ac.UserID).Return(id, err)
 			},
 			expectedID:    0,
 			expectedError: domain.ErrBadRequest,
@@ -154,7 +155,7 @@ func TestAdd(t *testing.T) {
 				}
 			},
 			setFilmsRepoExpectations: func(filmsRepo *mocks.FilmsRepository, id int, err error) {
-				filmsRepo.On("Insert", mock.Anything).Return(id, err)
+				filmsRepo.On("Insert", mock.Anything, ac.UserID).Return(id, err)
 			},
 			expectedID:    0,
 			expectedError: domain.ErrBadRequest,
@@ -166,8 +167,8 @@ func TestAdd(t *testing.T) {
 			filmsRepo := new(mocks.FilmsRepository)
 			test.setFilmsRepoExpectations(filmsRepo, test.expectedID, test.expectedError)
 
-			filmsUsecase := usecase.NewFilmsUsecase(filmsRepo)
-			id, err := filmsUsecase.Add(test.getFilm())
+			filmsUsecase := usecase.NewFilmsUsecase(filmsRepo, allowingAudit(), allowingEvents())
+			id, err := filmsUsecase.Add(test.getFilm(), ac)
 
 			assert.Equal(t, test.expectedID, id)
 			assert.Equal(t, test.expectedError, err)
@@ -290,7 +291,7 @@ func TestGetAll(t *testing.T) {
 			filmsRepo := new(mocks.FilmsRepository)
 			test.setFilmsRepoExpectations(filmsRepo, test.getFilms(), test.expectedError)
 
-			filmsUsecase := usecase.NewFilmsUsecase(filmsRepo)
+			filmsUsecase := usecase.NewFilmsUsecase(filmsRepo, allowingAudit(), allowingEvents())
 			films, err := filmsUsecase.GetAll(test.titleDir, test.releaseDateDir)
 
 			assert.Equal(t, test.getFilms(), films)
@@ -354,7 +355,7 @@ func TestSearch(t *testing.T) {
 			filmsRepo := new(mocks.FilmsRepository)
 			test.setFilmsRepoExpectations(filmsRepo, test.getFilms(), test.expectedError)
 
-			filmsUsecase := usecase.NewFilmsUsecase(filmsRepo)
+			filmsUsecase := usecase.NewFilmsUsecase(filmsRepo, allowingAudit(), allowingEvents())
 			films, err := filmsUsecase.Search(test.searchStr)
 
 			assert.Equal(t, test.getFilms(), films)
@@ -376,6 +377,7 @@ func TestRemove(t *testing.T) {
 			name: "GoodCase/Common",
 			id:   1,
 			setFilmsRepoExpectations: func(filmsRepo *mocks.FilmsRepository, err error) {
+				filmsRepo.On("SelectById", 1).Return(domain.Film{ID: 1}, nil)
 				filmsRepo.On("Delete", 1).Return(err)
 			},
 			expectedError: nil,
@@ -396,10 +398,19 @@ func TestRemove(t *testing.T) {
 			},
 			expectedError: domain.ErrNotFound,
 		},
+		{
+			name: "BadCase/NotFound",
+			id:   3,
+			setFilmsRepoExpectations: func(filmsRepo *mocks.FilmsRepository, err error) {
+				filmsRepo.On("SelectById", 3).Return(domain.Film{}, domain.ErrNotFound)
+			},
+			expectedError: domain.ErrNotFound,
+		},
 		{
 			name: "BadCase/RepoError",
 			id:   2,
 			setFilmsRepoExpectations: func(filmsRepo *mocks.FilmsRepository, err error) {
+				filmsRepo.On("SelectById", 2).Return(domain.Film{ID: 2}, nil)
 				filmsRepo.On("Delete", 2).Return(errors.New("repository error"))
 			},
 			expectedError: errors.New("repository error"),
@@ -411,8 +422,8 @@ func TestRemove(t *testing.T) {
 			filmsRepo := new(mocks.FilmsRepository)
 			test.setFilmsRepoExpectations(filmsRepo, test.expectedError)
 
-			filmsUsecase := usecase.NewFilmsUsecase(filmsRepo)
-			err := filmsUsecase.Remove(test.id)
+			filmsUsecase := usecase.NewFilmsUsecase(filmsRepo, allowingAudit(), allowingEvents())
+			err := filmsUsecase.Remove(test.id, ac)
 
 			assert.Equal(t, test.expectedError, err)
 
@@ -445,7 +456,7 @@ func TestModify(t *testing.T) {
 			},
 			setFilmsRepoExpectations: func(filmsRepo *mocks.FilmsRepository, oldFilm domain.Film, updatedFilm domain.Film, err error) {
 				filmsRepo.On("SelectById", 1).Return(oldFilm, err)
-				filmsRepo.On("Update", mock.Anything).Return(updatedFilm, err)
+				filmsRepo.On("Update", mock.Anything, ac.UserID).Return(updatedFilm, err)
 			},
 			getExpectedFilm: func() domain.Film {
 				var d pgtype.Date
@@ -502,8 +513,8 @@ func TestModify(t *testing.T) {
 			filmsRepo := new(mocks.FilmsRepository)
 			test.setFilmsRepoExpectations(filmsRepo, test.getExpectedFilm(), test.getNewFilm(), test.expectedError)
 
-			filmsUsecase := usecase.NewFilmsUsecase(filmsRepo)
-			updatedFilm, err := filmsUsecase.Modify(test.getNewFilm())
+			filmsUsecase := usecase.NewFilmsUsecase(filmsRepo, allowingAudit(), allowingEvents())
+			updatedFilm, err := filmsUsecase.Modify(test.getNewFilm(), ac)
 
 			assert.Equal(t, test.getExpectedFilm(), updatedFilm)
 			assert.Equal(t, test.expectedError, err)
@@ -512,3 +523,317 @@ func TestModify(t *testing.T) {
 		})
 	}
 }
+
+var ac = domain.AuditContext{UserID: 1, RequestID: "request"}
+
+func allowingAudit() *mocks.AuditUsecase {
+	audit := new(mocks.AuditUsecase)
+	audit.On("Record", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	return audit
+}
+
+func allowingEvents() *mocks.EventEmitter {
+	events := new(mocks.EventEmitter)
+	events.On("Emit", mock.Anything, mock.Anything).Maybe()
+	return events
+}
+
+func TestAudit(t *testing.T) {
+	filmsRepo := new(mocks.FilmsRepository)
+	audit := new(mocks.AuditUsecase)
+	old := domain.Film{ID: 1}
+	filmsRepo.On("SelectById", 1).Return(old, nil)
+	filmsRepo.On("Delete", 1).Return(nil)
+	audit.On("Record", ac, domain.AuditDelete, domain.AuditFilm, 1, old, nil).Once()
+
+	err := usecase.NewFilmsUsecase(filmsRepo, audit, allowingEvents()).Remove(1, ac)
+
+	assert.NoError(t, err)
+	filmsRepo.AssertExpectations(t)
+	audit.AssertExpectations(t)
+}
+
+func TestEvents(t *testing.T) {
+	filmsRepo := new(mocks.FilmsRepository)
+	events := new(mocks.EventEmitter)
+	old := domain.Film{ID: 1, Title: "Matrix"}
+	filmsRepo.On("SelectById", 1).Return(old, nil)
+	filmsRepo.On("Delete", 1).Return(nil)
+	events.On("Emit", domain.FilmDeleted, old).Once()
+
+	err := usecase.NewFilmsUsecase(filmsRepo, allowingAudit(), events).Remove(1, ac)
+
+	assert.NoError(t, err)
+	filmsRepo.AssertExpectations(t)
+	events.AssertExpectations(t)
+}
+
+func TestDiffRevisions(t *testing.T) {
+	tests := []struct {
+		name                string
+		from, to            int
+		setRepoExpectations func(filmsRepo *mocks.FilmsRepository)
+		changes             map[string]domain.FieldChange
+		err                 error
+	}{
+		{
+			name: "GoodCase/Common",
+			from: 1,
+			to:   2,
+			setRepoExpectations: func(filmsRepo *mocks.FilmsRepository) {
+				filmsRepo.On("SelectRevision", 1, 1).
+					Return(domain.FilmRevision{Revision: 1, Film: domain.Film{ID: 1, Title: "Matrix", Rating: 8}}, nil)
+				filmsRepo.On("SelectRevision", 1, 2).
+					Return(domain.FilmRevision{Revision: 2, Film: domain.Film{ID: 1, Title: "Matrix", Rating: 9}}, nil)
+			},
+			changes: map[string]domain.FieldChange{"rating": {Before: float64(8), After: float64(9)}},
+		},
+		{
+			name:                "BadCase/InvalidRevision",
+			from:                0,
+			to:                  2,
+			setRepoExpectations: func(filmsRepo *mocks.FilmsRepository) {},
+			err:                 domain.ErrBadRequest,
+		},
+		{
+			name: "BadCase/NotFound",
+			from: 1,
+			to:   5,
+			setRepoExpectations: func(filmsRepo *mocks.FilmsRepository) {
+				filmsRepo.On("SelectRevision", 1, 1).Return(domain.FilmRevision{Revision: 1}, nil)
+				filmsRepo.On("SelectRevision", 1, 5).Return(domain.FilmRevision{}, domain.ErrNotFound)
+			},
+			err: domain.ErrNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filmsRepo := new(mocks.FilmsRepository)
+			test.setRepoExpectations(filmsRepo)
+
+			diff, err := usecase.NewFilmsUsecase(filmsRepo, allowingAudit(), allowingEvents()).DiffRevisions(1, test.from, test.to)
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.changes, diff.Changes)
+			filmsRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRevert(t *testing.T) {
+	filmsRepo := new(mocks.FilmsRepository)
+	audit := new(mocks.AuditUsecase)
+	current := domain.Film{ID: 1, Title: "Matrix Reloaded"}
+	reverted := domain.Film{ID: 1, Title: "Matrix"}
+	filmsRepo.On("SelectById", 1).Return(current, nil)
+	filmsRepo.On("Revert", 1, 2, 0, ac.UserID).Return(reverted, nil)
+	audit.On("Record", ac, domain.AuditUpdate, domain.AuditFilm, 1, current, reverted).Once()
+
+	film, err := usecase.NewFilmsUsecase(filmsRepo, audit, allowingEvents()).Revert(1, 2, 0, ac)
+
+	assert.NoError(t, err)
+	assert.Equal(t, reverted, film)
+	filmsRepo.AssertExpectations(t)
+	audit.AssertExpectations(t)
+}
+
+func TestRestore(t *testing.T) {
+	tests := []struct {
+		name                string
+		id                  int
+		setRepoExpectations func(filmsRepo *mocks.FilmsRepository, audit *mocks.AuditUsecase)
+		err                 error
+	}{
+		{
+			name: "GoodCase/Common",
+			id:   1,
+			setRepoExpectations: func(filmsRepo *mocks.FilmsRepository, audit *mocks.AuditUsecase) {
+				filmsRepo.On("Restore", 1).Return(domain.Film{ID: 1, Title: "Matrix"}, nil)
+				audit.On("Record", ac, domain.AuditRestore, domain.AuditFilm, 1, nil, domain.Film{ID: 1, Title: "Matrix"}).Once()
+			},
+		},
+		{
+			name:                "BadCase/InvalidID",
+			id:                  0,
+			setRepoExpectations: func(filmsRepo *mocks.FilmsRepository, audit *mocks.AuditUsecase) {},
+			err:                 domain.ErrNotFound,
+		},
+		{
+			name: "BadCase/NotInTrash",
+			id:   2,
+			setRepoExpectations: func(filmsRepo *mocks.FilmsRepository, audit *mocks.AuditUsecase) {
+				filmsRepo.On("Restore", 2).Return(domain.Film{}, domain.ErrNotFound)
+			},
+			err: domain.ErrNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filmsRepo := new(mocks.FilmsRepository)
+			audit := new(mocks.AuditUsecase)
+			test.setRepoExpectations(filmsRepo, audit)
+
+			_, err := usecase.NewFilmsUsecase(filmsRepo, audit, allowingEvents()).Restore(test.id, ac)
+
+			assert.Equal(t, test.err, err)
+			filmsRepo.AssertExpectations(t)
+			audit.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPurge(t *testing.T) {
+	filmsRepo := new(mocks.FilmsRepository)
+	filmsRepo.On("Purge", mock.MatchedBy(func(before time.Time) bool {
+		return time.Since(before) >= time.Hour && time.Since(before) < time.Hour+time.Minute
+	})).Return(2, nil).Once()
+	filmsUsecase := usecase.NewFilmsUsecase(filmsRepo, allowingAudit(), allowingEvents())
+
+	count, err := filmsUsecase.Purge(time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	count, err = filmsUsecase.Purge(0)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+	filmsRepo.AssertExpectations(t)
+}
+
+func TestModifyVersion(t *testing.T) {
+	tests := []struct {
+		name                string
+		version             int
+		setRepoExpectations func(filmsRepo *mocks.FilmsRepository)
+		err                 error
+	}{
+		{
+			name:    "GoodCase/Matching",
+			version: 3,
+			setRepoExpectations: func(filmsRepo *mocks.FilmsRepository) {
+				filmsRepo.On("Update", domain.Film{ID: 1, Title: "Matrix", Version: 3}, ac.UserID).
+					Return(domain.Film{ID: 1, Title: "Matrix", Version: 4}, nil)
+			},
+		},
+		{
+			name:    "GoodCase/Unconditional",
+			version: 0,
+			setRepoExpectations: func(filmsRepo *mocks.FilmsRepository) {
+				filmsRepo.On("Update", domain.Film{ID: 1, Title: "Matrix"}, ac.UserID).
+					Return(domain.Film{ID: 1, Title: "Matrix", Version: 4}, nil)
+			},
+		},
+		{
+			name:                "BadCase/Stale",
+			version:             2,
+			setRepoExpectations: func(filmsRepo *mocks.FilmsRepository) {},
+			err:                 domain.ErrPreconditionFailed,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filmsRepo := new(mocks.FilmsRepository)
+			filmsRepo.On("SelectById", 1).Return(domain.Film{ID: 1, Title: "Old", Version: 3}, nil)
+			test.setRepoExpectations(filmsRepo)
+
+			_, err := usecase.NewFilmsUsecase(filmsRepo, allowingAudit(), allowingEvents()).
+				Modify(domain.Film{ID: 1, Title: "Matrix", Version: test.version}, ac)
+
+			assert.Equal(t, test.err, err)
+			filmsRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPatch(t *testing.T) {
+	var d pgtype.Date
+	d.Scan("1999-03-31")
+	old := domain.Film{ID: 1, Title: "Matrix", Description: "desc", ReleaseDate: d, Rating: 8.5, Version: 3}
+
+	tests := []struct {
+		name    string
+		patch   domain.Patch
+		updated *domain.Film
+		err     error
+	}{
+		{
+			name:    "GoodCase/MergeZeroRating",
+			patch:   domain.Patch{Type: domain.MergePatch, Body: []byte(`{"rating": 0}`)},
+			updated: &domain.Film{ID: 1, Title: "Matrix", Description: "desc", ReleaseDate: d, Rating: 0, Version: 3},
+		},
+		{
+			name:    "GoodCase/MergeEmptyDescription",
+			patch:   domain.Patch{Type: domain.MergePatch, Body: []byte(`{"description": ""}`), Version: 3},
+			updated: &domain.Film{ID: 1, Title: "Matrix", Description: "", ReleaseDate: d, Rating: 8.5, Version: 3},
+		},
+		{
+			name: "GoodCase/JSONPatch",
+			patch: domain.Patch{Type: domain.JSONPatch,
+				Body: []byte(`[{"op": "test", "path": "/rating", "value": 8.5}, {"op": "replace", "path": "/title", "value": "The Matrix"}]`)},
+			updated: &domain.Film{ID: 1, Title: "The Matrix", Description: "desc", ReleaseDate: d, Rating: 8.5, Version: 3},
+		},
+		{
+			name:  "BadCase/RemoveTitle",
+			patch: domain.Patch{Type: domain.MergePatch, Body: []byte(`{"title": null}`)},
+			err:   domain.ErrBadRequest,
+		},
+		{
+			name:  "BadCase/ChangeID",
+			patch: domain.Patch{Type: domain.MergePatch, Body: []byte(`{"id": 2}`)},
+			err:   domain.ErrBadRequest,
+		},
+		{
+			name:  "BadCase/UnknownField",
+			patch: domain.Patch{Type: domain.MergePatch, Body: []byte(`{"budget": 63000000}`)},
+			err:   domain.ErrBadRequest,
+		},
+		{
+			name:  "BadCase/RatingOutOfRange",
+			patch: domain.Patch{Type: domain.MergePatch, Body: []byte(`{"rating": 11}`)},
+			err:   domain.ErrBadRequest,
+		},
+		{
+			name:  "BadCase/FutureReleaseDate",
+			patch: domain.Patch{Type: domain.MergePatch, Body: []byte(`{"releaseDate": "2999-01-01"}`)},
+			err:   domain.ErrBadRequest,
+		},
+		{
+			name:  "BadCase/WrongType",
+			patch: domain.Patch{Type: domain.MergePatch, Body: []byte(`{"rating": "high"}`)},
+			err:   domain.ErrBadRequest,
+		},
+		{
+			name: "BadCase/TestFailed",
+			patch: domain.Patch{Type: domain.JSONPatch,
+				Body: []byte(`[{"op": "test", "path": "/rating", "value": 7}, {"op": "replace", "path": "/rating", "value": 9}]`)},
+			err: domain.ErrPreconditionFailed,
+		},
+		{
+			name:  "BadCase/StaleVersion",
+			patch: domain.Patch{Type: domain.MergePatch, Body: []byte(`{"rating": 9}`), Version: 2},
+			err:   domain.ErrPreconditionFailed,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filmsRepo := new(mocks.FilmsRepository)
+			filmsRepo.On("SelectById", 1).Return(old, nil)
+			if test.updated != nil {
+				updated := *test.updated
+				updated.Version++
+				filmsRepo.On("Update", *test.updated, ac.UserID).Return(updated, nil)
+			}
+
+			film, err := usecase.NewFilmsUsecase(filmsRepo, allowingAudit(), allowingEvents()).Patch(1, test.patch, ac)
+
+			assert.ErrorIs(t, err, test.err)
+			if test.updated != nil {
+				assert.Equal(t, test.updated.Version+1, film.Version)
+			}
+			filmsRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+)
+
+const idempotencyKeyPrefix = "idempotency:"
+
+type idempotencyRedisRepository struct {
+	client *redis.Client
+}
+
+func NewIdempotencyRedisRepository(client *redis.Client) domain.IdempotencyRepository {
+	return &idempotencyRedisRepository{client}
+}
+
+func (r *idempotencyRedisRepository) Reserve(key string, pending domain.IdempotentResponse,
+	ttl time.Duration) (domain.IdempotentResponse, bool, error) {
+	ctx := context.Background()
+	data, err := json.Marshal(pending)
+	if err != nil {
+		return domain.IdempotentResponse{}, false, err
+	}
+
+	ok, err := r.client.SetNX(ctx, idempotencyKeyPrefix+key, data, ttl).Result()
+	if err != nil || ok {
+		return domain.IdempotentResponse{}, ok, err
+	}
+
+	res, err := r.client.Get(ctx, idempotencyKeyPrefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		// expired right after the reserve failed, the client retries
+		return domain.IdempotentResponse{}, false, domain.ErrRequestInProgress
+	}
+	if err != nil {
+		return domain.IdempotentResponse{}, false, err
+	}
+
+	var stored domain.IdempotentResponse
+	if err = json.Unmarshal(res, &stored); err != nil {
+		return domain.IdempotentResponse{}, false, err
+	}
+
+	return stored, false, nil
+}
+
+func (r *idempotencyRedisRepository) Save(key string, response domain.IdempotentResponse, ttl time.Duration) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+
+	return r.client.Set(context.Background(), idempotencyKeyPrefix+key, data, ttl).Err()
+}
+
+func (r *idempotencyRedisRepository) Extend(key string, ttl time.Duration) error {
+	return r.client.Expire(context.Background(), idempotencyKeyPrefix+key, ttl).Err()
+}
+
+func (r *idempotencyRedisRepository) Release(key string) error {
+	return r.client.Del(context.Background(), idempotencyKeyPrefix+key).Err()
+}
@@ -0,0 +1,94 @@
+package redis_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	"github.com/ellexo2456/FilmLib/internal/idempotency/repository/redis"
+)
+
+func TestReserve(t *testing.T) {
+	pending := domain.IdempotentResponse{Hash: "hash"}
+	pendingData, _ := json.Marshal(pending)
+	stored := domain.IdempotentResponse{Hash: "hash", Status: http.StatusOK,
+		Header: http.Header{"Content-Type": {"application/json"}}, Body: []byte(`{"body":{"id":1}}`)}
+	storedData, _ := json.Marshal(stored)
+
+	tests := []struct {
+		name      string
+		setExpect func(mock redismock.ClientMock)
+		stored    domain.IdempotentResponse
+		reserved  bool
+		err       error
+	}{
+		{
+			name: "GoodCase/Reserved",
+			setExpect: func(mock redismock.ClientMock) {
+				mock.ExpectSetNX("idempotency:1:key", pendingData, time.Hour).SetVal(true)
+			},
+			reserved: true,
+		},
+		{
+			name: "GoodCase/Stored",
+			setExpect: func(mock redismock.ClientMock) {
+				mock.ExpectSetNX("idempotency:1:key", pendingData, time.Hour).SetVal(false)
+				mock.ExpectGet("idempotency:1:key").SetVal(string(storedData))
+			},
+			stored: stored,
+		},
+		{
+			name: "BadCase/Expired",
+			setExpect: func(mock redismock.ClientMock) {
+				mock.ExpectSetNX("idempotency:1:key", pendingData, time.Hour).SetVal(false)
+				mock.ExpectGet("idempotency:1:key").RedisNil()
+			},
+			err: domain.ErrRequestInProgress,
+		},
+		{
+			name: "BadCase/RedisError",
+			setExpect: func(mock redismock.ClientMock) {
+				mock.ExpectSetNX("idempotency:1:key", pendingData, time.Hour).SetErr(errors.New("redis is down"))
+			},
+			err: errors.New("redis is down"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock := redismock.NewClientMock()
+			defer db.Close()
+			test.setExpect(mock)
+
+			res, reserved, err := redis.NewIdempotencyRedisRepository(db).Reserve("1:key", pending, time.Hour)
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.reserved, reserved)
+			assert.Equal(t, test.stored, res)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSaveAndRelease(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	defer db.Close()
+	r := redis.NewIdempotencyRedisRepository(db)
+
+	response := domain.IdempotentResponse{Hash: "hash", Status: http.StatusCreated, Body: []byte(`{}`)}
+	data, _ := json.Marshal(response)
+	mock.ExpectSet("idempotency:1:key", data, time.Hour).SetVal("OK")
+	mock.ExpectExpire("idempotency:1:key", domain.IdempotencyLease).SetVal(true)
+	mock.ExpectDel("idempotency:1:key").SetVal(1)
+
+	assert.NoError(t, r.Save("1:key", response, time.Hour))
+	assert.NoError(t, r.Extend("1:key", domain.IdempotencyLease))
+	assert.NoError(t, r.Release("1:key"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+)
+
+type idempotencyUsecase struct {
+	idempotencyRepo domain.IdempotencyRepository
+	ttl             time.Duration
+}
+
+func NewIdempotencyUsecase(ir domain.IdempotencyRepository, ttl time.Duration) domain.IdempotencyUsecase {
+	return &idempotencyUsecase{
+		idempotencyRepo: ir,
+		ttl:             ttl,
+	}
+}
+
+func (u *idempotencyUsecase) Begin(userID int, key, hash string) (*domain.IdempotentResponse, error) {
+	if key == "" || len(key) > domain.MaxIdempotencyKeyLength {
+		return nil, domain.ErrBadRequest
+	}
+
+	stored, ok, err := u.idempotencyRepo.Reserve(userKey(userID, key), domain.IdempotentResponse{Hash: hash}, min(domain.IdempotencyLease, u.ttl))
+	if err != nil {
+		logs.LogError(logs.Logger, "idempotency/usecase", "Begin", err, err.Error())
+		return nil, err
+	}
+	if ok {
+		return nil, nil
+	}
+
+	if stored.Hash != hash {
+		return nil, domain.ErrKeyReused
+	}
+	if stored.Status == 0 {
+		return nil, domain.ErrRequestInProgress
+	}
+	if stored.TooLarge {
+		return nil, domain.ErrResponseTooLarge
+	}
+
+	return &stored, nil
+}
+
+func (u *idempotencyUsecase) Extend(userID int, key string) error {
+	if err := u.idempotencyRepo.Extend(userKey(userID, key), min(domain.IdempotencyLease, u.ttl)); err != nil {
+		logs.LogError(logs.Logger, "idempotency/usecase", "Extend", err, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (u *idempotencyUsecase) Finish(userID int, key string, response domain.IdempotentResponse) error {
+	// the request of a response too large to store has been done, so the
+	// key is kept to stop the retries from doing it again
+	if len(response.Body) > domain.MaxIdempotentResponseSize {
+		response = domain.IdempotentResponse{Hash: response.Hash, Status: response.Status, TooLarge: true}
+	}
+
+	var err error
+	if response.Status >= http.StatusInternalServerError {
+		err = u.idempotencyRepo.Release(userKey(userID, key))
+	} else {
+		err = u.idempotencyRepo.Save(userKey(userID, key), response, u.ttl)
+	}
+	if err != nil {
+		logs.LogError(logs.Logger, "idempotency/usecase", "Finish", err, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// the keys are generated by the clients, so they are kept per user
+func userKey(userID int, key string) string {
+	return strconv.Itoa(userID) + ":" + key
+}
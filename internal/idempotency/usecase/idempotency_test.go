@@ -0,0 +1,152 @@
+package usecase_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	"github.com/ellexo2456/FilmLib/internal/domain/mocks"
+	"github.com/ellexo2456/FilmLib/internal/idempotency/usecase"
+)
+
+func TestBegin(t *testing.T) {
+	stored := domain.IdempotentResponse{Hash: "hash", Status: http.StatusOK, Body: []byte(`{"body":{"id":1}}`)}
+
+	tests := []struct {
+		name                string
+		key                 string
+		setRepoExpectations func(repo *mocks.IdempotencyRepository)
+		response            *domain.IdempotentResponse
+		err                 error
+	}{
+		{
+			name: "GoodCase/First",
+			key:  "key",
+			setRepoExpectations: func(repo *mocks.IdempotencyRepository) {
+				repo.On("Reserve", "1:key", domain.IdempotentResponse{Hash: "hash"}, domain.IdempotencyLease).
+					Return(domain.IdempotentResponse{}, true, nil)
+			},
+		},
+		{
+			name: "GoodCase/Replay",
+			key:  "key",
+			setRepoExpectations: func(repo *mocks.IdempotencyRepository) {
+				repo.On("Reserve", "1:key", domain.IdempotentResponse{Hash: "hash"}, domain.IdempotencyLease).
+					Return(stored, false, nil)
+			},
+			response: &stored,
+		},
+		{
+			name: "BadCase/AnotherRequest",
+			key:  "key",
+			setRepoExpectations: func(repo *mocks.IdempotencyRepository) {
+				repo.On("Reserve", "1:key", domain.IdempotentResponse{Hash: "hash"}, domain.IdempotencyLease).
+					Return(domain.IdempotentResponse{Hash: "other", Status: http.StatusOK}, false, nil)
+			},
+			err: domain.ErrKeyReused,
+		},
+		{
+			name: "BadCase/InProgress",
+			key:  "key",
+			setRepoExpectations: func(repo *mocks.IdempotencyRepository) {
+				repo.On("Reserve", "1:key", domain.IdempotentResponse{Hash: "hash"}, domain.IdempotencyLease).
+					Return(domain.IdempotentResponse{Hash: "hash"}, false, nil)
+			},
+			err: domain.ErrRequestInProgress,
+		},
+		{
+			name: "BadCase/TooLarge",
+			key:  "key",
+			setRepoExpectations: func(repo *mocks.IdempotencyRepository) {
+				repo.On("Reserve", "1:key", domain.IdempotentResponse{Hash: "hash"}, domain.IdempotencyLease).
+					Return(domain.IdempotentResponse{Hash: "hash", Status: http.StatusOK, TooLarge: true}, false, nil)
+			},
+			err: domain.ErrResponseTooLarge,
+		},
+		{
+			name:                "BadCase/LongKey",
+			key:                 strings.Repeat("k", domain.MaxIdempotencyKeyLength+1),
+			setRepoExpectations: func(repo *mocks.IdempotencyRepository) {},
+			err:                 domain.ErrBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := new(mocks.IdempotencyRepository)
+			test.setRepoExpectations(repo)
+
+			response, err := usecase.NewIdempotencyUsecase(repo, time.Hour).Begin(1, test.key, "hash")
+
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.response, response)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFinish(t *testing.T) {
+	tests := []struct {
+		name                string
+		response            domain.IdempotentResponse
+		setRepoExpectations func(repo *mocks.IdempotencyRepository, response domain.IdempotentResponse)
+	}{
+		{
+			name:     "GoodCase/Saved",
+			response: domain.IdempotentResponse{Hash: "hash", Status: http.StatusOK, Body: []byte(`{}`)},
+			setRepoExpectations: func(repo *mocks.IdempotencyRepository, response domain.IdempotentResponse) {
+				repo.On("Save", "1:key", response, time.Hour).Return(nil)
+			},
+		},
+		{
+			name:     "GoodCase/ClientErrorSaved",
+			response: domain.IdempotentResponse{Hash: "hash", Status: http.StatusBadRequest},
+			setRepoExpectations: func(repo *mocks.IdempotencyRepository, response domain.IdempotentResponse) {
+				repo.On("Save", "1:key", response, time.Hour).Return(nil)
+			},
+		},
+		{
+			name:     "GoodCase/ServerErrorReleased",
+			response: domain.IdempotentResponse{Hash: "hash", Status: http.StatusInternalServerError},
+			setRepoExpectations: func(repo *mocks.IdempotencyRepository, response domain.IdempotentResponse) {
+				repo.On("Release", "1:key").Return(nil)
+			},
+		},
+		{
+			name: "GoodCase/TooLargeMarked",
+			response: domain.IdempotentResponse{Hash: "hash", Status: http.StatusOK,
+				Header: http.Header{"Content-Type": {"application/json"}},
+				Body:   make([]byte, domain.MaxIdempotentResponseSize+1)},
+			setRepoExpectations: func(repo *mocks.IdempotencyRepository, response domain.IdempotentResponse) {
+				repo.On("Save", "1:key", domain.IdempotentResponse{Hash: "hash", Status: http.StatusOK, TooLarge: true},
+					time.Hour).Return(nil)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := new(mocks.IdempotencyRepository)
+			test.setRepoExpectations(repo, test.response)
+
+			err := usecase.NewIdempotencyUsecase(repo, time.Hour).Finish(1, "key", test.response)
+
+			assert.NoError(t, err)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestExtend(t *testing.T) {
+	repo := new(mocks.IdempotencyRepository)
+	repo.On("Extend", "1:key", time.Minute).Return(nil)
+
+	err := usecase.NewIdempotencyUsecase(repo, time.Minute).Extend(1, "key")
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,166 @@
+package jsonpatch_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ellexo2456/FilmLib/internal/jsonpatch"
+)
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name   string
+		doc    string
+		patch  string
+		result string
+		err    error
+	}{
+		{
+			name:   "GoodCase/Replace",
+			doc:    `{"title": "Matrix", "rating": 8.5}`,
+			patch:  `{"rating": 0}`,
+			result: `{"title": "Matrix", "rating": 0}`,
+		},
+		{
+			name:   "GoodCase/RemoveWithNull",
+			doc:    `{"title": "Matrix", "rating": 8.5}`,
+			patch:  `{"rating": null}`,
+			result: `{"title": "Matrix"}`,
+		},
+		{
+			name:   "GoodCase/Nested",
+			doc:    `{"a": {"b": "c", "d": "e"}}`,
+			patch:  `{"a": {"d": null, "f": "g"}}`,
+			result: `{"a": {"b": "c", "f": "g"}}`,
+		},
+		{
+			name:   "GoodCase/ArrayIsReplaced",
+			doc:    `{"a": [1, 2]}`,
+			patch:  `{"a": [3]}`,
+			result: `{"a": [3]}`,
+		},
+		{
+			name:   "GoodCase/NotAnObject",
+			doc:    `{"a": "b"}`,
+			patch:  `["c"]`,
+			result: `["c"]`,
+		},
+		{
+			name:  "BadCase/InvalidJson",
+			doc:   `{"a": "b"}`,
+			patch: `{"a": `,
+			err:   jsonpatch.ErrInvalidPatch,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := jsonpatch.Merge([]byte(test.doc), []byte(test.patch))
+			require.Equal(t, test.err, err)
+			if test.err == nil {
+				assert.JSONEq(t, test.result, string(result))
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name   string
+		doc    string
+		patch  string
+		result string
+		err    error
+	}{
+		{
+			name:   "GoodCase/Add",
+			doc:    `{"a": [1, 3]}`,
+			patch:  `[{"op": "add", "path": "/a/1", "value": 2}, {"op": "add", "path": "/a/-", "value": 4}, {"op": "add", "path": "/b", "value": null}]`,
+			result: `{"a": [1, 2, 3, 4], "b": null}`,
+		},
+		{
+			name:   "GoodCase/RemoveReplace",
+			doc:    `{"title": "Matrix", "rating": 8.5, "a": [1, 2]}`,
+			patch:  `[{"op": "remove", "path": "/a/0"}, {"op": "replace", "path": "/rating", "value": 0}]`,
+			result: `{"title": "Matrix", "rating": 0, "a": [2]}`,
+		},
+		{
+			name:   "GoodCase/MoveCopy",
+			doc:    `{"a": {"b": 1}, "c": []}`,
+			patch:  `[{"op": "copy", "from": "/a/b", "path": "/c/0"}, {"op": "move", "from": "/a", "path": "/d"}]`,
+			result: `{"c": [1], "d": {"b": 1}}`,
+		},
+		{
+			name:   "GoodCase/EscapedPointer",
+			doc:    `{"a/b": 1, "m~n": 2}`,
+			patch:  `[{"op": "replace", "path": "/a~1b", "value": 3}, {"op": "remove", "path": "/m~0n"}]`,
+			result: `{"a/b": 3}`,
+		},
+		{
+			name:   "GoodCase/Test",
+			doc:    `{"rating": 8.5}`,
+			patch:  `[{"op": "test", "path": "/rating", "value": 8.5}, {"op": "replace", "path": "/rating", "value": 9}]`,
+			result: `{"rating": 9}`,
+		},
+		{
+			name:  "BadCase/TestFailed",
+			doc:   `{"rating": 8.5}`,
+			patch: `[{"op": "test", "path": "/rating", "value": 7}, {"op": "replace", "path": "/rating", "value": 9}]`,
+			err:   jsonpatch.ErrTestFailed,
+		},
+		{
+			name:  "BadCase/ReplaceMissing",
+			doc:   `{"rating": 8.5}`,
+			patch: `[{"op": "replace", "path": "/title", "value": "Matrix"}]`,
+			err:   jsonpatch.ErrPathNotFound,
+		},
+		{
+			name:  "BadCase/IndexOutOfRange",
+			doc:   `{"a": [1]}`,
+			patch: `[{"op": "add", "path": "/a/2", "value": 2}]`,
+			err:   jsonpatch.ErrPathNotFound,
+		},
+		{
+			name:  "BadCase/LeadingZero",
+			doc:   `{"a": [1, 2]}`,
+			patch: `[{"op": "remove", "path": "/a/01"}]`,
+			err:   jsonpatch.ErrInvalidPatch,
+		},
+		{
+			name:  "BadCase/MoveIntoChild",
+			doc:   `{"a": {"b": 1}}`,
+			patch: `[{"op": "move", "from": "/a", "path": "/a/c"}]`,
+			err:   jsonpatch.ErrInvalidPatch,
+		},
+		{
+			name:  "BadCase/NoValue",
+			doc:   `{"a": 1}`,
+			patch: `[{"op": "add", "path": "/b"}]`,
+			err:   jsonpatch.ErrInvalidPatch,
+		},
+		{
+			name:  "BadCase/UnknownOp",
+			doc:   `{"a": 1}`,
+			patch: `[{"op": "increment", "path": "/a", "value": 1}]`,
+			err:   jsonpatch.ErrInvalidPatch,
+		},
+		{
+			name:  "BadCase/NotAnArray",
+			doc:   `{"a": 1}`,
+			patch: `{"op": "remove", "path": "/a"}`,
+			err:   jsonpatch.ErrInvalidPatch,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := jsonpatch.Apply([]byte(test.doc), []byte(test.patch))
+			require.Equal(t, test.err, err)
+			if test.err == nil {
+				assert.JSONEq(t, test.result, string(result))
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"os"
+	"strings"
+
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+)
+
+// ParseKeys reads keys in the "id:alg:base64 secret" form separated by
+// commas. For EdDSA the secret is a 32 byte seed of the private key.
+func ParseKeys(s string) ([]Key, error) {
+	var keys []Key
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		fields := strings.SplitN(part, ":", 3)
+		if len(fields) != 3 || fields[0] == "" {
+			return nil, errors.New("invalid key " + part)
+		}
+		secret, err := base64.StdEncoding.DecodeString(fields[2])
+		if err != nil {
+			return nil, err
+		}
+
+		switch fields[1] {
+		case HS256:
+			if len(secret) == 0 {
+				return nil, errors.New("empty secret of key " + fields[0])
+			}
+			keys = append(keys, NewHMACKey(fields[0], secret))
+		case EdDSA:
+			if len(secret) != ed25519.SeedSize {
+				return nil, errors.New("invalid seed size of key " + fields[0])
+			}
+			keys = append(keys, NewEd25519Key(fields[0], secret))
+		default:
+			return nil, ErrUnknownAlg
+		}
+	}
+
+	return keys, nil
+}
+
+// New builds the keyring from JWT_KEYS and JWT_SIGNING_KEY_ID. Without
+// keys a random one is generated, which is fine for a single instance but
+// invalidates all issued tokens on restart.
+func New() (*Keyring, error) {
+	keys, err := ParseKeys(os.Getenv("JWT_KEYS"))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(keys) == 0 {
+		logs.Logger.Warn("JWT_KEYS is not set, using a random key")
+		secret := make([]byte, 32)
+		if _, err = rand.Read(secret); err != nil {
+			return nil, err
+		}
+		return NewKeyring("random", NewHMACKey("random", secret))
+	}
+
+	id := os.Getenv("JWT_SIGNING_KEY_ID")
+	if id == "" {
+		id = keys[len(keys)-1].ID
+	}
+
+	return NewKeyring(id, keys...)
+}
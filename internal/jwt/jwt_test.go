@@ -0,0 +1,170 @@
+package jwt_test
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	"github.com/ellexo2456/FilmLib/internal/jwt"
+)
+
+type claims struct {
+	Sub string `json:"sub"`
+}
+
+var seed = []byte("0123456789abcdef0123456789abcdef")
+
+func TestSignVerify(t *testing.T) {
+	hmacKey := jwt.NewHMACKey("h1", []byte("secret"))
+	edKey := jwt.NewEd25519Key("e1", seed)
+
+	tests := []struct {
+		name    string
+		signer  *jwt.Keyring
+		checker *jwt.Keyring
+		tamper  func(token string) string
+		err     error
+	}{
+		{
+			name:    "GoodCase/HMAC",
+			signer:  mustKeyring(t, "h1", hmacKey),
+			checker: mustKeyring(t, "h1", hmacKey),
+		},
+		{
+			name:    "GoodCase/Ed25519",
+			signer:  mustKeyring(t, "e1", edKey),
+			checker: mustKeyring(t, "e1", edKey),
+		},
+		{
+			name:    "GoodCase/RotatedKey",
+			signer:  mustKeyring(t, "h1", hmacKey),
+			checker: mustKeyring(t, "e1", hmacKey, edKey),
+		},
+		{
+			name:    "BadCase/UnknownKey",
+			signer:  mustKeyring(t, "h1", hmacKey),
+			checker: mustKeyring(t, "e1", edKey),
+			err:     domain.ErrUnauthorized,
+		},
+		{
+			name:    "BadCase/OtherSecret",
+			signer:  mustKeyring(t, "h1", hmacKey),
+			checker: mustKeyring(t, "h1", jwt.NewHMACKey("h1", []byte("other"))),
+			err:     domain.ErrUnauthorized,
+		},
+		{
+			name:    "BadCase/TamperedPayload",
+			signer:  mustKeyring(t, "e1", edKey),
+			checker: mustKeyring(t, "e1", edKey),
+			tamper: func(token string) string {
+				parts := strings.Split(token, ".")
+				parts[1] = base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"2"}`))
+				return strings.Join(parts, ".")
+			},
+			err: domain.ErrUnauthorized,
+		},
+		{
+			name:    "BadCase/AlgSwitched",
+			signer:  mustKeyring(t, "e1", edKey),
+			checker: mustKeyring(t, "e1", edKey),
+			tamper: func(token string) string {
+				parts := strings.Split(token, ".")
+				parts[0] = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT","kid":"e1"}`))
+				return strings.Join(parts, ".")
+			},
+			err: domain.ErrUnauthorized,
+		},
+		{
+			name:    "BadCase/Malformed",
+			signer:  mustKeyring(t, "h1", hmacKey),
+			checker: mustKeyring(t, "h1", hmacKey),
+			tamper:  func(token string) string { return "abc" },
+			err:     domain.ErrUnauthorized,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			token, err := test.signer.Sign(claims{Sub: "1"})
+			require.NoError(t, err)
+			if test.tamper != nil {
+				token = test.tamper(token)
+			}
+
+			var c claims
+			err = test.checker.Verify(token, &c)
+
+			assert.ErrorIs(t, err, test.err)
+			if test.err == nil {
+				assert.Equal(t, "1", c.Sub)
+			}
+		})
+	}
+}
+
+func TestNewKeyring(t *testing.T) {
+	_, err := jwt.NewKeyring("missing", jwt.NewHMACKey("h1", []byte("secret")))
+	assert.Error(t, err)
+}
+
+func TestParseKeys(t *testing.T) {
+	secret := base64.StdEncoding.EncodeToString([]byte("secret"))
+	edSeed := base64.StdEncoding.EncodeToString(seed)
+
+	tests := []struct {
+		name string
+		keys string
+		ids  []string
+		good bool
+	}{
+		{
+			name: "GoodCase/Common",
+			keys: "h1:HS256:" + secret + ", e1:EdDSA:" + edSeed,
+			ids:  []string{"h1", "e1"},
+			good: true,
+		},
+		{
+			name: "GoodCase/Empty",
+			good: true,
+		},
+		{
+			name: "BadCase/UnknownAlg",
+			keys: "h1:RS256:" + secret,
+		},
+		{
+			name: "BadCase/ShortSeed",
+			keys: "e1:EdDSA:" + secret,
+		},
+		{
+			name: "BadCase/NoSecret",
+			keys: "h1:HS256",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			keys, err := jwt.ParseKeys(test.keys)
+			if !test.good {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			var ids []string
+			for _, k := range keys {
+				ids = append(ids, k.ID)
+			}
+			assert.Equal(t, test.ids, ids)
+		})
+	}
+}
+
+func mustKeyring(t *testing.T, id string, keys ...jwt.Key) *jwt.Keyring {
+	k, err := jwt.NewKeyring(id, keys...)
+	require.NoError(t, err)
+	return k
+}
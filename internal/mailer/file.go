@@ -0,0 +1,72 @@
+package mailer
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+)
+
+type fileMailer struct {
+	path string
+	mu   sync.Mutex
+}
+
+type fileRecord struct {
+	SentAt  time.Time `json:"sentAt"`
+	To      string    `json:"to"`
+	Subject string    `json:"subject"`
+	Body    string    `json:"body"`
+}
+
+// NewFileMailer appends every mail as a json line to the file at path.
+// With an empty path mails are only written to the log.
+func NewFileMailer(path string) domain.Mailer {
+	return &fileMailer{path: path}
+}
+
+func (m *fileMailer) Send(mail domain.Mail) error {
+	if mail.To == "" {
+		return domain.ErrBadRequest
+	}
+
+	if m.path == "" {
+		logs.Logger.WithFields(logrus.Fields{
+			"to":      mail.To,
+			"subject": mail.Subject,
+		}).Info(mail.Body)
+		return nil
+	}
+
+	data, err := json.Marshal(fileRecord{
+		SentAt:  time.Now(),
+		To:      mail.To,
+		Subject: mail.Subject,
+		Body:    mail.Body,
+	})
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f, err := os.OpenFile(m.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		logs.LogError(logs.Logger, "mailer", "file.Send", err, err.Error())
+		return err
+	}
+	defer f.Close()
+
+	if _, err = f.Write(append(data, '\n')); err != nil {
+		logs.LogError(logs.Logger, "mailer", "file.Send", err, err.Error())
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package mailer_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	"github.com/ellexo2456/FilmLib/internal/mailer"
+)
+
+func TestFileMailerSend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mails.log")
+	m := mailer.NewFileMailer(path)
+
+	require.NoError(t, m.Send(domain.Mail{To: "a@mail.ru", Subject: "first", Body: "hello"}))
+	require.NoError(t, m.Send(domain.Mail{To: "b@mail.ru", Subject: "second", Body: "bye"}))
+	require.ErrorIs(t, m.Send(domain.Mail{Subject: "no recipient"}), domain.ErrBadRequest)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+
+	var mail struct {
+		To      string `json:"to"`
+		Subject string `json:"subject"`
+		Body    string `json:"body"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &mail))
+	require.Equal(t, "b@mail.ru", mail.To)
+	require.Equal(t, "second", mail.Subject)
+	require.Equal(t, "bye", mail.Body)
+}
+
+func TestFileMailerSendToLog(t *testing.T) {
+	m := mailer.NewFileMailer("")
+	require.NoError(t, m.Send(domain.Mail{To: "a@mail.ru", Subject: "first", Body: "hello"}))
+}
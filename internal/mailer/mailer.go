@@ -0,0 +1,28 @@
+package mailer
+
+import (
+	"os"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+)
+
+// New picks the mailer implementation by the MAILER env variable.
+// Everything except "smtp" falls back to the file mailer, so local
+// setups don`t need a mail server.
+func New() domain.Mailer {
+	switch os.Getenv("MAILER") {
+	case "smtp":
+		logs.Logger.Info("using smtp mailer")
+		return NewSMTPMailer(
+			os.Getenv("SMTP_HOST"),
+			os.Getenv("SMTP_PORT"),
+			os.Getenv("SMTP_USER"),
+			os.Getenv("SMTP_PASSWORD"),
+			os.Getenv("MAIL_FROM"),
+		)
+	default:
+		logs.Logger.Info("using file mailer")
+		return NewFileMailer(os.Getenv("MAIL_FILE"))
+	}
+}
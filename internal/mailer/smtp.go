@@ -0,0 +1,56 @@
+package mailer
+
+import (
+	"fmt"
+	"net/smtp"
+	"strings"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+)
+
+type smtpMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+func NewSMTPMailer(host, port, user, password, from string) domain.Mailer {
+	var auth smtp.Auth
+	if user != "" {
+		auth = smtp.PlainAuth("", user, password, host)
+	}
+
+	return &smtpMailer{
+		addr: host + ":" + port,
+		auth: auth,
+		from: from,
+	}
+}
+
+func (m *smtpMailer) Send(mail domain.Mail) error {
+	if mail.To == "" {
+		return domain.ErrBadRequest
+	}
+
+	err := smtp.SendMail(m.addr, m.auth, m.from, []string{mail.To}, buildMessage(m.from, mail))
+	if err != nil {
+		logs.LogError(logs.Logger, "mailer", "smtp.Send", err, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func buildMessage(from string, mail domain.Mail) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", mail.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mail.Subject)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(mail.Body)
+
+	return []byte(b.String())
+}
@@ -3,37 +3,54 @@ package middleware
 import (
 	"errors"
 	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
 	"golang.org/x/net/context"
 	"net/http"
-	"time"
+	"strings"
 )
 
+const bearerPrefix = "Bearer "
+
 type AuthMiddleware struct {
-	authUsecase domain.AuthUsecase
+	authUsecase         domain.AuthUsecase
+	verificationUsecase domain.VerificationUsecase
+	tokensUsecase       domain.APITokensUsecase
+	tokenAuthUsecase    domain.TokenAuthUsecase
 }
 
-func NewAuth(au domain.AuthUsecase) *AuthMiddleware {
-	return &AuthMiddleware{authUsecase: au}
+// NewAuth takes a nil tau when the jwt auth mode is off.
+func NewAuth(au domain.AuthUsecase, vu domain.VerificationUsecase, tu domain.APITokensUsecase,
+	tau domain.TokenAuthUsecase) *AuthMiddleware {
+	return &AuthMiddleware{authUsecase: au, verificationUsecase: vu, tokensUsecase: tu, tokenAuthUsecase: tau}
 }
 
+// IsAuth accepts either a bearer token in the Authorization header
+// or the session_token cookie. A bearer token is an API token or,
+// in the jwt auth mode, a signed access token. Without a live session
+// the remember_token cookie starts a new one.
 func (m *AuthMiddleware) IsAuth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		c, err := r.Cookie("session_token")
-		if err != nil {
-			if errors.Is(err, http.ErrNoCookie) {
-				domain.WriteError(w, err.Error(), http.StatusUnauthorized)
-				return
-			}
+		if h := r.Header.Get("Authorization"); h != "" {
+			m.bearerAuth(w, r, h, next)
+			return
+		}
 
+		c, err := r.Cookie(domain.SessionCookie)
+		if err != nil && !errors.Is(err, http.ErrNoCookie) {
 			domain.WriteError(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		if c.Expires.After(time.Now()) {
-			domain.WriteError(w, "cookie is expired", http.StatusUnauthorized)
-		}
 
-		sessionToken := c.Value
-		sc, err := m.authUsecase.RetrieveSessionContext(sessionToken)
+		var sc domain.SessionContext
+		if err == nil {
+			sc, err = m.authUsecase.RetrieveSessionContext(c.Value)
+			if err == nil && !sc.ExpiresAt.IsZero() {
+				domain.SetSessionCookie(w, c.Value, sc.ExpiresAt)
+			}
+		}
+		if err != nil && (errors.Is(err, http.ErrNoCookie) || errors.Is(err, domain.ErrNotFound)) {
+			sc, err = m.resume(w, r, err)
+		}
 		if err != nil {
 			domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
 			return
@@ -47,3 +64,89 @@ func (m *AuthMiddleware) IsAuth(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// resume starts a new session with the remember-me token. Without the
+// token the session error is returned as it is.
+func (m *AuthMiddleware) resume(w http.ResponseWriter, r *http.Request, sessionErr error) (domain.SessionContext, error) {
+	c, err := r.Cookie(domain.RememberCookie)
+	if err != nil {
+		if errors.Is(sessionErr, http.ErrNoCookie) {
+			return domain.SessionContext{}, domain.ErrUnauthorized
+		}
+		return domain.SessionContext{}, sessionErr
+	}
+
+	session, err := m.authUsecase.Resume(c.Value, domain.RequestClient(r))
+	if err != nil {
+		domain.ClearCookie(w, domain.RememberCookie)
+		logs.LogError(logs.Logger, "middleware", "IsAuth", err, "failed to resume the session")
+		return domain.SessionContext{}, domain.ErrUnauthorized
+	}
+
+	domain.SetSessionCookie(w, session.Token, session.ExpiresAt)
+	domain.SetRememberCookie(w, session.RememberToken, session.RememberExpiresAt)
+
+	return domain.SessionContext{
+		UserID:   session.UserID,
+		Role:     session.Role,
+		Verified: session.Verified,
+	}, nil
+}
+
+func (m *AuthMiddleware) bearerAuth(w http.ResponseWriter, r *http.Request, header string, next http.Handler) {
+	token, ok := strings.CutPrefix(header, bearerPrefix)
+	if !ok || token == "" {
+		domain.WriteError(w, "invalid authorization header", http.StatusUnauthorized)
+		return
+	}
+
+	var sc domain.SessionContext
+	var err error
+	switch {
+	case strings.HasPrefix(token, domain.APITokenPrefix):
+		sc, err = m.tokensUsecase.RetrieveSessionContext(token)
+	case m.tokenAuthUsecase != nil:
+		sc, err = m.tokenAuthUsecase.RetrieveSessionContext(token)
+	default:
+		err = domain.ErrUnauthorized
+	}
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "middleware", "IsAuth", err, "invalid bearer token")
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), domain.SessionContextKey, sc)
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// RefreshVerified double-checks the email of an unverified user before
+// a write, the session may have been created before the user clicked
+// the link. The writes themselves are blocked by Require, so the account
+// and session management stays open. Must be applied after IsAuth.
+func (m *AuthMiddleware) RefreshVerified(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sc, ok := r.Context().Value(domain.SessionContextKey).(domain.SessionContext)
+		if isSafeMethod(r.Method) || !ok || sc.Verified {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		verified, err := m.verificationUsecase.IsVerified(sc.UserID)
+		if err != nil {
+			domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+			logs.LogError(logs.Logger, "middleware", "RefreshVerified", err, err.Error())
+			return
+		}
+		if verified {
+			sc.Verified = true
+			r = r.WithContext(context.WithValue(r.Context(), domain.SessionContextKey, sc))
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
@@ -0,0 +1,100 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	"github.com/ellexo2456/FilmLib/internal/domain/mocks"
+	"github.com/ellexo2456/FilmLib/internal/middleware"
+)
+
+func TestRefreshVerified(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		sc             *domain.SessionContext
+		setUCaseExpect func(uCase *mocks.VerificationUsecase)
+		status         int
+		verified       bool
+	}{
+		{
+			name:           "GoodCase/Verified",
+			method:         http.MethodPost,
+			sc:             &domain.SessionContext{UserID: 1, Verified: true},
+			setUCaseExpect: func(uCase *mocks.VerificationUsecase) {},
+			status:         http.StatusOK,
+			verified:       true,
+		},
+		{
+			name:   "GoodCase/VerifiedAfterLogin",
+			method: http.MethodPost,
+			sc:     &domain.SessionContext{UserID: 1},
+			setUCaseExpect: func(uCase *mocks.VerificationUsecase) {
+				uCase.On("IsVerified", 1).Return(true, nil)
+			},
+			status:   http.StatusOK,
+			verified: true,
+		},
+		{
+			name:   "GoodCase/Unverified",
+			method: http.MethodDelete,
+			sc:     &domain.SessionContext{UserID: 1},
+			setUCaseExpect: func(uCase *mocks.VerificationUsecase) {
+				uCase.On("IsVerified", 1).Return(false, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:           "GoodCase/SafeMethod",
+			method:         http.MethodGet,
+			sc:             &domain.SessionContext{UserID: 1},
+			setUCaseExpect: func(uCase *mocks.VerificationUsecase) {},
+			status:         http.StatusOK,
+		},
+		{
+			name:           "GoodCase/NoUser",
+			method:         http.MethodPost,
+			setUCaseExpect: func(uCase *mocks.VerificationUsecase) {},
+			status:         http.StatusOK,
+		},
+		{
+			name:   "BadCase/DBError",
+			method: http.MethodPost,
+			sc:     &domain.SessionContext{UserID: 1},
+			setUCaseExpect: func(uCase *mocks.VerificationUsecase) {
+				uCase.On("IsVerified", 1).Return(false, domain.ErrInternalServerError)
+			},
+			status: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			uCase := new(mocks.VerificationUsecase)
+			test.setUCaseExpect(uCase)
+
+			var verified bool
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				sc, _ := r.Context().Value(domain.SessionContextKey).(domain.SessionContext)
+				verified = sc.Verified
+			})
+
+			req := httptest.NewRequest(test.method, "/films", nil)
+			if test.sc != nil {
+				req = req.WithContext(context.WithValue(req.Context(), domain.SessionContextKey, *test.sc))
+			}
+			rec := httptest.NewRecorder()
+
+			middleware.NewAuth(nil, uCase, nil, nil).RefreshVerified(next).ServeHTTP(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			assert.Equal(t, test.verified, verified)
+			uCase.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+)
+
+// Require lets the request through only if the role of the user has
+// the permission and, for API tokens, the token has it in its scopes.
+// Writes also need a verified email. Must be applied after
+// AuthMiddleware.IsAuth and AuthMiddleware.RefreshVerified.
+func Require(p domain.Permission, next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sc, ok := r.Context().Value(domain.SessionContextKey).(domain.SessionContext)
+		if !ok {
+			domain.WriteError(w, "You`re unauthorized", http.StatusUnauthorized)
+			logs.LogError(logs.Logger, "middleware", "Require", errors.New("can`t find user"), "can`t find user")
+			return
+		}
+
+		if !sc.Can(p) {
+			domain.WriteError(w, domain.ErrForbidden.Error(), http.StatusForbidden)
+			logs.LogError(logs.Logger, "middleware", "Require", domain.ErrForbidden, "no permission "+string(p))
+			return
+		}
+
+		if !sc.Verified && !isSafeMethod(r.Method) {
+			domain.WriteError(w, domain.ErrNotVerified.Error(), http.StatusForbidden)
+			logs.LogError(logs.Logger, "middleware", "Require", domain.ErrNotVerified, "email isn`t verified")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
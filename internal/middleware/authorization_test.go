@@ -0,0 +1,157 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	"github.com/ellexo2456/FilmLib/internal/middleware"
+)
+
+func TestRequire(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		permission domain.Permission
+		sc         *domain.SessionContext
+		status     int
+	}{
+		{
+			name:       "GoodCase/Write",
+			method:     http.MethodPost,
+			permission: domain.FilmsWrite,
+			sc:         &domain.SessionContext{UserID: 1, Role: domain.Moder, Verified: true},
+			status:     http.StatusOK,
+		},
+		{
+			name:       "GoodCase/UnverifiedRead",
+			method:     http.MethodGet,
+			permission: domain.FilmsWrite,
+			sc:         &domain.SessionContext{UserID: 1, Role: domain.Moder},
+			status:     http.StatusOK,
+		},
+		{
+			name:       "GoodCase/TokenScope",
+			method:     http.MethodPost,
+			permission: domain.FilmsWrite,
+			sc: &domain.SessionContext{UserID: 1, Role: domain.Moder, Verified: true,
+				TokenID: 2, Scopes: []domain.Permission{domain.FilmsWrite}},
+			status: http.StatusOK,
+		},
+		{
+			name:       "BadCase/NoUserContext",
+			method:     http.MethodPost,
+			permission: domain.FilmsWrite,
+			status:     http.StatusUnauthorized,
+		},
+		{
+			name:       "BadCase/NoPermission",
+			method:     http.MethodPost,
+			permission: domain.FilmsWrite,
+			sc:         &domain.SessionContext{UserID: 1, Role: domain.Usr, Verified: true},
+			status:     http.StatusForbidden,
+		},
+		{
+			name:       "BadCase/NoPermissionRead",
+			method:     http.MethodGet,
+			permission: domain.AuditRead,
+			sc:         &domain.SessionContext{UserID: 1, Role: domain.Moder, Verified: true},
+			status:     http.StatusForbidden,
+		},
+		{
+			name:       "BadCase/TokenScopeNarrowerThanRole",
+			method:     http.MethodDelete,
+			permission: domain.FilmsDelete,
+			sc: &domain.SessionContext{UserID: 1, Role: domain.Admin, Verified: true,
+				TokenID: 2, Scopes: []domain.Permission{domain.FilmsWrite}},
+			status: http.StatusForbidden,
+		},
+		{
+			name:       "BadCase/TokenScopeWiderThanRole",
+			method:     http.MethodPost,
+			permission: domain.UsersManage,
+			sc: &domain.SessionContext{UserID: 1, Role: domain.Moder, Verified: true,
+				TokenID: 2, Scopes: []domain.Permission{domain.UsersManage}},
+			status: http.StatusForbidden,
+		},
+		{
+			name:       "BadCase/UnverifiedWrite",
+			method:     http.MethodPost,
+			permission: domain.FilmsWrite,
+			sc:         &domain.SessionContext{UserID: 1, Role: domain.Moder},
+			status:     http.StatusForbidden,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			called := false
+			next := func(w http.ResponseWriter, r *http.Request) {
+				called = true
+			}
+
+			req := httptest.NewRequest(test.method, "/films", nil)
+			if test.sc != nil {
+				req = req.WithContext(context.WithValue(req.Context(), domain.SessionContextKey, *test.sc))
+			}
+			rec := httptest.NewRecorder()
+
+			middleware.Require(test.permission, next).ServeHTTP(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			assert.Equal(t, test.status == http.StatusOK, called)
+		})
+	}
+}
+
+func TestRolePermissions(t *testing.T) {
+	all := []domain.Permission{
+		domain.FilmsWrite, domain.FilmsDelete, domain.ActorsWrite, domain.ActorsDelete, domain.ActorsMerge,
+		domain.UsersManage, domain.AuditRead, domain.TrashManage, domain.CatalogImport, domain.CatalogExport,
+		domain.WebhooksManage,
+	}
+	moder := []domain.Permission{
+		domain.FilmsWrite, domain.FilmsDelete, domain.ActorsWrite, domain.ActorsDelete, domain.ActorsMerge,
+		domain.TrashManage, domain.CatalogImport, domain.CatalogExport,
+	}
+
+	tests := []struct {
+		name    string
+		role    domain.Role
+		allowed []domain.Permission
+	}{
+		{name: "Usr", role: domain.Usr},
+		{name: "Moder", role: domain.Moder, allowed: moder},
+		{name: "Admin", role: domain.Admin, allowed: all},
+	}
+
+	for _, test := range tests {
+		for _, p := range all {
+			t.Run(test.name+"/"+string(p), func(t *testing.T) {
+				allowed := false
+				for _, a := range test.allowed {
+					allowed = allowed || a == p
+				}
+
+				req := httptest.NewRequest(http.MethodPost, "/", nil)
+				req = req.WithContext(context.WithValue(req.Context(), domain.SessionContextKey,
+					domain.SessionContext{UserID: 1, Role: test.role, Verified: true}))
+				rec := httptest.NewRecorder()
+
+				middleware.Require(p, func(w http.ResponseWriter, r *http.Request) {}).ServeHTTP(rec, req)
+
+				assert.Equal(t, allowed, test.role.Can(p))
+				assert.Len(t, test.role.Permissions(), len(test.allowed))
+				if allowed {
+					assert.Equal(t, http.StatusOK, rec.Code)
+				} else {
+					assert.Equal(t, http.StatusForbidden, rec.Code)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+)
+
+// CSRF checks the double-submitted token on the unsafe requests which
+// are authorized with a cookie: the CSRFHeader must match the csrf_token
+// cookie. A bearer token isn`t sent by the browser on its own, so such
+// requests, as well as the ones without an auth cookie, are let through.
+func CSRF(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isSafeMethod(r.Method) || r.Header.Get("Authorization") != "" || !hasAuthCookie(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		c, err := r.Cookie(domain.CSRFCookie)
+		header := r.Header.Get(domain.CSRFHeader)
+		if err != nil || c.Value == "" || subtle.ConstantTimeCompare([]byte(c.Value), []byte(header)) != 1 {
+			domain.WriteError(w, "csrf token is missing or invalid", http.StatusForbidden)
+			logs.LogError(logs.Logger, "middleware", "CSRF", domain.ErrForbidden, "csrf token doesn`t match")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func hasAuthCookie(r *http.Request) bool {
+	for _, name := range []string{domain.SessionCookie, domain.RememberCookie} {
+		if _, err := r.Cookie(name); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,119 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	"github.com/ellexo2456/FilmLib/internal/middleware"
+)
+
+func TestCSRF(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		cookies []*http.Cookie
+		headers map[string]string
+		status  int
+	}{
+		{
+			name:   "GoodCase/MatchingHeader",
+			method: http.MethodPost,
+			cookies: []*http.Cookie{
+				{Name: domain.SessionCookie, Value: "session"},
+				{Name: domain.CSRFCookie, Value: "csrf"},
+			},
+			headers: map[string]string{domain.CSRFHeader: "csrf"},
+			status:  http.StatusOK,
+		},
+		{
+			name:   "GoodCase/RememberCookie",
+			method: http.MethodDelete,
+			cookies: []*http.Cookie{
+				{Name: domain.RememberCookie, Value: "remember"},
+				{Name: domain.CSRFCookie, Value: "csrf"},
+			},
+			headers: map[string]string{domain.CSRFHeader: "csrf"},
+			status:  http.StatusOK,
+		},
+		{
+			name:    "GoodCase/Bearer",
+			method:  http.MethodPost,
+			cookies: []*http.Cookie{{Name: domain.SessionCookie, Value: "session"}},
+			headers: map[string]string{"Authorization": "Bearer flb_token"},
+			status:  http.StatusOK,
+		},
+		{
+			name:    "GoodCase/Get",
+			method:  http.MethodGet,
+			cookies: []*http.Cookie{{Name: domain.SessionCookie, Value: "session"}},
+			status:  http.StatusOK,
+		},
+		{
+			name:    "GoodCase/Head",
+			method:  http.MethodHead,
+			cookies: []*http.Cookie{{Name: domain.SessionCookie, Value: "session"}},
+			status:  http.StatusOK,
+		},
+		{
+			name:    "GoodCase/Options",
+			method:  http.MethodOptions,
+			cookies: []*http.Cookie{{Name: domain.SessionCookie, Value: "session"}},
+			status:  http.StatusOK,
+		},
+		{
+			name:   "GoodCase/NoAuthCookie",
+			method: http.MethodPost,
+			status: http.StatusOK,
+		},
+		{
+			name:   "BadCase/MissingHeader",
+			method: http.MethodPost,
+			cookies: []*http.Cookie{
+				{Name: domain.SessionCookie, Value: "session"},
+				{Name: domain.CSRFCookie, Value: "csrf"},
+			},
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "BadCase/MismatchedHeader",
+			method: http.MethodPut,
+			cookies: []*http.Cookie{
+				{Name: domain.SessionCookie, Value: "session"},
+				{Name: domain.CSRFCookie, Value: "csrf"},
+			},
+			headers: map[string]string{domain.CSRFHeader: "other"},
+			status:  http.StatusForbidden,
+		},
+		{
+			name:    "BadCase/MissingCookie",
+			method:  http.MethodPatch,
+			cookies: []*http.Cookie{{Name: domain.SessionCookie, Value: "session"}},
+			headers: map[string]string{domain.CSRFHeader: ""},
+			status:  http.StatusForbidden,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(test.method, "/films", nil)
+			for _, c := range test.cookies {
+				req.AddCookie(c)
+			}
+			for name, value := range test.headers {
+				req.Header.Set(name, value)
+			}
+			rec := httptest.NewRecorder()
+			middleware.CSRF(next).ServeHTTP(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+		})
+	}
+}
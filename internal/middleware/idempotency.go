@@ -0,0 +1,170 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+)
+
+// maxIdempotentRequestSize is the size of the largest body a POST takes,
+// the one of the catalog import.
+const maxIdempotentRequestSize = 32 << 20
+
+// leaseRenewal is how often the lease of the key is extended while the
+// request is handled, a large import can take longer than the lease.
+const leaseRenewal = domain.IdempotencyLease / 2
+
+// replayedHeaders are kept with the response, the rest are set anew by
+// the middlewares on every request.
+var replayedHeaders = []string{"Content-Type", "ETag", "Location"}
+
+type IdempotencyMiddleware struct {
+	idempotencyUsecase domain.IdempotencyUsecase
+}
+
+func NewIdempotency(iu domain.IdempotencyUsecase) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{idempotencyUsecase: iu}
+}
+
+// Handle replays the first response to a POST with the Idempotency-Key
+// header to the retries of the same user with the key. Requests without
+// the key are let through. Must be applied after AuthMiddleware.IsAuth.
+func (m *IdempotencyMiddleware) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(domain.IdempotencyKeyHeader)
+		sc, ok := r.Context().Value(domain.SessionContextKey).(domain.SessionContext)
+		if r.Method != http.MethodPost || key == "" || !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentRequestSize))
+		if err != nil {
+			status := http.StatusBadRequest
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				status = http.StatusRequestEntityTooLarge
+			}
+			domain.WriteError(w, err.Error(), status)
+			logs.LogError(logs.Logger, "middleware", "Idempotency", err, err.Error())
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := requestHash(r, body)
+		stored, err := m.idempotencyUsecase.Begin(sc.UserID, key, hash)
+		if err != nil {
+			domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+			logs.LogError(logs.Logger, "middleware", "Idempotency", err, err.Error())
+			return
+		}
+		if stored != nil {
+			for name, values := range stored.Header {
+				w.Header()[name] = values
+			}
+			w.Header().Set(domain.IdempotentReplayHeader, "true")
+			w.WriteHeader(stored.Status)
+			w.Write(stored.Body)
+			return
+		}
+
+		stop := m.extendLease(sc.UserID, key)
+
+		// the key of a request which panicked is released, the panic goes on
+		defer func() {
+			if p := recover(); p != nil {
+				stop()
+				m.idempotencyUsecase.Finish(sc.UserID, key, domain.IdempotentResponse{
+					Hash:   hash,
+					Status: http.StatusInternalServerError,
+				})
+				panic(p)
+			}
+		}()
+
+		rw := &recordingResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rw, r)
+		stop()
+
+		header := make(http.Header)
+		for _, name := range replayedHeaders {
+			if values := w.Header().Values(name); len(values) != 0 {
+				header[name] = values
+			}
+		}
+		m.idempotencyUsecase.Finish(sc.UserID, key, domain.IdempotentResponse{
+			Hash:   hash,
+			Status: rw.status,
+			Header: header,
+			Body:   rw.body.Bytes(),
+		})
+	})
+}
+
+// extendLease keeps the key reserved until the returned func is called,
+// it returns once the lease isn`t extended anymore.
+func (m *IdempotencyMiddleware) extendLease(userID int, key string) func() {
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(leaseRenewal)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				m.idempotencyUsecase.Extend(userID, key)
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			wg.Wait()
+		})
+	}
+}
+
+// requestHash tells the requests sent with the same key apart, the query
+// is included as it switches the dry run of the import.
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.RequestURI()+"\n")
+	h.Write(body)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// recordingResponseWriter keeps a copy of the body, but not more than
+// one byte over the size that can be stored.
+type recordingResponseWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rw *recordingResponseWriter) WriteHeader(code int) {
+	rw.status = code
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *recordingResponseWriter) Write(b []byte) (int, error) {
+	if left := domain.MaxIdempotentResponseSize + 1 - rw.body.Len(); left > 0 {
+		rw.body.Write(b[:min(left, len(b))])
+	}
+
+	return rw.ResponseWriter.Write(b)
+}
@@ -0,0 +1,218 @@
+package middleware_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	"github.com/ellexo2456/FilmLib/internal/domain/mocks"
+	"github.com/ellexo2456/FilmLib/internal/idempotency/usecase"
+	"github.com/ellexo2456/FilmLib/internal/middleware"
+)
+
+func TestIdempotency(t *testing.T) {
+	const body = `{"title": "Matrix"}`
+	sum := sha256.Sum256([]byte("POST /films\n" + body))
+	hash := hex.EncodeToString(sum[:])
+	created := domain.IdempotentResponse{
+		Hash:   hash,
+		Status: http.StatusCreated,
+		Header: http.Header{"Content-Type": {"application/json"}},
+		Body:   []byte(`{"body":{"id":1}}`),
+	}
+
+	tests := []struct {
+		name                string
+		method              string
+		key                 string
+		noUser              bool
+		handler             http.HandlerFunc
+		setRepoExpectations func(repo *mocks.IdempotencyRepository)
+		status              int
+		replayed            bool
+		called              bool
+	}{
+		{
+			name:   "GoodCase/First",
+			method: http.MethodPost,
+			key:    "key",
+			setRepoExpectations: func(repo *mocks.IdempotencyRepository) {
+				repo.On("Reserve", "1:key", domain.IdempotentResponse{Hash: hash}, domain.IdempotencyLease).
+					Return(domain.IdempotentResponse{}, true, nil)
+				repo.On("Save", "1:key", created, time.Hour).Return(nil)
+			},
+			status: http.StatusCreated,
+			called: true,
+		},
+		{
+			name:   "GoodCase/Replay",
+			method: http.MethodPost,
+			key:    "key",
+			setRepoExpectations: func(repo *mocks.IdempotencyRepository) {
+				repo.On("Reserve", "1:key", domain.IdempotentResponse{Hash: hash}, domain.IdempotencyLease).
+					Return(created, false, nil)
+			},
+			status:   http.StatusCreated,
+			replayed: true,
+		},
+		{
+			name:   "GoodCase/ReleasedAfterServerError",
+			method: http.MethodPost,
+			key:    "key",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				domain.WriteError(w, domain.ErrInternalServerError.Error(), http.StatusInternalServerError)
+			},
+			setRepoExpectations: func(repo *mocks.IdempotencyRepository) {
+				repo.On("Reserve", "1:key", domain.IdempotentResponse{Hash: hash}, domain.IdempotencyLease).
+					Return(domain.IdempotentResponse{}, true, nil)
+				repo.On("Release", "1:key").Return(nil)
+			},
+			status: http.StatusInternalServerError,
+			called: true,
+		},
+		{
+			name:   "GoodCase/TooLargeMarked",
+			method: http.MethodPost,
+			key:    "key",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write(make([]byte, domain.MaxIdempotentResponseSize+1))
+			},
+			setRepoExpectations: func(repo *mocks.IdempotencyRepository) {
+				repo.On("Reserve", "1:key", domain.IdempotentResponse{Hash: hash}, domain.IdempotencyLease).
+					Return(domain.IdempotentResponse{}, true, nil)
+				repo.On("Save", "1:key", domain.IdempotentResponse{Hash: hash, Status: http.StatusOK, TooLarge: true},
+					time.Hour).Return(nil)
+			},
+			status: http.StatusOK,
+			called: true,
+		},
+		{
+			name:   "BadCase/TooLargeReplay",
+			method: http.MethodPost,
+			key:    "key",
+			setRepoExpectations: func(repo *mocks.IdempotencyRepository) {
+				repo.On("Reserve", "1:key", domain.IdempotentResponse{Hash: hash}, domain.IdempotencyLease).
+					Return(domain.IdempotentResponse{Hash: hash, Status: http.StatusOK, TooLarge: true}, false, nil)
+			},
+			status: http.StatusConflict,
+		},
+		{
+			name:                "GoodCase/NotPost",
+			method:              http.MethodPut,
+			key:                 "key",
+			setRepoExpectations: func(repo *mocks.IdempotencyRepository) {},
+			status:              http.StatusCreated,
+			called:              true,
+		},
+		{
+			name:                "GoodCase/NoKey",
+			method:              http.MethodPost,
+			setRepoExpectations: func(repo *mocks.IdempotencyRepository) {},
+			status:              http.StatusCreated,
+			called:              true,
+		},
+		{
+			name:                "GoodCase/NoUser",
+			method:              http.MethodPost,
+			key:                 "key",
+			noUser:              true,
+			setRepoExpectations: func(repo *mocks.IdempotencyRepository) {},
+			status:              http.StatusCreated,
+			called:              true,
+		},
+		{
+			name:   "BadCase/AnotherBody",
+			method: http.MethodPost,
+			key:    "key",
+			setRepoExpectations: func(repo *mocks.IdempotencyRepository) {
+				repo.On("Reserve", "1:key", domain.IdempotentResponse{Hash: hash}, domain.IdempotencyLease).
+					Return(domain.IdempotentResponse{Hash: "other", Status: http.StatusCreated}, false, nil)
+			},
+			status: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "BadCase/InProgress",
+			method: http.MethodPost,
+			key:    "key",
+			setRepoExpectations: func(repo *mocks.IdempotencyRepository) {
+				repo.On("Reserve", "1:key", domain.IdempotentResponse{Hash: hash}, domain.IdempotencyLease).
+					Return(domain.IdempotentResponse{Hash: hash}, false, nil)
+			},
+			status: http.StatusConflict,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := new(mocks.IdempotencyRepository)
+			test.setRepoExpectations(repo)
+
+			called := false
+			handler := test.handler
+			if handler == nil {
+				handler = func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusCreated)
+					w.Write([]byte(`{"body":{"id":1}}`))
+				}
+			}
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				handler(w, r)
+			})
+
+			req := httptest.NewRequest(test.method, "/films", strings.NewReader(body))
+			if test.key != "" {
+				req.Header.Set(domain.IdempotencyKeyHeader, test.key)
+			}
+			if !test.noUser {
+				req = req.WithContext(context.WithValue(req.Context(), domain.SessionContextKey, domain.SessionContext{UserID: 1}))
+			}
+			w := httptest.NewRecorder()
+
+			middleware.NewIdempotency(usecase.NewIdempotencyUsecase(repo, time.Hour)).Handle(next).ServeHTTP(w, req)
+
+			assert.Equal(t, test.status, w.Code)
+			assert.Equal(t, test.called, called)
+			if test.replayed {
+				assert.Equal(t, "true", w.Header().Get(domain.IdempotentReplayHeader))
+				assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+				assert.Equal(t, `{"body":{"id":1}}`, w.Body.String())
+			} else {
+				assert.Empty(t, w.Header().Get(domain.IdempotentReplayHeader))
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIdempotencyPanic(t *testing.T) {
+	repo := new(mocks.IdempotencyRepository)
+	repo.On("Reserve", "1:key", mock.Anything, domain.IdempotencyLease).
+		Return(domain.IdempotentResponse{}, true, nil)
+	repo.On("Release", "1:key").Return(nil)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("handler failed")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/films", strings.NewReader(`{}`))
+	req.Header.Set(domain.IdempotencyKeyHeader, "key")
+	req = req.WithContext(context.WithValue(req.Context(), domain.SessionContextKey, domain.SessionContext{UserID: 1}))
+
+	handler := middleware.NewIdempotency(usecase.NewIdempotencyUsecase(repo, time.Hour)).Handle(next)
+	assert.PanicsWithValue(t, "handler failed", func() {
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	})
+	repo.AssertExpectations(t)
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"github.com/ellexo2456/FilmLib/internal/domain"
 	"github.com/sirupsen/logrus"
 	"net/http"
 	"time"
@@ -24,7 +25,7 @@ func (ac *AccessLogger) AccessLogMiddleware(next http.Handler) http.Handler {
 		ac.LogrusLogger.WithFields(logrus.Fields{
 			"method":      r.Method,
 			"remote_addr": r.RemoteAddr,
-			"request_id":  r.Header.Get("Request-ID"),
+			"request_id":  r.Header.Get(domain.RequestIDHeader),
 			"work_time":   time.Since(start),
 			"status":      lrw.statusCode,
 		}).Info(r.URL.Path)
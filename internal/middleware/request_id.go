@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+)
+
+const maxRequestIDLength = 128
+
+// RequestID keeps the id sent by a proxy or generates a new one. The id
+// is put back into the request headers and sent in the response.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(domain.RequestIDHeader)
+		if id == "" || len(id) > maxRequestIDLength {
+			id = uuid.NewString()
+			r.Header.Set(domain.RequestIDHeader, id)
+		}
+
+		w.Header().Set(domain.RequestIDHeader, id)
+		next.ServeHTTP(w, r)
+	})
+}
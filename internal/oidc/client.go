@@ -0,0 +1,155 @@
+package oidc
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+)
+
+var ErrInvalidIDToken = errors.New("id token is invalid")
+
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type tokenResponse struct {
+	IDToken string `json:"id_token"`
+	Error   string `json:"error"`
+}
+
+// client implements the authorization code flow with PKCE against
+// one provider. Discovery is done lazily, so a provider being down
+// doesn`t stop the server from starting.
+type client struct {
+	config     domain.OIDCProviderConfig
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	discovery *discovery
+	keys      *keySet
+}
+
+func NewClient(config domain.OIDCProviderConfig, httpClient *http.Client) domain.OIDCClient {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email"}
+	}
+
+	return &client{config: config, httpClient: httpClient}
+}
+
+func (c *client) AuthCodeURL(state, nonce, codeChallenge string) (string, error) {
+	d, err := c.discover()
+	if err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(d.AuthorizationEndpoint)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", c.config.ClientID)
+	q.Set("redirect_uri", c.config.RedirectURL)
+	q.Set("scope", strings.Join(c.config.Scopes, " "))
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	q.Set("code_challenge", codeChallenge)
+	q.Set("code_challenge_method", "S256")
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+func (c *client) Exchange(code, codeVerifier, nonce string) (domain.Identity, error) {
+	d, err := c.discover()
+	if err != nil {
+		return domain.Identity{}, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {c.config.RedirectURL},
+		"code_verifier": {codeVerifier},
+	}
+	req, err := http.NewRequest(http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return domain.Identity{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(c.config.ClientID), url.QueryEscape(c.config.ClientSecret))
+
+	var tr tokenResponse
+	if err = c.doJSON(req, &tr); err != nil {
+		return domain.Identity{}, err
+	}
+	if tr.IDToken == "" {
+		return domain.Identity{}, errors.New("token endpoint returned no id token: " + tr.Error)
+	}
+
+	claims, err := c.verify(tr.IDToken, d)
+	if err != nil {
+		return domain.Identity{}, err
+	}
+	if claims.Nonce != nonce {
+		return domain.Identity{}, ErrInvalidIDToken
+	}
+
+	return domain.Identity{
+		Provider:      c.config.Name,
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+	}, nil
+}
+
+func (c *client) discover() (*discovery, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.discovery != nil {
+		return c.discovery, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(c.config.Issuer, "/")+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var d discovery
+	if err = c.doJSON(req, &d); err != nil {
+		return nil, err
+	}
+	if d.Issuer != c.config.Issuer {
+		return nil, errors.New("issuer mismatch in discovery document of " + c.config.Name)
+	}
+
+	c.discovery = &d
+	return c.discovery, nil
+}
+
+func (c *client) doJSON(req *http.Request, v any) error {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.New(req.URL.String() + " responded with " + resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
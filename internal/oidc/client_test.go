@@ -0,0 +1,157 @@
+package oidc_test
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	"github.com/ellexo2456/FilmLib/internal/oidc"
+	"github.com/ellexo2456/FilmLib/internal/oidc/oidctest"
+)
+
+func challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func TestFlow(t *testing.T) {
+	issuer := oidctest.NewIssuer("filmlib", "secret")
+	defer issuer.Close()
+	issuer.SetUser(oidctest.User{Subject: "42", Email: "uvybini@mail.ru", EmailVerified: true})
+
+	config := domain.OIDCProviderConfig{
+		Name:         "fake",
+		Issuer:       issuer.URL(),
+		ClientID:     "filmlib",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost/callback",
+	}
+
+	tests := []struct {
+		name          string
+		clientSecret  string
+		verifier      string
+		exchangeNonce string
+		issuedNonce   string
+		good          bool
+	}{
+		{
+			name:          "GoodCase/Common",
+			clientSecret:  "secret",
+			verifier:      "verifier",
+			exchangeNonce: "nonce",
+			good:          true,
+		},
+		{
+			name:          "BadCase/WrongVerifier",
+			clientSecret:  "secret",
+			verifier:      "other",
+			exchangeNonce: "nonce",
+		},
+		{
+			name:          "BadCase/WrongSecret",
+			clientSecret:  "wrong",
+			verifier:      "verifier",
+			exchangeNonce: "nonce",
+		},
+		{
+			name:          "BadCase/ReplayedNonce",
+			clientSecret:  "secret",
+			verifier:      "verifier",
+			exchangeNonce: "nonce",
+			issuedNonce:   "old nonce",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			issuer.OverrideNonce(test.issuedNonce)
+			c := config
+			c.ClientSecret = test.clientSecret
+			client := oidc.NewClient(c, nil)
+
+			url, err := client.AuthCodeURL("state", "nonce", challenge("verifier"))
+			require.NoError(t, err)
+			code, state, err := issuer.Authorize(url)
+			require.NoError(t, err)
+			assert.Equal(t, "state", state)
+
+			identity, err := client.Exchange(code, test.verifier, test.exchangeNonce)
+			if !test.good {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, domain.Identity{
+				Provider:      "fake",
+				Subject:       "42",
+				Email:         "uvybini@mail.ru",
+				EmailVerified: true,
+			}, identity)
+
+			_, err = client.Exchange(code, test.verifier, test.exchangeNonce)
+			assert.Error(t, err, "code must be single use")
+		})
+	}
+}
+
+func TestDiscoveryIssuerMismatch(t *testing.T) {
+	issuer := oidctest.NewIssuer("filmlib", "secret")
+	defer issuer.Close()
+
+	client := oidc.NewClient(domain.OIDCProviderConfig{
+		Name:        "fake",
+		Issuer:      issuer.URL() + "/other",
+		ClientID:    "filmlib",
+		RedirectURL: "http://localhost/callback",
+	}, nil)
+
+	_, err := client.AuthCodeURL("state", "nonce", "challenge")
+	assert.Error(t, err)
+}
+
+func TestParseProviders(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  string
+		count int
+		good  bool
+	}{
+		{
+			name:  "GoodCase/Common",
+			data:  `[{"name": "a", "issuer": "https://a", "clientId": "id", "redirectUrl": "http://x"}]`,
+			count: 1,
+			good:  true,
+		},
+		{
+			name: "BadCase/NoIssuer",
+			data: `[{"name": "a", "clientId": "id", "redirectUrl": "http://x"}]`,
+		},
+		{
+			name: "BadCase/Duplicate",
+			data: `[{"name": "a", "issuer": "https://a", "clientId": "id", "redirectUrl": "http://x"},
+				{"name": "a", "issuer": "https://b", "clientId": "id", "redirectUrl": "http://x"}]`,
+		},
+		{
+			name: "BadCase/InvalidJson",
+			data: `[{"name": `,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			providers, err := oidc.ParseProviders([]byte(test.data))
+			if test.good {
+				require.NoError(t, err)
+				assert.Len(t, providers, test.count)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
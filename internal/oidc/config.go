@@ -0,0 +1,57 @@
+package oidc
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+)
+
+// ParseProviders reads a JSON array of provider configs.
+func ParseProviders(data []byte) ([]domain.OIDCProviderConfig, error) {
+	var providers []domain.OIDCProviderConfig
+	if err := json.Unmarshal(data, &providers); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(providers))
+	for _, p := range providers {
+		if p.Name == "" || p.Issuer == "" || p.ClientID == "" || p.RedirectURL == "" {
+			return nil, errors.New("provider config must have name, issuer, clientId and redirectUrl")
+		}
+		if seen[p.Name] {
+			return nil, errors.New("duplicate provider " + p.Name)
+		}
+		seen[p.Name] = true
+	}
+
+	return providers, nil
+}
+
+// New builds clients of the providers listed in the OIDC_PROVIDERS_FILE.
+// Social login is off when it isn`t set.
+func New() (map[string]domain.OIDCClient, error) {
+	path := os.Getenv("OIDC_PROVIDERS_FILE")
+	if path == "" {
+		return map[string]domain.OIDCClient{}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	providers, err := ParseProviders(data)
+	if err != nil {
+		return nil, err
+	}
+
+	clients := make(map[string]domain.OIDCClient, len(providers))
+	for _, p := range providers {
+		clients[p.Name] = NewClient(p, nil)
+		logs.Logger.Info("oidc provider " + p.Name + " is enabled")
+	}
+
+	return clients, nil
+}
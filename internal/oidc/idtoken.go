@@ -0,0 +1,182 @@
+package oidc
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// allowed clock difference with the provider
+const leeway = time.Minute
+
+type idTokenClaims struct {
+	Issuer        string   `json:"iss"`
+	Subject       string   `json:"sub"`
+	Audience      audience `json:"aud"`
+	ExpiresAt     int64    `json:"exp"`
+	Nonce         string   `json:"nonce"`
+	Email         string   `json:"email"`
+	EmailVerified bool     `json:"email_verified"`
+}
+
+// audience is either a string or an array of strings in the token
+type audience []string
+
+func (a *audience) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*a = audience{s}
+		return nil
+	}
+
+	var ss []string
+	if err := json.Unmarshal(b, &ss); err != nil {
+		return err
+	}
+	*a = ss
+	return nil
+}
+
+func (a audience) contains(s string) bool {
+	for _, v := range a {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type keySet struct {
+	keys map[string]*rsa.PublicKey
+}
+
+// verify checks an RS256 signed id token. Keys are refetched once
+// when the token is signed by an unknown one, to follow key rotation
+// of the provider.
+func (c *client) verify(token string, d *discovery) (idTokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return idTokenClaims{}, ErrInvalidIDToken
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil || header.Alg != "RS256" {
+		return idTokenClaims{}, ErrInvalidIDToken
+	}
+
+	key, err := c.key(header.Kid, d)
+	if err != nil {
+		return idTokenClaims{}, err
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return idTokenClaims{}, ErrInvalidIDToken
+	}
+	hash := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if rsa.VerifyPKCS1v15(key, crypto.SHA256, hash[:], sig) != nil {
+		return idTokenClaims{}, ErrInvalidIDToken
+	}
+
+	var claims idTokenClaims
+	if err = decodeSegment(parts[1], &claims); err != nil {
+		return idTokenClaims{}, ErrInvalidIDToken
+	}
+	if claims.Issuer != c.config.Issuer || !claims.Audience.contains(c.config.ClientID) || claims.Subject == "" {
+		return idTokenClaims{}, ErrInvalidIDToken
+	}
+	if time.Now().Add(-leeway).Unix() >= claims.ExpiresAt {
+		return idTokenClaims{}, ErrInvalidIDToken
+	}
+
+	return claims, nil
+}
+
+func (c *client) key(kid string, d *discovery) (*rsa.PublicKey, error) {
+	c.mu.Lock()
+	keys := c.keys
+	c.mu.Unlock()
+
+	if keys != nil {
+		if key, ok := keys.keys[kid]; ok {
+			return key, nil
+		}
+	}
+
+	keys, err := c.fetchKeys(d.JWKSURI)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.keys = keys
+	c.mu.Unlock()
+
+	key, ok := keys.keys[kid]
+	if !ok {
+		return nil, ErrInvalidIDToken
+	}
+
+	return key, nil
+}
+
+func (c *client) fetchKeys(uri string) (*keySet, error) {
+	req, err := http.NewRequest(http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err = c.doJSON(req, &set); err != nil {
+		return nil, err
+	}
+
+	keys := &keySet{keys: make(map[string]*rsa.PublicKey, len(set.Keys))}
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			continue
+		}
+
+		keys.keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	return keys, nil
+}
+
+func decodeSegment(s string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,159 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+)
+
+type TokensHandler struct {
+	TokensUsecase domain.APITokensUsecase
+}
+
+func NewTokensHandler(mux *http.ServeMux, tu domain.APITokensUsecase) {
+	handler := &TokensHandler{
+		TokensUsecase: tu,
+	}
+
+	mux.HandleFunc("GET /me/tokens", handler.GetTokens)
+	mux.HandleFunc("POST /me/tokens", handler.CreateToken)
+	mux.HandleFunc("DELETE /me/tokens/{id}", handler.RevokeToken)
+}
+
+// GetTokens godoc
+//
+//	@Summary		Gets API tokens.
+//	@Description	Gets API tokens of the current user. Plain tokens are never returned here. Can`t be called with an API token.
+//	@Tags			Tokens
+//	@Produce		json
+//	@Success		200	{object}	object{body=object{tokens=[]domain.APIToken}}
+//	@Failure		401	{object}	object{err=string}
+//	@Failure		403	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/me/tokens [get]
+func (h *TokensHandler) GetTokens(w http.ResponseWriter, r *http.Request) {
+	sc, ok := sessionContext(w, r, "GetTokens")
+	if !ok {
+		return
+	}
+	if sc.TokenID != 0 {
+		domain.WriteError(w, domain.ErrForbidden.Error(), http.StatusForbidden)
+		logs.LogError(logs.Logger, "tokens/http", "GetTokens", domain.ErrForbidden, "called with an API token")
+		return
+	}
+
+	tokens, err := h.TokensUsecase.GetAll(sc.UserID)
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "tokens/http", "GetTokens", err, err.Error())
+		return
+	}
+
+	domain.WriteResponse(
+		w,
+		map[string]interface{}{
+			"tokens": tokens,
+		},
+		http.StatusOK,
+	)
+}
+
+// CreateToken godoc
+//
+//	@Summary		Creates an API token.
+//	@Description	Creates a named API token to be sent as "Authorization: Bearer <token>". Scopes can be films:write, films:delete, actors:write, actors:delete and users:manage and are limited by the user role. The token is shown only once. Can`t be called with an API token.
+//	@Tags			Tokens
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		domain.APITokenToAdd	true	"Token name, scopes and optional expiry"
+//	@Success		201		{object}	object{body=object{token=domain.CreatedAPIToken}}
+//	@Failure		400		{object}	object{err=string}
+//	@Failure		401		{object}	object{err=string}
+//	@Failure		403		{object}	object{err=string}
+//	@Failure		500		{object}	object{err=string}
+//	@Router			/api/v1/me/tokens [post]
+func (h *TokensHandler) CreateToken(w http.ResponseWriter, r *http.Request) {
+	sc, ok := sessionContext(w, r, "CreateToken")
+	if !ok {
+		return
+	}
+
+	var token domain.APITokenToAdd
+	err := json.NewDecoder(r.Body).Decode(&token)
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "tokens/http", "CreateToken", err, err.Error())
+		return
+	}
+	defer domain.CloseAndAlert(r.Body, "tokens/http", "CreateToken")
+
+	created, err := h.TokensUsecase.Create(sc, token)
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "tokens/http", "CreateToken", err, err.Error())
+		return
+	}
+
+	domain.WriteResponse(
+		w,
+		map[string]interface{}{
+			"token": created,
+		},
+		http.StatusCreated,
+	)
+}
+
+// RevokeToken godoc
+//
+//	@Summary		Revokes an API token.
+//	@Description	Deletes an API token of the current user. An API token can revoke only itself.
+//	@Tags			Tokens
+//	@Param			id	path	int	true	"Token id"
+//	@Success		204
+//	@Failure		400	{object}	object{err=string}
+//	@Failure		401	{object}	object{err=string}
+//	@Failure		403	{object}	object{err=string}
+//	@Failure		404	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/me/tokens/{id} [delete]
+func (h *TokensHandler) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	sc, ok := sessionContext(w, r, "RevokeToken")
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "tokens/http", "RevokeToken", err, err.Error())
+		return
+	}
+	if sc.TokenID != 0 && sc.TokenID != id {
+		domain.WriteError(w, domain.ErrForbidden.Error(), http.StatusForbidden)
+		logs.LogError(logs.Logger, "tokens/http", "RevokeToken", domain.ErrForbidden, "called with another API token")
+		return
+	}
+
+	if err = h.TokensUsecase.Revoke(sc.UserID, id); err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "tokens/http", "RevokeToken", err, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func sessionContext(w http.ResponseWriter, r *http.Request, funcName string) (domain.SessionContext, bool) {
+	sc, ok := r.Context().Value(domain.SessionContextKey).(domain.SessionContext)
+	if !ok {
+		domain.WriteError(w, "can`t find user", http.StatusInternalServerError)
+		logs.LogError(logs.Logger, "tokens/http", funcName, errors.New("can`t find user"), "can`t find user")
+		return domain.SessionContext{}, false
+	}
+
+	return sc, true
+}
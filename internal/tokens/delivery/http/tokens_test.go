@@ -0,0 +1,200 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	"github.com/ellexo2456/FilmLib/internal/domain/mocks"
+	tokens_http "github.com/ellexo2456/FilmLib/internal/tokens/delivery/http"
+)
+
+var userSC = domain.SessionContext{UserID: 1, Role: domain.Moder}
+
+var userCtx = context.WithValue(context.Background(), domain.SessionContextKey, userSC)
+
+var tokenCtx = context.WithValue(context.Background(), domain.SessionContextKey,
+	domain.SessionContext{UserID: 1, Role: domain.Moder, TokenID: 2})
+
+func TestGetTokens(t *testing.T) {
+	tests := []struct {
+		name                 string
+		setUCaseExpectations func(usecase *mocks.APITokensUsecase)
+		ctx                  context.Context
+		status               int
+	}{
+		{
+			name: "GoodCase/Common",
+			setUCaseExpectations: func(usecase *mocks.APITokensUsecase) {
+				usecase.On("GetAll", 1).Return([]domain.APIToken{{ID: 1, Name: "import"}}, nil)
+			},
+			ctx:    userCtx,
+			status: http.StatusOK,
+		},
+		{
+			name:                 "BadCase/NoUserContext",
+			setUCaseExpectations: func(usecase *mocks.APITokensUsecase) {},
+			ctx:                  context.Background(),
+			status:               http.StatusInternalServerError,
+		},
+		{
+			name:                 "BadCase/APIToken",
+			setUCaseExpectations: func(usecase *mocks.APITokensUsecase) {},
+			ctx:                  tokenCtx,
+			status:               http.StatusForbidden,
+		},
+		{
+			name: "BadCase/UsecaseError",
+			setUCaseExpectations: func(usecase *mocks.APITokensUsecase) {
+				usecase.On("GetAll", 1).Return(nil, domain.ErrInternalServerError)
+			},
+			ctx:    userCtx,
+			status: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockUsecase := new(mocks.APITokensUsecase)
+			test.setUCaseExpectations(mockUsecase)
+
+			req := httptest.NewRequest("GET", "/api/v1/me/tokens", nil)
+			req = req.WithContext(test.ctx)
+			rec := httptest.NewRecorder()
+
+			handler := &tokens_http.TokensHandler{TokensUsecase: mockUsecase}
+			handler.GetTokens(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreateToken(t *testing.T) {
+	tests := []struct {
+		name                 string
+		body                 string
+		setUCaseExpectations func(usecase *mocks.APITokensUsecase)
+		status               int
+	}{
+		{
+			name: "GoodCase/Common",
+			body: `{"name": "import", "scopes": ["films:write"]}`,
+			setUCaseExpectations: func(usecase *mocks.APITokensUsecase) {
+				usecase.On("Create", userSC, domain.APITokenToAdd{
+					Name:   "import",
+					Scopes: []domain.Permission{domain.FilmsWrite},
+				}).Return(domain.CreatedAPIToken{Token: domain.APITokenPrefix + "abc"}, nil)
+			},
+			status: http.StatusCreated,
+		},
+		{
+			name:                 "BadCase/InvalidBody",
+			body:                 `{"name": `,
+			setUCaseExpectations: func(usecase *mocks.APITokensUsecase) {},
+			status:               http.StatusBadRequest,
+		},
+		{
+			name: "BadCase/Forbidden",
+			body: `{"name": "import", "scopes": ["users:manage"]}`,
+			setUCaseExpectations: func(usecase *mocks.APITokensUsecase) {
+				usecase.On("Create", userSC, mock.Anything).Return(domain.CreatedAPIToken{}, domain.ErrForbidden)
+			},
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockUsecase := new(mocks.APITokensUsecase)
+			test.setUCaseExpectations(mockUsecase)
+
+			req := httptest.NewRequest("POST", "/api/v1/me/tokens", bytes.NewBufferString(test.body))
+			req = req.WithContext(userCtx)
+			rec := httptest.NewRecorder()
+
+			handler := &tokens_http.TokensHandler{TokensUsecase: mockUsecase}
+			handler.CreateToken(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRevokeToken(t *testing.T) {
+	tests := []struct {
+		name                 string
+		id                   string
+		setUCaseExpectations func(usecase *mocks.APITokensUsecase)
+		ctx                  context.Context
+		status               int
+	}{
+		{
+			name: "GoodCase/Common",
+			id:   "2",
+			setUCaseExpectations: func(usecase *mocks.APITokensUsecase) {
+				usecase.On("Revoke", 1, 2).Return(nil)
+			},
+			ctx:    userCtx,
+			status: http.StatusNoContent,
+		},
+		{
+			name: "GoodCase/ItselfWithAPIToken",
+			id:   "2",
+			setUCaseExpectations: func(usecase *mocks.APITokensUsecase) {
+				usecase.On("Revoke", 1, 2).Return(nil)
+			},
+			ctx:    tokenCtx,
+			status: http.StatusNoContent,
+		},
+		{
+			name:                 "BadCase/OtherWithAPIToken",
+			id:                   "3",
+			setUCaseExpectations: func(usecase *mocks.APITokensUsecase) {},
+			ctx:                  tokenCtx,
+			status:               http.StatusForbidden,
+		},
+		{
+			name:                 "BadCase/InvalidID",
+			id:                   "two",
+			setUCaseExpectations: func(usecase *mocks.APITokensUsecase) {},
+			ctx:                  userCtx,
+			status:               http.StatusBadRequest,
+		},
+		{
+			name: "BadCase/NotFound",
+			id:   "3",
+			setUCaseExpectations: func(usecase *mocks.APITokensUsecase) {
+				usecase.On("Revoke", 1, 3).Return(domain.ErrNotFound)
+			},
+			ctx:    userCtx,
+			status: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockUsecase := new(mocks.APITokensUsecase)
+			test.setUCaseExpectations(mockUsecase)
+
+			req := httptest.NewRequest("DELETE", "/api/v1/me/tokens/"+test.id, nil)
+			req.SetPathValue("id", test.id)
+			req = req.WithContext(test.ctx)
+			rec := httptest.NewRecorder()
+
+			handler := &tokens_http.TokensHandler{TokensUsecase: mockUsecase}
+			handler.RevokeToken(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
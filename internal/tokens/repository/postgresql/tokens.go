@@ -0,0 +1,192 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+)
+
+const insertQuery = `
+	INSERT INTO api_token (user_id, name, hash, scopes, expires_at)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING id, created_at
+`
+
+const selectByUserQuery = `
+	SELECT id, name, scopes, expires_at, last_used_at, created_at
+	FROM api_token
+	WHERE user_id = $1
+	ORDER BY id
+`
+
+const deleteQuery = `
+	DELETE
+	FROM api_token
+	WHERE id = $1
+	  AND user_id = $2
+`
+
+const deleteByUserQuery = `
+	DELETE
+	FROM api_token
+	WHERE user_id = $1
+`
+
+const getByHashQuery = `
+	SELECT t.id, t.name, t.scopes, t.expires_at, t.last_used_at, t.created_at,
+	       u.id, u.role, u.verified, u.disabled
+	FROM api_token t
+	         JOIN "user" u ON u.id = t.user_id
+	WHERE t.hash = $1
+`
+
+const updateLastUsedQuery = `
+	UPDATE api_token
+	SET last_used_at = $1
+	WHERE id = $2
+`
+
+type tokensPostgresqlRepository struct {
+	db  domain.PgxPoolIface
+	ctx context.Context
+}
+
+func NewTokensPostgresqlRepository(pool domain.PgxPoolIface, ctx context.Context) domain.APITokensRepository {
+	return &tokensPostgresqlRepository{
+		db:  pool,
+		ctx: ctx,
+	}
+}
+
+func (r *tokensPostgresqlRepository) Insert(token domain.APIToken) (domain.APIToken, error) {
+	result := r.db.QueryRow(r.ctx, insertQuery,
+		token.UserID, token.Name, token.Hash, scopesToStrings(token.Scopes), token.ExpiresAt)
+	if err := result.Scan(&token.ID, &token.CreatedAt); err != nil {
+		logs.LogError(logs.Logger, "tokens/postgres", "Insert", err, err.Error())
+		return domain.APIToken{}, err
+	}
+
+	return token, nil
+}
+
+func (r *tokensPostgresqlRepository) SelectByUser(userID int) ([]domain.APIToken, error) {
+	rows, err := r.db.Query(r.ctx, selectByUserQuery, userID)
+	if err != nil {
+		logs.LogError(logs.Logger, "tokens/postgres", "SelectByUser", err, err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := []domain.APIToken{}
+	for rows.Next() {
+		token := domain.APIToken{UserID: userID}
+		var scopes []string
+		err = rows.Scan(
+			&token.ID,
+			&token.Name,
+			&scopes,
+			&token.ExpiresAt,
+			&token.LastUsedAt,
+			&token.CreatedAt,
+		)
+		if err != nil {
+			logs.LogError(logs.Logger, "tokens/postgres", "SelectByUser", err, err.Error())
+			return nil, err
+		}
+		token.Scopes = stringsToScopes(scopes)
+
+		tokens = append(tokens, token)
+	}
+	if err = rows.Err(); err != nil {
+		logs.LogError(logs.Logger, "tokens/postgres", "SelectByUser", err, err.Error())
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+func (r *tokensPostgresqlRepository) Delete(userID, tokenID int) error {
+	res, err := r.db.Exec(r.ctx, deleteQuery, tokenID, userID)
+	if err != nil {
+		logs.LogError(logs.Logger, "tokens/postgres", "Delete", err, err.Error())
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *tokensPostgresqlRepository) DeleteByUser(userID int) error {
+	if _, err := r.db.Exec(r.ctx, deleteByUserQuery, userID); err != nil {
+		logs.LogError(logs.Logger, "tokens/postgres", "DeleteByUser", err, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (r *tokensPostgresqlRepository) GetByHash(hash []byte) (domain.APIToken, domain.User, error) {
+	var token domain.APIToken
+	var user domain.User
+	var scopes []string
+
+	err := r.db.QueryRow(r.ctx, getByHashQuery, hash).Scan(
+		&token.ID,
+		&token.Name,
+		&scopes,
+		&token.ExpiresAt,
+		&token.LastUsedAt,
+		&token.CreatedAt,
+		&user.ID,
+		&user.Role,
+		&user.Verified,
+		&user.Disabled,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.APIToken{}, domain.User{}, domain.ErrNotFound
+	}
+	if err != nil {
+		logs.LogError(logs.Logger, "tokens/postgres", "GetByHash", err, err.Error())
+		return domain.APIToken{}, domain.User{}, err
+	}
+	token.Scopes = stringsToScopes(scopes)
+	token.UserID = user.ID
+
+	return token, user, nil
+}
+
+func (r *tokensPostgresqlRepository) UpdateLastUsed(tokenID int, at time.Time) error {
+	_, err := r.db.Exec(r.ctx, updateLastUsedQuery, at, tokenID)
+	if err != nil {
+		logs.LogError(logs.Logger, "tokens/postgres", "UpdateLastUsed", err, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func scopesToStrings(scopes []domain.Permission) []string {
+	s := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		s = append(s, string(scope))
+	}
+
+	return s
+}
+
+func stringsToScopes(s []string) []domain.Permission {
+	scopes := make([]domain.Permission, 0, len(s))
+	for _, scope := range s {
+		scopes = append(scopes, domain.Permission(scope))
+	}
+
+	return scopes
+}
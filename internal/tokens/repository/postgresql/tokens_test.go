@@ -0,0 +1,417 @@
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	postgres "github.com/ellexo2456/FilmLib/internal/tokens/repository/postgresql"
+)
+
+const insertQueryTest = `
+	INSERT INTO api_token
+`
+
+const selectByUserQueryTest = `
+	SELECT id, name, scopes, expires_at, last_used_at, created_at
+	FROM api_token
+	WHERE user_id = \$1
+`
+
+const deleteQueryTest = `
+	DELETE
+	FROM api_token
+`
+
+const deleteByUserQueryTest = `
+	DELETE
+	FROM api_token
+	WHERE user_id = \$1
+`
+
+const getByHashQueryTest = `
+	SELECT t.id, t.name, t.scopes, t.expires_at, t.last_used_at, t.created_at,
+`
+
+const updateLastUsedQueryTest = `
+	UPDATE api_token
+	SET last_used_at = \$1
+`
+
+func TestInsert(t *testing.T) {
+	created := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		token domain.APIToken
+		err   error
+	}{
+		{
+			name: "GoodCase/Common",
+			token: domain.APIToken{
+				Name:   "import",
+				Scopes: []domain.Permission{domain.FilmsWrite},
+				UserID: 1,
+				Hash:   []byte{1, 2, 3},
+			},
+		},
+		{
+			name: "BadCase/DBError",
+			token: domain.APIToken{
+				Name:   "import",
+				Scopes: []domain.Permission{},
+				UserID: 1,
+				Hash:   []byte{1, 2, 3},
+			},
+			err: errors.New("some error"),
+		},
+	}
+
+	mockDB, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockDB.Close()
+	r := postgres.NewTokensPostgresqlRepository(mockDB, context.Background())
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scopes := make([]string, 0, len(test.token.Scopes))
+			for _, s := range test.token.Scopes {
+				scopes = append(scopes, string(s))
+			}
+
+			eq := mockDB.ExpectQuery(insertQueryTest).
+				WithArgs(test.token.UserID, test.token.Name, test.token.Hash, scopes, test.token.ExpiresAt)
+			if test.err != nil {
+				eq.WillReturnError(test.err)
+			} else {
+				eq.WillReturnRows(mockDB.NewRows([]string{"id", "created_at"}).AddRow(7, created))
+			}
+
+			token, err := r.Insert(test.token)
+			if test.err == nil {
+				require.Nil(t, err)
+				require.Equal(t, 7, token.ID)
+				require.Equal(t, created, token.CreatedAt)
+				require.Equal(t, test.token.Name, token.Name)
+			} else {
+				require.NotNil(t, err)
+			}
+
+			err = mockDB.ExpectationsWereMet()
+			require.Nil(t, err)
+		})
+	}
+}
+
+func TestSelectByUser(t *testing.T) {
+	created := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	expires := created.Add(24 * time.Hour)
+
+	tests := []struct {
+		name   string
+		userID int
+		tokens []domain.APIToken
+		err    error
+	}{
+		{
+			name:   "GoodCase/Common",
+			userID: 1,
+			tokens: []domain.APIToken{
+				{ID: 1, Name: "a", Scopes: []domain.Permission{domain.FilmsWrite}, CreatedAt: created, UserID: 1},
+				{ID: 3, Name: "b", Scopes: []domain.Permission{}, ExpiresAt: &expires, LastUsedAt: &created, CreatedAt: created, UserID: 1},
+			},
+		},
+		{
+			name:   "GoodCase/Empty",
+			userID: 2,
+			tokens: []domain.APIToken{},
+		},
+		{
+			name:   "BadCase/DBError",
+			userID: 1,
+			err:    errors.New("some error"),
+		},
+	}
+
+	mockDB, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockDB.Close()
+	r := postgres.NewTokensPostgresqlRepository(mockDB, context.Background())
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rows := mockDB.NewRows([]string{"id", "name", "scopes", "expires_at", "last_used_at", "created_at"})
+			for _, tk := range test.tokens {
+				scopes := make([]string, 0, len(tk.Scopes))
+				for _, s := range tk.Scopes {
+					scopes = append(scopes, string(s))
+				}
+				rows.AddRow(tk.ID, tk.Name, scopes, tk.ExpiresAt, tk.LastUsedAt, tk.CreatedAt)
+			}
+
+			eq := mockDB.ExpectQuery(selectByUserQueryTest).
+				WithArgs(test.userID)
+			if test.err != nil {
+				eq.WillReturnError(test.err)
+			} else {
+				eq.WillReturnRows(rows)
+			}
+
+			tokens, err := r.SelectByUser(test.userID)
+			if test.err == nil {
+				require.Nil(t, err)
+				require.Equal(t, test.tokens, tokens)
+			} else {
+				require.NotNil(t, err)
+			}
+
+			err = mockDB.ExpectationsWereMet()
+			require.Nil(t, err)
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   int
+		tokenID  int
+		affected int64
+		dbErr    error
+		err      error
+	}{
+		{
+			name:     "GoodCase/Common",
+			userID:   1,
+			tokenID:  2,
+			affected: 1,
+		},
+		{
+			name:    "BadCase/NotFound",
+			userID:  1,
+			tokenID: 3,
+			err:     domain.ErrNotFound,
+		},
+		{
+			name:    "BadCase/DBError",
+			userID:  1,
+			tokenID: 2,
+			dbErr:   errors.New("some error"),
+			err:     errors.New("some error"),
+		},
+	}
+
+	mockDB, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockDB.Close()
+	r := postgres.NewTokensPostgresqlRepository(mockDB, context.Background())
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ee := mockDB.ExpectExec(deleteQueryTest).
+				WithArgs(test.tokenID, test.userID)
+			if test.dbErr != nil {
+				ee.WillReturnError(test.dbErr)
+			} else {
+				ee.WillReturnResult(pgxmock.NewResult("DELETE", test.affected))
+			}
+
+			err := r.Delete(test.userID, test.tokenID)
+			if test.err == nil {
+				require.Nil(t, err)
+			} else {
+				require.EqualError(t, err, test.err.Error())
+			}
+
+			err = mockDB.ExpectationsWereMet()
+			require.Nil(t, err)
+		})
+	}
+}
+
+func TestDeleteByUser(t *testing.T) {
+	tests := []struct {
+		name   string
+		userID int
+		err    error
+	}{
+		{
+			name:   "GoodCase/Common",
+			userID: 1,
+		},
+		{
+			name:   "BadCase/DBError",
+			userID: 1,
+			err:    errors.New("some error"),
+		},
+	}
+
+	mockDB, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockDB.Close()
+	r := postgres.NewTokensPostgresqlRepository(mockDB, context.Background())
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ee := mockDB.ExpectExec(deleteByUserQueryTest).
+				WithArgs(test.userID)
+			if test.err != nil {
+				ee.WillReturnError(test.err)
+			} else {
+				ee.WillReturnResult(pgxmock.NewResult("DELETE", 2))
+			}
+
+			err := r.DeleteByUser(test.userID)
+			if test.err == nil {
+				require.Nil(t, err)
+			} else {
+				require.NotNil(t, err)
+			}
+
+			err = mockDB.ExpectationsWereMet()
+			require.Nil(t, err)
+		})
+	}
+}
+
+func TestGetByHash(t *testing.T) {
+	created := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		hash  []byte
+		token domain.APIToken
+		user  domain.User
+		dbErr error
+		err   error
+	}{
+		{
+			name: "GoodCase/Common",
+			hash: []byte{1},
+			token: domain.APIToken{
+				ID:        2,
+				Name:      "import",
+				Scopes:    []domain.Permission{domain.FilmsWrite, domain.ActorsWrite},
+				CreatedAt: created,
+				UserID:    5,
+			},
+			user: domain.User{ID: 5, Role: domain.Moder, Verified: true},
+		},
+		{
+			name:  "BadCase/NotFound",
+			hash:  []byte{2},
+			dbErr: pgx.ErrNoRows,
+			err:   domain.ErrNotFound,
+		},
+		{
+			name:  "BadCase/DBError",
+			hash:  []byte{3},
+			dbErr: errors.New("some error"),
+			err:   errors.New("some error"),
+		},
+	}
+
+	mockDB, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockDB.Close()
+	r := postgres.NewTokensPostgresqlRepository(mockDB, context.Background())
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			eq := mockDB.ExpectQuery(getByHashQueryTest).
+				WithArgs(test.hash)
+			if test.dbErr != nil {
+				eq.WillReturnError(test.dbErr)
+			} else {
+				scopes := make([]string, 0, len(test.token.Scopes))
+				for _, s := range test.token.Scopes {
+					scopes = append(scopes, string(s))
+				}
+				eq.WillReturnRows(mockDB.NewRows([]string{
+					"id", "name", "scopes", "expires_at", "last_used_at", "created_at",
+					"id", "role", "verified", "disabled",
+				}).AddRow(
+					test.token.ID, test.token.Name, scopes, test.token.ExpiresAt, test.token.LastUsedAt, test.token.CreatedAt,
+					test.user.ID, test.user.Role, test.user.Verified, test.user.Disabled,
+				))
+			}
+
+			token, user, err := r.GetByHash(test.hash)
+			if test.err == nil {
+				require.Nil(t, err)
+				require.Equal(t, test.token, token)
+				require.Equal(t, test.user, user)
+			} else {
+				require.EqualError(t, err, test.err.Error())
+			}
+
+			err = mockDB.ExpectationsWereMet()
+			require.Nil(t, err)
+		})
+	}
+}
+
+func TestUpdateLastUsed(t *testing.T) {
+	at := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		tokenID int
+		err     error
+	}{
+		{
+			name:    "GoodCase/Common",
+			tokenID: 1,
+		},
+		{
+			name:    "BadCase/DBError",
+			tokenID: 1,
+			err:     errors.New("some error"),
+		},
+	}
+
+	mockDB, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockDB.Close()
+	r := postgres.NewTokensPostgresqlRepository(mockDB, context.Background())
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ee := mockDB.ExpectExec(updateLastUsedQueryTest).
+				WithArgs(at, test.tokenID)
+			if test.err != nil {
+				ee.WillReturnError(test.err)
+			} else {
+				ee.WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+			}
+
+			err := r.UpdateLastUsed(test.tokenID, at)
+			if test.err == nil {
+				require.Nil(t, err)
+			} else {
+				require.NotNil(t, err)
+			}
+
+			err = mockDB.ExpectationsWereMet()
+			require.Nil(t, err)
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+)
+
+const (
+	maxNameLength = 100
+	tokenBytes    = 32
+)
+
+// lastUsedPrecision throttles last_used_at updates, so a busy script
+// doesn't cause a write on every request.
+const lastUsedPrecision = time.Minute
+
+type tokensUsecase struct {
+	tokensRepo domain.APITokensRepository
+}
+
+func NewTokensUsecase(tr domain.APITokensRepository) domain.APITokensUsecase {
+	return &tokensUsecase{
+		tokensRepo: tr,
+	}
+}
+
+// Create issues a token limited to the given scopes. Tokens can`t be
+// created with another token and can`t get scopes the role doesn`t have.
+func (u *tokensUsecase) Create(sc domain.SessionContext, token domain.APITokenToAdd) (domain.CreatedAPIToken, error) {
+	if sc.TokenID != 0 {
+		return domain.CreatedAPIToken{}, domain.ErrForbidden
+	}
+	if token.Name == "" || len(token.Name) > maxNameLength {
+		return domain.CreatedAPIToken{}, domain.ErrBadRequest
+	}
+	if token.ExpiresAt != nil && !token.ExpiresAt.After(time.Now()) {
+		return domain.CreatedAPIToken{}, domain.ErrBadRequest
+	}
+	for _, scope := range token.Scopes {
+		if !scope.Valid() {
+			return domain.CreatedAPIToken{}, domain.ErrBadRequest
+		}
+		if !sc.Role.Can(scope) {
+			return domain.CreatedAPIToken{}, domain.ErrForbidden
+		}
+	}
+
+	plain, err := newToken()
+	if err != nil {
+		logs.LogError(logs.Logger, "tokens/usecase", "Create", err, err.Error())
+		return domain.CreatedAPIToken{}, err
+	}
+
+	scopes := token.Scopes
+	if scopes == nil {
+		scopes = []domain.Permission{}
+	}
+
+	created, err := u.tokensRepo.Insert(domain.APIToken{
+		Name:      token.Name,
+		Scopes:    scopes,
+		ExpiresAt: token.ExpiresAt,
+		UserID:    sc.UserID,
+		Hash:      hashToken(plain),
+	})
+	if err != nil {
+		logs.LogError(logs.Logger, "tokens/usecase", "Create", err, err.Error())
+		return domain.CreatedAPIToken{}, err
+	}
+
+	return domain.CreatedAPIToken{APIToken: created, Token: plain}, nil
+}
+
+func (u *tokensUsecase) GetAll(userID int) ([]domain.APIToken, error) {
+	tokens, err := u.tokensRepo.SelectByUser(userID)
+	if err != nil {
+		logs.LogError(logs.Logger, "tokens/usecase", "GetAll", err, err.Error())
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+func (u *tokensUsecase) Revoke(userID, tokenID int) error {
+	if tokenID <= 0 {
+		return domain.ErrNotFound
+	}
+
+	if err := u.tokensRepo.Delete(userID, tokenID); err != nil {
+		logs.LogError(logs.Logger, "tokens/usecase", "Revoke", err, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (u *tokensUsecase) RetrieveSessionContext(token string) (domain.SessionContext, error) {
+	if !strings.HasPrefix(token, domain.APITokenPrefix) {
+		return domain.SessionContext{}, domain.ErrUnauthorized
+	}
+
+	t, user, err := u.tokensRepo.GetByHash(hashToken(token))
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.SessionContext{}, domain.ErrUnauthorized
+	}
+	if err != nil {
+		logs.LogError(logs.Logger, "tokens/usecase", "RetrieveSessionContext", err, err.Error())
+		return domain.SessionContext{}, err
+	}
+
+	now := time.Now()
+	if t.ExpiresAt != nil && !t.ExpiresAt.After(now) {
+		return domain.SessionContext{}, domain.ErrUnauthorized
+	}
+	if user.Disabled {
+		return domain.SessionContext{}, domain.ErrDisabled
+	}
+
+	if t.LastUsedAt == nil || now.Sub(*t.LastUsedAt) >= lastUsedPrecision {
+		if err = u.tokensRepo.UpdateLastUsed(t.ID, now); err != nil {
+			logs.LogError(logs.Logger, "tokens/usecase", "RetrieveSessionContext", err, "can`t update last used time")
+		}
+	}
+
+	return domain.SessionContext{
+		UserID:   user.ID,
+		Role:     user.Role,
+		Verified: user.Verified,
+		TokenID:  t.ID,
+		Scopes:   t.Scopes,
+	}, nil
+}
+
+func newToken() (string, error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return domain.APITokenPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// tokens are random enough for a plain sha256 to be as good as a slow hash
+func hashToken(token string) []byte {
+	h := sha256.Sum256([]byte(token))
+	return h[:]
+}
@@ -0,0 +1,252 @@
+package usecase_test
+
+import (
+	"crypto/sha256"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	"github.com/ellexo2456/FilmLib/internal/domain/mocks"
+	"github.com/ellexo2456/FilmLib/internal/tokens/usecase"
+)
+
+func TestCreate(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+	moder := domain.SessionContext{UserID: 1, Role: domain.Moder}
+
+	tests := []struct {
+		name            string
+		sc              domain.SessionContext
+		token           domain.APITokenToAdd
+		setExpectations func(tr *mocks.APITokensRepository)
+		err             error
+	}{
+		{
+			name:  "GoodCase/Common",
+			sc:    moder,
+			token: domain.APITokenToAdd{Name: "import", Scopes: []domain.Permission{domain.FilmsWrite}, ExpiresAt: &future},
+			setExpectations: func(tr *mocks.APITokensRepository) {
+				tr.On("Insert", mock.MatchedBy(func(t domain.APIToken) bool {
+					return t.UserID == 1 && t.Name == "import" && len(t.Hash) == sha256.Size
+				})).Return(domain.APIToken{ID: 1, Name: "import"}, nil)
+			},
+		},
+		{
+			name:  "GoodCase/NoScopes",
+			sc:    domain.SessionContext{UserID: 2, Role: domain.Usr},
+			token: domain.APITokenToAdd{Name: "read"},
+			setExpectations: func(tr *mocks.APITokensRepository) {
+				tr.On("Insert", mock.MatchedBy(func(t domain.APIToken) bool {
+					return t.Scopes != nil && len(t.Scopes) == 0
+				})).Return(domain.APIToken{ID: 2, Name: "read"}, nil)
+			},
+		},
+		{
+			name:            "BadCase/CreatedWithToken",
+			sc:              domain.SessionContext{UserID: 1, Role: domain.Moder, TokenID: 3},
+			token:           domain.APITokenToAdd{Name: "import"},
+			setExpectations: func(tr *mocks.APITokensRepository) {},
+			err:             domain.ErrForbidden,
+		},
+		{
+			name:            "BadCase/EmptyName",
+			sc:              moder,
+			token:           domain.APITokenToAdd{},
+			setExpectations: func(tr *mocks.APITokensRepository) {},
+			err:             domain.ErrBadRequest,
+		},
+		{
+			name:            "BadCase/Expired",
+			sc:              moder,
+			token:           domain.APITokenToAdd{Name: "import", ExpiresAt: &past},
+			setExpectations: func(tr *mocks.APITokensRepository) {},
+			err:             domain.ErrBadRequest,
+		},
+		{
+			name:            "BadCase/UnknownScope",
+			sc:              moder,
+			token:           domain.APITokenToAdd{Name: "import", Scopes: []domain.Permission{"films:everything"}},
+			setExpectations: func(tr *mocks.APITokensRepository) {},
+			err:             domain.ErrBadRequest,
+		},
+		{
+			name:            "BadCase/ScopeAboveRole",
+			sc:              moder,
+			token:           domain.APITokenToAdd{Name: "import", Scopes: []domain.Permission{domain.UsersManage}},
+			setExpectations: func(tr *mocks.APITokensRepository) {},
+			err:             domain.ErrForbidden,
+		},
+		{
+			name:  "BadCase/DBError",
+			sc:    moder,
+			token: domain.APITokenToAdd{Name: "import"},
+			setExpectations: func(tr *mocks.APITokensRepository) {
+				tr.On("Insert", mock.Anything).Return(domain.APIToken{}, domain.ErrInternalServerError)
+			},
+			err: domain.ErrInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tr := new(mocks.APITokensRepository)
+			test.setExpectations(tr)
+
+			created, err := usecase.NewTokensUsecase(tr).Create(test.sc, test.token)
+
+			assert.ErrorIs(t, err, test.err)
+			if test.err == nil {
+				assert.True(t, strings.HasPrefix(created.Token, domain.APITokenPrefix))
+				assert.NotZero(t, created.ID)
+			}
+			tr.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRevoke(t *testing.T) {
+	tests := []struct {
+		name            string
+		tokenID         int
+		setExpectations func(tr *mocks.APITokensRepository)
+		err             error
+	}{
+		{
+			name:    "GoodCase/Common",
+			tokenID: 2,
+			setExpectations: func(tr *mocks.APITokensRepository) {
+				tr.On("Delete", 1, 2).Return(nil)
+			},
+		},
+		{
+			name:            "BadCase/InvalidID",
+			tokenID:         0,
+			setExpectations: func(tr *mocks.APITokensRepository) {},
+			err:             domain.ErrNotFound,
+		},
+		{
+			name:    "BadCase/NotFound",
+			tokenID: 3,
+			setExpectations: func(tr *mocks.APITokensRepository) {
+				tr.On("Delete", 1, 3).Return(domain.ErrNotFound)
+			},
+			err: domain.ErrNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tr := new(mocks.APITokensRepository)
+			test.setExpectations(tr)
+
+			err := usecase.NewTokensUsecase(tr).Revoke(1, test.tokenID)
+
+			assert.ErrorIs(t, err, test.err)
+			tr.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRetrieveSessionContext(t *testing.T) {
+	token := domain.APITokenPrefix + "abc"
+	hash := sha256.Sum256([]byte(token))
+	past := time.Now().Add(-time.Hour)
+	recent := time.Now().Add(-time.Second)
+	future := time.Now().Add(time.Hour)
+	scopes := []domain.Permission{domain.FilmsWrite}
+	user := domain.User{ID: 5, Role: domain.Moder, Verified: true}
+
+	tests := []struct {
+		name            string
+		token           string
+		setExpectations func(tr *mocks.APITokensRepository)
+		sc              domain.SessionContext
+		err             error
+	}{
+		{
+			name:  "GoodCase/Common",
+			token: token,
+			setExpectations: func(tr *mocks.APITokensRepository) {
+				tr.On("GetByHash", hash[:]).
+					Return(domain.APIToken{ID: 2, Scopes: scopes, ExpiresAt: &future}, user, nil)
+				tr.On("UpdateLastUsed", 2, mock.AnythingOfType("time.Time")).Return(nil)
+			},
+			sc: domain.SessionContext{UserID: 5, Role: domain.Moder, Verified: true, TokenID: 2, Scopes: scopes},
+		},
+		{
+			name:  "GoodCase/RecentlyUsed",
+			token: token,
+			setExpectations: func(tr *mocks.APITokensRepository) {
+				tr.On("GetByHash", hash[:]).
+					Return(domain.APIToken{ID: 2, Scopes: scopes, LastUsedAt: &recent}, user, nil)
+			},
+			sc: domain.SessionContext{UserID: 5, Role: domain.Moder, Verified: true, TokenID: 2, Scopes: scopes},
+		},
+		{
+			name:  "GoodCase/LastUsedUpdateFailed",
+			token: token,
+			setExpectations: func(tr *mocks.APITokensRepository) {
+				tr.On("GetByHash", hash[:]).
+					Return(domain.APIToken{ID: 2, Scopes: scopes, LastUsedAt: &past}, user, nil)
+				tr.On("UpdateLastUsed", 2, mock.AnythingOfType("time.Time")).Return(domain.ErrInternalServerError)
+			},
+			sc: domain.SessionContext{UserID: 5, Role: domain.Moder, Verified: true, TokenID: 2, Scopes: scopes},
+		},
+		{
+			name:            "BadCase/NoPrefix",
+			token:           "abc",
+			setExpectations: func(tr *mocks.APITokensRepository) {},
+			err:             domain.ErrUnauthorized,
+		},
+		{
+			name:  "BadCase/Unknown",
+			token: token,
+			setExpectations: func(tr *mocks.APITokensRepository) {
+				tr.On("GetByHash", hash[:]).Return(domain.APIToken{}, domain.User{}, domain.ErrNotFound)
+			},
+			err: domain.ErrUnauthorized,
+		},
+		{
+			name:  "BadCase/Expired",
+			token: token,
+			setExpectations: func(tr *mocks.APITokensRepository) {
+				tr.On("GetByHash", hash[:]).Return(domain.APIToken{ID: 2, ExpiresAt: &past}, user, nil)
+			},
+			err: domain.ErrUnauthorized,
+		},
+		{
+			name:  "BadCase/Disabled",
+			token: token,
+			setExpectations: func(tr *mocks.APITokensRepository) {
+				tr.On("GetByHash", hash[:]).Return(domain.APIToken{ID: 2}, domain.User{ID: 5, Disabled: true}, nil)
+			},
+			err: domain.ErrDisabled,
+		},
+		{
+			name:  "BadCase/DBError",
+			token: token,
+			setExpectations: func(tr *mocks.APITokensRepository) {
+				tr.On("GetByHash", hash[:]).Return(domain.APIToken{}, domain.User{}, domain.ErrInternalServerError)
+			},
+			err: domain.ErrInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tr := new(mocks.APITokensRepository)
+			test.setExpectations(tr)
+
+			sc, err := usecase.NewTokensUsecase(tr).RetrieveSessionContext(test.token)
+
+			assert.ErrorIs(t, err, test.err)
+			assert.Equal(t, test.sc, sc)
+			tr.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package totp_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ellexo2456/FilmLib/internal/totp"
+)
+
+// test vectors from RFC 6238 appendix B, truncated to 6 digits
+func TestCode(t *testing.T) {
+	secret := []byte("12345678901234567890")
+
+	tests := []struct {
+		name string
+		unix int64
+		code string
+	}{
+		{name: "GoodCase/59", unix: 59, code: "287082"},
+		{name: "GoodCase/1111111109", unix: 1111111109, code: "081804"},
+		{name: "GoodCase/1234567890", unix: 1234567890, code: "005924"},
+		{name: "GoodCase/2000000000", unix: 2000000000, code: "279037"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.code, totp.Code(secret, totp.Step(time.Unix(test.unix, 0))))
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	now := time.Unix(1111111109, 0)
+	step := totp.Step(now)
+
+	tests := []struct {
+		name string
+		code string
+		step int64
+		good bool
+	}{
+		{name: "GoodCase/Current", code: totp.Code(secret, step), step: step, good: true},
+		{name: "GoodCase/Previous", code: totp.Code(secret, step-1), step: step - 1, good: true},
+		{name: "GoodCase/Next", code: totp.Code(secret, step+1), step: step + 1, good: true},
+		{name: "BadCase/TooOld", code: totp.Code(secret, step-2)},
+		{name: "BadCase/Short", code: "123"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			matched, ok := totp.Validate(secret, test.code, now)
+
+			assert.Equal(t, test.good, ok)
+			assert.Equal(t, test.step, matched)
+		})
+	}
+}
+
+func TestURI(t *testing.T) {
+	uri := totp.URI("FilmLib", "uvybini@mail.ru", []byte("12345678901234567890"))
+
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/FilmLib:uvybini@mail.ru?"))
+	assert.Contains(t, uri, "secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ")
+	assert.Contains(t, uri, "issuer=FilmLib")
+}
@@ -0,0 +1,213 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+	"github.com/ellexo2456/FilmLib/internal/middleware"
+)
+
+type WebhooksHandler struct {
+	WebhooksUsecase domain.WebhooksUsecase
+}
+
+func NewWebhooksHandler(mux *http.ServeMux, wu domain.WebhooksUsecase) {
+	handler := &WebhooksHandler{
+		WebhooksUsecase: wu,
+	}
+
+	mux.Handle("GET /admin/webhooks", middleware.Require(domain.WebhooksManage, handler.GetWebhooks))
+	mux.Handle("POST /admin/webhooks", middleware.Require(domain.WebhooksManage, handler.CreateWebhook))
+	mux.Handle("DELETE /admin/webhooks/{id}", middleware.Require(domain.WebhooksManage, handler.RemoveWebhook))
+	mux.Handle("GET /admin/webhooks/{id}/deliveries", middleware.Require(domain.WebhooksManage, handler.GetDeliveries))
+	mux.Handle("POST /admin/webhooks/deliveries/{id}/redeliver", middleware.Require(domain.WebhooksManage, handler.Redeliver))
+}
+
+// GetWebhooks godoc
+//
+//	@Summary		Gets webhooks.
+//	@Description	Gets webhooks ordered by id. Secrets are never returned here. Requires webhooks:manage permission.
+//	@Tags			Admin
+//	@Produce		json
+//	@Success		200	{object}	object{body=object{webhooks=[]domain.Webhook}}
+//	@Failure		403	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/admin/webhooks [get]
+func (h *WebhooksHandler) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := h.WebhooksUsecase.GetAll()
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "webhooks/http", "GetWebhooks", err, err.Error())
+		return
+	}
+
+	domain.WriteResponse(
+		w,
+		map[string]interface{}{
+			"webhooks": webhooks,
+		},
+		http.StatusOK,
+	)
+}
+
+// CreateWebhook godoc
+//
+//	@Summary		Creates a webhook.
+//	@Description	Subscribes an http(s) url to the events film.created, film.updated, film.deleted, film.restored, actor.created, actor.updated, actor.deleted, actor.restored and actor.merged. Every delivery is a POST signed in the Webhook-Signature header with "sha256=" and the hex HMAC-SHA256 of Webhook-Timestamp, a dot and the body. The secret is shown only once. Requires webhooks:manage permission.
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		domain.WebhookToAdd	true	"Url and events"
+//	@Success		201		{object}	object{body=object{webhook=domain.CreatedWebhook}}
+//	@Failure		400		{object}	object{err=string}
+//	@Failure		403		{object}	object{err=string}
+//	@Failure		500		{object}	object{err=string}
+//	@Router			/api/v1/admin/webhooks [post]
+func (h *WebhooksHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var webhook domain.WebhookToAdd
+	err := json.NewDecoder(r.Body).Decode(&webhook)
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "webhooks/http", "CreateWebhook", err, err.Error())
+		return
+	}
+	defer domain.CloseAndAlert(r.Body, "webhooks/http", "CreateWebhook")
+
+	created, err := h.WebhooksUsecase.Create(webhook)
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "webhooks/http", "CreateWebhook", err, err.Error())
+		return
+	}
+
+	domain.WriteResponse(
+		w,
+		map[string]interface{}{
+			"webhook": created,
+		},
+		http.StatusCreated,
+	)
+}
+
+// RemoveWebhook godoc
+//
+//	@Summary		Removes a webhook.
+//	@Description	Deletes a webhook with its deliveries. Requires webhooks:manage permission.
+//	@Tags			Admin
+//	@Param			id	path	int	true	"Webhook id"
+//	@Success		204
+//	@Failure		400	{object}	object{err=string}
+//	@Failure		403	{object}	object{err=string}
+//	@Failure		404	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/admin/webhooks/{id} [delete]
+func (h *WebhooksHandler) RemoveWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "webhooks/http", "RemoveWebhook", err, err.Error())
+		return
+	}
+
+	if err = h.WebhooksUsecase.Remove(id); err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "webhooks/http", "RemoveWebhook", err, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetDeliveries godoc
+//
+//	@Summary		Gets deliveries of a webhook.
+//	@Description	Gets deliveries of a webhook, newest first, with the status, the attempts count and the result of the last attempt. A pending delivery is retried with an exponential backoff and fails after 12 attempts. Requires webhooks:manage permission.
+//	@Tags			Admin
+//	@Param			id		path	int	true	"Webhook id"
+//	@Param			limit	query	int	false	"Max deliveries count, 50 by default"
+//	@Param			offset	query	int	false	"Deliveries count to skip"
+//	@Produce		json
+//	@Success		200	{object}	object{body=object{deliveries=[]domain.WebhookDelivery}}
+//	@Failure		400	{object}	object{err=string}
+//	@Failure		403	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/admin/webhooks/{id}/deliveries [get]
+func (h *WebhooksHandler) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "webhooks/http", "GetDeliveries", err, err.Error())
+		return
+	}
+
+	queryParams := r.URL.Query()
+	var limit, offset int
+	ints := map[string]*int{
+		domain.LimitParam:  &limit,
+		domain.OffsetParam: &offset,
+	}
+	for name, dst := range ints {
+		if v := queryParams.Get(name); v != "" {
+			if *dst, err = strconv.Atoi(v); err != nil {
+				domain.WriteError(w, err.Error(), http.StatusBadRequest)
+				logs.LogError(logs.Logger, "webhooks/http", "GetDeliveries", err, err.Error())
+				return
+			}
+		}
+	}
+
+	deliveries, err := h.WebhooksUsecase.GetDeliveries(id, limit, offset)
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "webhooks/http", "GetDeliveries", err, err.Error())
+		return
+	}
+
+	domain.WriteResponse(
+		w,
+		map[string]interface{}{
+			"deliveries": deliveries,
+		},
+		http.StatusOK,
+	)
+}
+
+// Redeliver godoc
+//
+//	@Summary		Redelivers an event.
+//	@Description	Queues a new delivery of the same event, whatever the status of the original one. The event id in the body stays the same. Requires webhooks:manage permission.
+//	@Tags			Admin
+//	@Param			id	path	int	true	"Delivery id"
+//	@Produce		json
+//	@Success		202	{object}	object{body=object{delivery=domain.WebhookDelivery}}
+//	@Failure		400	{object}	object{err=string}
+//	@Failure		403	{object}	object{err=string}
+//	@Failure		404	{object}	object{err=string}
+//	@Failure		500	{object}	object{err=string}
+//	@Router			/api/v1/admin/webhooks/deliveries/{id}/redeliver [post]
+func (h *WebhooksHandler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		domain.WriteError(w, err.Error(), http.StatusBadRequest)
+		logs.LogError(logs.Logger, "webhooks/http", "Redeliver", err, err.Error())
+		return
+	}
+
+	delivery, err := h.WebhooksUsecase.Redeliver(id)
+	if err != nil {
+		domain.WriteError(w, err.Error(), domain.GetStatusCode(err))
+		logs.LogError(logs.Logger, "webhooks/http", "Redeliver", err, err.Error())
+		return
+	}
+
+	domain.WriteResponse(
+		w,
+		map[string]interface{}{
+			"delivery": delivery,
+		},
+		http.StatusAccepted,
+	)
+}
@@ -0,0 +1,211 @@
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	"github.com/ellexo2456/FilmLib/internal/domain/mocks"
+	webhooks_http "github.com/ellexo2456/FilmLib/internal/webhooks/delivery/http"
+)
+
+var (
+	adminCtx = domain.SessionContext{UserID: 1, Role: domain.Admin, Verified: true}
+	moderCtx = domain.SessionContext{UserID: 2, Role: domain.Moder, Verified: true}
+)
+
+func TestCreateWebhook(t *testing.T) {
+	webhook := domain.WebhookToAdd{URL: "https://example.com/hook", Events: []domain.EventType{domain.FilmCreated}}
+
+	tests := []struct {
+		name                 string
+		body                 string
+		sc                   domain.SessionContext
+		setUCaseExpectations func(usecase *mocks.WebhooksUsecase)
+		status               int
+	}{
+		{
+			name: "GoodCase/Common",
+			body: `{"url": "https://example.com/hook", "events": ["film.created"]}`,
+			sc:   adminCtx,
+			setUCaseExpectations: func(usecase *mocks.WebhooksUsecase) {
+				usecase.On("Create", webhook).Return(domain.CreatedWebhook{Secret: "secret"}, nil)
+			},
+			status: http.StatusCreated,
+		},
+		{
+			name: "BadCase/InvalidEvent",
+			body: `{"url": "https://example.com/hook", "events": ["film.watched"]}`,
+			sc:   adminCtx,
+			setUCaseExpectations: func(usecase *mocks.WebhooksUsecase) {
+				usecase.On("Create", domain.WebhookToAdd{URL: webhook.URL, Events: []domain.EventType{"film.watched"}}).
+					Return(domain.CreatedWebhook{}, domain.ErrBadRequest)
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:                 "BadCase/InvalidBody",
+			body:                 `{"url":`,
+			sc:                   adminCtx,
+			setUCaseExpectations: func(usecase *mocks.WebhooksUsecase) {},
+			status:               http.StatusBadRequest,
+		},
+		{
+			name:                 "BadCase/Moderator",
+			body:                 `{"url": "https://example.com/hook", "events": ["film.created"]}`,
+			sc:                   moderCtx,
+			setUCaseExpectations: func(usecase *mocks.WebhooksUsecase) {},
+			status:               http.StatusForbidden,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockUsecase := new(mocks.WebhooksUsecase)
+			test.setUCaseExpectations(mockUsecase)
+
+			rec := serve(mockUsecase, "POST", "/admin/webhooks", test.body, test.sc)
+
+			assert.Equal(t, test.status, rec.Code)
+			if test.status == http.StatusCreated {
+				assert.Contains(t, rec.Body.String(), `"secret":"secret"`)
+			}
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRemoveWebhook(t *testing.T) {
+	tests := []struct {
+		name                 string
+		id                   string
+		setUCaseExpectations func(usecase *mocks.WebhooksUsecase)
+		status               int
+	}{
+		{
+			name: "GoodCase/Common",
+			id:   "1",
+			setUCaseExpectations: func(usecase *mocks.WebhooksUsecase) {
+				usecase.On("Remove", 1).Return(nil)
+			},
+			status: http.StatusNoContent,
+		},
+		{
+			name: "BadCase/NotFound",
+			id:   "2",
+			setUCaseExpectations: func(usecase *mocks.WebhooksUsecase) {
+				usecase.On("Remove", 2).Return(domain.ErrNotFound)
+			},
+			status: http.StatusNotFound,
+		},
+		{
+			name:                 "BadCase/InvalidId",
+			id:                   "one",
+			setUCaseExpectations: func(usecase *mocks.WebhooksUsecase) {},
+			status:               http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockUsecase := new(mocks.WebhooksUsecase)
+			test.setUCaseExpectations(mockUsecase)
+
+			rec := serve(mockUsecase, "DELETE", "/admin/webhooks/"+test.id, "", adminCtx)
+
+			assert.Equal(t, test.status, rec.Code)
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetDeliveries(t *testing.T) {
+	tests := []struct {
+		name                 string
+		query                string
+		setUCaseExpectations func(usecase *mocks.WebhooksUsecase)
+		status               int
+	}{
+		{
+			name:  "GoodCase/Common",
+			query: "?limit=10&offset=20",
+			setUCaseExpectations: func(usecase *mocks.WebhooksUsecase) {
+				usecase.On("GetDeliveries", 1, 10, 20).Return([]domain.WebhookDelivery{{ID: 1}}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:                 "BadCase/InvalidLimit",
+			query:                "?limit=ten",
+			setUCaseExpectations: func(usecase *mocks.WebhooksUsecase) {},
+			status:               http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockUsecase := new(mocks.WebhooksUsecase)
+			test.setUCaseExpectations(mockUsecase)
+
+			rec := serve(mockUsecase, "GET", "/admin/webhooks/1/deliveries"+test.query, "", adminCtx)
+
+			assert.Equal(t, test.status, rec.Code)
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRedeliver(t *testing.T) {
+	tests := []struct {
+		name                 string
+		id                   string
+		setUCaseExpectations func(usecase *mocks.WebhooksUsecase)
+		status               int
+	}{
+		{
+			name: "GoodCase/Common",
+			id:   "5",
+			setUCaseExpectations: func(usecase *mocks.WebhooksUsecase) {
+				usecase.On("Redeliver", int64(5)).Return(domain.WebhookDelivery{ID: 6}, nil)
+			},
+			status: http.StatusAccepted,
+		},
+		{
+			name: "BadCase/NotFound",
+			id:   "5",
+			setUCaseExpectations: func(usecase *mocks.WebhooksUsecase) {
+				usecase.On("Redeliver", int64(5)).Return(domain.WebhookDelivery{}, domain.ErrNotFound)
+			},
+			status: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockUsecase := new(mocks.WebhooksUsecase)
+			test.setUCaseExpectations(mockUsecase)
+
+			rec := serve(mockUsecase, "POST", "/admin/webhooks/deliveries/"+test.id+"/redeliver", "", adminCtx)
+
+			assert.Equal(t, test.status, rec.Code)
+			mockUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func serve(usecase domain.WebhooksUsecase, method, target, body string, sc domain.SessionContext) *httptest.ResponseRecorder {
+	mux := http.NewServeMux()
+	webhooks_http.NewWebhooksHandler(mux, usecase)
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req = req.WithContext(context.WithValue(context.Background(), domain.SessionContextKey, sc))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	return rec
+}
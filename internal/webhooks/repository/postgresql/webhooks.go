@@ -0,0 +1,274 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+)
+
+const insertQuery = `
+	INSERT INTO webhook (url, events, secret)
+	VALUES ($1, $2, $3)
+	RETURNING id, created_at
+`
+
+const selectAllQuery = `
+	SELECT id, url, events, created_at
+	FROM webhook
+	ORDER BY id
+`
+
+// the deliveries are removed by the cascade
+const deleteQuery = `
+	DELETE
+	FROM webhook
+	WHERE id = $1
+`
+
+const insertDeliveriesQuery = `
+	INSERT INTO webhook_delivery (webhook_id, event, payload)
+	SELECT id, $1::TEXT, $2::JSONB
+	FROM webhook
+	WHERE $1::TEXT = ANY (events)
+`
+
+// the deliveries are leased by moving the next attempt, the one which
+// isn`t updated in time is sent again
+const claimDeliveriesQuery = `
+	UPDATE webhook_delivery d
+	SET next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $2)
+	FROM webhook w
+	WHERE w.id = d.webhook_id
+	  AND d.id IN (SELECT id
+	               FROM webhook_delivery
+	               WHERE status = 'pending'
+	                 AND next_attempt_at <= CURRENT_TIMESTAMP
+	               ORDER BY next_attempt_at
+	               LIMIT $1 FOR UPDATE SKIP LOCKED)
+	RETURNING d.id, d.webhook_id, d.event, d.payload, d.attempts, w.url, w.secret
+`
+
+const updateDeliveryQuery = `
+	UPDATE webhook_delivery
+	SET status = $2, attempts = $3, response_status = $4, error = $5, next_attempt_at = $6
+	WHERE id = $1
+`
+
+const deliveryColumns = `
+	id, webhook_id, event, payload, status, attempts, response_status, error, next_attempt_at, created_at, updated_at
+`
+
+const selectDeliveriesQuery = `
+	SELECT` + deliveryColumns + `
+	FROM webhook_delivery
+	WHERE webhook_id = $1
+	ORDER BY id DESC
+	LIMIT $2 OFFSET $3
+`
+
+const redeliverQuery = `
+	INSERT INTO webhook_delivery (webhook_id, event, payload)
+	SELECT webhook_id, event, payload
+	FROM webhook_delivery
+	WHERE id = $1
+	RETURNING` + deliveryColumns
+
+type webhooksPostgresqlRepository struct {
+	db  domain.PgxPoolIface
+	ctx context.Context
+}
+
+func NewWebhooksPostgresqlRepository(pool domain.PgxPoolIface, ctx context.Context) domain.WebhooksRepository {
+	return &webhooksPostgresqlRepository{
+		db:  pool,
+		ctx: ctx,
+	}
+}
+
+func (r *webhooksPostgresqlRepository) Insert(webhook domain.Webhook) (domain.Webhook, error) {
+	result := r.db.QueryRow(r.ctx, insertQuery, webhook.URL, eventsToStrings(webhook.Events), webhook.Secret)
+	if err := result.Scan(&webhook.ID, &webhook.CreatedAt); err != nil {
+		logs.LogError(logs.Logger, "webhooks/postgres", "Insert", err, err.Error())
+		return domain.Webhook{}, err
+	}
+
+	return webhook, nil
+}
+
+func (r *webhooksPostgresqlRepository) SelectAll() ([]domain.Webhook, error) {
+	rows, err := r.db.Query(r.ctx, selectAllQuery)
+	if err != nil {
+		logs.LogError(logs.Logger, "webhooks/postgres", "SelectAll", err, err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []domain.Webhook{}
+	for rows.Next() {
+		var webhook domain.Webhook
+		var events []string
+		err = rows.Scan(
+			&webhook.ID,
+			&webhook.URL,
+			&events,
+			&webhook.CreatedAt,
+		)
+		if err != nil {
+			logs.LogError(logs.Logger, "webhooks/postgres", "SelectAll", err, err.Error())
+			return nil, err
+		}
+
+		webhook.Events = make([]domain.EventType, 0, len(events))
+		for _, event := range events {
+			webhook.Events = append(webhook.Events, domain.EventType(event))
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	if err = rows.Err(); err != nil {
+		logs.LogError(logs.Logger, "webhooks/postgres", "SelectAll", err, err.Error())
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+func (r *webhooksPostgresqlRepository) Delete(id int) error {
+	res, err := r.db.Exec(r.ctx, deleteQuery, id)
+	if err != nil {
+		logs.LogError(logs.Logger, "webhooks/postgres", "Delete", err, err.Error())
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *webhooksPostgresqlRepository) InsertDeliveries(event domain.EventType, payload []byte) (int, error) {
+	res, err := r.db.Exec(r.ctx, insertDeliveriesQuery, string(event), string(payload))
+	if err != nil {
+		logs.LogError(logs.Logger, "webhooks/postgres", "InsertDeliveries", err, err.Error())
+		return 0, err
+	}
+
+	return int(res.RowsAffected()), nil
+}
+
+func (r *webhooksPostgresqlRepository) ClaimDeliveries(limit int, lease time.Duration) ([]domain.WebhookDelivery, error) {
+	rows, err := r.db.Query(r.ctx, claimDeliveriesQuery, limit, lease.Seconds())
+	if err != nil {
+		logs.LogError(logs.Logger, "webhooks/postgres", "ClaimDeliveries", err, err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []domain.WebhookDelivery{}
+	for rows.Next() {
+		delivery := domain.WebhookDelivery{Status: domain.DeliveryPending}
+		err = rows.Scan(
+			&delivery.ID,
+			&delivery.WebhookID,
+			&delivery.Event,
+			&delivery.Payload,
+			&delivery.Attempts,
+			&delivery.URL,
+			&delivery.Secret,
+		)
+		if err != nil {
+			logs.LogError(logs.Logger, "webhooks/postgres", "ClaimDeliveries", err, err.Error())
+			return nil, err
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+	if err = rows.Err(); err != nil {
+		logs.LogError(logs.Logger, "webhooks/postgres", "ClaimDeliveries", err, err.Error())
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (r *webhooksPostgresqlRepository) UpdateDelivery(delivery domain.WebhookDelivery) error {
+	_, err := r.db.Exec(r.ctx, updateDeliveryQuery, delivery.ID, delivery.Status, delivery.Attempts,
+		delivery.ResponseStatus, delivery.Error, delivery.NextAttemptAt)
+	if err != nil {
+		logs.LogError(logs.Logger, "webhooks/postgres", "UpdateDelivery", err, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (r *webhooksPostgresqlRepository) SelectDeliveries(webhookID, limit, offset int) ([]domain.WebhookDelivery, error) {
+	rows, err := r.db.Query(r.ctx, selectDeliveriesQuery, webhookID, limit, offset)
+	if err != nil {
+		logs.LogError(logs.Logger, "webhooks/postgres", "SelectDeliveries", err, err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []domain.WebhookDelivery{}
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			logs.LogError(logs.Logger, "webhooks/postgres", "SelectDeliveries", err, err.Error())
+			return nil, err
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+	if err = rows.Err(); err != nil {
+		logs.LogError(logs.Logger, "webhooks/postgres", "SelectDeliveries", err, err.Error())
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (r *webhooksPostgresqlRepository) Redeliver(deliveryID int64) (domain.WebhookDelivery, error) {
+	delivery, err := scanDelivery(r.db.QueryRow(r.ctx, redeliverQuery, deliveryID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.WebhookDelivery{}, domain.ErrNotFound
+	}
+	if err != nil {
+		logs.LogError(logs.Logger, "webhooks/postgres", "Redeliver", err, err.Error())
+		return domain.WebhookDelivery{}, err
+	}
+
+	return delivery, nil
+}
+
+func scanDelivery(row pgx.Row) (domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	err := row.Scan(
+		&delivery.ID,
+		&delivery.WebhookID,
+		&delivery.Event,
+		&delivery.Payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.ResponseStatus,
+		&delivery.Error,
+		&delivery.NextAttemptAt,
+		&delivery.CreatedAt,
+		&delivery.UpdatedAt,
+	)
+
+	return delivery, err
+}
+
+func eventsToStrings(events []domain.EventType) []string {
+	res := make([]string, 0, len(events))
+	for _, event := range events {
+		res = append(res, string(event))
+	}
+
+	return res
+}
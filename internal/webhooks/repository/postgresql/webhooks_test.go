@@ -0,0 +1,159 @@
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	postgres "github.com/ellexo2456/FilmLib/internal/webhooks/repository/postgresql"
+)
+
+const insertDeliveriesQueryTest = `
+	INSERT INTO webhook_delivery \(webhook_id, event, payload\)
+	SELECT id, \$1::TEXT, \$2::JSONB
+`
+
+const claimDeliveriesQueryTest = `
+	UPDATE webhook_delivery d
+	SET next_attempt_at = CURRENT_TIMESTAMP \+ make_interval\(secs => \$2\)
+`
+
+const redeliverQueryTest = `
+	INSERT INTO webhook_delivery \(webhook_id, event, payload\)
+	SELECT webhook_id, event, payload
+`
+
+func TestInsertDeliveries(t *testing.T) {
+	tests := []struct {
+		name  string
+		count int
+		err   error
+	}{
+		{
+			name:  "GoodCase/Common",
+			count: 2,
+		},
+		{
+			name: "BadCase/DBError",
+			err:  errors.New("some error"),
+		},
+	}
+
+	mockDB, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockDB.Close()
+	r := postgres.NewWebhooksPostgresqlRepository(mockDB, context.Background())
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			eq := mockDB.ExpectExec(insertDeliveriesQueryTest).WithArgs("film.created", `{"id":"event"}`)
+			if test.err != nil {
+				eq.WillReturnError(test.err)
+			} else {
+				eq.WillReturnResult(pgxmock.NewResult("INSERT", int64(test.count)))
+			}
+
+			count, err := r.InsertDeliveries(domain.FilmCreated, []byte(`{"id":"event"}`))
+			require.Equal(t, test.err, err)
+			require.Equal(t, test.count, count)
+
+			err = mockDB.ExpectationsWereMet()
+			require.Nil(t, err)
+		})
+	}
+}
+
+func TestClaimDeliveries(t *testing.T) {
+	mockDB, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockDB.Close()
+	r := postgres.NewWebhooksPostgresqlRepository(mockDB, context.Background())
+
+	mockDB.ExpectQuery(claimDeliveriesQueryTest).
+		WithArgs(20, float64(300)).
+		WillReturnRows(mockDB.NewRows([]string{"id", "webhook_id", "event", "payload", "attempts", "url", "secret"}).
+			AddRow(int64(7), 1, domain.FilmCreated, []byte(`{}`), 2, "https://example.com/hook", "secret"))
+
+	deliveries, err := r.ClaimDeliveries(20, 5*time.Minute)
+	require.Nil(t, err)
+	require.Equal(t, []domain.WebhookDelivery{{
+		ID:        7,
+		WebhookID: 1,
+		Event:     domain.FilmCreated,
+		Payload:   []byte(`{}`),
+		Status:    domain.DeliveryPending,
+		Attempts:  2,
+		URL:       "https://example.com/hook",
+		Secret:    "secret",
+	}}, deliveries)
+
+	err = mockDB.ExpectationsWereMet()
+	require.Nil(t, err)
+}
+
+func TestRedeliver(t *testing.T) {
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		delivery domain.WebhookDelivery
+		err      error
+	}{
+		{
+			name: "GoodCase/Common",
+			delivery: domain.WebhookDelivery{
+				ID:            8,
+				WebhookID:     1,
+				Event:         domain.FilmCreated,
+				Payload:       []byte(`{}`),
+				Status:        domain.DeliveryPending,
+				NextAttemptAt: now,
+				CreatedAt:     now,
+				UpdatedAt:     now,
+			},
+		},
+		{
+			name: "BadCase/NotFound",
+			err:  domain.ErrNotFound,
+		},
+	}
+
+	mockDB, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockDB.Close()
+	r := postgres.NewWebhooksPostgresqlRepository(mockDB, context.Background())
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			eq := mockDB.ExpectQuery(redeliverQueryTest).WithArgs(int64(7))
+			if test.err != nil {
+				eq.WillReturnError(pgx.ErrNoRows)
+			} else {
+				d := test.delivery
+				eq.WillReturnRows(mockDB.NewRows([]string{"id", "webhook_id", "event", "payload", "status", "attempts",
+					"response_status", "error", "next_attempt_at", "created_at", "updated_at"}).
+					AddRow(d.ID, d.WebhookID, d.Event, []byte(d.Payload), d.Status, d.Attempts,
+						d.ResponseStatus, d.Error, d.NextAttemptAt, d.CreatedAt, d.UpdatedAt))
+			}
+
+			delivery, err := r.Redeliver(7)
+			require.Equal(t, test.err, err)
+			require.Equal(t, test.delivery, delivery)
+
+			err = mockDB.ExpectationsWereMet()
+			require.Nil(t, err)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package usecase_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ellexo2456/FilmLib/internal/webhooks/usecase"
+)
+
+func TestNewClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{name: "BadCase/Loopback", url: server.URL},
+		{name: "BadCase/Private", url: "http://10.0.0.1"},
+		{name: "BadCase/LinkLocal", url: "http://169.254.169.254/latest/meta-data"},
+		{name: "BadCase/Unspecified", url: "http://0.0.0.0"},
+		{name: "BadCase/LoopbackV6", url: "http://[::1]"},
+		{name: "BadCase/MappedV4", url: "http://[::ffff:127.0.0.1]"},
+	}
+
+	client := usecase.NewClient(time.Second)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp, err := client.Post(test.url, "application/json", nil)
+			if resp != nil {
+				resp.Body.Close()
+			}
+
+			assert.ErrorContains(t, err, "private address")
+		})
+	}
+}
+
+func TestNewClientRedirect(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "https://example.com/hooks", nil)
+
+	err := usecase.NewClient(time.Second).CheckRedirect(req, []*http.Request{req})
+
+	assert.ErrorIs(t, err, http.ErrUseLastResponse)
+}
@@ -0,0 +1,251 @@
+package usecase
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	logs "github.com/ellexo2456/FilmLib/internal/logger"
+)
+
+const (
+	maxLimit    = 500
+	secretBytes = 32
+
+	dispatchBatch = 20
+	// a delivery which isn`t finished within the lease is sent again
+	dispatchLease = 5 * time.Minute
+	maxAttempts   = 12
+	baseBackoff   = 30 * time.Second
+	maxBackoff    = 6 * time.Hour
+	// only the start of the response body is kept as the error
+	maxErrorLength = 500
+)
+
+type webhooksUsecase struct {
+	webhooksRepo domain.WebhooksRepository
+	client       *http.Client
+	clock        domain.Clock
+}
+
+func NewWebhooksUsecase(wr domain.WebhooksRepository, client *http.Client, clock domain.Clock) domain.WebhooksUsecase {
+	return &webhooksUsecase{
+		webhooksRepo: wr,
+		client:       client,
+		clock:        clock,
+	}
+}
+
+func (u *webhooksUsecase) Create(webhook domain.WebhookToAdd) (domain.CreatedWebhook, error) {
+	parsed, err := url.Parse(webhook.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return domain.CreatedWebhook{}, fmt.Errorf("%w: url must be an absolute http or https url", domain.ErrBadRequest)
+	}
+	if len(webhook.Events) == 0 {
+		return domain.CreatedWebhook{}, fmt.Errorf("%w: no events", domain.ErrBadRequest)
+	}
+	for _, event := range webhook.Events {
+		if !event.Valid() {
+			return domain.CreatedWebhook{}, fmt.Errorf("%w: unknown event %q", domain.ErrBadRequest, event)
+		}
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		logs.LogError(logs.Logger, "webhooks/usecase", "Create", err, err.Error())
+		return domain.CreatedWebhook{}, err
+	}
+
+	created, err := u.webhooksRepo.Insert(domain.Webhook{URL: webhook.URL, Events: webhook.Events, Secret: secret})
+	if err != nil {
+		logs.LogError(logs.Logger, "webhooks/usecase", "Create", err, err.Error())
+		return domain.CreatedWebhook{}, err
+	}
+
+	return domain.CreatedWebhook{Webhook: created, Secret: secret}, nil
+}
+
+func (u *webhooksUsecase) GetAll() ([]domain.Webhook, error) {
+	webhooks, err := u.webhooksRepo.SelectAll()
+	if err != nil {
+		logs.LogError(logs.Logger, "webhooks/usecase", "GetAll", err, err.Error())
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+func (u *webhooksUsecase) Remove(id int) error {
+	if err := u.webhooksRepo.Delete(id); err != nil {
+		logs.LogError(logs.Logger, "webhooks/usecase", "Remove", err, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (u *webhooksUsecase) GetDeliveries(webhookID, limit, offset int) ([]domain.WebhookDelivery, error) {
+	if limit < 0 || offset < 0 || limit > maxLimit {
+		return nil, domain.ErrBadRequest
+	}
+	if limit == 0 {
+		limit = domain.DefaultLimit
+	}
+
+	deliveries, err := u.webhooksRepo.SelectDeliveries(webhookID, limit, offset)
+	if err != nil {
+		logs.LogError(logs.Logger, "webhooks/usecase", "GetDeliveries", err, err.Error())
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (u *webhooksUsecase) Redeliver(deliveryID int64) (domain.WebhookDelivery, error) {
+	delivery, err := u.webhooksRepo.Redeliver(deliveryID)
+	if err != nil {
+		logs.LogError(logs.Logger, "webhooks/usecase", "Redeliver", err, err.Error())
+		return domain.WebhookDelivery{}, err
+	}
+
+	return delivery, nil
+}
+
+// Emit is called after the change has been made, like the audit Record,
+// so a failure to queue the event is only logged.
+func (u *webhooksUsecase) Emit(event domain.EventType, data interface{}) {
+	payload, err := json.Marshal(domain.WebhookEvent{
+		ID:        uuid.NewString(),
+		Event:     event,
+		CreatedAt: u.clock.Now().UTC(),
+		Data:      data,
+	})
+	if err == nil {
+		_, err = u.webhooksRepo.InsertDeliveries(event, payload)
+	}
+	if err != nil {
+		logs.LogError(logs.Logger, "webhooks/usecase", "Emit", err, "failed to queue "+string(payload))
+	}
+}
+
+func (u *webhooksUsecase) Dispatch() (int, error) {
+	deliveries, err := u.webhooksRepo.ClaimDeliveries(dispatchBatch, dispatchLease)
+	if err != nil {
+		logs.LogError(logs.Logger, "webhooks/usecase", "Dispatch", err, err.Error())
+		return 0, err
+	}
+
+	// a delivery which isn`t updated is sent again after the lease
+	sent := 0
+	for _, delivery := range deliveries {
+		delivery = u.send(delivery)
+		if err = u.webhooksRepo.UpdateDelivery(delivery); err != nil {
+			logs.LogError(logs.Logger, "webhooks/usecase", "Dispatch", err, err.Error())
+			continue
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+// send makes an attempt and sets the delivery status and the time of the
+// next attempt by its result.
+func (u *webhooksUsecase) send(delivery domain.WebhookDelivery) domain.WebhookDelivery {
+	delivery.Attempts++
+	delivery.ResponseStatus = 0
+	delivery.Error = ""
+
+	status, err := u.post(delivery)
+	delivery.ResponseStatus = status
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+
+	now := u.clock.Now()
+	delivery.NextAttemptAt = now
+	switch {
+	case err == nil:
+		delivery.Status = domain.DeliverySucceeded
+	case delivery.Attempts >= maxAttempts:
+		delivery.Status = domain.DeliveryFailed
+	default:
+		delivery.Status = domain.DeliveryPending
+		delivery.NextAttemptAt = now.Add(backoff(delivery.Attempts))
+	}
+
+	return delivery
+}
+
+func (u *webhooksUsecase) post(delivery domain.WebhookDelivery) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(u.clock.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(domain.WebhookIDHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(domain.WebhookEventHeader, string(delivery.Event))
+	req.Header.Set(domain.WebhookTimestampHeader, timestamp)
+	req.Header.Set(domain.WebhookSignatureHeader, Sign(delivery.Secret, timestamp, delivery.Payload))
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorLength))
+		return resp.StatusCode, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, body)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	return resp.StatusCode, nil
+}
+
+// Sign returns the value of the signature header, receivers compute it
+// the same way to check the delivery.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// backoff doubles the delay after every attempt, up to the max
+func backoff(attempts int) time.Duration {
+	delay := baseBackoff
+	for i := 1; i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+
+	return delay
+}
+
+func newSecret() (string, error) {
+	b := make([]byte, secretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
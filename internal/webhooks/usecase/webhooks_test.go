@@ -0,0 +1,286 @@
+package usecase_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/ellexo2456/FilmLib/internal/domain"
+	"github.com/ellexo2456/FilmLib/internal/domain/mocks"
+	"github.com/ellexo2456/FilmLib/internal/webhooks/usecase"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+var now = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func TestCreate(t *testing.T) {
+	tests := []struct {
+		name    string
+		webhook domain.WebhookToAdd
+		err     error
+	}{
+		{
+			name:    "GoodCase/Common",
+			webhook: domain.WebhookToAdd{URL: "https://example.com/hook", Events: []domain.EventType{domain.FilmCreated}},
+		},
+		{
+			name:    "BadCase/RelativeURL",
+			webhook: domain.WebhookToAdd{URL: "/hook", Events: []domain.EventType{domain.FilmCreated}},
+			err:     domain.ErrBadRequest,
+		},
+		{
+			name:    "BadCase/Scheme",
+			webhook: domain.WebhookToAdd{URL: "ftp://example.com/hook", Events: []domain.EventType{domain.FilmCreated}},
+			err:     domain.ErrBadRequest,
+		},
+		{
+			name:    "BadCase/NoEvents",
+			webhook: domain.WebhookToAdd{URL: "https://example.com/hook"},
+			err:     domain.ErrBadRequest,
+		},
+		{
+			name:    "BadCase/UnknownEvent",
+			webhook: domain.WebhookToAdd{URL: "https://example.com/hook", Events: []domain.EventType{"film.watched"}},
+			err:     domain.ErrBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := new(mocks.WebhooksRepository)
+			if test.err == nil {
+				repo.On("Insert", mock.MatchedBy(func(w domain.Webhook) bool {
+					return w.URL == test.webhook.URL && len(w.Secret) == 64
+				})).Return(func(w domain.Webhook) domain.Webhook {
+					w.ID = 1
+					return w
+				}, nil)
+			}
+
+			created, err := usecase.NewWebhooksUsecase(repo, http.DefaultClient, &fakeClock{now}).Create(test.webhook)
+
+			assert.ErrorIs(t, err, test.err)
+			if test.err == nil {
+				assert.Equal(t, 1, created.ID)
+				assert.Len(t, created.Secret, 64)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEmit(t *testing.T) {
+	repo := new(mocks.WebhooksRepository)
+	var payload []byte
+	repo.On("InsertDeliveries", domain.FilmDeleted, mock.Anything).
+		Run(func(args mock.Arguments) { payload = args.Get(1).([]byte) }).
+		Return(1, nil)
+
+	usecase.NewWebhooksUsecase(repo, http.DefaultClient, &fakeClock{now}).Emit(domain.FilmDeleted, domain.Film{ID: 1})
+
+	var event struct {
+		ID        string
+		Event     domain.EventType
+		CreatedAt time.Time
+		Data      domain.Film
+	}
+	assert.NoError(t, json.Unmarshal(payload, &event))
+	assert.NotEmpty(t, event.ID)
+	assert.Equal(t, domain.FilmDeleted, event.Event)
+	assert.Equal(t, now, event.CreatedAt)
+	assert.Equal(t, 1, event.Data.ID)
+	repo.AssertExpectations(t)
+}
+
+func TestGetDeliveries(t *testing.T) {
+	tests := []struct {
+		name          string
+		limit, offset int
+		repoLimit     int
+		err           error
+	}{
+		{
+			name:      "GoodCase/DefaultLimit",
+			repoLimit: domain.DefaultLimit,
+		},
+		{
+			name:      "GoodCase/Limit",
+			limit:     10,
+			offset:    20,
+			repoLimit: 10,
+		},
+		{
+			name:  "BadCase/TooLarge",
+			limit: 1000,
+			err:   domain.ErrBadRequest,
+		},
+		{
+			name:   "BadCase/NegativeOffset",
+			offset: -1,
+			err:    domain.ErrBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := new(mocks.WebhooksRepository)
+			if test.err == nil {
+				repo.On("SelectDeliveries", 1, test.repoLimit, test.offset).Return([]domain.WebhookDelivery{}, nil)
+			}
+
+			_, err := usecase.NewWebhooksUsecase(repo, http.DefaultClient, &fakeClock{now}).
+				GetDeliveries(1, test.limit, test.offset)
+
+			assert.ErrorIs(t, err, test.err)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDispatch(t *testing.T) {
+	payload := json.RawMessage(`{"id":"event","event":"film.created"}`)
+
+	tests := []struct {
+		name     string
+		status   int
+		attempts int
+		expected domain.WebhookDelivery
+	}{
+		{
+			name:   "GoodCase/Succeeded",
+			status: http.StatusNoContent,
+			expected: domain.WebhookDelivery{
+				Status:         domain.DeliverySucceeded,
+				Attempts:       1,
+				ResponseStatus: http.StatusNoContent,
+				NextAttemptAt:  now,
+			},
+		},
+		{
+			name:     "GoodCase/Retried",
+			status:   http.StatusServiceUnavailable,
+			attempts: 2,
+			expected: domain.WebhookDelivery{
+				Status:         domain.DeliveryPending,
+				Attempts:       3,
+				ResponseStatus: http.StatusServiceUnavailable,
+				Error:          "unexpected status 503: down",
+				NextAttemptAt:  now.Add(2 * time.Minute),
+			},
+		},
+		{
+			name:     "GoodCase/BackoffCapped",
+			status:   http.StatusInternalServerError,
+			attempts: 10,
+			expected: domain.WebhookDelivery{
+				Status:         domain.DeliveryPending,
+				Attempts:       11,
+				ResponseStatus: http.StatusInternalServerError,
+				Error:          "unexpected status 500: down",
+				NextAttemptAt:  now.Add(6 * time.Hour),
+			},
+		},
+		{
+			name:     "BadCase/Failed",
+			status:   http.StatusBadGateway,
+			attempts: 11,
+			expected: domain.WebhookDelivery{
+				Status:         domain.DeliveryFailed,
+				Attempts:       12,
+				ResponseStatus: http.StatusBadGateway,
+				Error:          "unexpected status 502: down",
+				NextAttemptAt:  now,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				timestamp := r.Header.Get(domain.WebhookTimestampHeader)
+				assert.Equal(t, strconv.FormatInt(now.Unix(), 10), timestamp)
+				assert.Equal(t, usecase.Sign("secret", timestamp, body), r.Header.Get(domain.WebhookSignatureHeader))
+				assert.Equal(t, "7", r.Header.Get(domain.WebhookIDHeader))
+				assert.Equal(t, string(domain.FilmCreated), r.Header.Get(domain.WebhookEventHeader))
+				assert.JSONEq(t, string(payload), string(body))
+
+				w.WriteHeader(test.status)
+				_, _ = w.Write([]byte("down"))
+			}))
+			defer server.Close()
+
+			claimed := domain.WebhookDelivery{
+				ID:        7,
+				WebhookID: 1,
+				Event:     domain.FilmCreated,
+				Payload:   payload,
+				Status:    domain.DeliveryPending,
+				Attempts:  test.attempts,
+				URL:       server.URL,
+				Secret:    "secret",
+			}
+			expected := claimed
+			expected.Status = test.expected.Status
+			expected.Attempts = test.expected.Attempts
+			expected.ResponseStatus = test.expected.ResponseStatus
+			expected.Error = test.expected.Error
+			expected.NextAttemptAt = test.expected.NextAttemptAt
+
+			repo := new(mocks.WebhooksRepository)
+			repo.On("ClaimDeliveries", mock.Anything, mock.Anything).Return([]domain.WebhookDelivery{claimed}, nil)
+			repo.On("UpdateDelivery", expected).Return(nil)
+
+			count, err := usecase.NewWebhooksUsecase(repo, server.Client(), &fakeClock{now}).Dispatch()
+
+			assert.NoError(t, err)
+			assert.Equal(t, 1, count)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDispatchUpdateFailed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	first := domain.WebhookDelivery{ID: 7, WebhookID: 1, Event: domain.FilmCreated, Payload: json.RawMessage(`{}`),
+		Status: domain.DeliveryPending, URL: server.URL, Secret: "secret"}
+	second := first
+	second.ID = 8
+
+	repo := new(mocks.WebhooksRepository)
+	repo.On("ClaimDeliveries", mock.Anything, mock.Anything).Return([]domain.WebhookDelivery{first, second}, nil)
+	repo.On("UpdateDelivery", mock.MatchedBy(func(d domain.WebhookDelivery) bool { return d.ID == 7 })).
+		Return(domain.ErrInternalServerError)
+	repo.On("UpdateDelivery", mock.MatchedBy(func(d domain.WebhookDelivery) bool { return d.ID == 8 })).
+		Return(nil)
+
+	count, err := usecase.NewWebhooksUsecase(repo, server.Client(), &fakeClock{now}).Dispatch()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	repo.AssertExpectations(t)
+}
+
+func TestSign(t *testing.T) {
+	assert.Equal(t,
+		"sha256=ea16c76f15c45d3298407b776492619d097941604d31e5def94f98c79bbcc2a0",
+		usecase.Sign("secret", "1709294400", []byte(`{}`)))
+}